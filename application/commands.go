@@ -0,0 +1,595 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck"
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/cpu"
+	"github.com/dfialho05/GoMonitor/application/pck/disk"
+	"github.com/dfialho05/GoMonitor/application/pck/health"
+	"github.com/dfialho05/GoMonitor/application/pck/ram"
+	"github.com/dfialho05/GoMonitor/application/pck/ui"
+)
+
+// command is a mode of gom, selected by its name (e.g. "top") or one of its flags (e.g. "-t")
+// Views print a report and can be combined and repeated with --watch; the other
+// commands (e.g. "burn") take the rest of the command line as their own arguments
+type command struct {
+	name     string                                           // Subcommand name (e.g. "top")
+	flags    []string                                         // Flags that also select it (e.g. "-t", "--top")
+	count    bool                                             // Takes an optional number of entries (e.g. "top 20")
+	noHeader bool                                             // Output must not start with the main header
+	view     func(n int, opts viewOptions)                    // Shows the view (nil for other commands)
+	csv      func(w io.Writer, n int, opts viewOptions) error // Writes the view as CSV (nil if not supported)
+	run      func(args []string)                              // Runs a command that isn't a view
+}
+
+// defaultCount is the number of entries of views that take a number (e.g. "top")
+const defaultCount = 10
+
+// commands lists every command in the order of the help
+var commands = []*command{
+	{name: "help", flags: []string{"-h", "--help"}, run: func([]string) { printHelp() }},
+	{name: "default", flags: []string{"-n", "--default"}, noHeader: true, view: func(_ int, opts viewOptions) { showDefaultInterface(opts.disk, opts.logo, opts.health) }},
+	{name: "startup", flags: []string{"-s", "--startup"}, run: func([]string) { toggleAutoStart() }},
+	{name: "tui", flags: []string{"-f", "--full"}, noHeader: true, run: func([]string) { showInteractiveTUI(loadConfig()) }},
+	{name: "fix-terminal", noHeader: true, run: func([]string) { fixTerminal() }},
+	{name: "overview", flags: []string{"-a", "--all"}, view: func(_ int, opts viewOptions) { showSystemOverview(opts.disk) }},
+	{name: "cpu", flags: []string{"-c", "--cpu"}, view: func(int, viewOptions) { showCPUInfo() }, csv: writeCPUCSV},
+	{name: "ram", flags: []string{"-r", "--ram"}, view: func(int, viewOptions) { showRAMInfo() }, csv: writeRAMCSV},
+	{name: "gpu", flags: []string{"-g", "--gpu"}, view: func(int, viewOptions) { showGPUInfo() }},
+	{name: "disk", flags: []string{"-d", "--disk"}, view: func(_ int, opts viewOptions) { showDiskInfo(opts.disk) }, csv: writeDiskCSV},
+	{name: "disk-health", flags: []string{"--disk-health"}, view: func(int, viewOptions) { showDiskHealth() }},
+	{name: "io", flags: []string{"-i", "--io"}, view: func(int, viewOptions) { showIOInfo() }},
+	{name: "logins", flags: []string{"-l", "--logins"}, view: func(int, viewOptions) { showLoginActivity() }},
+	{name: "sensors", flags: []string{"-S", "--sensors"}, view: func(int, viewOptions) { showSensorsInfo() }},
+	{name: "net-info", flags: []string{"--net-info"}, view: func(int, viewOptions) { showNetInfo() }},
+	{name: "ports", flags: []string{"--ports"}, view: func(int, viewOptions) { showPorts() }},
+	{name: "power", flags: []string{"--power"}, count: true, view: func(n int, _ viewOptions) { showWakeups(n) }},
+	{name: "energy", flags: []string{"--energy"}, count: true, view: func(n int, _ viewOptions) { showEnergy(n) }},
+	{name: "services", flags: []string{"--services"}, view: func(int, viewOptions) { showServices() }},
+	{name: "containers", flags: []string{"--containers"}, view: func(int, viewOptions) { showContainers() }},
+	{name: "check", noHeader: true, run: runCheck},
+	{name: "clean", run: runClean},
+	{name: "burn", run: runBurn},
+	{name: "throttle", run: runThrottle},
+	{name: "kill", run: runKill},
+	{name: "run", noHeader: true, run: runLaunch},
+	{name: "process", flags: []string{"-p", "--process"}, run: runProcessMonitor},
+	{name: "watch-pid", run: runWatchPID},
+	{name: "prometheus", noHeader: true, run: runPrometheus},
+	{name: "daemon", noHeader: true, run: runDaemon},
+	{name: "snapshot", noHeader: true, run: runSnapshot},
+	{name: "diff", run: runDiff},
+	{name: "chart", run: runChart},
+	{name: "events", run: showEvents},
+	{name: "paths", noHeader: true, run: showPaths},
+	{name: "health", flags: []string{"--health"}, noHeader: true, view: func(_ int, opts viewOptions) { showHealth(opts.health, opts.disk) }},
+	{name: "doctor", flags: []string{"--doctor"}, view: func(int, viewOptions) { showDoctor() }},
+	{name: "top", flags: []string{"-t", "--top"}, count: true, view: showTopView, csv: writeTopCSV},
+	{name: "top-io", flags: []string{"--top-io"}, count: true, view: func(n int, _ viewOptions) { showTopIO(n) }},
+	{name: "top-fds", flags: []string{"--top-fds"}, count: true, view: func(n int, _ viewOptions) { showTopFDs(n) }},
+}
+
+// findCommand looks up a command by its name or one of its flags
+//
+// Returns: the command (nil if arg isn't a command)
+func findCommand(arg string) *command {
+	for _, cmd := range commands {
+		if cmd.name == arg {
+			return cmd
+		}
+		for _, flag := range cmd.flags {
+			if flag == arg {
+				return cmd
+			}
+		}
+	}
+	return nil
+}
+
+// viewOptions contains the options shared by the views
+type viewOptions struct {
+	core    bool                   // --core: add the CPU core each top process last ran on
+	groups  bool                   // --groups: add the process group and session IDs of each top process
+	wide    bool                   // --wide: add the full command line of each top process
+	apps    bool                   // --group: show one row per application instead of per process in top
+	verbose bool                   // --verbose: report the processes and partitions that couldn't be read, and why
+	user    string                 // --user: only show the top processes of this user
+	sort    string                 // --sort: field the top processes are ranked by ("cpu", "ram", "time", "pid", "name" or "io"; empty = "cpu")
+	reverse bool                   // --reverse: reverse the usual order of the sort field
+	columns []common.ProcessColumn // --columns: columns of the top processes, instead of the usual ones (nil = usual)
+	csv     optionalValue          // --csv [file]: write the view as CSV (stdout if no file)
+	export  optionalValue          // --export [file]: write the default view to a file (stdout if no file)
+	logo    ui.LogoOptions         // --ascii, --ascii-file: logo of the default view (detected from the OS if not set)
+	watch   interval               // --watch N: show the views again every N seconds
+	disk    disk.Filter            // --include-fstype, --exclude-mount, --min-size: mounts listed by the disk views
+	health  *health.Scorer         // Health score weights and alert rules of the config file
+}
+
+// tableOptions returns the columns and order of the process tables
+func (opts viewOptions) tableOptions() common.TableOptions {
+	return common.TableOptions{ShowCore: opts.core, ShowGroups: opts.groups, ShowCPUTime: opts.sort == "time",
+		ShowCmdline: opts.wide, Columns: opts.columns, Reverse: opts.reverse}
+}
+
+// sortField returns the field the top processes are ranked by (CPU usage if --sort isn't given)
+func (opts viewOptions) sortField() string {
+	if opts.sort == "" {
+		return "cpu"
+	}
+	return opts.sort
+}
+
+// newViewFlags creates the flag set of the view options
+func newViewFlags(opts *viewOptions) *flag.FlagSet {
+	fs := newFlagSet("gom")
+	fs.BoolVar(&opts.core, "core", false, "add the CPU core each process last ran on")
+	fs.BoolVar(&opts.groups, "groups", false, "add the process group and session IDs")
+	fs.BoolVar(&opts.wide, "wide", false, "add the full command line of each process")
+	fs.BoolVar(&opts.apps, "group", false, "group the top processes by application")
+	fs.BoolVar(&opts.verbose, "verbose", false, "report what couldn't be read and why")
+	fs.StringVar(&opts.user, "user", "", "only show processes owned by this user")
+	fs.StringVar(&opts.sort, "sort", "", "rank the top processes by cpu, ram, time, pid, name or io")
+	fs.BoolVar(&opts.reverse, "reverse", false, "reverse the order of the top processes")
+	fs.Func("columns", "columns of the top processes (e.g. pid,name,cpu)", func(value string) error {
+		columns, err := common.ParseProcessColumns(value)
+		opts.columns = columns
+		return err
+	})
+	fs.Var(&opts.csv, "csv", "write the listing as CSV (stdout if no file)")
+	fs.Var(&opts.export, "export", "write the default view as text or PNG (stdout if no file)")
+	fs.StringVar(&opts.logo.Name, "ascii", "", "logo of the default view (e.g. arch)")
+	fs.StringVar(&opts.logo.File, "ascii-file", "", "text file with a custom logo for the default view")
+	fs.Var(&opts.watch, "watch", "show the views again every N seconds")
+	addDiskFlags(fs, &opts.disk)
+	return fs
+}
+
+// addDiskFlags adds the mount filter options to a flag set
+// The filter should already hold the config file settings: listed types and mounts are
+// added to them, and --min-size replaces the configured size
+func addDiskFlags(fs *flag.FlagSet, filter *disk.Filter) {
+	fs.Var((*listValue)(&filter.IncludeFsTypes), "include-fstype", "list a hidden file system type anyway")
+	fs.Var((*listValue)(&filter.ExcludeMounts), "exclude-mount", "hide a mountpoint and everything below it")
+	fs.Var((*byteSize)(&filter.MinSize), "min-size", "smallest partition listed")
+}
+
+// selectedView is a view given on the command line with its number of entries
+type selectedView struct {
+	cmd *command
+	n   int
+}
+
+// invocation is the parsed command line
+type invocation struct {
+	views   []selectedView // Views to show, in order (empty if cmd is set)
+	cmd     *command       // Command that isn't a view
+	args    []string       // Arguments of cmd
+	options viewOptions    // Options of the views
+}
+
+// parseArgs splits the command line into views and their options, or a single other command
+// Views and options can be given in any order, e.g. "gom --cpu --ram --watch 5" or "gom top 20 --core";
+// everything after another command belongs to it, e.g. "gom throttle 1234 --cpu 2"
+//
+// Parameters:
+//   - args: command line arguments without the program name
+//
+// Returns:
+//   - the invocation
+//   - error if an argument is unknown or options don't fit the views
+func parseArgs(args []string) (invocation, error) {
+	var inv invocation
+	inv.options.disk = diskFilter()
+	inv.options.health = healthScorer()
+	fs := newViewFlags(&inv.options)
+
+	// 1. Pick out the commands (and their numbers); the rest are options
+	var options []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if cmd := findCommand(arg); cmd != nil {
+			if cmd.view == nil {
+				if len(inv.views) > 0 {
+					return inv, fmt.Errorf("'%s' can't be combined with other commands", cmd.name)
+				}
+				inv.cmd, inv.args = cmd, args[i+1:]
+				break
+			}
+
+			view := selectedView{cmd: cmd, n: defaultCount}
+			if cmd.count && i+1 < len(args) {
+				if n, err := strconv.Atoi(args[i+1]); err == nil {
+					if n < 1 {
+						return inv, fmt.Errorf("'%s' needs a number of entries of at least 1, got %d", cmd.name, n)
+					}
+					view.n = n
+					i++
+				}
+			}
+			inv.views = append(inv.views, view)
+			continue
+		}
+
+		if !strings.HasPrefix(arg, "-") {
+			return inv, fmt.Errorf("unrecognized argument '%s'", arg)
+		}
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		option := fs.Lookup(name)
+		if option == nil {
+			return inv, fmt.Errorf("unrecognized argument '%s'", arg)
+		}
+
+		// Values are joined to their option so they aren't taken for commands;
+		// the value of --csv and --export is optional: a bare "--csv" becomes "--csv="
+		if !hasValue {
+			if boolFlag, ok := option.Value.(interface{ IsBoolFlag() bool }); !ok || !boolFlag.IsBoolFlag() {
+				_, optional := option.Value.(*optionalValue)
+				value := ""
+				if i+1 < len(args) && (!optional || !strings.HasPrefix(args[i+1], "-") && findCommand(args[i+1]) == nil) {
+					value = args[i+1]
+					i++
+				}
+				arg += "=" + value
+			}
+		}
+		options = append(options, arg)
+	}
+
+	// 2. Parse the options
+	if err := fs.Parse(options); err != nil {
+		return inv, err
+	}
+	if inv.cmd != nil {
+		if len(options) > 0 {
+			return inv, fmt.Errorf("%s must come after '%s'", options[0], inv.cmd.name)
+		}
+		return inv, nil
+	}
+
+	// 3. Check that the options fit the views ("gom --export" or "gom --ascii arch" alone
+	// use the default view)
+	logo := inv.options.logo
+	if len(inv.views) == 0 && (inv.options.export.set || logo.Name != "" || logo.File != "") {
+		inv.views = append(inv.views, selectedView{cmd: findCommand("default")})
+	}
+	if len(inv.views) == 0 {
+		return inv, errors.New("missing command (e.g. gom cpu)")
+	}
+	if inv.options.csv.set {
+		if len(inv.views) > 1 || inv.views[0].cmd.csv == nil {
+			return inv, errors.New("--csv works with one of top, cpu, ram or disk")
+		}
+		if inv.options.watch > 0 {
+			return inv, errors.New("--csv can't be combined with --watch")
+		}
+	}
+	if inv.options.apps {
+		for _, view := range inv.views {
+			if view.cmd.name != "top" {
+				return inv, errors.New("--group only works with top")
+			}
+		}
+		if inv.options.core || inv.options.groups || inv.options.wide || inv.options.sort != "" || inv.options.reverse || inv.options.columns != nil {
+			return inv, errors.New("--group can't be combined with --core, --groups, --wide, --sort, --reverse or --columns")
+		}
+	}
+	if inv.options.sort != "" && !pck.IsTopSortField(inv.options.sort) {
+		return inv, fmt.Errorf("unknown sort field '%s' (cpu, ram, time, pid, name or io)", inv.options.sort)
+	}
+	if inv.options.sort != "" || inv.options.reverse || inv.options.columns != nil || inv.options.wide {
+		for _, view := range inv.views {
+			if view.cmd.name != "top" {
+				return inv, errors.New("--sort, --reverse, --columns and --wide only work with top")
+			}
+		}
+	}
+	if inv.options.columns != nil && (inv.options.core || inv.options.groups || inv.options.wide) {
+		return inv, errors.New("--columns can't be combined with --core, --groups or --wide (add core, pgid, sid or cmd to the columns)")
+	}
+	if logo.Name != "" && !ui.IsLogo(logo.Name) {
+		return inv, fmt.Errorf("unknown logo '%s' (%s)", logo.Name, strings.Join(ui.LogoNames(), ", "))
+	}
+	if inv.options.export.set || logo.Name != "" || logo.File != "" {
+		if len(inv.views) > 1 || inv.views[0].cmd.name != "default" {
+			return inv, errors.New("--export, --ascii and --ascii-file only work with the default view")
+		}
+	}
+	if inv.options.export.set {
+		if inv.options.watch > 0 || inv.options.csv.set {
+			return inv, errors.New("--export can't be combined with --watch or --csv")
+		}
+	}
+	return inv, nil
+}
+
+// showHeader checks if the main header is printed before the output
+// It's left out of interactive and watched views and output meant for other programs
+func (inv invocation) showHeader() bool {
+	if inv.cmd != nil {
+		return !inv.cmd.noHeader
+	}
+	if inv.options.watch > 0 || (inv.options.csv.set && inv.options.csv.value == "") {
+		return false
+	}
+	for _, view := range inv.views {
+		if view.cmd.noHeader {
+			return false
+		}
+	}
+	return true
+}
+
+// run runs the command or shows the views (once, as CSV, or every --watch interval)
+func (inv invocation) run() {
+	common.SetVerbose(inv.options.verbose)
+	switch {
+	case inv.cmd != nil:
+		inv.cmd.run(inv.args)
+	case inv.options.export.set:
+		exportDefaultInterface(inv.options.disk, inv.options.logo, inv.options.health, inv.options.export.value)
+	case inv.options.csv.set:
+		view := inv.views[0]
+		exportCSV(inv.options.csv.value, func(w io.Writer) error {
+			return view.cmd.csv(w, view.n, inv.options)
+		})
+	case inv.options.watch > 0:
+		inv.watch()
+	default:
+		inv.showViews()
+	}
+}
+
+// showViews shows every view once, in the order given
+func (inv invocation) showViews() {
+	for _, view := range inv.views {
+		view.cmd.view(view.n, inv.options)
+	}
+}
+
+// watch clears the screen and shows the views again every --watch interval until Ctrl+C,
+// then prints the lowest, average and highest CPU and RAM usage and CPU temperature seen
+func (inv invocation) watch() {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	every := time.Duration(inv.options.watch)
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	var names []string
+	for _, view := range inv.views {
+		names = append(names, view.cmd.name)
+	}
+
+	// The first CPU sample only records a baseline, so the usage covers each interval
+	summary := common.NewSessionSummary()
+	cpu.GetSystemPercent()
+
+	for {
+		fmt.Print("\033[H\033[2J")
+		common.ResetReports()
+		fmt.Printf(colorCyan+"Every %s: %s (Ctrl+C to quit)  %s\n"+colorReset,
+			every, strings.Join(names, ", "), common.FormatTimestamp(time.Now()))
+		inv.showViews()
+
+		select {
+		case <-ticker.C:
+			sampleSummary(summary)
+		case <-sigChan:
+			fmt.Println()
+			summary.Print("the system")
+			return
+		}
+	}
+}
+
+// sampleSummary adds the system CPU and RAM usage and the CPU temperature to a watch summary
+// Readings that aren't available (e.g. the temperature on a VM) are left out
+func sampleSummary(summary *common.SessionSummary) {
+	now := time.Now()
+	if percent, err := cpu.GetSystemPercent(); err == nil {
+		summary.Series("CPU", common.FormatSummaryPercent).Add(percent, now)
+	}
+	if stats, err := ram.GetRamGeneral(); err == nil {
+		summary.Series("RAM", common.FormatSummaryPercent).Add(stats.Percent, now)
+	}
+	if temp := cpu.GetTemperature(); temp > 0 {
+		summary.Series("CPU temp", common.FormatSummaryCelsius).Add(float64(temp), now)
+	}
+}
+
+// showTopView shows the top processes with the --core, --groups, --wide, --group, --sort,
+// --reverse, --columns and --user options
+func showTopView(n int, opts viewOptions) {
+	if opts.apps {
+		showTopApplications(n, opts.user)
+		return
+	}
+	showTopProcesses(n, opts.user, opts.sortField(), opts.tableOptions())
+}
+
+// writeTopCSV writes the top processes by CPU usage (or the --sort field) as CSV
+func writeTopCSV(w io.Writer, n int, opts viewOptions) error {
+	field, options := opts.sortField(), opts.tableOptions()
+	processes, _, err := pck.CollectTopProcesses(field, options)
+	if err != nil {
+		return err
+	}
+	processes = common.FilterProcessesByUser(processes, opts.user)
+	if opts.apps {
+		return common.WriteApplicationCSV(w, common.GroupByApplication(processes), n)
+	}
+	processes = common.FirstKProcesses(processes, n, field, common.SortsDescending(field) != options.Reverse)
+	return common.WriteProcessCSV(w, processes, n, options)
+}
+
+// writeCPUCSV writes the process listing sorted by CPU usage as CSV
+func writeCPUCSV(w io.Writer, _ int, _ viewOptions) error {
+	processes, err := cpu.GetProcessStats()
+	if err != nil {
+		return err
+	}
+	return common.WriteProcessCSV(w, processes, 0, common.TableOptions{})
+}
+
+// writeRAMCSV writes the process listing sorted by RAM usage as CSV
+func writeRAMCSV(w io.Writer, _ int, _ viewOptions) error {
+	processes, err := ram.GetProcessStatsByRAM()
+	if err != nil {
+		return err
+	}
+	return common.WriteProcessCSV(w, processes, 0, common.TableOptions{})
+}
+
+// writeDiskCSV writes the storage devices as CSV
+func writeDiskCSV(w io.Writer, _ int, opts viewOptions) error {
+	return disk.WriteStorageCSV(w, opts.disk)
+}
+
+// newFlagSet creates a flag set that returns its errors instead of printing them
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// parseCommandArgs parses the options of a command, which may come before, between or after
+// its positional arguments (e.g. "gom chart cpu --last 6h ram")
+// Errors are printed with a pointer to the help
+//
+// Parameters:
+//   - fs: flag set with the command's options
+//   - args: arguments after the command name
+//
+// Returns:
+//   - positional arguments, in order
+//   - false if the options are invalid
+func parseCommandArgs(fs *flag.FlagSet, args []string) ([]string, bool) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				printHelp()
+			} else {
+				printArgError(fmt.Errorf("%s: %w", fs.Name(), err))
+			}
+			return nil, false
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, true
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// printArgError prints a command line error followed by the usage
+func printArgError(err error) {
+	fmt.Printf(colorRed+"Error: %v\n"+colorReset, err)
+	printUsage()
+}
+
+// optionalValue is a string flag whose value may be left out (e.g. "--csv" or "--csv file")
+type optionalValue struct {
+	value string // Value given (empty if left out)
+	set   bool   // The flag was given
+}
+
+func (v *optionalValue) String() string { return v.value }
+
+func (v *optionalValue) Set(value string) error {
+	v.value, v.set = value, true
+	return nil
+}
+
+// interval is a duration flag that also accepts a plain number of seconds (e.g. "5" or "500ms")
+type interval time.Duration
+
+func (i *interval) String() string { return time.Duration(*i).String() }
+
+func (i *interval) Set(value string) error {
+	duration, err := time.ParseDuration(value)
+	if seconds, convErr := strconv.ParseFloat(value, 64); convErr == nil {
+		duration, err = time.Duration(seconds*float64(time.Second)), nil
+	}
+	if err != nil {
+		return fmt.Errorf("'%s' is not a number of seconds or a duration", value)
+	}
+	if duration < 100*time.Millisecond {
+		return errors.New("must be at least 100ms")
+	}
+	*i = interval(duration)
+	return nil
+}
+
+// listValue is a flag that can be repeated or given a comma separated list (e.g. "--exclude-mount /a,/b")
+type listValue []string
+
+func (l *listValue) String() string { return strings.Join(*l, ",") }
+
+func (l *listValue) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+// byteSize is a size flag (e.g. "2G"), parsed like the sizes of the config file ("0" for none)
+type byteSize uint64
+
+func (b *byteSize) String() string { return common.FormatBytes(uint64(*b)) }
+
+func (b *byteSize) Set(value string) error {
+	bytes, err := common.ParseBytesOrZero(value)
+	if err != nil {
+		return err
+	}
+	*b = byteSize(bytes)
+	return nil
+}
+
+// ramThreshold is a RAM usage flag given as a percentage (e.g. "10%") or a size (e.g. "2G")
+type ramThreshold struct {
+	percent float64 // Percentage of the RAM (0 if a size was given)
+	bytes   uint64  // Resident memory in bytes (0 if a percentage was given)
+}
+
+func (r *ramThreshold) String() string {
+	if r.bytes > 0 {
+		return common.FormatBytes(r.bytes)
+	}
+	return strconv.FormatFloat(r.percent, 'g', -1, 64) + "%"
+}
+
+func (r *ramThreshold) Set(value string) error {
+	if number, ok := strings.CutSuffix(value, "%"); ok {
+		percent, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+		if err != nil || percent <= 0 || percent > 100 {
+			return fmt.Errorf("'%s' is not a percentage between 0 and 100", value)
+		}
+		*r = ramThreshold{percent: percent}
+		return nil
+	}
+	bytes, err := common.ParseBytes(value)
+	if err != nil {
+		return err
+	}
+	*r = ramThreshold{bytes: bytes}
+	return nil
+}
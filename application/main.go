@@ -1,55 +1,326 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"os/user"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/dfialho05/GoMonitor/application/pck"
+	"github.com/dfialho05/GoMonitor/application/pck/alerts"
+	"github.com/dfialho05/GoMonitor/application/pck/burn"
+	"github.com/dfialho05/GoMonitor/application/pck/capability"
+	"github.com/dfialho05/GoMonitor/application/pck/cgroup"
+	"github.com/dfialho05/GoMonitor/application/pck/check"
+	"github.com/dfialho05/GoMonitor/application/pck/clean"
 	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/config"
+	"github.com/dfialho05/GoMonitor/application/pck/container"
 	"github.com/dfialho05/GoMonitor/application/pck/cpu"
+	"github.com/dfialho05/GoMonitor/application/pck/daemon"
 	"github.com/dfialho05/GoMonitor/application/pck/disk"
 	"github.com/dfialho05/GoMonitor/application/pck/gpu"
+	"github.com/dfialho05/GoMonitor/application/pck/health"
+	"github.com/dfialho05/GoMonitor/application/pck/history"
+	"github.com/dfialho05/GoMonitor/application/pck/hwcache"
+	"github.com/dfialho05/GoMonitor/application/pck/launch"
+	"github.com/dfialho05/GoMonitor/application/pck/network"
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+	"github.com/dfialho05/GoMonitor/application/pck/power"
+	"github.com/dfialho05/GoMonitor/application/pck/prometheus"
 	"github.com/dfialho05/GoMonitor/application/pck/ram"
+	"github.com/dfialho05/GoMonitor/application/pck/security"
+	"github.com/dfialho05/GoMonitor/application/pck/sensors"
+	"github.com/dfialho05/GoMonitor/application/pck/services"
+	"github.com/dfialho05/GoMonitor/application/pck/snapshot"
 	"github.com/dfialho05/GoMonitor/application/pck/ui"
+	"github.com/dfialho05/GoMonitor/application/pck/watchdog"
+	"golang.org/x/term"
 )
 
-// Terminal color constants (ANSI codes)
-const (
-	colorReset  = "\033[0m"
-	colorRed    = "\033[31m"
-	colorGreen  = "\033[32m"
-	colorYellow = "\033[33m"
-	colorBlue   = "\033[34m"
-	colorPurple = "\033[35m"
-	colorCyan   = "\033[36m"
-	colorWhite  = "\033[37m"
-	colorBold   = "\033[1m"
+// Terminal colors (ANSI codes), set from the active theme by applyTheme
+var (
+	colorReset  string
+	colorRed    string
+	colorGreen  string
+	colorYellow string
+	colorBlue   string
+	colorPurple string
+	colorCyan   string
+	colorWhite  string
+	colorBold   string
 )
 
 func main() {
+	// Find the config file before it's read, then select the color theme before anything is printed
+	if err := applyPaths(); err != nil {
+		applyTheme()
+		printArgError(err)
+		return
+	}
+	applyTheme()
+	if err := applyLogging(); err != nil {
+		printArgError(err)
+		return
+	}
+	applyFormats()
+	applyBackend()
+	applyCommandTimeout()
+	applyHostLevel()
+
 	// Process command line arguments
 	if len(os.Args) > 1 {
+		inv, err := parseArgs(os.Args[1:])
+		if err != nil {
+			printArgError(err)
+			return
+		}
+
 		// Show header for commands that are not defaultUse and not interactive
-		arg1 := os.Args[1]
-		if arg1 != "-n" && arg1 != "--default" && arg1 != "-f" && arg1 != "--full" {
+		// CSV and generated Prometheus files written to stdout must not be mixed with the header
+		if inv.showHeader() {
 			printMainHeader()
 		}
-		handleCommandLineArgs()
+		inv.run()
+		return
+	}
+
+	// Default behavior: run the command chosen in the config file
+	runDefaultCommand()
+}
+
+// applyPaths moves the config and state directories given by --config-dir and --state-dir,
+// the kernel file systems given by --procfs and --sysfs, and applies --read-only
+// They are removed from the arguments so they can be combined with any command
+//
+// Returns: error if one of them has no directory
+func applyPaths() error {
+	setters := map[string]func(string){
+		"--config-dir": paths.SetConfigDir,
+		"--state-dir":  paths.SetStateDir,
+		"--procfs":     paths.SetProcRoot,
+		"--sysfs":      paths.SetSysRoot,
+	}
+
+	args := os.Args[:1]
+	for i := 1; i < len(os.Args); i++ {
+		if os.Args[i] == "--read-only" {
+			paths.SetReadOnly()
+			continue
+		}
+		name, value, hasValue := strings.Cut(os.Args[i], "=")
+		set, ok := setters[name]
+		if !ok {
+			args = append(args, os.Args[i])
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(os.Args) {
+				return fmt.Errorf("flag needs an argument: %s", name)
+			}
+			i++
+			value = os.Args[i]
+		}
+		if value == "" {
+			return fmt.Errorf("flag needs an argument: %s", name)
+		}
+		set(value)
+	}
+	os.Args = args
+	return nil
+}
+
+// applyLogging sends the log to the file given by --log-file, with debug records if --debug is given
+// Both are removed from the arguments so they can be combined with any command
+//
+// Returns: error if --log-file has no file or the log file can't be opened
+func applyLogging() error {
+	path, debug := "", false
+	args := os.Args[:1]
+	for i := 1; i < len(os.Args); i++ {
+		name, value, hasValue := strings.Cut(os.Args[i], "=")
+		switch {
+		case os.Args[i] == "--debug":
+			debug = true
+		case name == "--log-file":
+			if !hasValue {
+				if i+1 >= len(os.Args) {
+					return fmt.Errorf("flag needs an argument: %s", name)
+				}
+				i++
+				value = os.Args[i]
+			}
+			if value == "" {
+				return fmt.Errorf("flag needs an argument: %s", name)
+			}
+			path = value
+		default:
+			args = append(args, os.Args[i])
+		}
+	}
+	os.Args = args
+
+	logFile, err := common.SetupLogging(path, debug)
+	if err != nil {
+		return err
+	}
+	if debug && path == "" && logFile != "" {
+		fmt.Fprintf(os.Stderr, "gom: debug log: %s\n", logFile)
+	}
+	common.Logger().Debug("started", "args", strings.Join(os.Args[1:], " "))
+	return nil
+}
+
+// applyTheme selects the color theme for every view
+// NO_COLOR or --no-color select the monochrome theme; otherwise the "theme" setting is used
+// --no-color is removed from the arguments so it can be combined with any command
+func applyTheme() {
+	theme := ui.CurrentTheme()
+
+	if ui.ColorDisabled(os.Args[1:]) {
+		theme, _ = ui.ThemeByName(ui.ThemeMonochrome)
+		args := os.Args[:1]
+		for _, arg := range os.Args[1:] {
+			if arg != "--no-color" {
+				args = append(args, arg)
+			}
+		}
+		os.Args = args
+	} else if cfg, err := config.Load(); err == nil && cfg.Theme != "" {
+		// Problems with the config file are reported by the commands that use it
+		if named, ok := ui.ThemeByName(cfg.Theme); ok {
+			theme = named
+		} else {
+			fmt.Printf(theme.Yellow+"⚠ Unknown theme '%s' (available: %s)\n"+theme.Reset, cfg.Theme, strings.Join(ui.ThemeNames(), ", "))
+		}
+	}
+
+	ui.SetTheme(theme)
+	colorReset = theme.Reset
+	colorRed = theme.Red
+	colorGreen = theme.Green
+	colorYellow = theme.Yellow
+	colorBlue = theme.Blue
+	colorPurple = theme.Magenta
+	colorCyan = theme.Cyan
+	colorWhite = theme.White
+	colorBold = theme.Bold
+}
+
+// applyFormats selects the units and timestamp format of every view ("units" and "time_format" settings)
+// Problems with the config file are reported by the commands that use it, so the defaults are kept
+func applyFormats() {
+	if cfg, err := config.Load(); err == nil {
+		// Both values were already validated by config.Load
+		_ = common.SetFormats(cfg.Units, cfg.TimeFormat)
+	}
+}
+
+// applyBackend selects how statistics are collected: GOM_BACKEND, or else the "backend" setting
+// The environment variable wins so a minimal container can switch without a config file
+func applyBackend() {
+	if name := os.Getenv("GOM_BACKEND"); name != "" {
+		if err := common.SetBackend(name); err != nil {
+			fmt.Printf(colorYellow+"⚠ GOM_BACKEND: %v\n"+colorReset, err)
+		}
 		return
 	}
+	if cfg, err := config.Load(); err == nil {
+		// Already validated by config.Load
+		_ = common.SetBackend(cfg.Backend)
+	}
+}
+
+// applyCommandTimeout sets how long collectors wait for external programs ("command_timeout" setting)
+func applyCommandTimeout() {
+	if cfg, err := config.Load(); err == nil {
+		common.SetCommandTimeout(cfg.CommandTimeout.Duration)
+	}
+}
+
+// applyHostLevel makes the RAM and CPU figures relative to the cgroup limits GoMonitor runs
+// under (e.g. docker run --memory 2g --cpus 1.5), unless --host asks for the host totals
+// --host is removed from the arguments so it can be combined with any command
+func applyHostLevel() {
+	host := false
+	args := os.Args[:1]
+	for _, arg := range os.Args[1:] {
+		if arg == "--host" {
+			host = true
+			continue
+		}
+		args = append(args, arg)
+	}
+	os.Args = args
+
+	cgroup.SetHostLevel(host)
+	if limits, ok := cgroup.Effective(); ok && limits.HasMemoryLimit() {
+		common.SetMemoryLimit(limits.MemoryLimit)
+	}
+}
+
+// diskFilter returns the mounts the disk views list ("disk" settings)
+// Problems with the config file are reported by the commands that use it, so the built-in filters are kept
+func diskFilter() disk.Filter {
+	// config.Load returns the defaults if the file is invalid
+	cfg, _ := config.Load()
+	return cfg.Disk.Filter()
+}
+
+// healthScorer returns the health scorer of the "health" and "alerts" settings
+// Like diskFilter, the built-in weights are kept if the config file is invalid
+func healthScorer() *health.Scorer {
+	cfg, _ := config.Load()
+	return health.NewScorer(cfg)
+}
+
+// loadConfig reads the config file
+// Problems with the file are reported as a warning and the defaults are used
+func loadConfig() config.Config {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Printf(colorYellow+"⚠ %v\n"+colorReset, err)
+	}
+	return cfg
+}
+
+// runDefaultCommand runs the command configured for when gom has no arguments
+// Falls back to the default interface if the config file is invalid
+func runDefaultCommand() {
+	cfg := loadConfig()
 
-	// Default behavior: show default interface
-	showDefaultInterface()
+	switch cfg.DefaultCommand {
+	case config.CommandTUI:
+		showInteractiveTUI(cfg)
+	case config.CommandOverview:
+		printMainHeader()
+		showSystemOverview(cfg.Disk.Filter())
+	default:
+		showDefaultInterface(cfg.Disk.Filter(), ui.LogoOptions{}, health.NewScorer(cfg))
+	}
 }
 
 // printMainHeader prints the main application header
 // Displays the logo and basic information about GoMonitor
+// Terminals narrower than the logo get a one-line header instead
 func printMainHeader() {
-	fmt.Println(colorBold + colorCyan)
+	fmt.Print(colorBold + colorCyan)
+	if common.Compact() {
+		common.NewBox("GoMonitor - System Monitor in Go").End()
+		fmt.Println(colorReset)
+		return
+	}
+	fmt.Println()
 	fmt.Println("╔══════════════════════════════════════════════════════════════════════════════════╗")
 	fmt.Println("║                                                                                  ║")
 	fmt.Println("║                        ██████╗  ██████╗ ███╗   ███╗                              ║")
@@ -66,80 +337,36 @@ func printMainHeader() {
 	fmt.Println(colorReset)
 }
 
-// handleCommandLineArgs processes command line arguments
-// Supports various operation modes based on provided arguments
-func handleCommandLineArgs() {
-	arg1 := os.Args[1]
-
-	// Help mode
-	if arg1 == "-h" || arg1 == "--help" {
-		printHelp()
-		return
-	}
-
-	// Startup toggle mode
-	if arg1 == "-s" || arg1 == "--startup" {
-		toggleAutoStart()
-		return
-	}
-
-	// Top processes listing mode
-	if arg1 == "-t" || arg1 == "--top" {
-		n := 10 // Default: top 10
-		if len(os.Args) > 2 {
-			if num, err := strconv.Atoi(os.Args[2]); err == nil {
-				n = num
-			}
+// exportCSV runs a CSV writer against the file given with --csv, or stdout if none was given
+//
+// Parameters:
+//   - path: file to write (empty for stdout)
+//   - write: function that writes the CSV data
+func exportCSV(path string, write func(w io.Writer) error) {
+	if path == "" {
+		if err := write(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, colorRed+"Error writing CSV: %v\n"+colorReset, err)
 		}
-
-		showTopProcesses(n)
-		return
-	}
-
-	// CPU information mode
-	if arg1 == "-c" || arg1 == "--cpu" {
-		showCPUInfo()
-		return
-	}
-
-	// RAM information mode
-	if arg1 == "-r" || arg1 == "--ram" {
-		showRAMInfo()
-		return
-	}
-
-	// GPU information mode
-	if arg1 == "-g" || arg1 == "--gpu" {
-		showGPUInfo()
 		return
 	}
 
-	// Disk information mode
-	if arg1 == "-d" || arg1 == "--disk" {
-		showDiskInfo()
-		return
-	}
-
-	// Complete system overview mode
-	if arg1 == "-a" || arg1 == "--all" {
-		showSystemOverview()
+	file, err := os.Create(path)
+	if err != nil {
+		fmt.Printf(colorRed+"Error: Could not create %s: %v\n"+colorReset, path, err)
 		return
 	}
+	defer file.Close()
 
-	// Interactive TUI mode (full/interactive mode)
-	if arg1 == "-f" || arg1 == "--full" {
-		showInteractiveTUI()
+	if err := write(file); err != nil {
+		fmt.Printf(colorRed+"Error writing CSV: %v\n"+colorReset, err)
 		return
 	}
-
-	// If we got here, unrecognized argument
-	fmt.Printf(colorRed+"Error: Unrecognized argument '%s'\n"+colorReset, arg1)
-	printUsage()
+	fmt.Println(colorGreen + "CSV written to " + path + colorReset)
 }
 
 // printUsage prints basic usage information
 func printUsage() {
-	fmt.Println("\nUsage: gomonitor [options]")
+	fmt.Println("\nUsage: gomonitor [command] [options]")
 	fmt.Println("\nFor more information, use: gomonitor --help")
 }
 
@@ -148,26 +375,126 @@ func printHelp() {
 	fmt.Println(colorBold + colorGreen + "\n=== GoMonitor - Help ===" + colorReset)
 	fmt.Println("\nComplete system monitor written in Go")
 	fmt.Println("\n" + colorBold + "USAGE:" + colorReset)
-	fmt.Println("  gomonitor [options] [arguments]")
+	fmt.Println("  gomonitor [command] [options] [arguments]")
+	fmt.Println("  Commands can also be given by name: the long flag without dashes (e.g. 'gom top 20' for 'gom -t 20'),")
+	fmt.Println("  except tui (-f) and overview (-a). Views can be combined and their options go anywhere,")
+	fmt.Println("  e.g. 'gom --cpu --ram --watch 5' or 'gom --core top 20'")
 
 	fmt.Println("\n" + colorBold + "OPTIONS:" + colorReset)
 	fmt.Println("  " + colorCyan + "-h, --help" + colorReset + "              Shows this help message")
+	fmt.Println("  " + colorCyan + "-n, --default" + colorReset + "           Shows the default interface (logo and system summary)")
+	fmt.Println("      " + colorCyan + "--ascii" + colorReset + " <name>      Logo instead of the detected one (gom, linux, ubuntu, debian, arch, fedora, macos, windows)")
+	fmt.Println("      " + colorCyan + "--ascii-file" + colorReset + " <file> Custom logo from a text file (color codes allowed)")
+	fmt.Println("      " + colorCyan + "--export" + colorReset + " [file]     Writes the default view as text, ANSI (.ans) or PNG (.png) (stdout if no file)")
 	fmt.Println("  " + colorCyan + "-s, --startup" + colorReset + "           Toggle auto-start on terminal startup")
 	fmt.Println("  " + colorCyan + "-f, --full" + colorReset + "              Interactive TUI mode (navigate processes, kill, etc)")
+	fmt.Println("  " + colorCyan + "fix-terminal" + colorReset + "            Restores a terminal left in raw mode (no echo, hidden cursor) by a killed TUI")
 	fmt.Println("  " + colorCyan + "-a, --all" + colorReset + "               Shows complete system overview")
 	fmt.Println("  " + colorCyan + "-c, --cpu" + colorReset + "               Shows detailed CPU information")
 	fmt.Println("  " + colorCyan + "-r, --ram" + colorReset + "               Shows detailed RAM information")
 	fmt.Println("  " + colorCyan + "-g, --gpu" + colorReset + "               Shows GPU information")
-	fmt.Println("  " + colorCyan + "-d, --disk" + colorReset + "              Shows disk information")
+	fmt.Println("  " + colorCyan + "-d, --disk" + colorReset + "              Shows disk information and per-disk process I/O")
+	fmt.Println("      " + colorCyan + "--include-fstype" + colorReset + " <type> Lists a hidden file system type anyway (e.g. squashfs)")
+	fmt.Println("      " + colorCyan + "--exclude-mount" + colorReset + " <path>  Hides a mountpoint and everything below it")
+	fmt.Println("      " + colorCyan + "--min-size" + colorReset + " <size>    Smallest partition listed (default: 2G, 0 for all)")
+	fmt.Println("      " + colorCyan + "--disk-health" + colorReset + "       Shows SMART health of each disk (temperature, wear, reallocated sectors)")
+	fmt.Println("  " + colorCyan + "-i, --io" + colorReset + "                Shows dirty page writeback, I/O wait and disk utilization")
+	fmt.Println("  " + colorCyan + "-l, --logins" + colorReset + "            Shows active SSH sessions and recent failed logins")
+	fmt.Println("  " + colorCyan + "-S, --sensors" + colorReset + "           Shows all hardware sensors (temperatures, fans, voltages)")
+	fmt.Println("      " + colorCyan + "--net-info" + colorReset + "          Shows addresses, default gateway, link, DNS servers and public IP")
+	fmt.Println("      " + colorCyan + "--ports" + colorReset + "             Shows listening TCP/UDP ports with their processes and connection states")
+	fmt.Println("      " + colorCyan + "--power" + colorReset + " [N]         Shows the N processes causing the most CPU wake-ups (default: 10)")
+	fmt.Println("      " + colorCyan + "--energy" + colorReset + " [N]        Shows the N processes with the most estimated CPU power (RAPL, default: 10)")
+	fmt.Println("      " + colorCyan + "--services" + colorReset + "          Shows systemd services with the CPU/RAM of their processes")
+	fmt.Println("      " + colorCyan + "--containers" + colorReset + "        Shows CPU, memory and I/O of Docker/Podman/containerd containers")
+	fmt.Println("  " + colorCyan + "check" + colorReset + " [options]       Exits 2 (Nagios CRITICAL) if usage is above --cpu-max, --ram-max, --disk-max or --inodes-max (%)")
+	fmt.Println("  " + colorCyan + "clean" + colorReset + " [--dry-run]      Shows reclaimable disk space (caches, old logs, Docker, /tmp)")
+	fmt.Println("      " + colorCyan + "--apply" + colorReset + " <ids>       Frees the listed categories (e.g. --apply journal,tmp)")
+	fmt.Println("  " + colorCyan + "burn" + colorReset + " [options]        Generates CPU/memory/disk load while showing the TUI")
+	fmt.Println("      " + colorCyan + "--cpu" + colorReset + " <N>          Busy workers (default: all cores); --mem <size>, --disk <size>")
+	fmt.Println("      " + colorCyan + "--duration" + colorReset + " <time>  How long the load runs (default: 60s)")
+	fmt.Println("  " + colorCyan + "throttle" + colorReset + " <PID>        Limits a process's CPU and memory instead of killing it")
+	fmt.Println("      " + colorCyan + "--cpu" + colorReset + " <cores>      CPU time in cores (e.g. 0.5); --memory <size> (e.g. 4G)")
+	fmt.Println("  " + colorCyan + "kill" + colorReset + " [options]        Kills the processes matching --name, --regex, --older-than, --cpu-above, --ram-above")
+	fmt.Println("      " + colorCyan + "--dry-run" + colorReset + "           Only lists the processes that would be killed")
+	fmt.Println("  " + colorCyan + "run" + colorReset + " -- <command>      Runs a command and sums up its CPU time, peak memory and disk I/O on exit")
+	fmt.Println("  " + colorCyan + "-p, --process" + colorReset + " <PID>   Shows the CPU, RAM and threads of a process every 2s (--interval N)")
+	fmt.Println("      " + colorCyan + "--name" + colorReset + " <name>       Monitors every process with that name as a group, following respawns")
+	fmt.Println("      " + colorCyan + "--pattern" + colorReset + " <regex>   Same, for names or command lines matching a regular expression")
+	fmt.Println("  " + colorCyan + "watch-pid" + colorReset + " <PID|name>  Watches a process and reports when it exits, with its last statistics")
+	fmt.Println("      " + colorCyan + "--restart-cmd" + colorReset + " <cmd> Runs <cmd> after each exit (--max-restarts N, default 5)")
+	fmt.Println("      " + colorCyan + "--webhook" + colorReset + " <url>     POSTs each exit as JSON; --interval N (default 2s)")
+	fmt.Println("  " + colorCyan + "prometheus" + colorReset + " rules      Writes the alert rules as a Prometheus rule file (node_exporter metrics)")
+	fmt.Println("  " + colorCyan + "prometheus" + colorReset + " dashboard  Writes a matching Grafana dashboard (JSON)")
+	fmt.Println("  " + colorCyan + "daemon" + colorReset + "                  Evaluates alerts and stores the history in the background (one per state dir)")
+	fmt.Println("      " + colorCyan + "status|stop|reload" + colorReset + "  Controls the running daemon")
+	fmt.Println("  " + colorCyan + "snapshot" + colorReset + " [--top N]    Writes the key metrics and top processes as JSON")
+	fmt.Println("  " + colorCyan + "diff" + colorReset + " <a> <b>          Compares two snapshot files side by side")
+	fmt.Println("      " + colorCyan + "--remote" + colorReset + "            <a> and <b> are SSH hosts running gom (\"local\" for this one)")
+	fmt.Println("  " + colorCyan + "chart" + colorReset + " <series>        Charts stored cpu, ram, load or temp history (--last 1h, --blocks)")
+	fmt.Println("  " + colorCyan + "events" + colorReset + " [--since 24h]   Lists stored alerts and events (OOM kills, reboots)")
+	fmt.Println("      " + colorCyan + "--doctor" + colorReset + "            Shows which metrics can be collected on this OS")
+	fmt.Println("      " + colorCyan + "--health" + colorReset + "            Prints the system health score (0-100) and its components on one line")
+	fmt.Println("      " + colorCyan + "--no-color" + colorReset + "          Disables colors (same as the NO_COLOR environment variable)")
+	fmt.Println("      " + colorCyan + "--config-dir" + colorReset + " <dir>  Reads config.json from <dir> (same as GOM_CONFIG_DIR)")
+	fmt.Println("      " + colorCyan + "--state-dir" + colorReset + " <dir>   Keeps the history in <dir> (same as GOM_STATE_DIR)")
+	fmt.Println("      " + colorCyan + "--procfs" + colorReset + " <dir>      Reads /proc from <dir>, e.g. /host/proc in a container (same as HOST_PROC)")
+	fmt.Println("      " + colorCyan + "--sysfs" + colorReset + " <dir>       Reads /sys from <dir> (same as HOST_SYS)")
+	fmt.Println("      " + colorCyan + "--read-only" + colorReset + "         Never writes history, metrics or daemon files (same as GOM_READ_ONLY=1)")
+	fmt.Println("      " + colorCyan + "--host" + colorReset + "              Reports the host RAM and CPUs instead of the container's cgroup limits")
+	fmt.Println("      " + colorCyan + "--log-file" + colorReset + " <file>   Logs collection failures, external commands and TUI errors to <file>")
+	fmt.Println("      " + colorCyan + "--debug" + colorReset + "             Also logs debug details (to gom.log in the state directory without --log-file)")
+	fmt.Println("  " + colorCyan + "paths" + colorReset + "                   Shows where GoMonitor reads its settings and writes its data")
+	fmt.Println("      " + colorCyan + "--watch" + colorReset + " <N>         Shows the views again every N seconds (e.g. 5 or 500ms) until Ctrl+C")
+	fmt.Println("      " + colorCyan + "--verbose" + colorReset + "           Reports processes and partitions that couldn't be read, and why (stderr)")
 	fmt.Println("  " + colorCyan + "-t, --top" + colorReset + " [N]           Shows top N processes (default: 10)")
+	fmt.Println("      " + colorCyan + "--core" + colorReset + "              Adds the CPU core each process last ran on")
+	fmt.Println("      " + colorCyan + "--groups" + colorReset + "            Adds the process group and session IDs (E in the TUI, X kills a group)")
+	fmt.Println("      " + colorCyan + "--wide" + colorReset + "              Adds the full command line of each process (A in the TUI)")
+	fmt.Println("      " + colorCyan + "--user" + colorReset + " <name>       Only shows processes owned by <name>")
+	fmt.Println("      " + colorCyan + "--sort" + colorReset + " <field>      Ranks by cpu (default), ram, time (CPU time used since start), pid, name or io (disk I/O)")
+	fmt.Println("      " + colorCyan + "--reverse" + colorReset + "           Reverses the order (e.g. lowest CPU usage first, names from Z to A)")
+	fmt.Println("      " + colorCyan + "--columns" + colorReset + " <list>    Shows these columns: " + common.ProcessColumnNames())
+	fmt.Println("      " + colorCyan + "--group" + colorReset + "             One row per application with the total of its processes (U in the TUI)")
+	fmt.Println("      " + colorCyan + "--csv" + colorReset + " [file]        Writes -t, -c, -r or -d listings as CSV (stdout if no file)")
+	fmt.Println("      " + colorCyan + "--top-io" + colorReset + " [N]        Shows the top N processes by disk read/write speed (default: 10)")
+	fmt.Println("      " + colorCyan + "--top-fds" + colorReset + " [N]       Shows the top N processes by open file descriptors, with their limits (default: 10)")
 
 	fmt.Println("\n" + colorBold + "EXAMPLES:" + colorReset)
-	fmt.Println("  gom                          # Shows default interface")
+	fmt.Println("  gom                          # Runs the configured default command (default interface)")
 	fmt.Println("  gom -s                       # Toggle auto-start on terminal startup")
 	fmt.Println("  gom -f                       # Interactive TUI mode")
 	fmt.Println("  gom --all                    # Shows complete overview")
 	fmt.Println("  gom --cpu                    # Shows only CPU information")
 	fmt.Println("  gom -t 20                    # Shows top 20 processes")
+	fmt.Println("  gom -t 20 --core             # Shows top 20 processes and their CPU core")
+	fmt.Println("  gom -t 20 --user $USER       # Shows your own top 20 processes")
+	fmt.Println("  gom -t --sort time           # Shows the processes that used the most CPU time since they started")
+	fmt.Println("  gom -t --columns pid,cpu,cmd # Shows the top processes with their full command line")
+	fmt.Println("  gom cpu ram --watch 5        # Shows CPU and RAM information every 5 seconds")
+	fmt.Println("  gom -d --csv disks.csv       # Writes disk information to disks.csv")
+	fmt.Println("  gom --export specs.png       # Saves the logo and system summary as an image")
+	fmt.Println("  gom burn --cpu 4 --mem 2G    # Loads 4 cores and 2 GB of RAM for 60s")
+	fmt.Println("  gom throttle 1234 --cpu 2    # Limits process 1234 to 2 cores")
+	fmt.Println("  gom chart cpu ram --last 6h  # Charts CPU and RAM usage of the last 6 hours")
+
+	fmt.Println("\n" + colorBold + "CONFIGURATION:" + colorReset)
+	fmt.Println("  ~/.config/gomonitor/config.json (or $XDG_CONFIG_HOME, see 'gom paths'), e.g. {\"default_command\": \"tui\"}")
+	fmt.Println("  default_command: default, tui or overview (what gom runs without arguments)")
+	fmt.Println("  history_enabled: true to record alerts, events and usage (see 'gom events' and 'gom chart')")
+	fmt.Println("  refresh_interval: how often the interactive mode refreshes (e.g. \"2s\", default 2s)")
+	fmt.Println("  graph_history: how far back the TUI graphs go (e.g. \"5m\", press G to show them)")
+	fmt.Println("  theme: " + strings.Join(ui.ThemeNames(), ", ") + " (color theme)")
+	fmt.Println("  disk: mounts listed, e.g. {\"include_fstypes\": [\"squashfs\"], \"exclude_mounts\": [\"/mnt/backup\"], \"min_size\": \"500M\"}")
+	fmt.Println("  units: binary (1024, default) or si (1000); time_format: 24h (default), 12h or rfc3339")
+	fmt.Println("  throttle: limits of the TUI throttle key, e.g. {\"cpu\": 2, \"memory\": \"4G\"} (default: 1 core)")
+	fmt.Println("  fan_control: true to set fan levels in the Sensors tab of the TUI (needs root, off by default)")
+	fmt.Println("  public_ip_url: endpoint --net-info asks for the public IP, e.g. \"https://api.ipify.org\" (off by default)")
+	fmt.Println("  pinned: process names listed first in the TUI, e.g. [\"nginx\", \"postgres\"] (toggled with *)")
+	fmt.Println("  when_locked: slower TUI refresh while the screen is locked, e.g. {\"enabled\": true, \"refresh_interval\": \"30s\"}")
+	fmt.Println("  alerts: change alert rules per metric, e.g. {\"disk\": {\"threshold\": 85, \"thresholds\": {\"/data\": 97}}}")
+	fmt.Println("  health: weights of the health score, e.g. {\"weights\": {\"temperature\": 0, \"disk\": 3}}")
+	fmt.Println("  keys: remap TUI keys, e.g. {\"up\": [\"up\", \"k\"], \"kill\": []} ([] disables an action)")
 
 	fmt.Println("\n" + colorBold + "Author:" + colorReset)
 	fmt.Println("  GoMonitor is a system monitoring tool like neofetch based on Go")
@@ -177,186 +504,1292 @@ func printHelp() {
 
 // showSystemOverview shows a complete overview of all system resources
 // This is the main function that aggregates information from all modules
-func showSystemOverview() {
-	fmt.Println(colorBold + colorYellow + "\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━" + colorReset)
-	fmt.Println(colorBold + "                        SYSTEM OVERVIEW" + colorReset)
-	fmt.Println(colorBold + colorYellow + "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━" + colorReset)
+// Every section is collected at the same time and then printed in order, so the CPU usage,
+// the process CPU and the disk I/O, each measured over an interval, overlap
+func showSystemOverview(filter disk.Filter) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.CollectTimeout)
+	defer cancel()
+
+	// One process sample is shared by the CPU, RAM and top processes sections
+	processes := common.Async(ctx, collectProcessSample)
+	cpuReadings := startCPUReadings(ctx, processes)
+	gpus := common.Async(ctx, func() ([]gpu.GPUStats, error) { return gpu.GetAllGPUStats(ctx) })
+	diskIO := startDiskIO(ctx)
+
+	// The rules are as wide as the boxes (narrower on narrow terminals)
+	width := common.OutputWidth()
+	rule := strings.Repeat("━", width)
+	fmt.Println(colorBold + colorYellow + "\n" + rule + colorReset)
+	fmt.Println(colorBold + strings.Repeat(" ", min(24, (width-len("SYSTEM OVERVIEW"))/2)) + "SYSTEM OVERVIEW" + colorReset)
+	fmt.Println(colorBold + colorYellow + rule + colorReset)
 
 	// 1. CPU Information
 	fmt.Println(colorBold + colorBlue + "\n[1] PROCESSOR (CPU)" + colorReset)
-	showCPUInfo()
+	printCPUInfo(cpuReadings)
 
 	// 2. RAM Information
 	fmt.Println(colorBold + colorBlue + "\n[2] RAM MEMORY" + colorReset)
-	showRAMInfo()
+	printRAMInfo(processes)
 
 	// 3. GPU Information
 	fmt.Println(colorBold + colorBlue + "\n[3] GRAPHICS CARD (GPU)" + colorReset)
-	showGPUInfo()
+	printGPUInfo(gpus)
 
 	// 4. Disk Information
 	fmt.Println(colorBold + colorBlue + "\n[4] STORAGE" + colorReset)
-	showDiskInfo()
+	printDiskInfo(filter, diskIO)
 
 	// 5. Top Processes
 	fmt.Println(colorBold + colorBlue + "\n[5] MOST ACTIVE PROCESSES" + colorReset)
-	showTopProcesses(10)
+	if sample, err := processes(); err != nil {
+		printCollectionError("processes", err)
+	} else {
+		pck.PrintTopProcessesOf(sample.processes, sample.collection, 10, "", "cpu", common.TableOptions{})
+	}
+
+	// 6. Network connections
+	fmt.Println(colorBold + colorBlue + "\n[6] NETWORK CONNECTIONS" + colorReset)
+	if counts, err := network.GetConnectionStates(); err != nil {
+		printCollectionError("network connections", err)
+	} else {
+		network.PrintConnectionStates(counts)
+	}
 
 	// Footer with tips
-	fmt.Println(colorBold + colorYellow + "\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━" + colorReset)
+	fmt.Println(colorBold + colorYellow + "\n" + rule + colorReset)
 	fmt.Println(colorCyan + "\n💡 Tip: Use 'gomonitor --help' to see all available options" + colorReset)
 	fmt.Println()
 }
 
+// processSample is one collection of every process, shared by the sections that list processes
+type processSample struct {
+	processes  []common.ProcessInfo
+	collection common.CollectionStats
+}
+
+// collectProcessSample collects every process, with the CPU usage over common.DefaultSampleInterval
+func collectProcessSample() (processSample, error) {
+	processes, collection, err := common.CollectAllProcessInfoWithStats()
+	if err != nil {
+		return processSample{}, fmt.Errorf("error collecting processes: %w", err)
+	}
+	return processSample{processes: processes, collection: collection}, nil
+}
+
+// cpuReadings are the collections of the CPU view, started together by startCPUReadings
+type cpuReadings struct {
+	stats     func() (cpu.GeneralStats, error)
+	clusters  func() ([]cpu.ClusterStats, error) // Empty on CPUs with one type of core
+	processes func() (processSample, error)
+}
+
+// startCPUReadings starts the collections of the CPU view in the background
+// The usage and the cluster usage are each measured over one second, so they overlap
+//
+// Parameters:
+//   - ctx: shared deadline of the collections
+//   - processes: process sample, shared with the other sections
+func startCPUReadings(ctx context.Context, processes func() (processSample, error)) cpuReadings {
+	return cpuReadings{
+		stats: common.Async(ctx, cpu.GetGeneralStats),
+		clusters: common.Async(ctx, func() ([]cpu.ClusterStats, error) {
+			if !cpu.IsHeterogeneous() {
+				return nil, nil
+			}
+			return cpu.GetClusterStats()
+		}),
+		processes: processes,
+	}
+}
+
 // showCPUInfo shows detailed information about the CPU
 func showCPUInfo() {
+	ctx, cancel := context.WithTimeout(context.Background(), common.CollectTimeout)
+	defer cancel()
+	printCPUInfo(startCPUReadings(ctx, common.Async(ctx, collectProcessSample)))
+}
+
+// printCPUInfo prints the CPU view once its readings are collected
+func printCPUInfo(readings cpuReadings) {
 	// Get general CPU statistics
-	stats, err := cpu.GetGeneralStats()
+	stats, err := readings.stats()
 	if err != nil {
-		fmt.Printf(colorRed+"Error getting CPU information: %v\n"+colorReset, err)
+		printCollectionError("CPU information", err)
 		return
 	}
 
+	// Warn first if the CPU is throttled, since it explains a slow machine better than the usage
+	if warning := stats.Throttle.Warning(); warning != "" {
+		fmt.Println(colorRed + "⚠ " + warning + colorReset)
+	}
+
 	// Print general statistics
 	cpu.PrintGeneralStats(stats)
 
+	// On heterogeneous CPUs (big.LITTLE, P/E cores) show cores grouped by cluster
+	clusters, err := readings.clusters()
+	if err != nil || len(clusters) > 0 {
+		fmt.Println(colorPurple + "\n→ Core Clusters:" + colorReset)
+		if err != nil {
+			printCollectionError("CPU clusters", err)
+		} else {
+			cpu.PrintClusterStats(clusters)
+		}
+	}
+
 	// Show top 5 processes by CPU usage
 	fmt.Println(colorPurple + "\n→ Top 5 Processes by CPU Usage:" + colorReset)
-	if err := cpu.PrintTopProcessesByCPU(5); err != nil {
-		fmt.Printf(colorRed+"Error getting processes: %v\n"+colorReset, err)
+	if sample, err := readings.processes(); err != nil {
+		printCollectionError("processes", err)
+	} else {
+		cpu.PrintTopProcessesByCPU(sample.processes, 5)
 	}
 }
 
 // showRAMInfo shows detailed information about RAM
 func showRAMInfo() {
+	ctx, cancel := context.WithTimeout(context.Background(), common.CollectTimeout)
+	defer cancel()
+	printRAMInfo(common.Async(ctx, collectProcessSample))
+}
+
+// printRAMInfo prints the RAM view, with the top processes from a process sample
+func printRAMInfo(processes func() (processSample, error)) {
 	// Get general RAM statistics
 	stats, err := ram.GetRamGeneral()
 	if err != nil {
-		fmt.Printf(colorRed+"Error getting RAM information: %v\n"+colorReset, err)
+		printCollectionError("RAM information", err)
 		return
 	}
 
 	// Print general statistics
 	ram.PrintGeneralStats(stats)
 
+	// Inside a container the cgroup limit, not the host RAM, triggers the OOM killer
+	if limits, err := cgroup.Self(); err == nil && (limits.HasMemoryLimit() || limits.HasCPULimit()) {
+		fmt.Println(colorPurple + "\n→ Container Limits:" + colorReset)
+		cgroup.PrintLimits(limits)
+	}
+
 	// Show Swap information
 	fmt.Println(colorPurple + "\n→ Swap Memory:" + colorReset)
 	if err := ram.PrintSwapStats(); err != nil {
-		fmt.Printf(colorRed+"Error getting swap information: %v\n"+colorReset, err)
+		printCollectionError("swap information", err)
+	}
+
+	// Show which processes were pushed into swap (only if swap is in use)
+	if _, used, _, err := ram.GetSwapMemory(); err == nil && used > 0 {
+		fmt.Println(colorPurple + "\n→ Top 5 Processes by Swap Usage:" + colorReset)
+		if err := ram.PrintTopProcessesBySwap(5); err != nil {
+			printCollectionError("processes", err)
+		}
 	}
 
 	// Show top 5 processes by RAM usage
 	fmt.Println(colorPurple + "\n→ Top 5 Processes by RAM Usage:" + colorReset)
-	if err := ram.PrintTopProcessesByRAM(5); err != nil {
-		fmt.Printf(colorRed+"Error getting processes: %v\n"+colorReset, err)
+	if sample, err := processes(); err != nil {
+		printCollectionError("processes", err)
+	} else {
+		ram.PrintTopProcessesByRAM(sample.processes, 5)
+	}
+}
+
+// showDiskHealth shows the SMART health of every physical disk
+func showDiskHealth() {
+	devices, err := disk.GetDeviceHealth()
+	if err != nil {
+		printCollectionError("disk health", err)
+		return
+	}
+	disk.PrintDeviceHealth(devices)
+
+	// Without SMART access only the model and temperature are known
+	for _, device := range devices {
+		if device.Source == disk.HealthSourceSysfs {
+			fmt.Println(colorYellow + "⚠ Some disks show sysfs data only; install smartctl (smartmontools) and run as root for full SMART data" + colorReset)
+			break
+		}
 	}
 }
 
-// showGPUInfo shows information about the GPU
+// showGPUInfo shows information about all GPUs
 func showGPUInfo() {
-	// Get GPU statistics
-	stats, err := gpu.GetGPUStats()
+	ctx, cancel := context.WithTimeout(context.Background(), common.CollectTimeout)
+	defer cancel()
+	printGPUInfo(common.Async(ctx, func() ([]gpu.GPUStats, error) { return gpu.GetAllGPUStats(ctx) }))
+}
+
+// printGPUInfo prints the statistics of every GPU once they are collected
+func printGPUInfo(gpus func() ([]gpu.GPUStats, error)) {
+	// Get statistics from every GPU in the system
+	allStats, err := gpus()
+	if errors.Is(err, common.ErrCommandTimeout) {
+		fmt.Printf(colorYellow+"⚠ GPU not responding: %v\n"+colorReset, err)
+		fmt.Println(colorYellow + "  The NVIDIA driver may be broken; the wait can be changed with the \"command_timeout\" setting" + colorReset)
+		return
+	}
 	if err != nil {
 		fmt.Printf(colorYellow+"⚠ Could not detect GPU: %v\n"+colorReset, err)
 		return
 	}
 
 	// Print GPU statistics
-	gpu.PrintGPUStats(stats)
+	gpu.PrintAllGPUStats(allStats)
 }
 
 // showDiskInfo shows information about disks
-func showDiskInfo() {
+func showDiskInfo(filter disk.Filter) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.CollectTimeout)
+	defer cancel()
+	printDiskInfo(filter, startDiskIO(ctx))
+}
+
+// startDiskIO starts measuring the disk I/O of every process over one second, in the background
+func startDiskIO(ctx context.Context) func() ([]disk.DeviceProcesses, error) {
+	return common.Async(ctx, func() ([]disk.DeviceProcesses, error) {
+		return disk.GetProcessIOByDevice(time.Second)
+	})
+}
+
+// printDiskInfo prints the disk view, with the process I/O once it is measured
+func printDiskInfo(filter disk.Filter, diskIO func() ([]disk.DeviceProcesses, error)) {
 	// Show total statistics
-	if err := disk.PrintTotalStorageStats(); err != nil {
-		fmt.Printf(colorRed+"Error getting total statistics: %v\n"+colorReset, err)
+	if err := disk.PrintTotalStorageStats(filter); err != nil {
+		printCollectionError("total statistics", err)
 		return
 	}
 
 	// Show all devices
 	fmt.Println(colorPurple + "\n→ Individual Devices:" + colorReset)
-	if err := disk.PrintStorageDevices(); err != nil {
-		fmt.Printf(colorRed+"Error getting devices: %v\n"+colorReset, err)
+	if err := disk.PrintStorageDevices(filter); err != nil {
+		printCollectionError("devices", err)
+	}
+
+	// Show which processes are doing I/O on each disk
+	fmt.Println(colorPurple + "\n→ Processes by Disk I/O:" + colorReset)
+	devices, err := diskIO()
+	if err != nil {
+		printCollectionError("process I/O", err)
+		return
 	}
+	disk.PrintProcessIOByDevice(devices, 5)
 }
 
-// showTopProcesses shows the N most active processes in the system
-// Sorted by CPU usage
-func showTopProcesses(n int) {
-	if err := pck.PrintTopProcesses(n); err != nil {
-		fmt.Printf(colorRed+"Error getting processes: %v\n"+colorReset, err)
+// showIOInfo shows dirty page writeback volumes correlated with I/O wait and disk utilization
+// Helps diagnose system stalls during large file copies
+func showIOInfo() {
+	stats, err := ram.GetWritebackStats(time.Second)
+	if err != nil {
+		printCollectionError("I/O information", err)
+		return
 	}
+
+	ram.PrintWritebackStats(stats)
 }
 
-// Auxiliary function to get process association statistics
-// (maintained for compatibility with existing code)
-func getProcessAssociationStats() {
-	fmt.Println(colorBold + colorGreen + "\n=== Process Association Statistics ===" + colorReset)
+// showLoginActivity shows active SSH sessions and recent failed login attempts
+// Failed logins require access to journald or the auth logs (usually root)
+func showLoginActivity() {
+	sessions, err := security.GetSSHSessions()
+	if err != nil {
+		printCollectionError("SSH sessions", err)
+	} else {
+		security.PrintSSHSessions(sessions)
+	}
 
-	// Get all processes
-	processes, err := pck.GetProcessAssociation()
+	security.PrintFailedLogins(security.GetFailedLogins())
+}
+
+// showNetInfo shows the addresses of every interface, the default gateway, the DNS servers
+// and, if an endpoint is configured, the public IP
+func showNetInfo() {
+	ctx, cancel := context.WithTimeout(context.Background(), common.CollectTimeout)
+	defer cancel()
+
+	info, err := network.GetInfo(ctx, loadConfig().PublicIPURL)
 	if err != nil {
-		fmt.Printf(colorRed+"Error getting processes: %v\n"+colorReset, err)
+		printCollectionError("network information", err)
 		return
 	}
+	network.PrintInfo(info)
+}
 
-	fmt.Printf("\n"+colorCyan+"Total monitored processes: "+colorReset+"%d\n", len(processes))
+// showPorts shows the listening sockets with the process that owns each one, and the
+// number of TCP connections in each state
+func showPorts() {
+	ctx, cancel := context.WithTimeout(context.Background(), common.CollectTimeout)
+	defer cancel()
 
-	// Calculate aggregate statistics
-	var totalCPU float64
-	var totalRAM float32
+	sample, err := collectProcessSample()
+	if err != nil {
+		printCollectionError("processes", err)
+		return
+	}
+	sockets, err := network.GetListeningSockets(ctx, sample.processes)
+	if err != nil {
+		printCollectionError("listening ports", err)
+		return
+	}
+	network.PrintListeningSockets(sockets)
 
-	for _, p := range processes {
-		totalCPU += p.CPUPercentage
-		totalRAM += p.RAMPercentage
+	if counts, err := network.GetConnectionStates(); err == nil {
+		fmt.Println(colorCyan + "\nTCP connections: " + colorReset + network.FormatStates(counts))
 	}
+}
 
-	fmt.Printf(colorYellow+"Total CPU usage (sum of all processes): "+colorReset+"%.2f%%\n", totalCPU)
-	fmt.Printf(colorYellow+"Total RAM usage (sum of all processes): "+colorReset+"%.2f%%\n", totalRAM)
+// showSensorsInfo shows the temperatures, fan speeds and voltages of every hwmon chip
+func showSensorsInfo() {
+	chips, err := sensors.GetChips()
+	if err != nil {
+		fmt.Printf(colorRed+"Error getting sensors: %v\n"+colorReset, err)
+		return
+	}
 
-	// Show example of specific process
-	if len(processes) > 0 {
-		fmt.Println(colorPurple + "\n→ Example - First Process:" + colorReset)
-		p := processes[0]
-		fmt.Printf("  PID:  %d\n", p.PID)
-		fmt.Printf("  Name: %s\n", p.Name)
-		fmt.Printf("  CPU:  %.2f%%\n", p.CPUPercentage)
-		fmt.Printf("  RAM:  %.2f%% (%s)\n", p.RAMPercentage, common.FormatBytes(p.RAMBytes))
+	sensors.PrintChips(chips)
+}
+
+// showWakeups shows the processes that wake the CPU up most often
+// Useful on laptops to find battery drain that CPU usage doesn't explain
+func showWakeups(n int) {
+	fmt.Printf(colorCyan+"Counting wake-ups for %s...\n"+colorReset, power.SampleInterval)
+
+	wakeups, total, err := power.GetTopWakeups(n)
+	if err != nil {
+		printCollectionError("wake-ups", err)
+		return
 	}
+
+	power.PrintWakeups(wakeups, total)
 }
 
-// showDefaultInterface shows the default style interface
-// GoMonitor logo on the left and system information on the right
-func showDefaultInterface() {
-	if err := ui.PrintDefaultStyle(); err != nil {
-		fmt.Printf(colorRed+"Error showing default interface: %v\n"+colorReset, err)
+// showEnergy shows the processes the CPU package power is attributed to
+// Useful to compare the battery impact of programs (Intel/AMD CPUs, usually needs root)
+func showEnergy(n int) {
+	fmt.Printf(colorCyan+"Measuring CPU power for %s...\n"+colorReset, power.SampleInterval)
+
+	energy, watts, err := power.GetTopEnergy(n)
+	if errors.Is(err, power.ErrNoRAPL) {
+		fmt.Printf(colorYellow+"⚠ Energy: %v\n"+colorReset, err)
+		return
 	}
+	if err != nil {
+		printCollectionError("energy", err)
+		return
+	}
+
+	power.PrintEnergy(energy, watts)
 }
 
-// showInteractiveTUI starts the interactive TUI interface
-// Allows navigating through processes, killing processes, sorting, etc.
-func showInteractiveTUI() {
-	// Check if we're in an interactive terminal
-	fileInfo, err := os.Stdin.Stat()
+// showServices shows the active and failed systemd services and the usage of their processes
+func showServices() {
+	list, err := services.GetServices()
 	if err != nil {
-		fmt.Printf(colorRed+"Error: Could not access stdin: %v\n"+colorReset, err)
-		fmt.Println(colorYellow + "\nInteractive mode requires a TTY terminal." + colorReset)
-		fmt.Println("Use: gomonitor --all  to see information without interactivity")
+		printCollectionError("systemd services", err)
 		return
 	}
 
-	if (fileInfo.Mode() & os.ModeCharDevice) == 0 {
-		fmt.Printf(colorRed + "Error: Interactive mode requires a TTY terminal.\n" + colorReset)
-		fmt.Println(colorYellow + "It seems that input is being redirected or executed in a pipe." + colorReset)
-		fmt.Println("\nUse: gomonitor --all  to see information without interactivity")
+	services.PrintServices(list)
+	if len(list) == 0 {
 		return
 	}
 
-	tui := ui.NewInteractiveTUI()
-	if err := tui.Run(); err != nil {
-		fmt.Printf(colorRed+"\nError running interactive interface: %v\n"+colorReset, err)
-		fmt.Println(colorYellow + "\nTip: Make sure you're running in a real interactive terminal." + colorReset)
+	usages := make([]cgroup.LimitUsage, len(list))
+	for i, service := range list {
+		usages[i] = service.LimitUsage()
+	}
+	cgroup.PrintLimitUsage("Services Against Their Limits (MemoryMax, CPUQuota)", usages, limitColors())
+}
+
+// showContainers shows the resource usage of every running container
+func showContainers() {
+	containers, err := container.List()
+	if err != nil {
+		printCollectionError("containers", err)
+		return
+	}
+
+	container.PrintContainers(containers)
+	if len(containers) == 0 {
+		return
+	}
+
+	usages := make([]cgroup.LimitUsage, len(containers))
+	for i, c := range containers {
+		usages[i] = c.LimitUsage()
+	}
+	cgroup.PrintLimitUsage("Containers Against Their Limits", usages, limitColors())
+}
+
+// limitColors returns the theme colors of the usage levels against cgroup limits
+func limitColors() cgroup.LevelColors {
+	return cgroup.LevelColors{Normal: colorGreen, Warning: colorYellow, Critical: colorRed, Reset: colorReset}
+}
+
+// runCheck samples the usage once and exits with a Nagios/Icinga plugin status
+// e.g. "gom check --cpu-max 90 --ram-max 80 --disk-max 95" exits 2 if any value is above its maximum
+// The output is a single plain line with performance data, as monitoring systems expect
+func runCheck(args []string) {
+	var thresholds check.Thresholds
+	fs := newFlagSet("check")
+	fs.Float64Var(&thresholds.CPU, "cpu-max", 0, "maximum CPU usage (%)")
+	fs.Float64Var(&thresholds.RAM, "ram-max", 0, "maximum RAM usage (%)")
+	fs.Float64Var(&thresholds.Disk, "disk-max", 0, "maximum usage of every disk (%)")
+	fs.Float64Var(&thresholds.Inodes, "inodes-max", 0, "maximum inode usage of every disk (%)")
+	disks := diskFilter()
+	addDiskFlags(fs, &disks)
+	positional, ok := parseCommandArgs(fs, args)
+	if !ok {
+		os.Exit(int(check.StatusUnknown))
+	}
+	if len(positional) > 0 {
+		printArgError(fmt.Errorf("unrecognized argument '%s'", positional[0]))
+		os.Exit(int(check.StatusUnknown))
+	}
+
+	result := check.Run(thresholds, disks)
+	fmt.Println(result)
+	os.Exit(int(result.Status))
+}
+
+// runClean reports reclaimable disk space and frees it only for the categories given with --apply
+// Without --apply (or with --dry-run) nothing is changed
+func runClean(args []string) {
+	fs := newFlagSet("clean")
+	dryRun := fs.Bool("dry-run", false, "only show the reclaimable space")
+	apply := fs.String("apply", "", "comma separated categories to free")
+	if positional, ok := parseCommandArgs(fs, args); !ok {
+		return
+	} else if len(positional) > 0 {
+		printArgError(fmt.Errorf("unrecognized argument '%s'", positional[0]))
+		return
+	}
+
+	fmt.Println(colorCyan + "Scanning for reclaimable space..." + colorReset)
+	candidates := clean.Scan()
+	clean.PrintCandidates(candidates)
+
+	if *apply == "" || *dryRun {
+		if len(candidates) > 0 {
+			fmt.Println("\nDry run: nothing was removed. Free space with e.g. 'gom clean --apply " + candidates[0].ID + "'")
+		}
+		return
+	}
+
+	// Only the categories explicitly listed are cleaned
+	for _, id := range strings.Split(*apply, ",") {
+		found := false
+		for _, candidate := range candidates {
+			if candidate.ID != id {
+				continue
+			}
+			found = true
+			fmt.Printf(colorCyan+"\n→ Cleaning %s (%s)\n"+colorReset, candidate.ID, common.FormatBytes(candidate.Bytes))
+			if err := clean.Apply(candidate); err != nil {
+				fmt.Printf(colorRed+"Error: %v\n"+colorReset, err)
+			}
+		}
+		if !found {
+			fmt.Printf(colorYellow+"⚠ Nothing to clean for '%s'\n"+colorReset, id)
+		}
+	}
+}
+
+// runBurn generates CPU, memory and disk load while the interactive TUI shows its effect
+// Without a TTY the load runs without the TUI, printing a line when it ends
+func runBurn(args []string) {
+	// 1. Parse the load options
+	options := burn.Options{Duration: 60 * time.Second}
+	fs := newFlagSet("burn")
+	fs.IntVar(&options.CPUWorkers, "cpu", runtime.NumCPU(), "busy workers")
+	fs.Var((*byteSize)(&options.MemoryBytes), "mem", "memory to allocate")
+	fs.Var((*byteSize)(&options.DiskBytes), "disk", "data to write")
+	fs.DurationVar(&options.Duration, "duration", options.Duration, "how long the load runs")
+	if positional, ok := parseCommandArgs(fs, args); !ok {
+		return
+	} else if len(positional) > 0 {
+		printArgError(fmt.Errorf("unrecognized argument '%s'", positional[0]))
+		return
+	}
+
+	var available uint64
+	if stats, err := ram.GetRamGeneral(); err == nil {
+		available = stats.Available
+	}
+	if err := options.Validate(available); err != nil {
+		fmt.Printf(colorRed+"Error: %v\n"+colorReset, err)
+		return
+	}
+
+	// 2. Start the load
+	fmt.Println(colorCyan + "Starting stress test: " + options.String() + colorReset)
+	load := burn.Start(options)
+	defer load.Stop()
+
+	// 3. Watch it in the TUI (or just wait for it without a terminal)
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		tui := ui.NewInteractiveTUI(loadConfig())
+		tui.SetStatus("Burn", func() string {
+			return fmt.Sprintf("%s left", load.Remaining())
+		})
+		tui.ExitOn(load.Done())
+		if err := tui.Run(); err != nil {
+			fmt.Printf(colorRed+"\nError running interactive interface: %v\n"+colorReset, err)
+			common.Logger().Error("error running the interactive interface", "err", err)
+		}
+	} else {
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(sigChan)
+		select {
+		case <-load.Done():
+		case <-sigChan:
+		}
+	}
+
+	load.Stop()
+	if err := load.Err(); err != nil {
+		fmt.Printf(colorYellow+"⚠ %v\n"+colorReset, err)
+	}
+	fmt.Println(colorGreen + "Stress test finished" + colorReset)
+}
+
+// runLaunch runs a command and prints its CPU, memory and disk usage when it exits, like time(1)
+// gom exits with the command's exit status, so it can wrap commands in scripts
+// e.g. "gom run -- make -j8"
+func runLaunch(args []string) {
+	// Options end at the command (or "--"), so the command's own flags are left alone
+	every := interval(launch.DefaultInterval)
+	fs := newFlagSet("run")
+	fs.Var(&every, "interval", "time between memory samples")
+	if err := fs.Parse(args); err != nil {
+		printArgError(fmt.Errorf("run: %w", err))
+		os.Exit(2)
+	}
+	if fs.NArg() == 0 {
+		fmt.Println(colorRed + "Error: Missing command (e.g. gom run -- make -j8)" + colorReset)
+		os.Exit(2)
+	}
+
+	summary, err := launch.Run(fs.Args(), time.Duration(every))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, colorRed+"Error: %v\n"+colorReset, err)
+		os.Exit(127)
+	}
+	launch.PrintSummary(os.Stderr, summary)
+	os.Exit(summary.ExitCode)
+}
+
+// runProcessMonitor monitors one process, or every process with a name or matching a pattern
+// e.g. "gom -p 1234", "gom -p --name nginx" or "gom -p --pattern 'php-fpm|worker'"
+func runProcessMonitor(args []string) {
+	fs := newFlagSet("process")
+	name := fs.String("name", "", "exact process name")
+	pattern := fs.String("pattern", "", "regular expression matched against the name and command line")
+	seconds := fs.Int("interval", 2, "seconds between rows")
+	positional, ok := parseCommandArgs(fs, args)
+	if !ok {
+		return
+	}
+	if *seconds <= 0 {
+		fmt.Printf(colorRed+"Error: --interval must be a positive number of seconds, got %d\n"+colorReset, *seconds)
+		return
+	}
+
+	// 1. A PID monitors that process alone
+	if len(positional) > 0 {
+		pid, err := strconv.ParseInt(positional[0], 10, 32)
+		if err != nil || pid <= 0 || len(positional) > 1 || *name != "" || *pattern != "" {
+			fmt.Println(colorRed + "Error: Give a PID, or --name/--pattern (e.g. gom -p 1234 or gom -p --name nginx)" + colorReset)
+			return
+		}
+		if err := pck.MonitorProcessContinuous(int32(pid), *seconds); err != nil {
+			fmt.Printf(colorRed+"Error: %v\n"+colorReset, err)
+		}
+		return
+	}
+
+	// 2. A name or pattern monitors all the matching processes together
+	matcher, err := common.NewProcessMatcher(*name, *pattern)
+	if err != nil {
+		fmt.Printf(colorRed+"Error: %v (e.g. gom -p 1234 or gom -p --name nginx)\n"+colorReset, err)
+		return
+	}
+	if err := pck.MonitorProcessGroup(matcher, *seconds); err != nil {
+		fmt.Printf(colorRed+"Error: %v\n"+colorReset, err)
+	}
+}
+
+// runWatchPID watches a process until it exits, then reports the exit with its last statistics
+// and runs the restart command or calls the webhook, if given
+// e.g. "gom watch-pid nginx --restart-cmd 'systemctl restart nginx'"
+func runWatchPID(args []string) {
+	// 1. Parse the process and what to do when it exits
+	options := watchdog.Options{Interval: 2 * time.Second}
+	fs := newFlagSet("watch-pid")
+	fs.Var((*interval)(&options.Interval), "interval", "time between rows")
+	fs.StringVar(&options.RestartCommand, "restart-cmd", "", "shell command run after each exit")
+	fs.StringVar(&options.Webhook, "webhook", "", "URL the exit is POSTed to as JSON")
+	fs.IntVar(&options.MaxRestarts, "max-restarts", 5, "restarts before giving up (0 = no limit)")
+	positional, ok := parseCommandArgs(fs, args)
+	if !ok {
+		return
+	}
+	if len(positional) != 1 {
+		fmt.Println(colorRed + "Error: Give one PID or process name (e.g. gom watch-pid nginx --restart-cmd 'systemctl restart nginx')" + colorReset)
+		return
+	}
+	if options.MaxRestarts < 0 {
+		fmt.Printf(colorRed+"Error: --max-restarts must be 0 or more, got %d\n"+colorReset, options.MaxRestarts)
+		return
+	}
+
+	target, matches, err := watchdog.Resolve(positional[0])
+	if err != nil {
+		fmt.Printf(colorRed+"Error: %v\n"+colorReset, err)
+		return
+	}
+	if matches > 1 {
+		fmt.Printf(colorYellow+"%d processes are named %s, watching the oldest (PID %d)\n"+colorReset, matches, target.Name, target.PID)
+	}
+
+	// Exits are stored with the other events when the history is enabled
+	var store *history.Store
+	if loadConfig().HistoryEnabled {
+		if path, err := history.DefaultPath(); err == nil {
+			store = history.NewStore(path)
+		}
+	}
+
+	// 2. Watch until the process exits, then report it and restart it
+	for restarts := 0; ; restarts++ {
+		title := fmt.Sprintf("Watching %s (PID %d) every %s (Ctrl+C to stop)", target.Name, target.PID, options.Interval)
+		last, err := common.WatchProcess(context.Background(), target.PID, options.Interval, title, nil)
+		if !errors.Is(err, common.ErrProcessExited) {
+			fmt.Printf(colorRed+"Error: %v\n"+colorReset, err)
+			return
+		}
+
+		exit := watchdog.Exit{Target: target, Time: time.Now(), Last: last, Restarts: restarts}
+		restarting := options.RestartCommand != "" && (options.MaxRestarts == 0 || restarts < options.MaxRestarts)
+		fmt.Printf(colorRed+"✗ %s %s\n"+colorReset, common.FormatTimestamp(exit.Time), exit)
+
+		if store != nil {
+			if err := store.Append(history.Event{Time: exit.Time, Kind: history.KindExit, Message: exit.String()}); err != nil {
+				fmt.Printf(colorYellow+"⚠ %v\n"+colorReset, err)
+			}
+		}
+		if options.Webhook != "" {
+			if err := watchdog.NotifyWebhook(options.Webhook, exit, restarting); err != nil {
+				fmt.Printf(colorYellow+"⚠ %v\n"+colorReset, err)
+			}
+		}
+
+		if !restarting {
+			if options.RestartCommand != "" {
+				fmt.Printf(colorRed+"Giving up: --max-restarts %d reached\n"+colorReset, options.MaxRestarts)
+			}
+			return
+		}
+		if err := watchdog.Restart(options.RestartCommand, exit); err != nil {
+			fmt.Printf(colorRed+"Error: %v\n"+colorReset, err)
+			return
+		}
+		target, err = watchdog.WaitForRestart(exit, options.Interval)
+		if err != nil {
+			fmt.Printf(colorRed+"Error: restart failed: %v\n"+colorReset, err)
+			return
+		}
+		fmt.Printf(colorGreen+"✓ %s restarted as PID %d (restart %d)\n"+colorReset, target.Name, target.PID, restarts+1)
+	}
+}
+
+// runThrottle limits the CPU and memory of a running process instead of killing it
+// e.g. "gom throttle 1234 --cpu 2 --memory 4G"
+func runThrottle(args []string) {
+	// 1. Parse the PID and the limits
+	var quota cgroup.Quota
+	fs := newFlagSet("throttle")
+	fs.Float64Var(&quota.CPUCores, "cpu", 0, "CPU time in cores")
+	fs.Var((*byteSize)(&quota.MemoryBytes), "memory", "memory limit")
+	positional, ok := parseCommandArgs(fs, args)
+	if !ok {
+		return
+	}
+	if len(positional) == 0 {
+		fmt.Println(colorRed + "Error: Missing PID (e.g. gom throttle 1234 --cpu 2 --memory 4G)" + colorReset)
+		return
+	}
+	pid, err := strconv.ParseInt(positional[0], 10, 32)
+	if err != nil || pid <= 0 || len(positional) > 1 {
+		fmt.Printf(colorRed+"Error: Invalid PID '%s'\n"+colorReset, strings.Join(positional, " "))
+		return
+	}
+	if err := quota.Validate(); err != nil {
+		fmt.Printf(colorRed+"Error: %v\n"+colorReset, err)
+		return
+	}
+
+	// 2. A memory limit below the current usage makes the kernel reclaim (and maybe OOM-kill)
+	name := "unknown"
+	if p, err := common.GetProcessByPID(int32(pid)); err == nil {
+		if processName, err := p.Name(); err == nil {
+			name = processName
+		}
+		if memory, err := p.MemoryInfo(); err == nil && quota.MemoryBytes > 0 && memory.RSS > quota.MemoryBytes {
+			fmt.Printf(colorYellow+"⚠ %s is using %s: it will be pushed to swap or OOM-killed if it can't shrink\n"+colorReset,
+				name, common.FormatBytes(memory.RSS))
+		}
+	}
+
+	// 3. Move the process and show the limits now in effect
+	result, err := cgroup.Throttle(int32(pid), quota)
+	if err != nil {
+		fmt.Printf(colorRed+"Error: %v\n"+colorReset, err)
+		return
+	}
+	fmt.Printf(colorGreen+"✓ PID %d (%s) limited to %s (%s %s)\n"+colorReset, pid, name, quota, result.Method, result.Name)
+
+	if limits, err := cgroup.Get(int32(pid)); err == nil {
+		cgroup.PrintLimits(limits)
+	}
+}
+
+// runKill terminates every process that meets the given criteria, with SIGTERM and then SIGKILL
+// like the kill key of the TUI, e.g. "gom kill --name chrome --ram-above 2G --dry-run"
+// At least one criterion is required, so a typo can't kill every process
+func runKill(args []string) {
+	// 1. Parse the criteria
+	var criteria common.KillCriteria
+	var ramAbove ramThreshold
+	fs := newFlagSet("kill")
+	name := fs.String("name", "", "exact process name")
+	pattern := fs.String("regex", "", "regular expression matched against the name or command line")
+	fs.Var((*interval)(&criteria.OlderThan), "older-than", "minimum running time")
+	fs.Float64Var(&criteria.CPUAbove, "cpu-above", 0, "minimum CPU usage (%)")
+	fs.Var(&ramAbove, "ram-above", "minimum RAM usage (% or size)")
+	dryRun := fs.Bool("dry-run", false, "only list the processes that would be killed")
+	if positional, ok := parseCommandArgs(fs, args); !ok {
+		return
+	} else if len(positional) > 0 {
+		printArgError(fmt.Errorf("unrecognized argument '%s'", positional[0]))
+		return
+	}
+	if *name != "" || *pattern != "" {
+		matcher, err := common.NewProcessMatcher(*name, *pattern)
+		if err != nil {
+			fmt.Printf(colorRed+"Error: %v\n"+colorReset, err)
+			return
+		}
+		criteria.Matcher = matcher
+	}
+	criteria.RAMAbove, criteria.RAMBytesAbove = ramAbove.percent, ramAbove.bytes
+	if criteria.Empty() {
+		fmt.Println(colorRed + "Error: Give at least one of --name, --regex, --older-than, --cpu-above or --ram-above (e.g. gom kill --name chrome --dry-run)" + colorReset)
+		return
+	}
+
+	// 2. Select the processes (the CPU usage is measured over a short sample)
+	processes, err := common.CollectAllProcessInfo()
+	if err != nil {
+		fmt.Printf(colorRed+"Error: %v\n"+colorReset, err)
+		return
+	}
+	targets := common.SelectKillTargets(processes, criteria)
+	if len(targets) == 0 {
+		fmt.Printf(colorYellow+"No process %s\n"+colorReset, criteria)
+		return
+	}
+
+	title := fmt.Sprintf("%d processes %s", len(targets), criteria)
+	if *dryRun {
+		common.PrintProcessTable(targets, 0, "Would kill "+title)
+		return
+	}
+	common.PrintProcessTable(targets, 0, "Killing "+title)
+
+	// 3. Kill them, reporting each failure
+	killed := 0
+	for _, p := range targets {
+		if err := common.KillProcess(p.PID); err != nil {
+			fmt.Printf(colorRed+"✗ PID %d (%s): %v\n"+colorReset, p.PID, p.Name, err)
+			continue
+		}
+		killed++
+	}
+	color := colorGreen
+	if killed < len(targets) {
+		color = colorYellow
+	}
+	fmt.Printf(color+"✓ %d of %d processes killed\n"+colorReset, killed, len(targets))
+}
+
+// runPrometheus writes the configured alert rules as a Prometheus rule file ("rules")
+// or a Grafana dashboard ("dashboard") to stdout, for moving alerting to a central stack
+// Messages go to stderr so the output can be redirected to a file
+func runPrometheus(args []string) {
+	positional, ok := parseCommandArgs(newFlagSet("prometheus"), args)
+	if !ok {
+		return
+	}
+	kind := ""
+	if len(positional) == 1 {
+		kind = positional[0]
+	}
+	if kind != "rules" && kind != "dashboard" {
+		fmt.Fprintln(os.Stderr, colorRed+"Error: Use 'gom prometheus rules' or 'gom prometheus dashboard'"+colorReset)
+		return
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, colorYellow+"⚠ %v\n"+colorReset, err)
+	}
+	rules, err := alerts.ConfiguredRules(cfg.Alerts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, colorYellow+"⚠ %v\n"+colorReset, err)
+	}
+
+	write := prometheus.WriteRules
+	if kind == "dashboard" {
+		write = prometheus.WriteDashboard
+	}
+	if err := write(os.Stdout, rules); err != nil {
+		fmt.Fprintf(os.Stderr, colorRed+"Error: %v\n"+colorReset, err)
+	}
+}
+
+// runDaemon runs the background monitor in the foreground, or controls the running one
+// with "status", "stop" or "reload"
+func runDaemon(args []string) {
+	fs := newFlagSet("daemon")
+	positional, ok := parseCommandArgs(fs, args)
+	if !ok {
+		return
+	}
+	if len(positional) > 1 {
+		printArgError(fmt.Errorf("unrecognized argument '%s'", positional[1]))
+		return
+	}
+
+	// Without a control command, this process becomes the daemon
+	if len(positional) == 0 {
+		d, err := daemon.Start(loadConfig)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, colorRed+"Error: %v\n"+colorReset, err)
+			os.Exit(1)
+		}
+		fmt.Printf(colorCyan+"gom daemon running (PID %d), stop with Ctrl+C or 'gom daemon stop'\n"+colorReset, os.Getpid())
+		d.Run()
+		return
+	}
+
+	command := positional[0]
+	switch command {
+	case daemon.CommandStatus, daemon.CommandStop, daemon.CommandReload:
+	default:
+		printArgError(fmt.Errorf("unknown daemon command '%s' (status, stop or reload)", command))
+		return
+	}
+	reply, err := daemon.Control(command)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, colorRed+"Error: %v\n"+colorReset, err)
+		os.Exit(1)
+	}
+	fmt.Println(reply)
+}
+
+// runSnapshot writes a JSON snapshot of the key metrics and top processes to stdout
+// Used by diff --remote on other hosts, or saved to compare later with gom diff
+func runSnapshot(args []string) {
+	fs := newFlagSet("snapshot")
+	top := fs.Int("top", defaultCount, "number of top processes")
+	if positional, ok := parseCommandArgs(fs, args); !ok {
+		return
+	} else if len(positional) > 0 {
+		printArgError(fmt.Errorf("unrecognized argument '%s'", positional[0]))
+		return
+	}
+
+	snap, err := snapshot.Take(*top, diskFilter())
+	if err == nil {
+		err = snap.Write(os.Stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, colorRed+"Error: %v\n"+colorReset, err)
+		os.Exit(1)
+	}
+}
+
+// runDiff compares two machines side by side: saved snapshot files, or with --remote
+// SSH hosts whose snapshots are collected at the same time
+func runDiff(args []string) {
+	fs := newFlagSet("diff")
+	remote := fs.Bool("remote", false, "arguments are SSH hosts instead of snapshot files")
+	command := fs.String("command", "gom", "gom program on the remote hosts (a path, run without a shell)")
+	top := fs.Int("top", defaultCount, "number of top processes")
+	positional, ok := parseCommandArgs(fs, args)
+	if !ok {
+		return
+	}
+	if len(positional) != 2 {
+		printArgError(errors.New("diff needs two snapshot files, or two hosts with --remote"))
+		return
+	}
+
+	// Both snapshots are collected at once, so remote hosts are compared at the same moment
+	var snaps [2]snapshot.Snapshot
+	var errs [2]error
+	var wg sync.WaitGroup
+	for i, source := range positional {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if *remote {
+				snaps[i], errs[i] = snapshot.Fetch(source, *command, *top, diskFilter())
+			} else {
+				snaps[i], errs[i] = snapshot.Read(source)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			fmt.Printf(colorRed+"Error: %v\n"+colorReset, err)
+			return
+		}
+	}
+	snapshot.PrintDiff(snaps[0], snaps[1])
+}
+
+// showPaths shows the directories and files GoMonitor uses, after the overrides
+// (--config-dir, --state-dir, GOM_CONFIG_DIR, GOM_STATE_DIR, GOM_CACHE_DIR and the XDG variables)
+func showPaths(args []string) {
+	if len(args) > 0 {
+		printArgError(fmt.Errorf("unrecognized argument '%s'", args[0]))
+		return
+	}
+
+	entries := []struct {
+		label string
+		path  func() (string, error)
+	}{
+		{"Config file", config.Path},
+		{"State directory", paths.StateDir},
+		{"Events", history.DefaultPath},
+		{"Metrics", history.DefaultMetricsPath},
+		{"Debug log", common.DefaultLogPath},
+		{"Hardware cache", hwcache.Path},
+	}
+
+	for _, entry := range entries {
+		path, err := entry.path()
+		if err != nil {
+			path = colorRed + err.Error() + colorReset
+		}
+		fmt.Printf("%-16s %s\n", entry.label+":", path)
+	}
+}
+
+// showEvents lists the alerts and notable events stored in the history
+// The look-back window is given with --since (default: 24h)
+func showEvents(args []string) {
+	fs := newFlagSet("events")
+	since := fs.String("since", "24h", "look-back window")
+	if positional, ok := parseCommandArgs(fs, args); !ok {
+		return
+	} else if len(positional) > 0 {
+		printArgError(fmt.Errorf("unrecognized argument '%s'", positional[0]))
+		return
+	}
+	cfg := loadConfig()
+
+	window, err := history.ParseSince(*since)
+	if err != nil {
+		fmt.Printf(colorRed+"Error: %v\n"+colorReset, err)
+		return
+	}
+
+	path, err := history.DefaultPath()
+	if err != nil {
+		fmt.Printf(colorRed+"Error: %v\n"+colorReset, err)
+		return
+	}
+	store := history.NewStore(path)
+
+	if cfg.HistoryEnabled {
+		// A reboot can only be noticed afterwards, so check for one now
+		if err := history.NewRecorder(store).RecordBoot(); err != nil {
+			fmt.Printf(colorYellow+"⚠ Could not record boot event: %v\n"+colorReset, err)
+		}
+	} else {
+		fmt.Println(colorYellow + "⚠ The history store is disabled; set \"history_enabled\": true in the config file to record events" + colorReset)
+	}
+
+	events, err := store.Since(time.Now().Add(-window))
+	if err != nil {
+		fmt.Printf(colorRed+"Error reading events: %v\n"+colorReset, err)
+		return
+	}
+	history.PrintEvents(events, *since)
+}
+
+// runChart draws the stored system usage as a line chart in the terminal
+// e.g. "gom chart cpu ram --last 6h"; the look-back window is given with --last (default: 1h)
+func runChart(args []string) {
+	// 1. Series to draw (names may also be comma separated) and options
+	fs := newFlagSet("chart")
+	last := fs.String("last", "1h", "look-back window")
+	blocks := fs.Bool("blocks", false, "draw with block characters instead of braille")
+	positional, ok := parseCommandArgs(fs, args)
+	if !ok {
+		return
+	}
+	cfg := loadConfig()
+
+	var names []string
+	for _, arg := range positional {
+		for _, name := range strings.Split(arg, ",") {
+			if name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		names = []string{history.SeriesCPU}
+	}
+
+	colors := map[string]string{
+		history.SeriesCPU:  colorGreen,
+		history.SeriesRAM:  colorPurple,
+		history.SeriesLoad: colorYellow,
+		history.SeriesTemp: colorRed,
+	}
+	var series []history.ChartSeries
+	for _, name := range names {
+		color, ok := colors[name]
+		if !ok {
+			fmt.Printf(colorRed+"Error: Unknown series '%s' (available: %s)\n"+colorReset, name, strings.Join(history.SeriesNames, ", "))
+			return
+		}
+		series = append(series, history.ChartSeries{Name: name, Color: color})
+	}
+
+	window, err := history.ParseSince(*last)
+	if err != nil {
+		fmt.Printf(colorRed+"Error: %v\n"+colorReset, err)
+		return
+	}
+
+	// 2. Read the samples of the window
+	path, err := history.DefaultMetricsPath()
+	if err != nil {
+		fmt.Printf(colorRed+"Error: %v\n"+colorReset, err)
+		return
+	}
+	to := time.Now()
+	from := to.Add(-window)
+	samples, err := history.NewMetricStore(path).Since(from)
+	if err != nil {
+		fmt.Printf(colorRed+"Error reading metrics: %v\n"+colorReset, err)
+		return
+	}
+
+	// 3. Draw the chart across the terminal width
+	width := common.TerminalWidth()
+	if width == 0 {
+		width = 80
+	}
+	options := history.ChartOptions{Width: width, Height: 12, Style: history.ChartBraille, Reset: colorReset}
+	if *blocks {
+		options.Style = history.ChartBlocks
+	}
+
+	fmt.Printf(colorPurple+"\n→ %s (last %s):\n\n"+colorReset, strings.Join(names, ", "), *last)
+	lines, ok := history.RenderChart(samples, series, from, to, options)
+	if !ok {
+		fmt.Printf("No samples recorded in the last %s\n", *last)
+		if !cfg.HistoryEnabled {
+			fmt.Println(colorYellow + "⚠ The history store is disabled; set \"history_enabled\": true in the config file and samples are stored while gom -f runs" + colorReset)
+		}
+		return
+	}
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+}
+
+// printCollectionError prints an error from a collection call
+// Calls that are not implemented on this OS are reported as unsupported instead of failing
+//
+// Parameters:
+//   - what: what was being collected (e.g. "CPU information")
+//   - err: error returned by the collection call
+func printCollectionError(what string, err error) {
+	if capability.IsNotImplemented(err) {
+		fmt.Printf(colorYellow+"⚠ %s: %s\n"+colorReset, what, capability.Unsupported())
+		return
+	}
+	fmt.Printf(colorRed+"Error getting %s: %v\n"+colorReset, what, err)
+}
+
+// showTopProcesses shows the N most active processes in the system
+// Sorted by CPU usage (or another field), optionally only the processes of one user
+func showTopProcesses(n int, username, field string, options common.TableOptions) {
+	if err := pck.PrintTopProcesses(n, username, field, options); err != nil {
+		printCollectionError("processes", err)
+	}
+}
+
+// showTopApplications shows the N applications using the most CPU, each with the
+// total of all its processes
+func showTopApplications(n int, username string) {
+	if err := pck.PrintTopApplications(n, username); err != nil {
+		printCollectionError("processes", err)
+	}
+}
+
+// showTopIO shows the N processes reading and writing the most to storage
+// The counters are read twice, one second apart, to get the throughput
+func showTopIO(n int) {
+	interval := time.Second
+	processes, err := disk.GetTopProcessesByIO(n, interval)
+	if err != nil {
+		printCollectionError("process disk I/O", err)
+		return
+	}
+	disk.PrintTopProcessesByIO(processes, interval)
+	if os.Geteuid() != 0 {
+		fmt.Println(colorYellow + "⚠ Only your own processes are measured (run as root to see all)" + colorReset)
+	}
+}
+
+// showTopFDs shows the N processes with the most open file descriptors and their limits,
+// to find descriptor leaks before a process fails with "too many open files"
+func showTopFDs(n int) {
+	processes, unreadable, err := pck.GetTopProcessesByFDs(n)
+	if err != nil {
+		printCollectionError("open file descriptors", err)
+		return
+	}
+	pck.PrintTopProcessesByFDs(processes)
+	if unreadable > 0 && os.Geteuid() != 0 {
+		fmt.Printf(colorYellow+"⚠ The descriptors of %d processes of other users can't be read (run as root to see all)\n"+colorReset, unreadable)
+	}
+}
+
+// Auxiliary function to get process association statistics
+// (maintained for compatibility with existing code)
+func getProcessAssociationStats() {
+	fmt.Println(colorBold + colorGreen + "\n=== Process Association Statistics ===" + colorReset)
+
+	// Get all processes
+	processes, err := pck.GetProcessAssociation()
+	if err != nil {
+		printCollectionError("processes", err)
+		return
+	}
+
+	fmt.Printf("\n"+colorCyan+"Total monitored processes: "+colorReset+"%d\n", len(processes))
+
+	// Calculate aggregate statistics
+	var totalCPU float64
+	var totalRAM float32
+
+	for _, p := range processes {
+		totalCPU += p.CPUPercentage
+		totalRAM += p.RAMPercentage
+	}
+
+	fmt.Printf(colorYellow+"Total CPU usage (sum of all processes): "+colorReset+"%.2f%%\n", totalCPU)
+	fmt.Printf(colorYellow+"Total RAM usage (sum of all processes): "+colorReset+"%.2f%%\n", totalRAM)
+
+	// Show example of specific process
+	if len(processes) > 0 {
+		fmt.Println(colorPurple + "\n→ Example - First Process:" + colorReset)
+		p := processes[0]
+		fmt.Printf("  PID:  %d\n", p.PID)
+		fmt.Printf("  Name: %s\n", p.Name)
+		fmt.Printf("  CPU:  %.2f%%\n", p.CPUPercentage)
+		fmt.Printf("  RAM:  %.2f%% (%s)\n", p.RAMPercentage, common.FormatBytes(p.RAMBytes))
+	}
+}
+
+// showDoctor shows which metrics can be collected on this OS and which external tools are installed
+func showDoctor() {
+	capability.PrintMatrix(capability.Detect())
+	capability.PrintTools(capability.DetectTools())
+}
+
+// showHealth prints the health score and the score of each component on one line
+// e.g. "Health: 87/100 good (cpu 95, memory 90, disk 60, temperature 100, alerts 100)"
+func showHealth(scorer *health.Scorer, filter disk.Filter) {
+	score := scorer.Score(scorer.Collect(filter))
+	color := colorGreen
+	switch score.Level() {
+	case health.LevelFair:
+		color = colorYellow
+	case health.LevelPoor:
+		color = colorRed
+	}
+	fmt.Println(color + "Health: " + score.String() + colorReset)
+}
+
+// showDefaultInterface shows the default style interface
+// Logo of the OS (or the one chosen with --ascii/--ascii-file) on the left and system information on the right
+func showDefaultInterface(filter disk.Filter, logo ui.LogoOptions, scorer *health.Scorer) {
+	if err := ui.PrintDefaultStyle(filter, logo, scorer); err != nil {
+		fmt.Printf(colorRed+"Error showing default interface: %v\n"+colorReset, err)
+	}
+}
+
+// exportDefaultInterface writes the default interface to a file (plain text, ANSI or PNG
+// by extension) or as plain text to stdout
+func exportDefaultInterface(filter disk.Filter, logo ui.LogoOptions, scorer *health.Scorer, path string) {
+	if err := ui.ExportDefaultStyle(filter, logo, scorer, path); err != nil {
+		fmt.Printf(colorRed+"Error exporting default interface: %v\n"+colorReset, err)
+		return
+	}
+	if path != "" {
+		fmt.Printf(colorGreen+"✓ Default view written to %s\n"+colorReset, path)
+	}
+}
+
+// showInteractiveTUI starts the interactive TUI interface
+// Allows navigating through processes, killing processes, sorting, etc.
+func showInteractiveTUI(cfg config.Config) {
+	// Check if we're in an interactive terminal
+	fileInfo, err := os.Stdin.Stat()
+	if err != nil {
+		fmt.Printf(colorRed+"Error: Could not access stdin: %v\n"+colorReset, err)
+		fmt.Println(colorYellow + "\nInteractive mode requires a TTY terminal." + colorReset)
+		fmt.Println("Use: gomonitor --all  to see information without interactivity")
+		return
+	}
+
+	if (fileInfo.Mode() & os.ModeCharDevice) == 0 {
+		fmt.Print(colorRed + "Error: Interactive mode requires a TTY terminal.\n" + colorReset)
+		fmt.Println(colorYellow + "It seems that input is being redirected or executed in a pipe." + colorReset)
+		fmt.Println("\nUse: gomonitor --all  to see information without interactivity")
+		return
+	}
+
+	tui := ui.NewInteractiveTUI(cfg)
+	if err := tui.Run(); err != nil {
+		fmt.Printf(colorRed+"\nError running interactive interface: %v\n"+colorReset, err)
+		common.Logger().Error("error running the interactive interface", "err", err)
+		fmt.Println(colorYellow + "\nTip: Make sure you're running in a real interactive terminal." + colorReset)
+	}
+}
+
+// fixTerminal makes a terminal usable again after the TUI was killed without restoring it
+// (typed input not shown, Enter not working, cursor hidden)
+func fixTerminal() {
+	if err := ui.FixTerminal(); err != nil {
+		fmt.Printf(colorRed+"Error: %v\n"+colorReset, err)
+		return
 	}
+	fmt.Println(colorGreen + "Terminal restored" + colorReset)
 }
 
 // toggleAutoStart enables or disables auto-start on terminal startup
@@ -0,0 +1,331 @@
+package alerts
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/config"
+	"github.com/dfialho05/GoMonitor/application/pck/disk"
+	"github.com/dfialho05/GoMonitor/application/pck/gpu"
+	"github.com/dfialho05/GoMonitor/application/pck/ram"
+)
+
+// Metric identifies the value a rule is evaluated against
+type Metric string
+
+const (
+	MetricCPU     Metric = "cpu"      // Global CPU usage (%)
+	MetricRAM     Metric = "ram"      // RAM usage (%)
+	MetricDisk    Metric = "disk"     // Disk usage of a mountpoint (%)
+	MetricInodes  Metric = "inodes"   // Inode usage of a mountpoint (%)
+	MetricGPUTemp Metric = "gpu_temp" // GPU temperature (°C)
+	MetricGPUVRAM Metric = "gpu_vram" // GPU memory usage (%)
+	MetricGPUUtil Metric = "gpu_util" // GPU utilization (%)
+	MetricRespawn Metric = "respawns" // Respawns of a process name within common.RespawnWindow
+	MetricRename  Metric = "renamed"  // Name changes of a process within common.RenameWindow
+)
+
+// Unit returns the display unit of the metric
+func (m Metric) Unit() string {
+	switch m {
+	case MetricGPUTemp:
+		return "°C"
+	case MetricRespawn:
+		return " respawns"
+	case MetricRename:
+		return " rename"
+	default:
+		return "%"
+	}
+}
+
+// Rule describes when an alert should fire
+// An alert fires when the metric stays at or above the threshold for at least Duration
+type Rule struct {
+	Name       string             // Human readable rule name (e.g. "GPU overheating")
+	Metric     Metric             // Metric to evaluate
+	Threshold  float64            // Value at or above which the rule is violated
+	Duration   time.Duration      // How long the violation must be sustained (0 = fire immediately)
+	Thresholds map[string]float64 // Per-target thresholds overriding Threshold (e.g. {"/": 90, "/data": 97})
+}
+
+// ThresholdFor returns the threshold that applies to a target
+// Uses the per-target threshold if one is configured, the global Threshold otherwise
+//
+// Parameters:
+//   - target: what was measured (e.g. "/data", "GPU 0")
+//
+// Returns: threshold value for the target
+func (r Rule) ThresholdFor(target string) float64 {
+	if threshold, ok := r.Thresholds[target]; ok {
+		return threshold
+	}
+	return r.Threshold
+}
+
+// Sample is a single metric reading
+type Sample struct {
+	Metric Metric  // Metric this value belongs to
+	Target string  // What was measured (e.g. "GPU 0", "/home"; empty for global metrics)
+	Value  float64 // Measured value
+}
+
+// Alert is an active (firing) alert
+type Alert struct {
+	Rule   Rule      // Rule that fired
+	Target string    // What triggered the rule (e.g. "GPU 0")
+	Value  float64   // Latest measured value
+	Since  time.Time // When the violation started
+}
+
+// Engine evaluates samples against rules, tracking how long each violation has lasted
+type Engine struct {
+	rules   []Rule               // Rules to evaluate
+	pending map[string]time.Time // Start time of each ongoing violation, keyed by rule and target
+}
+
+// DefaultRules returns the built-in alert rules
+// GPU rules are aimed at ML boxes and gaming rigs left unattended
+func DefaultRules() []Rule {
+	return []Rule{
+		{Name: "High CPU usage", Metric: MetricCPU, Threshold: 95, Duration: 2 * time.Minute},
+		{Name: "High RAM usage", Metric: MetricRAM, Threshold: 90, Duration: time.Minute},
+		{Name: "Disk almost full", Metric: MetricDisk, Threshold: 90},
+		{Name: "Inodes almost exhausted", Metric: MetricInodes, Threshold: 90},
+		{Name: "GPU overheating", Metric: MetricGPUTemp, Threshold: 85, Duration: 30 * time.Second},
+		{Name: "GPU memory almost full", Metric: MetricGPUVRAM, Threshold: 95, Duration: time.Minute},
+		{Name: "GPU saturated", Metric: MetricGPUUtil, Threshold: 98, Duration: 5 * time.Minute},
+		{Name: "Process crash loop", Metric: MetricRespawn, Threshold: common.RespawnThreshold},
+		{Name: "Process renamed", Metric: MetricRename, Threshold: 1},
+	}
+}
+
+// ConfiguredRules returns the built-in alert rules with the changes from the config file
+// Overrides for unknown metrics are reported but don't stop the other rules from applying
+//
+// Parameters:
+//   - overrides: "alerts" section of the config file, keyed by metric name
+//
+// Returns:
+//   - slice of rules (disabled rules are left out)
+//   - error listing the metric names that don't match a rule
+func ConfiguredRules(overrides config.AlertRules) ([]Rule, error) {
+	defaults := DefaultRules()
+	known := make(map[string]bool, len(defaults))
+
+	var rules []Rule
+	for _, rule := range defaults {
+		known[string(rule.Metric)] = true
+		override, ok := overrides[string(rule.Metric)]
+		if !ok {
+			rules = append(rules, rule)
+			continue
+		}
+		if override.Disabled {
+			continue
+		}
+		if override.Threshold != nil {
+			rule.Threshold = *override.Threshold
+		}
+		if override.Duration != nil {
+			rule.Duration = override.Duration.Duration
+		}
+		if override.Thresholds != nil {
+			rule.Thresholds = override.Thresholds
+		}
+		rules = append(rules, rule)
+	}
+
+	var unknown []string
+	for metric := range overrides {
+		if !known[metric] {
+			unknown = append(unknown, metric)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return rules, fmt.Errorf("unknown alert metrics in config: %s", strings.Join(unknown, ", "))
+	}
+	return rules, nil
+}
+
+// NewEngine creates an alert engine for the given rules
+//
+// Parameters:
+//   - rules: rules to evaluate (e.g. DefaultRules())
+//
+// Returns: pointer to a configured Engine
+func NewEngine(rules []Rule) *Engine {
+	return &Engine{
+		rules:   rules,
+		pending: make(map[string]time.Time),
+	}
+}
+
+// Evaluate checks samples against all rules and returns the alerts that are firing
+// Violations that stop being reported (or drop below the threshold) are reset
+//
+// Parameters:
+//   - samples: latest metric readings
+//   - now: time of the readings
+//
+// Returns:
+//   - slice of firing alerts, in rule order
+func (e *Engine) Evaluate(samples []Sample, now time.Time) []Alert {
+	var firing []Alert
+	seen := make(map[string]struct{})
+
+	for _, rule := range e.rules {
+		for _, sample := range samples {
+			if sample.Metric != rule.Metric || sample.Value < rule.ThresholdFor(sample.Target) {
+				continue
+			}
+
+			// Remember when the violation started
+			key := rule.Name + "|" + sample.Target
+			seen[key] = struct{}{}
+			since, ok := e.pending[key]
+			if !ok {
+				since = now
+				e.pending[key] = now
+			}
+
+			// Only fire once the violation has been sustained long enough
+			if now.Sub(since) >= rule.Duration {
+				firing = append(firing, Alert{
+					Rule:   rule,
+					Target: sample.Target,
+					Value:  sample.Value,
+					Since:  since,
+				})
+			}
+		}
+	}
+
+	// Forget violations that are no longer happening
+	for key := range e.pending {
+		if _, ok := seen[key]; !ok {
+			delete(e.pending, key)
+		}
+	}
+
+	return firing
+}
+
+// Check returns the rules a single reading violates, ignoring how long they must last
+// Used where there is no history to track (e.g. one-off views)
+//
+// Parameters:
+//   - rules: rules to check
+//   - samples: metric readings
+//
+// Returns: slice of violations, in rule order
+func Check(rules []Rule, samples []Sample) []Alert {
+	instant := make([]Rule, len(rules))
+	for i, rule := range rules {
+		rule.Duration = 0
+		instant[i] = rule
+	}
+	now := time.Now()
+	return NewEngine(instant).Evaluate(samples, now)
+}
+
+// CPUSample converts the global CPU usage into an alert sample
+//
+// Parameters:
+//   - percent: global CPU usage (0-100%, e.g. from cpu.GetSystemPercent)
+//
+// Returns: CPU usage sample (global, no target)
+func CPUSample(percent float64) Sample {
+	return Sample{Metric: MetricCPU, Value: percent}
+}
+
+// RAMSample converts the system RAM usage into an alert sample
+//
+// Parameters:
+//   - stats: system RAM usage (from ram.GetRamGeneral)
+//
+// Returns: RAM usage sample (global, no target)
+func RAMSample(stats ram.RamGeneral) Sample {
+	return Sample{Metric: MetricRAM, Value: stats.Percent}
+}
+
+// GPUSamples converts GPU statistics into alert samples
+// Only values that were actually measured are reported
+//
+// Parameters:
+//   - allStats: statistics of every GPU (from gpu.GetAllGPUStats)
+//
+// Returns: slice of samples (temperature, VRAM usage and utilization per GPU)
+func GPUSamples(allStats []gpu.GPUStats) []Sample {
+	var samples []Sample
+	for i, stats := range allStats {
+		target := fmt.Sprintf("GPU %d", i)
+
+		if stats.Temp > 0 {
+			samples = append(samples, Sample{Metric: MetricGPUTemp, Target: target, Value: float64(stats.Temp)})
+		}
+		if stats.MemoryTotal > 0 {
+			vram := float64(stats.MemoryUsed) / float64(stats.MemoryTotal) * 100
+			samples = append(samples, Sample{Metric: MetricGPUVRAM, Target: target, Value: vram})
+		}
+		if stats.HasUtilization {
+			samples = append(samples, Sample{Metric: MetricGPUUtil, Target: target, Value: stats.Utilization})
+		}
+	}
+	return samples
+}
+
+// DiskSamples converts storage devices into alert samples
+// Each mountpoint is a separate target, so per-mountpoint thresholds can apply
+// File systems without an inode table (e.g. btrfs) only have a disk usage sample
+//
+// Parameters:
+//   - devices: storage devices (from disk.GetAllStorageDevices)
+//
+// Returns: slice of disk and inode usage samples, one of each per mountpoint
+func DiskSamples(devices []disk.StorageDevice) []Sample {
+	samples := make([]Sample, 0, 2*len(devices))
+	for _, device := range devices {
+		samples = append(samples, Sample{Metric: MetricDisk, Target: device.Mountpoint, Value: device.Percent})
+		if device.InodesTotal > 0 {
+			samples = append(samples, Sample{Metric: MetricInodes, Target: device.Mountpoint, Value: device.InodesPercent})
+		}
+	}
+	return samples
+}
+
+// ProcessSamples converts the lifecycle events of a process tracker into alert samples
+// Each respawning name and each renamed process is a separate target
+//
+// Parameters:
+//   - tracker: process tracker updated by the process list refresh
+//
+// Returns: slice of respawn and rename samples
+func ProcessSamples(tracker *common.ProcessTracker) []Sample {
+	var samples []Sample
+	for name, count := range tracker.Respawns() {
+		samples = append(samples, Sample{Metric: MetricRespawn, Target: name, Value: float64(count)})
+	}
+	for _, rename := range tracker.Renames() {
+		samples = append(samples, Sample{Metric: MetricRename, Target: rename.String(), Value: 1})
+	}
+	return samples
+}
+
+// String formats an alert as a single line (e.g. "GPU overheating: GPU 0 at 88°C (≥ 85°C for 45s)")
+func (a Alert) String() string {
+	unit := a.Rule.Metric.Unit()
+
+	target := ""
+	if a.Target != "" {
+		target = a.Target + " "
+	}
+
+	duration := time.Since(a.Since).Round(time.Second)
+	return fmt.Sprintf("%s: %sat %.0f%s (≥ %.0f%s for %s)",
+		a.Rule.Name, target, a.Value, unit, a.Rule.ThresholdFor(a.Target), unit, duration)
+}
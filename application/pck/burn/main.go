@@ -0,0 +1,245 @@
+package burn
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+)
+
+// Options describes the load to generate
+type Options struct {
+	CPUWorkers  int           // Number of busy-looping workers (one core each)
+	MemoryBytes uint64        // Memory to allocate and keep resident
+	DiskBytes   uint64        // Size of the file that is rewritten over and over (0 = no disk load)
+	Duration    time.Duration // How long the load runs
+}
+
+// pageSize is the step used to touch the allocated memory, so every page is really used
+const pageSize = 4096
+
+// memoryTouchInterval is how often the allocated memory is touched again, so it stays resident
+const memoryTouchInterval = time.Second
+
+// String describes the load (e.g. "4 CPU workers, 2.00 GB RAM, 60s")
+func (o Options) String() string {
+	var parts []string
+	if o.CPUWorkers > 0 {
+		parts = append(parts, fmt.Sprintf("%d CPU workers", o.CPUWorkers))
+	}
+	if o.MemoryBytes > 0 {
+		parts = append(parts, common.FormatBytes(o.MemoryBytes)+" RAM")
+	}
+	if o.DiskBytes > 0 {
+		parts = append(parts, common.FormatBytes(o.DiskBytes)+" disk writes")
+	}
+	return strings.Join(append(parts, o.Duration.String()), ", ")
+}
+
+// Validate checks that the load can be generated safely
+//
+// Parameters:
+//   - availableRAM: memory available for new processes in bytes (0 skips the check)
+//
+// Returns: error describing the first invalid option
+func (o Options) Validate(availableRAM uint64) error {
+	if o.CPUWorkers < 0 {
+		return fmt.Errorf("--cpu must be 0 or more, got %d", o.CPUWorkers)
+	}
+	if o.CPUWorkers == 0 && o.MemoryBytes == 0 && o.DiskBytes == 0 {
+		return fmt.Errorf("nothing to do: use --cpu, --mem or --disk")
+	}
+	if o.Duration <= 0 {
+		return fmt.Errorf("--duration must be positive, got %s", o.Duration)
+	}
+	// Leave some memory for the rest of the system, the goal is load, not an OOM kill
+	if availableRAM > 0 && o.MemoryBytes > availableRAM/10*9 {
+		return fmt.Errorf("--mem %s exceeds 90%% of the available memory (%s)",
+			common.FormatBytes(o.MemoryBytes), common.FormatBytes(availableRAM))
+	}
+	return nil
+}
+
+// Load is a running stress test
+type Load struct {
+	options Options
+	started time.Time
+	stop    chan struct{}  // Closed to stop the workers
+	done    chan struct{}  // Closed when the duration has passed or Stop was called
+	once    sync.Once      // Stops only once
+	workers sync.WaitGroup // Running workers
+	errMu   sync.Mutex
+	err     error // First error of a worker (e.g. disk full)
+}
+
+// Start starts generating the load in the background
+// The load stops by itself after the duration; Stop ends it early
+//
+// Parameters:
+//   - options: load to generate (checked with Validate)
+//
+// Returns: the running Load
+func Start(options Options) *Load {
+	load := &Load{
+		options: options,
+		started: time.Now(),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	// 1. CPU: one busy loop per worker
+	for i := 0; i < options.CPUWorkers; i++ {
+		load.run(load.burnCPU)
+	}
+
+	// 2. Memory: allocate, touch every page and keep touching it
+	if options.MemoryBytes > 0 {
+		load.run(load.burnMemory)
+	}
+
+	// 3. Disk: rewrite a file and sync it to the device
+	if options.DiskBytes > 0 {
+		load.run(load.burnDisk)
+	}
+
+	go func() {
+		select {
+		case <-time.After(options.Duration):
+			load.Stop()
+		case <-load.stop:
+		}
+	}()
+
+	return load
+}
+
+// run starts a worker
+func (l *Load) run(worker func()) {
+	l.workers.Add(1)
+	go func() {
+		defer l.workers.Done()
+		worker()
+	}()
+}
+
+// Stop stops the load and waits for the workers to finish (memory is released, files removed)
+func (l *Load) Stop() {
+	l.once.Do(func() {
+		close(l.stop)
+		l.workers.Wait()
+		runtime.GC()
+		close(l.done)
+	})
+}
+
+// Done returns a channel that is closed once the load has stopped
+func (l *Load) Done() <-chan struct{} {
+	return l.done
+}
+
+// Remaining returns how long the load still runs
+func (l *Load) Remaining() time.Duration {
+	remaining := l.options.Duration - time.Since(l.started)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining.Round(time.Second)
+}
+
+// Err returns the first error of a worker (nil if all workers ran fine)
+func (l *Load) Err() error {
+	l.errMu.Lock()
+	defer l.errMu.Unlock()
+	return l.err
+}
+
+// fail records the error of a worker
+func (l *Load) fail(err error) {
+	l.errMu.Lock()
+	defer l.errMu.Unlock()
+	if l.err == nil {
+		l.err = err
+	}
+}
+
+// stopped checks if the load was stopped
+func (l *Load) stopped() bool {
+	select {
+	case <-l.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// burnCPU keeps one core busy until the load stops
+func (l *Load) burnCPU() {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	x := 1.0
+	for !l.stopped() {
+		// Enough work between checks that the channel check is negligible
+		for i := 0; i < 100000; i++ {
+			x = x*1.0000001 + 0.0000001
+		}
+	}
+	_ = x
+}
+
+// burnMemory allocates the memory and keeps it resident until the load stops
+func (l *Load) burnMemory() {
+	memory := make([]byte, l.options.MemoryBytes)
+	ticker := time.NewTicker(memoryTouchInterval)
+	defer ticker.Stop()
+
+	for value := byte(1); ; value++ {
+		for i := 0; i < len(memory); i += pageSize {
+			memory[i] = value
+		}
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// burnDisk rewrites a temporary file and syncs it until the load stops
+func (l *Load) burnDisk() {
+	file, err := os.CreateTemp("", "gomonitor-burn-*")
+	if err != nil {
+		l.fail(fmt.Errorf("error creating disk load file: %w", err))
+		return
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	chunk := make([]byte, 1<<20)
+	for i := range chunk {
+		chunk[i] = byte(i)
+	}
+
+	for !l.stopped() {
+		if _, err := file.Seek(0, 0); err != nil {
+			l.fail(fmt.Errorf("error writing disk load file: %w", err))
+			return
+		}
+		for written := uint64(0); written < l.options.DiskBytes && !l.stopped(); written += uint64(len(chunk)) {
+			size := min(uint64(len(chunk)), l.options.DiskBytes-written)
+			if _, err := file.Write(chunk[:size]); err != nil {
+				l.fail(fmt.Errorf("error writing disk load file: %w", err))
+				return
+			}
+		}
+		// Flush to the device so the load isn't absorbed by the page cache
+		if err := file.Sync(); err != nil {
+			l.fail(fmt.Errorf("error syncing disk load file: %w", err))
+			return
+		}
+	}
+}
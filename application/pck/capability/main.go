@@ -0,0 +1,162 @@
+package capability
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/host"
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// Capability identifies a gopsutil collection call
+type Capability string
+
+const (
+	CPUPercent     Capability = "cpu.Percent"        // Global and per-core CPU usage
+	CPUInfo        Capability = "cpu.Info"           // CPU model, cores and flags
+	CPUTimes       Capability = "cpu.Times"          // CPU time breakdown (user, system, iowait, ...)
+	Memory         Capability = "mem.VirtualMemory"  // RAM usage
+	Swap           Capability = "mem.SwapMemory"     // Swap usage
+	DiskPartitions Capability = "disk.Partitions"    // Mounted partitions
+	DiskIO         Capability = "disk.IOCounters"    // Per-device I/O counters
+	LoadAvg        Capability = "load.Avg"           // Load average (1, 5 and 15 minutes)
+	HostInfo       Capability = "host.Info"          // OS, kernel and uptime
+	HostUsers      Capability = "host.Users"         // Logged in users (utmp)
+	Processes      Capability = "process.Processes"  // Process list
+	ProcessMemory  Capability = "process.MemoryInfo" // Per-process memory usage
+)
+
+// Status is the result of probing a single capability
+type Status struct {
+	Capability  Capability // Probed gopsutil call
+	Description string     // What the call is used for
+	Supported   bool       // False if the call returned ErrNotImplemented on this OS
+	Err         error      // Any other error returned by the probe (nil if it worked)
+}
+
+// probe describes how to check a capability
+type probe struct {
+	capability  Capability
+	description string
+	run         func() error
+}
+
+// probes lists every capability, in the order they are reported
+var probes = []probe{
+	{CPUPercent, "CPU usage", func() error { _, err := cpu.Percent(0, false); return err }},
+	{CPUInfo, "CPU model and cores", func() error { _, err := cpu.Info(); return err }},
+	{CPUTimes, "CPU time breakdown", func() error { _, err := cpu.Times(false); return err }},
+	{Memory, "RAM usage", func() error { _, err := mem.VirtualMemory(); return err }},
+	{Swap, "Swap usage", func() error { _, err := mem.SwapMemory(); return err }},
+	{DiskPartitions, "Mounted partitions", func() error { _, err := disk.Partitions(false); return err }},
+	{DiskIO, "Disk I/O counters", func() error { _, err := disk.IOCounters(); return err }},
+	{LoadAvg, "Load average", func() error { _, err := load.Avg(); return err }},
+	{HostInfo, "OS and uptime", func() error { _, err := host.Info(); return err }},
+	{HostUsers, "Logged in users", func() error { _, err := host.Users(); return err }},
+	{Processes, "Process list", func() error { _, err := process.Processes(); return err }},
+	{ProcessMemory, "Process memory", probeProcessMemory},
+}
+
+var (
+	detectOnce sync.Once
+	statuses   []Status
+)
+
+// Detect probes every gopsutil call used by GoMonitor
+// The probes run only once; later calls return the cached matrix
+//
+// Returns:
+//   - slice of Status, one per capability
+func Detect() []Status {
+	detectOnce.Do(func() {
+		statuses = make([]Status, 0, len(probes))
+		for _, p := range probes {
+			err := p.run()
+			status := Status{
+				Capability:  p.capability,
+				Description: p.description,
+				Supported:   !IsNotImplemented(err),
+			}
+			if status.Supported {
+				status.Err = err
+			}
+			statuses = append(statuses, status)
+		}
+	})
+	return statuses
+}
+
+// IsSupported checks if a capability works on this OS
+//
+// Parameters:
+//   - c: capability to check (e.g. capability.Swap)
+//
+// Returns: false if the call returned ErrNotImplemented
+func IsSupported(c Capability) bool {
+	for _, status := range Detect() {
+		if status.Capability == c {
+			return status.Supported
+		}
+	}
+	return true
+}
+
+// IsNotImplemented checks if an error is (or wraps) gopsutil's ErrNotImplemented
+// gopsutil keeps the error in an internal package, so it's matched by message
+func IsNotImplemented(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "not implemented yet")
+}
+
+// Unsupported returns the text views show instead of zeros for an unsupported metric
+// e.g. "unsupported on darwin"
+func Unsupported() string {
+	return "unsupported on " + runtime.GOOS
+}
+
+// probeProcessMemory reads the memory of the current process
+func probeProcessMemory() error {
+	processes, err := process.Processes()
+	if err != nil {
+		return err
+	}
+	if len(processes) == 0 {
+		return nil
+	}
+	_, err = processes[0].MemoryInfo()
+	return err
+}
+
+// PrintMatrix prints the capability matrix in a formatted table
+//
+// Parameters:
+//   - statuses: slice of Status to present (from Detect)
+func PrintMatrix(statuses []Status) {
+	title := fmt.Sprintf("Collection Capabilities (%s/%s)", runtime.GOOS, runtime.GOARCH)
+	box := common.NewBox(title)
+
+	for _, status := range statuses {
+		result := "OK"
+		switch {
+		case !status.Supported:
+			result = Unsupported()
+		case status.Err != nil:
+			result = "error: " + status.Err.Error()
+		}
+
+		// Narrow terminals leave out the description, so the result still fits
+		if common.Compact() {
+			box.Text(fmt.Sprintf("%-20s %s", status.Capability, result))
+		} else {
+			box.Text(fmt.Sprintf("%-20s %-22s %s", status.Capability, status.Description, result))
+		}
+	}
+
+	box.End()
+}
@@ -0,0 +1,84 @@
+package capability
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/gpu"
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+)
+
+// Tool is an external program some views run
+// Minimal container images usually have none of them: the views that need one
+// then leave its data out
+type Tool struct {
+	Name    string // Executable name (e.g. "nvidia-smi")
+	Purpose string // What it is used for (short, shown in a column)
+	Path    string // Where it was found (empty if it's not installed)
+}
+
+// tools lists the external programs, in the order they are reported
+var tools = []Tool{
+	{Name: "nvidia-smi", Purpose: "NVIDIA GPU stats"},
+	{Name: "intel_gpu_top", Purpose: "Intel GPU usage"},
+	{Name: "smartctl", Purpose: "Disk health (SMART)"},
+	{Name: "systemctl", Purpose: "Services, throttling"},
+	{Name: "busctl", Purpose: "Session lock state"},
+	{Name: "journalctl", Purpose: "Failed units, cleanup"},
+	{Name: "docker", Purpose: "Docker cleanup"},
+	{Name: "ssh", Purpose: "gom diff --remote"},
+	{Name: "wl-copy", Purpose: "TUI copy (Wayland)"},
+	{Name: "xclip", Purpose: "TUI copy (X11)"},
+}
+
+// DetectTools looks up every external program in the PATH
+//
+// Returns: slice of Tool with Path set for the installed ones
+func DetectTools() []Tool {
+	found := make([]Tool, len(tools))
+	for i, tool := range tools {
+		found[i] = tool
+		if path, err := exec.LookPath(tool.Name); err == nil {
+			found[i].Path = path
+		}
+	}
+	return found
+}
+
+// PrintTools prints the external programs, whether NVML replaces nvidia-smi and where the
+// kernel file systems are read from
+//
+// Parameters:
+//   - tools: programs to present (from DetectTools)
+func PrintTools(tools []Tool) {
+	box := common.NewBox("External Tools")
+
+	for _, tool := range tools {
+		result := tool.Path
+		if result == "" {
+			result = "not installed"
+		}
+
+		// Narrow terminals leave out the purpose, so the path still fits
+		if common.Compact() {
+			box.Text(fmt.Sprintf("%-20s %s", tool.Name, result))
+		} else {
+			box.Text(fmt.Sprintf("%-20s %s %s", tool.Name, common.FitString(tool.Purpose, 22), result))
+		}
+	}
+
+	// With NVML the NVIDIA readings don't need nvidia-smi
+	nvml := "not available (nvidia-smi is used)"
+	if gpu.UsingNVML(context.Background()) {
+		nvml = "loaded"
+	}
+
+	box.Divider()
+	box.Text(fmt.Sprintf("%-20s %s", "nvml", nvml))
+	box.Text(fmt.Sprintf("%-20s %s", "procfs", paths.Proc()))
+	box.Text(fmt.Sprintf("%-20s %s", "sysfs", paths.Sys()))
+	box.Text(fmt.Sprintf("%-20s %t", "read-only", paths.ReadOnly()))
+	box.End()
+}
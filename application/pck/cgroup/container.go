@@ -0,0 +1,56 @@
+package cgroup
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// hostLevel makes the RAM and CPU figures use the host totals even in a limited cgroup (--host)
+var hostLevel atomic.Bool
+
+// SetHostLevel chooses between the limits of GoMonitor's cgroup and the host totals
+//
+// Parameters:
+//   - host: true to report the host RAM and CPUs even inside a container
+func SetHostLevel(host bool) {
+	hostLevel.Store(host)
+}
+
+// Effective gets the limits the RAM and CPU figures are reported against
+// Inside a container (e.g. docker run --memory 2g --cpus 1.5) the processes can only use
+// what the cgroup allows, so a host with 64 GB free would hide that the container is
+// about to be OOM-killed
+//
+// Returns:
+//   - Limits of GoMonitor's cgroup
+//   - false if it has no memory or CPU limit, can't be read, or --host was given
+func Effective() (Limits, bool) {
+	if hostLevel.Load() {
+		return Limits{}, false
+	}
+	limits, err := Self()
+	if err != nil || (!limits.HasMemoryLimit() && !limits.HasCPULimit()) {
+		return Limits{}, false
+	}
+	return limits, true
+}
+
+// SelfCPUTime reads the CPU time used by every process of GoMonitor's cgroup
+// Two readings give the usage against the CPU quota (see Limits.CPULimit)
+//
+// Returns:
+//   - CPU time of the cgroup
+//   - error if the cgroup or its CPU time can't be read
+func SelfCPUTime() (time.Duration, error) {
+	paths, err := Paths(int32(os.Getpid()))
+	if err != nil {
+		return 0, fmt.Errorf("error reading own cgroup: %w", err)
+	}
+	usage := GetUsage(paths)
+	if usage.CPUTime == 0 {
+		return 0, fmt.Errorf("error reading the CPU time of the cgroup")
+	}
+	return usage.CPUTime, nil
+}
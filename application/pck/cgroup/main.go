@@ -0,0 +1,293 @@
+package cgroup
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+)
+
+// Limits contains the memory and CPU limits of the cgroup a process runs in
+// Inside containers these limits, not the host RAM, determine when the OOM killer acts
+type Limits struct {
+	Version     int     // cgroup version (1 or 2)
+	Path        string  // cgroup path of the process (e.g. "/system.slice/docker-abc.scope")
+	MemoryUsage uint64  // Working set: memory charged to the cgroup minus inactive file cache (bytes)
+	MemoryLimit uint64  // Memory limit in bytes (0 if unlimited)
+	CPULimit    float64 // CPU quota in cores (0 if unlimited)
+}
+
+// cgroupRoot returns where the cgroup hierarchies are mounted
+func cgroupRoot() string {
+	return paths.Sys("fs", "cgroup")
+}
+
+// HasMemoryLimit checks if the cgroup (or one of its parents) limits memory
+func (l Limits) HasMemoryLimit() bool {
+	return l.MemoryLimit > 0
+}
+
+// HasCPULimit checks if the cgroup (or one of its parents) has a CPU quota
+func (l Limits) HasCPULimit() bool {
+	return l.CPULimit > 0
+}
+
+// MemoryPercent returns the memory usage relative to the limit (0 if unlimited)
+func (l Limits) MemoryPercent() float64 {
+	if !l.HasMemoryLimit() {
+		return 0
+	}
+	return float64(l.MemoryUsage) / float64(l.MemoryLimit) * 100
+}
+
+// FormatMemory formats the memory usage against the limit
+// e.g. "1.20 GB / 2.00 GB limit (60.00%)"
+func (l Limits) FormatMemory() string {
+	return fmt.Sprintf("%s / %s limit (%.2f%%)",
+		common.FormatBytes(l.MemoryUsage), common.FormatBytes(l.MemoryLimit), l.MemoryPercent())
+}
+
+// Self gets the cgroup limits of GoMonitor itself
+//
+// Returns:
+//   - Limits of the current process
+//   - error if the cgroup can't be read
+func Self() (Limits, error) {
+	return Get(int32(os.Getpid()))
+}
+
+// Get gets the cgroup limits of a process
+// The effective limit is the lowest one along the cgroup path, since parents
+// (e.g. the container's slice) can be more restrictive than the process's own cgroup
+//
+// Parameters:
+//   - pid: process ID
+//
+// Returns:
+//   - Limits of the process (no limits if it isn't in a limited cgroup)
+//   - error if the cgroup of the process can't be read
+func Get(pid int32) (Limits, error) {
+	paths, err := Paths(pid)
+	if err != nil {
+		return Limits{}, fmt.Errorf("error reading cgroup of PID %d: %w", pid, err)
+	}
+
+	// 1. cgroup v2 (unified hierarchy): one path for every controller
+	if isUnified() {
+		limits := Limits{Version: 2, Path: paths[""]}
+		dirs := cgroupDirs(cgroupRoot(), limits.Path)
+		limits.MemoryLimit = lowestLimit(dirs, "memory.max")
+		if limits.HasMemoryLimit() {
+			limits.MemoryUsage = workingSet(dirs[0], "memory.current", "inactive_file")
+		}
+		limits.CPULimit = lowestCPULimit(dirs, readCPUMaxV2)
+		return limits, nil
+	}
+
+	// 2. cgroup v1: each controller has its own hierarchy
+	limits := Limits{Version: 1, Path: paths["memory"]}
+	memoryRoot := filepath.Join(cgroupRoot(), "memory")
+	memoryDirs := cgroupDirs(memoryRoot, paths["memory"])
+	limits.MemoryLimit = lowestLimit(memoryDirs, "memory.limit_in_bytes")
+	if limits.HasMemoryLimit() {
+		limits.MemoryUsage = workingSet(memoryDirs[0], "memory.usage_in_bytes", "total_inactive_file")
+	}
+	cpuRoot := filepath.Join(cgroupRoot(), "cpu")
+	limits.CPULimit = lowestCPULimit(cgroupDirs(cpuRoot, paths["cpu"]), readCPUQuotaV1)
+
+	return limits, nil
+}
+
+// isUnified checks if the system uses the cgroup v2 unified hierarchy
+func isUnified() bool {
+	_, err := os.Stat(filepath.Join(cgroupRoot(), "cgroup.controllers"))
+	return err == nil
+}
+
+// Paths reads the cgroup paths of a process from /proc/<pid>/cgroup
+// Each line is "hierarchy-ID:controller-list:path"; cgroup v2 has an empty controller list
+//
+// Returns:
+//   - map of controller name to cgroup path ("" for the v2 unified hierarchy)
+//   - error if the file can't be read
+func Paths(pid int32) (map[string]string, error) {
+	file, err := os.Open(paths.Proc(fmt.Sprint(pid), "cgroup"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	paths := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		if fields[1] == "" {
+			paths[""] = fields[2]
+			continue
+		}
+		for _, controller := range strings.Split(fields[1], ",") {
+			paths[controller] = fields[2]
+		}
+	}
+
+	return paths, scanner.Err()
+}
+
+// cgroupDirs returns the directories from a cgroup up to the hierarchy root
+// Inside a cgroup namespace the path isn't visible under the mount, so the root is
+// used instead (it is then the container's own cgroup)
+//
+// Parameters:
+//   - root: mountpoint of the hierarchy (e.g. "/sys/fs/cgroup/memory")
+//   - path: cgroup path of the process
+//
+// Returns: directories, starting with the process's cgroup
+func cgroupDirs(root, path string) []string {
+	dir := filepath.Join(root, path)
+	if _, err := os.Stat(dir); err != nil {
+		return []string{root}
+	}
+
+	var dirs []string
+	for ; dir != root && strings.HasPrefix(dir, root); dir = filepath.Dir(dir) {
+		dirs = append(dirs, dir)
+	}
+	return append(dirs, root)
+}
+
+// lowestLimit returns the lowest memory limit set in any of the directories
+// v2 uses "max" for no limit; v1 uses a huge page-aligned number, so limits larger
+// than the host RAM are treated as unlimited
+//
+// Returns: limit in bytes (0 if unlimited)
+func lowestLimit(dirs []string, file string) uint64 {
+	hostTotal, _ := common.GetSystemMemoryTotal()
+
+	var lowest uint64
+	for _, dir := range dirs {
+		value := readString(filepath.Join(dir, file))
+		if value == "" || value == "max" {
+			continue
+		}
+		limit, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || limit == 0 || (hostTotal > 0 && limit >= hostTotal) {
+			continue
+		}
+		if lowest == 0 || limit < lowest {
+			lowest = limit
+		}
+	}
+	return lowest
+}
+
+// workingSet reads the memory usage of a cgroup without the inactive file cache
+// This is the value container runtimes report and compare against the limit
+//
+// Parameters:
+//   - dir: cgroup directory
+//   - usageFile: file with the charged memory ("memory.current" or "memory.usage_in_bytes")
+//   - inactiveKey: memory.stat key of the inactive file cache
+//
+// Returns: working set in bytes (0 if not available)
+func workingSet(dir, usageFile, inactiveKey string) uint64 {
+	usage, err := strconv.ParseUint(readString(filepath.Join(dir, usageFile)), 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	for _, line := range strings.Split(readString(filepath.Join(dir, "memory.stat")), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[0] != inactiveKey {
+			continue
+		}
+		if inactive, err := strconv.ParseUint(fields[1], 10, 64); err == nil && inactive < usage {
+			usage -= inactive
+		}
+		break
+	}
+
+	return usage
+}
+
+// lowestCPULimit returns the lowest CPU quota set in any of the directories
+//
+// Parameters:
+//   - dirs: cgroup directories (from cgroupDirs)
+//   - read: function reading the quota of one directory (in cores, 0 if unlimited)
+//
+// Returns: quota in cores (0 if unlimited)
+func lowestCPULimit(dirs []string, read func(dir string) float64) float64 {
+	var lowest float64
+	for _, dir := range dirs {
+		if cores := read(dir); cores > 0 && (lowest == 0 || cores < lowest) {
+			lowest = cores
+		}
+	}
+	return lowest
+}
+
+// readCPUMaxV2 reads the v2 cpu.max file ("<quota> <period>" or "max <period>")
+func readCPUMaxV2(dir string) float64 {
+	fields := strings.Fields(readString(filepath.Join(dir, "cpu.max")))
+	if len(fields) != 2 || fields[0] == "max" {
+		return 0
+	}
+	return quotaCores(fields[0], fields[1])
+}
+
+// readCPUQuotaV1 reads the v1 cpu.cfs_quota_us and cpu.cfs_period_us files (quota -1 means unlimited)
+func readCPUQuotaV1(dir string) float64 {
+	return quotaCores(readString(filepath.Join(dir, "cpu.cfs_quota_us")), readString(filepath.Join(dir, "cpu.cfs_period_us")))
+}
+
+// quotaCores converts a CFS quota and period (in microseconds) to a number of cores
+func quotaCores(quotaText, periodText string) float64 {
+	quota, err := strconv.ParseFloat(quotaText, 64)
+	if err != nil || quota <= 0 {
+		return 0
+	}
+	period, err := strconv.ParseFloat(periodText, 64)
+	if err != nil || period <= 0 {
+		return 0
+	}
+	return quota / period
+}
+
+// readString reads a trimmed string from a cgroup file (empty if not available)
+func readString(path string) string {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(buf))
+}
+
+// PrintLimits prints the cgroup limits of a process in a formatted table
+//
+// Parameters:
+//   - limits: Limits to present
+func PrintLimits(limits Limits) {
+	box := common.NewBox(fmt.Sprintf("Cgroup Limits (v%d)", limits.Version))
+	box.Field("Path", limits.Path)
+
+	memory := "unlimited"
+	if limits.HasMemoryLimit() {
+		memory = limits.FormatMemory()
+	}
+	box.Field("Memory", memory)
+
+	cpuLimit := "unlimited"
+	if limits.HasCPULimit() {
+		cpuLimit = fmt.Sprintf("%.2f cores", limits.CPULimit)
+	}
+	box.Field("CPU", cpuLimit)
+	box.End()
+}
@@ -0,0 +1,112 @@
+package cgroup
+
+import (
+	"fmt"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+)
+
+// Usage levels against a limit, in percent of the limit
+const (
+	WarningPercent  = 80 // Close to the limit: memory is reclaimed, CPU is about to be throttled
+	CriticalPercent = 95 // At the limit: CPU is throttled, memory is about to be OOM-killed
+)
+
+// LimitUsage is the usage of a service or container against its cgroup limits
+// Like Kubernetes' usage against limits, it shows how close a workload is to being
+// throttled or OOM-killed, which usage against the host's resources doesn't
+type LimitUsage struct {
+	Name        string  // Service or container name
+	CPUPercent  float64 // CPU usage (100% = one core)
+	CPULimit    float64 // CPU quota in cores (0 if unlimited)
+	MemoryUsage uint64  // Working set in bytes
+	MemoryLimit uint64  // Memory limit in bytes (0 if unlimited)
+}
+
+// Limited checks if the CPU or memory is limited
+func (u LimitUsage) Limited() bool {
+	return u.CPULimit > 0 || u.MemoryLimit > 0
+}
+
+// CPULimitPercent returns the CPU usage relative to the quota (0 if unlimited)
+func (u LimitUsage) CPULimitPercent() float64 {
+	if u.CPULimit <= 0 {
+		return 0
+	}
+	return u.CPUPercent / u.CPULimit
+}
+
+// MemoryLimitPercent returns the memory usage relative to the limit (0 if unlimited)
+func (u LimitUsage) MemoryLimitPercent() float64 {
+	if u.MemoryLimit == 0 {
+		return 0
+	}
+	return float64(u.MemoryUsage) / float64(u.MemoryLimit) * 100
+}
+
+// LevelColors are the ANSI colors of the usage levels
+// Empty colors (e.g. with the monochrome theme) print the table without colors
+type LevelColors struct {
+	Normal   string // Below WarningPercent
+	Warning  string // From WarningPercent
+	Critical string // From CriticalPercent
+	Reset    string // Resets the color after a value
+}
+
+// For returns the color of a usage level
+func (c LevelColors) For(percent float64) string {
+	switch {
+	case percent >= CriticalPercent:
+		return c.Critical
+	case percent >= WarningPercent:
+		return c.Warning
+	default:
+		return c.Normal
+	}
+}
+
+// PrintLimitUsage prints the usage against the limits in a formatted table
+// Only entries with a CPU or memory limit are listed
+//
+// Parameters:
+//   - title: table title (e.g. "Services Against Their Limits")
+//   - usages: slice of LimitUsage to present
+//   - colors: colors of the usage levels
+func PrintLimitUsage(title string, usages []LimitUsage, colors LevelColors) {
+	table := common.NewTable(title,
+		common.Column{Header: "Name", Fill: true}, common.Column{Header: "CPU / Quota", Short: "CPU/Quota", Optional: true},
+		common.Column{Header: "CPU", Right: true}, common.Column{Header: "Memory / Limit", Short: "Mem/Limit", Optional: true},
+		common.Column{Header: "Mem", Right: true})
+
+	limited := 0
+	for _, u := range usages {
+		if !u.Limited() {
+			continue
+		}
+		limited++
+
+		cpu, cpuPercent := "unlimited", "-"
+		if u.CPULimit > 0 {
+			cpu = fmt.Sprintf("%.2f / %.2f cores", u.CPUPercent/100, u.CPULimit)
+			cpuPercent = formatLevel(u.CPULimitPercent(), colors)
+		}
+		memory, memoryPercent := "unlimited", "-"
+		if u.MemoryLimit > 0 {
+			memory = common.FormatBytes(u.MemoryUsage) + " / " + common.FormatBytes(u.MemoryLimit)
+			memoryPercent = formatLevel(u.MemoryLimitPercent(), colors)
+		}
+
+		table.AddRow(common.TruncateString(u.Name, 18), cpu, cpuPercent, memory, memoryPercent)
+	}
+
+	if limited == 0 {
+		table.AddNote("No CPU or memory limits configured")
+	}
+
+	table.Print()
+}
+
+// formatLevel formats a percentage of a limit in the color of its level (5 characters wide)
+func formatLevel(percent float64, colors LevelColors) string {
+	return colors.For(percent) + fmt.Sprintf("%4.0f%%", percent) + colors.Reset
+}
@@ -0,0 +1,319 @@
+package cgroup
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+)
+
+// How a process was throttled
+const (
+	ThrottleSystemd = "systemd scope" // Transient scope unit created through systemd's D-Bus API
+	ThrottleCgroup  = "cgroup"        // Cgroup created by GoMonitor under its own directory
+)
+
+// throttleDir is the directory GoMonitor creates its cgroups in (in each hierarchy)
+const throttleDir = "gomonitor"
+
+// cfsPeriod is the CFS period used for the CPU quota (100ms, the kernel default)
+const cfsPeriod = 100000
+
+// Quota contains the limits a process is throttled to
+type Quota struct {
+	CPUCores    float64 // CPU time in cores (0 for no CPU limit)
+	MemoryBytes uint64  // Memory limit in bytes (0 for no memory limit)
+}
+
+// String formats the quota (e.g. "2.00 cores, 4.00 GB")
+func (q Quota) String() string {
+	var parts []string
+	if q.CPUCores > 0 {
+		parts = append(parts, fmt.Sprintf("%.2f cores", q.CPUCores))
+	}
+	if q.MemoryBytes > 0 {
+		parts = append(parts, common.FormatBytes(q.MemoryBytes))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Validate checks that the quota limits something and that the limits are usable
+//
+// Returns: error describing the first invalid limit
+func (q Quota) Validate() error {
+	if q.CPUCores == 0 && q.MemoryBytes == 0 {
+		return fmt.Errorf("no limit given (use a CPU and/or memory limit)")
+	}
+	if q.CPUCores < 0 || (q.CPUCores > 0 && q.CPUCores < 0.01) {
+		return fmt.Errorf("CPU limit must be at least 0.01 cores, got %g", q.CPUCores)
+	}
+	if q.MemoryBytes > 0 && q.MemoryBytes < 4<<20 {
+		return fmt.Errorf("memory limit must be at least 4 MB, got %s", common.FormatBytes(q.MemoryBytes))
+	}
+	return nil
+}
+
+// cpuQuotaMicros returns the CPU quota per CFS period in microseconds
+func (q Quota) cpuQuotaMicros() int64 {
+	return int64(q.CPUCores * cfsPeriod)
+}
+
+// ThrottleResult describes where a throttled process was placed
+type ThrottleResult struct {
+	Method string // ThrottleSystemd or ThrottleCgroup
+	Name   string // Scope unit name or cgroup directory
+}
+
+// Throttle places a running process in a new cgroup with CPU and memory limits
+// A gentler alternative to killing a runaway process: it keeps running, only slower
+// (or reclaiming memory). Uses a transient systemd scope when systemd manages the
+// cgroups, so systemd doesn't move the process back, and a GoMonitor cgroup otherwise.
+// The limits apply to the process and the children it starts afterwards; they are
+// removed when the process exits
+//
+// Parameters:
+//   - pid: process to throttle
+//   - quota: limits to apply
+//
+// Returns:
+//   - ThrottleResult with the scope or cgroup used
+//   - error if the process can't be moved (usually missing permissions)
+func Throttle(pid int32, quota Quota) (ThrottleResult, error) {
+	if err := quota.Validate(); err != nil {
+		return ThrottleResult{}, err
+	}
+	if _, err := os.Stat(paths.Proc(fmt.Sprint(pid))); err != nil {
+		return ThrottleResult{}, fmt.Errorf("process %d not found", pid)
+	}
+
+	// 1. systemd owns the cgroup tree, so ask it for a scope when it is running
+	if _, err := os.Stat("/run/systemd/system"); err == nil {
+		result, err := throttleSystemd(pid, quota)
+		if err == nil {
+			return result, nil
+		}
+		common.Logger().Debug("systemd throttle not available", "pid", pid, "err", err)
+	}
+
+	// 2. Otherwise create the cgroup directly
+	if isUnified() {
+		return throttleCgroupV2(pid, quota)
+	}
+	return throttleCgroupV1(pid, quota)
+}
+
+// throttleSystemd moves a process into a transient scope unit with the limits
+// systemd-run can only start new commands, so the scope is created with busctl
+// (StartTransientUnit with the PIDs property adopts a running process). A process
+// that is already in its GoMonitor scope has the unit's limits changed instead
+//
+// Returns:
+//   - ThrottleResult with the scope name
+//   - error if busctl isn't installed or systemd refuses the request
+func throttleSystemd(pid int32, quota Quota) (ThrottleResult, error) {
+	unit := fmt.Sprintf("gomonitor-throttle-%d.scope", pid)
+	result := ThrottleResult{Method: ThrottleSystemd, Name: unit}
+
+	// Processes of other users need the system manager (root); our own use the user manager
+	scope := "--system"
+	if os.Geteuid() != 0 {
+		scope = "--user"
+	}
+
+	// 1. Already throttled: change the limits of the existing scope
+	if paths, err := Paths(pid); err == nil && (strings.HasSuffix(paths[""], "/"+unit) || strings.HasSuffix(paths["cpu"], "/"+unit)) {
+		args := []string{scope, "set-property", "--runtime", unit}
+		args = append(args, systemdProperties(quota)...)
+		cmd := exec.Command("systemctl", args...)
+		started := time.Now()
+		output, err := cmd.CombinedOutput()
+		common.LogCommand(cmd, started, err)
+		if err != nil {
+			return result, fmt.Errorf("error running systemctl: %s", commandError(output, err))
+		}
+		return result, nil
+	}
+
+	// 2. New scope: signature "ssa(sv)a(sa(sv))" is name, mode, properties and auxiliary units
+	properties := []string{
+		"PIDs", "au", "1", strconv.Itoa(int(pid)),
+		"Description", "s", fmt.Sprintf("Process %d throttled by GoMonitor", pid),
+	}
+	if quota.CPUCores > 0 {
+		properties = append(properties, "CPUQuotaPerSecUSec", "t", strconv.FormatInt(quota.cpuQuotaMicros()*10, 10))
+	}
+	if quota.MemoryBytes > 0 {
+		properties = append(properties, "MemoryMax", "t", strconv.FormatUint(quota.MemoryBytes, 10))
+	}
+
+	args := []string{scope, "call", "org.freedesktop.systemd1", "/org/freedesktop/systemd1",
+		"org.freedesktop.systemd1.Manager", "StartTransientUnit", "ssa(sv)a(sa(sv))",
+		unit, "fail", strconv.Itoa(len(properties) / 3)}
+	args = append(args, properties...)
+	args = append(args, "0")
+
+	cmd := exec.Command("busctl", args...)
+	started := time.Now()
+	output, err := cmd.CombinedOutput()
+	common.LogCommand(cmd, started, err)
+	if err != nil {
+		return result, fmt.Errorf("error running busctl: %s", commandError(output, err))
+	}
+	return result, nil
+}
+
+// systemdProperties returns the unit properties of a quota for systemctl set-property
+func systemdProperties(quota Quota) []string {
+	cpuQuota, memoryMax := "CPUQuota=", "MemoryMax=infinity"
+	if quota.CPUCores > 0 {
+		cpuQuota = fmt.Sprintf("CPUQuota=%d%%", int(quota.CPUCores*100))
+	}
+	if quota.MemoryBytes > 0 {
+		memoryMax = fmt.Sprintf("MemoryMax=%d", quota.MemoryBytes)
+	}
+	return []string{cpuQuota, memoryMax}
+}
+
+// commandError returns the output of a failed command, or the error if there is none
+func commandError(output []byte, err error) string {
+	if message := strings.TrimSpace(string(output)); message != "" {
+		return message
+	}
+	return err.Error()
+}
+
+// throttleCgroupV2 moves a process into /sys/fs/cgroup/gomonitor/pid-<pid>
+// The cpu and memory controllers have to be enabled for the children of the root
+// and of the gomonitor directory, which holds no processes itself
+//
+// Returns:
+//   - ThrottleResult with the cgroup directory
+//   - error if the cgroup can't be created or the process can't be moved
+func throttleCgroupV2(pid int32, quota Quota) (ThrottleResult, error) {
+	// 1. Enable the controllers down to the gomonitor directory
+	base := filepath.Join(cgroupRoot(), throttleDir)
+	if err := os.MkdirAll(base, 0755); err != nil {
+		return ThrottleResult{}, fmt.Errorf("error creating cgroup: %w", err)
+	}
+	for _, dir := range []string{cgroupRoot(), base} {
+		for _, controller := range []string{"+cpu", "+memory"} {
+			if err := writeControl(filepath.Join(dir, "cgroup.subtree_control"), controller); err != nil {
+				return ThrottleResult{}, fmt.Errorf("error enabling the %s controller: %w", controller[1:], err)
+			}
+		}
+	}
+
+	// 2. Create the process's cgroup and set the limits
+	dir, err := newThrottleCgroup(base, pid)
+	if err != nil {
+		return ThrottleResult{}, err
+	}
+	cpuMax := fmt.Sprintf("max %d", cfsPeriod)
+	if quota.CPUCores > 0 {
+		cpuMax = fmt.Sprintf("%d %d", quota.cpuQuotaMicros(), cfsPeriod)
+	}
+	memoryMax := "max"
+	if quota.MemoryBytes > 0 {
+		memoryMax = strconv.FormatUint(quota.MemoryBytes, 10)
+	}
+	if err := writeControl(filepath.Join(dir, "cpu.max"), cpuMax); err != nil {
+		return ThrottleResult{}, fmt.Errorf("error setting CPU limit: %w", err)
+	}
+	if err := writeControl(filepath.Join(dir, "memory.max"), memoryMax); err != nil {
+		return ThrottleResult{}, fmt.Errorf("error setting memory limit: %w", err)
+	}
+
+	// 3. Move the process
+	if err := writeControl(filepath.Join(dir, "cgroup.procs"), strconv.Itoa(int(pid))); err != nil {
+		return ThrottleResult{}, fmt.Errorf("error moving process %d: %w", pid, err)
+	}
+	return ThrottleResult{Method: ThrottleCgroup, Name: dir}, nil
+}
+
+// throttleCgroupV1 moves a process into gomonitor/pid-<pid> of the cpu and memory hierarchies
+//
+// Returns:
+//   - ThrottleResult with the cpu cgroup directory
+//   - error if a cgroup can't be created or the process can't be moved
+func throttleCgroupV1(pid int32, quota Quota) (ThrottleResult, error) {
+	// 1. CPU: quota -1 removes the limit of an already throttled process
+	cpuDir, err := newThrottleCgroup(filepath.Join(cgroupRoot(), "cpu", throttleDir), pid)
+	if err != nil {
+		return ThrottleResult{}, err
+	}
+	cpuQuota := "-1"
+	if quota.CPUCores > 0 {
+		cpuQuota = strconv.FormatInt(quota.cpuQuotaMicros(), 10)
+	}
+	if err := writeControl(filepath.Join(cpuDir, "cpu.cfs_period_us"), strconv.Itoa(cfsPeriod)); err != nil {
+		return ThrottleResult{}, fmt.Errorf("error setting CPU period: %w", err)
+	}
+	if err := writeControl(filepath.Join(cpuDir, "cpu.cfs_quota_us"), cpuQuota); err != nil {
+		return ThrottleResult{}, fmt.Errorf("error setting CPU limit: %w", err)
+	}
+
+	// 2. Memory: -1 removes the limit
+	memoryDir, err := newThrottleCgroup(filepath.Join(cgroupRoot(), "memory", throttleDir), pid)
+	if err != nil {
+		return ThrottleResult{}, err
+	}
+	memoryLimit := "-1"
+	if quota.MemoryBytes > 0 {
+		memoryLimit = strconv.FormatUint(quota.MemoryBytes, 10)
+	}
+	if err := writeControl(filepath.Join(memoryDir, "memory.limit_in_bytes"), memoryLimit); err != nil {
+		return ThrottleResult{}, fmt.Errorf("error setting memory limit: %w", err)
+	}
+
+	// 3. Move the process in both hierarchies
+	for _, dir := range []string{cpuDir, memoryDir} {
+		if err := writeControl(filepath.Join(dir, "cgroup.procs"), strconv.Itoa(int(pid))); err != nil {
+			return ThrottleResult{}, fmt.Errorf("error moving process %d: %w", pid, err)
+		}
+	}
+	return ThrottleResult{Method: ThrottleCgroup, Name: cpuDir}, nil
+}
+
+// newThrottleCgroup creates (or reuses) the cgroup of a process under a GoMonitor directory
+// Cgroups left over by processes that have exited are removed first: the kernel
+// refuses to remove a cgroup that still has processes, so only empty ones go away
+//
+// Parameters:
+//   - base: GoMonitor directory of the hierarchy
+//   - pid: process to throttle
+//
+// Returns:
+//   - cgroup directory of the process
+//   - error if it can't be created
+func newThrottleCgroup(base string, pid int32) (string, error) {
+	if entries, err := os.ReadDir(base); err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() && strings.HasPrefix(entry.Name(), "pid-") {
+				os.Remove(filepath.Join(base, entry.Name()))
+			}
+		}
+	}
+
+	dir := filepath.Join(base, fmt.Sprintf("pid-%d", pid))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("error creating cgroup: %w", err)
+	}
+	return dir, nil
+}
+
+// writeControl writes a value to a cgroup control file
+// Permission errors get a hint, since throttling usually needs root
+func writeControl(path, value string) error {
+	err := os.WriteFile(path, []byte(value), 0644)
+	if errors.Is(err, os.ErrPermission) {
+		return fmt.Errorf("permission denied (run as root or throttle your own processes under systemd)")
+	}
+	return err
+}
@@ -0,0 +1,120 @@
+package cgroup
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Usage contains the resource usage counters of a cgroup
+// CPU time and I/O bytes are cumulative, so rates need two readings
+type Usage struct {
+	CPUTime     time.Duration // CPU time consumed by all tasks of the cgroup
+	MemoryUsage uint64        // Working set in bytes (charged memory minus inactive file cache)
+	ReadBytes   uint64        // Bytes read from block devices
+	WriteBytes  uint64        // Bytes written to block devices
+}
+
+// GetUsage reads the usage counters of a cgroup
+// Counters that can't be read (e.g. controller not enabled) are left at 0
+//
+// Parameters:
+//   - paths: cgroup paths of a process in the cgroup (from Paths)
+//
+// Returns: Usage of the cgroup
+func GetUsage(paths map[string]string) Usage {
+	var usage Usage
+
+	// 1. cgroup v2: cpu.stat, memory.current and io.stat in the same directory
+	if isUnified() {
+		dir := filepath.Join(cgroupRoot(), paths[""])
+		if usec, ok := readStatValue(filepath.Join(dir, "cpu.stat"), "usage_usec"); ok {
+			usage.CPUTime = time.Duration(usec) * time.Microsecond
+		}
+		usage.MemoryUsage = workingSet(dir, "memory.current", "inactive_file")
+		usage.ReadBytes, usage.WriteBytes = readIOStatV2(filepath.Join(dir, "io.stat"))
+		return usage
+	}
+
+	// 2. cgroup v1: cpuacct, memory and blkio hierarchies
+	cpuacctDir := filepath.Join(cgroupRoot(), "cpuacct", paths["cpuacct"])
+	if ns, err := strconv.ParseUint(readString(filepath.Join(cpuacctDir, "cpuacct.usage")), 10, 64); err == nil {
+		usage.CPUTime = time.Duration(ns)
+	}
+	usage.MemoryUsage = workingSet(filepath.Join(cgroupRoot(), "memory", paths["memory"]),
+		"memory.usage_in_bytes", "total_inactive_file")
+	blkioDir := filepath.Join(cgroupRoot(), "blkio", paths["blkio"])
+	usage.ReadBytes, usage.WriteBytes = readIOServiceBytesV1(filepath.Join(blkioDir, "blkio.throttle.io_service_bytes"))
+
+	return usage
+}
+
+// readStatValue reads one "key value" line of a stat file (e.g. cpu.stat)
+//
+// Returns:
+//   - value of the key
+//   - false if the file or key doesn't exist
+func readStatValue(path, key string) (uint64, bool) {
+	for _, line := range strings.Split(readString(path), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[0] != key {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		return value, err == nil
+	}
+	return 0, false
+}
+
+// readIOStatV2 sums the bytes read and written over every device in io.stat
+// Each line is "<major>:<minor> rbytes=N wbytes=N rios=N wios=N ..."
+//
+// Returns: bytes read and bytes written
+func readIOStatV2(path string) (uint64, uint64) {
+	var read, written uint64
+	for _, line := range strings.Split(readString(path), "\n") {
+		for _, field := range strings.Fields(line) {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			n, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				continue
+			}
+			switch key {
+			case "rbytes":
+				read += n
+			case "wbytes":
+				written += n
+			}
+		}
+	}
+	return read, written
+}
+
+// readIOServiceBytesV1 sums the bytes read and written over every device in blkio.throttle.io_service_bytes
+// Each line is "<major>:<minor> Read|Write|Sync|Async|Discard|Total N"
+//
+// Returns: bytes read and bytes written
+func readIOServiceBytesV1(path string) (uint64, uint64) {
+	var read, written uint64
+	for _, line := range strings.Split(readString(path), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		n, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[1] {
+		case "Read":
+			read += n
+		case "Write":
+			written += n
+		}
+	}
+	return read, written
+}
@@ -0,0 +1,160 @@
+package check
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/cpu"
+	"github.com/dfialho05/GoMonitor/application/pck/disk"
+	"github.com/dfialho05/GoMonitor/application/pck/ram"
+)
+
+// Status is the result of a check, as a Nagios/Icinga plugin exit code
+type Status int
+
+// Plugin exit codes (1 is WARNING, which the maximum thresholds don't use)
+const (
+	StatusOK       Status = 0 // Every value is within its threshold
+	StatusCritical Status = 2 // At least one value is above its threshold
+	StatusUnknown  Status = 3 // A value couldn't be measured
+)
+
+// String returns the status name used in the plugin output (e.g. "CRITICAL")
+func (s Status) String() string {
+	switch s {
+	case StatusOK:
+		return "OK"
+	case StatusCritical:
+		return "CRITICAL"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// cpuSampleTime is how long the CPU usage is measured
+const cpuSampleTime = time.Second
+
+// Thresholds are the maximum usage percentages (0 leaves the metric unchecked)
+type Thresholds struct {
+	CPU    float64 // Global CPU usage
+	RAM    float64 // RAM usage
+	Disk   float64 // Usage of every mounted disk
+	Inodes float64 // Inode usage of every mounted disk (file systems without an inode table are skipped)
+}
+
+// Measurement is a measured value and its threshold
+type Measurement struct {
+	Label     string  // Metric name in the output (e.g. "cpu" or "disk /home")
+	Value     float64 // Usage percentage
+	Threshold float64 // Maximum usage percentage
+}
+
+// Exceeded checks if the value is above its threshold
+func (m Measurement) Exceeded() bool {
+	return m.Value > m.Threshold
+}
+
+// Result contains the measurements of a check and its status
+type Result struct {
+	Status       Status
+	Measurements []Measurement
+	Err          error // Why the status is UNKNOWN (nil otherwise)
+}
+
+// Run samples the metrics that have a threshold once and compares them against it
+// The CPU usage is measured over one second
+//
+// Parameters:
+//   - thresholds: maximum usage of each metric
+//   - disks: disks that are checked against thresholds.Disk and thresholds.Inodes
+//
+// Returns: Result (StatusUnknown if nothing is checked or a metric can't be read)
+func Run(thresholds Thresholds, disks disk.Filter) Result {
+	if thresholds.CPU <= 0 && thresholds.RAM <= 0 && thresholds.Disk <= 0 && thresholds.Inodes <= 0 {
+		return Result{Status: StatusUnknown, Err: errors.New("no thresholds given (e.g. --cpu-max 90)")}
+	}
+
+	var result Result
+	add := func(label string, value, threshold float64) {
+		result.Measurements = append(result.Measurements, Measurement{Label: label, Value: value, Threshold: threshold})
+	}
+	fail := func(err error) Result {
+		return Result{Status: StatusUnknown, Measurements: result.Measurements, Err: err}
+	}
+
+	// 1. CPU: the first reading is only a baseline
+	if thresholds.CPU > 0 {
+		cpu.GetSystemPercent()
+		time.Sleep(cpuSampleTime)
+		percent, err := cpu.GetSystemPercent()
+		if err != nil {
+			return fail(err)
+		}
+		add("cpu", percent, thresholds.CPU)
+	}
+
+	// 2. RAM
+	if thresholds.RAM > 0 {
+		stats, err := ram.GetRamGeneral()
+		if err != nil {
+			return fail(err)
+		}
+		add("ram", stats.Percent, thresholds.RAM)
+	}
+
+	// 3. Every listed disk, for its space and its inodes
+	if thresholds.Disk > 0 || thresholds.Inodes > 0 {
+		devices, err := disk.GetAllStorageDevices(disks)
+		if err != nil {
+			return fail(err)
+		}
+		for _, device := range devices {
+			if thresholds.Disk > 0 {
+				add("disk "+device.Mountpoint, device.Percent, thresholds.Disk)
+			}
+			if thresholds.Inodes > 0 && device.InodesTotal > 0 {
+				add("inodes "+device.Mountpoint, device.InodesPercent, thresholds.Inodes)
+			}
+		}
+	}
+
+	result.Status = StatusOK
+	for _, m := range result.Measurements {
+		if m.Exceeded() {
+			result.Status = StatusCritical
+		}
+	}
+	return result
+}
+
+// String formats the result as a plugin output line with performance data
+// e.g. "GOMONITOR CRITICAL - ram 85.2% > 80% | 'cpu'=12.3%;;90;0;100 'ram'=85.2%;;80;0;100"
+func (r Result) String() string {
+	var summary []string
+	if r.Err != nil {
+		summary = append(summary, r.Err.Error())
+	} else {
+		// Only the exceeded values when critical, every value when OK
+		for _, m := range r.Measurements {
+			switch {
+			case m.Exceeded():
+				summary = append(summary, fmt.Sprintf("%s %.1f%% > %g%%", m.Label, m.Value, m.Threshold))
+			case r.Status == StatusOK:
+				summary = append(summary, fmt.Sprintf("%s %.1f%%", m.Label, m.Value))
+			}
+		}
+	}
+
+	var perfdata []string
+	for _, m := range r.Measurements {
+		perfdata = append(perfdata, fmt.Sprintf("'%s'=%.1f%%;;%g;0;100", m.Label, m.Value, m.Threshold))
+	}
+
+	line := fmt.Sprintf("GOMONITOR %s - %s", r.Status, strings.Join(summary, ", "))
+	if len(perfdata) > 0 {
+		line += " | " + strings.Join(perfdata, " ")
+	}
+	return line
+}
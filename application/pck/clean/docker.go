@@ -0,0 +1,84 @@
+package clean
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+)
+
+// dockerPrune maps the "docker system df" types to their cleanup
+var dockerPrune = []struct {
+	dfType      string   // Type column of "docker system df"
+	id          string   // Category name used with --apply
+	description string   // What would be removed
+	command     []string // Command that frees the space
+}{
+	{"Images", "docker-images", "Docker images not used by any container", []string{"docker", "image", "prune", "--all", "--force"}},
+	{"Local Volumes", "docker-volumes", "Docker volumes not used by any container", []string{"docker", "volume", "prune", "--force"}},
+}
+
+// dockerCandidates asks Docker how much space unused images and volumes take
+// Requires access to the Docker daemon (root or the docker group)
+//
+// Returns: slice of Candidate (empty if Docker is not installed or not reachable)
+func dockerCandidates() []Candidate {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return nil
+	}
+
+	output, err := common.RunCommand(context.Background(), "docker", "system", "df", "--format", "{{.Type}}\t{{.Reclaimable}}")
+	if err != nil {
+		return nil
+	}
+
+	// Each line is e.g. "Images\t1.234GB (45%)"
+	reclaimable := make(map[string]uint64)
+	for _, line := range strings.Split(string(output), "\n") {
+		dfType, value, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		if fields := strings.Fields(value); len(fields) > 0 {
+			reclaimable[dfType] = parseDockerSize(fields[0])
+		}
+	}
+
+	var candidates []Candidate
+	for _, prune := range dockerPrune {
+		command := prune.command
+		candidates = append(candidates, Candidate{
+			ID:          prune.id,
+			Description: prune.description,
+			Bytes:       reclaimable[prune.dfType],
+			Action:      strings.Join(command, " "),
+			clean:       func() error { return run(command...) },
+		})
+	}
+	return candidates
+}
+
+// parseDockerSize parses the sizes printed by Docker (decimal units, e.g. "1.234GB", "512kB", "0B")
+//
+// Returns: size in bytes (0 if it can't be parsed)
+func parseDockerSize(text string) uint64 {
+	units := []struct {
+		suffix     string
+		multiplier float64
+	}{
+		{"TB", 1e12}, {"GB", 1e9}, {"MB", 1e6}, {"kB", 1e3}, {"B", 1},
+	}
+
+	for _, unit := range units {
+		if number, ok := strings.CutSuffix(text, unit.suffix); ok {
+			value, err := strconv.ParseFloat(number, 64)
+			if err != nil {
+				return 0
+			}
+			return uint64(value * unit.multiplier)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,284 @@
+package clean
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+)
+
+// Candidate is a category of reclaimable disk space
+type Candidate struct {
+	ID          string       // Category name used with --apply (e.g. "journal")
+	Description string       // What would be removed
+	Bytes       uint64       // Estimated space that would be freed
+	Action      string       // How it is cleaned (command or files), shown in the report
+	clean       func() error // Frees the space
+}
+
+// Thresholds for the file based categories
+const (
+	journalMaxAge = 14 * 24 * time.Hour // Archived journal files older than this are reclaimable
+	tmpMinSize    = 10 * 1024 * 1024    // Only large files in /tmp are suggested
+	tmpMinAge     = 7 * 24 * time.Hour  // ... and only if they haven't been modified for a week
+	cacheMinAge   = 30 * 24 * time.Hour // Files in ~/.cache not modified for a month
+)
+
+// packageCache describes the download cache of a package manager
+type packageCache struct {
+	id      string   // Category name used with --apply
+	tool    string   // Package manager executable (only suggested if installed)
+	dir     string   // Cache directory
+	command []string // Command that empties the cache
+}
+
+// packageCaches are the package managers whose caches can be emptied safely
+var packageCaches = []packageCache{
+	{"packages-apt", "apt-get", "/var/cache/apt/archives", []string{"apt-get", "clean"}},
+	{"packages-dnf", "dnf", "/var/cache/dnf", []string{"dnf", "clean", "packages"}},
+	{"packages-pacman", "pacman", "/var/cache/pacman/pkg", []string{"pacman", "-Scc", "--noconfirm"}},
+}
+
+// Scan looks for reclaimable space without changing anything
+// Only categories with something to free are returned
+//
+// Returns: slice of Candidate sorted by reclaimable space (descending)
+func Scan() []Candidate {
+	var candidates []Candidate
+
+	// 1. Package manager caches
+	for _, cache := range packageCaches {
+		if _, err := exec.LookPath(cache.tool); err != nil {
+			continue
+		}
+		command := cache.command
+		candidates = append(candidates, Candidate{
+			ID:          cache.id,
+			Description: "Downloaded packages in " + cache.dir,
+			Bytes:       dirSize(cache.dir),
+			Action:      strings.Join(command, " "),
+			clean:       func() error { return run(command...) },
+		})
+	}
+
+	// 2. Old journald logs
+	if _, err := exec.LookPath("journalctl"); err == nil {
+		files := staleFiles("/var/log/journal", 0, journalMaxAge, func(path string) bool {
+			// Only archived journals (active ones have no "@" in the name) are removed by vacuum
+			return strings.HasSuffix(path, ".journal") && strings.Contains(filepath.Base(path), "@")
+		})
+		vacuum := fmt.Sprintf("--vacuum-time=%dd", int(journalMaxAge.Hours()/24))
+		candidates = append(candidates, Candidate{
+			ID:          "journal",
+			Description: "Archived journald logs older than 2 weeks",
+			Bytes:       totalSize(files),
+			Action:      "journalctl " + vacuum,
+			clean:       func() error { return run("journalctl", vacuum) },
+		})
+	}
+
+	// 3. Unused Docker images and volumes
+	candidates = append(candidates, dockerCandidates()...)
+
+	// 4. Large stale files in /tmp, only those of the invoking user (other users' files
+	// are shared by everyone and must not be removed just because gom runs as root)
+	uid := invokingUID()
+	tmpFiles := staleFiles(os.TempDir(), tmpMinSize, tmpMinAge, func(path string) bool {
+		return ownedBy(path, uid)
+	})
+	candidates = append(candidates, Candidate{
+		ID:          "tmp",
+		Description: "Your files over 10 MB in " + os.TempDir() + " untouched for a week",
+		Bytes:       totalSize(tmpFiles),
+		Action:      describeFiles(tmpFiles),
+		clean:       func() error { return removeFiles(tmpFiles) },
+	})
+
+	// 5. Stale files in the user's cache
+	if home, err := os.UserHomeDir(); err == nil {
+		cacheDir := filepath.Join(home, ".cache")
+		cacheFiles := staleFiles(cacheDir, 0, cacheMinAge, nil)
+		candidates = append(candidates, Candidate{
+			ID:          "cache",
+			Description: "Files in " + cacheDir + " untouched for a month",
+			Bytes:       totalSize(cacheFiles),
+			Action:      describeFiles(cacheFiles),
+			clean:       func() error { return removeFiles(cacheFiles) },
+		})
+	}
+
+	// Keep only categories with something to free
+	found := candidates[:0]
+	for _, candidate := range candidates {
+		if candidate.Bytes > 0 {
+			found = append(found, candidate)
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].Bytes > found[j].Bytes
+	})
+
+	return found
+}
+
+// Apply frees the space of a candidate
+//
+// Parameters:
+//   - candidate: category to clean (from Scan)
+//
+// Returns: error if the cleanup failed (usually missing permissions)
+func Apply(candidate Candidate) error {
+	if err := candidate.clean(); err != nil {
+		return fmt.Errorf("error cleaning %s: %w", candidate.ID, err)
+	}
+	return nil
+}
+
+// file is a file found while scanning
+type file struct {
+	path string
+	size uint64
+}
+
+// staleFiles finds the regular files under a directory that haven't been modified recently
+// Directories that can't be read are skipped
+//
+// Parameters:
+//   - root: directory to scan
+//   - minSize: minimum file size in bytes
+//   - minAge: minimum time since the last modification
+//   - match: optional filter on the file path (nil accepts every file)
+//
+// Returns: matching files, largest first
+func staleFiles(root string, minSize uint64, minAge time.Duration, match func(path string) bool) []file {
+	cutoff := time.Now().Add(-minAge)
+
+	var files []file
+	_ = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if entry != nil && entry.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() || (match != nil && !match(path)) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil || uint64(info.Size()) < minSize || info.ModTime().After(cutoff) {
+			return nil
+		}
+		files = append(files, file{path: path, size: uint64(info.Size())})
+		return nil
+	})
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].size > files[j].size
+	})
+	return files
+}
+
+// invokingUID returns the user that ran gom, looking through sudo
+//
+// Returns: SUDO_UID when running under sudo, the real user ID otherwise
+func invokingUID() uint32 {
+	if sudoUID, err := strconv.ParseUint(os.Getenv("SUDO_UID"), 10, 32); err == nil && os.Geteuid() == 0 {
+		return uint32(sudoUID)
+	}
+	return uint32(os.Getuid())
+}
+
+// ownedBy reports whether a file belongs to a user (without following symlinks)
+func ownedBy(path string, uid uint32) bool {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return false
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	return ok && stat.Uid == uid
+}
+
+// dirSize sums the size of every regular file under a directory
+func dirSize(root string) uint64 {
+	return totalSize(staleFiles(root, 0, 0, nil))
+}
+
+// totalSize sums the size of the files
+func totalSize(files []file) uint64 {
+	var total uint64
+	for _, f := range files {
+		total += f.size
+	}
+	return total
+}
+
+// describeFiles summarizes files for the report (e.g. "delete 3 file(s), largest: /tmp/a.iso (1.20 GB)")
+func describeFiles(files []file) string {
+	if len(files) == 0 {
+		return "no files"
+	}
+	return fmt.Sprintf("delete %d file(s), largest: %s (%s)",
+		len(files), files[0].path, common.FormatBytes(files[0].size))
+}
+
+// removeFiles deletes files, continuing after errors
+//
+// Returns: the first error (if any)
+func removeFiles(files []file) error {
+	var firstErr error
+	for _, f := range files {
+		if err := os.Remove(f.path); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// run runs a cleanup command, showing its output
+func run(command ...string) error {
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	started := time.Now()
+	err := cmd.Run()
+	common.LogCommand(cmd, started, err)
+	return err
+}
+
+// PrintCandidates prints the reclaimable space report in a formatted table
+//
+// Parameters:
+//   - candidates: slice of Candidate to present (from Scan)
+func PrintCandidates(candidates []Candidate) {
+	var total uint64
+	for _, candidate := range candidates {
+		total += candidate.Bytes
+	}
+
+	box := common.NewBox("Reclaimable Disk Space")
+
+	if len(candidates) == 0 {
+		box.Text("Nothing to clean")
+	}
+
+	for i, candidate := range candidates {
+		if i > 0 {
+			box.Divider()
+		}
+		box.Text(fmt.Sprintf("%-15s %-10s %s", candidate.ID, common.FormatBytes(candidate.Bytes), candidate.Description))
+		box.Text(fmt.Sprintf("%-26s %s", "", candidate.Action))
+	}
+
+	box.Divider()
+	box.Field("Total", common.FormatBytes(total))
+	box.End()
+}
@@ -0,0 +1,107 @@
+package clipboard
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+)
+
+// ErrNoTool is returned by Copy when no clipboard program can be used
+var ErrNoTool = errors.New("no clipboard program found")
+
+// copyTimeout bounds a clipboard program, which may hang without a display to talk to
+const copyTimeout = 5 * time.Second
+
+// tool is a program that copies its standard input to the clipboard
+type tool struct {
+	name    string   // Executable name
+	args    []string // Arguments selecting the clipboard (not the primary selection)
+	display string   // Environment variable the session must set for it to work (empty = none)
+}
+
+// tools lists the clipboard programs in the order they are tried
+// Wayland comes first, since XWayland sessions set DISPLAY as well
+var tools = []tool{
+	{name: "wl-copy", display: "WAYLAND_DISPLAY"},
+	{name: "xclip", args: []string{"-selection", "clipboard"}, display: "DISPLAY"},
+	{name: "xsel", args: []string{"--clipboard", "--input"}, display: "DISPLAY"},
+	{name: "pbcopy"},
+}
+
+// Copy puts text on the clipboard with the first program that fits the session
+// Over SSH without X forwarding none of them can reach a clipboard
+//
+// Parameters:
+//   - text: text to copy
+//
+// Returns:
+//   - name of the program used (e.g. "xclip")
+//   - ErrNoTool if none is installed for the session, or the error of the program
+func Copy(text string) (string, error) {
+	for _, t := range tools {
+		if t.display != "" && os.Getenv(t.display) == "" {
+			continue
+		}
+		if _, err := exec.LookPath(t.name); err != nil {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), copyTimeout)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, t.name, t.args...)
+		cmd.Stdin = strings.NewReader(text)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		started := time.Now()
+		err := cmd.Run()
+		common.LogCommand(cmd, started, err)
+		if err != nil {
+			if message := strings.TrimSpace(stderr.String()); message != "" {
+				return t.name, fmt.Errorf("error running %s: %s", t.name, message)
+			}
+			return t.name, fmt.Errorf("error running %s: %w", t.name, err)
+		}
+		return t.name, nil
+	}
+	return "", ErrNoTool
+}
+
+// WriteFile writes text to a new file in the temporary directory, for when there's no clipboard
+// The file gets a random name, so a file or symlink planted in the shared directory
+// (with the predictable name) can't be overwritten, even when gom runs as root
+//
+// Parameters:
+//   - pattern: file name, whose last "*" is replaced by a random string (e.g. "gom-process-1234-*.txt")
+//   - text: file content
+//
+// Returns:
+//   - path of the file that was created
+//   - error if writing files is disabled (--read-only) or the file can't be written
+func WriteFile(pattern, text string) (string, error) {
+	if paths.ReadOnly() {
+		return "", errors.New("writing files is disabled (read-only)")
+	}
+	file, err := os.CreateTemp("", pattern) // Created with O_EXCL and mode 0600
+	if err != nil {
+		return "", fmt.Errorf("error creating a file in %s: %w", os.TempDir(), err)
+	}
+	path := file.Name()
+	if _, err := file.WriteString(text); err != nil {
+		file.Close()
+		os.Remove(path)
+		return "", fmt.Errorf("error writing %s: %w", path, err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(path)
+		return "", fmt.Errorf("error writing %s: %w", path, err)
+	}
+	return path, nil
+}
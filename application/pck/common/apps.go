@@ -0,0 +1,172 @@
+package common
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// maxAppDepth bounds the walk up the process tree (parent links are read at different
+// times, so a reused PID could make a loop)
+const maxAppDepth = 64
+
+// Application is a group of processes of one program (e.g. a browser and all its renderers)
+type Application struct {
+	ProcessInfo         // Main process, with the CPU, RAM and threads of every process of the group
+	Count       int     // Number of processes in the group
+	PIDs        []int32 // Processes in the group (the main process first)
+}
+
+// GroupByApplication aggregates processes into one entry per application
+// A process belongs to the application of its parent when both have the same owner and
+// either the same name or the same process group (helpers a program starts without a
+// session of their own), unless the parent leads the session, like a shell.
+// Applications with the same name and owner are merged, so two windows of one program count once
+//
+// Parameters:
+//   - processes: processes to group (not modified)
+//
+// Returns: applications sorted by CPU usage (descending)
+func GroupByApplication(processes []ProcessInfo) []Application {
+	byPID := make(map[int32]*ProcessInfo, len(processes))
+	for i := range processes {
+		byPID[processes[i].PID] = &processes[i]
+	}
+
+	// 1. Find the top process of the application of each process
+	roots := make(map[int32]*ProcessInfo, len(processes))
+	var rootOf func(p *ProcessInfo, depth int) *ProcessInfo
+	rootOf = func(p *ProcessInfo, depth int) *ProcessInfo {
+		if root, ok := roots[p.PID]; ok {
+			return root
+		}
+		root := p
+		if parent, ok := byPID[p.PPID]; ok && parent != p && depth < maxAppDepth && sameApplication(p, parent) {
+			root = rootOf(parent, depth+1)
+		}
+		roots[p.PID] = root
+		return root
+	}
+
+	// 2. Add every process to the application of its top process, named after it
+	type appKey struct{ name, user string }
+	index := make(map[appKey]int)
+	var apps []Application
+	for i := range processes {
+		p := &processes[i]
+		root := rootOf(p, 0)
+		key := appKey{root.Name, root.Username}
+		n, ok := index[key]
+		if !ok {
+			n = len(apps)
+			index[key] = n
+			app := Application{ProcessInfo: *root}
+			app.CPUPercentage, app.RAMPercentage, app.RAMBytes, app.NumThreads, app.CPUTime = 0, 0, 0, 0, 0
+			apps = append(apps, app)
+		}
+
+		app := &apps[n]
+		app.Count++
+		app.CPUPercentage += p.CPUPercentage
+		app.RAMPercentage += p.RAMPercentage
+		app.RAMBytes += p.RAMBytes
+		app.NumThreads += p.NumThreads
+		app.CPUTime += p.CPUTime
+		app.PIDs = append(app.PIDs, p.PID)
+
+		// The oldest top process is the main one (e.g. the first window of a browser)
+		if root == p && p.PID != app.PID && p.CreateTime > 0 && p.CreateTime < app.CreateTime {
+			app.PID, app.CreateTime, app.State, app.Nice, app.PPID, app.PGID, app.SID = p.PID, p.CreateTime, p.State, p.Nice, p.PPID, p.PGID, p.SID
+		}
+	}
+
+	// 3. Put the main process first, then sort by CPU usage
+	for i := range apps {
+		app := &apps[i]
+		for j, pid := range app.PIDs {
+			if pid == app.PID {
+				app.PIDs[0], app.PIDs[j] = app.PIDs[j], app.PIDs[0]
+				break
+			}
+		}
+	}
+	sort.SliceStable(apps, func(i, j int) bool {
+		return apps[i].CPUPercentage > apps[j].CPUPercentage
+	})
+	return apps
+}
+
+// sameApplication checks if a process belongs to the application of its parent
+func sameApplication(p, parent *ProcessInfo) bool {
+	if p.Username != parent.Username || parent.PID == kthreaddPID {
+		return false
+	}
+	if p.Name == parent.Name {
+		return true
+	}
+	return p.PGID > 0 && p.PGID == parent.PGID && parent.PID != parent.SID
+}
+
+// PrintApplicationTable prints applications in a formatted table
+// The PID is the one of the main process of each application
+//
+// Parameters:
+//   - apps: applications to print (already sorted)
+//   - maxApps: maximum number of applications to show (0 = all)
+//   - title: title shown above the table
+func PrintApplicationTable(apps []Application, maxApps int, title string) {
+	if maxApps > 0 && maxApps < len(apps) {
+		apps = apps[:maxApps]
+	}
+
+	table := NewTable(title,
+		Column{Header: "PID"}, Column{Header: "User"}, Column{Header: "Name", Fill: true},
+		Column{Header: "Procs", Right: true, Optional: true}, Column{Header: "CPU %", Right: true},
+		Column{Header: "RAM %", Right: true, Optional: true}, Column{Header: "RAM", Right: true})
+	for _, app := range apps {
+		table.AddRow(strconv.Itoa(int(app.PID)), TruncateString(app.Username, 10), TruncateString(app.Name, 17),
+			strconv.Itoa(app.Count), fmt.Sprintf("%.2f%%", app.CPUPercentage),
+			fmt.Sprintf("%.2f%%", app.RAMPercentage), FormatBytes(app.RAMBytes))
+	}
+	table.Print()
+}
+
+// WriteApplicationCSV writes a list of applications as CSV (with a header row)
+//
+// Parameters:
+//   - w: destination (file or stdout)
+//   - apps: applications to write
+//   - maxApps: maximum number of applications to write (0 = all)
+//
+// Returns: error if writing fails
+func WriteApplicationCSV(w io.Writer, apps []Application, maxApps int) error {
+	if maxApps > 0 && maxApps < len(apps) {
+		apps = apps[:maxApps]
+	}
+
+	writer := csv.NewWriter(w)
+	header := []string{"pid", "user", "name", "processes", "cpu_percent", "ram_percent", "ram_bytes"}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("error writing CSV header: %w", err)
+	}
+
+	for _, app := range apps {
+		record := []string{
+			strconv.Itoa(int(app.PID)),
+			app.Username,
+			app.Name,
+			strconv.Itoa(app.Count),
+			strconv.FormatFloat(app.CPUPercentage, 'f', 2, 64),
+			strconv.FormatFloat(float64(app.RAMPercentage), 'f', 2, 32),
+			strconv.FormatUint(app.RAMBytes, 10),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("error writing CSV record: %w", err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,41 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// CollectTimeout is the shared deadline of the collections of a view (see Async), so a hung
+// nvidia-smi or disk doesn't keep the other sections from being shown
+const CollectTimeout = 10 * time.Second
+
+// Async starts a collection in the background and returns a function that waits for its result
+// Views that measure over an interval (CPU usage, process CPU, disk I/O) start all their
+// collections first, so they are measured at the same time instead of one after the other
+//
+// Parameters:
+//   - ctx: shared deadline of the collections; the wait gives up when it is done
+//   - collect: function that collects the value
+//
+// Returns:
+//   - function returning the value and error of collect, or an error if ctx is done first
+func Async[T any](ctx context.Context, collect func() (T, error)) func() (T, error) {
+	done := make(chan struct{})
+	var value T
+	var err error
+	go func() {
+		value, err = collect()
+		close(done)
+	}()
+
+	return func() (T, error) {
+		select {
+		case <-done:
+			return value, err
+		case <-ctx.Done():
+			var zero T
+			return zero, fmt.Errorf("collection timed out: %w", ctx.Err())
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package common
+
+import "fmt"
+
+// Collection backends of the process, memory, CPU and disk I/O statistics
+const (
+	BackendGopsutil = "gopsutil" // gopsutil, which also works on other operating systems (default)
+	BackendProcfs   = "procfs"   // Reads /proc directly, for minimal containers where gopsutil misbehaves
+)
+
+// backend is the active collection backend (defaultBackend depends on the "procfs" build tag)
+var backend = defaultBackend
+
+// SetBackend selects the collection backend
+//
+// Parameters:
+//   - name: BackendGopsutil or BackendProcfs (empty keeps the build default)
+//
+// Returns: error if the backend is not supported (nothing is changed)
+func SetBackend(name string) error {
+	if err := ValidateBackend(name); err != nil {
+		return err
+	}
+	if name != "" {
+		backend = name
+	}
+	return nil
+}
+
+// ValidateBackend checks a backend name (empty is valid)
+func ValidateBackend(name string) error {
+	switch name {
+	case "", BackendGopsutil, BackendProcfs:
+		return nil
+	}
+	return fmt.Errorf("backend must be %q or %q, got %q", BackendGopsutil, BackendProcfs, name)
+}
+
+// Backend returns the name of the active collection backend
+func Backend() string {
+	return backend
+}
+
+// UsingProcfs checks if statistics are read from /proc directly instead of through gopsutil
+func UsingProcfs() bool {
+	return backend == BackendProcfs
+}
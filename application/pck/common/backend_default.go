@@ -0,0 +1,6 @@
+//go:build !procfs
+
+package common
+
+// defaultBackend is the collection backend used unless the config file or GOM_BACKEND select another
+const defaultBackend = BackendGopsutil
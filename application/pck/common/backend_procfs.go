@@ -0,0 +1,7 @@
+//go:build procfs
+
+package common
+
+// defaultBackend is the collection backend used unless the config file or GOM_BACKEND select another
+// Built with "-tags procfs" for minimal containers and embedded systems
+const defaultBackend = BackendProcfs
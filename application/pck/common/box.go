@@ -0,0 +1,129 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// boxLabelWidth is the width of the label column of a box ("Label:" and the spaces after it)
+const boxLabelWidth = 17
+
+// boxRow is a row of a Box, drawn by End
+type boxRow struct {
+	kind  boxRowKind
+	label string
+	text  string
+}
+
+// boxRowKind selects how a row of a Box is drawn
+type boxRowKind int
+
+const (
+	boxText      boxRowKind = iota // Text across the whole box
+	boxField                       // "Label: value" row
+	boxSeparator                   // Double line (╠═╣), between sections
+	boxDivider                     // Single line (╟─╢), between entries of a section
+)
+
+// Box draws information as "Label: value" rows in a box with a title, like the CPU and RAM views
+// The box is the usual width of the tables; on narrower terminals it shrinks to fit, with the
+// label column as wide as the longest label and long values cut
+type Box struct {
+	w     io.Writer
+	title string
+	rows  []boxRow
+}
+
+// NewBox creates a box that is drawn on stdout by End
+//
+// Parameters:
+//   - title: title shown above the rows
+//
+// Returns: pointer to an empty Box
+func NewBox(title string) *Box {
+	return NewBoxTo(os.Stdout, title)
+}
+
+// NewBoxTo creates a box that is drawn on a writer by End (e.g. os.Stderr)
+func NewBoxTo(w io.Writer, title string) *Box {
+	return &Box{w: w, title: title}
+}
+
+// Field adds a "Label: value" row
+// An empty label continues the value of the row above, aligned with it
+func (b *Box) Field(label, value string) {
+	b.rows = append(b.rows, boxRow{kind: boxField, label: label, text: value})
+}
+
+// Fieldf adds a "Label: value" row with a formatted value
+func (b *Box) Fieldf(label, format string, args ...any) {
+	b.Field(label, fmt.Sprintf(format, args...))
+}
+
+// Text adds a row of text across the whole box (e.g. a message or a subtitle)
+func (b *Box) Text(text string) {
+	b.rows = append(b.rows, boxRow{kind: boxText, text: text})
+}
+
+// Separator adds a double line, to start a new section
+func (b *Box) Separator() {
+	b.rows = append(b.rows, boxRow{kind: boxSeparator})
+}
+
+// Divider adds a single line, between the entries of a section
+func (b *Box) Divider() {
+	b.rows = append(b.rows, boxRow{kind: boxDivider})
+}
+
+// End draws the box with all its rows
+func (b *Box) End() {
+	width := outputWidth(b.w) - 2 // Between the vertical borders
+	text := width - 4             // Without the padding inside the borders
+
+	// The label column is the usual width (wider for long labels), or only as wide as the
+	// labels when compact
+	longest := 0
+	for _, row := range b.rows {
+		if row.kind == boxField {
+			longest = max(longest, DisplayWidth(row.label)+2) // With ": "
+		}
+	}
+	labelWidth := max(boxLabelWidth, longest)
+	if width+2 < minTableWidth {
+		labelWidth = longest
+	}
+	labelWidth = min(labelWidth, text/2)
+
+	line := func(s string) {
+		fmt.Fprintf(b.w, "║  %s  ║\n", FitString(s, text))
+	}
+	border := func(left, fill, right string) {
+		fmt.Fprintln(b.w, left+strings.Repeat(fill, width)+right)
+	}
+
+	fmt.Fprintln(b.w)
+	border("╔", "═", "╗")
+	line(b.title)
+	if len(b.rows) > 0 && b.rows[0].kind != boxSeparator {
+		border("╠", "═", "╣")
+	}
+	for _, row := range b.rows {
+		switch row.kind {
+		case boxText:
+			line(row.text)
+		case boxField:
+			label := ""
+			if row.label != "" {
+				label = row.label + ":"
+			}
+			line(FitString(label, labelWidth) + row.text)
+		case boxSeparator:
+			border("╠", "═", "╣")
+		case boxDivider:
+			border("╟", "─", "╢")
+		}
+	}
+	border("╚", "═", "╝")
+}
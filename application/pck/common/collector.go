@@ -0,0 +1,138 @@
+package common
+
+import (
+	"sync"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// ProcessCollector collects process information repeatedly, reusing Process objects
+// between collections. gopsutil keeps the previous CPU times and static data (start
+// time) inside each Process, so reusing them gives incremental CPU usage since the
+// previous collection and avoids re-reading data that never changes
+type ProcessCollector struct {
+	mu        sync.Mutex                 // Serializes collections
+	processes map[int32]*process.Process // Cached processes, keyed by PID
+	samples   map[int32]procfsSample     // CPU times of the previous collection (procfs backend)
+}
+
+// NewProcessCollector creates a collector with an empty cache
+// Used by long-running views such as the TUI refresh loop
+//
+// Returns: pointer to a configured ProcessCollector
+func NewProcessCollector() *ProcessCollector {
+	return &ProcessCollector{
+		processes: make(map[int32]*process.Process),
+	}
+}
+
+// Collect collects all active processes
+// Processes seen in the previous collection report their CPU usage since then;
+// new processes report their lifetime average until the next collection
+//
+// Returns:
+//   - slice of ProcessInfo with all valid processes
+//   - CollectionStats with the number of shown and skipped processes
+//   - error (if any)
+func (c *ProcessCollector) Collect() ([]ProcessInfo, CollectionStats, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The procfs backend keeps the CPU times of the previous collection instead of Process objects
+	if UsingProcfs() {
+		processes, samples, stats, err := collectProcfs(c.samples)
+		if err != nil {
+			return nil, stats, err
+		}
+		c.samples = samples
+		c.logSkipped(stats)
+		return processes, stats, nil
+	}
+
+	var stats CollectionStats
+
+	// 1. Get total system memory
+	totalSystemMem, err := GetProcessMemoryTotal()
+	if err != nil {
+		return nil, stats, err
+	}
+
+	// 2. Get the PIDs of all active processes
+	pids, err := process.Pids()
+	if err != nil {
+		return nil, stats, err
+	}
+
+	processInfoList := make([]ProcessInfo, 0, len(pids))
+	seen := make(map[int32]*process.Process, len(pids))
+
+	// 3. Collect each process, reusing the cached object if it's the same process
+	for _, pid := range pids {
+		p, cpuPercent := c.lookup(pid)
+		if p == nil {
+			continue // Terminated before it could be read
+		}
+
+		info, err := getProcessInfo(p, totalSystemMem, cpuPercent)
+		if err != nil {
+			if isProcessGone(err) {
+				continue
+			}
+			stats.Skip(err)
+			continue
+		}
+
+		// The cached name is stale if the process called exec or renamed itself
+		if p == c.processes[pid] {
+			if name, err := (&process.Process{Pid: pid}).Name(); err == nil {
+				info.Name = name
+			}
+		}
+
+		seen[pid] = p
+		processInfoList = append(processInfoList, *info)
+	}
+	stats.Shown = len(processInfoList)
+
+	// 4. Forget processes that terminated (or couldn't be read)
+	c.processes = seen
+
+	c.logSkipped(stats)
+	return processInfoList, stats, nil
+}
+
+// logSkipped logs the details of the skipped processes (at most once per interval)
+func (c *ProcessCollector) logSkipped(stats CollectionStats) {
+	if stats.Skipped > 0 {
+		DebugEvery("skipped-processes", skippedLogInterval,
+			"process collection: %s; first errors: %v", stats, stats.Errors)
+	}
+}
+
+// lookup returns the Process for a PID and how its CPU usage should be measured
+// A cached object is only reused if the PID still belongs to the same process
+// (same start time), otherwise a PID reused by a new process would inherit old CPU times
+//
+// Returns:
+//   - pointer to the process (nil if it no longer exists)
+//   - function returning its CPU usage
+func (c *ProcessCollector) lookup(pid int32) (*process.Process, func() (float64, error)) {
+	if cached, ok := c.processes[pid]; ok {
+		if running, err := cached.IsRunning(); err == nil && running {
+			// Incremental usage since the previous collection
+			return cached, func() (float64, error) { return cached.Percent(0) }
+		}
+	}
+
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		return nil, nil
+	}
+
+	// First collection of this process: report the lifetime average and record
+	// the CPU times, so the next collection can report incremental usage
+	return p, func() (float64, error) {
+		_, _ = p.Percent(0)
+		return p.CPUPercent()
+	}
+}
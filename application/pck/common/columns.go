@@ -0,0 +1,210 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ProcessColumn is a column of the process listings, shared by the tables and the CSV output
+// so both show the same columns (e.g. the ones picked with --columns)
+type ProcessColumn struct {
+	Name   string                     // Name used by --columns (e.g. "cpu")
+	Column Column                     // Title and alignment in tables
+	CSV    string                     // Header of the CSV column (e.g. "cpu_percent")
+	Width  int                        // Longest value shown in tables, longer values are cut (0 = no limit)
+	cell   func(p ProcessInfo) string // Value shown in tables
+	raw    func(p ProcessInfo) string // Value written to CSV (unrounded, in bytes and seconds)
+}
+
+// Cell returns the value of the column for a process, as shown in tables
+func (c ProcessColumn) Cell(p ProcessInfo) string {
+	value := c.cell(p)
+	if c.Width > 0 {
+		return TruncateString(value, c.Width)
+	}
+	return value
+}
+
+// Raw returns the value of the column for a process, as written to CSV
+func (c ProcessColumn) Raw(p ProcessInfo) string {
+	return c.raw(p)
+}
+
+// processColumns lists every process column in the order of the help
+var processColumns = []ProcessColumn{
+	{Name: "pid", Column: Column{Header: "PID"}, CSV: "pid",
+		cell: func(p ProcessInfo) string { return strconv.Itoa(int(p.PID)) },
+		raw:  func(p ProcessInfo) string { return strconv.Itoa(int(p.PID)) }},
+	{Name: "user", Column: Column{Header: "User"}, CSV: "user", Width: 10,
+		cell: func(p ProcessInfo) string { return p.Username },
+		raw:  func(p ProcessInfo) string { return p.Username }},
+	{Name: "name", Column: Column{Header: "Name", Fill: true}, CSV: "name",
+		cell: func(p ProcessInfo) string { return p.Name },
+		raw:  func(p ProcessInfo) string { return p.Name }},
+	{Name: "pgid", Column: Column{Header: "PGID", Right: true, Optional: true}, CSV: "pgid",
+		cell: func(p ProcessInfo) string { return FormatID(p.PGID) },
+		raw:  func(p ProcessInfo) string { return strconv.Itoa(int(p.PGID)) }},
+	{Name: "sid", Column: Column{Header: "SID", Right: true, Optional: true}, CSV: "sid",
+		cell: func(p ProcessInfo) string { return FormatID(p.SID) },
+		raw:  func(p ProcessInfo) string { return strconv.Itoa(int(p.SID)) }},
+	{Name: "core", Column: Column{Header: "Core", Right: true, Optional: true}, CSV: "core",
+		cell: func(p ProcessInfo) string { return FormatCore(p.LastCPU) },
+		raw:  func(p ProcessInfo) string { return strconv.Itoa(p.LastCPU) }},
+	{Name: "cpu", Column: Column{Header: "CPU %", Right: true}, CSV: "cpu_percent",
+		cell: func(p ProcessInfo) string { return fmt.Sprintf("%.2f%%", p.CPUPercentage) },
+		raw:  func(p ProcessInfo) string { return strconv.FormatFloat(p.CPUPercentage, 'f', 2, 64) }},
+	{Name: "ram", Column: Column{Header: "RAM %", Right: true, Optional: true}, CSV: "ram_percent",
+		cell: func(p ProcessInfo) string { return fmt.Sprintf("%.2f%%", p.RAMPercentage) },
+		raw:  func(p ProcessInfo) string { return strconv.FormatFloat(float64(p.RAMPercentage), 'f', 2, 32) }},
+	{Name: "rss", Column: Column{Header: "RAM", Right: true}, CSV: "ram_bytes",
+		cell: func(p ProcessInfo) string { return FormatBytes(p.RAMBytes) },
+		raw:  func(p ProcessInfo) string { return strconv.FormatUint(p.RAMBytes, 10) }},
+	{Name: "time", Column: Column{Header: "CPU Time", Short: "Time", Right: true}, CSV: "cpu_time_seconds",
+		cell: func(p ProcessInfo) string { return FormatCPUTime(p.CPUTime) },
+		raw:  func(p ProcessInfo) string { return strconv.FormatFloat(p.CPUTime, 'f', 2, 64) }},
+	{Name: "threads", Column: Column{Header: "Threads", Short: "Thr", Right: true, Optional: true}, CSV: "threads",
+		cell: func(p ProcessInfo) string { return strconv.Itoa(int(p.NumThreads)) },
+		raw:  func(p ProcessInfo) string { return strconv.Itoa(int(p.NumThreads)) }},
+	{Name: "io", Column: Column{Header: "Disk I/O", Short: "I/O", Right: true}, CSV: "io_bytes_per_second",
+		cell: func(p ProcessInfo) string { return FormatBytes(uint64(p.DiskIO)) + "/s" },
+		raw:  func(p ProcessInfo) string { return strconv.FormatFloat(p.DiskIO, 'f', 0, 64) }},
+	{Name: "cmd", Column: Column{Header: "Command", Short: "Cmd", Fill: true}, CSV: "cmdline", Width: 200,
+		cell: DescribeCmdline,
+		raw:  func(p ProcessInfo) string { return GetCmdline(p.PID) }},
+}
+
+// ProcessColumnNames returns the names accepted by ParseProcessColumns, separated by commas
+func ProcessColumnNames() string {
+	names := make([]string, len(processColumns))
+	for i, c := range processColumns {
+		names[i] = c.Name
+	}
+	return strings.Join(names, ",")
+}
+
+// processColumn looks up a column by name
+func processColumn(name string) (ProcessColumn, bool) {
+	for _, c := range processColumns {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return ProcessColumn{}, false
+}
+
+// ParseProcessColumns parses a list of column names separated by commas (e.g. "pid,name,cpu")
+// The columns keep the given order; with "cmd" the command line takes the spare width instead
+// of the name
+//
+// Parameters:
+//   - spec: column names (see ProcessColumnNames)
+//
+// Returns:
+//   - the columns
+//   - error if a name is unknown or given twice, or the list is empty
+func ParseProcessColumns(spec string) ([]ProcessColumn, error) {
+	var columns []ProcessColumn
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		column, ok := processColumn(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown column '%s' (%s)", name, ProcessColumnNames())
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("column '%s' is given twice", name)
+		}
+		seen[name] = true
+		columns = append(columns, column)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no columns given (%s)", ProcessColumnNames())
+	}
+
+	return withCmdlineFill(columns), nil
+}
+
+// withCmdlineFill lets the command line take the spare width of the table instead of the name,
+// since only one column can and a command line needs it more
+func withCmdlineFill(columns []ProcessColumn) []ProcessColumn {
+	for _, c := range columns {
+		if c.Name == "cmd" {
+			for i := range columns {
+				if columns[i].Name == "name" {
+					columns[i].Column.Fill = false
+				}
+			}
+			break
+		}
+	}
+	return columns
+}
+
+// TableColumns returns the columns of the process tables
+// Without --columns, these are the usual columns plus the ones turned on by the options
+func (o TableOptions) TableColumns() []ProcessColumn {
+	if o.Columns != nil {
+		return o.Columns
+	}
+	names := []string{"pid", "user", "name"}
+	if o.ShowGroups {
+		names = append(names, "pgid", "sid")
+	}
+	if o.ShowCore {
+		names = append(names, "core")
+	}
+	names = append(names, "cpu", "ram", "rss")
+	if o.ShowCPUTime {
+		names = append(names, "time")
+	}
+	if o.ShowCmdline {
+		names = append(names, "cmd")
+	}
+
+	return withCmdlineFill(mustProcessColumns(names))
+}
+
+// CSVColumns returns the columns of the process CSV output
+// Without --columns, the optional ones come after the usual ones so scripts can rely on their position
+func (o TableOptions) CSVColumns() []ProcessColumn {
+	if o.Columns != nil {
+		return o.Columns
+	}
+	names := []string{"pid", "user", "name", "cpu", "ram", "rss"}
+	if o.ShowCore {
+		names = append(names, "core")
+	}
+	if o.ShowGroups {
+		names = append(names, "pgid", "sid")
+	}
+	if o.ShowCPUTime {
+		names = append(names, "time")
+	}
+	if o.ShowCmdline {
+		names = append(names, "cmd")
+	}
+	return mustProcessColumns(names)
+}
+
+// HasColumn checks if a column is shown, in the table or in the CSV output (e.g. "io")
+func (o TableOptions) HasColumn(name string) bool {
+	for _, c := range o.TableColumns() {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// mustProcessColumns looks up columns by name (only used with names of processColumns)
+func mustProcessColumns(names []string) []ProcessColumn {
+	columns := make([]ProcessColumn, len(names))
+	for i, name := range names {
+		columns[i], _ = processColumn(name)
+	}
+	return columns
+}
@@ -0,0 +1,74 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// DefaultCommandTimeout is how long collectors wait for an external program (e.g. nvidia-smi)
+// A broken driver install can make nvidia-smi hang forever, which would freeze the views
+const DefaultCommandTimeout = 5 * time.Second
+
+// commandWaitDelay is how long the output of a killed program is waited for, since a child it
+// started may keep the pipe open
+const commandWaitDelay = 500 * time.Millisecond
+
+// ErrCommandTimeout is returned (wrapped) when an external program doesn't finish in time
+var ErrCommandTimeout = errors.New("timed out")
+
+// commandTimeout is the timeout of RunCommand ("command_timeout" setting)
+var commandTimeout = DefaultCommandTimeout
+
+// SetCommandTimeout changes how long RunCommand waits for an external program
+//
+// Parameters:
+//   - timeout: maximum run time (DefaultCommandTimeout if 0 or less)
+func SetCommandTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultCommandTimeout
+	}
+	commandTimeout = timeout
+}
+
+// CommandTimeout returns how long collectors wait for an external program or driver call
+func CommandTimeout() time.Duration {
+	return commandTimeout
+}
+
+// RunCommand runs an external program of a collector and returns its standard output
+// The program is killed when ctx is done or after the command timeout, whichever comes first,
+// and every run is logged (see LogCommand)
+//
+// Parameters:
+//   - ctx: context of the collection (e.g. the shared deadline of a view)
+//   - name: program to run (looked up in PATH)
+//   - args: arguments of the program
+//
+// Returns:
+//   - standard output of the program (also when it exits with an error)
+//   - error wrapping ErrCommandTimeout if it was killed, exec.ErrNotFound if it isn't
+//     installed, or the exit error
+func RunCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	runCtx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(runCtx, name, args...)
+	cmd.WaitDelay = commandWaitDelay
+	started := time.Now()
+	output, err := cmd.Output()
+	switch {
+	case err == nil || runCtx.Err() == nil:
+		// Finished, or failed on its own
+	case ctx.Err() != nil:
+		// The deadline of the whole collection came first
+		err = fmt.Errorf("%s %w: %w", filepath.Base(name), ErrCommandTimeout, ctx.Err())
+	default:
+		err = fmt.Errorf("%s %w after %s", filepath.Base(name), ErrCommandTimeout, commandTimeout)
+	}
+	LogCommand(cmd, started, err)
+	return output, err
+}
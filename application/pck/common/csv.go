@@ -0,0 +1,48 @@
+package common
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// WriteProcessCSV writes a list of processes as CSV (with a header row)
+// Values are written raw (bytes, unrounded percentages) so they are easy to use in spreadsheets
+//
+// Parameters:
+//   - w: destination (file or stdout)
+//   - processes: slice of ProcessInfo to write
+//   - maxProcesses: maximum number of processes to write (0 = all)
+//   - options: columns to include
+//
+// Returns: error if writing fails
+func WriteProcessCSV(w io.Writer, processes []ProcessInfo, maxProcesses int, options TableOptions) error {
+	// Limit to the requested number of processes
+	if maxProcesses > 0 && maxProcesses < len(processes) {
+		processes = processes[:maxProcesses]
+	}
+
+	writer := csv.NewWriter(w)
+
+	columns := options.CSVColumns()
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.CSV
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("error writing CSV header: %w", err)
+	}
+
+	for _, p := range processes {
+		record := make([]string, len(columns))
+		for i, c := range columns {
+			record[i] = c.Raw(p)
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("error writing CSV record: %w", err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
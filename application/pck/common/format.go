@@ -0,0 +1,74 @@
+package common
+
+import (
+	"fmt"
+	"time"
+)
+
+// Units of FormatBytes
+const (
+	UnitsBinary = "binary" // Powers of 1024 (KB, MB, GB), like free and top (default)
+	UnitsSI     = "si"     // Powers of 1000 (kB, MB, GB), like disk vendors and df -H
+)
+
+// Timestamp formats of FormatTimestamp
+const (
+	TimeFormat24h     = "24h"     // 15:04:05 (default)
+	TimeFormat12h     = "12h"     // 3:04:05 PM
+	TimeFormatRFC3339 = "rfc3339" // 2006-01-02T15:04:05+01:00, to correlate with logs
+)
+
+// Active formats (set once at startup from the config file)
+var (
+	byteUnits  = UnitsBinary
+	timeFormat = TimeFormat24h
+)
+
+// SetFormats selects the units of FormatBytes and the format of FormatTimestamp
+//
+// Parameters:
+//   - units: UnitsBinary or UnitsSI (empty keeps the default)
+//   - timestamps: TimeFormat24h, TimeFormat12h or TimeFormatRFC3339 (empty keeps the default)
+//
+// Returns: error if a value is not supported (nothing is changed)
+func SetFormats(units, timestamps string) error {
+	if err := ValidateFormats(units, timestamps); err != nil {
+		return err
+	}
+	if units != "" {
+		byteUnits = units
+	}
+	if timestamps != "" {
+		timeFormat = timestamps
+	}
+	return nil
+}
+
+// ValidateFormats checks the units and timestamp format names (empty values are valid)
+func ValidateFormats(units, timestamps string) error {
+	switch units {
+	case "", UnitsBinary, UnitsSI:
+	default:
+		return fmt.Errorf("units must be %q or %q, got %q", UnitsBinary, UnitsSI, units)
+	}
+
+	switch timestamps {
+	case "", TimeFormat24h, TimeFormat12h, TimeFormatRFC3339:
+	default:
+		return fmt.Errorf("time_format must be %q, %q or %q, got %q", TimeFormat24h, TimeFormat12h, TimeFormatRFC3339, timestamps)
+	}
+	return nil
+}
+
+// FormatTimestamp formats the time of a sample in the selected timestamp format
+// RFC 3339 includes the date and the UTC offset, so lines can be matched against logs
+func FormatTimestamp(t time.Time) string {
+	switch timeFormat {
+	case TimeFormat12h:
+		return t.Format("3:04:05 PM")
+	case TimeFormatRFC3339:
+		return t.Format(time.RFC3339)
+	default:
+		return t.Format("15:04:05")
+	}
+}
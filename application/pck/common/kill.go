@@ -0,0 +1,115 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// KillCriteria selects the processes to terminate by name, pattern, age and resource usage
+// A process must meet every criterion that is set
+type KillCriteria struct {
+	Matcher       ProcessMatcher // Name and pattern (the zero value matches any process)
+	OlderThan     time.Duration  // Minimum running time (0 = any)
+	CPUAbove      float64        // Minimum CPU usage in % (0 = any)
+	RAMAbove      float64        // Minimum RAM usage in % (0 = any)
+	RAMBytesAbove uint64         // Minimum resident memory in bytes (0 = any)
+}
+
+// Empty checks if no criterion is set, which would select every process
+func (c KillCriteria) Empty() bool {
+	return c.Matcher.Name == "" && c.Matcher.Pattern == nil && c.OlderThan <= 0 &&
+		c.CPUAbove <= 0 && c.RAMAbove <= 0 && c.RAMBytesAbove == 0
+}
+
+// String describes the selected processes (e.g. "named php-fpm, using more than 50% CPU")
+func (c KillCriteria) String() string {
+	var parts []string
+	if matcher := c.Matcher.String(); matcher != "" {
+		parts = append(parts, matcher)
+	}
+	switch {
+	case c.OlderThan >= time.Minute:
+		parts = append(parts, "running for more than "+FormatDuration(c.OlderThan))
+	case c.OlderThan > 0:
+		parts = append(parts, "running for more than "+c.OlderThan.String())
+	}
+	if c.CPUAbove > 0 {
+		parts = append(parts, fmt.Sprintf("using more than %g%% CPU", c.CPUAbove))
+	}
+	if c.RAMAbove > 0 {
+		parts = append(parts, fmt.Sprintf("using more than %g%% RAM", c.RAMAbove))
+	}
+	if c.RAMBytesAbove > 0 {
+		parts = append(parts, "using more than "+FormatBytes(c.RAMBytesAbove)+" of RAM")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Match checks if a process meets every criterion
+// Processes without a start time never match an age criterion
+//
+// Parameters:
+//   - p: process to check
+//   - now: time the ages are measured at
+func (c KillCriteria) Match(p ProcessInfo, now time.Time) bool {
+	if c.OlderThan > 0 && (p.CreateTime <= 0 || now.Sub(time.UnixMilli(p.CreateTime)) < c.OlderThan) {
+		return false
+	}
+	if c.CPUAbove > 0 && p.CPUPercentage <= c.CPUAbove {
+		return false
+	}
+	if c.RAMAbove > 0 && float64(p.RAMPercentage) <= c.RAMAbove {
+		return false
+	}
+	if c.RAMBytesAbove > 0 && p.RAMBytes <= c.RAMBytesAbove {
+		return false
+	}
+	// The matcher goes last, since a pattern may read the command line
+	return c.Matcher.Match(p)
+}
+
+// SelectKillTargets returns the processes that meet the criteria
+// init (PID 1), gom itself and zombies (which already exited) are never selected
+//
+// Parameters:
+//   - processes: slice of ProcessInfo to select from
+//   - criteria: processes to select
+//
+// Returns: new slice with the selected processes (order is preserved)
+func SelectKillTargets(processes []ProcessInfo, criteria KillCriteria) []ProcessInfo {
+	self := int32(os.Getpid())
+	now := time.Now()
+
+	var targets []ProcessInfo
+	for _, p := range processes {
+		if p.PID <= 1 || p.PID == self || p.State == StateZombie {
+			continue
+		}
+		if criteria.Match(p, now) {
+			targets = append(targets, p)
+		}
+	}
+	return targets
+}
+
+// KillProcess kills a process, gracefully with SIGTERM (15) and with SIGKILL (9) if that fails
+//
+// Returns: error if neither signal could be sent (permission denied is explained)
+func KillProcess(pid int32) error {
+	process, err := os.FindProcess(int(pid))
+	if err != nil {
+		return err
+	}
+	if err := process.Signal(syscall.SIGTERM); err == nil {
+		return nil
+	}
+	err = process.Signal(syscall.SIGKILL)
+	if errors.Is(err, syscall.EPERM) {
+		return fmt.Errorf("permission denied (other users' processes need root)")
+	}
+	return err
+}
@@ -0,0 +1,217 @@
+package common
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// ProcessFlag marks unusual lifecycle behavior of a process
+type ProcessFlag int
+
+const (
+	FlagRenamed    ProcessFlag = 1 << iota // Changed its name recently (exec or prctl)
+	FlagRespawning                         // Restarted repeatedly under the same name (crash loop)
+)
+
+const (
+	RenameWindow     = time.Minute     // How long a renamed process stays flagged
+	RespawnWindow    = 2 * time.Minute // Time window in which respawns are counted
+	RespawnThreshold = 3               // Respawns within RespawnWindow to flag a crash loop
+)
+
+// kthreaddPID is the PID of kthreadd, the parent of every kernel thread on Linux
+const kthreaddPID = 2
+
+// processIdentity identifies a process instance
+// PIDs are reused by the kernel, so the start time is part of the identity
+type processIdentity struct {
+	pid        int32
+	createTime int64
+}
+
+// processInstance is what the tracker remembers of a process instance between samples
+type processInstance struct {
+	name string     // Name at the previous sample
+	key  respawnKey // Identity shared by the replacements of this instance
+	seen int        // Number of samples the instance was in
+}
+
+// respawnKey identifies the instances that replace each other: a supervisor (the parent)
+// starting the same command again. Unrelated short-lived commands that merely share a
+// name (sh, grep, git...) have other parents or arguments, so they don't count as respawns
+type respawnKey struct {
+	name    string // Process name
+	ppid    int32  // Parent PID (the supervisor, e.g. systemd or a shell loop)
+	cmdline string // Full command line
+}
+
+// Rename is a name change of a running process
+type Rename struct {
+	PID     int32     // Process ID
+	OldName string    // Name before the change
+	NewName string    // Name after the change
+	Time    time.Time // When the change was observed
+}
+
+// ProcessTracker follows process instances across samples to detect
+// processes that change their name and processes that keep respawning
+type ProcessTracker struct {
+	mu        sync.Mutex                          // Protects the fields below (read by the alert watcher)
+	instances map[processIdentity]processInstance // Instances of the previous sample
+	renames   map[processIdentity]Rename          // Latest rename of each instance (within RenameWindow)
+	exits     map[respawnKey][]time.Time          // Recent exit times of each key, not yet matched by a start
+	respawns  map[respawnKey][]time.Time          // Recent respawn times of each key (within RespawnWindow)
+	started   bool                                // False until the first sample was observed
+}
+
+// NewProcessTracker creates a tracker with no history
+//
+// Returns: pointer to a configured ProcessTracker
+func NewProcessTracker() *ProcessTracker {
+	return &ProcessTracker{
+		instances: make(map[processIdentity]processInstance),
+		renames:   make(map[processIdentity]Rename),
+		exits:     make(map[respawnKey][]time.Time),
+		respawns:  make(map[respawnKey][]time.Time),
+	}
+}
+
+// Observe compares a sample with the previous one and flags the processes
+// A process is renamed when the same (PID, start time) reports a different name;
+// it respawns when a new instance appears shortly after an instance with the same
+// name, parent and command line exited. Instances seen in a single sample are
+// short-lived commands, so their exits aren't counted
+//
+// Parameters:
+//   - processes: latest process sample; the Flags field of each process is updated
+//   - now: time of the sample
+func (t *ProcessTracker) Observe(processes []ProcessInfo, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	current := make(map[processIdentity]processInstance, len(processes))
+
+	// 1. Detect renames and new instances
+	for _, p := range processes {
+		id := processIdentity{pid: p.PID, createTime: p.CreateTime}
+		previous, known := t.instances[id]
+		if !known {
+			// The parent and command line are read once per instance
+			previous = processInstance{name: p.Name, key: respawnKeyOf(p)}
+			if t.started && len(t.exits[previous.key]) > 0 {
+				// A new instance replaces one that exited: consume the exit
+				t.exits[previous.key] = t.exits[previous.key][1:]
+				t.respawns[previous.key] = append(t.respawns[previous.key], now)
+			}
+		} else if previous.name != p.Name && previous.key.ppid != kthreaddPID && p.PID != kthreaddPID {
+			// Kernel worker threads (e.g. kworker) rename themselves constantly, so they are ignored
+			t.renames[id] = Rename{PID: p.PID, OldName: previous.name, NewName: p.Name, Time: now}
+		}
+		current[id] = processInstance{name: p.Name, key: previous.key, seen: previous.seen + 1}
+	}
+
+	// 2. Record the instances that exited after living through more than one sample
+	for id, instance := range t.instances {
+		if _, ok := current[id]; !ok {
+			if instance.seen > 1 {
+				t.exits[instance.key] = append(t.exits[instance.key], now)
+			}
+			delete(t.renames, id)
+		}
+	}
+	t.instances = current
+	t.started = true
+
+	// 3. Forget events older than their window
+	t.prune(now)
+
+	// 4. Flag the processes
+	for i := range processes {
+		id := processIdentity{pid: processes[i].PID, createTime: processes[i].CreateTime}
+		processes[i].Flags = 0
+		if _, ok := t.renames[id]; ok {
+			processes[i].Flags |= FlagRenamed
+		}
+		if len(t.respawns[current[id].key]) >= RespawnThreshold {
+			processes[i].Flags |= FlagRespawning
+		}
+	}
+}
+
+// prune removes renames, exits and respawns that are older than their window
+func (t *ProcessTracker) prune(now time.Time) {
+	for id, rename := range t.renames {
+		if now.Sub(rename.Time) > RenameWindow {
+			delete(t.renames, id)
+		}
+	}
+	for key, times := range t.exits {
+		if times = recentTimes(times, now, RespawnWindow); len(times) > 0 {
+			t.exits[key] = times
+		} else {
+			delete(t.exits, key)
+		}
+	}
+	for key, times := range t.respawns {
+		if times = recentTimes(times, now, RespawnWindow); len(times) > 0 {
+			t.respawns[key] = times
+		} else {
+			delete(t.respawns, key)
+		}
+	}
+}
+
+// respawnKeyOf reads the parent PID and command line of a process
+// Both are left empty if the process exited meanwhile
+func respawnKeyOf(p ProcessInfo) respawnKey {
+	key := respawnKey{name: p.Name, cmdline: GetCmdline(p.PID)}
+	if ppid, err := (&process.Process{Pid: p.PID}).Ppid(); err == nil {
+		key.ppid = ppid
+	}
+	return key
+}
+
+// recentTimes drops the times older than window (times are in ascending order)
+func recentTimes(times []time.Time, now time.Time, window time.Duration) []time.Time {
+	for len(times) > 0 && now.Sub(times[0]) > window {
+		times = times[1:]
+	}
+	return times
+}
+
+// Renames returns the processes renamed within RenameWindow
+//
+// Returns: slice of Rename (in no particular order)
+func (t *ProcessTracker) Renames() []Rename {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	renames := make([]Rename, 0, len(t.renames))
+	for _, rename := range t.renames {
+		renames = append(renames, rename)
+	}
+	return renames
+}
+
+// Respawns returns how many times each name respawned within RespawnWindow
+// When several commands with the same name respawn, the highest count is reported
+//
+// Returns: map of process name to respawn count
+func (t *ProcessTracker) Respawns() map[string]int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	respawns := make(map[string]int, len(t.respawns))
+	for key, times := range t.respawns {
+		respawns[key.name] = max(respawns[key.name], len(times))
+	}
+	return respawns
+}
+
+// String formats a rename (e.g. "PID 1234 bash → sleep")
+func (r Rename) String() string {
+	return fmt.Sprintf("PID %d %s → %s", r.PID, r.OldName, r.NewName)
+}
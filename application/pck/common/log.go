@@ -0,0 +1,160 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+)
+
+// debugEnvVar enables debug logging when set to a non-empty value (e.g. GOM_DEBUG=1)
+// Without --log-file, messages go to stderr, so the TUI can be debugged with "gom -f 2>debug.log"
+const debugEnvVar = "GOM_DEBUG"
+
+// defaultLogFile is the log file in the state directory, used by --debug when no --log-file is given
+const defaultLogFile = "gom.log"
+
+var (
+	// logger is the logger of every module; it discards everything until SetupLogging
+	// is given a log file or debug logging
+	logger = slog.New(slog.DiscardHandler)
+
+	// Last time each rate-limited message was logged, keyed by message key
+	debugLastLogged   = make(map[string]time.Time)
+	debugLastLoggedMu sync.Mutex
+)
+
+// SetupLogging sends the log of every module (collection failures, external commands,
+// TUI errors) to a file, so they are kept instead of swallowed or mixed into the output
+// Without a file or debug logging nothing is logged
+//
+// Parameters:
+//   - path: file the records are appended to (--log-file; empty for the default)
+//   - debug: also log debug records (--debug or GOM_DEBUG); without a path they go to
+//     gom.log in the state directory, or to stderr with GOM_DEBUG or --read-only
+//
+// Returns:
+//   - path of the log file (empty if the log goes to stderr or nowhere)
+//   - error if the file can't be opened
+func SetupLogging(path string, debug bool) (string, error) {
+	fromEnv := os.Getenv(debugEnvVar) != ""
+	debug = debug || fromEnv
+
+	level := slog.LevelInfo
+	if debug {
+		level = slog.LevelDebug
+	}
+
+	// 1. Choose where the records go
+	if path == "" {
+		if !debug {
+			return "", nil
+		}
+		if fromEnv || paths.ReadOnly() {
+			logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+			return "", nil
+		}
+		statePath, err := DefaultLogPath()
+		if err != nil {
+			return "", fmt.Errorf("error finding the log file: %w", err)
+		}
+		path = statePath
+	}
+
+	// 2. Append to the file, so the log of earlier runs is kept
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("error creating the log directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return "", fmt.Errorf("error opening the log file: %w", err)
+	}
+	logger = slog.New(slog.NewTextHandler(file, &slog.HandlerOptions{Level: level})).With("pid", os.Getpid())
+	return path, nil
+}
+
+// DefaultLogPath returns the log file of --debug when no --log-file is given (gom.log in the state directory)
+func DefaultLogPath() (string, error) {
+	return paths.StateFile(defaultLogFile)
+}
+
+// Logger returns the logger of every module, for structured records
+// e.g. common.Logger().Warn("error reading the load average", "err", err)
+func Logger() *slog.Logger {
+	return logger
+}
+
+// DebugEvery logs a debug message at most once per interval for the same key
+// Used for messages produced on every refresh, which would otherwise flood the log
+//
+// Parameters:
+//   - key: identifies the message (e.g. "skipped-processes")
+//   - interval: minimum time between two messages with the same key
+//   - format: printf-style format
+//   - args: format arguments
+func DebugEvery(key string, interval time.Duration, format string, args ...any) {
+	if !logger.Enabled(context.Background(), slog.LevelDebug) || !logDue(key, interval) {
+		return
+	}
+	logger.Debug(fmt.Sprintf(format, args...))
+}
+
+// WarnEvery logs a warning at most once per interval for the same key
+// Used for collection failures of the refresh loops (e.g. the TUI reading the load every 2s)
+//
+// Parameters:
+//   - key: identifies the message (e.g. "load")
+//   - interval: minimum time between two messages with the same key
+//   - msg: message of the record
+//   - args: attributes of the record, as key-value pairs
+func WarnEvery(key string, interval time.Duration, msg string, args ...any) {
+	if !logger.Enabled(context.Background(), slog.LevelWarn) || !logDue(key, interval) {
+		return
+	}
+	logger.Warn(msg, args...)
+}
+
+// logDue checks if a rate-limited message can be logged now, and records it as logged
+func logDue(key string, interval time.Duration) bool {
+	debugLastLoggedMu.Lock()
+	defer debugLastLoggedMu.Unlock()
+
+	now := time.Now()
+	if last, ok := debugLastLogged[key]; ok && now.Sub(last) < interval {
+		return false
+	}
+	debugLastLogged[key] = now
+	return true
+}
+
+// commandWarnInterval is how often the failures of one external program are logged, since
+// the refresh loops run the same programs every few seconds
+const commandWarnInterval = time.Minute
+
+// LogCommand logs a run of an external program (e.g. nvidia-smi): as a debug record when
+// it succeeds or isn't installed, as a warning (once a minute per program) when it fails
+//
+// Parameters:
+//   - cmd: command that was run
+//   - started: when it was started, for the duration
+//   - err: error of the run (nil if it succeeded)
+func LogCommand(cmd *exec.Cmd, started time.Time, err error) {
+	name := filepath.Base(cmd.Args[0])
+	attrs := []any{"command", name, "args", strings.Join(cmd.Args[1:], " "), "duration", time.Since(started).Round(time.Millisecond)}
+	switch {
+	case err == nil:
+		logger.Debug("external command", attrs...)
+	case errors.Is(err, exec.ErrNotFound):
+		logger.Debug("external command not installed", attrs...)
+	default:
+		WarnEvery("command:"+name, commandWarnInterval, "external command failed", append(attrs, "err", err)...)
+	}
+}
@@ -0,0 +1,203 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProcessMatcher selects processes by name or by a regular expression
+// When both are set, a process must match both
+type ProcessMatcher struct {
+	Name    string         // Exact process name (empty = any name)
+	Pattern *regexp.Regexp // Matched against the name, then the command line (nil = any)
+}
+
+// NewProcessMatcher creates a matcher from the --name and --pattern options
+//
+// Parameters:
+//   - name: exact process name (may be empty)
+//   - pattern: regular expression (may be empty)
+//
+// Returns:
+//   - the matcher
+//   - error if both are empty or the pattern doesn't compile
+func NewProcessMatcher(name, pattern string) (ProcessMatcher, error) {
+	matcher := ProcessMatcher{Name: name}
+	if name == "" && pattern == "" {
+		return matcher, fmt.Errorf("give a process name or a pattern")
+	}
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return matcher, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		matcher.Pattern = re
+	}
+	return matcher, nil
+}
+
+// String describes the matched processes (e.g. "named nginx" or "matching /php-fpm/")
+func (m ProcessMatcher) String() string {
+	var parts []string
+	if m.Name != "" {
+		parts = append(parts, "named "+m.Name)
+	}
+	if m.Pattern != nil {
+		parts = append(parts, "matching /"+m.Pattern.String()+"/")
+	}
+	return strings.Join(parts, " and ")
+}
+
+// Match checks if a process is selected
+// The command line is only read when the pattern doesn't match the name
+// (e.g. a script run by "python3")
+func (m ProcessMatcher) Match(p ProcessInfo) bool {
+	if m.Name != "" && p.Name != m.Name {
+		return false
+	}
+	if m.Pattern == nil || m.Pattern.MatchString(p.Name) {
+		return true
+	}
+	return m.Pattern.MatchString(GetCmdline(p.PID))
+}
+
+// FilterProcesses keeps only the processes a matcher selects
+//
+// Parameters:
+//   - processes: slice of ProcessInfo to filter
+//   - matcher: processes to keep
+//
+// Returns: new slice with the matching processes (order is preserved)
+func FilterProcesses(processes []ProcessInfo, matcher ProcessMatcher) []ProcessInfo {
+	var filtered []ProcessInfo
+	for _, p := range processes {
+		if matcher.Match(p) {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
+// MonitorProcessGroup continuously monitors every process a matcher selects, as one group
+// Prints a table row with the total CPU, RAM and threads of the group at each interval until
+// Ctrl+C. The processes are looked up again for every row, so workers respawned with new
+// PIDs are followed; the Changes column counts the PIDs that appeared (+) and went away (-)
+//
+// Parameters:
+//   - matcher: processes of the group
+//   - intervalSeconds: interval between updates in seconds (also the CPU usage sample)
+//
+// Returns: error if no process matches at the start, or the processes can't be read
+func MonitorProcessGroup(matcher ProcessMatcher, intervalSeconds int) error {
+	options := CollectOptions{SampleInterval: time.Duration(intervalSeconds) * time.Second}
+
+	table := NewTable(fmt.Sprintf("Monitoring processes %s every %d seconds (Ctrl+C to stop)", matcher, intervalSeconds),
+		Column{Header: "Time"},
+		Column{Header: "Procs", Right: true},
+		Column{Header: "PIDs", Fill: true},
+		Column{Header: "Changes", Short: "Chg", Right: true, Optional: true},
+		Column{Header: "CPU %", Right: true},
+		Column{Header: "RAM %", Right: true, Optional: true},
+		Column{Header: "RAM", Right: true},
+		Column{Header: "Threads", Short: "Thr", Right: true, Optional: true},
+	)
+
+	self := int32(os.Getpid())
+	var previous map[int32]bool
+	for {
+		// 1. Find the processes of the group (the sampling takes the interval)
+		processes, _, err := CollectProcessInfo(options)
+		if err != nil {
+			table.PrintEnd()
+			return fmt.Errorf("error getting processes: %w", err)
+		}
+		// A pattern also matches the command line of gom itself; zombies already exited
+		group := slices.DeleteFunc(FilterProcesses(processes, matcher), func(p ProcessInfo) bool {
+			return p.PID == self || p.State == StateZombie
+		})
+		if previous == nil && len(group) == 0 {
+			return fmt.Errorf("no process %s", matcher)
+		}
+
+		// 2. Sum the group and compare its PIDs with the previous row
+		var total ProcessInfo
+		current := make(map[int32]bool, len(group))
+		pids := make([]int, 0, len(group))
+		for _, p := range group {
+			total.CPUPercentage += p.CPUPercentage
+			total.RAMPercentage += p.RAMPercentage
+			total.RAMBytes += p.RAMBytes
+			total.NumThreads += p.NumThreads
+			current[p.PID] = true
+			pids = append(pids, int(p.PID))
+		}
+		changes := "-"
+		if previous != nil {
+			changes = formatChanges(countChanges(previous, current), countChanges(current, previous))
+		}
+		previous = current
+
+		// 3. Print formatted statistics
+		table.PrintRow(
+			FormatTimestamp(time.Now()),
+			strconv.Itoa(len(group)),
+			formatPIDs(pids, 24),
+			changes,
+			fmt.Sprintf("%.2f%%", total.CPUPercentage),
+			fmt.Sprintf("%.2f%%", total.RAMPercentage),
+			FormatBytes(total.RAMBytes),
+			strconv.Itoa(int(total.NumThreads)))
+	}
+}
+
+// countChanges counts the PIDs of after that are not in before
+func countChanges(before, after map[int32]bool) int {
+	count := 0
+	for pid := range after {
+		if !before[pid] {
+			count++
+		}
+	}
+	return count
+}
+
+// formatChanges describes how many PIDs appeared and went away (e.g. "+2 -1", "-" if none)
+func formatChanges(started, ended int) string {
+	var parts []string
+	if started > 0 {
+		parts = append(parts, fmt.Sprintf("+%d", started))
+	}
+	if ended > 0 {
+		parts = append(parts, fmt.Sprintf("-%d", ended))
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatPIDs lists PIDs in ascending order, cut with "..." to fit a width (e.g. "812,813,...")
+func formatPIDs(pids []int, width int) string {
+	if len(pids) == 0 {
+		return "-"
+	}
+	sort.Ints(pids)
+	text := ""
+	for i, pid := range pids {
+		next := strconv.Itoa(pid)
+		if i > 0 {
+			next = "," + next
+		}
+		if len(text)+len(next) > width-4 && i < len(pids)-1 {
+			return text + ",..."
+		}
+		text += next
+	}
+	return text
+}
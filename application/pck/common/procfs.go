@@ -0,0 +1,115 @@
+package common
+
+import (
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/procfs"
+)
+
+// procfsSample is a reading of the CPU time of a process by the procfs backend
+type procfsSample struct {
+	start float64   // Start time of the process (a reused PID belongs to a process with another one)
+	cpu   cpuSample // CPU time used so far
+}
+
+// collectProcfsSampled is CollectProcessInfo for the procfs backend
+// With a sample interval, /proc is read twice and each process gets its CPU usage over that interval
+func collectProcfsSampled(options CollectOptions) ([]ProcessInfo, CollectionStats, error) {
+	var first map[int32]procfsSample
+	if options.SampleInterval > 0 {
+		var err error
+		if _, first, _, err = collectProcfs(nil); err != nil {
+			return nil, CollectionStats{}, err
+		}
+		time.Sleep(options.SampleInterval)
+	}
+
+	processes, _, stats, err := collectProcfs(first)
+	if err != nil {
+		return nil, stats, err
+	}
+
+	ReportSkipped("processes", stats)
+	if stats.Skipped > 0 {
+		DebugEvery("skipped-processes", skippedLogInterval,
+			"process collection: %s; first errors: %v", stats, stats.Errors)
+	}
+	return processes, stats, nil
+}
+
+// collectProcfs collects all active processes by reading /proc directly (procfs backend)
+// Processes with a sample in previous report their CPU usage since then; the others report
+// their lifetime average
+//
+// Parameters:
+//   - previous: CPU samples of an earlier collection (nil for lifetime averages)
+//
+// Returns:
+//   - slice of ProcessInfo with all valid processes
+//   - CPU samples of this collection, for the next one
+//   - CollectionStats with the number of shown and skipped processes
+//   - error if /proc or the memory total can't be read
+func collectProcfs(previous map[int32]procfsSample) ([]ProcessInfo, map[int32]procfsSample, CollectionStats, error) {
+	var stats CollectionStats
+
+	// 1. Get the total system memory, the boot time and the PIDs
+	totalSystemMem, err := GetProcessMemoryTotal()
+	if err != nil {
+		return nil, nil, stats, err
+	}
+	boot, err := procfs.BootTime()
+	if err != nil {
+		return nil, nil, stats, err
+	}
+	pids, err := procfs.Pids()
+	if err != nil {
+		return nil, nil, stats, err
+	}
+
+	processInfoList := make([]ProcessInfo, 0, len(pids))
+	samples := make(map[int32]procfsSample, len(pids))
+
+	// 2. Read each process and measure its CPU usage
+	for _, pid := range pids {
+		p, err := procfs.ReadProcess(pid)
+		if err != nil {
+			if isProcessGone(err) {
+				continue
+			}
+			stats.Skip(err)
+			continue
+		}
+
+		now := time.Now()
+		sample := procfsSample{start: p.StartTime, cpu: cpuSample{total: p.CPUTime, taken: now}}
+		samples[pid] = sample
+
+		var cpuPercent float64
+		if last, ok := previous[pid]; ok && last.start == p.StartTime {
+			cpuPercent = cpuPercentBetween(last.cpu, sample.cpu)
+		} else if lifetime := float64(now.Unix()-boot) - p.StartTime; lifetime > 0 {
+			cpuPercent = p.CPUTime / lifetime * 100
+		}
+
+		processInfoList = append(processInfoList, ProcessInfo{
+			PID:           p.PID,
+			Name:          p.Name,
+			CPUPercentage: cpuPercent,
+			RAMPercentage: float32(float64(p.RSS) / float64(totalSystemMem) * 100),
+			RAMBytes:      p.RSS,
+			LastCPU:       p.LastCPU,
+			Username:      lookupUsername(p.UID),
+			NumThreads:    p.NumThreads,
+			CreateTime:    boot*1000 + int64(p.StartTime*1000),
+			CPUTime:       p.CPUTime,
+			State:         stateFromLetter(p.State),
+			Nice:          p.Nice,
+			PPID:          p.PPID,
+			PGID:          p.PGID,
+			SID:           p.SID,
+		})
+	}
+	stats.Shown = len(processInfoList)
+
+	return processInfoList, samples, stats, nil
+}
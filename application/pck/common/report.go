@@ -0,0 +1,124 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// Reasons an item is skipped during a collection (see CollectionStats.Skip)
+const (
+	ReasonPermission = "permission denied" // Needs root (or the owner) to read
+	ReasonNotFound   = "not found"         // Disappeared or never existed (e.g. a removed disk)
+	ReasonTimeout    = "timed out"         // Didn't answer in time (e.g. a hung network mount)
+	ReasonOther      = "other error"       // Anything else (see the first errors)
+)
+
+// verbose prints the collection reports of one-shot views (set once at startup by --verbose)
+var verbose bool
+
+// reported contains the reports already printed, since a view may collect the same
+// items more than once (e.g. the disk totals and the disk list)
+var (
+	reported   = make(map[string]bool)
+	reportedMu sync.Mutex
+)
+
+// SetVerbose turns the collection reports of one-shot views on or off
+// Unlike GOM_DEBUG, only what was skipped and why is printed, for users wondering why
+// totals look low when running unprivileged
+func SetVerbose(enabled bool) {
+	verbose = enabled
+}
+
+// ResetReports prints the next reports even if they were already printed
+// Called before each --watch refresh, since the screen is cleared
+func ResetReports() {
+	reportedMu.Lock()
+	defer reportedMu.Unlock()
+	clear(reported)
+}
+
+// Skip counts an item that exists but couldn't be read
+//
+// Parameters:
+//   - err: why the item couldn't be read
+func (s *CollectionStats) Skip(err error) {
+	s.Skipped++
+	if s.Reasons == nil {
+		s.Reasons = make(map[string]int)
+	}
+	s.Reasons[skipReason(err)]++
+	if len(s.Errors) < maxSkippedLogged {
+		s.Errors = append(s.Errors, err)
+	}
+}
+
+// ReasonsString formats the skip reasons from most to least common (e.g. "permission denied: 85, other error: 2")
+func (s CollectionStats) ReasonsString() string {
+	reasons := make([]string, 0, len(s.Reasons))
+	for reason := range s.Reasons {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if s.Reasons[reasons[i]] != s.Reasons[reasons[j]] {
+			return s.Reasons[reasons[i]] > s.Reasons[reasons[j]]
+		}
+		return reasons[i] < reasons[j]
+	})
+
+	parts := make([]string, len(reasons))
+	for i, reason := range reasons {
+		parts[i] = fmt.Sprintf("%s: %d", reason, s.Reasons[reason])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// skipReason classifies why an item couldn't be read
+func skipReason(err error) string {
+	switch {
+	case errors.Is(err, fs.ErrPermission), errors.Is(err, syscall.EPERM):
+		return ReasonPermission
+	case errors.Is(err, fs.ErrNotExist), errors.Is(err, syscall.ENOENT), errors.Is(err, syscall.ENODEV):
+		return ReasonNotFound
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded):
+		return ReasonTimeout
+	default:
+		return ReasonOther
+	}
+}
+
+// ReportSkipped prints what a collection skipped and why to stderr when --verbose is set
+// Nothing is printed if every item could be read or the same report was already printed
+//
+// Parameters:
+//   - what: items collected (e.g. "processes", "partitions")
+//   - stats: counts of the collection
+func ReportSkipped(what string, stats CollectionStats) {
+	if !verbose || stats.Skipped == 0 {
+		return
+	}
+
+	var report strings.Builder
+	fmt.Fprintf(&report, "gom: %s: %d shown, %d skipped (%s)\n", what, stats.Shown, stats.Skipped, stats.ReasonsString())
+	for _, err := range stats.Errors {
+		fmt.Fprintf(&report, "  %v\n", err)
+	}
+	if stats.Reasons[ReasonPermission] > 0 && os.Geteuid() != 0 {
+		fmt.Fprintf(&report, "  run as root to read the %s skipped for permissions\n", what)
+	}
+
+	reportedMu.Lock()
+	defer reportedMu.Unlock()
+	if reported[report.String()] {
+		return
+	}
+	reported[report.String()] = true
+	fmt.Fprint(os.Stderr, report.String())
+}
@@ -0,0 +1,159 @@
+package common
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// processLess returns the ascending comparison function for a sort field
+//
+// Parameters:
+//   - field: field to compare ("cpu", "ram", "time", "pid", "name", "io")
+//
+// Returns: function reporting whether a sorts before b (nil for unknown fields)
+func processLess(field string) func(a, b *ProcessInfo) bool {
+	switch field {
+	case "cpu":
+		return func(a, b *ProcessInfo) bool { return a.CPUPercentage < b.CPUPercentage }
+	case "ram":
+		return func(a, b *ProcessInfo) bool { return a.RAMPercentage < b.RAMPercentage }
+	case "time":
+		return func(a, b *ProcessInfo) bool { return a.CPUTime < b.CPUTime }
+	case "pid":
+		return func(a, b *ProcessInfo) bool { return a.PID < b.PID }
+	case "name":
+		return func(a, b *ProcessInfo) bool { return a.Name < b.Name }
+	case "io":
+		return func(a, b *ProcessInfo) bool { return a.DiskIO < b.DiskIO }
+	}
+	return nil
+}
+
+// processOrder returns the comparison function of the order of a sort field
+// Processes with equal values are ordered by PID, so the order is the same on every run
+// whether the whole list is sorted or only the first K processes are selected
+//
+// Parameters:
+//   - field: field to compare ("cpu", "ram", "time", "pid", "name", "io")
+//   - descending: true for the largest values first, false for the smallest
+//
+// Returns: function reporting whether a comes before b (nil for unknown fields)
+func processOrder(field string, descending bool) func(a, b *ProcessInfo) bool {
+	less := processLess(field)
+	if less == nil {
+		return nil
+	}
+	if descending {
+		ascending := less
+		less = func(a, b *ProcessInfo) bool { return ascending(b, a) }
+	}
+	return func(a, b *ProcessInfo) bool {
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		return a.PID < b.PID
+	}
+}
+
+// SortsDescending reports the usual order of a sort field: largest first for usage
+// ("cpu", "ram", "time", "io"), smallest first for identifiers ("pid", "name")
+func SortsDescending(field string) bool {
+	return field != "pid" && field != "name"
+}
+
+// SortProcessesByField sorts a slice of ProcessInfo by a specific field
+// Processes with equal values are ordered by PID
+//
+// Parameters:
+//   - processes: slice of ProcessInfo to sort (is modified in-place)
+//   - field: field to sort by ("cpu", "ram", "time", "pid", "name", "io")
+//   - descending: true for descending order (largest -> smallest), false for ascending
+func SortProcessesByField(processes []ProcessInfo, field string, descending bool) {
+	before := processOrder(field, descending)
+	if before == nil {
+		return // Unknown field, leave the order unchanged
+	}
+
+	sort.Slice(processes, func(i, j int) bool {
+		return before(&processes[i], &processes[j])
+	})
+}
+
+// processHeap is a heap of processes used to keep the K first ones of an order
+// The last of the kept processes is at the root, so it's the one replaced
+type processHeap struct {
+	items  []ProcessInfo
+	before func(a, b *ProcessInfo) bool // Order of the processes (see processOrder)
+}
+
+func (h *processHeap) Len() int           { return len(h.items) }
+func (h *processHeap) Less(i, j int) bool { return h.before(&h.items[j], &h.items[i]) }
+func (h *processHeap) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *processHeap) Push(x any)         { h.items = append(h.items, x.(ProcessInfo)) }
+func (h *processHeap) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
+
+// TopKProcesses selects the K processes with the largest value of a field
+// Runs in O(n log k), much faster than sorting the whole list when only a few
+// processes are shown on systems with thousands of them
+//
+// Parameters:
+//   - processes: slice of ProcessInfo to select from (not modified)
+//   - k: number of processes to return (0 or less = all)
+//   - field: field to rank by ("cpu", "ram", "time", "pid", "name", "io")
+//
+// Returns: new slice with at most k processes, sorted in descending order
+func TopKProcesses(processes []ProcessInfo, k int, field string) []ProcessInfo {
+	return FirstKProcesses(processes, k, field, true)
+}
+
+// FirstKProcesses selects the first K processes in the order of a field
+// Like TopKProcesses, but can also select the K smallest values (e.g. the lowest PIDs)
+//
+// Parameters:
+//   - processes: slice of ProcessInfo to select from (not modified)
+//   - k: number of processes to return (0 or less = all)
+//   - field: field to rank by ("cpu", "ram", "time", "pid", "name", "io")
+//   - descending: true for the largest values first, false for the smallest
+//
+// Returns: new slice with at most k processes, in the requested order
+func FirstKProcesses(processes []ProcessInfo, k int, field string, descending bool) []ProcessInfo {
+	before := processOrder(field, descending)
+	if before == nil {
+		return nil
+	}
+
+	// Asking for everything is just a full sort
+	if k <= 0 || k >= len(processes) {
+		top := make([]ProcessInfo, len(processes))
+		copy(top, processes)
+		SortProcessesByField(top, field, descending)
+		return top
+	}
+
+	// 1. Keep the K first processes in a heap, with the last of them at the root
+	h := &processHeap{items: make([]ProcessInfo, 0, k), before: before}
+	for _, p := range processes {
+		if h.Len() < k {
+			heap.Push(h, p)
+			continue
+		}
+		if before(&p, &h.items[0]) {
+			h.items[0] = p
+			heap.Fix(h, 0)
+		}
+	}
+
+	// 2. Pop from last to first, filling the result from the end
+	top := make([]ProcessInfo, h.Len())
+	for i := len(top) - 1; i >= 0; i-- {
+		top[i] = heap.Pop(h).(ProcessInfo)
+	}
+	return top
+}
@@ -0,0 +1,121 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// Process scheduling states, as the single letter ps and top show
+const (
+	StateRunning   = "R" // Running or waiting for a CPU
+	StateSleeping  = "S" // Interruptible sleep (waiting for an event)
+	StateDiskSleep = "D" // Uninterruptible sleep (usually waiting for I/O; can't be killed)
+	StateZombie    = "Z" // Terminated but not yet reaped by its parent
+	StateStopped   = "T" // Stopped by a signal or a debugger
+	StateIdle      = "I" // Idle kernel thread
+	StateUnknown   = "?" // State couldn't be read
+)
+
+// stateLetter converts a gopsutil process status to its state letter
+//
+// Parameters:
+//   - status: status names from process.Status (e.g. ["sleep"])
+//
+// Returns: state letter (StateUnknown if not recognized)
+func stateLetter(status []string) string {
+	if len(status) == 0 {
+		return StateUnknown
+	}
+
+	switch status[0] {
+	case process.Running:
+		return StateRunning
+	case process.Sleep, process.Wait:
+		return StateSleeping
+	case process.Blocked, process.Lock:
+		return StateDiskSleep
+	case process.Zombie:
+		return StateZombie
+	case process.Stop:
+		return StateStopped
+	case process.Idle:
+		return StateIdle
+	default:
+		return StateUnknown
+	}
+}
+
+// stateFromLetter normalizes a state letter of /proc/<pid>/stat
+// Linux also reports "t" (stopped by a debugger) and a few transient states
+//
+// Parameters:
+//   - letter: state field (e.g. "S")
+//
+// Returns: state letter (StateUnknown if not recognized)
+func stateFromLetter(letter string) string {
+	switch letter {
+	case StateRunning, StateSleeping, StateDiskSleep, StateZombie, StateStopped, StateIdle:
+		return letter
+	case "t":
+		return StateStopped
+	case "W":
+		return StateSleeping // Paging (before Linux 2.6) or waking
+	default:
+		return StateUnknown
+	}
+}
+
+// StateName returns a readable name of a state letter (e.g. "disk sleep" for "D")
+func StateName(state string) string {
+	switch state {
+	case StateRunning:
+		return "running"
+	case StateSleeping:
+		return "sleeping"
+	case StateDiskSleep:
+		return "disk sleep"
+	case StateZombie:
+		return "zombie"
+	case StateStopped:
+		return "stopped"
+	case StateIdle:
+		return "idle"
+	default:
+		return "unknown"
+	}
+}
+
+// StateCounts is the number of processes in each state
+type StateCounts map[string]int
+
+// CountStates counts the processes in each state
+//
+// Parameters:
+//   - processes: processes to count
+//
+// Returns: StateCounts keyed by state letter
+func CountStates(processes []ProcessInfo) StateCounts {
+	counts := make(StateCounts)
+	for _, p := range processes {
+		counts[p.State]++
+	}
+	return counts
+}
+
+// String formats the counts as a summary (e.g. "2 running, 140 sleeping, 0 zombie")
+// Sleeping includes idle kernel threads; disk sleep and stopped are only listed when present
+func (c StateCounts) String() string {
+	parts := []string{
+		fmt.Sprintf("%d running", c[StateRunning]),
+		fmt.Sprintf("%d sleeping", c[StateSleeping]+c[StateIdle]),
+	}
+	for _, state := range []string{StateDiskSleep, StateStopped} {
+		if c[state] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", c[state], StateName(state)))
+		}
+	}
+	parts = append(parts, fmt.Sprintf("%d zombie", c[StateZombie]))
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,116 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// SessionSummary keeps the minimum, average and maximum of a few series while gom watches
+// the system or a process, to print them when it stops (Ctrl+C)
+// Only running aggregates are kept, so a long session doesn't use more memory
+type SessionSummary struct {
+	start  time.Time        // When the session started
+	series []*SummarySeries // Series in the order they were added
+}
+
+// SummarySeries is the running aggregate of one series of a SessionSummary
+type SummarySeries struct {
+	name    string               // Series name (e.g. "CPU")
+	format  func(float64) string // Formats a value of the series (e.g. FormatSummaryPercent)
+	count   int                  // Number of values added
+	sum     float64              // Sum of the values, for the average
+	min     float64              // Lowest value
+	max     float64              // Highest value
+	minTime time.Time            // When the lowest value was first seen
+	maxTime time.Time            // When the highest value (the peak) was first seen
+}
+
+// NewSessionSummary creates an empty summary of a session starting now
+func NewSessionSummary() *SessionSummary {
+	return &SessionSummary{start: time.Now()}
+}
+
+// Series returns the series with a name, adding it on first use
+// Series are printed in the order they are first used
+//
+// Parameters:
+//   - name: series name shown in the summary (e.g. "CPU", "RAM", "CPU temp")
+//   - format: formats the values of the series (used when the series is added)
+func (s *SessionSummary) Series(name string, format func(float64) string) *SummarySeries {
+	for _, series := range s.series {
+		if series.name == name {
+			return series
+		}
+	}
+	series := &SummarySeries{name: name, format: format}
+	s.series = append(s.series, series)
+	return series
+}
+
+// Add adds a value of the series measured at a point in time
+func (s *SummarySeries) Add(value float64, at time.Time) {
+	if s.count == 0 || value < s.min {
+		s.min, s.minTime = value, at
+	}
+	if s.count == 0 || value > s.max {
+		s.max, s.maxTime = value, at
+	}
+	s.sum += value
+	s.count++
+}
+
+// Avg returns the average of the values added (0 if there are none)
+func (s *SummarySeries) Avg() float64 {
+	if s.count == 0 {
+		return 0
+	}
+	return s.sum / float64(s.count)
+}
+
+// FormatSummaryPercent formats a percentage of a summary (e.g. "42.5%")
+func FormatSummaryPercent(value float64) string {
+	return fmt.Sprintf("%.1f%%", value)
+}
+
+// FormatSummaryBytes formats a size in bytes of a summary (e.g. "1.2 GB")
+func FormatSummaryBytes(value float64) string {
+	return FormatBytes(uint64(value))
+}
+
+// FormatSummaryCelsius formats a temperature of a summary (e.g. "65°C")
+func FormatSummaryCelsius(value float64) string {
+	return strconv.Itoa(int(value)) + "°C"
+}
+
+// Print prints the minimum, average and maximum of every series in a table, with the
+// time of the lowest and highest values; timestamps follow the format selected with SetFormats
+//
+// Parameters:
+//   - title: what was watched (e.g. "System", "PID 1234")
+func (s *SessionSummary) Print(title string) {
+	elapsed := time.Since(s.start)
+	duration := FormatDuration(elapsed)
+	if elapsed < time.Minute {
+		duration = elapsed.Round(time.Second).String()
+	}
+
+	table := NewTable(fmt.Sprintf("Summary of %s over %s", title, duration),
+		Column{Header: "Metric", Fill: true},
+		Column{Header: "Min", Right: true}, Column{Header: "Avg", Right: true},
+		Column{Header: "Max", Right: true},
+		Column{Header: "Min at", Optional: true}, Column{Header: "Peak at"},
+		Column{Header: "Samples", Right: true, Optional: true})
+
+	if len(s.series) == 0 {
+		table.AddNote("Nothing was measured")
+	}
+	for _, series := range s.series {
+		table.AddRow(series.name,
+			series.format(series.min), series.format(series.Avg()), series.format(series.max),
+			FormatTimestamp(series.minTime), FormatTimestamp(series.maxTime),
+			strconv.Itoa(series.count))
+	}
+
+	table.Print()
+}
@@ -0,0 +1,268 @@
+package common
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// minTableWidth is the usual width of the boxes and tables, so they all line up
+const minTableWidth = 84
+
+// Column is a column of a Table
+type Column struct {
+	Header   string // Column title
+	Short    string // Shorter title for narrow terminals (empty = Header)
+	Right    bool   // Right-align the values (numbers)
+	Fill     bool   // Takes the spare width when the table is narrower than the usual width
+	Optional bool   // Left out when the terminal is too narrow for every column
+	width    int    // Widest value seen so far
+}
+
+// Table draws rows in a box whose columns grow to fit their values
+// Unlike the rows of a Box, long values (large PIDs, long names, RFC 3339
+// timestamps) are never cut or pushed out of the box, unless the terminal is too
+// narrow for them: then the short titles are used, the Fill column is cut and
+// the Optional columns are left out
+type Table struct {
+	title   string
+	columns []Column
+	rows    []tableRow
+	started bool // The title of a streamed table was printed (see PrintRow)
+}
+
+// NewTable creates an empty table
+//
+// Parameters:
+//   - title: title shown above the header
+//   - columns: columns of the table, from left to right
+//
+// Returns: pointer to a configured Table
+func NewTable(title string, columns ...Column) *Table {
+	return &Table{title: title, columns: columns}
+}
+
+// AddRow adds a row to be drawn by Print (missing cells are left empty)
+func (t *Table) AddRow(cells ...string) {
+	t.fit(cells)
+	t.rows = append(t.rows, tableRow{kind: tableCells, cells: cells})
+}
+
+// AddNote adds a line of text across the whole table, drawn by Print (e.g. "No process found")
+func (t *Table) AddNote(text string) {
+	t.rows = append(t.rows, tableRow{kind: tableNote, note: text})
+}
+
+// AddDivider adds a single line between two groups of rows, drawn by Print
+func (t *Table) AddDivider() {
+	t.rows = append(t.rows, tableRow{kind: tableDivider})
+}
+
+// Print draws the whole table
+func (t *Table) Print() {
+	fmt.Println()
+	t.printTitle()
+	t.printColumnTitles()
+	for _, row := range t.rows {
+		switch row.kind {
+		case tableCells:
+			t.printRow(row.cells)
+		case tableNote:
+			fmt.Printf("║  %s  ║\n", FitString(row.note, t.layout().inner()-4))
+		case tableDivider:
+			fmt.Println("╟" + strings.Repeat("─", t.layout().inner()) + "╢")
+		}
+	}
+	t.PrintEnd()
+}
+
+// PrintRow draws one row right away, for output that grows over time (e.g. a monitor)
+// The title is drawn before the first row, and the column titles again whenever a
+// value widens the table
+func (t *Table) PrintRow(cells ...string) {
+	before := t.layout()
+	t.fit(cells)
+	switch {
+	case !t.started:
+		fmt.Println()
+		t.printTitle()
+		t.printColumnTitles()
+		t.started = true
+	case !before.equal(t.layout()):
+		fmt.Println(t.border("╠", "╣"))
+		t.printColumnTitles()
+	}
+	t.printRow(cells)
+}
+
+// PrintEnd draws the bottom border (after PrintRow)
+func (t *Table) PrintEnd() {
+	fmt.Println(t.border("╚", "╝"))
+}
+
+// fit widens the columns to fit the cells
+func (t *Table) fit(cells []string) {
+	for i, cell := range cells {
+		if i < len(t.columns) {
+			t.columns[i].width = max(t.columns[i].width, DisplayWidth(cell))
+		}
+	}
+}
+
+// tableRow is a row added to a Table
+type tableRow struct {
+	kind  tableRowKind
+	cells []string
+	note  string
+}
+
+// tableRowKind selects how a row of a Table is drawn
+type tableRowKind int
+
+const (
+	tableCells   tableRowKind = iota // A value in each column
+	tableNote                        // Text across the whole table
+	tableDivider                     // Single line (╟─╢), between groups of rows
+)
+
+// tableLayout is how a table is drawn: which columns, how wide and with which titles
+type tableLayout struct {
+	shown   []int    // Indexes of the columns drawn
+	widths  []int    // Width of each column drawn
+	headers []string // Title of each column drawn
+}
+
+// equal checks if two layouts draw the table the same way
+func (l tableLayout) equal(other tableLayout) bool {
+	return slices.Equal(l.shown, other.shown) && slices.Equal(l.widths, other.widths)
+}
+
+// inner returns the width between the vertical borders
+func (l tableLayout) inner() int {
+	inner := 2 + 3*(len(l.widths)-1) // Outer padding and the " │ " separators
+	for _, width := range l.widths {
+		inner += width
+	}
+	return inner
+}
+
+// layout works out how the table is drawn, with the spare width given to the Fill column
+// When the terminal is narrower than the table, the short titles are used, then the Fill
+// column is cut down, then the Optional columns are left out from the right
+func (t *Table) layout() tableLayout {
+	limit := 0 // No limit when the output isn't a terminal
+	if width := TerminalWidth(); width > 0 {
+		limit = max(width, minOutputWidth) - 2
+	}
+
+	shown := make([]int, 0, len(t.columns))
+	for i := range t.columns {
+		shown = append(shown, i)
+	}
+	for {
+		l := t.layoutOf(shown, false)
+		if limit == 0 || l.inner() <= limit {
+			return t.grow(l, limit)
+		}
+		l = t.layoutOf(shown, true)
+		t.shrinkFill(l, limit)
+		if l.inner() <= limit {
+			return l
+		}
+		drop := -1
+		for j, i := range shown {
+			if t.columns[i].Optional {
+				drop = j
+			}
+		}
+		if drop < 0 {
+			return l // Wraps, but every value is still there
+		}
+		shown = slices.Delete(shown, drop, drop+1)
+	}
+}
+
+// layoutOf returns the layout with the given columns at the width of their values
+func (t *Table) layoutOf(shown []int, short bool) tableLayout {
+	l := tableLayout{shown: slices.Clone(shown)}
+	for _, i := range shown {
+		c := t.columns[i]
+		header := c.Header
+		if short && c.Short != "" {
+			header = c.Short
+		}
+		l.headers = append(l.headers, header)
+		l.widths = append(l.widths, max(c.width, DisplayWidth(header)))
+	}
+	return l
+}
+
+// fillIndex returns the position in the layout of the column that takes or gives up width
+func (t *Table) fillIndex(l tableLayout) int {
+	for j, i := range l.shown {
+		if t.columns[i].Fill {
+			return j
+		}
+	}
+	return len(l.shown) - 1
+}
+
+// grow widens the Fill column so the table has room for the title and is at least the
+// usual width (or of the terminal, when narrower)
+func (t *Table) grow(l tableLayout, limit int) tableLayout {
+	target := max(minTableWidth-2, DisplayWidth(t.title)+4)
+	if limit > 0 {
+		target = min(target, limit)
+	}
+	if inner := l.inner(); inner < target {
+		l.widths[t.fillIndex(l)] += target - inner
+	}
+	return l
+}
+
+// shrinkFill narrows the Fill column, down to its title, so the table fits in limit
+func (t *Table) shrinkFill(l tableLayout, limit int) {
+	fill := t.fillIndex(l)
+	narrowest := max(DisplayWidth(l.headers[fill]), 3)
+	l.widths[fill] = max(l.widths[fill]-(l.inner()-limit), min(l.widths[fill], narrowest))
+}
+
+// border returns a horizontal border with the given corners
+func (t *Table) border(left, right string) string {
+	return left + strings.Repeat("═", t.layout().inner()) + right
+}
+
+// printTitle draws the top border and the title
+func (t *Table) printTitle() {
+	fmt.Println(t.border("╔", "╗"))
+	fmt.Printf("║  %s  ║\n", FitString(t.title, t.layout().inner()-4))
+	fmt.Println(t.border("╠", "╣"))
+}
+
+// printColumnTitles draws the column titles and the separator below them
+func (t *Table) printColumnTitles() {
+	l := t.layout()
+	t.printCells(l, l.headers)
+	fmt.Println(t.border("╠", "╣"))
+}
+
+// printRow draws one row of cells
+func (t *Table) printRow(cells []string) {
+	l := t.layout()
+	shown := make([]string, len(l.shown))
+	for j, i := range l.shown {
+		if i < len(cells) {
+			shown[j] = cells[i]
+		}
+	}
+	t.printCells(l, shown)
+}
+
+// printCells draws the cells of the columns in the layout, cut to the column widths
+func (t *Table) printCells(l tableLayout, cells []string) {
+	parts := make([]string, len(cells))
+	for j, cell := range cells {
+		parts[j] = PadString(TruncateString(cell, l.widths[j]), l.widths[j], t.columns[l.shown[j]].Right)
+	}
+	fmt.Printf("║ %s ║\n", strings.Join(parts, " │ "))
+}
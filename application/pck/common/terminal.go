@@ -0,0 +1,65 @@
+package common
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// minOutputWidth is the narrowest the boxes and tables get, however small the terminal
+// (below it they wrap, but still show every value)
+const minOutputWidth = 40
+
+// TerminalWidth returns the width of the terminal the output goes to
+//
+// Returns: width in columns (0 if the output isn't a terminal, e.g. a pipe or a file)
+func TerminalWidth() int {
+	return terminalWidth(os.Stdout)
+}
+
+// terminalWidth returns the width of the terminal a writer goes to (0 if it isn't one)
+func terminalWidth(w io.Writer) int {
+	file, ok := w.(*os.File)
+	if !ok {
+		return 0
+	}
+	width, _, err := term.GetSize(int(file.Fd()))
+	if err != nil || width <= 0 {
+		return 0
+	}
+	return width
+}
+
+// OutputWidth returns the width the boxes and tables are drawn at
+// It's the usual width of the tables, or the terminal width when the terminal is narrower
+// (output to pipes and files always has the usual width)
+//
+// Returns: width in columns, borders included
+func OutputWidth() int {
+	return outputWidth(os.Stdout)
+}
+
+// outputWidth returns the width boxes are drawn at on a writer (see OutputWidth)
+func outputWidth(w io.Writer) int {
+	if width := terminalWidth(w); width > 0 && width < minTableWidth {
+		return max(width, minOutputWidth)
+	}
+	return minTableWidth
+}
+
+// Compact checks if the output is narrower than usual, so views should leave out details
+func Compact() bool {
+	return OutputWidth() < minTableWidth
+}
+
+// NarrowWidth narrows a width meant for the usual output width (e.g. of a usage bar) by as
+// much as the output is narrower, so it still fits in the box
+//
+// Parameters:
+//   - width: width in columns at the usual output width
+//
+// Returns: width in columns, at least 10
+func NarrowWidth(width int) int {
+	return max(width-(minTableWidth-OutputWidth()), 10)
+}
@@ -0,0 +1,195 @@
+package common
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ansiEscape matches terminal escape sequences: CSI (colors, cursor moves, e.g. "\033[1;91m")
+// and OSC (window titles, links), which take no room on screen
+var ansiEscape = regexp.MustCompile("\033\\[[0-9;?]*[ -/]*[@-~]|\033\\][^\033\007]*(?:\007|\033\\\\)")
+
+// wideRunes are the characters terminals draw two columns wide: East Asian wide and
+// fullwidth characters (CJK, Hangul, kana) and emoji
+var wideRunes = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x1100, Hi: 0x115f, Stride: 1}, // Hangul Jamo initial consonants
+		{Lo: 0x231a, Hi: 0x231b, Stride: 1}, // Watch, hourglass
+		{Lo: 0x2329, Hi: 0x232a, Stride: 1}, // Angle brackets
+		{Lo: 0x23e9, Hi: 0x23ec, Stride: 1},
+		{Lo: 0x23f0, Hi: 0x23f3, Stride: 3},
+		{Lo: 0x25fd, Hi: 0x25fe, Stride: 1},
+		{Lo: 0x2614, Hi: 0x2615, Stride: 1},
+		{Lo: 0x2648, Hi: 0x2653, Stride: 1}, // Zodiac signs
+		{Lo: 0x267f, Hi: 0x2693, Stride: 20},
+		{Lo: 0x26a1, Hi: 0x26a1, Stride: 1},
+		{Lo: 0x26aa, Hi: 0x26ab, Stride: 1},
+		{Lo: 0x26bd, Hi: 0x26be, Stride: 1},
+		{Lo: 0x26c4, Hi: 0x26c5, Stride: 1},
+		{Lo: 0x26ce, Hi: 0x26d4, Stride: 6},
+		{Lo: 0x26ea, Hi: 0x26ea, Stride: 1},
+		{Lo: 0x26f2, Hi: 0x26f3, Stride: 1},
+		{Lo: 0x26f5, Hi: 0x26fa, Stride: 5},
+		{Lo: 0x26fd, Hi: 0x26fd, Stride: 1},
+		{Lo: 0x2705, Hi: 0x2705, Stride: 1},
+		{Lo: 0x270a, Hi: 0x270b, Stride: 1},
+		{Lo: 0x2728, Hi: 0x2728, Stride: 1},
+		{Lo: 0x274c, Hi: 0x274e, Stride: 2},
+		{Lo: 0x2753, Hi: 0x2755, Stride: 1},
+		{Lo: 0x2757, Hi: 0x2757, Stride: 1},
+		{Lo: 0x2795, Hi: 0x2797, Stride: 1},
+		{Lo: 0x27b0, Hi: 0x27bf, Stride: 15},
+		{Lo: 0x2b1b, Hi: 0x2b1c, Stride: 1},
+		{Lo: 0x2b50, Hi: 0x2b55, Stride: 5},
+		{Lo: 0x2e80, Hi: 0x303e, Stride: 1}, // CJK radicals, symbols and punctuation
+		{Lo: 0x3041, Hi: 0x33ff, Stride: 1}, // Hiragana, katakana, CJK compatibility
+		{Lo: 0x3400, Hi: 0x4dbf, Stride: 1}, // CJK extension A
+		{Lo: 0x4e00, Hi: 0x9fff, Stride: 1}, // CJK unified ideographs
+		{Lo: 0xa000, Hi: 0xa4cf, Stride: 1}, // Yi
+		{Lo: 0xa960, Hi: 0xa97f, Stride: 1}, // Hangul Jamo extended A
+		{Lo: 0xac00, Hi: 0xd7a3, Stride: 1}, // Hangul syllables
+		{Lo: 0xf900, Hi: 0xfaff, Stride: 1}, // CJK compatibility ideographs
+		{Lo: 0xfe10, Hi: 0xfe19, Stride: 1}, // Vertical forms
+		{Lo: 0xfe30, Hi: 0xfe6f, Stride: 1}, // CJK compatibility forms, small forms
+		{Lo: 0xff00, Hi: 0xff60, Stride: 1}, // Fullwidth forms
+		{Lo: 0xffe0, Hi: 0xffe6, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x16fe0, Hi: 0x16fe4, Stride: 1},
+		{Lo: 0x17000, Hi: 0x18cff, Stride: 1}, // Tangut
+		{Lo: 0x1b000, Hi: 0x1b2ff, Stride: 1}, // Kana supplement
+		{Lo: 0x1f004, Hi: 0x1f004, Stride: 1},
+		{Lo: 0x1f0cf, Hi: 0x1f0cf, Stride: 1},
+		{Lo: 0x1f18e, Hi: 0x1f18e, Stride: 1},
+		{Lo: 0x1f191, Hi: 0x1f19a, Stride: 1},
+		{Lo: 0x1f200, Hi: 0x1f2ff, Stride: 1}, // Enclosed ideographic supplement
+		{Lo: 0x1f300, Hi: 0x1f64f, Stride: 1}, // Pictographs, emoticons
+		{Lo: 0x1f680, Hi: 0x1f6ff, Stride: 1}, // Transport and map symbols
+		{Lo: 0x1f7e0, Hi: 0x1f7eb, Stride: 1},
+		{Lo: 0x1f900, Hi: 0x1f9ff, Stride: 1}, // Supplemental symbols and pictographs
+		{Lo: 0x1fa70, Hi: 0x1faff, Stride: 1},
+		{Lo: 0x20000, Hi: 0x2fffd, Stride: 1}, // CJK extensions B to F
+		{Lo: 0x30000, Hi: 0x3fffd, Stride: 1}, // CJK extension G
+	},
+}
+
+// StripANSI removes the terminal escape sequences (colors, cursor moves) from a string
+func StripANSI(s string) string {
+	if !strings.ContainsRune(s, '\033') {
+		return s
+	}
+	return ansiEscape.ReplaceAllString(s, "")
+}
+
+// RuneWidth returns how many terminal columns a character takes
+// Combining marks (accents drawn over the previous character), zero-width characters and
+// control characters take none, East Asian wide characters and emoji take two
+func RuneWidth(r rune) int {
+	switch {
+	case r < 0x20 || (r >= 0x7f && r < 0xa0):
+		return 0
+	case r < 0x300:
+		return 1 // Latin, the most common case
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) || (r >= 0x1160 && r <= 0x11ff):
+		return 0 // Also the Hangul vowels and final consonants that join the initial one
+	case unicode.Is(wideRunes, r):
+		return 2
+	}
+	return 1
+}
+
+// DisplayWidth returns how many terminal columns a string takes
+// Escape sequences take none; unlike len and the fmt widths, multibyte and wide characters
+// count as the columns they cover
+//
+// Parameters:
+//   - s: text, possibly with color codes
+//
+// Returns: width in columns
+func DisplayWidth(s string) int {
+	width := 0
+	for _, r := range StripANSI(s) {
+		width += RuneWidth(r)
+	}
+	return width
+}
+
+// CutString cuts a string to at most width columns, without splitting a character
+// Escape sequences are kept as they are and take no room
+//
+// Parameters:
+//   - s: text, possibly with color codes
+//   - width: maximum width in columns
+//
+// Returns: the start of s that fits in width columns
+func CutString(s string, width int) string {
+	var b strings.Builder
+	used := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\033' {
+			if loc := ansiEscape.FindStringIndex(s[i:]); loc != nil && loc[0] == 0 {
+				b.WriteString(s[i : i+loc[1]])
+				i += loc[1]
+				continue
+			}
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if used+RuneWidth(r) > width {
+			break
+		}
+		b.WriteString(s[i : i+size])
+		used += RuneWidth(r)
+		i += size
+	}
+	return b.String()
+}
+
+// TruncateString truncates a string to a maximum width in columns
+// Adds "..." at the end if the string is truncated; characters are never split and
+// wide characters count as two columns
+//
+// Parameters:
+//   - s: string to truncate
+//   - maxLen: maximum allowed width
+//
+// Returns: truncated string (if necessary)
+func TruncateString(s string, maxLen int) string {
+	if DisplayWidth(s) <= maxLen {
+		return s
+	}
+	if maxLen <= 3 {
+		return CutString(s, maxLen) // If maxLen is too small, just cut
+	}
+	return CutString(s, maxLen-3) + "..."
+}
+
+// PadString pads a string with spaces to a width in columns (fmt's %-*s counts characters,
+// so wide characters would push the rest of the line out of place)
+//
+// Parameters:
+//   - s: text, possibly with color codes
+//   - width: width in columns
+//   - right: right-align the text (spaces on the left)
+//
+// Returns: the padded string (s as is if it is already wider)
+func PadString(s string, width int, right bool) string {
+	spaces := strings.Repeat(" ", max(width-DisplayWidth(s), 0))
+	if right {
+		return spaces + s
+	}
+	return s + spaces
+}
+
+// FitString cuts or pads a string to exactly width columns, left-aligned
+// Used for the text columns of fixed-width rows, in place of "%-*s"
+//
+// Parameters:
+//   - s: text of the cell
+//   - width: width of the column
+//
+// Returns: the text truncated with "..." or padded with spaces
+func FitString(s string, width int) string {
+	return PadString(TruncateString(s, width), width, false)
+}
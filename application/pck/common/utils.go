@@ -1,9 +1,24 @@
 package common
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"os/user"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
+	"unicode"
 
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+	"github.com/dfialho05/GoMonitor/application/pck/procfs"
 	"github.com/shirou/gopsutil/v3/mem"
 	"github.com/shirou/gopsutil/v3/process"
 )
@@ -11,17 +26,47 @@ import (
 // ProcessInfo contains detailed information about a process
 // This structure is used in all modules to represent process data
 type ProcessInfo struct {
-	PID           int32   // Process ID in the operating system
-	Name          string  // Process/executable name
-	CPUPercentage float64 // CPU usage percentage (0-100+, can exceed 100 on multi-core systems)
-	RAMPercentage float32 // RAM usage percentage relative to total system memory
-	RAMBytes      uint64  // RAM memory used in bytes (RSS - Resident Set Size)
+	PID           int32       // Process ID in the operating system
+	Name          string      // Process/executable name
+	CPUPercentage float64     // CPU usage percentage (0-100+, can exceed 100 on multi-core systems)
+	RAMPercentage float32     // RAM usage percentage relative to total system memory
+	RAMBytes      uint64      // RAM memory used in bytes (RSS - Resident Set Size)
+	LastCPU       int         // CPU core the process last ran on (-1 if not available)
+	Username      string      // Owner of the process ("?" if not available)
+	NumThreads    int32       // Number of threads (0 if not available)
+	CreateTime    int64       // Process start time in milliseconds since the epoch (0 if not available)
+	CPUTime       float64     // CPU time used since the process started, user + system, in seconds (0 if not available)
+	State         string      // Scheduling state letter (see StateRunning; StateUnknown if not available)
+	Nice          int32       // Nice value, from -20 (highest priority) to 19 (0 if not available)
+	PPID          int32       // Parent process ID (0 if not available)
+	PGID          int32       // Process group ID, shared by a pipeline or shell job (0 if not available)
+	SID           int32       // Session ID, shared by everything started from one terminal or login (0 if not available)
+	Flags         ProcessFlag // Lifecycle flags set by a ProcessTracker (renamed, respawning)
+	DiskIO        float64     // Disk read + write throughput in bytes per second (0 if not measured, see disk.MeasureProcessIO)
+}
+
+// TableOptions controls the columns and order of the process listings (tables and CSV)
+type TableOptions struct {
+	ShowCore    bool            // Show the CPU core each process last ran on
+	ShowGroups  bool            // Show the process group and session IDs
+	ShowCPUTime bool            // Show the CPU time used since each process started
+	ShowCmdline bool            // Show the full command line of each process (--wide)
+	Columns     []ProcessColumn // Columns picked with --columns, instead of the usual ones and the options above (nil = usual)
+	Reverse     bool            // Reverse the usual order of the sort field (e.g. lowest CPU usage first)
 }
 
 // GetSystemMemoryTotal gets the total system memory once
 // This function is optimized to be called only once and the result reused
 // Returns: total memory in bytes and error (if any)
 func GetSystemMemoryTotal() (uint64, error) {
+	if UsingProcfs() {
+		info, err := procfs.ReadMeminfo()
+		if err != nil {
+			return 0, fmt.Errorf("error getting system memory information: %w", err)
+		}
+		return info.Total, nil
+	}
+
 	vm, err := mem.VirtualMemory()
 	if err != nil {
 		return 0, fmt.Errorf("error getting system memory information: %w", err)
@@ -29,6 +74,28 @@ func GetSystemMemoryTotal() (uint64, error) {
 	return vm.Total, nil
 }
 
+// memoryLimit is the memory the RAM percentages of processes are relative to (0 = host RAM)
+var memoryLimit atomic.Uint64
+
+// SetMemoryLimit makes the RAM percentages of processes relative to a cgroup memory limit
+// Inside a container, 50% then means half of what the container may use, not of the host RAM
+//
+// Parameters:
+//   - limit: memory limit in bytes (0 for the host RAM)
+func SetMemoryLimit(limit uint64) {
+	memoryLimit.Store(limit)
+}
+
+// GetProcessMemoryTotal gets the memory the RAM percentages of processes are relative to:
+// the limit set with SetMemoryLimit, or else the total system memory
+// Returns: memory in bytes and error (if any)
+func GetProcessMemoryTotal() (uint64, error) {
+	if limit := memoryLimit.Load(); limit > 0 {
+		return limit, nil
+	}
+	return GetSystemMemoryTotal()
+}
+
 // GetProcessInfo collects complete information about a specific process
 // This function centralizes the logic of obtaining process data, avoiding duplication
 //
@@ -38,6 +105,19 @@ func GetSystemMemoryTotal() (uint64, error) {
 //
 // Returns: filled ProcessInfo and error (if any)
 func GetProcessInfo(p *process.Process, totalSystemMem uint64) (*ProcessInfo, error) {
+	return getProcessInfo(p, totalSystemMem, p.CPUPercent)
+}
+
+// getProcessInfo collects information about a process with a given CPU usage source
+//
+// Parameters:
+//   - p: pointer to the process (gopsutil/process.Process)
+//   - totalSystemMem: total system memory in bytes (to calculate percentages)
+//   - cpuPercent: function returning the CPU usage of the process
+//     (lifetime average for fresh processes, incremental for reused ones)
+//
+// Returns: filled ProcessInfo and error (if any)
+func getProcessInfo(p *process.Process, totalSystemMem uint64, cpuPercent func() (float64, error)) (*ProcessInfo, error) {
 	// 1. Get the process PID
 	pid := p.Pid
 
@@ -49,12 +129,10 @@ func GetProcessInfo(p *process.Process, totalSystemMem uint64) (*ProcessInfo, er
 	}
 
 	// 3. Get CPU usage percentage
-	// CPUPercent() returns CPU utilization since the last call
-	// If it's the first call, it may return 0.0 or a not very accurate value
-	cpuPercent, err := p.CPUPercent()
+	cpuUsage, err := cpuPercent()
 	if err != nil {
 		// If there's an error getting CPU, don't fail - just assume 0%
-		cpuPercent = 0.0
+		cpuUsage = 0.0
 	}
 
 	// 4. Get memory usage information
@@ -69,16 +147,232 @@ func GetProcessInfo(p *process.Process, totalSystemMem uint64) (*ProcessInfo, er
 	rss := float64(memInfo.RSS)
 	ramPercentage := float32((rss / float64(totalSystemMem)) * 100)
 
-	// 6. Return structured process information
+	// 6. Get thread count and start time (optional, 0 if not available)
+	numThreads, _ := p.NumThreads()
+	createTime, _ := p.CreateTime()
+
+	// 7. Get last CPU core, scheduling state, nice value, parent, process group and session (optional)
+	// Read from /proc/<pid>/stat where it exists, since gopsutil's Nice returns the
+	// kernel priority (20 - nice) on Linux
+	state, nice := StateUnknown, int32(0)
+	var ppid, pgid, sid int32
+	var cpuTime float64
+	stat := procStat(pid)
+	if len(stat) > 16 {
+		state = stateFromLetter(stat[0])
+		if value, err := strconv.Atoi(stat[16]); err == nil {
+			nice = int32(value)
+		}
+		ppid, pgid, sid = statInt32(stat[1]), statInt32(stat[2]), statInt32(stat[3])
+		utime, _ := strconv.ParseUint(stat[11], 10, 64)
+		stime, _ := strconv.ParseUint(stat[12], 10, 64)
+		cpuTime = float64(utime+stime) / procfs.ClockTicks
+	} else {
+		if status, err := p.Status(); err == nil {
+			state = stateLetter(status)
+		}
+		nice, _ = p.Nice()
+		ppid, _ = p.Ppid()
+		if times, err := p.Times(); err == nil {
+			cpuTime = times.User + times.System
+		}
+	}
+
+	// 8. Return structured process information
 	return &ProcessInfo{
 		PID:           pid,
 		Name:          name,
-		CPUPercentage: cpuPercent,
+		CPUPercentage: cpuUsage,
 		RAMPercentage: ramPercentage,
 		RAMBytes:      memInfo.RSS,
+		LastCPU:       lastCPU(stat),
+		Username:      getUsername(p),
+		NumThreads:    numThreads,
+		CreateTime:    createTime,
+		CPUTime:       cpuTime,
+		State:         state,
+		Nice:          nice,
+		PPID:          ppid,
+		PGID:          pgid,
+		SID:           sid,
 	}, nil
 }
 
+// usernameCache maps user IDs to usernames, since looking them up reads /etc/passwd
+var (
+	usernameCache   = make(map[int32]string)
+	usernameCacheMu sync.Mutex
+)
+
+// getUsername gets the name of the user that owns a process
+// Falls back to the numeric UID if the user has no name (e.g. inside containers)
+//
+// Parameters:
+//   - p: pointer to the process
+//
+// Returns: username, or "?" if the owner can't be read
+func getUsername(p *process.Process) string {
+	uids, err := p.Uids()
+	if err != nil || len(uids) == 0 {
+		return "?"
+	}
+	return lookupUsername(uids[0]) // Real user ID
+}
+
+// lookupUsername gets the name of a user
+// Falls back to the numeric UID if the user has no name
+func lookupUsername(uid int32) string {
+	usernameCacheMu.Lock()
+	defer usernameCacheMu.Unlock()
+
+	if name, ok := usernameCache[uid]; ok {
+		return name
+	}
+
+	name := strconv.Itoa(int(uid))
+	if u, err := user.LookupId(name); err == nil {
+		name = u.Username
+	}
+	usernameCache[uid] = name
+	return name
+}
+
+// FilterProcessesByUser keeps only the processes owned by a user
+//
+// Parameters:
+//   - processes: slice of ProcessInfo to filter
+//   - username: owner to keep (empty = keep all)
+//
+// Returns: new slice with the matching processes (order is preserved)
+func FilterProcessesByUser(processes []ProcessInfo, username string) []ProcessInfo {
+	if username == "" {
+		return processes
+	}
+
+	filtered := make([]ProcessInfo, 0, len(processes))
+	for _, p := range processes {
+		if p.Username == username {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
+// FilterProcessesByName keeps only the processes with a name
+//
+// Parameters:
+//   - processes: slice of ProcessInfo to filter
+//   - name: exact process name to keep (e.g. "nginx")
+//
+// Returns: new slice with the matching processes, oldest first
+func FilterProcessesByName(processes []ProcessInfo, name string) []ProcessInfo {
+	var filtered []ProcessInfo
+	for _, p := range processes {
+		if p.Name == name {
+			filtered = append(filtered, p)
+		}
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].CreateTime < filtered[j].CreateTime
+	})
+	return filtered
+}
+
+// GetLastCPU gets the CPU core a process last ran on
+// Reads the "processor" field (39th) of /proc/<pid>/stat
+//
+// Parameters:
+//   - pid: process ID
+//
+// Returns: core number, or -1 if not available
+func GetLastCPU(pid int32) int {
+	return lastCPU(procStat(pid))
+}
+
+// GetCmdline gets the full command line of a process
+// Reads /proc/<pid>/cmdline, where the arguments are separated by NUL bytes, and joins them
+// with spaces without otherwise changing them
+//
+// Parameters:
+//   - pid: process ID
+//
+// Returns: command line, or empty for kernel threads and on systems without /proc
+func GetCmdline(pid int32) string {
+	data, err := os.ReadFile(paths.Proc(fmt.Sprint(pid), "cmdline"))
+	if err != nil {
+		return ""
+	}
+	return strings.ReplaceAll(strings.TrimRight(string(data), "\x00"), "\x00", " ")
+}
+
+// DescribeCmdline returns the full command line of a process for display, read with gopsutil
+// Kernel threads have none, so their name is shown in brackets like ps does, and a command
+// line hidden from the caller (e.g. /proc mounted with hidepid) is reported instead of left empty
+// Arguments can contain newlines and tabs, which are shown as spaces so they don't break tables
+//
+// Parameters:
+//   - p: process to describe
+//
+// Returns: command line, "[name]", "(not readable, run as root)" or "-" if the process is gone
+func DescribeCmdline(p ProcessInfo) string {
+	cmdline, err := (&process.Process{Pid: p.PID}).Cmdline()
+	switch {
+	case errors.Is(err, fs.ErrPermission):
+		return "(not readable, run as root)"
+	case err != nil:
+		return "-"
+	case cmdline == "":
+		return "[" + p.Name + "]"
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, cmdline)
+}
+
+// procStat reads the fields of /proc/<pid>/stat that follow the process name
+// The name (2nd field) may contain spaces, so the fields start after its closing parenthesis:
+// index 0 is the 3rd field (state)
+//
+// Returns: fields (nil if the file can't be read, e.g. on systems without /proc)
+func procStat(pid int32) []string {
+	data, err := os.ReadFile(paths.Proc(fmt.Sprint(pid), "stat"))
+	if err != nil {
+		return nil
+	}
+
+	stat := string(data)
+	end := strings.LastIndex(stat, ")")
+	if end < 0 {
+		return nil
+	}
+	return strings.Fields(stat[end+1:])
+}
+
+// statInt32 parses a numeric field of /proc/<pid>/stat (0 if invalid)
+func statInt32(field string) int32 {
+	value, err := strconv.ParseInt(field, 10, 32)
+	if err != nil {
+		return 0
+	}
+	return int32(value)
+}
+
+// lastCPU returns the "processor" field (39th, index 36) of the stat fields (-1 if missing)
+func lastCPU(fields []string) int {
+	if len(fields) <= 36 {
+		return -1
+	}
+
+	core, err := strconv.Atoi(fields[36])
+	if err != nil {
+		return -1
+	}
+	return core
+}
+
 // GetAllProcesses gets the list of all active processes in the system
 // This function is an optimized wrapper for process.Processes() with error handling
 //
@@ -106,195 +400,397 @@ func GetProcessByPID(pid int32) (*process.Process, error) {
 	return p, nil
 }
 
+// CollectionStats counts the items (processes, partitions) seen during a collection
+// and why the skipped ones couldn't be read
+type CollectionStats struct {
+	Shown   int            // Items whose information could be read
+	Skipped int            // Items that exist but couldn't be read (usually permissions)
+	Reasons map[string]int // Skipped items per reason (e.g. ReasonPermission)
+	Errors  []error        // First errors of the skipped items (at most maxSkippedLogged)
+}
+
+// String formats the statistics as "312 shown, 87 inaccessible"
+func (s CollectionStats) String() string {
+	return fmt.Sprintf("%d shown, %d inaccessible", s.Shown, s.Skipped)
+}
+
+// skippedLogInterval limits how often skipped process details are logged
+const skippedLogInterval = time.Minute
+
+// maxSkippedLogged is how many skipped processes are detailed in each debug message
+const maxSkippedLogged = 5
+
+// DefaultSampleInterval is the time between the two CPU snapshots of a sampled collection
+// Long enough to get meaningful deltas, short enough for one-shot commands
+const DefaultSampleInterval = 500 * time.Millisecond
+
+// CollectOptions controls how process information is collected
+type CollectOptions struct {
+	// SampleInterval is the time between two CPU snapshots used to compute per-process CPU usage
+	// 0 disables sampling: CPU usage is then the average over the whole process lifetime
+	SampleInterval time.Duration
+}
+
+// cpuSample is a snapshot of the CPU time used by a process
+type cpuSample struct {
+	total float64   // User + system CPU time in seconds
+	taken time.Time // When the snapshot was taken
+}
+
 // CollectAllProcessInfo collects complete information from all active processes
 // This is the main function that should be used by modules to get process data
 // Centralizes all iteration and error handling logic
+// CPU usage is measured over DefaultSampleInterval
 //
 // Returns: slice of ProcessInfo with all valid processes and error (if any)
 func CollectAllProcessInfo() ([]ProcessInfo, error) {
+	processes, _, err := CollectAllProcessInfoWithStats()
+	return processes, err
+}
+
+// CollectAllProcessInfoWithStats collects all active processes and counts the skipped ones
+// On hardened systems most processes may be unreadable, so views show the count
+// to avoid misleading totals
+// CPU usage is measured over DefaultSampleInterval
+//
+// Returns:
+//   - slice of ProcessInfo with all valid processes
+//   - CollectionStats with the number of shown and skipped processes
+//   - error (if any)
+func CollectAllProcessInfoWithStats() ([]ProcessInfo, CollectionStats, error) {
+	return CollectProcessInfo(CollectOptions{SampleInterval: DefaultSampleInterval})
+}
+
+// CollectProcessInfo collects all active processes with the given options
+// With a sample interval, two CPU snapshots are taken and each process gets its
+// real CPU usage over that interval (a fresh gopsutil Process only knows its lifetime average)
+//
+// Parameters:
+//   - options: collection options (e.g. sample interval)
+//
+// Returns:
+//   - slice of ProcessInfo with all valid processes
+//   - CollectionStats with the number of shown and skipped processes
+//   - error (if any)
+func CollectProcessInfo(options CollectOptions) ([]ProcessInfo, CollectionStats, error) {
+	if UsingProcfs() {
+		return collectProcfsSampled(options)
+	}
+
+	var stats CollectionStats
+
 	// 1. Get total system memory (we do this only once)
-	totalSystemMem, err := GetSystemMemoryTotal()
+	totalSystemMem, err := GetProcessMemoryTotal()
 	if err != nil {
-		return nil, err
+		return nil, stats, err
 	}
 
 	// 2. Get all active processes
 	allProcesses, err := GetAllProcesses()
 	if err != nil {
-		return nil, err
+		return nil, stats, err
 	}
 
-	// 3. Pre-allocate the slice with estimated capacity to avoid reallocations
+	// 3. Take the first CPU snapshot and wait for the sample interval
+	var firstSamples map[int32]cpuSample
+	if options.SampleInterval > 0 {
+		firstSamples = make(map[int32]cpuSample, len(allProcesses))
+		for _, p := range allProcesses {
+			if sample, ok := takeCPUSample(p); ok {
+				firstSamples[p.Pid] = sample
+			}
+		}
+		time.Sleep(options.SampleInterval)
+	}
+
+	// 4. Pre-allocate the slice with estimated capacity to avoid reallocations
 	processInfoList := make([]ProcessInfo, 0, len(allProcesses))
 
-	// 4. Iterate through each process and collect its statistics
+	// 5. Iterate through each process and collect its statistics
 	for _, p := range allProcesses {
 		// Try to get process information
 		info, err := GetProcessInfo(p, totalSystemMem)
 		if err != nil {
-			// If we can't get information, skip this process
-			// This is common for system processes or processes that have terminated in the meantime
+			// Processes that terminated in the meantime are not counted as skipped
+			if isProcessGone(err) {
+				continue
+			}
+
+			// The process exists but can't be read (usually system processes without root)
+			stats.Skip(err)
 			continue
 		}
 
+		// Replace the lifetime average with the usage over the sample interval
+		if first, ok := firstSamples[p.Pid]; ok {
+			if second, ok := takeCPUSample(p); ok {
+				info.CPUPercentage = cpuPercentBetween(first, second)
+			}
+		}
+
 		// Add process information to the list
 		processInfoList = append(processInfoList, *info)
 	}
+	stats.Shown = len(processInfoList)
 
-	return processInfoList, nil
-}
+	// 6. Report the skipped processes (--verbose), and log their details (at most once per interval)
+	ReportSkipped("processes", stats)
+	if stats.Skipped > 0 {
+		DebugEvery("skipped-processes", skippedLogInterval,
+			"process collection: %s; first errors: %v", stats, stats.Errors)
+	}
 
-// SortProcessesByField sorts a slice of ProcessInfo by a specific field
-// Uses a simple sorting algorithm (selection sort) to avoid external dependencies
-//
-// Parameters:
-//   - processes: slice of ProcessInfo to sort (is modified in-place)
-//   - field: field to sort by ("cpu", "ram", "pid", "name")
-//   - descending: true for descending order (largest -> smallest), false for ascending
-func SortProcessesByField(processes []ProcessInfo, field string, descending bool) {
-	n := len(processes)
-	if n <= 1 {
-		return // Nothing to sort
-	}
-
-	// Selection sort - simple and sufficient for most cases
-	for i := 0; i < n-1; i++ {
-		selectedIdx := i
-		for j := i + 1; j < n; j++ {
-			shouldSwap := false
-
-			// Determine if we should swap based on field and order
-			switch field {
-			case "cpu":
-				if descending {
-					shouldSwap = processes[j].CPUPercentage > processes[selectedIdx].CPUPercentage
-				} else {
-					shouldSwap = processes[j].CPUPercentage < processes[selectedIdx].CPUPercentage
-				}
-			case "ram":
-				if descending {
-					shouldSwap = processes[j].RAMPercentage > processes[selectedIdx].RAMPercentage
-				} else {
-					shouldSwap = processes[j].RAMPercentage < processes[selectedIdx].RAMPercentage
-				}
-			case "pid":
-				if descending {
-					shouldSwap = processes[j].PID > processes[selectedIdx].PID
-				} else {
-					shouldSwap = processes[j].PID < processes[selectedIdx].PID
-				}
-			case "name":
-				if descending {
-					shouldSwap = processes[j].Name > processes[selectedIdx].Name
-				} else {
-					shouldSwap = processes[j].Name < processes[selectedIdx].Name
-				}
-			}
+	return processInfoList, stats, nil
+}
 
-			if shouldSwap {
-				selectedIdx = j
-			}
-		}
+// isProcessGone checks if an error means the process terminated while it was being read
+func isProcessGone(err error) bool {
+	return errors.Is(err, process.ErrorProcessNotRunning) || errors.Is(err, os.ErrNotExist)
+}
 
-		// Swap elements if necessary
-		if selectedIdx != i {
-			processes[i], processes[selectedIdx] = processes[selectedIdx], processes[i]
-		}
+// takeCPUSample reads the CPU time used so far by a process
+//
+// Returns:
+//   - cpuSample with the total CPU time and when it was read
+//   - false if the CPU times can't be read
+func takeCPUSample(p *process.Process) (cpuSample, bool) {
+	times, err := p.Times()
+	if err != nil {
+		return cpuSample{}, false
 	}
+	return cpuSample{total: times.User + times.System, taken: time.Now()}, true
 }
 
-// TruncateString truncates a string to a maximum length
-// Adds "..." at the end if the string is truncated
+// cpuPercentBetween calculates the CPU usage of a process between two snapshots
+// Like top, 100% means one full core (can exceed 100% on multi-core systems)
 //
-// Parameters:
-//   - s: string to truncate
-//   - maxLen: maximum allowed length
-//
-// Returns: truncated string (if necessary)
-func TruncateString(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
-	}
-	if maxLen <= 3 {
-		return s[:maxLen] // If maxLen is too small, just cut
+// Returns: CPU usage percentage (0 if the snapshots are not in order)
+func cpuPercentBetween(first, second cpuSample) float64 {
+	elapsed := second.taken.Sub(first.taken).Seconds()
+	if elapsed <= 0 || second.total < first.total {
+		return 0
 	}
-	return s[:maxLen-3] + "..."
+	return (second.total - first.total) / elapsed * 100
 }
 
 // FormatBytes converts bytes to a readable string (MB, GB, etc.)
 // Useful for presenting memory sizes in a user-friendly way
+// Uses powers of 1024, or of 1000 when the "si" units are selected (see SetFormats)
 //
 // Parameters:
 //   - bytes: number of bytes to format
 //
 // Returns: formatted string (e.g. "256.5 MB", "1.2 GB")
 func FormatBytes(bytes uint64) string {
-	const (
-		KB = 1024
-		MB = KB * 1024
-		GB = MB * 1024
-		TB = GB * 1024
-	)
+	KB, kilo := 1024.0, "KB"
+	if byteUnits == UnitsSI {
+		KB, kilo = 1000, "kB"
+	}
+	MB := KB * KB
+	GB := MB * KB
+	TB := GB * KB
 
+	value := float64(bytes)
 	switch {
-	case bytes >= TB:
-		return fmt.Sprintf("%.2f TB", float64(bytes)/float64(TB))
-	case bytes >= GB:
-		return fmt.Sprintf("%.2f GB", float64(bytes)/float64(GB))
-	case bytes >= MB:
-		return fmt.Sprintf("%.2f MB", float64(bytes)/float64(MB))
-	case bytes >= KB:
-		return fmt.Sprintf("%.2f KB", float64(bytes)/float64(KB))
+	case value >= TB:
+		return fmt.Sprintf("%.2f TB", value/TB)
+	case value >= GB:
+		return fmt.Sprintf("%.2f GB", value/GB)
+	case value >= MB:
+		return fmt.Sprintf("%.2f MB", value/MB)
+	case value >= KB:
+		return fmt.Sprintf("%.2f %s", value/KB, kilo)
 	default:
 		return fmt.Sprintf("%d B", bytes)
 	}
 }
 
+// ParseBytes converts a size such as "2G", "512M" or "1.5GB" to bytes
+// Units are powers of 1024, like FormatBytes; a number without a unit is in bytes
+//
+// Parameters:
+//   - text: size to parse (case-insensitive)
+//
+// Returns:
+//   - number of bytes
+//   - error if the size is not a positive number with a known unit
+func ParseBytes(text string) (uint64, error) {
+	units := []struct {
+		suffix string
+		size   float64
+	}{
+		{"t", 1 << 40}, {"g", 1 << 30}, {"m", 1 << 20}, {"k", 1 << 10}, {"", 1},
+	}
+
+	number := strings.TrimSuffix(strings.TrimSuffix(strings.ToLower(strings.TrimSpace(text)), "b"), "i")
+	for _, unit := range units {
+		if !strings.HasSuffix(number, unit.suffix) {
+			continue
+		}
+		value, err := strconv.ParseFloat(strings.TrimSuffix(number, unit.suffix), 64)
+		if err != nil || value <= 0 {
+			return 0, fmt.Errorf("invalid size %q (e.g. \"512M\" or \"2G\")", text)
+		}
+		return uint64(value * unit.size), nil
+	}
+	return 0, fmt.Errorf("invalid size %q (e.g. \"512M\" or \"2G\")", text)
+}
+
+// ParseBytesOrZero parses a size like ParseBytes, but also accepts "0"
+// For settings where 0 turns a limit off (e.g. the minimum disk size)
+func ParseBytesOrZero(text string) (uint64, error) {
+	if strings.TrimSpace(text) == "0" {
+		return 0, nil
+	}
+	return ParseBytes(text)
+}
+
+// FormatCPUTime formats a CPU time in seconds as hours, minutes and seconds, like ps
+// e.g. "0:00:42" or "125:03:17" (hours are not split into days)
+//
+// Parameters:
+//   - seconds: CPU time in seconds
+//
+// Returns: formatted string as h:mm:ss
+func FormatCPUTime(seconds float64) string {
+	total := int64(seconds)
+	return fmt.Sprintf("%d:%02d:%02d", total/3600, total/60%60, total%60)
+}
+
+// FormatDuration formats a duration as "2d 3h 15m", "3h 15m" or "15m"
+//
+// Parameters:
+//   - d: duration to format
+//
+// Returns: formatted string with minute precision
+func FormatDuration(d time.Duration) string {
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh %dm", days, hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+// ErrProcessExited is returned by WatchProcess when the process is gone
+var ErrProcessExited = errors.New("process terminated or is not accessible")
+
 // MonitorProcessContinuously continuously monitors a specific process
-// Prints statistics at each specified interval until the process terminates or Ctrl+C
+// Prints a table row with its statistics at each specified interval until the process
+// terminates or Ctrl+C, then a summary of its CPU and RAM usage over the session;
+// timestamps and sizes follow the formats selected with SetFormats
 //
 // Parameters:
 //   - targetPID: PID of the process to monitor
 //   - intervalSeconds: interval between updates in seconds
 //
-// Returns: error if the process cannot be monitored
+// Returns: error if the process cannot be monitored (ErrProcessExited once it terminates)
 func MonitorProcessContinuously(targetPID int32, intervalSeconds int) error {
-	fmt.Printf("\n╔══════════════════════════════════════════════════════════════╗\n")
-	fmt.Printf("║  Monitoring process PID %d every %d seconds              ║\n", targetPID, intervalSeconds)
-	fmt.Printf("║  Press Ctrl+C to stop                                       ║\n")
-	fmt.Printf("╚══════════════════════════════════════════════════════════════╝\n\n")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	title := fmt.Sprintf("Monitoring process PID %d every %d seconds (Ctrl+C to stop)", targetPID, intervalSeconds)
+	summary := NewSessionSummary()
+	last, err := WatchProcess(ctx, targetPID, time.Duration(intervalSeconds)*time.Second, title, summary)
+	if last != nil {
+		summary.Print(fmt.Sprintf("%s (PID %d)", last.Name, targetPID))
+	}
+	if ctx.Err() != nil {
+		return nil // Stopped with Ctrl+C
+	}
+	return err
+}
 
+// WatchProcess prints a table row with the statistics of a process at each interval until it exits
+// A zombie, or a new process that reused the PID, counts as an exit
+//
+// Parameters:
+//   - ctx: stops the watch when it is done (e.g. on Ctrl+C)
+//   - targetPID: PID of the process to watch
+//   - interval: time between rows
+//   - title: title of the table
+//   - summary: receives the CPU and RAM usage of every row (nil = none)
+//
+// Returns:
+//   - last statistics read before the process exited (nil if it was never read)
+//   - error wrapping ErrProcessExited when it exits, the context's error when it is done,
+//     or why it couldn't be read
+func WatchProcess(ctx context.Context, targetPID int32, interval time.Duration, title string, summary *SessionSummary) (*ProcessInfo, error) {
 	// Get total system memory once
-	totalSystemMem, err := GetSystemMemoryTotal()
+	totalSystemMem, err := GetProcessMemoryTotal()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	// Columns grow to fit long names, large PIDs and RFC 3339 timestamps
+	table := NewTable(title,
+		Column{Header: "Time"},
+		Column{Header: "PID", Right: true},
+		Column{Header: "Name", Fill: true},
+		Column{Header: "S"},
+		Column{Header: "CPU %", Right: true},
+		Column{Header: "RAM %", Right: true, Optional: true},
+		Column{Header: "RAM", Right: true},
+		Column{Header: "Threads", Short: "Thr", Right: true, Optional: true},
+	)
+
 	// Infinite monitoring loop
+	var last *ProcessInfo
 	for {
 		// Get the process
 		p, err := GetProcessByPID(targetPID)
 		if err != nil {
-			return fmt.Errorf("process terminated or is not accessible: %w", err)
+			table.PrintEnd()
+			return last, fmt.Errorf("%w: %w", ErrProcessExited, err)
 		}
 
 		// Get process statistics
 		info, err := GetProcessInfo(p, totalSystemMem)
 		if err != nil {
-			return fmt.Errorf("error getting process statistics: %w", err)
+			table.PrintEnd()
+			if last != nil {
+				// It was readable a moment ago, so it exited in between
+				return last, fmt.Errorf("%w: %w", ErrProcessExited, err)
+			}
+			return nil, fmt.Errorf("error getting process statistics: %w", err)
+		}
+		if info.State == StateZombie || (last != nil && info.CreateTime != last.CreateTime) {
+			table.PrintEnd()
+			return last, ErrProcessExited
+		}
+		last = info
+
+		now := time.Now()
+		if summary != nil {
+			summary.Series("CPU", FormatSummaryPercent).Add(info.CPUPercentage, now)
+			summary.Series("RAM", FormatSummaryBytes).Add(float64(info.RAMBytes), now)
+			summary.Series("RAM %", FormatSummaryPercent).Add(float64(info.RAMPercentage), now)
 		}
 
 		// Print formatted statistics
-		timestamp := time.Now().Format("15:04:05")
-		fmt.Printf("┌─ [%s] ────────────────────────────────────────────────┐\n", timestamp)
-		fmt.Printf("│ PID:  %-50d │\n", info.PID)
-		fmt.Printf("│ Name: %-50s │\n", TruncateString(info.Name, 50))
-		fmt.Printf("│ CPU:  %-6.2f%% %-42s │\n", info.CPUPercentage, "")
-		fmt.Printf("│ RAM:  %-6.2f%% (%-36s) │\n", info.RAMPercentage, FormatBytes(info.RAMBytes))
-		fmt.Printf("└───────────────────────────────────────────────────────────┘\n\n")
+		table.PrintRow(
+			FormatTimestamp(now),
+			strconv.Itoa(int(info.PID)),
+			info.Name,
+			info.State,
+			fmt.Sprintf("%.2f%%", info.CPUPercentage),
+			fmt.Sprintf("%.2f%%", info.RAMPercentage),
+			FormatBytes(info.RAMBytes),
+			strconv.Itoa(int(info.NumThreads)))
 
 		// Wait for the specified interval before the next update
-		time.Sleep(time.Duration(intervalSeconds) * time.Second)
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			table.PrintEnd()
+			return last, ctx.Err()
+		}
 	}
 }
 
@@ -306,27 +802,54 @@ func MonitorProcessContinuously(targetPID int32, intervalSeconds int) error {
 //   - maxProcesses: maximum number of processes to show (0 = all)
 //   - title: table title
 func PrintProcessTable(processes []ProcessInfo, maxProcesses int, title string) {
+	PrintProcessTableWithOptions(processes, maxProcesses, title, TableOptions{})
+}
+
+// PrintProcessTableWithOptions prints a formatted table of processes with optional or chosen columns
+// The Name column shrinks to make room for the optional columns, and the table grows
+// when they don't fit (e.g. the process group and session IDs)
+//
+// Parameters:
+//   - processes: slice of ProcessInfo to print
+//   - maxProcesses: maximum number of processes to show (0 = all)
+//   - title: table title
+//   - options: columns to show (see TableOptions)
+func PrintProcessTableWithOptions(processes []ProcessInfo, maxProcesses int, title string, options TableOptions) {
 	// Limit to the requested number of processes
 	if maxProcesses > 0 && maxProcesses < len(processes) {
 		processes = processes[:maxProcesses]
 	}
 
-	// Print header
-	fmt.Printf("\n╔══════════════════════════════════════════════════════════════════════════════════╗\n")
-	fmt.Printf("║  %-80s  ║\n", title)
-	fmt.Printf("╠══════════════════════════════════════════════════════════════════════════════════╣\n")
-	fmt.Printf("║ %-8s │ %-30s │ %-10s │ %-10s │ %-12s ║\n", "PID", "Name", "CPU %", "RAM %", "RAM")
-	fmt.Printf("╠══════════════════════════════════════════════════════════════════════════════════╣\n")
+	columns := options.TableColumns()
+	headers := make([]Column, len(columns))
+	for i, c := range columns {
+		headers[i] = c.Column
+	}
+	table := NewTable(title, headers...)
 
-	// Print each process
 	for _, p := range processes {
-		fmt.Printf("║ %-8d │ %-30s │ %9.2f%% │ %9.2f%% │ %12s ║\n",
-			p.PID,
-			TruncateString(p.Name, 30),
-			p.CPUPercentage,
-			p.RAMPercentage,
-			FormatBytes(p.RAMBytes))
+		row := make([]string, len(columns))
+		for i, c := range columns {
+			row[i] = c.Cell(p)
+		}
+		table.AddRow(row...)
 	}
 
-	fmt.Printf("╚══════════════════════════════════════════════════════════════════════════════════╝\n")
+	table.Print()
+}
+
+// FormatID formats a process group or session ID for table columns ("-" if not available)
+func FormatID(id int32) string {
+	if id <= 0 {
+		return "-"
+	}
+	return strconv.Itoa(int(id))
+}
+
+// FormatCore formats a CPU core number for table columns ("-" if not available)
+func FormatCore(core int) string {
+	if core < 0 {
+		return "-"
+	}
+	return strconv.Itoa(core)
 }
@@ -0,0 +1,206 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Action is something a key does in the interactive TUI
+type Action string
+
+// TUI actions that can be bound to keys in the "keys" section of the config file
+const (
+	ActionQuit            Action = "quit"             // Exit the TUI
+	ActionUp              Action = "up"               // Select the previous process
+	ActionDown            Action = "down"             // Select the next process
+	ActionRefresh         Action = "refresh"          // Refresh the process list now
+	ActionPause           Action = "pause"            // Pause/resume the automatic refresh
+	ActionSortCPU         Action = "sort_cpu"         // Sort by CPU usage
+	ActionSortRAM         Action = "sort_ram"         // Sort by RAM usage
+	ActionSortPID         Action = "sort_pid"         // Sort by PID
+	ActionSortNext        Action = "sort_next"        // Cycle through all sort modes
+	ActionToggleCore      Action = "toggle_core"      // Show/hide the CPU core column
+	ActionToggleGroups    Action = "toggle_groups"    // Show/hide the process group and session ID columns
+	ActionToggleApps      Action = "toggle_apps"      // Group the processes of each application into one row
+	ActionToggleContainer Action = "toggle_container" // Show/hide the container column
+	ActionToggleSwap      Action = "toggle_swap"      // Show/hide the swap column
+	ActionToggleIO        Action = "toggle_io"        // Show/hide the disk I/O column
+	ActionToggleEnergy    Action = "toggle_energy"    // Show/hide the estimated power column
+	ActionToggleGraphs    Action = "toggle_graphs"    // Show/hide the CPU, RAM and temperature graphs
+	ActionToggleCmdline   Action = "toggle_cmdline"   // Show the full command line instead of the name
+	ActionScrollLeft      Action = "scroll_left"      // Scroll the name/command column left
+	ActionScrollRight     Action = "scroll_right"     // Scroll the name/command column right
+	ActionSplit           Action = "split"            // Cycle the second pane: network, process details, none
+	ActionFocus           Action = "focus"            // Move the focus between the process table and the second pane
+	ActionNextTab         Action = "next_tab"         // Switch to the next tab
+	ActionPrevTab         Action = "prev_tab"         // Switch to the previous tab
+	ActionTabProcesses    Action = "tab_processes"    // Switch to the Processes tab
+	ActionTabCPU          Action = "tab_cpu"          // Switch to the CPU tab
+	ActionTabMemory       Action = "tab_memory"       // Switch to the Memory tab
+	ActionTabDisk         Action = "tab_disk"         // Switch to the Disk tab
+	ActionTabNetwork      Action = "tab_network"      // Switch to the Network tab
+	ActionTabGPU          Action = "tab_gpu"          // Switch to the GPU tab
+	ActionTabSensors      Action = "tab_sensors"      // Switch to the Sensors tab
+	ActionTabConnections  Action = "tab_connections"  // Switch to the Connections tab
+	ActionThrottle        Action = "throttle"         // Limit the CPU and memory of the selected process
+	ActionCopy            Action = "copy"             // Copy the details of the selected process to the clipboard (or a file)
+	ActionNiceUp          Action = "nice_up"          // Raise the nice value of the selected process (lower priority)
+	ActionNiceDown        Action = "nice_down"        // Lower the nice value of the selected process (higher priority)
+	ActionStop            Action = "stop"             // Stop the selected process (SIGSTOP), or continue it if stopped (SIGCONT)
+	ActionKill            Action = "kill"             // Kill the selected process
+	ActionKillGroup       Action = "kill_group"       // Kill the process group of the selected process (after confirmation)
+	ActionFanAuto         Action = "fan_auto"         // Give the selected fan back to automatic control (Sensors tab)
+	ActionFilter          Action = "filter"           // Type a filter for the connections (Connections tab)
+	ActionPin             Action = "pin"              // Pin the name of the selected process to the top of the list (or unpin it)
+	ActionMark            Action = "mark"             // Mark the selected process for the bulk kill and renice (or unmark it)
+)
+
+// DefaultKeys returns the built-in key bindings of every action
+// Key names are single characters (letters are case-insensitive) or named keys (see namedKeys)
+func DefaultKeys() map[Action][]string {
+	return map[Action][]string{
+		ActionQuit:            {"q", "esc"},
+		ActionUp:              {"up"},
+		ActionDown:            {"down"},
+		ActionRefresh:         {"f5", "r"},
+		ActionPause:           {"l"},
+		ActionSortCPU:         {"c"},
+		ActionSortRAM:         {"m"},
+		ActionSortPID:         {"p"},
+		ActionSortNext:        {"s"},
+		ActionToggleCore:      {"o"},
+		ActionToggleGroups:    {"e"},
+		ActionToggleApps:      {"u"},
+		ActionToggleContainer: {"n"},
+		ActionToggleSwap:      {"w"},
+		ActionToggleIO:        {"i"},
+		ActionToggleEnergy:    {"b"},
+		ActionToggleGraphs:    {"g"},
+		ActionToggleCmdline:   {"a"},
+		ActionScrollLeft:      {"shift+left", "ctrl+left"},
+		ActionScrollRight:     {"shift+right", "ctrl+right"},
+		ActionSplit:           {"v"},
+		ActionFocus:           {"f"},
+		ActionNextTab:         {"tab"},
+		ActionPrevTab:         {"shift+tab"},
+		ActionTabProcesses:    {"1"},
+		ActionTabCPU:          {"2"},
+		ActionTabMemory:       {"3"},
+		ActionTabDisk:         {"4"},
+		ActionTabNetwork:      {"5"},
+		ActionTabGPU:          {"6"},
+		ActionTabSensors:      {"7"},
+		ActionTabConnections:  {"8"},
+		ActionThrottle:        {"t"},
+		ActionCopy:            {"y"},
+		ActionNiceUp:          {"+"},
+		ActionNiceDown:        {"-"},
+		ActionStop:            {"z"},
+		ActionKill:            {"d", "delete", "backspace"},
+		ActionKillGroup:       {"x"},
+		ActionFanAuto:         {"h"},
+		ActionFilter:          {"/"},
+		ActionPin:             {"*"},
+		ActionMark:            {"space"},
+	}
+}
+
+// namedKeys are the non-printable keys that can be bound
+var namedKeys = map[string]bool{
+	"up": true, "down": true, "left": true, "right": true,
+	"shift+left": true, "shift+right": true, "ctrl+left": true, "ctrl+right": true, "alt+left": true, "alt+right": true,
+	"home": true, "end": true, "pgup": true, "pgdown": true,
+	"enter": true, "tab": true, "shift+tab": true, "space": true, "esc": true, "backspace": true, "delete": true,
+	"f1": true, "f2": true, "f3": true, "f4": true, "f5": true, "f6": true,
+	"f7": true, "f8": true, "f9": true, "f10": true, "f11": true, "f12": true,
+}
+
+// NormalizeKey returns the canonical name of a key
+// Named keys are lowercase ("F9" → "f9") and letters are case-insensitive ("Q" → "q")
+func NormalizeKey(key string) string {
+	return strings.ToLower(key)
+}
+
+// validKey checks if a key name can be bound (a single printable character or a named key)
+func validKey(key string) bool {
+	runes := []rune(key)
+	if len(runes) == 1 {
+		return runes[0] > ' ' && runes[0] != 127
+	}
+	return namedKeys[key]
+}
+
+// Keymap merges the configured key bindings with the defaults
+// An action listed in the config file replaces its default keys; an empty list disables it
+//
+// Returns: map of normalized key name to action
+func (c Config) Keymap() map[string]Action {
+	keymap := make(map[string]Action)
+	for action := range DefaultKeys() {
+		for _, key := range c.KeysFor(action) {
+			keymap[key] = action
+		}
+	}
+	return keymap
+}
+
+// KeysFor returns the keys bound to an action, in the configured order
+//
+// Parameters:
+//   - action: TUI action (e.g. ActionKill)
+//
+// Returns: normalized key names (empty if the action is disabled)
+func (c Config) KeysFor(action Action) []string {
+	keys, ok := c.Keys[string(action)]
+	if !ok {
+		keys = DefaultKeys()[action]
+	}
+
+	normalized := make([]string, 0, len(keys))
+	for _, key := range keys {
+		normalized = append(normalized, NormalizeKey(key))
+	}
+	return normalized
+}
+
+// validateKeys checks the "keys" section: known actions, valid key names and no key bound twice
+//
+// Returns: error listing every problem found
+func (c Config) validateKeys() error {
+	defaults := DefaultKeys()
+	var problems []string
+
+	// 1. Unknown actions and invalid key names
+	for action, keys := range c.Keys {
+		if _, ok := defaults[Action(action)]; !ok {
+			problems = append(problems, fmt.Sprintf("unknown action %q", action))
+			continue
+		}
+		for _, key := range keys {
+			if !validKey(NormalizeKey(key)) {
+				problems = append(problems, fmt.Sprintf("invalid key %q for %s", key, action))
+			}
+		}
+	}
+
+	// 2. Keys bound to more than one action (including the defaults that weren't changed)
+	owners := make(map[string][]string)
+	for action := range defaults {
+		for _, key := range c.KeysFor(action) {
+			owners[key] = append(owners[key], string(action))
+		}
+	}
+	for key, actions := range owners {
+		if len(actions) > 1 {
+			sort.Strings(actions)
+			problems = append(problems, fmt.Sprintf("key %q is bound to %s", key, strings.Join(actions, " and ")))
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("keys: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,363 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/disk"
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+)
+
+// Commands that can run when gom is started without arguments
+const (
+	CommandDefault  = "default"  // neofetch-style logo and system summary
+	CommandTUI      = "tui"      // Interactive TUI (same as -f)
+	CommandOverview = "overview" // Complete system overview (same as -a)
+)
+
+// Config contains the user settings read from the config file
+type Config struct {
+	DefaultCommand  string              `json:"default_command"`  // Command to run without arguments (CommandDefault, CommandTUI or CommandOverview)
+	HistoryEnabled  bool                `json:"history_enabled"`  // Record alerts and notable events in the history store
+	RefreshInterval Duration            `json:"refresh_interval"` // How often the TUI refreshes automatically (e.g. "2s")
+	GraphHistory    Duration            `json:"graph_history"`    // How far back the TUI graphs go (e.g. "5m")
+	Theme           string              `json:"theme"`            // Color theme (default, monochrome, solarized or high-contrast)
+	Units           string              `json:"units"`            // Units of sizes: binary (1024, default) or si (1000)
+	TimeFormat      string              `json:"time_format"`      // Timestamps of continuous output: 24h (default), 12h or rfc3339
+	Backend         string              `json:"backend"`          // Collection backend: gopsutil (default) or procfs (overridden by GOM_BACKEND)
+	CommandTimeout  Duration            `json:"command_timeout"`  // How long collectors wait for external programs such as nvidia-smi (e.g. "5s")
+	Disk            DiskConfig          `json:"disk"`             // Mounts listed by the disk views, the summaries and the disk alerts
+	Throttle        ThrottleConfig      `json:"throttle"`         // Limits applied by the TUI throttle action
+	WhenLocked      LockedConfig        `json:"when_locked"`      // Sampling of the TUI while the screen is locked or idle
+	FanControl      bool                `json:"fan_control"`      // Allow setting fan levels in the Sensors tab of the TUI (off by default)
+	Mouse           bool                `json:"mouse"`            // Select, scroll, sort and switch tabs with the mouse in the TUI (on by default)
+	PublicIPURL     string              `json:"public_ip_url"`    // Endpoint gom --net-info asks for the public IP (e.g. "https://api.ipify.org", empty to skip)
+	Pinned          []string            `json:"pinned"`           // Process names always listed first in the TUI (changed with the pin key)
+	Keys            map[string][]string `json:"keys"`             // TUI key bindings per action, replacing the defaults (e.g. {"kill": ["f9"]})
+	Alerts          AlertRules          `json:"alerts"`           // Changes to the built-in alert rules per metric (e.g. {"cpu": {"threshold": 90}})
+	Health          HealthConfig        `json:"health"`           // How much each reading counts in the health score
+}
+
+// ThrottleConfig contains the limits the TUI throttle action puts a process under
+type ThrottleConfig struct {
+	CPU    float64 `json:"cpu"`    // CPU time in cores (0 for no CPU limit)
+	Memory string  `json:"memory"` // Memory limit such as "4G" (empty for no memory limit)
+}
+
+// MemoryBytes returns the memory limit in bytes (0 if not set or invalid)
+func (t ThrottleConfig) MemoryBytes() uint64 {
+	if t.Memory == "" {
+		return 0
+	}
+	bytes, _ := common.ParseBytes(t.Memory)
+	return bytes
+}
+
+// DiskConfig contains which mounts are listed, on top of the built-in filters
+// (virtual file systems, snaps and partitions under 2G are hidden by default)
+type DiskConfig struct {
+	IncludeFsTypes []string `json:"include_fstypes"` // Hidden file system types to list anyway (e.g. ["squashfs"])
+	ExcludeMounts  []string `json:"exclude_mounts"`  // Mountpoints to hide, with everything below them (e.g. ["/mnt/backup"])
+	MinSize        string   `json:"min_size"`        // Smallest partition listed such as "500M" ("0" for every size, empty for 2G)
+}
+
+// Filter returns the disk filter of the settings (the default minimum size if min_size is invalid)
+func (d DiskConfig) Filter() disk.Filter {
+	filter := disk.DefaultFilter()
+	filter.IncludeFsTypes = d.IncludeFsTypes
+	filter.ExcludeMounts = d.ExcludeMounts
+	if size, err := common.ParseBytesOrZero(d.MinSize); err == nil && d.MinSize != "" {
+		filter.MinSize = size
+	}
+	return filter
+}
+
+// LockedConfig contains how the TUI samples while the session is locked or idle,
+// to save battery on laptops left running GoMonitor
+type LockedConfig struct {
+	Enabled         bool     `json:"enabled"`          // Watch the session state (systemd-logind)
+	RefreshInterval Duration `json:"refresh_interval"` // Refresh interval while locked or idle (e.g. "30s")
+	PauseGPU        bool     `json:"pause_gpu"`        // Stop polling the GPUs while locked or idle
+}
+
+// AlertRules are the changes to the built-in alert rules, keyed by metric name (e.g. "disk")
+type AlertRules map[string]AlertRule
+
+// AlertRule changes a built-in alert rule in the "alerts" section of the config file
+// Settings that are left out keep the built-in values
+type AlertRule struct {
+	Threshold  *float64           `json:"threshold"`  // Value at or above which the alert fires
+	Duration   *Duration          `json:"duration"`   // How long the value must stay above the threshold (e.g. "2m")
+	Thresholds map[string]float64 `json:"thresholds"` // Per-target thresholds (e.g. {"/data": 97} for disk)
+	Disabled   bool               `json:"disabled"`   // Turns the rule off
+}
+
+// Components of the health score, as named in the "health" section of the config file
+const (
+	HealthCPU         = "cpu"         // Global CPU usage
+	HealthMemory      = "memory"      // RAM that can't be reclaimed
+	HealthDisk        = "disk"        // Usage of the fullest mount
+	HealthTemperature = "temperature" // CPU temperature
+	HealthAlerts      = "alerts"      // Alerts firing
+)
+
+// HealthComponents lists the components of the health score, in display order
+var HealthComponents = []string{HealthCPU, HealthMemory, HealthDisk, HealthTemperature, HealthAlerts}
+
+// defaultHealthWeights is how much each component counts when the config file doesn't say
+// Running out of memory hurts the most, a hot CPU the least (it throttles itself)
+var defaultHealthWeights = map[string]float64{
+	HealthCPU:         2,
+	HealthMemory:      3,
+	HealthDisk:        2,
+	HealthTemperature: 1,
+	HealthAlerts:      2,
+}
+
+// HealthConfig contains the weights of the health score components
+type HealthConfig struct {
+	Weights map[string]float64 `json:"weights"` // Weight per component (e.g. {"temperature": 0} to leave it out)
+}
+
+// ComponentWeights returns the weight of every component, the configured ones replacing the defaults
+func (h HealthConfig) ComponentWeights() map[string]float64 {
+	weights := make(map[string]float64, len(defaultHealthWeights))
+	for name, weight := range defaultHealthWeights {
+		weights[name] = weight
+	}
+	for name, weight := range h.Weights {
+		weights[name] = weight
+	}
+	return weights
+}
+
+// Duration is a time.Duration written as text in the config file (e.g. "2s", "500ms")
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalJSON parses a duration string such as "2s"
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("duration must be a string such as \"2s\": %w", err)
+	}
+
+	duration, err := time.ParseDuration(text)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", text, err)
+	}
+	d.Duration = duration
+	return nil
+}
+
+// MarshalJSON writes the duration as a string such as "2s"
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// minRefreshInterval is the fastest allowed TUI refresh, since each refresh reads every process
+const minRefreshInterval = 250 * time.Millisecond
+
+// minCommandTimeout is the shortest allowed command timeout, since nvidia-smi takes a few
+// hundred milliseconds even when it works
+const minCommandTimeout = 500 * time.Millisecond
+
+// Default returns the settings used when there's no config file
+func Default() Config {
+	return Config{
+		DefaultCommand:  CommandDefault,
+		RefreshInterval: Duration{2 * time.Second},
+		GraphHistory:    Duration{5 * time.Minute},
+		Throttle:        ThrottleConfig{CPU: 1},
+		CommandTimeout:  Duration{common.DefaultCommandTimeout},
+		WhenLocked:      LockedConfig{RefreshInterval: Duration{30 * time.Second}, PauseGPU: true},
+		Mouse:           true,
+	}
+}
+
+// Path returns the location of the config file
+// Usually ~/.config/gomonitor/config.json (see paths.ConfigDir for the overrides)
+//
+// Returns:
+//   - absolute path of the config file
+//   - error if the config directory can't be determined
+func Path() (string, error) {
+	return paths.ConfigFile("config.json")
+}
+
+// Load reads the config file
+// Settings missing from the file keep their default values
+//
+// Returns:
+//   - Config with the user settings (Default() if the file doesn't exist)
+//   - error if the file can't be read, parsed or contains invalid values
+func Load() (Config, error) {
+	cfg := Default()
+
+	path, err := Path()
+	if err != nil {
+		return cfg, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("error reading config file %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Default(), fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Default(), fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// SavePinned writes the pinned process names to the config file
+// Only the "pinned" setting is replaced: the other settings keep their values (in alphabetical
+// order) and settings left at their defaults stay out of the file
+//
+// Parameters:
+//   - names: process names to pin (nil or empty removes the setting)
+//
+// Returns: error if the file can't be read, parsed or written, or in read-only mode
+func SavePinned(names []string) error {
+	if paths.ReadOnly() {
+		return errors.New("read-only mode, the config file can't be changed")
+	}
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+
+	// 1. Read the current settings (an empty file if there's none yet)
+	settings := make(map[string]json.RawMessage)
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("error reading config file %s: %w", path, err)
+	default:
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return fmt.Errorf("error parsing config file %s: %w", path, err)
+		}
+	}
+
+	// 2. Replace the pinned names
+	if len(names) == 0 {
+		delete(settings, "pinned")
+	} else {
+		pinned, err := json.Marshal(names)
+		if err != nil {
+			return err
+		}
+		settings["pinned"] = pinned
+	}
+
+	// 3. Write to a temporary file first, so an error can't leave half a config file
+	data, err = json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("error creating config directory: %w", err)
+	}
+	temp := path + ".tmp"
+	if err := os.WriteFile(temp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("error writing config file: %w", err)
+	}
+	return os.Rename(temp, path)
+}
+
+// Validate checks that all settings have supported values
+//
+// Returns: error describing the first invalid setting
+func (c Config) Validate() error {
+	switch c.DefaultCommand {
+	case CommandDefault, CommandTUI, CommandOverview:
+	default:
+		return fmt.Errorf("default_command must be %q, %q or %q, got %q",
+			CommandDefault, CommandTUI, CommandOverview, c.DefaultCommand)
+	}
+
+	if c.RefreshInterval.Duration < minRefreshInterval {
+		return fmt.Errorf("refresh_interval must be at least %s, got %s", minRefreshInterval, c.RefreshInterval)
+	}
+
+	if c.GraphHistory.Duration < c.RefreshInterval.Duration {
+		return fmt.Errorf("graph_history must be at least refresh_interval (%s), got %s", c.RefreshInterval, c.GraphHistory)
+	}
+
+	if c.Throttle.CPU < 0 {
+		return fmt.Errorf("throttle.cpu must be a positive number of cores, got %g", c.Throttle.CPU)
+	}
+	if c.Throttle.Memory != "" {
+		if _, err := common.ParseBytes(c.Throttle.Memory); err != nil {
+			return fmt.Errorf("throttle.memory: %w", err)
+		}
+	}
+	if c.Throttle.CPU == 0 && c.Throttle.Memory == "" {
+		return fmt.Errorf("throttle needs a cpu or memory limit")
+	}
+
+	if c.Disk.MinSize != "" {
+		if _, err := common.ParseBytesOrZero(c.Disk.MinSize); err != nil {
+			return fmt.Errorf("disk.min_size: %w", err)
+		}
+	}
+
+	if err := common.ValidateFormats(c.Units, c.TimeFormat); err != nil {
+		return err
+	}
+
+	if err := common.ValidateBackend(c.Backend); err != nil {
+		return err
+	}
+
+	if c.CommandTimeout.Duration < minCommandTimeout {
+		return fmt.Errorf("command_timeout must be at least %s, got %s", minCommandTimeout, c.CommandTimeout)
+	}
+
+	if c.PublicIPURL != "" {
+		if u, err := url.Parse(c.PublicIPURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("public_ip_url must be an http or https URL, got %q", c.PublicIPURL)
+		}
+	}
+
+	if c.WhenLocked.RefreshInterval.Duration < minRefreshInterval {
+		return fmt.Errorf("when_locked.refresh_interval must be at least %s, got %s", minRefreshInterval, c.WhenLocked.RefreshInterval)
+	}
+
+	for metric, rule := range c.Alerts {
+		if rule.Threshold != nil && *rule.Threshold < 0 {
+			return fmt.Errorf("alerts.%s.threshold must not be negative, got %g", metric, *rule.Threshold)
+		}
+		if rule.Duration != nil && rule.Duration.Duration < 0 {
+			return fmt.Errorf("alerts.%s.duration must not be negative, got %s", metric, rule.Duration)
+		}
+	}
+
+	for name, weight := range c.Health.Weights {
+		if _, ok := defaultHealthWeights[name]; !ok {
+			return fmt.Errorf("health.weights: unknown component %q (use cpu, memory, disk, temperature or alerts)", name)
+		}
+		if weight < 0 {
+			return fmt.Errorf("health.weights.%s must not be negative, got %g", name, weight)
+		}
+	}
+
+	return c.validateKeys()
+}
@@ -0,0 +1,149 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+)
+
+// engineSockets are the API sockets of Docker and Podman (both speak the Docker API)
+var engineSockets = []string{
+	"/var/run/docker.sock",
+	"/run/podman/podman.sock",
+}
+
+// engineTimeout limits how long an unresponsive engine can delay the listing
+const engineTimeout = time.Second
+
+// engineContainer is the part of the /containers/json response that is used
+type engineContainer struct {
+	ID     string            `json:"Id"`
+	Names  []string          `json:"Names"`
+	Image  string            `json:"Image"`
+	Labels map[string]string `json:"Labels"`
+}
+
+// Labels of the deployable unit a container belongs to, kept from the engine's labels
+const (
+	LabelPodName        = "io.kubernetes.pod.name"      // Kubernetes pod
+	LabelPodNamespace   = "io.kubernetes.pod.namespace" // Namespace of the Kubernetes pod
+	LabelComposeProject = "com.docker.compose.project"  // Docker Compose project
+	LabelComposeService = "com.docker.compose.service"  // Service of the Docker Compose project
+)
+
+// keptLabels are the engine labels copied to Metadata (the others are often long or many)
+var keptLabels = []string{LabelPodName, LabelPodNamespace, LabelComposeProject, LabelComposeService}
+
+// Metadata is what a container engine reports about a container
+type Metadata struct {
+	Name   string            `json:"name,omitempty"`   // Container name
+	Image  string            `json:"image,omitempty"`  // Image the container was started from (e.g. "nginx:1.25")
+	Labels map[string]string `json:"labels,omitempty"` // Pod and compose labels (see keptLabels)
+}
+
+// Unit describes the deployable unit of the container from its labels
+// e.g. "pod default/web-0" or "compose shop/api" (empty if it has none)
+func (m Metadata) Unit() string {
+	switch {
+	case m.Labels[LabelPodName] != "":
+		if namespace := m.Labels[LabelPodNamespace]; namespace != "" {
+			return "pod " + namespace + "/" + m.Labels[LabelPodName]
+		}
+		return "pod " + m.Labels[LabelPodName]
+	case m.Labels[LabelComposeProject] != "":
+		if service := m.Labels[LabelComposeService]; service != "" {
+			return "compose " + m.Labels[LabelComposeProject] + "/" + service
+		}
+		return "compose " + m.Labels[LabelComposeProject]
+	}
+	return ""
+}
+
+// Origin describes where the container comes from: its image and deployable unit
+// e.g. "nginx:1.25, compose shop/web" (empty if neither is known)
+func (m Metadata) Origin() string {
+	var parts []string
+	if m.Image != "" {
+		parts = append(parts, m.Image)
+	}
+	if unit := m.Unit(); unit != "" {
+		parts = append(parts, unit)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// engineMetadata asks the container engines about the running containers
+// Reading the sockets usually requires root or membership in the docker group;
+// without access the containers are shown by short ID, with no image or labels
+//
+// Returns: map of full container ID to its metadata (empty if no engine is reachable)
+func engineMetadata() map[string]Metadata {
+	metadata := make(map[string]Metadata)
+	for _, socket := range engineSockets {
+		containers, err := queryEngine(socket)
+		if err != nil {
+			common.Logger().Debug("container engine not reachable", "socket", socket, "err", err)
+			continue
+		}
+		for _, c := range containers {
+			var m Metadata
+			if len(c.Names) > 0 {
+				m.Name = strings.TrimPrefix(c.Names[0], "/")
+			}
+			m.Image = c.Image
+			for _, label := range keptLabels {
+				if value := c.Labels[label]; value != "" {
+					if m.Labels == nil {
+						m.Labels = make(map[string]string)
+					}
+					m.Labels[label] = value
+				}
+			}
+			metadata[c.ID] = m
+		}
+	}
+	return metadata
+}
+
+// queryEngine lists the running containers through a Docker API socket
+//
+// Parameters:
+//   - socket: path of the unix socket
+//
+// Returns:
+//   - containers reported by the engine
+//   - error if the socket can't be reached or the response can't be decoded
+func queryEngine(socket string) ([]engineContainer, error) {
+	client := http.Client{
+		Timeout: engineTimeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", socket)
+			},
+		},
+	}
+
+	// The host is ignored: every request goes through the socket
+	resp, err := client.Get("http://engine/containers/json")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+
+	var containers []engineContainer
+	if err := json.NewDecoder(resp.Body).Decode(&containers); err != nil {
+		return nil, err
+	}
+	return containers, nil
+}
@@ -0,0 +1,241 @@
+package container
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/cgroup"
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// Runtime identifies the container engine that started a container
+type Runtime string
+
+const (
+	RuntimeDocker     Runtime = "docker"
+	RuntimePodman     Runtime = "podman"
+	RuntimeContainerd Runtime = "containerd"
+	RuntimeCRIO       Runtime = "cri-o"
+)
+
+// Container contains the resource usage of a running container
+type Container struct {
+	ID          string            // Full container ID (64 hex characters)
+	Name        string            // Container name (empty if the engine API is not reachable)
+	Image       string            // Image the container was started from (empty if the engine API is not reachable)
+	Labels      map[string]string // Pod and compose labels reported by the engine (see Metadata)
+	Runtime     Runtime           // Engine that started the container
+	PIDs        []int32           // Processes running in the container
+	CPUPercent  float64           // CPU usage over the sample interval (can exceed 100% on multi-core systems)
+	MemoryUsage uint64            // Working set in bytes
+	MemoryLimit uint64            // Memory limit in bytes (0 if unlimited)
+	CPULimit    float64           // CPU quota in cores (0 if unlimited)
+	ReadBytes   uint64            // Bytes read from block devices since the container started
+	WriteBytes  uint64            // Bytes written to block devices since the container started
+	paths       map[string]string // cgroup paths of the container's processes
+}
+
+// idPattern matches the container ID in a cgroup path of a runtime
+type idPattern struct {
+	runtime Runtime
+	pattern *regexp.Regexp
+}
+
+// idPatterns recognize container cgroups, with the cgroupfs and systemd drivers
+// e.g. "/docker/<id>", "/system.slice/docker-<id>.scope", "/machine.slice/libpod-<id>.scope",
+// "/kubepods.slice/.../cri-containerd-<id>.scope", "/kubepods/burstable/pod<uid>/<id>"
+var idPatterns = []idPattern{
+	{RuntimeDocker, regexp.MustCompile(`/docker[-/]([0-9a-f]{64})(?:\.scope)?$`)},
+	{RuntimePodman, regexp.MustCompile(`/libpod-([0-9a-f]{64})(?:\.scope)?(?:/container)?$`)},
+	{RuntimeCRIO, regexp.MustCompile(`/crio-([0-9a-f]{64})(?:\.scope)?$`)},
+	{RuntimeContainerd, regexp.MustCompile(`/cri-containerd-([0-9a-f]{64})(?:\.scope)?$`)},
+	{RuntimeContainerd, regexp.MustCompile(`/kubepods[^ ]*/([0-9a-f]{64})$`)},
+}
+
+// Detect checks if a cgroup path belongs to a container
+//
+// Parameters:
+//   - cgroupPath: cgroup path of a process (e.g. "/system.slice/docker-4f1c...e2.scope")
+//
+// Returns:
+//   - runtime of the container
+//   - full container ID
+//   - false if the path isn't a container cgroup
+func Detect(cgroupPath string) (Runtime, string, bool) {
+	for _, p := range idPatterns {
+		if match := p.pattern.FindStringSubmatch(cgroupPath); match != nil {
+			return p.runtime, match[1], true
+		}
+	}
+	return "", "", false
+}
+
+// detectPaths checks the cgroup paths of a process for a container
+// With cgroup v1 the memory or cpu hierarchy is used, with v2 the unified one
+func detectPaths(paths map[string]string) (Runtime, string, bool) {
+	for _, controller := range []string{"", "memory", "cpu", "pids"} {
+		if path, ok := paths[controller]; ok {
+			if runtime, id, ok := Detect(path); ok {
+				return runtime, id, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// Of gets the container a process runs in
+//
+// Parameters:
+//   - pid: process ID
+//
+// Returns:
+//   - runtime of the container
+//   - full container ID
+//   - false if the process isn't in a container (or its cgroup can't be read)
+func Of(pid int32) (Runtime, string, bool) {
+	paths, err := cgroup.Paths(pid)
+	if err != nil {
+		return "", "", false
+	}
+	return detectPaths(paths)
+}
+
+// List finds every running container and measures its resource usage
+// CPU usage is measured from the cgroup CPU time over common.DefaultSampleInterval
+//
+// Returns:
+//   - slice of Container sorted by CPU usage (descending)
+//   - error if the process list can't be read
+func List() ([]Container, error) {
+	// 1. Group the processes by container
+	pids, err := process.Pids()
+	if err != nil {
+		return nil, fmt.Errorf("error getting processes: %w", err)
+	}
+
+	byID := make(map[string]*Container)
+	for _, pid := range pids {
+		paths, err := cgroup.Paths(pid)
+		if err != nil {
+			continue
+		}
+		runtime, id, ok := detectPaths(paths)
+		if !ok {
+			continue
+		}
+
+		c, ok := byID[id]
+		if !ok {
+			c = &Container{ID: id, Runtime: runtime, paths: paths}
+			byID[id] = c
+		}
+		c.PIDs = append(c.PIDs, pid)
+	}
+
+	containers := make([]Container, 0, len(byID))
+	for _, c := range byID {
+		containers = append(containers, *c)
+	}
+
+	// 2. Measure CPU time over the sample interval
+	before := make([]cgroup.Usage, len(containers))
+	for i := range containers {
+		before[i] = cgroup.GetUsage(containers[i].paths)
+	}
+	start := time.Now()
+	time.Sleep(common.DefaultSampleInterval)
+	elapsed := time.Since(start)
+
+	metadata := engineMetadata()
+	for i := range containers {
+		c := &containers[i]
+		after := cgroup.GetUsage(c.paths)
+
+		c.Name, c.Image, c.Labels = metadata[c.ID].Name, metadata[c.ID].Image, metadata[c.ID].Labels
+		c.CPUPercent = float64(after.CPUTime-before[i].CPUTime) / float64(elapsed) * 100
+		c.MemoryUsage = after.MemoryUsage
+		c.ReadBytes = after.ReadBytes
+		c.WriteBytes = after.WriteBytes
+		if limits, err := cgroup.Get(c.PIDs[0]); err == nil {
+			c.MemoryLimit = limits.MemoryLimit
+			c.CPULimit = limits.CPULimit
+		}
+	}
+
+	sort.SliceStable(containers, func(i, j int) bool {
+		return containers[i].CPUPercent > containers[j].CPUPercent
+	})
+
+	return containers, nil
+}
+
+// ShortID returns the first 12 characters of a container ID, as shown by docker ps
+func ShortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+// DisplayName returns the container name, or its short ID if the name is unknown
+func (c Container) DisplayName() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return ShortID(c.ID)
+}
+
+// Metadata returns what the engine reported about the container
+func (c Container) Metadata() Metadata {
+	return Metadata{Name: c.Name, Image: c.Image, Labels: c.Labels}
+}
+
+// LimitUsage returns the usage of the container against its CPU quota and memory limit
+func (c Container) LimitUsage() cgroup.LimitUsage {
+	return cgroup.LimitUsage{
+		Name:        c.DisplayName(),
+		CPUPercent:  c.CPUPercent,
+		CPULimit:    c.CPULimit,
+		MemoryUsage: c.MemoryUsage,
+		MemoryLimit: c.MemoryLimit,
+	}
+}
+
+// PrintContainers prints the running containers in a formatted table
+//
+// Parameters:
+//   - containers: slice of Container to present
+func PrintContainers(containers []Container) {
+	table := common.NewTable("Containers",
+		common.Column{Header: "Name", Fill: true}, common.Column{Header: "Runtime", Optional: true},
+		common.Column{Header: "CPU", Right: true}, common.Column{Header: "Memory"},
+		common.Column{Header: "Read", Right: true, Optional: true}, common.Column{Header: "Written", Right: true, Optional: true})
+
+	if len(containers) == 0 {
+		table.AddNote("No running containers found")
+	}
+
+	for _, c := range containers {
+		memory := common.FormatBytes(c.MemoryUsage)
+		if c.MemoryLimit > 0 {
+			memory += " / " + common.FormatBytes(c.MemoryLimit)
+		}
+
+		table.AddRow(common.TruncateString(c.DisplayName(), 12),
+			string(c.Runtime),
+			fmt.Sprintf("%.1f%%", c.CPUPercent),
+			memory,
+			common.FormatBytes(c.ReadBytes),
+			common.FormatBytes(c.WriteBytes))
+
+		// Image and deployable unit below the usage, so the numbers map to what was deployed
+		if origin := c.Metadata().Origin(); origin != "" {
+			table.AddNote(" ↳ " + origin)
+		}
+	}
+
+	table.Print()
+}
@@ -0,0 +1,93 @@
+package container
+
+import "github.com/dfialho05/GoMonitor/application/pck/common"
+
+// Resolver maps processes to the containers they run in, for long-running views
+// The container of each process is cached (keyed by PID and start time) and the engine
+// metadata is cached per container, so a refresh only reads the cgroup of new processes
+// and only queries the engines when a new container appears
+type Resolver struct {
+	processes map[int32]resolvedProcess // Container of each known process
+	metadata  map[string]Metadata       // Name, image and labels of each known container ID
+}
+
+// resolvedProcess is the cached container of a process
+type resolvedProcess struct {
+	createTime int64  // Start time, to detect reused PIDs
+	id         string // Container ID (empty if the process runs on the host)
+}
+
+// NewResolver creates a resolver with empty caches
+//
+// Returns: pointer to a configured Resolver
+func NewResolver() *Resolver {
+	return &Resolver{
+		processes: make(map[int32]resolvedProcess),
+		metadata:  make(map[string]Metadata),
+	}
+}
+
+// Resolve gets the container of every process
+//
+// Parameters:
+//   - processes: current process list
+//
+// Returns: map of PID to container name or short ID (processes on the host are not included)
+func (r *Resolver) Resolve(processes []common.ProcessInfo) map[int32]string {
+	resolved := make(map[int32]resolvedProcess, len(processes))
+	newContainer := false
+
+	// 1. Find the container of new processes
+	for _, p := range processes {
+		cached, ok := r.processes[p.PID]
+		if !ok || cached.createTime != p.CreateTime {
+			cached = resolvedProcess{createTime: p.CreateTime}
+			if _, id, ok := Of(p.PID); ok {
+				cached.id = id
+			}
+		}
+		if _, known := r.metadata[cached.id]; cached.id != "" && !known {
+			newContainer = true
+		}
+		resolved[p.PID] = cached
+	}
+	r.processes = resolved
+
+	// 2. Ask the engines about the containers only when one appeared
+	if newContainer {
+		metadata := engineMetadata()
+		for _, cached := range resolved {
+			if cached.id != "" {
+				r.metadata[cached.id] = metadata[cached.id]
+			}
+		}
+	}
+
+	// 3. Label the processes, forgetting containers that stopped
+	labels := make(map[int32]string)
+	metadata := make(map[string]Metadata, len(r.metadata))
+	for pid, cached := range resolved {
+		if cached.id == "" {
+			continue
+		}
+		metadata[cached.id] = r.metadata[cached.id]
+		labels[pid] = Container{ID: cached.id, Name: r.metadata[cached.id].Name}.DisplayName()
+	}
+	r.metadata = metadata
+	return labels
+}
+
+// Container returns the container of a process and what the engine reported about it
+// Only processes of the last Resolve are known
+//
+// Returns:
+//   - full container ID
+//   - name, image and labels of the container
+//   - false if the process runs on the host or wasn't resolved
+func (r *Resolver) Container(pid int32) (string, Metadata, bool) {
+	cached, ok := r.processes[pid]
+	if !ok || cached.id == "" {
+		return "", Metadata{}, false
+	}
+	return cached.id, r.metadata[cached.id], true
+}
@@ -0,0 +1,321 @@
+package cpu
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+	"github.com/shirou/gopsutil/v3/cpu"
+)
+
+// CoreStats contains usage and frequency information for a single logical core
+type CoreStats struct {
+	ID         int     // Logical CPU number (as in /sys/devices/system/cpu/cpuN)
+	Usage      float64 // Core usage percentage (0-100%)
+	CurrentMHz float64 // Current core frequency in MHz (0 if not available)
+	MaxMHz     float64 // Maximum core frequency in MHz (0 if not available)
+}
+
+// ClusterStats groups cores of the same type on heterogeneous CPUs
+// (ARM big.LITTLE, Apple P/E cores, Intel hybrid P/E cores)
+type ClusterStats struct {
+	Label      string      // Cluster label (e.g. "Performance", "Efficiency")
+	Cores      []CoreStats // Cores belonging to this cluster
+	Usage      float64     // Average usage of the cluster cores (0-100%)
+	AvgMHz     float64     // Average current frequency of the cluster cores in MHz
+	MaxMHz     float64     // Highest maximum frequency among the cluster cores in MHz
+	clusterKey int         // Value used to group and rank the cores (capacity or frequency)
+}
+
+// cpuSysfsPath returns the base sysfs path for per-CPU topology and frequency information
+func cpuSysfsPath() string {
+	return paths.Sys("devices", "system", "cpu")
+}
+
+// minClusterFrequencyGap is how much lower (as a fraction) the maximum frequency of a core
+// must be to put it in a slower cluster when there is no core type or capacity information
+// Homogeneous CPUs with favored cores (Turbo Boost Max 3.0, Ryzen preferred cores) have
+// per-core maximum frequencies a few percent apart, which must not look like P/E cores
+const minClusterFrequencyGap = 0.2
+
+// IsHeterogeneous checks if the CPU has more than one type of core
+// This only reads sysfs and doesn't sample usage, so it's cheap to call
+//
+// Returns:
+//   - true if cores can be grouped in more than one cluster
+func IsHeterogeneous() bool {
+	keys := getCoreClusterKeys()
+	seen := make(map[int]struct{})
+	for _, key := range keys {
+		seen[key] = struct{}{}
+	}
+	return len(seen) > 1
+}
+
+// GetClusterStats collects per-core usage and frequency grouped by cluster
+// Cores are grouped by type (Intel hybrid PMU), CPU capacity (ARM) or maximum frequency,
+// and clusters are ordered from the most to the least powerful
+// Waits 1 second to get an accurate per-core usage reading
+//
+// Returns:
+//   - slice of ClusterStats (a single cluster on homogeneous CPUs)
+//   - error if unable to get the per-core usage
+func GetClusterStats() ([]ClusterStats, error) {
+	// 1. Get usage of each logical core
+	perCore, err := cpu.Percent(time.Second, true)
+	if err != nil {
+		return nil, fmt.Errorf("error getting per-core CPU usage: %w", err)
+	}
+
+	// 2. Group cores by their cluster key
+	keys := getCoreClusterKeys()
+	clustersByKey := make(map[int]*ClusterStats)
+	for id, usage := range perCore {
+		key := keys[id]
+		cluster, ok := clustersByKey[key]
+		if !ok {
+			cluster = &ClusterStats{clusterKey: key}
+			clustersByKey[key] = cluster
+		}
+
+		cluster.Cores = append(cluster.Cores, CoreStats{
+			ID:         id,
+			Usage:      usage,
+			CurrentMHz: readCoreFrequency(id, "scaling_cur_freq"),
+			MaxMHz:     readCoreFrequency(id, "cpuinfo_max_freq"),
+		})
+	}
+
+	// 3. Order clusters from the most to the least powerful
+	clusters := make([]ClusterStats, 0, len(clustersByKey))
+	for _, cluster := range clustersByKey {
+		clusters = append(clusters, *cluster)
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].clusterKey > clusters[j].clusterKey
+	})
+
+	// 4. Compute summaries and labels
+	for i := range clusters {
+		summarizeCluster(&clusters[i])
+		clusters[i].Label = clusterLabel(i, len(clusters))
+	}
+
+	return clusters, nil
+}
+
+// summarizeCluster computes average usage and frequency summaries for a cluster
+func summarizeCluster(cluster *ClusterStats) {
+	var totalUsage, totalMHz float64
+	for _, core := range cluster.Cores {
+		totalUsage += core.Usage
+		totalMHz += core.CurrentMHz
+		if core.MaxMHz > cluster.MaxMHz {
+			cluster.MaxMHz = core.MaxMHz
+		}
+	}
+
+	if len(cluster.Cores) > 0 {
+		cluster.Usage = totalUsage / float64(len(cluster.Cores))
+		cluster.AvgMHz = totalMHz / float64(len(cluster.Cores))
+	}
+}
+
+// clusterLabel returns a readable label based on the cluster rank
+// Clusters are ranked from the most (0) to the least powerful
+func clusterLabel(rank, total int) string {
+	switch {
+	case total == 1:
+		return "All Cores"
+	case rank == 0:
+		return "Performance"
+	case rank == total-1:
+		return "Efficiency"
+	default:
+		return fmt.Sprintf("Mid %d", rank)
+	}
+}
+
+// getCoreClusterKeys returns a grouping key for every logical core
+// Cores with the same key belong to the same cluster, and a higher key means a faster core
+//
+// Detection order:
+//  1. Intel hybrid CPUs expose P-cores and E-cores as separate PMUs (cpu_core / cpu_atom)
+//  2. ARM CPUs (including Apple Silicon on Asahi Linux) expose a relative cpu_capacity
+//  3. Otherwise the maximum frequency of each core, split only where it drops by more
+//     than minClusterFrequencyGap
+//
+// Returns:
+//   - map with logical CPU number as key and cluster key as value
+func getCoreClusterKeys() map[int]int {
+	keys := make(map[int]int)
+
+	// 1. Intel hybrid: P-cores get a higher key than E-cores
+	pCores := readCPUList(paths.Sys("bus", "event_source", "devices", "cpu_core", "cpus"))
+	eCores := readCPUList(paths.Sys("bus", "event_source", "devices", "cpu_atom", "cpus"))
+	if len(pCores) > 0 && len(eCores) > 0 {
+		for _, id := range pCores {
+			keys[id] = 2
+		}
+		for _, id := range eCores {
+			keys[id] = 1
+		}
+		return keys
+	}
+
+	// 2. and 3. Per-core capacity or maximum frequency
+	cpuDirs, err := filepath.Glob(filepath.Join(cpuSysfsPath(), "cpu[0-9]*"))
+	if err != nil {
+		return keys
+	}
+
+	frequencies := make(map[int]int)
+	for _, dir := range cpuDirs {
+		id, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(dir), "cpu"))
+		if err != nil {
+			continue
+		}
+
+		if capacity, err := readSysfsInt(filepath.Join(dir, "cpu_capacity")); err == nil {
+			keys[id] = capacity
+			continue
+		}
+
+		if maxFreq, err := readSysfsInt(filepath.Join(dir, "cpufreq", "cpuinfo_max_freq")); err == nil {
+			frequencies[id] = maxFreq
+		}
+	}
+	if len(keys) > 0 {
+		return keys
+	}
+
+	return groupByFrequency(frequencies)
+}
+
+// groupByFrequency groups cores by maximum frequency, starting a slower cluster only
+// where the frequency drops by more than minClusterFrequencyGap from the next faster core
+//
+// Parameters:
+//   - frequencies: maximum frequency of each logical CPU (kHz)
+//
+// Returns:
+//   - map with logical CPU number as key and cluster key (the fastest frequency of its cluster) as value
+func groupByFrequency(frequencies map[int]int) map[int]int {
+	distinct := make([]int, 0, len(frequencies))
+	seen := make(map[int]bool)
+	for _, frequency := range frequencies {
+		if !seen[frequency] {
+			seen[frequency] = true
+			distinct = append(distinct, frequency)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(distinct)))
+
+	// Key of each distinct frequency: the fastest frequency of its cluster
+	clusterOf := make(map[int]int, len(distinct))
+	for i, frequency := range distinct {
+		if i > 0 && float64(frequency) >= float64(distinct[i-1])*(1-minClusterFrequencyGap) {
+			clusterOf[frequency] = clusterOf[distinct[i-1]]
+			continue
+		}
+		clusterOf[frequency] = frequency
+	}
+
+	keys := make(map[int]int, len(frequencies))
+	for id, frequency := range frequencies {
+		keys[id] = clusterOf[frequency]
+	}
+	return keys
+}
+
+// readCoreFrequency reads a cpufreq value (in kHz) for a core and converts it to MHz
+//
+// Parameters:
+//   - id: logical CPU number
+//   - file: cpufreq file name (e.g. "scaling_cur_freq", "cpuinfo_max_freq")
+//
+// Returns:
+//   - frequency in MHz (0 if not available)
+func readCoreFrequency(id int, file string) float64 {
+	path := filepath.Join(cpuSysfsPath(), fmt.Sprintf("cpu%d", id), "cpufreq", file)
+	kHz, err := readSysfsInt(path)
+	if err != nil {
+		return 0
+	}
+	return float64(kHz) / 1000
+}
+
+// readSysfsInt reads a single integer value from a sysfs file
+func readSysfsInt(path string) (int, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(buf)))
+}
+
+// readCPUList parses a kernel CPU list file (e.g. "0-7,16-19")
+//
+// Returns:
+//   - slice with all logical CPU numbers in the list (empty if the file doesn't exist)
+func readCPUList(path string) []int {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var ids []int
+	for _, part := range strings.Split(strings.TrimSpace(string(buf)), ",") {
+		bounds := strings.SplitN(part, "-", 2)
+		start, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			continue
+		}
+
+		end := start
+		if len(bounds) == 2 {
+			if end, err = strconv.Atoi(bounds[1]); err != nil {
+				continue
+			}
+		}
+
+		for id := start; id <= end; id++ {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// PrintClusterStats prints per-cluster summaries followed by the cores of each cluster
+//
+// Parameters:
+//   - clusters: slice of ClusterStats to present
+func PrintClusterStats(clusters []ClusterStats) {
+	box := common.NewBox("CPU Clusters")
+
+	for i, cluster := range clusters {
+		if i > 0 {
+			box.Divider()
+		}
+
+		summary := fmt.Sprintf("%s (%d cores)", cluster.Label, len(cluster.Cores))
+		box.Field("Cluster", summary)
+		box.Fieldf("Usage", "%.2f %%", cluster.Usage)
+		box.Fieldf("Avg Frequency", "%.0f MHz", cluster.AvgMHz)
+		box.Fieldf("Max Frequency", "%.0f MHz", cluster.MaxMHz)
+
+		for _, core := range cluster.Cores {
+			label := fmt.Sprintf("%c-core %d", cluster.Label[0], core.ID)
+			box.Text(fmt.Sprintf("  %-14s%6.2f%% @ %.0f MHz", label, core.Usage, core.CurrentMHz))
+		}
+	}
+
+	box.End()
+}
@@ -0,0 +1,54 @@
+package cpu
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/capability"
+	"github.com/shirou/gopsutil/v3/host"
+	"github.com/shirou/gopsutil/v3/load"
+)
+
+// LoadStats contains the system load average and uptime
+type LoadStats struct {
+	Load1   float64       // Load average over the last minute
+	Load5   float64       // Load average over the last 5 minutes
+	Load15  float64       // Load average over the last 15 minutes
+	HasLoad bool          // False if the OS doesn't provide a load average (e.g. Windows)
+	Uptime  time.Duration // Time since the system booted
+}
+
+// GetLoadStats gets the load average and uptime of the system
+// Both values are cheap to read, so this is safe to call on every TUI refresh
+//
+// Returns:
+//   - LoadStats filled with load average and uptime
+//   - error if the uptime can't be read
+func GetLoadStats() (LoadStats, error) {
+	var stats LoadStats
+
+	// 1. Load average (not implemented on every OS, so it's optional)
+	if avg, err := load.Avg(); err == nil {
+		stats.Load1 = avg.Load1
+		stats.Load5 = avg.Load5
+		stats.Load15 = avg.Load15
+		stats.HasLoad = true
+	}
+
+	// 2. Uptime
+	uptime, err := host.Uptime()
+	if err != nil {
+		return stats, fmt.Errorf("error getting system uptime: %w", err)
+	}
+	stats.Uptime = time.Duration(uptime) * time.Second
+
+	return stats, nil
+}
+
+// FormatLoad formats the load averages as "0.52 0.48 0.40"
+func (s LoadStats) FormatLoad() string {
+	if !s.HasLoad {
+		return capability.Unsupported()
+	}
+	return fmt.Sprintf("%.2f %.2f %.2f", s.Load1, s.Load5, s.Load15)
+}
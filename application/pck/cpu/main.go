@@ -2,81 +2,215 @@ package cpu
 
 import (
 	"fmt"
-	"os"
-	"strconv"
-	"strings"
+	"sync"
 	"time"
 
 	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/hwcache"
+	"github.com/dfialho05/GoMonitor/application/pck/procfs"
+	"github.com/dfialho05/GoMonitor/application/pck/sensors"
 	"github.com/shirou/gopsutil/v3/cpu"
 )
 
 // GeneralStats contains general information about the system CPU
 // This structure aggregates static data (model, cores) and dynamic data (current usage)
 type GeneralStats struct {
-	Percentage  float64 // Global CPU usage percentage (0-100%)
-	Cores       int     // Number of physical CPU cores
-	ClockSpeed  float64 // Clock speed in MHz
-	ModelName   string  // CPU model name (e.g. "Intel Core i7-8550U")
-	VendorID    string  // Vendor identifier (e.g. "GenuineIntel", "AuthenticAMD")
-	Microcode   string  // CPU microcode version
-	CacheSize   int32   // CPU cache size in KB
-	Flags       string  // CPU flags/capabilities (e.g. "sse", "avx", "aes")
-	Temperature int     // CPU temperature in degrees Celsius (0 if not available)
+	Percentage  float64        // Global CPU usage percentage (0-100%), of the CPU quota if CPULimit is set
+	CPULimit    float64        // CPU quota of the container in cores the usage is relative to (0 = host CPUs)
+	HostPercent float64        // Usage of the host CPUs (0-100%), only set with CPULimit
+	Cores       int            // Number of physical CPU cores
+	ClockSpeed  float64        // Clock speed in MHz
+	ModelName   string         // CPU model name (e.g. "Intel Core i7-8550U")
+	VendorID    string         // Vendor identifier (e.g. "GenuineIntel", "AuthenticAMD")
+	Microcode   string         // CPU microcode version
+	CacheSize   int32          // CPU cache size in KB
+	Flags       string         // CPU flags/capabilities (e.g. "sse", "avx", "aes")
+	Temperature int            // CPU temperature in degrees Celsius (0 if not available)
+	Load        LoadStats      // Load average and system uptime
+	Breakdown   TimeBreakdown  // Share of each CPU mode (user, system, iowait, ...) over the measured second
+	Throttle    ThrottleStatus // Current frequency, turbo and throttling over the measured second
 }
 
 // GetGeneralStats collects general information about the system CPU
 // This function aggregates static data (model, cores, cache) and dynamic data (current usage)
 // Similar to the output of 'lscpu' command
+// The static data comes from hwcache, so only the usage is sampled on every call
+// Inside a container with a CPU quota the usage is relative to the quota (see cgroup.Effective)
 //
 // Returns:
-//   - GeneralStats filled with CPU information
-//   - error if unable to get the information
+//   - GeneralStats filled with CPU information (static fields empty if they can't be read)
+//   - error if unable to get the CPU usage
 func GetGeneralStats() (GeneralStats, error) {
 	// 1. Get global CPU usage percentage
 	// Wait 1 second to get an accurate reading
 	// false = return only one global value (average of all cores)
-	cpuPercent, err := cpu.Percent(time.Second, false)
+	// The CPU times are read around the same second for the breakdown by mode (optional),
+	// and the throttle counters to detect throttling
+	// Under a CPU quota the CPU time of the cgroup is read around the same second
+	var quotaBefore quotaSample
+	cores := quotaCores()
+	if cores > 0 {
+		var err error
+		if quotaBefore, err = readQuotaSample(); err != nil {
+			cores = 0
+		}
+	}
+	timesBefore, timesErr := readTimes()
+	throttleBefore := readThrottleSample()
+	cpuPercent, err := percent(time.Second)
 	if err != nil {
 		return GeneralStats{}, fmt.Errorf("error getting CPU usage percentage: %w", err)
 	}
+	var breakdown TimeBreakdown
+	if timesAfter, err := readTimes(); err == nil && timesErr == nil {
+		breakdown = breakdownBetween(timesBefore, timesAfter)
+	}
 
 	// Extract the first (and only) value from the slice
 	percentage := 0.0
 	if len(cpuPercent) > 0 {
 		percentage = cpuPercent[0]
 	}
+	throttle := throttleBetween(throttleBefore, readThrottleSample(), percentage)
+	hostPercent := 0.0
+	if cores > 0 {
+		if quotaAfter, err := readQuotaSample(); err == nil {
+			percentage, hostPercent = quotaPercent(quotaBefore, quotaAfter, cores), percentage
+		} else {
+			cores = 0
+		}
+	}
+
+	// 2. Get static CPU information (read once and kept by hwcache)
+	static := hwcache.Get().CPU
+
+	// 3. Initialize the return structure with usage percentage and the static fields
+	stats := GeneralStats{
+		Percentage:  percentage,
+		CPULimit:    cores,
+		HostPercent: hostPercent,
+		Breakdown:   breakdown,
+		Throttle:    throttle,
+		ModelName:   static.Model,
+		Cores:       static.Cores,
+		ClockSpeed:  static.MHz,
+		VendorID:    static.Vendor,
+		Microcode:   static.Microcode,
+		CacheSize:   static.CacheKB,
+		Flags:       static.Flags,
+	}
+
+	// 4. Get CPU temperature
+	stats.Temperature = GetTemperature()
+
+	// 5. Get load average and uptime (optional, not available on every OS)
+	if loadStats, err := GetLoadStats(); err == nil {
+		stats.Load = loadStats
+	}
+
+	return stats, nil
+}
+
+// GetSystemPercent gets the global CPU usage since the previous call, without blocking
+// The first call only records a baseline and returns an error
+// Used by the TUI, which samples on every refresh instead of waiting 1 second
+// Inside a container with a CPU quota the usage is relative to the quota, like GetGeneralStats
+//
+// Returns:
+//   - global CPU usage percentage (0-100%)
+//   - error if there's no previous sample yet or the CPU times can't be read
+func GetSystemPercent() (float64, error) {
+	if cores := quotaCores(); cores > 0 {
+		return systemQuotaPercent(cores)
+	}
+	cpuPercent, err := percent(0)
+	if err != nil {
+		return 0, fmt.Errorf("error getting CPU usage percentage: %w", err)
+	}
+	if len(cpuPercent) == 0 {
+		return 0, fmt.Errorf("error getting CPU usage percentage: no data")
+	}
+	return cpuPercent[0], nil
+}
+
+// GetPerCorePercent returns the usage of each logical CPU since the previous call
+// Like GetSystemPercent, the first call only records a baseline and returns an error
+// Used by the CPU tab of the TUI, which samples on every refresh
+//
+// Returns:
+//   - usage percentage of each logical CPU (0-100%), in CPU number order
+//   - error if there's no previous sample yet or the CPU times can't be read
+func GetPerCorePercent() ([]float64, error) {
+	if !common.UsingProcfs() {
+		cpuPercent, err := cpu.Percent(0, true)
+		if err != nil {
+			return nil, fmt.Errorf("error getting per-core CPU usage: %w", err)
+		}
+		if len(cpuPercent) == 0 {
+			return nil, fmt.Errorf("error getting per-core CPU usage: no data")
+		}
+		return cpuPercent, nil
+	}
 
-	// 2. Get static CPU information
-	cpuInfo, err := cpu.Info()
+	procfsLastMu.Lock()
+	defer procfsLastMu.Unlock()
+
+	after, err := procfs.ReadPerCPUTimes()
 	if err != nil {
-		return GeneralStats{}, fmt.Errorf("error getting CPU information: %w", err)
+		return nil, fmt.Errorf("error getting per-core CPU usage: %w", err)
+	}
+	before := procfsLastPerCore
+	procfsLastPerCore = after
+	if len(before) != len(after) {
+		return nil, fmt.Errorf("error getting per-core CPU usage: no previous sample")
 	}
 
-	// 3. Initialize the return structure with usage percentage
-	stats := GeneralStats{
-		Percentage: percentage,
+	cpuPercent := make([]float64, len(after))
+	for i := range after {
+		cpuPercent[i] = procfs.BusyPercent(before[i], after[i])
 	}
+	return cpuPercent, nil
+}
+
+// procfsLast and procfsLastPerCore are the previous readings of /proc/stat of
+// GetSystemPercent and GetPerCorePercent (procfs backend)
+var (
+	procfsLast        *procfs.CPUTimes
+	procfsLastPerCore []procfs.CPUTimes
+	procfsLastMu      sync.Mutex
+)
 
-	// 4. Fill static fields if information is available
-	// Normally the cpuInfo slice contains one entry per logical core,
-	// but they all have the same static information, so we use the first one
-	if len(cpuInfo) > 0 {
-		info := cpuInfo[0]
-		stats.ModelName = info.ModelName
-		stats.Cores = int(info.Cores)
-		stats.ClockSpeed = info.Mhz // gopsutil uses 'Mhz' for frequency in MHz
-		stats.VendorID = info.VendorID
-		stats.Microcode = info.Microcode
-		stats.CacheSize = info.CacheSize
-		// Join flags into a space-separated string
-		stats.Flags = strings.Join(info.Flags, " ")
+// percent measures the global CPU usage with the active backend, like cpu.Percent(interval, false)
+// An interval of 0 compares with the previous call (the first call returns no data)
+//
+// Returns: slice with the global usage (empty if there's no previous reading yet)
+func percent(interval time.Duration) ([]float64, error) {
+	if !common.UsingProcfs() {
+		return cpu.Percent(interval, false)
 	}
 
-	// 5. Get CPU temperature
-	stats.Temperature = getCPUTemperature()
+	procfsLastMu.Lock()
+	defer procfsLastMu.Unlock()
 
-	return stats, nil
+	before := procfsLast
+	if interval > 0 {
+		times, err := procfs.ReadCPUTimes()
+		if err != nil {
+			return nil, err
+		}
+		before = &times
+		time.Sleep(interval)
+	}
+
+	after, err := procfs.ReadCPUTimes()
+	if err != nil {
+		return nil, err
+	}
+	procfsLast = &after
+	if before == nil {
+		return nil, nil
+	}
+	return []float64{procfs.BusyPercent(*before, after)}, nil
 }
 
 // GetProcessStats collects CPU information for all active processes
@@ -106,25 +240,45 @@ func GetProcessStats() ([]common.ProcessInfo, error) {
 // Parameters:
 //   - stats: GeneralStats structure with data to present
 func PrintGeneralStats(stats GeneralStats) {
-	fmt.Printf("\n╔══════════════════════════════════════════════════════════════════════════════════╗\n")
-	fmt.Printf("║  %-80s  ║\n", "General CPU Information")
-	fmt.Printf("╠══════════════════════════════════════════════════════════════════════════════════╣\n")
-	fmt.Printf("║  Model:           %-62s  ║\n", common.TruncateString(stats.ModelName, 62))
-	fmt.Printf("║  Vendor:          %-62s  ║\n", stats.VendorID)
-	fmt.Printf("║  Cores:           %-62d  ║\n", stats.Cores)
-	fmt.Printf("║  Frequency:       %-58.2f MHz  ║\n", stats.ClockSpeed)
-	fmt.Printf("║  Current Usage:   %-58.2f %%    ║\n", stats.Percentage)
-	fmt.Printf("║  Cache:           %-58d KB  ║\n", stats.CacheSize)
-	fmt.Printf("║  Microcode:       %-62s  ║\n", stats.Microcode)
+	box := common.NewBox("General CPU Information")
+	box.Field("Model", stats.ModelName)
+	box.Field("Vendor", stats.VendorID)
+	box.Fieldf("Cores", "%d", stats.Cores)
+	box.Fieldf("Frequency", "%.2f MHz", stats.ClockSpeed)
+	if stats.Throttle.Valid {
+		box.Field("Current Clock", stats.Throttle.FormatFrequency())
+	}
+	if stats.Throttle.Turbo != TurboUnknown {
+		box.Field("Turbo Boost", stats.Throttle.Turbo.String())
+	}
+	if stats.Throttle.HasEvents {
+		box.Fieldf("Throttle Events", "%d since boot (%d in the last second)", stats.Throttle.TotalEvents, stats.Throttle.Events)
+	}
+	if stats.CPULimit > 0 {
+		box.Fieldf("CPU Quota", "%.2f cores (cgroup limit, --host for the host CPUs)", stats.CPULimit)
+		box.Fieldf("Current Usage", "%.2f %% of the quota (host: %.2f %%)", stats.Percentage, stats.HostPercent)
+	} else {
+		box.Fieldf("Current Usage", "%.2f %%", stats.Percentage)
+	}
+	if stats.Breakdown.Valid {
+		modes := stats.Breakdown.Modes()
+		box.Field("Time by Mode", stats.Breakdown.Bar(common.NarrowWidth(58)))
+		box.Field("", FormatModes(modes[:3]))
+		box.Field("", FormatModes(modes[3:]))
+	}
+	box.Fieldf("Cache", "%d KB", stats.CacheSize)
+	box.Field("Microcode", stats.Microcode)
+	box.Field("Load Average", stats.Load.FormatLoad())
+	box.Field("Uptime", common.FormatDuration(stats.Load.Uptime))
 
 	// Show temperature if available
 	if stats.Temperature > 0 {
-		fmt.Printf("║  Temperature:     %-58d °C  ║\n", stats.Temperature)
+		box.Fieldf("Temperature", "%d °C", stats.Temperature)
 	} else {
-		fmt.Printf("║  Temperature:     %-62s  ║\n", "N/A (not available)")
+		box.Field("Temperature", "N/A (not available)")
 	}
 
-	fmt.Printf("╚══════════════════════════════════════════════════════════════════════════════════╝\n")
+	box.End()
 
 	// Note: Flags are not printed by default as they are very long
 	// Uncomment the line below if you want to see all CPU flags
@@ -135,22 +289,15 @@ func PrintGeneralStats(stats GeneralStats) {
 // This function provides a formatted view of the most active processes
 //
 // Parameters:
+//   - processes: every process (from common.CollectAllProcessInfo), shared with the other views
 //   - n: number of processes to show (top N)
-//
-// Returns:
-//   - error if unable to get the data
-func PrintTopProcessesByCPU(n int) error {
-	// Get processes sorted by CPU
-	processes, err := GetProcessStats()
-	if err != nil {
-		return err
-	}
+func PrintTopProcessesByCPU(processes []common.ProcessInfo, n int) {
+	// Get the N processes with highest CPU usage
+	processes = common.TopKProcesses(processes, n, "cpu")
 
 	// Use the common function to print the table
 	title := fmt.Sprintf("Top %d Processes by CPU Usage", n)
 	common.PrintProcessTable(processes, n, title)
-
-	return nil
 }
 
 // GetCPUUsageByPID gets the CPU usage of a specific process
@@ -164,7 +311,7 @@ func PrintTopProcessesByCPU(n int) error {
 //   - error if the process doesn't exist or is not accessible
 func GetCPUUsageByPID(pid int32) (float64, error) {
 	// Get total system memory
-	totalMem, err := common.GetSystemMemoryTotal()
+	totalMem, err := common.GetProcessMemoryTotal()
 	if err != nil {
 		return 0, err
 	}
@@ -184,63 +331,18 @@ func GetCPUUsageByPID(pid int32) (float64, error) {
 	return info.CPUPercentage, nil
 }
 
-// getCPUTemperature gets the system CPU temperature
-// Searches for thermal zones that contain CPU temperature (x86_pkg_temp, coretemp, etc.)
+// GetTemperature gets the system CPU temperature
+// Uses the hwmon chips (coretemp, k10temp, ...) and falls back to the thermal zones
+// that contain CPU temperature (x86_pkg_temp, coretemp, etc.)
 //
 // Returns:
 //   - temperature in degrees Celsius (0 if not available)
-func getCPUTemperature() int {
-	// List of thermal zone types that contain CPU temperature
-	// x86_pkg_temp is the CPU package temperature (most common on Intel systems)
-	// acpitz can also contain CPU temperature on some systems
-	targetTypes := []string{"x86_pkg_temp", "coretemp", "cpu_thermal", "acpitz"}
-
-	// Search all available thermal zones
-	for i := 0; i < 20; i++ {
-		zonePath := fmt.Sprintf("/sys/class/thermal/thermal_zone%d/", i)
-
-		// Read the thermal zone type
-		typeBuf, err := os.ReadFile(zonePath + "type")
-		if err != nil {
-			continue // This zone doesn't exist or is not accessible
-		}
-
-		zoneType := strings.TrimSpace(string(typeBuf))
-
-		// Check if it's a CPU thermal zone
-		isCPUZone := false
-		for _, targetType := range targetTypes {
-			if zoneType == targetType || strings.Contains(zoneType, targetType) {
-				isCPUZone = true
-				break
-			}
-		}
-
-		if !isCPUZone {
-			continue
-		}
-
-		// Read the temperature from this zone
-		tempBuf, err := os.ReadFile(zonePath + "temp")
-		if err != nil {
-			continue
-		}
-
-		// Convert from string to integer
-		tempMilliC, err := strconv.Atoi(strings.TrimSpace(string(tempBuf)))
-		if err != nil {
-			continue
-		}
-
-		// Convert from millidegrees Celsius to degrees Celsius
-		temp := tempMilliC / 1000
-
-		// Validate if temperature is reasonable (between 0 and 150°C)
-		if temp > 0 && temp < 150 {
-			return temp
-		}
+func GetTemperature() int {
+	if temp := sensors.CPUTemperature(); temp > 0 {
+		return temp
 	}
 
-	// If not found, return 0 (not available)
-	return 0
+	// x86_pkg_temp is the CPU package temperature (most common on Intel systems)
+	// acpitz can also contain CPU temperature on some systems
+	return sensors.ThermalZoneTemperature([]string{"x86_pkg_temp", "coretemp", "cpu_thermal", "acpitz"})
 }
@@ -0,0 +1,164 @@
+package cpu
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/procfs"
+	"github.com/shirou/gopsutil/v3/cpu"
+)
+
+// TimeBreakdown is how the CPUs spent their time over an interval, by mode
+// Every value is a percentage of the time elapsed on all CPUs, and they add up to 100%
+type TimeBreakdown struct {
+	User   float64 // Running user code (including niced processes)
+	System float64 // Running kernel code
+	Iowait float64 // Idle while waiting for I/O (high values point at slow storage)
+	Irq    float64 // Serving hardware and soft interrupts
+	Steal  float64 // Taken by the hypervisor for other virtual machines (high values point at a busy host)
+	Idle   float64 // Idle
+	Valid  bool    // The breakdown was measured (false before the second reading)
+}
+
+// Mode is the share of one CPU mode in a TimeBreakdown
+type Mode struct {
+	Name    string  // Mode name (e.g. "iowait")
+	Percent float64 // Share of the CPU time (0-100%)
+	Symbol  string  // Character drawing the mode in a plain text bar
+}
+
+// Modes lists the modes of the breakdown, in the order they are drawn
+func (b TimeBreakdown) Modes() []Mode {
+	return []Mode{
+		{"user", b.User, "█"},
+		{"system", b.System, "▓"},
+		{"iowait", b.Iowait, "▒"},
+		{"irq", b.Irq, "░"},
+		{"steal", b.Steal, "×"},
+		{"idle", b.Idle, "·"},
+	}
+}
+
+// Cells splits a bar between the modes, in the order of Modes
+// Every mode takes cells proportional to its share; the rounding goes to idle
+//
+// Parameters:
+//   - width: width of the bar
+//
+// Returns: cells of each mode (they add up to width)
+func (b TimeBreakdown) Cells(width int) []int {
+	modes := b.Modes()
+	cells := make([]int, len(modes))
+	used := 0
+	for i, mode := range modes[:len(modes)-1] {
+		cells[i] = max(0, min(int(mode.Percent/100*float64(width)+0.5), width-used))
+		used += cells[i]
+	}
+	cells[len(cells)-1] = width - used
+	return cells
+}
+
+// Bar draws the breakdown as a bar with one character per mode (see Mode.Symbol)
+//
+// Parameters:
+//   - width: width of the bar without the brackets
+func (b TimeBreakdown) Bar(width int) string {
+	var bar strings.Builder
+	cells := b.Cells(width)
+	for i, mode := range b.Modes() {
+		bar.WriteString(strings.Repeat(mode.Symbol, cells[i]))
+	}
+	return "[" + bar.String() + "]"
+}
+
+// FormatModes lists the share of some modes with their symbols (e.g. "█ user 12.0%  ▓ system 3.1%")
+func FormatModes(modes []Mode) string {
+	parts := make([]string, 0, len(modes))
+	for _, mode := range modes {
+		parts = append(parts, fmt.Sprintf("%s %s %.1f%%", mode.Symbol, mode.Name, mode.Percent))
+	}
+	return strings.Join(parts, "  ")
+}
+
+// breakdownBetween computes the breakdown between two readings of the CPU times
+//
+// Returns: the breakdown (not Valid if no time elapsed)
+func breakdownBetween(before, after procfs.CPUTimes) TimeBreakdown {
+	total := after.Total() - before.Total()
+	if total <= 0 {
+		return TimeBreakdown{}
+	}
+
+	share := func(after, before float64) float64 {
+		return min(max((after-before)/total*100, 0), 100)
+	}
+	return TimeBreakdown{
+		User:   share(after.User+after.Nice, before.User+before.Nice),
+		System: share(after.System, before.System),
+		Iowait: share(after.Iowait, before.Iowait),
+		Irq:    share(after.Irq+after.Softirq, before.Irq+before.Softirq),
+		Steal:  share(after.Steal, before.Steal),
+		Idle:   share(after.Idle, before.Idle),
+		Valid:  true,
+	}
+}
+
+// readTimes reads the time all CPUs spent in each mode since boot with the active backend
+//
+// Returns:
+//   - CPU times in seconds
+//   - error if they can't be read
+func readTimes() (procfs.CPUTimes, error) {
+	if common.UsingProcfs() {
+		return procfs.ReadCPUTimes()
+	}
+
+	times, err := cpu.Times(false)
+	if err != nil {
+		return procfs.CPUTimes{}, fmt.Errorf("error reading CPU times: %w", err)
+	}
+	if len(times) == 0 {
+		return procfs.CPUTimes{}, fmt.Errorf("error reading CPU times: no data")
+	}
+	t := times[0]
+	return procfs.CPUTimes{
+		User: t.User, Nice: t.Nice, System: t.System, Idle: t.Idle,
+		Iowait: t.Iowait, Irq: t.Irq, Softirq: t.Softirq, Steal: t.Steal,
+	}, nil
+}
+
+// BreakdownSampler measures the CPU time breakdown between samples without blocking
+// Used by the TUI, which samples on every refresh
+type BreakdownSampler struct {
+	mu   sync.Mutex
+	last *procfs.CPUTimes // Reading of the previous sample (nil before the first)
+}
+
+// NewBreakdownSampler creates a sampler with no previous reading
+func NewBreakdownSampler() *BreakdownSampler {
+	return &BreakdownSampler{}
+}
+
+// Sample measures the breakdown since the previous call
+// The first call only records a baseline and returns a breakdown that isn't Valid
+//
+// Returns:
+//   - breakdown since the previous sample
+//   - error if the CPU times can't be read
+func (s *BreakdownSampler) Sample() (TimeBreakdown, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	after, err := readTimes()
+	if err != nil {
+		return TimeBreakdown{}, err
+	}
+	before := s.last
+	s.last = &after
+	if before == nil {
+		return TimeBreakdown{}, nil
+	}
+	return breakdownBetween(*before, after), nil
+}
@@ -0,0 +1,82 @@
+package cpu
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/cgroup"
+)
+
+// quotaSample is a reading of the CPU time of GoMonitor's cgroup
+type quotaSample struct {
+	cpuTime time.Duration // CPU time used by the cgroup
+	at      time.Time     // When it was read
+}
+
+// quotaLast is the previous reading of GetSystemPercent under a CPU quota
+var (
+	quotaLast   *quotaSample
+	quotaLastMu sync.Mutex
+)
+
+// quotaCores returns the CPU quota the usage is reported against
+// Inside a container (e.g. docker run --cpus 1.5) the host CPUs can be idle while the
+// container is throttled, so the usage of the quota is what matters
+//
+// Returns: quota in cores (0 if there is none or --host was given)
+func quotaCores() float64 {
+	if limits, ok := cgroup.Effective(); ok {
+		return limits.CPULimit
+	}
+	return 0
+}
+
+// readQuotaSample reads the CPU time of GoMonitor's cgroup
+func readQuotaSample() (quotaSample, error) {
+	cpuTime, err := cgroup.SelfCPUTime()
+	if err != nil {
+		return quotaSample{}, err
+	}
+	return quotaSample{cpuTime: cpuTime, at: time.Now()}, nil
+}
+
+// quotaPercent returns the usage of a CPU quota between two readings
+//
+// Parameters:
+//   - before, after: readings of the cgroup CPU time
+//   - cores: CPU quota in cores
+//
+// Returns: usage of the quota (0-100%)
+func quotaPercent(before, after quotaSample, cores float64) float64 {
+	elapsed := after.at.Sub(before.at).Seconds()
+	if elapsed <= 0 || cores <= 0 || after.cpuTime < before.cpuTime {
+		return 0
+	}
+	return min((after.cpuTime-before.cpuTime).Seconds()/(elapsed*cores)*100, 100)
+}
+
+// systemQuotaPercent is GetSystemPercent under a CPU quota: the usage of the quota since the
+// previous call (the first call only records a baseline)
+//
+// Parameters:
+//   - cores: CPU quota in cores
+//
+// Returns:
+//   - usage of the quota (0-100%)
+//   - error if there's no previous reading yet or the CPU time of the cgroup can't be read
+func systemQuotaPercent(cores float64) (float64, error) {
+	quotaLastMu.Lock()
+	defer quotaLastMu.Unlock()
+
+	after, err := readQuotaSample()
+	if err != nil {
+		return 0, fmt.Errorf("error getting CPU usage percentage: %w", err)
+	}
+	before := quotaLast
+	quotaLast = &after
+	if before == nil {
+		return 0, fmt.Errorf("error getting CPU usage percentage: no previous sample")
+	}
+	return quotaPercent(*before, after, cores), nil
+}
@@ -0,0 +1,255 @@
+package cpu
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Throttling detection
+const (
+	throttleLoadPercent    = 80      // CPU usage above which a low frequency counts as throttling (%)
+	throttleFrequencyRatio = 0.7     // Share of the reachable frequency under which a busy CPU counts as throttled
+	msrPackageThermStatus  = 0x1B1   // IA32_PACKAGE_THERM_STATUS (Intel)
+	msrThermalStatusBit    = 1 << 0  // The package is at its thermal limit (PROCHOT)
+	msrPowerLimitBit       = 1 << 10 // The package is held back by its power limit (PL1/PL2)
+)
+
+// TurboState tells if the CPU may run above its base frequency (Turbo Boost, Precision Boost)
+type TurboState int
+
+const (
+	TurboUnknown  TurboState = iota // No intel_pstate or cpufreq boost switch
+	TurboEnabled                    // The CPU may boost
+	TurboDisabled                   // Boosting is turned off (e.g. by a power profile)
+)
+
+// String returns the state as shown in the CPU view (e.g. "enabled")
+func (s TurboState) String() string {
+	switch s {
+	case TurboEnabled:
+		return "enabled"
+	case TurboDisabled:
+		return "disabled"
+	default:
+		return "unknown"
+	}
+}
+
+// throttleSample is a reading of the core frequencies and the throttle counters
+type throttleSample struct {
+	currentMHz float64    // Average current frequency of the cores
+	maxMHz     float64    // Highest hardware frequency (cpuinfo_max_freq)
+	limitMHz   float64    // Highest frequency the cpufreq policy allows (scaling_max_freq)
+	baseMHz    float64    // Base frequency (intel_pstate only, 0 if unknown)
+	events     uint64     // Thermal throttle events since boot (core and package counters)
+	hasEvents  bool       // The thermal_throttle counters exist (Intel)
+	msr        uint64     // IA32_PACKAGE_THERM_STATUS of the first package
+	hasMSR     bool       // The MSR could be read (root and the msr module)
+	turbo      TurboState // Turbo switch
+}
+
+// ThrottleStatus tells if the CPU is held below the frequency it could run at
+type ThrottleStatus struct {
+	Valid       bool       // False if the frequencies can't be read (e.g. no cpufreq in a VM)
+	CurrentMHz  float64    // Average current frequency of the cores
+	MaxMHz      float64    // Highest hardware frequency, with turbo
+	LimitMHz    float64    // Highest frequency the cpufreq policy allows (lower than MaxMHz when capped)
+	Turbo       TurboState // Turbo switch
+	HasEvents   bool       // The thermal throttle counters exist (Intel)
+	TotalEvents uint64     // Thermal throttle events since boot
+	Events      uint64     // Thermal throttle events during the measurement
+	Throttling  bool       // The CPU is being throttled
+	Reasons     []string   // Why (e.g. "at its thermal limit (PROCHOT)")
+}
+
+// Warning returns the banner shown while the CPU is throttled (empty if it isn't)
+func (s ThrottleStatus) Warning() string {
+	if !s.Throttling {
+		return ""
+	}
+	return "CPU is being throttled: " + strings.Join(s.Reasons, ", ")
+}
+
+// FormatFrequency describes the current frequency against the maximum
+// e.g. "2400 / 4200 MHz", "1800 / 4200 MHz (capped at 3000 MHz)"
+func (s ThrottleStatus) FormatFrequency() string {
+	text := fmt.Sprintf("%.0f / %.0f MHz", s.CurrentMHz, s.MaxMHz)
+	if s.LimitMHz > 0 && s.LimitMHz < s.MaxMHz {
+		text += fmt.Sprintf(" (capped at %.0f MHz)", s.LimitMHz)
+	}
+	return text
+}
+
+// ThrottleSampler detects throttling between readings, for views that refresh (the TUI)
+type ThrottleSampler struct {
+	last *throttleSample // Previous reading (nil before the first)
+}
+
+// NewThrottleSampler creates a sampler without a previous reading
+//
+// Returns: pointer to a configured ThrottleSampler
+func NewThrottleSampler() *ThrottleSampler {
+	return &ThrottleSampler{}
+}
+
+// Sample reads the frequencies and throttle counters and compares them with the previous call
+// The first call can't count throttle events yet, but still checks the frequency and the MSR
+//
+// Parameters:
+//   - usage: system CPU usage over the same interval (0-100%)
+//
+// Returns: ThrottleStatus since the previous call
+func (s *ThrottleSampler) Sample(usage float64) ThrottleStatus {
+	after := readThrottleSample()
+	before := after
+	if s.last != nil {
+		before = *s.last
+	}
+	s.last = &after
+	return throttleBetween(before, after, usage)
+}
+
+// readThrottleSample reads the frequencies of every core, the throttle counters, the turbo
+// switch and (as root, with the msr module) the thermal status register
+func readThrottleSample() throttleSample {
+	sample := throttleSample{turbo: readTurbo()}
+	dirs, _ := filepath.Glob(filepath.Join(cpuSysfsPath(), "cpu[0-9]*"))
+
+	var currentSum float64
+	var cores int
+	packages := make(map[int]bool)
+	for _, dir := range dirs {
+		// 1. Frequencies (kHz)
+		if current, err := readSysfsInt(filepath.Join(dir, "cpufreq", "scaling_cur_freq")); err == nil {
+			currentSum += float64(current) / 1000
+			cores++
+		}
+		if maxFreq, err := readSysfsInt(filepath.Join(dir, "cpufreq", "cpuinfo_max_freq")); err == nil {
+			sample.maxMHz = max(sample.maxMHz, float64(maxFreq)/1000)
+		}
+		if limit, err := readSysfsInt(filepath.Join(dir, "cpufreq", "scaling_max_freq")); err == nil {
+			sample.limitMHz = max(sample.limitMHz, float64(limit)/1000)
+		}
+		if base, err := readSysfsInt(filepath.Join(dir, "cpufreq", "base_frequency")); err == nil {
+			sample.baseMHz = max(sample.baseMHz, float64(base)/1000)
+		}
+
+		// 2. Thermal throttle counters: one per core, and one per package shared by its cores
+		if count, err := readSysfsInt(filepath.Join(dir, "thermal_throttle", "core_throttle_count")); err == nil {
+			sample.events += uint64(count)
+			sample.hasEvents = true
+		}
+		pkg, _ := readSysfsInt(filepath.Join(dir, "topology", "physical_package_id"))
+		if !packages[pkg] {
+			if count, err := readSysfsInt(filepath.Join(dir, "thermal_throttle", "package_throttle_count")); err == nil {
+				sample.events += uint64(count)
+				sample.hasEvents = true
+				packages[pkg] = true
+			}
+		}
+	}
+	if cores > 0 {
+		sample.currentMHz = currentSum / float64(cores)
+	}
+
+	// 3. Thermal status register of the first package
+	sample.msr, sample.hasMSR = readMSR(0, msrPackageThermStatus)
+	return sample
+}
+
+// readTurbo reads the turbo switch of intel_pstate (no_turbo) or of cpufreq (boost)
+func readTurbo() TurboState {
+	if noTurbo, err := readSysfsInt(filepath.Join(cpuSysfsPath(), "intel_pstate", "no_turbo")); err == nil {
+		if noTurbo == 1 {
+			return TurboDisabled
+		}
+		return TurboEnabled
+	}
+	if boost, err := readSysfsInt(filepath.Join(cpuSysfsPath(), "cpufreq", "boost")); err == nil {
+		if boost == 1 {
+			return TurboEnabled
+		}
+		return TurboDisabled
+	}
+	return TurboUnknown
+}
+
+// readMSR reads a model-specific register of a CPU through the msr driver
+// This needs root and the msr module (modprobe msr); AMD CPUs don't have the Intel registers
+//
+// Parameters:
+//   - cpu: logical CPU number
+//   - register: MSR address (e.g. 0x1B1)
+//
+// Returns:
+//   - value of the register
+//   - false if it can't be read
+func readMSR(cpu int, register int64) (uint64, bool) {
+	file, err := os.Open(fmt.Sprintf("/dev/cpu/%d/msr", cpu))
+	if err != nil {
+		return 0, false
+	}
+	defer file.Close()
+
+	buf := make([]byte, 8)
+	if _, err := file.ReadAt(buf, register); err != nil {
+		return 0, false
+	}
+	return binary.LittleEndian.Uint64(buf), true
+}
+
+// throttleBetween decides if the CPU was throttled between two readings
+// The CPU counts as throttled when the thermal status register says so, when the thermal
+// throttle counters went up, or when it runs well below its reachable frequency while busy
+// (a limit set by the cpufreq policy, e.g. a power profile, is not throttling)
+//
+// Parameters:
+//   - before, after: readings around the measurement
+//   - usage: system CPU usage over the measurement (0-100%)
+//
+// Returns: ThrottleStatus of the measurement
+func throttleBetween(before, after throttleSample, usage float64) ThrottleStatus {
+	status := ThrottleStatus{
+		Valid:       after.currentMHz > 0 && after.maxMHz > 0,
+		CurrentMHz:  after.currentMHz,
+		MaxMHz:      after.maxMHz,
+		LimitMHz:    after.limitMHz,
+		Turbo:       after.turbo,
+		HasEvents:   after.hasEvents,
+		TotalEvents: after.events,
+	}
+
+	// 1. Thermal and power limit status of the package
+	if after.hasMSR {
+		if after.msr&msrThermalStatusBit != 0 {
+			status.Reasons = append(status.Reasons, "at its thermal limit (PROCHOT)")
+		}
+		if after.msr&msrPowerLimitBit != 0 {
+			status.Reasons = append(status.Reasons, "held back by its power limit")
+		}
+	}
+
+	// 2. Thermal throttle events during the measurement
+	if before.hasEvents && after.hasEvents && after.events > before.events {
+		status.Events = after.events - before.events
+		status.Reasons = append(status.Reasons, fmt.Sprintf("%d thermal throttle events", status.Events))
+	}
+
+	// 3. Low frequency under load, against the frequency the policy and turbo switch allow
+	reachable := after.maxMHz
+	if after.turbo == TurboDisabled && after.baseMHz > 0 {
+		reachable = after.baseMHz
+	}
+	if after.limitMHz > 0 {
+		reachable = min(reachable, after.limitMHz)
+	}
+	if status.Valid && usage >= throttleLoadPercent && after.currentMHz < reachable*throttleFrequencyRatio {
+		status.Reasons = append(status.Reasons, fmt.Sprintf("running at %.0f of %.0f MHz under load", after.currentMHz, reachable))
+	}
+
+	status.Throttling = len(status.Reasons) > 0
+	return status
+}
@@ -0,0 +1,412 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/alerts"
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/config"
+	"github.com/dfialho05/GoMonitor/application/pck/cpu"
+	"github.com/dfialho05/GoMonitor/application/pck/disk"
+	"github.com/dfialho05/GoMonitor/application/pck/gpu"
+	"github.com/dfialho05/GoMonitor/application/pck/history"
+	"github.com/dfialho05/GoMonitor/application/pck/hwcache"
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+	"github.com/dfialho05/GoMonitor/application/pck/ram"
+)
+
+// Files of the running daemon in the state directory
+const (
+	pidFileName    = "daemon.pid"  // PID of the daemon, locked while it runs
+	socketFileName = "daemon.sock" // Unix socket for the control commands
+)
+
+// Control commands understood by the daemon
+const (
+	CommandStatus = "status" // Report the PID, uptime and firing alerts
+	CommandStop   = "stop"   // Exit
+	CommandReload = "reload" // Read the config file again (same as SIGHUP)
+)
+
+// controlTimeout bounds how long a control command waits for the daemon
+const controlTimeout = 5 * time.Second
+
+// ErrNotRunning is returned by Control when no daemon is listening
+var ErrNotRunning = errors.New("gom daemon is not running")
+
+// Daemon is a started daemon, holding the PID file lock and the control socket
+type Daemon struct {
+	load     func() config.Config // Reads the config file (on start and reload)
+	started  time.Time            // When the daemon started
+	requests chan request         // Control commands for the main loop
+	pidPath  string               // Location of the PID file
+	pidFile  *os.File             // Locked PID file (unlocked when closed)
+	listener net.Listener         // Control socket
+	handlers sync.WaitGroup       // Control connections being answered
+
+	// Set by the main loop, read by status
+	mu      sync.Mutex
+	cfg     config.Config  // Config in use
+	samples int            // Number of samples taken
+	firing  []alerts.Alert // Alerts firing at the last sample
+}
+
+// request is a control command waiting for the main loop
+type request struct {
+	command string
+	reply   chan string
+}
+
+// Start becomes the daemon of the state directory: only one daemon runs per state
+// directory, so Start fails while another one is running
+// In read-only mode (paths.ReadOnly) no PID file or socket is created, so neither the
+// single instance check nor the control commands are available
+//
+// Parameters:
+//   - load: reads the config file, called on start and on every reload
+//
+// Returns:
+//   - the daemon, ready to Run
+//   - error if another daemon is running or the control socket can't be created
+func Start(load func() config.Config) (*Daemon, error) {
+	if paths.ReadOnly() {
+		return &Daemon{load: load, started: time.Now(), requests: make(chan request)}, nil
+	}
+
+	// 1. Take the PID file lock, so a second instance refuses to start
+	pidPath, err := paths.StateFile(pidFileName)
+	if err != nil {
+		return nil, err
+	}
+	pidFile, err := lockPIDFile(pidPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Listen for control commands; a socket left by a crashed daemon is replaced,
+	// since holding the lock proves no other daemon uses it
+	d := &Daemon{load: load, started: time.Now(), requests: make(chan request), pidPath: pidPath, pidFile: pidFile}
+	socketPath, err := paths.StateFile(socketFileName)
+	if err != nil {
+		d.unlock()
+		return nil, err
+	}
+	os.Remove(socketPath)
+	if d.listener, err = net.Listen("unix", socketPath); err != nil {
+		d.unlock()
+		return nil, fmt.Errorf("error creating control socket: %w", err)
+	}
+	if err := os.Chmod(socketPath, 0o600); err != nil {
+		d.listener.Close()
+		d.unlock()
+		return nil, fmt.Errorf("error securing control socket: %w", err)
+	}
+	return d, nil
+}
+
+// Run samples the system like the TUI does in the background until the daemon is stopped
+// (Ctrl+C, SIGTERM or "gom daemon stop"): alerts are evaluated and, with history_enabled,
+// fired alerts, events and metrics are stored
+// The PID file and the control socket are removed when it returns
+func (d *Daemon) Run() {
+	if d.listener == nil {
+		d.loop() // Read-only mode
+		return
+	}
+	defer d.unlock()
+
+	go d.serve()
+	d.loop()
+
+	// Let the last answers (e.g. to "stop") reach their clients
+	d.listener.Close() // Also removes the socket file
+	d.handlers.Wait()
+}
+
+// unlock removes the PID file and releases its lock
+func (d *Daemon) unlock() {
+	os.Remove(d.pidPath)
+	d.pidFile.Close()
+}
+
+// lockPIDFile creates the PID file and locks it for as long as the daemon runs
+// The lock is released by the kernel when the process exits, so a crash never
+// leaves a stale lock behind (only a stale file, which is reused)
+//
+// Returns:
+//   - the open, locked PID file
+//   - error naming the running daemon if the file is already locked
+func lockPIDFile(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("error creating state directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("error opening PID file: %w", err)
+	}
+
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		defer file.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			buf, _ := io.ReadAll(file)
+			if pid := strings.TrimSpace(string(buf)); pid != "" {
+				return nil, fmt.Errorf("gom daemon is already running (PID %s); use 'gom daemon status' or 'gom daemon stop'", pid)
+			}
+			return nil, errors.New("gom daemon is already running; use 'gom daemon status' or 'gom daemon stop'")
+		}
+		return nil, fmt.Errorf("error locking PID file: %w", err)
+	}
+
+	if err := file.Truncate(0); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error writing PID file: %w", err)
+	}
+	if _, err := file.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error writing PID file: %w", err)
+	}
+	return file, nil
+}
+
+// loop samples the system at the configured refresh interval and handles the control
+// commands and signals until the daemon is stopped
+func (d *Daemon) loop() {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(sigChan)
+
+	s := newSampler(d.load())
+	d.setConfig(s.cfg)
+	ticker := time.NewTicker(s.cfg.RefreshInterval.Duration)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			firing := s.sample(time.Now())
+			d.mu.Lock()
+			d.samples++
+			d.firing = firing
+			d.mu.Unlock()
+
+		case sig := <-sigChan:
+			if sig != syscall.SIGHUP {
+				return
+			}
+			s = d.reload(s, ticker)
+
+		case req := <-d.requests:
+			switch req.command {
+			case CommandStatus:
+				req.reply <- d.status()
+			case CommandReload:
+				s = d.reload(s, ticker)
+				req.reply <- "Config reloaded"
+			case CommandStop:
+				req.reply <- fmt.Sprintf("Stopping gom daemon (PID %d)", os.Getpid())
+				return
+			default:
+				req.reply <- fmt.Sprintf("unknown command '%s'", req.command)
+			}
+		}
+	}
+}
+
+// reload reads the config file again and applies it (rules, refresh interval, history)
+// The alert durations restart, since the rules may have changed
+func (d *Daemon) reload(old *sampler, ticker *time.Ticker) *sampler {
+	s := newSampler(d.load())
+	s.tracker = old.tracker
+	d.setConfig(s.cfg)
+	ticker.Reset(s.cfg.RefreshInterval.Duration)
+	return s
+}
+
+// setConfig stores the config in use for the status report
+func (d *Daemon) setConfig(cfg config.Config) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.cfg = cfg
+}
+
+// status describes the running daemon
+func (d *Daemon) status() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "gom daemon is running (PID %d)\n", os.Getpid())
+	fmt.Fprintf(&b, "Started:  %s (up %s)\n", common.FormatTimestamp(d.started), time.Since(d.started).Round(time.Second))
+	fmt.Fprintf(&b, "Interval: %s\n", d.cfg.RefreshInterval.Duration)
+	fmt.Fprintf(&b, "History:  %t\n", d.cfg.HistoryEnabled)
+	fmt.Fprintf(&b, "Samples:  %d\n", d.samples)
+	fmt.Fprintf(&b, "Alerts:   %d firing", len(d.firing))
+	for _, alert := range d.firing {
+		fmt.Fprintf(&b, "\n  %s", alert.String())
+	}
+	return b.String()
+}
+
+// serve accepts control connections: one command per connection, answered with text
+func (d *Daemon) serve() {
+	for {
+		conn, err := d.listener.Accept()
+		if err != nil {
+			return // Closed when the daemon stops
+		}
+		d.handlers.Add(1)
+		go d.handle(conn)
+	}
+}
+
+// handle reads one command from a control connection and writes the answer of the main loop
+func (d *Daemon) handle(conn net.Conn) {
+	defer d.handlers.Done()
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(controlTimeout))
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil && line == "" {
+		return
+	}
+
+	req := request{command: strings.TrimSpace(line), reply: make(chan string, 1)}
+	select {
+	case d.requests <- req:
+		fmt.Fprintln(conn, <-req.reply)
+	case <-time.After(controlTimeout):
+		fmt.Fprintln(conn, "gom daemon is busy, try again")
+	}
+}
+
+// Control sends a command to the running daemon
+//
+// Parameters:
+//   - command: one of CommandStatus, CommandStop or CommandReload
+//
+// Returns:
+//   - the daemon's answer
+//   - ErrNotRunning if no daemon is listening, or an error if it didn't answer
+func Control(command string) (string, error) {
+	socketPath, err := paths.StateFile(socketFileName)
+	if err != nil {
+		return "", err
+	}
+
+	conn, err := net.DialTimeout("unix", socketPath, controlTimeout)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) || errors.Is(err, syscall.ECONNREFUSED) {
+			return "", ErrNotRunning
+		}
+		return "", fmt.Errorf("error connecting to gom daemon: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(2 * controlTimeout))
+
+	if _, err := fmt.Fprintln(conn, command); err != nil {
+		return "", fmt.Errorf("error sending command: %w", err)
+	}
+	reply, err := io.ReadAll(conn)
+	if err != nil {
+		return "", fmt.Errorf("error reading answer: %w", err)
+	}
+	return strings.TrimRight(string(reply), "\n"), nil
+}
+
+// sampler collects what the daemon monitors with one config
+type sampler struct {
+	cfg      config.Config
+	engine   *alerts.Engine
+	tracker  *common.ProcessTracker // Detects renamed and respawning processes
+	recorder *history.Recorder      // Stores fired alerts and events (nil if history is disabled)
+	metrics  *history.MetricStore   // Stores the system usage (nil if history is disabled)
+}
+
+// newSampler prepares the alert rules and history stores of a config
+func newSampler(cfg config.Config) *sampler {
+	rules, err := alerts.ConfiguredRules(cfg.Alerts)
+	if err != nil {
+		common.Logger().Error("error loading the alert rules", "err", err)
+	}
+	s := &sampler{cfg: cfg, engine: alerts.NewEngine(rules), tracker: common.NewProcessTracker()}
+
+	if cfg.HistoryEnabled {
+		if path, err := history.DefaultPath(); err == nil {
+			s.recorder = history.NewRecorder(history.NewStore(path))
+			if err := s.recorder.RecordBoot(); err != nil {
+				common.WarnEvery("history", time.Minute, "error writing the history", "err", err)
+			}
+		}
+		if path, err := history.DefaultMetricsPath(); err == nil {
+			s.metrics = history.NewMetricStore(path)
+			if err := s.metrics.Prune(); err != nil {
+				common.WarnEvery("history", time.Minute, "error writing the history", "err", err)
+			}
+		}
+	}
+	return s
+}
+
+// sample measures the system once, evaluates the alerts and stores the history
+//
+// Returns: alerts currently firing
+func (s *sampler) sample(now time.Time) []alerts.Alert {
+	var samples []alerts.Sample
+	values := make(map[string]float64)
+
+	// The first CPU sample only records a baseline
+	if percent, err := cpu.GetSystemPercent(); err == nil {
+		samples = append(samples, alerts.CPUSample(percent))
+		values[history.SeriesCPU] = percent
+	}
+	if stats, err := ram.GetRamGeneral(); err == nil {
+		samples = append(samples, alerts.RAMSample(stats))
+		values[history.SeriesRAM] = stats.Percent
+	}
+	if load, err := cpu.GetLoadStats(); err == nil && load.HasLoad {
+		values[history.SeriesLoad] = load.Load1
+	}
+	if temp := cpu.GetTemperature(); temp > 0 {
+		values[history.SeriesTemp] = float64(temp)
+	}
+
+	// Without a GPU, nvidia-smi and the DRM scan are skipped on every sample
+	if len(hwcache.Get().GPUs) > 0 {
+		if allStats, err := gpu.GetAllGPUStats(context.Background()); err == nil {
+			samples = append(samples, alerts.GPUSamples(allStats)...)
+		}
+	}
+	if devices, err := disk.GetAllStorageDevices(s.cfg.Disk.Filter()); err == nil {
+		samples = append(samples, alerts.DiskSamples(devices)...)
+	}
+	if processes, err := common.CollectAllProcessInfo(); err == nil {
+		s.tracker.Observe(processes, now)
+		samples = append(samples, alerts.ProcessSamples(s.tracker)...)
+	}
+
+	firing := s.engine.Evaluate(samples, now)
+	if s.recorder != nil {
+		if err := s.recorder.Observe(firing, now); err != nil {
+			common.WarnEvery("history", time.Minute, "error writing the history", "err", err)
+		}
+	}
+	if s.metrics != nil {
+		if err := s.metrics.Record(history.MetricSample{Time: now, Values: values}); err != nil {
+			common.WarnEvery("history", time.Minute, "error writing the history", "err", err)
+		}
+	}
+	return firing
+}
@@ -0,0 +1,196 @@
+package disk
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+)
+
+// UnknownDevice groups the I/O of processes without open files on a block device
+// (e.g. files written and already closed, memory-mapped files or swap)
+const UnknownDevice = "other"
+
+// ProcessDiskIO contains the disk I/O of a process, in total or attributed to one device
+type ProcessDiskIO struct {
+	Process          common.ProcessInfo // Process information
+	ReadBytesPerSec  float64            // Read throughput (of the device, when attributed)
+	WriteBytesPerSec float64            // Write throughput (of the device, when attributed)
+}
+
+// DeviceProcesses contains the processes doing I/O on a disk
+type DeviceProcesses struct {
+	Device           string          // Whole disk name (e.g. "sda", "nvme0n1") or UnknownDevice
+	ReadBytesPerSec  float64         // Sum of the attributed read throughput
+	WriteBytesPerSec float64         // Sum of the attributed write throughput
+	Processes        []ProcessDiskIO // Processes sorted by total throughput (descending)
+}
+
+// ioCounters contains the storage I/O counters of /proc/<pid>/io
+type ioCounters struct {
+	readBytes  uint64
+	writeBytes uint64
+}
+
+// GetProcessIOByDevice measures which processes are doing I/O on which disk
+// /proc/<pid>/io only has per-process totals, so each process is mapped to the disks
+// it has files open on (device numbers of the files, resolved through sysfs and the
+// mount table). A process with files on several disks has its I/O split evenly between them
+//
+// Parameters:
+//   - interval: how long the I/O is measured for
+//
+// Returns:
+//   - slice of DeviceProcesses sorted by total throughput (descending)
+//   - error if the processes can't be read
+func GetProcessIOByDevice(interval time.Duration) ([]DeviceProcesses, error) {
+	// 1. Measure the I/O counters over the interval (the process collection waits for it)
+	before := readAllIOCounters()
+	start := time.Now()
+	processes, _, err := common.CollectProcessInfo(common.CollectOptions{SampleInterval: interval})
+	if err != nil {
+		return nil, fmt.Errorf("error collecting processes: %w", err)
+	}
+	after := readAllIOCounters()
+	seconds := time.Since(start).Seconds()
+
+	// 2. Attribute the I/O of every active process to its disks
+	resolver := newDeviceResolver()
+	byDevice := make(map[string]*DeviceProcesses)
+	for _, p := range processes {
+		first, ok := before[p.PID]
+		if !ok {
+			continue
+		}
+		second, ok := after[p.PID]
+		if !ok || second.readBytes < first.readBytes || second.writeBytes < first.writeBytes {
+			continue
+		}
+		read := float64(second.readBytes-first.readBytes) / seconds
+		written := float64(second.writeBytes-first.writeBytes) / seconds
+		if read == 0 && written == 0 {
+			continue
+		}
+
+		devices := resolver.processDisks(p.PID)
+		if len(devices) == 0 {
+			devices = []string{UnknownDevice}
+		}
+		share := float64(len(devices))
+		for _, device := range devices {
+			entry, ok := byDevice[device]
+			if !ok {
+				entry = &DeviceProcesses{Device: device}
+				byDevice[device] = entry
+			}
+			entry.ReadBytesPerSec += read / share
+			entry.WriteBytesPerSec += written / share
+			entry.Processes = append(entry.Processes, ProcessDiskIO{
+				Process:          p,
+				ReadBytesPerSec:  read / share,
+				WriteBytesPerSec: written / share,
+			})
+		}
+	}
+
+	// 3. Busiest disks and processes first
+	result := make([]DeviceProcesses, 0, len(byDevice))
+	for _, entry := range byDevice {
+		sort.Slice(entry.Processes, func(i, j int) bool {
+			a, b := entry.Processes[i], entry.Processes[j]
+			return a.ReadBytesPerSec+a.WriteBytesPerSec > b.ReadBytesPerSec+b.WriteBytesPerSec
+		})
+		result = append(result, *entry)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ReadBytesPerSec+result[i].WriteBytesPerSec > result[j].ReadBytesPerSec+result[j].WriteBytesPerSec
+	})
+
+	return result, nil
+}
+
+// readAllIOCounters reads the storage I/O counters of every process
+// Other users' processes can only be read as root, so they are left out otherwise
+//
+// Returns: map of PID to counters
+func readAllIOCounters() map[int32]ioCounters {
+	entries, err := os.ReadDir(paths.Proc())
+	if err != nil {
+		return nil
+	}
+
+	counters := make(map[int32]ioCounters, len(entries))
+	for _, entry := range entries {
+		pid, err := strconv.ParseInt(entry.Name(), 10, 32)
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(paths.Proc(entry.Name(), "io"))
+		if err != nil {
+			continue
+		}
+
+		// read_bytes/write_bytes count what reached the storage layer (not cached reads)
+		var c ioCounters
+		for _, line := range strings.Split(string(data), "\n") {
+			key, value, ok := strings.Cut(line, ":")
+			if !ok {
+				continue
+			}
+			n, err := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+			if err != nil {
+				continue
+			}
+			switch key {
+			case "read_bytes":
+				c.readBytes = n
+			case "write_bytes":
+				c.writeBytes = n
+			}
+		}
+		counters[int32(pid)] = c
+	}
+	return counters
+}
+
+// PrintProcessIOByDevice prints the processes doing I/O on each disk in a formatted table
+//
+// Parameters:
+//   - devices: slice of DeviceProcesses to present (from GetProcessIOByDevice)
+//   - n: maximum number of processes shown per disk
+func PrintProcessIOByDevice(devices []DeviceProcesses, n int) {
+	table := common.NewTable("Disk I/O by Process",
+		common.Column{Header: "Disk"}, common.Column{Header: "PID"}, common.Column{Header: "Name", Fill: true},
+		common.Column{Header: "Read/s", Right: true}, common.Column{Header: "Write/s", Right: true})
+
+	if len(devices) == 0 {
+		table.AddNote("No disk I/O during the sample")
+	}
+
+	for i, d := range devices {
+		if i > 0 {
+			table.AddDivider()
+		}
+		table.AddRow(d.Device, "", "(total)", formatRate(d.ReadBytesPerSec), formatRate(d.WriteBytesPerSec))
+
+		for j, p := range d.Processes {
+			if j == n {
+				break
+			}
+			table.AddRow("", strconv.Itoa(int(p.Process.PID)), common.TruncateString(p.Process.Name, 25),
+				formatRate(p.ReadBytesPerSec), formatRate(p.WriteBytesPerSec))
+		}
+	}
+
+	table.Print()
+}
+
+// formatRate formats a throughput in bytes per second (e.g. "1.50 MB/s")
+func formatRate(bytesPerSec float64) string {
+	return common.FormatBytes(uint64(bytesPerSec)) + "/s"
+}
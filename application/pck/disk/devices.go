@@ -0,0 +1,188 @@
+package disk
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+)
+
+// PhysicalDisk contains the identity of a whole disk, read from sysfs
+type PhysicalDisk struct {
+	Name   string // Block device name (e.g. "sda", "nvme0n1")
+	Model  string // Device model (empty if not available)
+	Serial string // Serial number (empty if not available)
+	Size   uint64 // Capacity in bytes
+}
+
+// sectorSize is the unit of the sysfs "size" attribute (always 512 bytes, whatever the disk uses)
+const sectorSize = 512
+
+// GetPhysicalDisk reads the model, serial number and size of a disk from sysfs
+//
+// Parameters:
+//   - name: block device name (e.g. "sda")
+//
+// Returns: PhysicalDisk (fields that can't be read are left empty)
+func GetPhysicalDisk(name string) PhysicalDisk {
+	disk := PhysicalDisk{
+		Name:   name,
+		Model:  readSysfsModel(name),
+		Serial: readSysfsString(paths.Sys("block", name, "device", "serial")),
+	}
+	if sectors, err := strconv.ParseUint(readSysfsString(paths.Sys("block", name, "size")), 10, 64); err == nil {
+		disk.Size = sectors * sectorSize
+	}
+	return disk
+}
+
+// diskOfPath finds the physical disk a file or mount point is stored on
+//
+// Parameters:
+//   - path: any path (e.g. a mount point)
+//
+// Returns: whole disk name (empty if it isn't on a disk, e.g. tmpfs or network file systems)
+func (r *deviceResolver) diskOfPath(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return ""
+	}
+	return r.diskOf(stat.Dev)
+}
+
+// deviceResolver maps device numbers (major:minor) to whole disk names
+// Results are cached, since most processes share the same few file systems
+type deviceResolver struct {
+	mountSources map[string]string // major:minor → mount source (e.g. "/dev/sda1"), from the mount table
+	disks        map[string]string // major:minor → whole disk name ("" if not on a disk)
+}
+
+// newDeviceResolver creates a resolver with the current mount table
+func newDeviceResolver() *deviceResolver {
+	return &deviceResolver{
+		mountSources: readMountSources(),
+		disks:        make(map[string]string),
+	}
+}
+
+// processDisks finds the disks a process has regular files or directories open on
+//
+// Parameters:
+//   - pid: process ID
+//
+// Returns: whole disk names (empty if none were found or the process can't be read)
+func (r *deviceResolver) processDisks(pid int32) []string {
+	links, err := filepath.Glob(paths.Proc(fmt.Sprint(pid), "fd", "*"))
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var disks []string
+	for _, link := range links {
+		info, err := os.Stat(link) // Follows the link to the open file
+		if err != nil || !(info.Mode().IsRegular() || info.IsDir()) {
+			continue
+		}
+		stat, ok := info.Sys().(*syscall.Stat_t)
+		if !ok {
+			continue
+		}
+
+		disk := r.diskOf(stat.Dev)
+		if disk != "" && !seen[disk] {
+			seen[disk] = true
+			disks = append(disks, disk)
+		}
+	}
+	sort.Strings(disks)
+	return disks
+}
+
+// diskOf resolves a device number to the whole disk it belongs to
+// Block devices are found in /sys/dev/block; file systems with anonymous device numbers
+// (btrfs subvolumes, ...) are resolved through the source device in the mount table
+//
+// Parameters:
+//   - dev: device number (st_dev of a file)
+//
+// Returns: whole disk name (empty if the device isn't backed by a disk, e.g. tmpfs)
+func (r *deviceResolver) diskOf(dev uint64) string {
+	// Linux encoding of major/minor in dev_t (see gnu_dev_major/gnu_dev_minor)
+	major := (dev>>8)&0xfff | (dev>>32)&^uint64(0xfff)
+	minor := dev&0xff | (dev>>12)&^uint64(0xff)
+	id := fmt.Sprintf("%d:%d", major, minor)
+
+	if disk, ok := r.disks[id]; ok {
+		return disk
+	}
+
+	var disk string
+	if path, err := filepath.EvalSymlinks(paths.Sys("dev", "block", id)); err == nil {
+		disk = wholeDisk(path)
+	} else if source, ok := r.mountSources[id]; ok && strings.HasPrefix(source, "/dev/") {
+		if path, err := filepath.EvalSymlinks(paths.Sys("class", "block", filepath.Base(source))); err == nil {
+			disk = wholeDisk(path)
+		}
+	}
+
+	r.disks[id] = disk
+	return disk
+}
+
+// wholeDisk returns the physical disk of a block device sysfs path
+// Partitions map to their parent, and device-mapper/md devices (LVM, LUKS, RAID) to the
+// disk of their first underlying device
+func wholeDisk(sysfsPath string) string {
+	if _, err := os.Stat(filepath.Join(sysfsPath, "partition")); err == nil {
+		return filepath.Base(filepath.Dir(sysfsPath))
+	}
+
+	if slaves, _ := filepath.Glob(filepath.Join(sysfsPath, "slaves", "*")); len(slaves) > 0 {
+		if path, err := filepath.EvalSymlinks(slaves[0]); err == nil {
+			return wholeDisk(path)
+		}
+	}
+	return filepath.Base(sysfsPath)
+}
+
+// readMountSources reads the mount table of the current process
+// Each line of /proc/self/mountinfo has the device number (3rd field) and,
+// after the " - " separator, the file system type and its source
+//
+// Returns: map of major:minor to mount source
+func readMountSources() map[string]string {
+	sources := make(map[string]string)
+
+	file, err := os.Open(paths.Proc("self", "mountinfo"))
+	if err != nil {
+		return sources
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		mount, details, ok := strings.Cut(scanner.Text(), " - ")
+		if !ok {
+			continue
+		}
+		mountFields, detailFields := strings.Fields(mount), strings.Fields(details)
+		if len(mountFields) < 3 || len(detailFields) < 2 {
+			continue
+		}
+		if _, exists := sources[mountFields[2]]; !exists {
+			sources[mountFields[2]] = detailFields[1]
+		}
+	}
+	return sources
+}
@@ -1,6 +1,9 @@
 package disk
 
-import "strings"
+import (
+	"slices"
+	"strings"
+)
 
 // ignoredFsTypes contains a map of filesystem types to ignore
 // Uses map[string]struct{} because struct{} doesn't occupy memory space
@@ -82,31 +85,43 @@ func IsRealDisk(mountpoint string, fstype string) bool {
 	return true
 }
 
-// AddIgnoredFsType adds a filesystem type to the ignored list
-// Useful for customizing which filesystem types should be filtered
-//
-// Parameters:
-//   - fstype: filesystem type to ignore (e.g. "btrfs", "zfs")
-func AddIgnoredFsType(fstype string) {
-	ignoredFsTypes[fstype] = struct{}{}
+// Filter selects which mounts are listed, on top of the built-in filters of IsRealDisk
+// Each view gets its own Filter (from the config file and the command line), so the
+// built-in lists are never changed
+type Filter struct {
+	IncludeFsTypes []string // File system types to list even though IsRealDisk ignores them (e.g. "squashfs")
+	ExcludeMounts  []string // Mountpoints to hide, with everything mounted below them (e.g. "/mnt/backup")
+	MinSize        uint64   // Smallest partition listed in bytes (0 lists every size)
 }
 
-// RemoveIgnoredFsType removes a filesystem type from the ignored list
-// Useful if you want to monitor a filesystem type that's in the default list
-//
-// Parameters:
-//   - fstype: filesystem type to stop ignoring
-func RemoveIgnoredFsType(fstype string) {
-	delete(ignoredFsTypes, fstype)
+// DefaultFilter returns the filter used when nothing is configured: the built-in
+// filters and partitions of at least MinStorageSize
+func DefaultFilter() Filter {
+	return Filter{MinSize: MinStorageSize}
 }
 
-// AddIgnoredPrefix adds a path prefix to the ignored list
-// Useful for customizing which paths should be filtered
+// Allows checks if a mount passes the filter
 //
 // Parameters:
-//   - prefix: path prefix to ignore (e.g. "/mnt/temp")
-func AddIgnoredPrefix(prefix string) {
-	ignoredPrefixes = append(ignoredPrefixes, prefix)
+//   - mountpoint: path where the filesystem is mounted (e.g. "/", "/home")
+//   - fstype: filesystem type (e.g. "ext4", "squashfs")
+//
+// Returns: true if the mount should be listed (its size is checked separately, see MinSize)
+func (f Filter) Allows(mountpoint string, fstype string) bool {
+	// 1. Explicitly hidden mountpoints ("/mnt/backup" also hides "/mnt/backup/old", not "/mnt/backups")
+	for _, excluded := range f.ExcludeMounts {
+		excluded = strings.TrimSuffix(excluded, "/")
+		if mountpoint == excluded || strings.HasPrefix(mountpoint, excluded+"/") {
+			return false
+		}
+	}
+
+	// 2. Explicitly included types skip the built-in filters
+	if slices.Contains(f.IncludeFsTypes, fstype) {
+		return true
+	}
+
+	return IsRealDisk(mountpoint, fstype)
 }
 
 // GetIgnoredFsTypes returns a list of all ignored filesystem types
@@ -0,0 +1,284 @@
+package disk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+)
+
+// Sources of the health data
+const (
+	HealthSourceSmartctl = "smartctl" // smartctl --json (complete SMART data, usually needs root)
+	HealthSourceSysfs    = "sysfs"    // /sys/block (model and temperature only)
+)
+
+// DeviceHealth contains the SMART health data of a physical disk
+// Values that the device or data source doesn't report are -1
+type DeviceHealth struct {
+	Name               string // Block device name (e.g. "sda", "nvme0n1")
+	Model              string // Device model
+	Serial             string // Serial number (empty if not available)
+	Source             string // Where the data came from (HealthSourceSmartctl or HealthSourceSysfs)
+	Status             string // Overall SMART assessment: "PASSED", "FAILED" or "" if unknown
+	Temperature        int    // Current temperature in °C
+	PercentUsed        int    // Estimated wear (0% = new, 100% = rated endurance used)
+	ReallocatedSectors int64  // Sectors remapped to spare sectors (ATA attribute 5)
+	MediaErrors        int64  // Unrecovered data integrity errors (NVMe)
+	PowerOnHours       int64  // Total time powered on
+}
+
+// smartctlOutput contains the fields read from "smartctl --json -a"
+type smartctlOutput struct {
+	ModelName    string `json:"model_name"`
+	SerialNumber string `json:"serial_number"`
+	SmartStatus  *struct {
+		Passed bool `json:"passed"`
+	} `json:"smart_status"`
+	Temperature *struct {
+		Current int `json:"current"`
+	} `json:"temperature"`
+	PowerOnTime *struct {
+		Hours int64 `json:"hours"`
+	} `json:"power_on_time"`
+	NVMeHealth *struct {
+		PercentageUsed int   `json:"percentage_used"`
+		MediaErrors    int64 `json:"media_errors"`
+	} `json:"nvme_smart_health_information_log"`
+	ATAAttributes *struct {
+		Table []struct {
+			ID    int `json:"id"`
+			Value int `json:"value"`
+			Raw   struct {
+				Value int64 `json:"value"`
+			} `json:"raw"`
+		} `json:"table"`
+	} `json:"ata_smart_attributes"`
+}
+
+// ATA SMART attributes used for the wear and reallocation values
+const (
+	ataReallocatedSectors = 5   // Reallocated_Sector_Ct (raw value is the count)
+	ataWearLeveling       = 177 // Wear_Leveling_Count (normalized value: 100 = new)
+	ataSSDLifeLeft        = 231 // SSD_Life_Left (normalized value: 100 = new)
+)
+
+// GetDeviceHealth collects the health of every physical disk
+// Uses smartctl when it is installed and can read the device, and falls back to sysfs
+//
+// Returns:
+//   - slice of DeviceHealth sorted by device name
+//   - error if the block devices can't be listed
+func GetDeviceHealth() ([]DeviceHealth, error) {
+	entries, err := os.ReadDir(paths.Sys("block"))
+	if err != nil {
+		return nil, fmt.Errorf("error listing block devices: %w", err)
+	}
+
+	_, lookErr := exec.LookPath("smartctl")
+	hasSmartctl := lookErr == nil
+
+	var devices []DeviceHealth
+	for _, entry := range entries {
+		name := entry.Name()
+		if !isPhysicalBlockDevice(name) || isRemovedDevice(name) {
+			continue
+		}
+
+		if hasSmartctl {
+			health, err := readSmartctl(name)
+			if err == nil {
+				devices = append(devices, health)
+				continue
+			}
+			common.Logger().Warn("error reading SMART data, using sysfs", "device", name, "err", err)
+		}
+		devices = append(devices, readSysfsHealth(name))
+	}
+
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].Name < devices[j].Name
+	})
+	return devices, nil
+}
+
+// unknownHealth returns a DeviceHealth with every value marked as not available
+func unknownHealth(name, source string) DeviceHealth {
+	return DeviceHealth{
+		Name:               name,
+		Source:             source,
+		Temperature:        -1,
+		PercentUsed:        -1,
+		ReallocatedSectors: -1,
+		MediaErrors:        -1,
+		PowerOnHours:       -1,
+	}
+}
+
+// isRemovedDevice checks if a block device has no backing media (e.g. an empty card reader)
+func isRemovedDevice(name string) bool {
+	size, err := os.ReadFile(paths.Sys("block", name, "size"))
+	return err == nil && strings.TrimSpace(string(size)) == "0"
+}
+
+// readSmartctl reads the SMART data of a device with smartctl
+// smartctl's exit status is a bit mask that is also set for warnings (e.g. old errors
+// in the log), so the JSON output is parsed whenever there is one
+//
+// Parameters:
+//   - name: block device name
+//
+// Returns:
+//   - DeviceHealth filled from the SMART data
+//   - error if smartctl can't read the device (e.g. missing permissions, no SMART support)
+func readSmartctl(name string) (DeviceHealth, error) {
+	output, runErr := common.RunCommand(context.Background(), "smartctl", "--json", "-a", "/dev/"+name)
+
+	var data smartctlOutput
+	if err := json.Unmarshal(output, &data); err != nil {
+		if runErr != nil {
+			return DeviceHealth{}, fmt.Errorf("error running smartctl: %w", runErr)
+		}
+		return DeviceHealth{}, fmt.Errorf("error parsing smartctl output: %w", err)
+	}
+	if data.SmartStatus == nil && data.Temperature == nil && data.PowerOnTime == nil {
+		return DeviceHealth{}, fmt.Errorf("no SMART data (permission denied or not supported)")
+	}
+
+	health := unknownHealth(name, HealthSourceSmartctl)
+	health.Model = data.ModelName
+	health.Serial = data.SerialNumber
+
+	if data.SmartStatus != nil {
+		health.Status = "FAILED"
+		if data.SmartStatus.Passed {
+			health.Status = "PASSED"
+		}
+	}
+	if data.Temperature != nil {
+		health.Temperature = data.Temperature.Current
+	}
+	if data.PowerOnTime != nil {
+		health.PowerOnHours = data.PowerOnTime.Hours
+	}
+
+	// NVMe drives report wear and media errors directly
+	if data.NVMeHealth != nil {
+		health.PercentUsed = data.NVMeHealth.PercentageUsed
+		health.MediaErrors = data.NVMeHealth.MediaErrors
+	}
+
+	// ATA drives report them as vendor attributes
+	if data.ATAAttributes != nil {
+		for _, attribute := range data.ATAAttributes.Table {
+			switch attribute.ID {
+			case ataReallocatedSectors:
+				health.ReallocatedSectors = attribute.Raw.Value
+			case ataWearLeveling, ataSSDLifeLeft:
+				if health.PercentUsed < 0 && attribute.Value <= 100 {
+					health.PercentUsed = 100 - attribute.Value
+				}
+			}
+		}
+	}
+
+	if health.Model == "" {
+		health.Model = readSysfsModel(name)
+	}
+	return health, nil
+}
+
+// readSysfsHealth reads what the kernel exposes without SMART access: model and temperature
+// The temperature comes from the hwmon of the nvme driver or the drivetemp module (SATA)
+//
+// Parameters:
+//   - name: block device name
+//
+// Returns: DeviceHealth with the values found in sysfs
+func readSysfsHealth(name string) DeviceHealth {
+	health := unknownHealth(name, HealthSourceSysfs)
+	health.Model = readSysfsModel(name)
+	health.Serial = readSysfsString(paths.Sys("block", name, "device", "serial"))
+
+	patterns := []string{
+		paths.Sys("block", name, "device", "hwmon*", "temp1_input"),          // NVMe (controller hwmon)
+		paths.Sys("block", name, "device", "hwmon", "hwmon*", "temp1_input"), // SATA (drivetemp)
+	}
+	for _, pattern := range patterns {
+		matches, _ := filepath.Glob(pattern)
+		for _, path := range matches {
+			if milli, err := strconv.Atoi(readSysfsString(path)); err == nil && milli > 0 {
+				health.Temperature = milli / 1000
+				return health
+			}
+		}
+	}
+	return health
+}
+
+// readSysfsModel reads the model of a block device from sysfs
+func readSysfsModel(name string) string {
+	return readSysfsString(paths.Sys("block", name, "device", "model"))
+}
+
+// readSysfsString reads a sysfs attribute without surrounding whitespace (empty on error)
+func readSysfsString(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// PrintDeviceHealth prints the health of every physical disk in a formatted table
+//
+// Parameters:
+//   - devices: slice of DeviceHealth to present (from GetDeviceHealth)
+func PrintDeviceHealth(devices []DeviceHealth) {
+	box := common.NewBox("Disk Health (SMART)")
+
+	if len(devices) == 0 {
+		box.Text("No physical disks found")
+	}
+
+	for i, d := range devices {
+		if i > 0 {
+			box.Divider()
+		}
+
+		model := d.Model
+		if model == "" {
+			model = "Unknown model"
+		}
+		status := d.Status
+		if status == "" {
+			status = "N/A"
+		}
+
+		box.Text(fmt.Sprintf("/dev/%s  %s  (%s)", d.Name, model, d.Source))
+		box.Field("SMART status", status)
+		box.Field("Temperature", formatHealthValue(int64(d.Temperature), "%d°C"))
+		box.Field("Wear", formatHealthValue(int64(d.PercentUsed), "%d%% used"))
+		box.Field("Reallocated", formatHealthValue(d.ReallocatedSectors, "%d sectors"))
+		box.Field("Media errors", formatHealthValue(d.MediaErrors, "%d"))
+		box.Field("Power-on time", formatHealthValue(d.PowerOnHours, "%d hours"))
+	}
+
+	box.End()
+}
+
+// formatHealthValue formats a health value, or "N/A" if it isn't available (-1)
+func formatHealthValue(value int64, format string) string {
+	if value < 0 {
+		return "N/A"
+	}
+	return fmt.Sprintf(format, value)
+}
@@ -1,9 +1,18 @@
 package disk
 
 import (
+	"encoding/csv"
 	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+	"github.com/dfialho05/GoMonitor/application/pck/procfs"
 	"github.com/shirou/gopsutil/v3/disk"
 )
 
@@ -16,6 +25,15 @@ type StorageDevice struct {
 	Used       uint64  // Used disk space in bytes
 	Free       uint64  // Free disk space in bytes
 	Percent    float64 // Usage percentage (0-100%)
+	Device     string  // Partition device (e.g. "/dev/sda1")
+	Disk       string  // Physical disk the partition is on (e.g. "sda", empty if unknown)
+
+	// Inodes (0 on file systems without a fixed inode table, e.g. btrfs or vfat)
+	// A disk with free space can't create files once every inode is used
+	InodesTotal   uint64  // Total inodes
+	InodesUsed    uint64  // Used inodes
+	InodesFree    uint64  // Free inodes
+	InodesPercent float64 // Inode usage percentage (0-100%)
 }
 
 const (
@@ -27,24 +45,46 @@ const (
 // GetAllStorageDevices collects information about all storage devices
 // This function automatically filters virtual and temporary file systems
 //
+// Parameters:
+//   - filter: mounts to include or hide and the minimum size (DefaultFilter for the built-in filters)
+//
+// Returns:
+//   - slice of StorageDevice with all real physical disks in the system
+//   - error if unable to get the information
+func GetAllStorageDevices(filter Filter) ([]StorageDevice, error) {
+	devices, _, err := GetAllStorageDevicesWithStats(filter)
+	return devices, err
+}
+
+// GetAllStorageDevicesWithStats collects all storage devices and counts the partitions
+// that couldn't be read (e.g. network mounts that don't answer, FUSE mounts of other users)
+// Filtered partitions (virtual, hidden or too small) are not counted as skipped
+//
+// Parameters:
+//   - filter: mounts to include or hide and the minimum size
+//
 // Returns:
 //   - slice of StorageDevice with all real physical disks in the system
+//   - CollectionStats with the number of shown and skipped partitions
 //   - error if unable to get the information
-func GetAllStorageDevices() ([]StorageDevice, error) {
+func GetAllStorageDevicesWithStats(filter Filter) ([]StorageDevice, common.CollectionStats, error) {
+	var stats common.CollectionStats
+
 	// 1. Get all system partitions
 	// false = don't include virtual partitions (but we still need to filter manually)
 	partitions, err := disk.Partitions(false)
 	if err != nil {
-		return nil, fmt.Errorf("error getting disk partitions: %w", err)
+		return nil, stats, fmt.Errorf("error getting disk partitions: %w", err)
 	}
 
 	// 2. Pre-allocate slice with estimated capacity to avoid reallocations
 	storageList := make([]StorageDevice, 0, len(partitions))
+	resolver := newDeviceResolver()
 
 	// 3. Iterate through each partition and collect its statistics
 	for _, partition := range partitions {
-		// 3.1. Check if it's a real disk (not virtual/temporary)
-		if !IsRealDisk(partition.Mountpoint, partition.Fstype) {
+		// 3.1. Check if it's a real disk (not virtual/temporary) or explicitly included
+		if !filter.Allows(partition.Mountpoint, partition.Fstype) {
 			continue
 		}
 
@@ -53,11 +93,12 @@ func GetAllStorageDevices() ([]StorageDevice, error) {
 		if err != nil {
 			// If we can't get usage, skip this partition
 			// This can happen if the disk is removed or not accessible
+			stats.Skip(fmt.Errorf("%s: %w", partition.Mountpoint, err))
 			continue
 		}
 
 		// 3.3. Filter very small disks (boot partitions, EFI, etc.)
-		if usage.Total < MinStorageSize {
+		if usage.Total < filter.MinSize {
 			continue
 		}
 
@@ -69,10 +110,19 @@ func GetAllStorageDevices() ([]StorageDevice, error) {
 			Used:       usage.Used,
 			Free:       usage.Free,
 			Percent:    usage.UsedPercent,
+			Device:     partition.Device,
+			Disk:       resolver.diskOfPath(partition.Mountpoint),
+
+			InodesTotal:   usage.InodesTotal,
+			InodesUsed:    usage.InodesUsed,
+			InodesFree:    usage.InodesFree,
+			InodesPercent: usage.InodesUsedPercent,
 		})
 	}
+	stats.Shown = len(storageList)
 
-	return storageList, nil
+	common.ReportSkipped("partitions", stats)
+	return storageList, stats, nil
 }
 
 // GetStorageByMountpoint gets information about a specific disk by its mount point
@@ -99,9 +149,11 @@ func GetStorageByMountpoint(mountpoint string) (*StorageDevice, error) {
 
 	// Search for the partition corresponding to the mount point
 	fstype := "unknown"
+	device := ""
 	for _, partition := range partitions {
 		if partition.Mountpoint == mountpoint {
 			fstype = partition.Fstype
+			device = partition.Device
 			break
 		}
 	}
@@ -114,17 +166,27 @@ func GetStorageByMountpoint(mountpoint string) (*StorageDevice, error) {
 		Used:       usage.Used,
 		Free:       usage.Free,
 		Percent:    usage.UsedPercent,
+		Device:     device,
+		Disk:       newDeviceResolver().diskOfPath(mountpoint),
+
+		InodesTotal:   usage.InodesTotal,
+		InodesUsed:    usage.InodesUsed,
+		InodesFree:    usage.InodesFree,
+		InodesPercent: usage.InodesUsedPercent,
 	}, nil
 }
 
 // PrintStorageDevices prints information about all storage devices
-// This function presents a formatted table with data from all disks
+// Partitions are grouped under the physical disk they are on, with its model and serial
+//
+// Parameters:
+//   - filter: mounts to include or hide and the minimum size
 //
 // Returns:
 //   - error if unable to get disk data
-func PrintStorageDevices() error {
+func PrintStorageDevices(filter Filter) error {
 	// Get all storage devices
-	devices, err := GetAllStorageDevices()
+	devices, err := GetAllStorageDevices(filter)
 	if err != nil {
 		return err
 	}
@@ -135,58 +197,112 @@ func PrintStorageDevices() error {
 		return nil
 	}
 
+	// Group the partitions by physical disk (partitions on an unknown disk go last)
+	sort.SliceStable(devices, func(i, j int) bool {
+		if (devices[i].Disk == "") != (devices[j].Disk == "") {
+			return devices[j].Disk == ""
+		}
+		return devices[i].Disk < devices[j].Disk
+	})
+
 	// Print header
-	fmt.Printf("\n╔══════════════════════════════════════════════════════════════════════════════════╗\n")
-	fmt.Printf("║  %-80s  ║\n", "Storage Devices")
-	fmt.Printf("╠══════════════════════════════════════════════════════════════════════════════════╣\n")
+	box := common.NewBox("Storage Devices")
 
-	// Print each device
+	// Print each device, with a disk header whenever the disk changes
 	for i, device := range devices {
-		if i > 0 {
-			fmt.Printf("╟──────────────────────────────────────────────────────────────────────────────────╢\n")
+		if i == 0 || device.Disk != devices[i-1].Disk {
+			box.Separator()
+			box.Text(diskTitle(device.Disk))
 		}
-
-		fmt.Printf("║  Mount Point:       %-58s  ║\n", common.TruncateString(device.Mountpoint, 58))
-		fmt.Printf("║  File System:       %-58s  ║\n", device.Fstype)
-		fmt.Printf("║  Total:             %-58s  ║\n", common.FormatBytes(device.Total))
-		fmt.Printf("║  Used:              %-58s  ║\n", common.FormatBytes(device.Used))
-		fmt.Printf("║  Free:              %-58s  ║\n", common.FormatBytes(device.Free))
-		fmt.Printf("║  Usage:             %-58.2f %%    ║\n", device.Percent)
+		box.Divider()
+
+		box.Field("Mount Point", device.Mountpoint)
+		box.Field("Partition", device.Device)
+		box.Field("File System", device.Fstype)
+		box.Field("Total", common.FormatBytes(device.Total))
+		box.Field("Used", common.FormatBytes(device.Used))
+		box.Field("Free", common.FormatBytes(device.Free))
+		box.Fieldf("Usage", "%.2f %%", device.Percent)
+		box.Field("Inodes", formatInodes(device))
 	}
 
-	fmt.Printf("╚══════════════════════════════════════════════════════════════════════════════════╝\n")
+	box.End()
 
 	return nil
 }
 
+// diskTitle describes a physical disk for the storage table (e.g. "/dev/sda  Samsung SSD 870 (S5Y1...)  500.11 GB")
+func diskTitle(name string) string {
+	if name == "" {
+		return "Unknown disk (network, virtual or overlay file systems)"
+	}
+
+	physical := GetPhysicalDisk(name)
+	parts := []string{"/dev/" + name}
+	if physical.Model != "" {
+		parts = append(parts, physical.Model)
+	}
+	if physical.Serial != "" {
+		parts = append(parts, "("+physical.Serial+")")
+	}
+	if physical.Size > 0 {
+		parts = append(parts, common.FormatBytes(physical.Size))
+	}
+	return strings.Join(parts, "  ")
+}
+
 // PrintStorageDevice prints information about a single storage device
 // This function is useful for showing details of a specific disk
 //
 // Parameters:
 //   - device: StorageDevice with data to present
 func PrintStorageDevice(device StorageDevice) {
-	fmt.Printf("\n╔══════════════════════════════════════════════════════════════════════════════════╗\n")
-	fmt.Printf("║  %-80s  ║\n", "Disk Information")
-	fmt.Printf("╠══════════════════════════════════════════════════════════════════════════════════╣\n")
-	fmt.Printf("║  Mount Point:       %-58s  ║\n", common.TruncateString(device.Mountpoint, 58))
-	fmt.Printf("║  File System:       %-58s  ║\n", device.Fstype)
-	fmt.Printf("║  Total:             %-58s  ║\n", common.FormatBytes(device.Total))
-	fmt.Printf("║  Used:              %-58s  ║\n", common.FormatBytes(device.Used))
-	fmt.Printf("║  Free:              %-58s  ║\n", common.FormatBytes(device.Free))
-	fmt.Printf("║  Usage:             %-58.2f %%    ║\n", device.Percent)
-	fmt.Printf("╚══════════════════════════════════════════════════════════════════════════════════╝\n")
+	box := common.NewBox("Disk Information")
+	box.Field("Mount Point", device.Mountpoint)
+	box.Field("File System", device.Fstype)
+	box.Field("Total", common.FormatBytes(device.Total))
+	box.Field("Used", common.FormatBytes(device.Used))
+	box.Field("Free", common.FormatBytes(device.Free))
+	box.Fieldf("Usage", "%.2f %%", device.Percent)
+	box.Field("Inodes", formatInodes(device))
+	box.End()
+}
+
+// formatInodes describes the inode usage of a device (e.g. "1.24M of 6.55M used, 18.93 %")
+func formatInodes(device StorageDevice) string {
+	if device.InodesTotal == 0 {
+		return "n/a (" + device.Fstype + " allocates inodes dynamically)"
+	}
+	return fmt.Sprintf("%s of %s used, %.2f %%", formatCount(device.InodesUsed), formatCount(device.InodesTotal), device.InodesPercent)
+}
+
+// formatCount formats a large count with a metric suffix (e.g. 1240000 → "1.24M")
+func formatCount(count uint64) string {
+	switch {
+	case count >= 1e9:
+		return fmt.Sprintf("%.2fG", float64(count)/1e9)
+	case count >= 1e6:
+		return fmt.Sprintf("%.2fM", float64(count)/1e6)
+	case count >= 1e3:
+		return fmt.Sprintf("%.2fK", float64(count)/1e3)
+	default:
+		return fmt.Sprint(count)
+	}
 }
 
 // GetTotalStorageStats calculates total statistics from all disks
 // This function aggregates information from all storage devices
 //
+// Parameters:
+//   - filter: mounts to include or hide and the minimum size
+//
 // Returns:
 //   - total: sum of all available storage space in bytes
 //   - used: sum of all used space in bytes
 //   - free: sum of all free space in bytes
 //   - error if unable to get the data
-func GetTotalStorageStats() (uint64, uint64, uint64, error) {
-	devices, err := GetAllStorageDevices()
+func GetTotalStorageStats(filter Filter) (uint64, uint64, uint64, error) {
+	devices, err := GetAllStorageDevices(filter)
 	if err != nil {
 		return 0, 0, 0, err
 	}
@@ -205,10 +321,13 @@ func GetTotalStorageStats() (uint64, uint64, uint64, error) {
 // PrintTotalStorageStats prints aggregated statistics from all disks
 // This function shows a summary of total storage space in the system
 //
+// Parameters:
+//   - filter: mounts to include or hide and the minimum size
+//
 // Returns:
 //   - error if unable to get the data
-func PrintTotalStorageStats() error {
-	total, used, free, err := GetTotalStorageStats()
+func PrintTotalStorageStats(filter Filter) error {
+	total, used, free, err := GetTotalStorageStats(filter)
 	if err != nil {
 		return err
 	}
@@ -218,14 +337,12 @@ func PrintTotalStorageStats() error {
 		percent = (float64(used) / float64(total)) * 100
 	}
 
-	fmt.Printf("\n╔══════════════════════════════════════════════════════════════════════════════════╗\n")
-	fmt.Printf("║  %-80s  ║\n", "Total System Storage")
-	fmt.Printf("╠══════════════════════════════════════════════════════════════════════════════════╣\n")
-	fmt.Printf("║  Total:             %-58s  ║\n", common.FormatBytes(total))
-	fmt.Printf("║  Used:              %-58s  ║\n", common.FormatBytes(used))
-	fmt.Printf("║  Free:              %-58s  ║\n", common.FormatBytes(free))
-	fmt.Printf("║  Usage:             %-58.2f %%    ║\n", percent)
-	fmt.Printf("╚══════════════════════════════════════════════════════════════════════════════════╝\n")
+	box := common.NewBox("Total System Storage")
+	box.Field("Total", common.FormatBytes(total))
+	box.Field("Used", common.FormatBytes(used))
+	box.Field("Free", common.FormatBytes(free))
+	box.Fieldf("Usage", "%.2f %%", percent)
+	box.End()
 
 	return nil
 }
@@ -237,9 +354,182 @@ func PrintTotalStorageStats() error {
 //   - map with disk name as key and IOCountersStat as value
 //   - error if unable to get the data
 func GetIOCounters() (map[string]disk.IOCountersStat, error) {
+	// The procfs backend reads /proc/diskstats directly, into the same structure
+	if common.UsingProcfs() {
+		stats, err := procfs.ReadDiskstats()
+		if err != nil {
+			return nil, fmt.Errorf("error getting I/O counters: %w", err)
+		}
+		ioCounters := make(map[string]disk.IOCountersStat, len(stats))
+		for name, s := range stats {
+			ioCounters[name] = disk.IOCountersStat{
+				Name:             name,
+				ReadCount:        s.ReadCount,
+				MergedReadCount:  s.MergedReadCount,
+				ReadBytes:        s.ReadBytes,
+				ReadTime:         s.ReadTime,
+				WriteCount:       s.WriteCount,
+				MergedWriteCount: s.MergedWriteCount,
+				WriteBytes:       s.WriteBytes,
+				WriteTime:        s.WriteTime,
+				IopsInProgress:   s.InProgress,
+				IoTime:           s.IoTime,
+				WeightedIO:       s.WeightedIO,
+			}
+		}
+		return ioCounters, nil
+	}
+
 	ioCounters, err := disk.IOCounters()
 	if err != nil {
 		return nil, fmt.Errorf("error getting I/O counters: %w", err)
 	}
 	return ioCounters, nil
 }
+
+// DeviceUtilization contains the I/O activity of a physical block device over an interval
+// Utilization is the share of wall time the device had at least one request in flight
+type DeviceUtilization struct {
+	Name             string  // Block device name (e.g. "sda", "nvme0n1")
+	Percent          float64 // Busy time percentage over the interval (0-100%)
+	ReadBytesPerSec  float64 // Read throughput in bytes per second
+	WriteBytesPerSec float64 // Write throughput in bytes per second
+}
+
+// CalculateUtilization computes per-device utilization from two I/O counter snapshots
+// Only whole physical devices (entries present in /sys/block) are reported,
+// skipping partitions and virtual devices such as loop and ram disks
+//
+// Parameters:
+//   - before: I/O counters taken at the start of the interval
+//   - after: I/O counters taken at the end of the interval
+//   - elapsed: time between both snapshots
+//
+// Returns:
+//   - slice of DeviceUtilization, one entry per physical device
+func CalculateUtilization(before, after map[string]disk.IOCountersStat, elapsed time.Duration) []DeviceUtilization {
+	seconds := elapsed.Seconds()
+	if seconds <= 0 {
+		return nil
+	}
+
+	devices := make([]DeviceUtilization, 0, len(after))
+	for name, end := range after {
+		start, ok := before[name]
+		if !ok || !isPhysicalBlockDevice(name) {
+			continue
+		}
+
+		// IoTime is in milliseconds, so convert the interval to milliseconds too
+		percent := float64(end.IoTime-start.IoTime) / (seconds * 1000) * 100
+		if percent > 100 {
+			percent = 100
+		}
+
+		devices = append(devices, DeviceUtilization{
+			Name:             name,
+			Percent:          percent,
+			ReadBytesPerSec:  float64(end.ReadBytes-start.ReadBytes) / seconds,
+			WriteBytesPerSec: float64(end.WriteBytes-start.WriteBytes) / seconds,
+		})
+	}
+
+	// Keep a stable, predictable order for printing
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].Name < devices[j].Name
+	})
+
+	return devices
+}
+
+// UtilizationSampler measures the activity of the block devices between calls
+// Kept by long-running views (e.g. the TUI), which get the activity since their previous refresh
+type UtilizationSampler struct {
+	previous map[string]disk.IOCountersStat // Counters of the previous call (nil before the first one)
+	taken    time.Time                      // When the previous counters were read
+}
+
+// NewUtilizationSampler creates a sampler without previous counters
+//
+// Returns: pointer to a configured UtilizationSampler
+func NewUtilizationSampler() *UtilizationSampler {
+	return &UtilizationSampler{}
+}
+
+// Sample reads the I/O counters and computes the activity of each device since the previous call
+//
+// Returns:
+//   - slice of DeviceUtilization (nil on the first call)
+//   - error if the counters can't be read
+func (s *UtilizationSampler) Sample() ([]DeviceUtilization, error) {
+	counters, err := GetIOCounters()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	previous, taken := s.previous, s.taken
+	s.previous, s.taken = counters, now
+	if previous == nil {
+		return nil, nil
+	}
+	return CalculateUtilization(previous, counters, now.Sub(taken)), nil
+}
+
+// isPhysicalBlockDevice checks if a block device name is a whole, non-virtual device
+// Partitions don't have their own entry in /sys/block, so they are filtered out as well
+func isPhysicalBlockDevice(name string) bool {
+	for _, prefix := range []string{"loop", "ram", "zram", "dm-"} {
+		if strings.HasPrefix(name, prefix) {
+			return false
+		}
+	}
+
+	_, err := os.Stat(paths.Sys("block", name))
+	return err == nil
+}
+
+// WriteStorageCSV writes information about all storage devices as CSV (with a header row)
+//
+// Parameters:
+//   - w: destination (file or stdout)
+//   - filter: mounts to include or hide and the minimum size
+//
+// Returns:
+//   - error if unable to get disk data or writing fails
+func WriteStorageCSV(w io.Writer, filter Filter) error {
+	devices, err := GetAllStorageDevices(filter)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	header := []string{"mountpoint", "fstype", "total_bytes", "used_bytes", "free_bytes", "used_percent", "device", "disk",
+		"inodes_total", "inodes_used", "inodes_free", "inodes_percent"}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("error writing CSV header: %w", err)
+	}
+
+	for _, device := range devices {
+		record := []string{
+			device.Mountpoint,
+			device.Fstype,
+			strconv.FormatUint(device.Total, 10),
+			strconv.FormatUint(device.Used, 10),
+			strconv.FormatUint(device.Free, 10),
+			strconv.FormatFloat(device.Percent, 'f', 2, 64),
+			device.Device,
+			device.Disk,
+			strconv.FormatUint(device.InodesTotal, 10),
+			strconv.FormatUint(device.InodesUsed, 10),
+			strconv.FormatUint(device.InodesFree, 10),
+			strconv.FormatFloat(device.InodesPercent, 'f', 2, 64),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("error writing CSV record: %w", err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
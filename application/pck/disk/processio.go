@@ -0,0 +1,173 @@
+package disk
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// IORate contains the disk throughput of a process
+type IORate struct {
+	ReadBytesPerSec  float64 // Bytes read from storage per second
+	WriteBytesPerSec float64 // Bytes written to storage per second
+}
+
+// Total returns the combined read and write throughput
+func (r IORate) Total() float64 {
+	return r.ReadBytesPerSec + r.WriteBytesPerSec
+}
+
+// ioSample is the I/O counters of a process at one point in time
+type ioSample struct {
+	readBytes  uint64    // Bytes read from storage since the process started
+	writeBytes uint64    // Bytes written to storage since the process started
+	createTime int64     // Process start time, so a reused PID isn't mixed up with the old process
+	time       time.Time // When the counters were read
+}
+
+// IOSampler measures the disk throughput of processes between calls
+// Kept by long-running views (e.g. the TUI), which get the rate since their previous refresh
+type IOSampler struct {
+	previous map[int32]ioSample // Counters of the previous call, keyed by PID
+}
+
+// NewIOSampler creates a sampler without previous counters
+//
+// Returns: pointer to a configured IOSampler
+func NewIOSampler() *IOSampler {
+	return &IOSampler{previous: make(map[int32]ioSample)}
+}
+
+// Sample reads the I/O counters of the processes and returns their rates since the previous call
+// Processes seen for the first time (and those whose counters can't be read, usually other
+// users' processes without root) have no rate yet
+//
+// Parameters:
+//   - processes: processes to measure
+//
+// Returns: map of PID to IORate
+func (s *IOSampler) Sample(processes []common.ProcessInfo) map[int32]IORate {
+	now := time.Now()
+	current := make(map[int32]ioSample, len(processes))
+	rates := make(map[int32]IORate, len(processes))
+
+	for _, p := range processes {
+		counters, err := (&process.Process{Pid: p.PID}).IOCounters()
+		if err != nil {
+			continue
+		}
+		sample := ioSample{
+			readBytes:  counters.ReadBytes,
+			writeBytes: counters.WriteBytes,
+			createTime: p.CreateTime,
+			time:       now,
+		}
+		current[p.PID] = sample
+
+		previous, ok := s.previous[p.PID]
+		if !ok || previous.createTime != sample.createTime ||
+			sample.readBytes < previous.readBytes || sample.writeBytes < previous.writeBytes {
+			continue
+		}
+		seconds := now.Sub(previous.time).Seconds()
+		if seconds <= 0 {
+			continue
+		}
+		rates[p.PID] = IORate{
+			ReadBytesPerSec:  float64(sample.readBytes-previous.readBytes) / seconds,
+			WriteBytesPerSec: float64(sample.writeBytes-previous.writeBytes) / seconds,
+		}
+	}
+
+	s.previous = current
+	return rates
+}
+
+// MeasureProcessIO sets the DiskIO of processes to their throughput over an interval
+// Used by listings that can sort by or show the disk I/O (e.g. top --sort io)
+//
+// Parameters:
+//   - processes: processes to measure (their DiskIO is set in-place)
+//   - interval: how long the I/O is measured for
+func MeasureProcessIO(processes []common.ProcessInfo, interval time.Duration) {
+	sampler := NewIOSampler()
+	sampler.Sample(processes)
+	time.Sleep(interval)
+	rates := sampler.Sample(processes)
+	for i := range processes {
+		processes[i].DiskIO = rates[processes[i].PID].Total()
+	}
+}
+
+// GetTopProcessesByIO measures which processes read and write the most to storage
+//
+// Parameters:
+//   - n: maximum number of processes to return (0 or less for all)
+//   - interval: how long the I/O is measured for
+//
+// Returns:
+//   - slice of ProcessDiskIO sorted by total throughput (descending), idle processes left out
+//   - error if the processes can't be read
+func GetTopProcessesByIO(n int, interval time.Duration) ([]ProcessDiskIO, error) {
+	processes, err := common.CollectAllProcessInfo()
+	if err != nil {
+		return nil, fmt.Errorf("error collecting processes: %w", err)
+	}
+
+	// 1. Read the counters twice, interval apart
+	sampler := NewIOSampler()
+	sampler.Sample(processes)
+	time.Sleep(interval)
+	rates := sampler.Sample(processes)
+
+	// 2. Busiest processes first
+	var top []ProcessDiskIO
+	for _, p := range processes {
+		rate, ok := rates[p.PID]
+		if !ok || rate.Total() == 0 {
+			continue
+		}
+		top = append(top, ProcessDiskIO{
+			Process:          p,
+			ReadBytesPerSec:  rate.ReadBytesPerSec,
+			WriteBytesPerSec: rate.WriteBytesPerSec,
+		})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		return top[i].ReadBytesPerSec+top[i].WriteBytesPerSec > top[j].ReadBytesPerSec+top[j].WriteBytesPerSec
+	})
+	if n > 0 && len(top) > n {
+		top = top[:n]
+	}
+	return top, nil
+}
+
+// PrintTopProcessesByIO prints the processes doing the most disk I/O in a formatted table
+//
+// Parameters:
+//   - processes: slice of ProcessDiskIO to present (from GetTopProcessesByIO)
+//   - interval: how long the I/O was measured for
+func PrintTopProcessesByIO(processes []ProcessDiskIO, interval time.Duration) {
+	table := common.NewTable(fmt.Sprintf("Top Processes by Disk I/O (measured over %s)", interval),
+		common.Column{Header: "PID"}, common.Column{Header: "Name", Fill: true},
+		common.Column{Header: "User", Optional: true},
+		common.Column{Header: "Read/s", Right: true}, common.Column{Header: "Write/s", Right: true})
+
+	if len(processes) == 0 {
+		table.AddNote("No disk I/O during the sample")
+	}
+
+	for _, p := range processes {
+		table.AddRow(strconv.Itoa(int(p.Process.PID)),
+			common.TruncateString(p.Process.Name, 25),
+			common.TruncateString(p.Process.Username, 12),
+			formatRate(p.ReadBytesPerSec),
+			formatRate(p.WriteBytesPerSec))
+	}
+
+	table.Print()
+}
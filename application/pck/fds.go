@@ -0,0 +1,108 @@
+package pck
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/procfs"
+)
+
+// fdWarningPercent is the share of its open files limit above which a process is reported
+const fdWarningPercent = 80
+
+// ProcessFDs contains the open file descriptors of a process and its limit
+type ProcessFDs struct {
+	Process common.ProcessInfo // Process the descriptors belong to
+	Count   int                // Open file descriptors
+	Limit   uint64             // Soft limit of open files (0 if unlimited or unknown)
+}
+
+// Percent returns the share of the limit in use (0 if there's no limit)
+func (p ProcessFDs) Percent() float64 {
+	if p.Limit == 0 {
+		return 0
+	}
+	return float64(p.Count) / float64(p.Limit) * 100
+}
+
+// GetTopProcessesByFDs finds the processes with the most open file descriptors
+// A growing count is the usual sign of a descriptor leak, which ends with "too many
+// open files" errors once the process reaches its limit
+//
+// Parameters:
+//   - n: maximum number of processes to return (0 or less for all)
+//
+// Returns:
+//   - slice of ProcessFDs sorted by descriptor count (descending)
+//   - number of processes whose descriptors couldn't be read (other users' without root)
+//   - error if the processes can't be read
+func GetTopProcessesByFDs(n int) ([]ProcessFDs, int, error) {
+	// The CPU usage isn't shown, so it isn't sampled
+	processes, _, err := common.CollectProcessInfo(common.CollectOptions{})
+	if err != nil {
+		return nil, 0, fmt.Errorf("error collecting processes: %w", err)
+	}
+
+	var top []ProcessFDs
+	unreadable := 0
+	for _, p := range processes {
+		count, err := procfs.CountFDs(p.PID)
+		if err != nil {
+			unreadable++
+			continue
+		}
+		limit, _ := procfs.ReadFDLimit(p.PID)
+		top = append(top, ProcessFDs{Process: p, Count: count, Limit: limit})
+	}
+
+	sort.Slice(top, func(i, j int) bool {
+		return top[i].Count > top[j].Count
+	})
+	if n > 0 && len(top) > n {
+		top = top[:n]
+	}
+	return top, unreadable, nil
+}
+
+// PrintTopProcessesByFDs prints the processes with the most open file descriptors in a table
+// Processes close to their limit are listed below the table
+//
+// Parameters:
+//   - processes: slice of ProcessFDs to present (from GetTopProcessesByFDs)
+func PrintTopProcessesByFDs(processes []ProcessFDs) {
+	table := common.NewTable("Top Processes by Open File Descriptors",
+		common.Column{Header: "PID"}, common.Column{Header: "Name", Fill: true},
+		common.Column{Header: "User", Optional: true},
+		common.Column{Header: "FDs", Right: true}, common.Column{Header: "Limit", Right: true},
+		common.Column{Header: "Used", Right: true})
+
+	if len(processes) == 0 {
+		table.AddNote("No process could be read")
+	}
+
+	var nearLimit []ProcessFDs
+	for _, p := range processes {
+		limit, used := "unlimited", "-"
+		if p.Limit > 0 {
+			limit, used = strconv.FormatUint(p.Limit, 10), fmt.Sprintf("%.1f%%", p.Percent())
+		}
+		table.AddRow(strconv.Itoa(int(p.Process.PID)),
+			common.TruncateString(p.Process.Name, 25),
+			common.TruncateString(p.Process.Username, 12),
+			strconv.Itoa(p.Count), limit, used)
+		if p.Percent() >= fdWarningPercent {
+			nearLimit = append(nearLimit, p)
+		}
+	}
+
+	if len(nearLimit) > 0 {
+		table.AddDivider()
+		for _, p := range nearLimit {
+			table.AddNote(fmt.Sprintf("⚠ %s (PID %d) uses %.0f%% of its open files limit", p.Process.Name, p.Process.PID, p.Percent()))
+		}
+	}
+
+	table.Print()
+}
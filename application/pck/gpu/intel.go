@@ -0,0 +1,252 @@
+package gpu
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+)
+
+// intelSampleInterval is how long Intel GPU busyness is sampled for
+const intelSampleInterval = 500 * time.Millisecond
+
+// intelSampler keeps the utilization state of one Intel card between calls, so that
+// repeated calls (TUI refreshes, the alert watcher) never wait for a measurement
+type intelSampler struct {
+	mu         sync.Mutex
+	gpuTopBusy bool      // An intel_gpu_top measurement is running in the background
+	gpuTopOK   bool      // gpuTopUtil holds a successful intel_gpu_top measurement
+	gpuTopDown bool      // intel_gpu_top is missing or failed (not retried)
+	gpuTopUtil float64   // Last intel_gpu_top utilization
+	rc6Ms      uint64    // RC6 residency at the previous call (valid if rc6Taken is set)
+	rc6Taken   time.Time // When rc6Ms was read
+}
+
+// intelSamplers holds the sampler of each Intel card, keyed by sysfs card path
+var (
+	intelSamplers   = make(map[string]*intelSampler)
+	intelSamplersMu sync.Mutex
+)
+
+// intelSamplerFor returns the sampler of a card, creating it on first use
+func intelSamplerFor(cardPath string) *intelSampler {
+	intelSamplersMu.Lock()
+	defer intelSamplersMu.Unlock()
+
+	sampler, ok := intelSamplers[cardPath]
+	if !ok {
+		sampler = &intelSampler{}
+		intelSamplers[cardPath] = sampler
+	}
+	return sampler
+}
+
+// intelGPUTopSample is the subset of one intel_gpu_top -J sample that we use
+type intelGPUTopSample struct {
+	Engines map[string]struct {
+		Busy float64 `json:"busy"`
+	} `json:"engines"`
+}
+
+// getIntelUtilization gets the utilization of an Intel (i915) GPU without blocking
+// intel_gpu_top (accurate per-engine busyness, usually requires root) runs in the background
+// and its last result is used once available; until then, or if it can't run, the
+// utilization comes from the RC6 residency counter in sysfs, which doesn't need privileges
+// Only the very first call for a card waits (one RC6 sampling interval)
+//
+// Parameters:
+//   - cardPath: sysfs path of the DRM card (e.g. "/sys/class/drm/card0")
+//
+// Returns:
+//   - GPU utilization percentage (0-100%)
+//   - error if no backend could measure the utilization
+func getIntelUtilization(cardPath string) (float64, error) {
+	sampler := intelSamplerFor(cardPath)
+	sampler.mu.Lock()
+	defer sampler.mu.Unlock()
+
+	// 1. Start a new intel_gpu_top measurement for the next call
+	if !sampler.gpuTopBusy && !sampler.gpuTopDown {
+		sampler.gpuTopBusy = true
+		go sampler.measureGPUTop(cardPath)
+	}
+
+	// 2. Use the last intel_gpu_top result if there is one
+	if sampler.gpuTopOK {
+		return sampler.gpuTopUtil, nil
+	}
+
+	// 3. Otherwise the RC6 counter since the previous call
+	return sampler.rc6Utilization(cardPath)
+}
+
+// measureGPUTop runs intel_gpu_top once and stores the result (run as a goroutine)
+func (s *intelSampler) measureGPUTop(cardPath string) {
+	util, err := getIntelGPUTopUtilization(cardPath)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gpuTopBusy = false
+	if err != nil {
+		s.gpuTopDown = true
+		return
+	}
+	s.gpuTopUtil, s.gpuTopOK = util, true
+}
+
+// getIntelGPUTopUtilization runs intel_gpu_top in JSON mode for a short period
+// The render engine busyness of the last complete sample is used as GPU utilization
+//
+// Parameters:
+//   - cardPath: sysfs path of the DRM card (e.g. "/sys/class/drm/card0"), selected with -d
+//
+// Returns:
+//   - GPU utilization percentage (0-100%)
+//   - error if intel_gpu_top is not available or produced no usable sample
+func getIntelGPUTopUtilization(cardPath string) (float64, error) {
+	if _, err := exec.LookPath("intel_gpu_top"); err != nil {
+		return 0, fmt.Errorf("intel_gpu_top not available: %w", err)
+	}
+
+	// intel_gpu_top streams samples forever, so stop it after two sampling periods
+	ctx, cancel := context.WithTimeout(context.Background(), 3*intelSampleInterval)
+	defer cancel()
+
+	periodMs := strconv.Itoa(int(intelSampleInterval.Milliseconds()))
+	device := "drm:/dev/dri/" + filepath.Base(cardPath)
+	cmd := exec.CommandContext(ctx, "intel_gpu_top", "-J", "-s", periodMs, "-d", device)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	started := time.Now()
+	err := cmd.Run() // Always "fails" because it's killed by the timeout
+	if errors.Is(err, exec.ErrNotFound) || output.Len() == 0 {
+		common.LogCommand(cmd, started, err) // Only a failure if it printed nothing
+	} else {
+		common.LogCommand(cmd, started, nil)
+	}
+
+	return parseIntelGPUTopOutput(output.Bytes())
+}
+
+// parseIntelGPUTopOutput parses the (possibly truncated) JSON array printed by intel_gpu_top -J
+//
+// Parameters:
+//   - output: raw intel_gpu_top output
+//
+// Returns:
+//   - render engine busyness of the last complete sample (highest engine if no render engine)
+//   - error if no complete sample was found
+func parseIntelGPUTopOutput(output []byte) (float64, error) {
+	// The output is a JSON array that is never closed, so decode the elements one by one
+	// and keep the last complete one
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	if _, err := decoder.Token(); err != nil {
+		return 0, fmt.Errorf("invalid intel_gpu_top output: %w", err)
+	}
+
+	var last *intelGPUTopSample
+	for decoder.More() {
+		var sample intelGPUTopSample
+		if err := decoder.Decode(&sample); err != nil {
+			break
+		}
+		last = &sample
+	}
+
+	if last == nil || len(last.Engines) == 0 {
+		return 0, fmt.Errorf("no complete sample in intel_gpu_top output")
+	}
+
+	// Prefer the render engine; otherwise report the busiest engine
+	busiest := 0.0
+	for name, engine := range last.Engines {
+		if strings.HasPrefix(name, "Render/3D") {
+			return engine.Busy, nil
+		}
+		if engine.Busy > busiest {
+			busiest = engine.Busy
+		}
+	}
+	return busiest, nil
+}
+
+// rc6Utilization estimates GPU utilization from the i915 RC6 residency counter since the
+// previous call (the caller holds s.mu)
+// RC6 is the GPU idle power state, so the time not spent in RC6 is time the GPU was busy
+// Without a previous reading the counter is sampled over intelSampleInterval
+//
+// Parameters:
+//   - cardPath: sysfs path of the DRM card (e.g. "/sys/class/drm/card0")
+//
+// Returns:
+//   - estimated GPU utilization percentage (0-100%)
+//   - error if the RC6 counter is not available
+func (s *intelSampler) rc6Utilization(cardPath string) (float64, error) {
+	// Newer kernels expose the counter per GT, older ones under power/
+	var counterPath string
+	for _, candidate := range []string{
+		filepath.Join(cardPath, "gt", "gt0", "rc6_residency_ms"),
+		filepath.Join(cardPath, "power", "rc6_residency_ms"),
+	} {
+		if _, err := os.Stat(candidate); err == nil {
+			counterPath = candidate
+			break
+		}
+	}
+
+	if counterPath == "" {
+		return 0, fmt.Errorf("i915 RC6 residency counter not available")
+	}
+
+	if s.rc6Taken.IsZero() {
+		before, err := readCounterMs(counterPath)
+		if err != nil {
+			return 0, err
+		}
+		s.rc6Ms, s.rc6Taken = before, time.Now()
+		time.Sleep(intelSampleInterval)
+	}
+
+	after, err := readCounterMs(counterPath)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	before, start := s.rc6Ms, s.rc6Taken
+	s.rc6Ms, s.rc6Taken = after, now
+	elapsedMs := float64(now.Sub(start).Milliseconds())
+
+	if elapsedMs <= 0 {
+		return 0, fmt.Errorf("invalid sampling interval")
+	}
+
+	// Clamp to 0-100% since both readings are not perfectly synchronized
+	idle := float64(after-before) / elapsedMs * 100
+	if idle > 100 {
+		idle = 100
+	}
+	if idle < 0 {
+		idle = 0
+	}
+
+	return 100 - idle, nil
+}
+
+// readCounterMs reads a millisecond counter from a sysfs file
+func readCounterMs(path string) (uint64, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("error reading %s: %w", path, err)
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(buf)), 10, 64)
+}
@@ -1,74 +1,194 @@
 package gpu
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+	"github.com/dfialho05/GoMonitor/application/pck/sensors"
 )
 
 // GPUStats contains GPU usage statistics
 // This structure supports both dedicated GPUs (NVIDIA) and integrated GPUs (Intel)
 type GPUStats struct {
-	Model        string  // GPU model name (e.g. "NVIDIA GeForce RTX 3060", "Intel UHD Graphics 620")
-	Utilization  float64 // GPU utilization percentage (0-100%)
-	MemoryTotal  uint64  // Total GPU memory in MB (VRAM)
-	MemoryUsed   uint64  // Used GPU memory in MB
-	Temp         int     // GPU temperature in degrees Celsius
-	IsIntegrated bool    // Indicates if it's an integrated GPU (true) or dedicated (false)
+	Model          string  // GPU model name (e.g. "NVIDIA GeForce RTX 3060", "Intel UHD Graphics 620")
+	Utilization    float64 // GPU utilization percentage (0-100%)
+	HasUtilization bool    // Indicates if Utilization was actually measured
+	MemoryTotal    uint64  // Total GPU memory in MB (VRAM)
+	MemoryUsed     uint64  // Used GPU memory in MB
+	Temp           int     // GPU temperature in degrees Celsius
+	IsIntegrated   bool    // Indicates if it's an integrated GPU (true) or dedicated (false)
+	Index          int     // GPU index in GetAllGPUStats order (NVIDIA cards first), unique among all GPUs
+
+	// NVIDIA only (0 if not reported, e.g. "[N/A]" on laptop GPUs)
+	SMClock     int     // Graphics (streaming multiprocessor) clock in MHz
+	MemClock    int     // Memory clock in MHz
+	PowerDraw   float64 // Power draw in watts
+	PowerLimit  float64 // Power limit in watts (the card slows down to stay below it)
+	FanSpeed    int     // Fan speed as a percentage of its maximum (0-100%)
+	HasFanSpeed bool    // Indicates if FanSpeed was reported (a stopped fan is 0%)
 }
 
 // GetGPUStats detects and collects statistics from the active GPU in the system
 // This function first tries to detect an NVIDIA GPU using nvidia-smi
 // If that fails, it tries to detect an integrated GPU through sysfs (Linux)
 //
+// Parameters:
+//   - ctx: context of the collection; nvidia-smi is killed when it is done or times out
+//
 // Returns:
 //   - GPUStats filled with GPU information
-//   - error if no GPU is detected or if there's an error reading
-func GetGPUStats() (GPUStats, error) {
+//   - error if no GPU is detected or if there's an error reading (wrapping
+//     common.ErrCommandTimeout if nvidia-smi hung and there's no other GPU)
+func GetGPUStats(ctx context.Context) (GPUStats, error) {
 	// 1. Try to detect NVIDIA GPU first
 	// NVIDIA GPUs are easier to monitor through nvidia-smi
-	stats, err := getNvidiaStats()
-	if err == nil {
+	stats, nvidiaErr := getNvidiaStats(ctx)
+	if nvidiaErr == nil {
 		stats.IsIntegrated = false
 		return stats, nil
 	}
 
 	// 2. If NVIDIA detection fails, try integrated GPU
 	// Integrated GPUs (Intel, AMD APU) use shared RAM memory
-	stats, err = getIntegratedStats()
+	stats, err := getIntegratedStats()
 	if err == nil {
 		stats.IsIntegrated = true
 		return stats, nil
 	}
 
+	if errors.Is(nvidiaErr, common.ErrCommandTimeout) {
+		return GPUStats{}, nvidiaErr
+	}
 	return GPUStats{}, fmt.Errorf("could not detect any GPU in the system")
 }
 
+// GetAllGPUStats detects and collects statistics from every GPU in the system
+// NVIDIA cards are enumerated through NVML or nvidia-smi, all other cards through DRM sysfs entries
+// NVIDIA DRM cards are only reported from sysfs when neither is available
+//
+// Parameters:
+//   - ctx: context of the collection; nvidia-smi is killed when it is done or times out
+//
+// Returns:
+//   - slice of GPUStats, NVIDIA cards first
+//   - error if no GPU is detected (wrapping common.ErrCommandTimeout if nvidia-smi hung
+//     and there's no other GPU)
+func GetAllGPUStats(ctx context.Context) ([]GPUStats, error) {
+	// 1. Enumerate NVIDIA cards through NVML or nvidia-smi
+	allStats, err := getAllNvidiaStats(ctx)
+	hasNvidiaSmi := err == nil
+
+	// 2. Enumerate every DRM card, skipping NVIDIA ones already covered by nvidia-smi
+	// When nvidia-smi hung, the NVIDIA cards are still listed from sysfs, without their readings
+	allStats = append(allStats, getAllDRMStats(hasNvidiaSmi)...)
+
+	if len(allStats) == 0 {
+		if errors.Is(err, common.ErrCommandTimeout) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("could not detect any GPU in the system")
+	}
+
+	// 3. Number the cards once both lists are joined: nvidia-smi numbers from 0 and so do
+	// the DRM cards, so a hybrid laptop would otherwise have two GPUs with index 0
+	for i := range allStats {
+		allStats[i].Index = i
+	}
+
+	return allStats, nil
+}
+
 // getNvidiaStats collects statistics from an NVIDIA GPU using the nvidia-smi command
 // This command provides detailed information about usage, memory and temperature
+// When several NVIDIA cards are installed, only the first one is returned
 //
 // Returns:
 //   - GPUStats filled with NVIDIA GPU data
 //   - error if nvidia-smi is not available or fails
-func getNvidiaStats() (GPUStats, error) {
+func getNvidiaStats(ctx context.Context) (GPUStats, error) {
+	allStats, err := getAllNvidiaStats(ctx)
+	if err != nil {
+		return GPUStats{}, err
+	}
+	return allStats[0], nil
+}
+
+// getAllNvidiaStats collects statistics from every NVIDIA GPU
+// NVML is used when the driver's library can be loaded, and the nvidia-smi command otherwise;
+// when NVML hangs, nvidia-smi (which uses the same driver) isn't tried
+//
+// Returns:
+//   - slice of GPUStats, one per NVIDIA card
+//   - error if neither NVML nor nvidia-smi is available, or they report no cards
+//     (wrapping common.ErrCommandTimeout if the driver didn't answer in time)
+func getAllNvidiaStats(ctx context.Context) ([]GPUStats, error) {
+	allStats, err := getAllNVMLStats(ctx)
+	if err == nil || errors.Is(err, common.ErrCommandTimeout) {
+		return allStats, err
+	}
+	return getAllNvidiaSmiStats(ctx)
+}
+
+// getAllNvidiaSmiStats collects statistics from every NVIDIA GPU using the nvidia-smi command
+// nvidia-smi prints one CSV line per card, in index order
+//
+// Returns:
+//   - slice of GPUStats, one per NVIDIA card
+//   - error if nvidia-smi is not available, fails or reports no cards
+func getAllNvidiaSmiStats(ctx context.Context) ([]GPUStats, error) {
 	// Execute nvidia-smi with specific query to get structured data
 	// --query-gpu: specifies which fields we want
 	// --format=csv,noheader,nounits: output format without headers and units
-	cmd := exec.Command("nvidia-smi",
-		"--query-gpu=name,utilization.gpu,memory.total,memory.used,temperature.gpu",
+	output, err := common.RunCommand(ctx, "nvidia-smi",
+		"--query-gpu=name,utilization.gpu,memory.total,memory.used,temperature.gpu,clocks.sm,clocks.mem,power.draw,power.limit,fan.speed",
 		"--format=csv,noheader,nounits")
-
-	output, err := cmd.Output()
+	if errors.Is(err, common.ErrCommandTimeout) {
+		return nil, err
+	}
 	if err != nil {
-		return GPUStats{}, fmt.Errorf("nvidia-smi not available or failed: %w", err)
+		return nil, fmt.Errorf("nvidia-smi not available or failed: %w", err)
+	}
+
+	// Parse each CSV line
+	// Expected format: "Name, Utilization, Total Memory, Used Memory, Temperature, SM Clock,
+	// Memory Clock, Power Draw, Power Limit, Fan Speed"
+	var allStats []GPUStats
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		stats, err := parseNvidiaLine(line)
+		if err != nil {
+			return nil, err
+		}
+		stats.Index = len(allStats) // Position among the NVIDIA cards (renumbered by GetAllGPUStats)
+		allStats = append(allStats, stats)
 	}
 
-	// Parse CSV output
-	// Expected format: "Name, Utilization, Total Memory, Used Memory, Temperature"
-	fields := strings.Split(strings.TrimSpace(string(output)), ", ")
-	if len(fields) < 5 {
+	if len(allStats) == 0 {
+		return nil, fmt.Errorf("nvidia-smi did not report any GPU")
+	}
+
+	return allStats, nil
+}
+
+// parseNvidiaLine parses a single CSV line of nvidia-smi output into GPUStats
+//
+// Parameters:
+//   - line: one line of "Name, Utilization, Total Memory, Used Memory, Temperature, SM Clock,
+//     Memory Clock, Power Draw, Power Limit, Fan Speed" (values not supported by the card
+//     are "[N/A]" or "[Not Supported]" and read as 0)
+//
+// Returns:
+//   - GPUStats filled with the line's data
+//   - error if the line doesn't have the expected format
+func parseNvidiaLine(line string) (GPUStats, error) {
+	fields := strings.Split(strings.TrimSpace(line), ", ")
+	if len(fields) < 10 {
 		return GPUStats{}, fmt.Errorf("unexpected format in nvidia-smi output")
 	}
 
@@ -93,13 +213,25 @@ func getNvidiaStats() (GPUStats, error) {
 		temp = 0
 	}
 
-	return GPUStats{
-		Model:       strings.TrimSpace(fields[0]),
-		Utilization: util,
-		MemoryTotal: memTotal,
-		MemoryUsed:  memUsed,
-		Temp:        temp,
-	}, nil
+	stats := GPUStats{
+		Model:          strings.TrimSpace(fields[0]),
+		Utilization:    util,
+		HasUtilization: true,
+		MemoryTotal:    memTotal,
+		MemoryUsed:     memUsed,
+		Temp:           temp,
+	}
+
+	// Clocks, power and fan (invalid values stay 0)
+	stats.SMClock, _ = strconv.Atoi(fields[5])
+	stats.MemClock, _ = strconv.Atoi(fields[6])
+	stats.PowerDraw, _ = strconv.ParseFloat(fields[7], 64)
+	stats.PowerLimit, _ = strconv.ParseFloat(fields[8], 64)
+	if fan, err := strconv.ParseFloat(fields[9], 64); err == nil {
+		stats.FanSpeed, stats.HasFanSpeed = int(fan), true
+	}
+
+	return stats, nil
 }
 
 // getIntegratedStats collects statistics from an integrated GPU through sysfs (Linux)
@@ -111,11 +243,12 @@ func getNvidiaStats() (GPUStats, error) {
 func getIntegratedStats() (GPUStats, error) {
 	// Search for GPU in card0, card1, card2, etc.
 	// The GPU can be on any card depending on system configuration
-	var vendor, device string
+	var vendor, device, cardPath string
 	var foundGPU bool
 
 	for i := 0; i < 10; i++ {
-		gpuPath := fmt.Sprintf("/sys/class/drm/card%d/device/", i)
+		cardPath = paths.Sys("class", "drm", fmt.Sprintf("card%d", i))
+		gpuPath := cardPath + "/device/"
 
 		// Try to read vendor ID
 		vendorBuf, err := os.ReadFile(gpuPath + "vendor")
@@ -150,13 +283,161 @@ func getIntegratedStats() (GPUStats, error) {
 	// Search for thermal zones that may have GPU temperature
 	temp := readGPUTemperature()
 
-	return GPUStats{
+	stats := GPUStats{
 		Model:       modelName,
-		Utilization: 0.0, // Integrated GPU: utilization not easily available
+		Utilization: 0.0, // Only available for Intel GPUs (see below)
 		MemoryTotal: 0,   // Integrated GPU: uses shared RAM (not fixed value)
 		MemoryUsed:  0,
 		Temp:        temp,
-	}, nil
+	}
+
+	// Intel GPUs expose busyness through intel_gpu_top or the i915 RC6 counter
+	if vendor == "0x8086" {
+		if util, err := getIntelUtilization(cardPath); err == nil {
+			stats.Utilization = util
+			stats.HasUtilization = true
+		}
+	}
+
+	return stats, nil
+}
+
+// getAllDRMStats collects basic statistics from every DRM card exposed in sysfs (Linux)
+// Intel and AMD cards are reported as integrated, like in getIntegratedStats
+//
+// Parameters:
+//   - skipNvidia: true to ignore NVIDIA cards (already reported by nvidia-smi)
+//
+// Returns:
+//   - slice of GPUStats, one per DRM card (empty if none found)
+func getAllDRMStats(skipNvidia bool) []GPUStats {
+	var allStats []GPUStats
+	temp := readGPUTemperature()
+
+	for _, card := range drmCards(skipNvidia) {
+		isIntegrated := card.vendor == "0x8086" || card.vendor == "0x1002"
+
+		stats := GPUStats{
+			Model:        identifyGPUModel(card.vendor, card.device),
+			IsIntegrated: isIntegrated,
+		}
+
+		// The thermal zone temperature only makes sense for integrated GPUs
+		if isIntegrated {
+			stats.Temp = temp
+		}
+
+		// Intel GPUs expose busyness through intel_gpu_top or the i915 RC6 counter
+		if card.vendor == "0x8086" {
+			if util, err := getIntelUtilization(card.path); err == nil {
+				stats.Utilization = util
+				stats.HasUtilization = true
+			}
+		}
+
+		allStats = append(allStats, stats)
+	}
+
+	return allStats
+}
+
+// drmCard is a graphics card exposed in DRM sysfs
+type drmCard struct {
+	path   string // sysfs directory of the card (e.g. /sys/class/drm/card0)
+	vendor string // PCI vendor ID (e.g. "0x8086")
+	device string // PCI device ID
+}
+
+// drmCards lists the DRM cards with their PCI IDs
+//
+// Parameters:
+//   - skipNvidia: true to ignore NVIDIA cards (already reported by nvidia-smi)
+//
+// Returns:
+//   - slice of drmCard in sysfs order (empty if none found)
+func drmCards(skipNvidia bool) []drmCard {
+	// Card directories are named cardN; connectors (e.g. card0-HDMI-A-1) are skipped
+	cardPaths, err := filepath.Glob(paths.Sys("class", "drm", "card[0-9]*"))
+	if err != nil {
+		return nil
+	}
+
+	var cards []drmCard
+	for _, cardPath := range cardPaths {
+		cardName := filepath.Base(cardPath)
+		if strings.Contains(cardName, "-") {
+			continue
+		}
+
+		if _, err := strconv.Atoi(strings.TrimPrefix(cardName, "card")); err != nil {
+			continue
+		}
+
+		vendorBuf, err := os.ReadFile(filepath.Join(cardPath, "device", "vendor"))
+		if err != nil {
+			continue
+		}
+
+		deviceBuf, err := os.ReadFile(filepath.Join(cardPath, "device", "device"))
+		if err != nil {
+			continue
+		}
+
+		vendor := strings.TrimSpace(string(vendorBuf))
+		if skipNvidia && vendor == "0x10de" {
+			continue
+		}
+		cards = append(cards, drmCard{path: cardPath, vendor: vendor, device: strings.TrimSpace(string(deviceBuf))})
+	}
+
+	return cards
+}
+
+// Models lists the model of every GPU without sampling them, in GetAllGPUStats order
+// The models don't change while the system is running, so hwcache keeps them
+//
+// Parameters:
+//   - ctx: context of the collection; nvidia-smi is killed when it is done or times out
+//
+// Returns:
+//   - slice of model names (empty if no GPU is detected)
+//   - error wrapping common.ErrCommandTimeout if NVML or nvidia-smi hung (the NVIDIA cards
+//     are then listed from sysfs, with generic names)
+func Models(ctx context.Context) ([]string, error) {
+	var models []string
+
+	// 1. NVIDIA cards through NVML, or nvidia-smi when it can't be loaded
+	var err error
+	hasNvidia := false
+	nvmlStats, nvmlErr := getAllNVMLStats(ctx)
+	switch {
+	case nvmlErr == nil:
+		for _, stats := range nvmlStats {
+			models = append(models, stats.Model)
+		}
+		hasNvidia = true
+	case errors.Is(nvmlErr, common.ErrCommandTimeout):
+		err = nvmlErr
+	default:
+		var output []byte
+		output, err = common.RunCommand(ctx, "nvidia-smi", "--query-gpu=name", "--format=csv,noheader")
+		hasNvidia = err == nil && strings.TrimSpace(string(output)) != ""
+		if hasNvidia {
+			for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+				models = append(models, strings.TrimSpace(line))
+			}
+		}
+	}
+
+	// 2. Every other DRM card
+	for _, card := range drmCards(hasNvidia) {
+		models = append(models, identifyGPUModel(card.vendor, card.device))
+	}
+
+	if errors.Is(err, common.ErrCommandTimeout) {
+		return models, err
+	}
+	return models, nil
 }
 
 // identifyGPUModel identifies the GPU model based on vendor/device IDs
@@ -194,89 +475,32 @@ func identifyGPUModel(vendor, device string) string {
 		return "AMD Radeon Graphics"
 	}
 
-	// If not recognized, return generic
-	return fmt.Sprintf("Integrated Graphics (Vendor: %s, Device: %s)", vendor, device)
-}
-
-// readThermalZone reads the temperature from a system thermal zone
-// This function tries to read from thermal_zone0, which usually represents CPU/GPU on laptops
-//
-// Returns:
-//   - temperature in degrees Celsius (0 if not available)
-func readThermalZone() int {
-	// Try to read from the first thermal zone
-	// The value is in millidegrees Celsius (e.g. 45000 = 45°C)
-	tempBuf, err := os.ReadFile("/sys/class/thermal/thermal_zone0/temp")
-	if err != nil {
-		return 0
-	}
-
-	// Convert from string to integer
-	tempMilliC, err := strconv.Atoi(strings.TrimSpace(string(tempBuf)))
-	if err != nil {
-		return 0
+	// NVIDIA: 0x10de (only seen here when nvidia-smi is not available, e.g. nouveau)
+	if vendor == "0x10de" {
+		return "NVIDIA Graphics"
 	}
 
-	// Convert from millidegrees to degrees Celsius
-	return tempMilliC / 1000
+	// If not recognized, return generic
+	return fmt.Sprintf("Integrated Graphics (Vendor: %s, Device: %s)", vendor, device)
 }
 
-// readGPUTemperature tries to read GPU temperature from various thermal zones
-// Specifically searches for zones that may contain GPU temperature
+// readGPUTemperature tries to read GPU temperature
+// Uses the GPU hwmon chips (amdgpu, radeon, nouveau) first, then the thermal zones
+// that may contain GPU temperature, and finally thermal_zone0, which usually
+// represents CPU/GPU on laptops
 //
 // Returns:
 //   - temperature in degrees Celsius (0 if not available)
 func readGPUTemperature() int {
-	// List of thermal zone types that may contain GPU temperature
-	targetTypes := []string{"INT3400", "acpitz", "pch_skylake", "B0D4"}
-
-	// Search all thermal zones
-	for i := 0; i < 20; i++ {
-		zonePath := fmt.Sprintf("/sys/class/thermal/thermal_zone%d/", i)
-
-		// Read the thermal zone type
-		typeBuf, err := os.ReadFile(zonePath + "type")
-		if err != nil {
-			continue
-		}
-
-		zoneType := strings.TrimSpace(string(typeBuf))
-
-		// Check if it's one of the types we're looking for
-		isTarget := false
-		for _, targetType := range targetTypes {
-			if strings.Contains(zoneType, targetType) {
-				isTarget = true
-				break
-			}
-		}
-
-		if !isTarget {
-			continue
-		}
-
-		// Read the temperature from this zone
-		tempBuf, err := os.ReadFile(zonePath + "temp")
-		if err != nil {
-			continue
-		}
-
-		tempMilliC, err := strconv.Atoi(strings.TrimSpace(string(tempBuf)))
-		if err != nil {
-			continue
-		}
-
-		// Convert from millidegrees to degrees Celsius
-		temp := tempMilliC / 1000
+	if temp := sensors.GPUTemperature(); temp > 0 {
+		return temp
+	}
 
-		// Return the first valid temperature found
-		if temp > 0 && temp < 150 { // Sanity check
-			return temp
-		}
+	if temp := sensors.ThermalZoneTemperature([]string{"INT3400", "acpitz", "pch_skylake", "B0D4"}); temp > 0 {
+		return temp
 	}
 
-	// If no specific GPU temperature found, use thermal zone 0
-	return readThermalZone()
+	return sensors.ReadThermalZone(0)
 }
 
 // PrintGPUStats prints GPU statistics in a formatted way
@@ -285,71 +509,93 @@ func readGPUTemperature() int {
 // Parameters:
 //   - stats: GPUStats structure with data to present
 func PrintGPUStats(stats GPUStats) {
-	fmt.Printf("\n╔══════════════════════════════════════════════════════════════════════════════════╗\n")
-	fmt.Printf("║  %-80s  ║\n", "GPU Information")
-	fmt.Printf("╠══════════════════════════════════════════════════════════════════════════════════╣\n")
-	fmt.Printf("║  Model:           %-62s  ║\n", truncateString(stats.Model, 62))
+	printGPUStatsWithTitle(stats, "GPU Information")
+}
+
+// PrintAllGPUStats prints statistics for every GPU in the system
+// Each GPU gets its own box, numbered when there is more than one
+//
+// Parameters:
+//   - allStats: slice of GPUStats to present
+func PrintAllGPUStats(allStats []GPUStats) {
+	if len(allStats) == 1 {
+		PrintGPUStats(allStats[0])
+		return
+	}
+
+	for i, stats := range allStats {
+		printGPUStatsWithTitle(stats, fmt.Sprintf("GPU %d Information", i))
+	}
+}
+
+// printGPUStatsWithTitle prints GPU statistics in a box with the given title
+func printGPUStatsWithTitle(stats GPUStats, title string) {
+	box := common.NewBox(title)
+	box.Field("Model", stats.Model)
 
 	// GPU type (integrated or dedicated)
 	gpuType := "Dedicated"
 	if stats.IsIntegrated {
 		gpuType = "Integrated"
 	}
-	fmt.Printf("║  Type:            %-62s  ║\n", gpuType)
+	box.Field("Type", gpuType)
 
 	// Utilization (only if available)
-	if stats.Utilization > 0 {
-		fmt.Printf("║  Utilization:     %-58.1f %%    ║\n", stats.Utilization)
+	if stats.HasUtilization {
+		box.Fieldf("Utilization", "%.1f %%", stats.Utilization)
 	} else {
-		fmt.Printf("║  Utilization:     %-62s  ║\n", "N/A (not available)")
+		box.Field("Utilization", "N/A (not available)")
 	}
 
 	// Memory (only if available)
 	if stats.MemoryTotal > 0 {
-		fmt.Printf("║  VRAM Total:      %-58d MB  ║\n", stats.MemoryTotal)
-		fmt.Printf("║  VRAM Used:       %-58d MB  ║\n", stats.MemoryUsed)
+		box.Fieldf("VRAM Total", "%d MB", stats.MemoryTotal)
+		box.Fieldf("VRAM Used", "%d MB", stats.MemoryUsed)
 		memPercent := float64(stats.MemoryUsed) / float64(stats.MemoryTotal) * 100
-		fmt.Printf("║  VRAM Usage:      %-58.1f %%    ║\n", memPercent)
+		box.Fieldf("VRAM Usage", "%.1f %%", memPercent)
 	} else {
-		fmt.Printf("║  VRAM:            %-62s  ║\n", "Shared (system RAM)")
+		box.Field("VRAM", "Shared (system RAM)")
 	}
 
 	// Temperature (only if available)
 	if stats.Temp > 0 {
-		fmt.Printf("║  Temperature:     %-58d °C  ║\n", stats.Temp)
+		box.Fieldf("Temperature", "%d °C", stats.Temp)
 	} else {
-		fmt.Printf("║  Temperature:     %-62s  ║\n", "N/A (not available)")
+		box.Field("Temperature", "N/A (not available)")
 	}
 
-	fmt.Printf("╚══════════════════════════════════════════════════════════════════════════════════╝\n")
+	// Clocks, power and fan (NVIDIA only, when the card reports them)
+	if stats.SMClock > 0 {
+		box.Fieldf("Core Clock", "%d MHz", stats.SMClock)
+	}
+	if stats.MemClock > 0 {
+		box.Fieldf("Memory Clock", "%d MHz", stats.MemClock)
+	}
+	switch {
+	case stats.PowerDraw > 0 && stats.PowerLimit > 0:
+		box.Fieldf("Power", "%.1f W of %.0f W (%.0f %%)", stats.PowerDraw, stats.PowerLimit, stats.PowerDraw/stats.PowerLimit*100)
+	case stats.PowerDraw > 0:
+		box.Fieldf("Power", "%.1f W", stats.PowerDraw)
+	}
+	if stats.HasFanSpeed {
+		box.Fieldf("Fan", "%d %%", stats.FanSpeed)
+	}
+
+	box.End()
 }
 
 // HasNvidiaGPU checks if the system has an NVIDIA GPU available
 // This function is useful to determine if it's worth trying to use nvidia-smi
 //
-// Returns:
-//   - true if nvidia-smi is available and functional
-func HasNvidiaGPU() bool {
-	cmd := exec.Command("nvidia-smi", "-L")
-	err := cmd.Run()
-	return err == nil
-}
-
-// truncateString truncates a string to a maximum length
-// Adds "..." at the end if the string is truncated
-//
 // Parameters:
-//   - s: string to truncate
-//   - maxLen: maximum allowed length
+//   - ctx: context of the check; nvidia-smi is killed when it is done or times out
 //
 // Returns:
-//   - truncated string (if necessary)
-func truncateString(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
+//   - true if NVML or nvidia-smi is available and functional
+func HasNvidiaGPU(ctx context.Context) bool {
+	if UsingNVML(ctx) {
+		return true
 	}
-	if maxLen <= 3 {
-		return s[:maxLen]
-	}
-	return s[:maxLen-3] + "..."
+	_, err := common.RunCommand(ctx, "nvidia-smi", "-L")
+	return err == nil
 }
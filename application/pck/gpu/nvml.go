@@ -0,0 +1,104 @@
+package gpu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+)
+
+// nvmlLibrary is the NVML library installed with the NVIDIA driver (the one nvidia-smi uses)
+const nvmlLibrary = "libnvidia-ml.so.1"
+
+// errNoNVML is returned when NVML can't be used (no NVIDIA driver, or gom built without cgo)
+var errNoNVML = errors.New("NVML not available")
+
+// nvml is the state of the NVML library, loaded on the first NVIDIA reading and kept loaded
+// Each reading then takes microseconds, instead of starting nvidia-smi on every refresh
+var nvml struct {
+	once    sync.Once
+	err     error      // Why NVML can't be used (nil once it is loaded)
+	reading sync.Mutex // Held while a reading runs (kept by a reading stuck in the driver)
+}
+
+// loadNVML loads and initializes NVML once
+//
+// Returns: error if the library is missing or fails to initialize (e.g. no NVIDIA GPU)
+func loadNVML() error {
+	nvml.once.Do(func() {
+		nvml.err = openNVML()
+	})
+	return nvml.err
+}
+
+// UsingNVML checks if NVIDIA GPUs are read through NVML rather than by running nvidia-smi
+//
+// Parameters:
+//   - ctx: context of the check; NVML is given up on when it is done or times out
+func UsingNVML(ctx context.Context) bool {
+	_, err := getAllNVMLStats(ctx)
+	return err == nil
+}
+
+// getAllNVMLStats collects statistics from every NVIDIA GPU through NVML
+// Readings a card doesn't support (e.g. the fan of a laptop GPU) are left at 0
+// A wedged driver can block an NVML call forever, and a call into C can't be interrupted, so
+// the reading runs in the background and is given up on when ctx is done or after the command
+// timeout, as a hung nvidia-smi is killed. While it stays stuck, later readings give up at once
+//
+// Parameters:
+//   - ctx: context of the collection (e.g. the shared deadline of a view)
+//
+// Returns:
+//   - slice of GPUStats, one per NVIDIA card in index order
+//   - error if NVML can't be used or reports no cards (wrapping common.ErrCommandTimeout
+//     if it didn't answer in time)
+func getAllNVMLStats(ctx context.Context) ([]GPUStats, error) {
+	if !nvml.reading.TryLock() {
+		return nil, fmt.Errorf("NVML %w (a previous reading is still blocked)", common.ErrCommandTimeout)
+	}
+
+	type result struct {
+		stats []GPUStats
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer nvml.reading.Unlock()
+		stats, err := readAllNVML()
+		done <- result{stats, err}
+	}()
+
+	timeout := time.NewTimer(common.CommandTimeout())
+	defer timeout.Stop()
+	select {
+	case r := <-done:
+		return r.stats, r.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("NVML %w: %w", common.ErrCommandTimeout, ctx.Err())
+	case <-timeout.C:
+		return nil, fmt.Errorf("NVML %w after %s", common.ErrCommandTimeout, common.CommandTimeout())
+	}
+}
+
+// readAllNVML loads NVML (the first time) and reads every NVIDIA card
+//
+// Returns:
+//   - slice of GPUStats, one per NVIDIA card in index order
+//   - error if NVML can't be used or reports no cards
+func readAllNVML() ([]GPUStats, error) {
+	if err := loadNVML(); err != nil {
+		return nil, err
+	}
+	allStats, err := readNVMLDevices()
+	if err != nil {
+		return nil, err
+	}
+	if len(allStats) == 0 {
+		return nil, errors.New("NVML did not report any GPU")
+	}
+	return allStats, nil
+}
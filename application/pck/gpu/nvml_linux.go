@@ -0,0 +1,192 @@
+//go:build linux && cgo
+
+package gpu
+
+// NVML is opened at run time with dlopen rather than linked, so gom still starts on
+// systems without the NVIDIA driver. The few types used are declared here, matching
+// nvml.h, so the NVIDIA headers aren't needed to build
+
+/*
+#cgo LDFLAGS: -ldl
+#include <dlfcn.h>
+#include <stdlib.h>
+
+typedef int nvmlReturn_t;
+typedef void *nvmlDevice_t;
+typedef struct { unsigned int gpu; unsigned int memory; } nvmlUtilization_t;
+typedef struct { unsigned long long total; unsigned long long free; unsigned long long used; } nvmlMemory_t;
+
+#define NVML_SUCCESS 0
+#define NVML_TEMPERATURE_GPU 0
+#define NVML_CLOCK_SM 1
+#define NVML_CLOCK_MEM 2
+#define NVML_NAME_SIZE 96
+
+static void *nvmlInitFn, *nvmlCountFn, *nvmlHandleFn, *nvmlNameFn, *nvmlUtilFn, *nvmlMemFn,
+	*nvmlTempFn, *nvmlClockFn, *nvmlPowerFn, *nvmlLimitFn, *nvmlFanFn;
+
+// gomOpenNVML loads the library and initializes it (0 on success)
+static int gomOpenNVML(const char *path) {
+	void *lib = dlopen(path, RTLD_LAZY);
+	if (!lib) return -1;
+
+	nvmlInitFn = dlsym(lib, "nvmlInit_v2");
+	nvmlCountFn = dlsym(lib, "nvmlDeviceGetCount_v2");
+	nvmlHandleFn = dlsym(lib, "nvmlDeviceGetHandleByIndex_v2");
+	nvmlNameFn = dlsym(lib, "nvmlDeviceGetName");
+	nvmlUtilFn = dlsym(lib, "nvmlDeviceGetUtilizationRates");
+	nvmlMemFn = dlsym(lib, "nvmlDeviceGetMemoryInfo");
+	nvmlTempFn = dlsym(lib, "nvmlDeviceGetTemperature");
+	nvmlClockFn = dlsym(lib, "nvmlDeviceGetClockInfo");
+	nvmlPowerFn = dlsym(lib, "nvmlDeviceGetPowerUsage");
+	nvmlLimitFn = dlsym(lib, "nvmlDeviceGetPowerManagementLimit");
+	nvmlFanFn = dlsym(lib, "nvmlDeviceGetFanSpeed");
+	if (!nvmlInitFn || !nvmlCountFn || !nvmlHandleFn || !nvmlNameFn || !nvmlUtilFn || !nvmlMemFn ||
+		!nvmlTempFn || !nvmlClockFn || !nvmlPowerFn || !nvmlLimitFn || !nvmlFanFn) {
+		dlclose(lib);
+		return -1;
+	}
+
+	nvmlReturn_t ret = ((nvmlReturn_t (*)(void))nvmlInitFn)();
+	if (ret != NVML_SUCCESS) {
+		dlclose(lib);
+		return ret;
+	}
+	return 0;
+}
+
+static nvmlReturn_t gomDeviceCount(unsigned int *count) {
+	return ((nvmlReturn_t (*)(unsigned int *))nvmlCountFn)(count);
+}
+
+static nvmlReturn_t gomDeviceHandle(unsigned int index, nvmlDevice_t *device) {
+	return ((nvmlReturn_t (*)(unsigned int, nvmlDevice_t *))nvmlHandleFn)(index, device);
+}
+
+static nvmlReturn_t gomDeviceName(nvmlDevice_t device, char *name, unsigned int length) {
+	return ((nvmlReturn_t (*)(nvmlDevice_t, char *, unsigned int))nvmlNameFn)(device, name, length);
+}
+
+static nvmlReturn_t gomDeviceUtilization(nvmlDevice_t device, nvmlUtilization_t *util) {
+	return ((nvmlReturn_t (*)(nvmlDevice_t, nvmlUtilization_t *))nvmlUtilFn)(device, util);
+}
+
+static nvmlReturn_t gomDeviceMemory(nvmlDevice_t device, nvmlMemory_t *memory) {
+	return ((nvmlReturn_t (*)(nvmlDevice_t, nvmlMemory_t *))nvmlMemFn)(device, memory);
+}
+
+static nvmlReturn_t gomDeviceTemperature(nvmlDevice_t device, unsigned int *temp) {
+	return ((nvmlReturn_t (*)(nvmlDevice_t, int, unsigned int *))nvmlTempFn)(device, NVML_TEMPERATURE_GPU, temp);
+}
+
+static nvmlReturn_t gomDeviceClock(nvmlDevice_t device, int clock, unsigned int *mhz) {
+	return ((nvmlReturn_t (*)(nvmlDevice_t, int, unsigned int *))nvmlClockFn)(device, clock, mhz);
+}
+
+static nvmlReturn_t gomDevicePower(nvmlDevice_t device, unsigned int *milliwatts) {
+	return ((nvmlReturn_t (*)(nvmlDevice_t, unsigned int *))nvmlPowerFn)(device, milliwatts);
+}
+
+static nvmlReturn_t gomDevicePowerLimit(nvmlDevice_t device, unsigned int *milliwatts) {
+	return ((nvmlReturn_t (*)(nvmlDevice_t, unsigned int *))nvmlLimitFn)(device, milliwatts);
+}
+
+static nvmlReturn_t gomDeviceFan(nvmlDevice_t device, unsigned int *percent) {
+	return ((nvmlReturn_t (*)(nvmlDevice_t, unsigned int *))nvmlFanFn)(device, percent);
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// openNVML loads libnvidia-ml and initializes it
+//
+// Returns: errNoNVML if the library is missing, or the NVML error code if it fails to
+// initialize (e.g. the driver isn't loaded)
+func openNVML() error {
+	path := C.CString(nvmlLibrary)
+	defer C.free(unsafe.Pointer(path))
+
+	switch ret := C.gomOpenNVML(path); ret {
+	case 0:
+		return nil
+	case -1:
+		return errNoNVML
+	default:
+		return fmt.Errorf("NVML failed to initialize (error %d)", int(ret))
+	}
+}
+
+// readNVMLDevices reads every NVIDIA card, in the same order as nvidia-smi
+// A reading the card doesn't support stays 0, as "[N/A]" does with nvidia-smi
+//
+// Returns:
+//   - slice of GPUStats, one per card
+//   - error if the cards can't be counted or a card can't be opened
+func readNVMLDevices() ([]GPUStats, error) {
+	var count C.uint
+	if ret := C.gomDeviceCount(&count); ret != C.NVML_SUCCESS {
+		return nil, fmt.Errorf("NVML failed to count GPUs (error %d)", int(ret))
+	}
+
+	allStats := make([]GPUStats, 0, int(count))
+	for i := C.uint(0); i < count; i++ {
+		var device C.nvmlDevice_t
+		if ret := C.gomDeviceHandle(i, &device); ret != C.NVML_SUCCESS {
+			return nil, fmt.Errorf("NVML failed to open GPU %d (error %d)", int(i), int(ret))
+		}
+		stats := readNVMLDevice(device)
+		stats.Index = int(i) // Position among the NVIDIA cards (renumbered by GetAllGPUStats)
+		allStats = append(allStats, stats)
+	}
+	return allStats, nil
+}
+
+// readNVMLDevice reads the model and every reading of one card
+func readNVMLDevice(device C.nvmlDevice_t) GPUStats {
+	var stats GPUStats
+
+	var name [C.NVML_NAME_SIZE]C.char
+	if C.gomDeviceName(device, &name[0], C.NVML_NAME_SIZE) == C.NVML_SUCCESS {
+		stats.Model = C.GoString(&name[0])
+	}
+
+	var util C.nvmlUtilization_t
+	if C.gomDeviceUtilization(device, &util) == C.NVML_SUCCESS {
+		stats.Utilization, stats.HasUtilization = float64(util.gpu), true
+	}
+
+	// NVML reports memory in bytes, nvidia-smi (and GPUStats) in MB
+	var memory C.nvmlMemory_t
+	if C.gomDeviceMemory(device, &memory) == C.NVML_SUCCESS {
+		stats.MemoryTotal = uint64(memory.total) / 1024 / 1024
+		stats.MemoryUsed = uint64(memory.used) / 1024 / 1024
+	}
+
+	var value C.uint
+	if C.gomDeviceTemperature(device, &value) == C.NVML_SUCCESS {
+		stats.Temp = int(value)
+	}
+	if C.gomDeviceClock(device, C.NVML_CLOCK_SM, &value) == C.NVML_SUCCESS {
+		stats.SMClock = int(value)
+	}
+	if C.gomDeviceClock(device, C.NVML_CLOCK_MEM, &value) == C.NVML_SUCCESS {
+		stats.MemClock = int(value)
+	}
+
+	// Power is reported in milliwatts
+	if C.gomDevicePower(device, &value) == C.NVML_SUCCESS {
+		stats.PowerDraw = float64(value) / 1000
+	}
+	if C.gomDevicePowerLimit(device, &value) == C.NVML_SUCCESS {
+		stats.PowerLimit = float64(value) / 1000
+	}
+	if C.gomDeviceFan(device, &value) == C.NVML_SUCCESS {
+		stats.FanSpeed, stats.HasFanSpeed = int(value), true
+	}
+
+	return stats
+}
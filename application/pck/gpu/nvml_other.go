@@ -0,0 +1,14 @@
+//go:build !linux || !cgo
+
+package gpu
+
+// openNVML reports that NVML isn't supported by this build (it needs cgo on Linux),
+// so nvidia-smi is always used
+func openNVML() error {
+	return errNoNVML
+}
+
+// readNVMLDevices is never called, since openNVML fails
+func readNVMLDevices() ([]GPUStats, error) {
+	return nil, errNoNVML
+}
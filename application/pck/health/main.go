@@ -0,0 +1,220 @@
+package health
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/alerts"
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/config"
+	"github.com/dfialho05/GoMonitor/application/pck/cpu"
+	"github.com/dfialho05/GoMonitor/application/pck/disk"
+	"github.com/dfialho05/GoMonitor/application/pck/ram"
+)
+
+// Score levels, from the overall score
+const (
+	LevelGood = "good" // 80 and above
+	LevelFair = "fair" // 50 to 79
+	LevelPoor = "poor" // Below 50
+)
+
+// curve maps a reading to a component score: 100 up to ok, 0 from bad, linear in between
+type curve struct {
+	ok  float64
+	bad float64
+}
+
+// curves are the component scores of the readings
+// Memory and disks get worse later than the CPU, since a busy CPU only slows things down
+var curves = map[string]curve{
+	config.HealthCPU:         {ok: 50, bad: 100}, // % of CPU time
+	config.HealthMemory:      {ok: 60, bad: 95},  // % of RAM that can't be reclaimed
+	config.HealthDisk:        {ok: 70, bad: 95},  // % used of the fullest mount
+	config.HealthTemperature: {ok: 60, bad: 95},  // °C
+	config.HealthAlerts:      {ok: 0, bad: 2.5},  // Alerts firing: 60 with one, 20 with two, 0 from three
+}
+
+// Inputs are the readings the score is computed from
+type Inputs struct {
+	CPUPercent    float64 // Global CPU usage (0-100%)
+	HasCPU        bool    // False if the CPU usage couldn't be measured
+	MemoryPercent float64 // RAM in use that can't be reclaimed (0-100%)
+	HasMemory     bool    // False if the RAM usage couldn't be read
+	DiskPercent   float64 // Usage of the fullest listed mount (0-100%)
+	HasDisk       bool    // False if no mount could be read
+	Temperature   int     // CPU temperature in °C (0 if not available)
+	Alerts        int     // Alerts firing (or alert thresholds reached, for a single reading)
+}
+
+// Component is the score of one reading and how much it counts
+type Component struct {
+	Name   string  // Component name (e.g. "disk")
+	Score  float64 // Score of the reading (0-100)
+	Weight float64 // Weight in the overall score
+}
+
+// Score is the overall health of the system and the scores it's made of
+type Score struct {
+	Value      int         // Weighted average of the components (0-100)
+	Components []Component // Components that could be read, in config.HealthComponents order
+}
+
+// Level describes the score in one word (LevelGood, LevelFair or LevelPoor)
+func (s Score) Level() string {
+	switch {
+	case s.Value >= 80:
+		return LevelGood
+	case s.Value >= 50:
+		return LevelFair
+	default:
+		return LevelPoor
+	}
+}
+
+// String formats the score and its components as one line
+// e.g. "87/100 good (cpu 95, memory 90, disk 60, temperature 100, alerts 100)"
+func (s Score) String() string {
+	parts := make([]string, 0, len(s.Components))
+	for _, c := range s.Components {
+		parts = append(parts, fmt.Sprintf("%s %.0f", c.Name, c.Score))
+	}
+	return fmt.Sprintf("%d/100 %s (%s)", s.Value, s.Level(), strings.Join(parts, ", "))
+}
+
+// Scorer computes health scores with the configured weights and alert rules
+type Scorer struct {
+	weights map[string]float64 // Weight of each component
+	rules   []alerts.Rule      // Alert rules checked by a single reading
+}
+
+// NewScorer creates a scorer with the "health" and "alerts" settings of the config file
+//
+// Parameters:
+//   - cfg: user settings (already validated)
+//
+// Returns: pointer to a configured Scorer
+func NewScorer(cfg config.Config) *Scorer {
+	rules, _ := alerts.ConfiguredRules(cfg.Alerts) // Unknown metrics are reported by the alert views
+	return &Scorer{weights: cfg.Health.ComponentWeights(), rules: rules}
+}
+
+// Score computes the overall score of the readings
+// Components that couldn't be read are left out, so they don't count as healthy or unhealthy
+//
+// Parameters:
+//   - in: readings
+//
+// Returns: the score (100 if nothing could be read or every weight is 0)
+func (s *Scorer) Score(in Inputs) Score {
+	readings := map[string]float64{config.HealthAlerts: float64(in.Alerts)}
+	if in.HasCPU {
+		readings[config.HealthCPU] = in.CPUPercent
+	}
+	if in.HasMemory {
+		readings[config.HealthMemory] = in.MemoryPercent
+	}
+	if in.HasDisk {
+		readings[config.HealthDisk] = in.DiskPercent
+	}
+	if in.Temperature > 0 {
+		readings[config.HealthTemperature] = float64(in.Temperature)
+	}
+
+	var score Score
+	var sum, total float64
+	for _, name := range config.HealthComponents {
+		value, ok := readings[name]
+		if !ok {
+			continue
+		}
+		c := Component{Name: name, Score: curves[name].score(value), Weight: s.weights[name]}
+		score.Components = append(score.Components, c)
+		sum += c.Score * c.Weight
+		total += c.Weight
+	}
+
+	score.Value = 100
+	if total > 0 {
+		score.Value = int(math.Round(sum / total))
+	}
+	return score
+}
+
+// score maps a reading to a component score (0-100)
+func (c curve) score(value float64) float64 {
+	switch {
+	case value <= c.ok:
+		return 100
+	case value >= c.bad:
+		return 0
+	default:
+		return (c.bad - value) / (c.bad - c.ok) * 100
+	}
+}
+
+// Violations counts the alert rules whose threshold the samples reach
+// For a single reading, which can't tell how long a value has been high
+//
+// Parameters:
+//   - samples: readings to check
+func (s *Scorer) Violations(samples []alerts.Sample) int {
+	return len(alerts.Check(s.rules, samples))
+}
+
+// Collect takes every reading of the score
+// CPU usage is measured over common.DefaultSampleInterval
+//
+// Parameters:
+//   - filter: mounts checked for the disk component
+//
+// Returns: the readings (missing ones are marked as not read)
+func (s *Scorer) Collect(filter disk.Filter) Inputs {
+	var in Inputs
+	var samples []alerts.Sample
+
+	cpu.GetSystemPercent()
+	time.Sleep(common.DefaultSampleInterval)
+	if percent, err := cpu.GetSystemPercent(); err == nil {
+		in.CPUPercent, in.HasCPU = percent, true
+		samples = append(samples, alerts.CPUSample(percent))
+	}
+	if stats, err := ram.GetRamGeneral(); err == nil {
+		in.MemoryPercent, in.HasMemory = MemoryPressure(stats), true
+		samples = append(samples, alerts.RAMSample(stats))
+	}
+	if devices, err := disk.GetAllStorageDevices(filter); err == nil {
+		in.DiskPercent, in.HasDisk = FullestDisk(devices)
+		samples = append(samples, alerts.DiskSamples(devices)...)
+	}
+	in.Temperature = cpu.GetTemperature()
+	in.Alerts = s.Violations(samples)
+	return in
+}
+
+// MemoryPressure returns the share of RAM that can't be given back to programs (0-100%)
+// Uses the available memory, so caches the kernel reclaims on demand don't count
+func MemoryPressure(stats ram.RamGeneral) float64 {
+	if stats.Total == 0 {
+		return 0
+	}
+	if stats.Available == 0 || stats.Available > stats.Total {
+		return stats.Percent
+	}
+	return float64(stats.Total-stats.Available) / float64(stats.Total) * 100
+}
+
+// FullestDisk returns the usage of the fullest mount
+//
+// Returns:
+//   - usage (0-100%)
+//   - false if there are no mounts
+func FullestDisk(devices []disk.StorageDevice) (float64, bool) {
+	fullest := 0.0
+	for _, device := range devices {
+		fullest = max(fullest, device.Percent)
+	}
+	return fullest, len(devices) > 0
+}
@@ -0,0 +1,320 @@
+package history
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Chart styles
+const (
+	ChartBraille = "braille" // 2x4 dots per character, for smooth lines
+	ChartBlocks  = "blocks"  // Half blocks, 1x2 per character, for fonts without braille
+)
+
+// chartAxisWidth is the width of the value labels and the axis line ("  100.0 ┤")
+const chartAxisWidth = 9
+
+// ChartSeries is one line of a chart
+type ChartSeries struct {
+	Name  string // Series name (e.g. SeriesCPU)
+	Color string // ANSI color of the line (empty for the terminal color)
+}
+
+// ChartOptions contains the size and style of a chart
+type ChartOptions struct {
+	Width  int    // Total width in characters, including the axis labels
+	Height int    // Height of the plot area in rows
+	Style  string // ChartBraille or ChartBlocks
+	Reset  string // ANSI code that ends a colored cell (empty without colors)
+}
+
+// chartGrid is the dot matrix a chart is drawn on
+// Each character cell holds cellWidth x cellHeight dots
+type chartGrid struct {
+	cells      [][]uint8  // Dots set in each cell (bit mask, see dotBit)
+	colors     [][]string // Color of the last series drawn in each cell
+	cellWidth  int        // Dots per cell horizontally
+	cellHeight int        // Dots per cell vertically
+	braille    bool       // Braille (true) or half blocks (false)
+}
+
+// newChartGrid creates an empty grid of rows x columns cells
+func newChartGrid(rows, columns int, style string) *chartGrid {
+	grid := &chartGrid{cellWidth: 1, cellHeight: 2}
+	if style != ChartBlocks {
+		grid.cellWidth, grid.cellHeight, grid.braille = 2, 4, true
+	}
+	grid.cells = make([][]uint8, rows)
+	grid.colors = make([][]string, rows)
+	for i := range grid.cells {
+		grid.cells[i] = make([]uint8, columns)
+		grid.colors[i] = make([]string, columns)
+	}
+	return grid
+}
+
+// dotBit returns the bit of a dot within its cell
+// Braille numbers the dots 1-2-3-7 down the left column and 4-5-6-8 down the right one
+func (g *chartGrid) dotBit(column, row int) uint8 {
+	if !g.braille {
+		return 1 << row // Top half, bottom half
+	}
+	if row == 3 {
+		return 0x40 << column
+	}
+	return 1 << (column*3 + row)
+}
+
+// set turns a dot on (x from the left, y from the top)
+func (g *chartGrid) set(x, y int, color string) {
+	row, column := y/g.cellHeight, x/g.cellWidth
+	if row < 0 || row >= len(g.cells) || column < 0 || column >= len(g.cells[row]) {
+		return
+	}
+	g.cells[row][column] |= g.dotBit(x%g.cellWidth, y%g.cellHeight)
+	g.colors[row][column] = color
+}
+
+// line draws a straight line between two dots (Bresenham's algorithm)
+func (g *chartGrid) line(x0, y0, x1, y1 int, color string) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+	for {
+		g.set(x0, y0, color)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		if e2 := 2 * err; e2 >= dy {
+			err += dy
+			x0 += sx
+		} else {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// cell returns the character of a cell
+func (g *chartGrid) cell(row, column int) string {
+	mask := g.cells[row][column]
+	switch {
+	case mask == 0:
+		return " "
+	case g.braille:
+		return string(rune(0x2800 + int(mask)))
+	case mask == 3:
+		return "█"
+	case mask == 1:
+		return "▀"
+	default:
+		return "▄"
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// bucketSeries averages the samples of a series into evenly spaced time buckets
+//
+// Parameters:
+//   - samples: stored samples (oldest first)
+//   - name: series to read
+//   - from, to: time range of the chart
+//   - buckets: number of buckets (one per horizontal dot)
+//
+// Returns: average of each bucket (NaN for buckets without samples)
+func bucketSeries(samples []MetricSample, name string, from, to time.Time, buckets int) []float64 {
+	sums := make([]float64, buckets)
+	counts := make([]int, buckets)
+	span := to.Sub(from)
+
+	for _, sample := range samples {
+		value, ok := sample.Values[name]
+		if !ok || sample.Time.Before(from) || sample.Time.After(to) {
+			continue
+		}
+		i := int(float64(sample.Time.Sub(from)) / float64(span) * float64(buckets))
+		if i >= buckets {
+			i = buckets - 1
+		}
+		sums[i] += value
+		counts[i]++
+	}
+
+	values := make([]float64, buckets)
+	for i := range values {
+		values[i] = math.NaN()
+		if counts[i] > 0 {
+			values[i] = sums[i] / float64(counts[i])
+		}
+	}
+	return values
+}
+
+// RenderChart draws stored samples as a line chart with auto-scaled axes
+// Several series share the chart; where their lines cross, the cell takes the color
+// of the series listed last. Gaps longer than a few sample intervals (e.g. while the
+// TUI wasn't running) are left empty instead of being joined
+//
+// Parameters:
+//   - samples: stored samples (from MetricStore.Since)
+//   - series: series to draw, with their colors
+//   - from, to: time range of the chart
+//   - options: size and style of the chart
+//
+// Returns:
+//   - chart lines (plot, time axis and a legend with min/avg/max per series)
+//   - false if none of the series has samples in the range
+func RenderChart(samples []MetricSample, series []ChartSeries, from, to time.Time, options ChartOptions) ([]string, bool) {
+	columns := options.Width - chartAxisWidth - 1
+	if columns < 10 {
+		columns = 10
+	}
+	grid := newChartGrid(options.Height, columns, options.Style)
+	dotsX, dotsY := columns*grid.cellWidth, options.Height*grid.cellHeight
+
+	// 1. Bucket every series and find the value range
+	values := make([][]float64, len(series))
+	low, high := math.Inf(1), math.Inf(-1)
+	for i, s := range series {
+		values[i] = bucketSeries(samples, s.Name, from, to, dotsX)
+		for _, v := range values[i] {
+			if !math.IsNaN(v) {
+				low, high = math.Min(low, v), math.Max(high, v)
+			}
+		}
+	}
+	if math.IsInf(low, 1) {
+		return nil, false
+	}
+	low, high = math.Floor(low), math.Ceil(high)
+	if high-low < 1 {
+		high = low + 1
+	}
+
+	// 2. Draw the lines, joining points that are at most a few sample intervals apart
+	bucketDuration := to.Sub(from) / time.Duration(dotsX)
+	maxGap := 1
+	if bucketDuration > 0 {
+		maxGap = int(3*MetricInterval/bucketDuration) + 1
+	}
+	for i, s := range series {
+		lastX, lastY := -1, 0
+		for x, v := range values[i] {
+			if math.IsNaN(v) {
+				continue
+			}
+			y := dotsY - 1 - int(math.Round((v-low)/(high-low)*float64(dotsY-1)))
+			if lastX >= 0 && x-lastX <= maxGap {
+				grid.line(lastX, lastY, x, y, s.Color)
+			} else {
+				grid.set(x, y, s.Color)
+			}
+			lastX, lastY = x, y
+		}
+	}
+
+	// 3. Plot rows with value labels on the first, middle and last rows
+	var lines []string
+	for row := 0; row < options.Height; row++ {
+		axis := strings.Repeat(" ", chartAxisWidth-1) + "│"
+		if row == 0 || row == options.Height-1 || row == options.Height/2 {
+			value := high - (high-low)*float64(row)/float64(max(options.Height-1, 1))
+			axis = fmt.Sprintf("%*s ┤", chartAxisWidth-2, formatAxisValue(value, high-low))
+		}
+
+		// Color codes are only written where the color changes
+		var b strings.Builder
+		b.WriteString(axis)
+		current := ""
+		for column := 0; column < columns; column++ {
+			color := grid.colors[row][column]
+			if grid.cells[row][column] == 0 {
+				color = current // Spaces don't need a color
+			}
+			if color != current {
+				if current != "" {
+					b.WriteString(options.Reset)
+				}
+				b.WriteString(color)
+				current = color
+			}
+			b.WriteString(grid.cell(row, column))
+		}
+		if current != "" {
+			b.WriteString(options.Reset)
+		}
+		lines = append(lines, b.String())
+	}
+
+	// 4. Time axis with the start, middle and end times
+	lines = append(lines, strings.Repeat(" ", chartAxisWidth-1)+"└"+strings.Repeat("─", columns))
+	lines = append(lines, timeAxis(from, to, columns))
+
+	// 5. Legend
+	lines = append(lines, "")
+	for i, s := range series {
+		lines = append(lines, legendLine(s, values[i], options.Reset))
+	}
+	return lines, true
+}
+
+// formatAxisValue formats an axis label with one decimal for small ranges
+func formatAxisValue(value, span float64) string {
+	if span < 10 {
+		return fmt.Sprintf("%.1f", value)
+	}
+	return fmt.Sprintf("%.0f", value)
+}
+
+// timeAxis returns the line with the start, middle and end times under the plot
+// Ranges longer than a day include the date
+func timeAxis(from, to time.Time, columns int) string {
+	layout := "15:04"
+	if to.Sub(from) > 24*time.Hour {
+		layout = "01-02 15:04"
+	}
+	start, middle, end := from.Local().Format(layout), from.Add(to.Sub(from)/2).Local().Format(layout), to.Local().Format(layout)
+
+	axis := []rune(strings.Repeat(" ", chartAxisWidth+columns))
+	place := func(label string, at int) {
+		at = min(max(at, 0), len(axis)-len(label))
+		copy(axis[at:], []rune(label))
+	}
+	place(start, chartAxisWidth)
+	place(middle, chartAxisWidth+columns/2-len(middle)/2)
+	place(end, chartAxisWidth+columns-len(end))
+	return strings.TrimRight(string(axis), " ")
+}
+
+// legendLine returns the legend entry of a series with its minimum, average and maximum
+func legendLine(s ChartSeries, values []float64, reset string) string {
+	low, high, sum, count := math.Inf(1), math.Inf(-1), 0.0, 0
+	for _, v := range values {
+		if math.IsNaN(v) {
+			continue
+		}
+		low, high = math.Min(low, v), math.Max(high, v)
+		sum += v
+		count++
+	}
+
+	entry := fmt.Sprintf("  %s● %-5s%s", s.Color, s.Name, reset)
+	if count == 0 {
+		return entry + " no samples"
+	}
+	return entry + fmt.Sprintf(" min %.1f  avg %.1f  max %.1f", low, sum/float64(count), high)
+}
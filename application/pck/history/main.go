@@ -0,0 +1,176 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+)
+
+// Kind identifies the type of an event
+type Kind string
+
+const (
+	KindAlert Kind = "alert" // An alert rule started firing
+	KindOOM   Kind = "oom"   // The kernel OOM killer killed a process
+	KindBoot  Kind = "boot"  // The system was (re)booted
+	KindExit  Kind = "exit"  // A process watched with gom watch-pid exited
+)
+
+// Event is a notable event stored in the history
+type Event struct {
+	Time    time.Time `json:"time"`    // When the event happened
+	Kind    Kind      `json:"kind"`    // Type of event
+	Message string    `json:"message"` // Human readable description
+}
+
+// Store is an append-only event history kept in a JSON Lines file
+type Store struct {
+	path string // Location of the events file
+}
+
+// DefaultPath returns the default location of the events file
+// Usually ~/.local/state/gomonitor/events.jsonl (see paths.StateDir for the overrides)
+//
+// Returns:
+//   - absolute path of the events file
+//   - error if the state directory can't be determined
+func DefaultPath() (string, error) {
+	return paths.StateFile("events.jsonl")
+}
+
+// NewStore creates a store backed by the given file
+// The file and its directory are created on the first Append
+//
+// Parameters:
+//   - path: location of the events file (e.g. DefaultPath())
+//
+// Returns: pointer to a configured Store
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Append adds events to the end of the history
+// Nothing is written in read-only mode (paths.ReadOnly)
+//
+// Parameters:
+//   - events: events to store
+//
+// Returns: error if the file can't be written
+func (s *Store) Append(events ...Event) error {
+	if len(events) == 0 || paths.ReadOnly() {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("error creating history directory: %w", err)
+	}
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("error opening history file: %w", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return fmt.Errorf("error writing event: %w", err)
+		}
+	}
+	return nil
+}
+
+// Since reads the events that happened after a point in time
+// Lines that can't be parsed (e.g. a partially written last line) are skipped
+//
+// Parameters:
+//   - since: oldest event time to return
+//
+// Returns:
+//   - slice of Event in the order they were stored (oldest first)
+//   - error if the file exists but can't be read
+func (s *Store) Since(since time.Time) ([]Event, error) {
+	file, err := os.Open(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error opening history file: %w", err)
+	}
+	defer file.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		if !event.Time.Before(since) {
+			events = append(events, event)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return events, fmt.Errorf("error reading history file: %w", err)
+	}
+
+	return events, nil
+}
+
+// ParseSince parses a look-back duration such as "90m", "24h" or "7d"
+// Supports everything time.ParseDuration does, plus days ("d")
+//
+// Parameters:
+//   - value: duration text
+//
+// Returns:
+//   - parsed duration
+//   - error if the value is not a valid positive duration
+func ParseSince(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid duration %q (e.g. 30m, 24h, 7d)", value)
+	}
+	return duration, nil
+}
+
+// PrintEvents prints events in a formatted table
+//
+// Parameters:
+//   - events: slice of Event to present
+//   - since: look-back window the events were selected with, as typed (e.g. "24h")
+func PrintEvents(events []Event, since string) {
+	title := fmt.Sprintf("Events (last %s)", since)
+	table := common.NewTable(title,
+		common.Column{Header: "Time"}, common.Column{Header: "Kind"}, common.Column{Header: "Message", Fill: true})
+
+	if len(events) == 0 {
+		table.AddNote("No events recorded")
+	}
+
+	for _, event := range events {
+		table.AddRow(event.Time.Local().Format("2006-01-02 15:04:05"),
+			string(event.Kind),
+			common.TruncateString(event.Message, 50))
+	}
+
+	table.Print()
+}
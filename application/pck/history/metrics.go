@@ -0,0 +1,170 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+)
+
+// Series that are recorded in the metrics history
+const (
+	SeriesCPU  = "cpu"  // System CPU usage (%)
+	SeriesRAM  = "ram"  // System RAM usage (%)
+	SeriesLoad = "load" // 1 minute load average
+	SeriesTemp = "temp" // CPU temperature (°C)
+)
+
+// SeriesNames lists the recorded series in the order they are documented
+var SeriesNames = []string{SeriesCPU, SeriesRAM, SeriesLoad, SeriesTemp}
+
+// MetricInterval is how often a metric sample is stored
+// Faster refreshes are not all stored, to keep the file small (about 1 MB per day)
+const MetricInterval = 10 * time.Second
+
+// MetricRetention is how long metric samples are kept
+const MetricRetention = 7 * 24 * time.Hour
+
+// MetricSample contains the system usage at one point in time
+// Series that couldn't be measured (e.g. the temperature on a VM) are left out
+type MetricSample struct {
+	Time   time.Time          `json:"time"`   // When the sample was taken
+	Values map[string]float64 `json:"values"` // Value of each series (e.g. {"cpu": 12.5})
+}
+
+// MetricStore is an append-only metrics history kept in a JSON Lines file
+type MetricStore struct {
+	path string    // Location of the metrics file
+	last time.Time // Time of the last stored sample
+}
+
+// DefaultMetricsPath returns the default location of the metrics file
+// Usually ~/.local/state/gomonitor/metrics.jsonl (see paths.StateDir for the overrides)
+//
+// Returns:
+//   - absolute path of the metrics file
+//   - error if the state directory can't be determined
+func DefaultMetricsPath() (string, error) {
+	return paths.StateFile("metrics.jsonl")
+}
+
+// NewMetricStore creates a metrics store backed by the given file
+// The file and its directory are created on the first Record
+//
+// Parameters:
+//   - path: location of the metrics file (e.g. DefaultMetricsPath())
+//
+// Returns: pointer to a configured MetricStore
+func NewMetricStore(path string) *MetricStore {
+	return &MetricStore{path: path}
+}
+
+// Record stores a sample if at least MetricInterval passed since the last one
+// Nothing is written in read-only mode (paths.ReadOnly)
+//
+// Parameters:
+//   - sample: system usage to store
+//
+// Returns: error if the file can't be written
+func (s *MetricStore) Record(sample MetricSample) error {
+	if len(sample.Values) == 0 || sample.Time.Sub(s.last) < MetricInterval || paths.ReadOnly() {
+		return nil
+	}
+	s.last = sample.Time
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("error creating history directory: %w", err)
+	}
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("error opening metrics file: %w", err)
+	}
+	defer file.Close()
+
+	if err := json.NewEncoder(file).Encode(sample); err != nil {
+		return fmt.Errorf("error writing metric sample: %w", err)
+	}
+	return nil
+}
+
+// Since reads the samples taken after a point in time
+// Lines that can't be parsed (e.g. a partially written last line) are skipped
+//
+// Parameters:
+//   - since: oldest sample time to return
+//
+// Returns:
+//   - slice of MetricSample in the order they were stored (oldest first)
+//   - error if the file exists but can't be read
+func (s *MetricStore) Since(since time.Time) ([]MetricSample, error) {
+	file, err := os.Open(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error opening metrics file: %w", err)
+	}
+	defer file.Close()
+
+	var samples []MetricSample
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var sample MetricSample
+		if err := json.Unmarshal(scanner.Bytes(), &sample); err != nil {
+			continue
+		}
+		if !sample.Time.Before(since) {
+			samples = append(samples, sample)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return samples, fmt.Errorf("error reading metrics file: %w", err)
+	}
+
+	return samples, nil
+}
+
+// Prune removes the samples older than MetricRetention
+// The file is rewritten through a temporary file, so a crash never loses the recent samples
+// Nothing is written in read-only mode (paths.ReadOnly)
+//
+// Returns: error if the file can't be read or replaced
+func (s *MetricStore) Prune() error {
+	if _, err := os.Stat(s.path); errors.Is(err, fs.ErrNotExist) || paths.ReadOnly() {
+		return nil
+	}
+	samples, err := s.Since(time.Now().Add(-MetricRetention))
+	if err != nil {
+		return err
+	}
+
+	temp := s.path + ".tmp"
+	file, err := os.Create(temp)
+	if err != nil {
+		return fmt.Errorf("error creating metrics file: %w", err)
+	}
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, sample := range samples {
+		if err := encoder.Encode(sample); err != nil {
+			file.Close()
+			os.Remove(temp)
+			return fmt.Errorf("error writing metric sample: %w", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		os.Remove(temp)
+		return fmt.Errorf("error writing metrics file: %w", err)
+	}
+	file.Close()
+
+	return os.Rename(temp, s.path)
+}
@@ -0,0 +1,116 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/alerts"
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+	"github.com/shirou/gopsutil/v3/host"
+)
+
+// Recorder turns periodic observations into history events
+// Only changes are recorded: an alert when it starts firing, an OOM kill when the
+// kernel counter increases and a boot when the boot time is newer than the last one stored
+type Recorder struct {
+	store    *Store              // Where events are written
+	firing   map[string]struct{} // Alerts firing at the previous observation
+	oomKills uint64              // OOM kill counter at the previous observation
+	hasOOM   bool                // False until the OOM counter has been read once
+}
+
+// NewRecorder creates a recorder writing to a store
+//
+// Parameters:
+//   - store: destination of the recorded events
+//
+// Returns: pointer to a configured Recorder
+func NewRecorder(store *Store) *Recorder {
+	return &Recorder{
+		store:  store,
+		firing: make(map[string]struct{}),
+	}
+}
+
+// Observe records the events that happened since the previous observation
+//
+// Parameters:
+//   - firing: alerts that are currently firing (from alerts.Engine.Evaluate)
+//   - now: time of the observation
+//
+// Returns: error if the events can't be stored
+func (r *Recorder) Observe(firing []alerts.Alert, now time.Time) error {
+	var events []Event
+
+	// 1. Alerts that started firing (disk crossed 90%, GPU overheating, ...)
+	current := make(map[string]struct{}, len(firing))
+	for _, alert := range firing {
+		key := alert.Rule.Name + "|" + alert.Target
+		current[key] = struct{}{}
+		if _, ok := r.firing[key]; !ok {
+			events = append(events, Event{Time: now, Kind: KindAlert, Message: alert.String()})
+		}
+	}
+	r.firing = current
+
+	// 2. OOM killer activity
+	if count, err := readOOMKills(); err == nil {
+		if r.hasOOM && count > r.oomKills {
+			killed := count - r.oomKills
+			events = append(events, Event{
+				Time:    now,
+				Kind:    KindOOM,
+				Message: fmt.Sprintf("OOM killer killed %d process(es)", killed),
+			})
+		}
+		r.oomKills = count
+		r.hasOOM = true
+	}
+
+	return r.store.Append(events...)
+}
+
+// RecordBoot stores a boot event if the system booted after the last stored boot
+// Called on startup, since a reboot can't be observed while it happens
+//
+// Returns: error if the boot time or the history can't be read
+func (r *Recorder) RecordBoot() error {
+	bootTime, err := host.BootTime()
+	if err != nil {
+		return fmt.Errorf("error getting boot time: %w", err)
+	}
+	booted := time.Unix(int64(bootTime), 0)
+
+	// Look for a boot event at (or after) the current boot time
+	// The boot time is computed from the uptime, so allow a few seconds of drift
+	events, err := r.store.Since(booted.Add(-5 * time.Second))
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		if event.Kind == KindBoot {
+			return nil
+		}
+	}
+
+	return r.store.Append(Event{Time: booted, Kind: KindBoot, Message: "System booted"})
+}
+
+// readOOMKills reads the number of processes killed by the OOM killer since boot
+func readOOMKills() (uint64, error) {
+	data, err := os.ReadFile(paths.Proc("vmstat"))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "oom_kill" {
+			return strconv.ParseUint(fields[1], 10, 64)
+		}
+	}
+	return 0, fmt.Errorf("oom_kill counter not available")
+}
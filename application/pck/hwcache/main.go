@@ -0,0 +1,245 @@
+package hwcache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/gpu"
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+	"github.com/shirou/gopsutil/v3/cpu"
+)
+
+// cacheFileName is the file in the cache directory the hardware info is kept in
+const cacheFileName = "hw.json"
+
+// cacheVersion is increased whenever Info changes, so older files are collected again
+const cacheVersion = 1
+
+// Info contains the hardware and OS facts that don't change while the system is running
+// Refreshes use it instead of reading /proc/cpuinfo, /etc/os-release or running nvidia-smi
+// again, and only sample the values that change
+type Info struct {
+	CPU    CPUInfo  `json:"cpu"`
+	OS     OSInfo   `json:"os"`
+	Kernel string   `json:"kernel"` // Kernel release (e.g. "6.8.0-45-generic")
+	GPUs   []string `json:"gpus"`   // Model of every GPU, in gpu.GetAllGPUStats order (empty if none)
+}
+
+// CPUInfo contains the static information of the CPU (from the first logical core)
+type CPUInfo struct {
+	Model     string  `json:"model"`     // CPU model name (e.g. "Intel Core i7-8550U")
+	Vendor    string  `json:"vendor"`    // Vendor identifier (e.g. "GenuineIntel")
+	Microcode string  `json:"microcode"` // CPU microcode version
+	Cores     int     `json:"cores"`     // Number of physical cores
+	MHz       float64 `json:"mhz"`       // Clock speed in MHz
+	CacheKB   int32   `json:"cache_kb"`  // Cache size in KB
+	Flags     string  `json:"flags"`     // CPU flags, separated by spaces
+}
+
+// OSInfo contains the fields of /etc/os-release GoMonitor uses
+type OSInfo struct {
+	Name   string   `json:"name"`    // PRETTY_NAME (e.g. "Ubuntu 24.04 LTS"), or the GOOS if unknown
+	ID     string   `json:"id"`      // ID in lowercase (e.g. "ubuntu")
+	IDLike []string `json:"id_like"` // ID_LIKE in lowercase, closest distro first (e.g. ["ubuntu", "debian"])
+}
+
+// cacheFile is the content of hw.json
+// The file is only used on the same boot (a new CPU, GPU or kernel needs a reboot)
+// and with the same proc file system (HOST_PROC may point to another host)
+type cacheFile struct {
+	Version int    `json:"version"`
+	BootID  string `json:"boot_id"`
+	Proc    string `json:"proc"`
+	Info    Info   `json:"info"`
+}
+
+var (
+	info     Info
+	infoOnce sync.Once
+)
+
+// Get returns the static hardware info
+// It is collected once per run, and read from hw.json in the cache directory when the
+// file was written on the same boot; otherwise it is collected and the file is written
+// (unless GoMonitor is read-only)
+//
+// Returns: Info (fields that can't be read are left empty)
+func Get() Info {
+	infoOnce.Do(func() {
+		path, pathErr := Path()
+		bootID := readBootID()
+
+		// 1. Use the file of an earlier run on the same boot
+		if pathErr == nil && bootID != "" {
+			if cached, ok := load(path, bootID); ok {
+				info = cached
+				return
+			}
+		}
+
+		// 2. Collect everything and keep it for the next runs
+		var complete bool
+		info, complete = collect()
+		if pathErr != nil || bootID == "" || !complete || paths.ReadOnly() {
+			return
+		}
+		if err := save(path, cacheFile{Version: cacheVersion, BootID: bootID, Proc: paths.Proc(), Info: info}); err != nil {
+			common.Logger().Warn("error writing the hardware info cache", "path", path, "err", err)
+		}
+	})
+	return info
+}
+
+// Path returns the location of hw.json (in the cache directory)
+// Removing the file makes the next run collect the hardware info again
+func Path() (string, error) {
+	return paths.CacheFile(cacheFileName)
+}
+
+// load reads hw.json if it is valid for this boot
+//
+// Parameters:
+//   - path: location of hw.json
+//   - bootID: ID of the running boot
+//
+// Returns:
+//   - Info from the file
+//   - false if the file is missing, unreadable or from another boot, version or proc file system
+func load(path, bootID string) (Info, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Info{}, false
+	}
+	var file cacheFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		common.Logger().Debug("ignoring the hardware info cache", "path", path, "err", err)
+		return Info{}, false
+	}
+	if file.Version != cacheVersion || file.BootID != bootID || file.Proc != paths.Proc() {
+		return Info{}, false
+	}
+	return file.Info, true
+}
+
+// save writes hw.json through a temporary file, so a concurrent run never reads half of it
+func save(path string, file cacheFile) error {
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding hardware info: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("error creating cache directory: %w", err)
+	}
+	temp, err := os.CreateTemp(filepath.Dir(path), cacheFileName+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error creating cache file: %w", err)
+	}
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		os.Remove(temp.Name())
+		return fmt.Errorf("error writing cache file: %w", err)
+	}
+	temp.Close()
+	return os.Rename(temp.Name(), path)
+}
+
+// collect reads every static fact
+//
+// Returns:
+//   - Info with what could be read
+//   - false if the CPU information couldn't be read or nvidia-smi hung, so it isn't kept
+//     for the next runs
+func collect() (Info, bool) {
+	collected := Info{
+		OS:     readOSRelease(),
+		Kernel: readKernelVersion(),
+	}
+
+	gpus, err := gpu.Models(context.Background())
+	collected.GPUs = gpus
+	complete := err == nil
+
+	cpuInfo, err := cpu.Info()
+	if err != nil || len(cpuInfo) == 0 {
+		common.Logger().Warn("error reading the CPU information", "err", err)
+		return collected, false
+	}
+
+	// There is one entry per logical core, but they all have the same static information
+	first := cpuInfo[0]
+	collected.CPU = CPUInfo{
+		Model:     first.ModelName,
+		Vendor:    first.VendorID,
+		Microcode: first.Microcode,
+		Cores:     int(first.Cores),
+		MHz:       first.Mhz,
+		CacheKB:   first.CacheSize,
+		Flags:     strings.Join(first.Flags, " "),
+	}
+	return collected, complete
+}
+
+// readBootID reads the random ID the kernel gives every boot (empty if not available)
+func readBootID() string {
+	data, err := os.ReadFile(paths.Proc("sys", "kernel", "random", "boot_id"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// readOSRelease reads the name and IDs of the distribution from /etc/os-release
+func readOSRelease() OSInfo {
+	osInfo := OSInfo{Name: runtime.GOOS}
+
+	data, err := os.ReadFile("/etc/os-release")
+	if err != nil {
+		return osInfo
+	}
+	fields := make(map[string]string)
+	for _, line := range strings.Split(string(data), "\n") {
+		if key, value, ok := strings.Cut(line, "="); ok {
+			fields[key] = strings.Trim(value, "\"'")
+		}
+	}
+
+	if fields["PRETTY_NAME"] != "" {
+		osInfo.Name = fields["PRETTY_NAME"]
+	}
+	osInfo.ID = strings.ToLower(fields["ID"])
+	osInfo.IDLike = strings.Fields(strings.ToLower(fields["ID_LIKE"]))
+	return osInfo
+}
+
+// readKernelVersion reads the kernel release
+// Ubuntu's /proc/version_signature has the release the package was built as, so it is preferred
+//
+// Returns: kernel release (the Go version if it can't be read)
+func readKernelVersion() string {
+	data, err := os.ReadFile(paths.Proc("version_signature"))
+	if err == nil {
+		parts := strings.Fields(string(data))
+		if len(parts) >= 3 {
+			return parts[2]
+		}
+	}
+	// Fallback
+	data, err = os.ReadFile(paths.Proc("version"))
+	if err == nil {
+		version := string(data)
+		if strings.Contains(version, "Linux version") {
+			parts := strings.Split(version, " ")
+			if len(parts) >= 3 {
+				return parts[2]
+			}
+		}
+	}
+	return runtime.Version()
+}
@@ -0,0 +1,204 @@
+package launch
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+	"github.com/dfialho05/GoMonitor/application/pck/procfs"
+)
+
+// DefaultInterval is how often the memory of the process tree is sampled
+const DefaultInterval = 200 * time.Millisecond
+
+// Summary is the resource usage of a command over its lifetime, including its children
+type Summary struct {
+	Command    []string      // Command and arguments that were run
+	Wall       time.Duration // Time from start to exit
+	User       time.Duration // CPU time in user mode of the command and the children it waited for
+	System     time.Duration // CPU time in kernel mode, likewise
+	PeakRSS    uint64        // Highest resident memory of the whole process tree (sampled)
+	MaxRSS     uint64        // Highest resident memory of a single process of the tree (exact)
+	PeakProcs  int           // Most processes running at once in the tree (sampled)
+	ReadBytes  uint64        // Bytes read from storage (cached reads are not counted)
+	WriteBytes uint64        // Bytes written to storage
+	ExitCode   int           // Exit status (128 + signal number if killed by a signal)
+	Signal     string        // Signal that killed the command (empty if it exited)
+}
+
+// AverageCPU returns the CPU usage averaged over the wall time (100% = one core busy)
+func (s Summary) AverageCPU() float64 {
+	if s.Wall <= 0 {
+		return 0
+	}
+	return float64(s.User+s.System) / float64(s.Wall) * 100
+}
+
+// Run starts a command with the terminal of gom, samples its process tree until it exits
+// and returns its resource usage
+// Ctrl+C reaches the command through the terminal and SIGTERM is passed on, so gom
+// outlives the command and can still report on it
+//
+// Parameters:
+//   - args: command and its arguments (e.g. ["make", "-j8"])
+//   - interval: time between memory samples (e.g. DefaultInterval)
+//
+// Returns:
+//   - usage summary (ExitCode is the command's)
+//   - error if the command can't be started
+func Run(args []string, interval time.Duration) (Summary, error) {
+	summary := Summary{Command: args}
+
+	// 1. Storage I/O of reaped children is added to their parent's counters, so the
+	// difference of gom's own counters is the I/O of the whole tree
+	readBefore, writeBefore := selfIO()
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		return summary, fmt.Errorf("error starting %s: %w", args[0], err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	// 2. Sample the process tree until the command exits
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	summary.sample(int32(cmd.Process.Pid))
+	var waitErr error
+loop:
+	for {
+		select {
+		case waitErr = <-done:
+			break loop
+		case sig := <-sigChan:
+			if sig == syscall.SIGTERM {
+				cmd.Process.Signal(sig)
+			}
+		case <-ticker.C:
+			summary.sample(int32(cmd.Process.Pid))
+		}
+	}
+	summary.Wall = time.Since(start)
+
+	// 3. CPU time and the largest process come from the kernel's accounting
+	state := cmd.ProcessState
+	var exitErr *exec.ExitError
+	if waitErr != nil && !errors.As(waitErr, &exitErr) {
+		return summary, fmt.Errorf("error waiting for %s: %w", args[0], waitErr)
+	}
+	summary.User, summary.System = state.UserTime(), state.SystemTime()
+	if usage, ok := state.SysUsage().(*syscall.Rusage); ok {
+		summary.MaxRSS = uint64(usage.Maxrss) * 1024 // Kilobytes on Linux
+	}
+	summary.PeakRSS = max(summary.PeakRSS, summary.MaxRSS)
+	summary.ExitCode = state.ExitCode()
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		summary.Signal = status.Signal().String()
+		summary.ExitCode = 128 + int(status.Signal())
+	}
+
+	readAfter, writeAfter := selfIO()
+	summary.ReadBytes = readAfter - min(readBefore, readAfter)
+	summary.WriteBytes = writeAfter - min(writeBefore, writeAfter)
+	return summary, nil
+}
+
+// sample adds one reading of the memory and process count of the tree under root
+func (s *Summary) sample(root int32) {
+	pids, err := procfs.Pids()
+	if err != nil {
+		return
+	}
+
+	// 1. Index the children of every process
+	processes := make(map[int32]procfs.Process, len(pids))
+	children := make(map[int32][]int32)
+	for _, pid := range pids {
+		p, err := procfs.ReadProcess(pid)
+		if err != nil {
+			continue
+		}
+		processes[pid] = p
+		children[p.PPID] = append(children[p.PPID], pid)
+	}
+
+	// 2. Sum the tree (zombies have already freed their memory)
+	var rss uint64
+	count := 0
+	stack := []int32{root}
+	for len(stack) > 0 && count < len(processes) {
+		pid := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if p, ok := processes[pid]; ok && p.State != common.StateZombie {
+			rss += p.RSS
+			count++
+		}
+		stack = append(stack, children[pid]...)
+	}
+	s.PeakRSS = max(s.PeakRSS, rss)
+	s.PeakProcs = max(s.PeakProcs, count)
+}
+
+// selfIO reads the storage I/O counters of gom (0 if they can't be read)
+func selfIO() (read, write uint64) {
+	data, err := os.ReadFile(paths.Proc("self", "io"))
+	if err != nil {
+		return 0, 0
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, _ := strings.Cut(line, ":")
+		n, _ := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+		switch key {
+		case "read_bytes":
+			read = n
+		case "write_bytes":
+			write = n
+		}
+	}
+	return read, write
+}
+
+// PrintSummary prints the usage of a command in a formatted table
+// Written to stderr like time(1), so the command's own output can still be piped
+//
+// Parameters:
+//   - w: destination (usually os.Stderr)
+//   - s: usage of the command (from Run)
+func PrintSummary(w io.Writer, s Summary) {
+	status := fmt.Sprintf("exited with %d", s.ExitCode)
+	if s.Signal != "" {
+		status = "killed by " + s.Signal
+	}
+
+	box := common.NewBoxTo(w, strings.Join(s.Command, " "))
+	box.Field("Status", status)
+	box.Field("Wall Time", formatSeconds(s.Wall))
+	box.Fieldf("CPU Time", "%s user + %s system", formatSeconds(s.User), formatSeconds(s.System))
+	box.Fieldf("Average CPU", "%.1f%% (100%% = one core)", s.AverageCPU())
+	box.Fieldf("Peak RSS", "%s (all processes), %s (largest process)",
+		common.FormatBytes(s.PeakRSS), common.FormatBytes(s.MaxRSS))
+	box.Fieldf("Processes", "%d at most at once", max(s.PeakProcs, 1))
+	box.Field("Disk Read", common.FormatBytes(s.ReadBytes))
+	box.Field("Disk Written", common.FormatBytes(s.WriteBytes))
+	box.End()
+}
+
+// formatSeconds formats a duration in seconds with millisecond precision (e.g. "12.345s")
+func formatSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3fs", d.Seconds())
+}
@@ -0,0 +1,267 @@
+package network
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+	gnet "github.com/shirou/gopsutil/v3/net"
+)
+
+// resolvedStub is the address of the local DNS stub of systemd-resolved, which forwards
+// to the servers listed in resolvedConfig
+const resolvedStub = "127.0.0.53"
+
+// resolvedConfig lists the upstream DNS servers of systemd-resolved
+const resolvedConfig = "/run/systemd/resolve/resolv.conf"
+
+// maxPublicIPResponse is the longest answer read from the public IP endpoint
+const maxPublicIPResponse = 256
+
+// Info is the network summary of the machine (gom --net-info)
+type Info struct {
+	Interfaces   []InterfaceAddresses // Interfaces with their addresses, loopback excluded
+	Gateway      string               // IPv4 address of the default gateway (empty if there's no default route)
+	GatewayIface string               // Interface of the default route
+	Link         Link                 // Link of the interface of the default route
+	DNS          []string             // DNS servers (the upstream ones behind systemd-resolved)
+	DNSSource    string               // File the DNS servers were read from
+	Search       []string             // DNS search domains
+	PublicIP     string               // Address seen by the public IP endpoint (empty if not asked)
+	PublicIPErr  error                // Error of the public IP endpoint
+}
+
+// InterfaceAddresses is a network interface with its addresses
+type InterfaceAddresses struct {
+	Name      string   // Interface name (e.g. "eth0")
+	Up        bool     // Interface is administratively up
+	MAC       string   // Hardware address (empty for virtual interfaces without one)
+	Addresses []string // IP addresses with prefix length (e.g. "192.168.1.10/24")
+}
+
+// GetInfo collects the network summary
+//
+// Parameters:
+//   - ctx: deadline of the collection (iw and the public IP endpoint)
+//   - publicIPURL: HTTP endpoint answering with the public IP in plain text, e.g.
+//     "https://api.ipify.org" (empty to skip it, since it sends a request to a third party)
+//
+// Returns:
+//   - Info with every part that could be read
+//   - error if the interfaces can't be listed
+func GetInfo(ctx context.Context, publicIPURL string) (Info, error) {
+	var info Info
+
+	// 1. Interfaces and their addresses
+	interfaces, err := gnet.Interfaces()
+	if err != nil {
+		return Info{}, fmt.Errorf("error listing network interfaces: %w", err)
+	}
+	for _, iface := range interfaces {
+		if hasFlag(iface.Flags, "loopback") {
+			continue
+		}
+		entry := InterfaceAddresses{Name: iface.Name, Up: hasFlag(iface.Flags, "up"), MAC: iface.HardwareAddr}
+		for _, addr := range iface.Addrs {
+			entry.Addresses = append(entry.Addresses, addr.Addr)
+		}
+		info.Interfaces = append(info.Interfaces, entry)
+	}
+
+	// 2. Default gateway and the link it goes through
+	if iface, gateway, err := defaultGateway(); err == nil {
+		info.GatewayIface, info.Gateway = iface, gateway
+		if _, link, err := GetDefaultLink(ctx); err == nil {
+			info.Link = link
+		}
+	}
+
+	// 3. DNS servers
+	info.DNS, info.Search, info.DNSSource = readDNS()
+
+	// 4. Public IP (only when an endpoint is configured)
+	if publicIPURL != "" {
+		info.PublicIP, info.PublicIPErr = GetPublicIP(ctx, publicIPURL)
+	}
+	return info, nil
+}
+
+// defaultGateway reads the gateway of the default route from /proc/net/route
+//
+// Returns:
+//   - interface of the default route (see DefaultInterface)
+//   - IPv4 address of its gateway
+//   - error if there is no default route
+func defaultGateway() (string, string, error) {
+	name, err := DefaultInterface()
+	if err != nil {
+		return "", "", err
+	}
+	file, err := os.Open(paths.Proc("net", "route"))
+	if err != nil {
+		return "", "", fmt.Errorf("error reading the routing table: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 8 || fields[0] != name || fields[1] != "00000000" {
+			continue
+		}
+		// The gateway is written in hex, in the byte order of the host (little-endian)
+		raw, err := hex.DecodeString(fields[2])
+		if err != nil || len(raw) != 4 {
+			continue
+		}
+		ip := make(net.IP, 4)
+		binary.BigEndian.PutUint32(ip, binary.LittleEndian.Uint32(raw))
+		return name, ip.String(), nil
+	}
+	return "", "", fmt.Errorf("no default gateway")
+}
+
+// readDNS reads the DNS servers and search domains from /etc/resolv.conf
+// Behind systemd-resolved, resolv.conf only lists its local stub, so the upstream servers
+// are read from its own file
+//
+// Returns:
+//   - DNS servers
+//   - search domains
+//   - file they were read from (empty if none could be read)
+func readDNS() ([]string, []string, string) {
+	servers, search, err := readResolvConf("/etc/resolv.conf")
+	if err != nil {
+		return nil, nil, ""
+	}
+	if len(servers) == 1 && servers[0] == resolvedStub {
+		if upstream, upstreamSearch, err := readResolvConf(resolvedConfig); err == nil && len(upstream) > 0 {
+			return upstream, upstreamSearch, resolvedConfig + " (systemd-resolved)"
+		}
+	}
+	return servers, search, "/etc/resolv.conf"
+}
+
+// readResolvConf reads the nameserver and search lines of a resolv.conf file
+func readResolvConf(path string) ([]string, []string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	var servers, search []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "nameserver":
+			servers = append(servers, fields[1])
+		case "search", "domain":
+			search = append(search, fields[1:]...)
+		}
+	}
+	return servers, search, scanner.Err()
+}
+
+// GetPublicIP asks an HTTP endpoint for the address the machine is seen from on the internet
+//
+// Parameters:
+//   - ctx: deadline of the request
+//   - url: endpoint answering with the address in plain text (e.g. "https://api.ipify.org")
+//
+// Returns:
+//   - public IP address
+//   - error if the endpoint can't be reached or doesn't answer with an address
+func GetPublicIP(ctx context.Context, url string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, common.DefaultCommandTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("error asking %s for the public IP: %w", url, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error asking %s for the public IP: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error asking %s for the public IP: unexpected response: %s", url, resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPublicIPResponse))
+	if err != nil {
+		return "", fmt.Errorf("error asking %s for the public IP: %w", url, err)
+	}
+	address := strings.TrimSpace(string(body))
+	if net.ParseIP(address) == nil {
+		return "", fmt.Errorf("error asking %s for the public IP: the answer is not an address", url)
+	}
+	return address, nil
+}
+
+// PrintInfo prints the network summary
+//
+// Parameters:
+//   - info: Info to present
+func PrintInfo(info Info) {
+	box := common.NewBox("Network")
+
+	// Default route first: it's what decides how the machine reaches the internet
+	if info.Gateway != "" {
+		box.Field("Gateway", info.Gateway+" via "+info.GatewayIface)
+		if info.Link.State != "" {
+			box.Field("Link", info.Link.Summary())
+		}
+	} else {
+		box.Field("Gateway", "none (no default route)")
+	}
+	switch {
+	case info.PublicIP != "":
+		box.Field("Public IP", info.PublicIP)
+	case info.PublicIPErr != nil:
+		box.Field("Public IP", "N/A ("+info.PublicIPErr.Error()+")")
+	default:
+		box.Field("Public IP", "not asked (set \"public_ip_url\" in the config file)")
+	}
+
+	if len(info.DNS) > 0 {
+		box.Field("DNS", strings.Join(info.DNS, ", "))
+		if len(info.Search) > 0 {
+			box.Field("Search", strings.Join(info.Search, ", "))
+		}
+		box.Field("DNS Source", info.DNSSource)
+	} else {
+		box.Field("DNS", "N/A (no nameserver in /etc/resolv.conf)")
+	}
+
+	// Then every interface with its addresses
+	for _, iface := range info.Interfaces {
+		box.Separator()
+		state := "up"
+		if !iface.Up {
+			state = "down"
+		}
+		if iface.MAC != "" {
+			state += ", " + iface.MAC
+		}
+		box.Field(iface.Name, state)
+		for _, addr := range iface.Addresses {
+			box.Field("", addr)
+		}
+	}
+	box.End()
+}
@@ -0,0 +1,234 @@
+package network
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+)
+
+// ssidRefresh is how often the Sampler asks iw for the network of a Wi-Fi interface
+// The network rarely changes, so the TUI doesn't run iw on every refresh
+const ssidRefresh = 30 * time.Second
+
+// Link is the physical state of a network interface
+type Link struct {
+	State       string  // Operational state from sysfs (e.g. "up", "down", "dormant")
+	SpeedMbps   int     // Negotiated speed in Mb/s (0 if unknown, e.g. Wi-Fi or virtual interfaces)
+	Duplex      string  // "full" or "half" (empty if unknown)
+	Wireless    bool    // Wi-Fi interface
+	SSID        string  // Wi-Fi network the interface is connected to (empty if unknown, read with iw)
+	BitrateMbps float64 // Wi-Fi transmit bitrate in Mb/s (0 if unknown, read with iw)
+	SignalDBm   int     // Wi-Fi signal level in dBm (0 if unknown)
+	Quality     float64 // Wi-Fi link quality (0-100%, 0 if unknown)
+}
+
+// netPath returns the sysfs directory of a network interface
+func netPath(name string, elem ...string) string {
+	return paths.Sys(append([]string{"class", "net", name}, elem...)...)
+}
+
+// readLink reads the state and speed of an interface from sysfs, and the signal of
+// Wi-Fi interfaces from /proc/net/wireless (the SSID needs iw, see readWifi)
+//
+// Parameters:
+//   - name: interface name (e.g. "eth0")
+//   - signals: Wi-Fi signals by interface (from readWireless)
+//
+// Returns: Link of the interface (fields it doesn't report are left empty)
+func readLink(name string, signals map[string]Link) Link {
+	link := Link{State: readNetString(name, "operstate")}
+	// The speed and duplex can't be read (EINVAL) while there's no carrier
+	if speed, err := strconv.Atoi(readNetString(name, "speed")); err == nil && speed > 0 {
+		link.SpeedMbps = speed
+	}
+	if duplex := readNetString(name, "duplex"); duplex == "full" || duplex == "half" {
+		link.Duplex = duplex
+	}
+
+	_, errWireless := os.Stat(netPath(name, "wireless"))
+	_, errPhy := os.Stat(netPath(name, "phy80211"))
+	link.Wireless = errWireless == nil || errPhy == nil
+	if signal, ok := signals[name]; ok {
+		link.Wireless = true
+		link.SignalDBm, link.Quality = signal.SignalDBm, signal.Quality
+	}
+	return link
+}
+
+// readNetString reads a sysfs attribute of an interface (empty if it can't be read)
+func readNetString(name, attribute string) string {
+	data, err := os.ReadFile(netPath(name, attribute))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// readWireless reads the link quality and signal level of the Wi-Fi interfaces
+// from /proc/net/wireless, e.g. "wlan0: 0000   54.  -56.  -256  0 0 0 0 12 0"
+//
+// Returns: Link with SignalDBm and Quality by interface (empty without Wi-Fi)
+func readWireless() map[string]Link {
+	signals := make(map[string]Link)
+	file, err := os.Open(paths.Proc("net", "wireless"))
+	if err != nil {
+		return signals
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		name, values, ok := strings.Cut(scanner.Text(), ":")
+		fields := strings.Fields(values)
+		if !ok || len(fields) < 3 {
+			continue // Header lines
+		}
+		quality, errQuality := strconv.ParseFloat(strings.TrimSuffix(fields[1], "."), 64)
+		level, errLevel := strconv.ParseFloat(strings.TrimSuffix(fields[2], "."), 64)
+		if errQuality != nil || errLevel != nil {
+			continue
+		}
+		// Old drivers report the level as an unsigned byte
+		if level > 0 {
+			level -= 256
+		}
+		// The link quality is out of 70 for most drivers
+		signals[strings.TrimSpace(name)] = Link{SignalDBm: int(level), Quality: min(quality/70*100, 100)}
+	}
+	return signals
+}
+
+// readWifi reads the network and bitrate of a Wi-Fi interface with iw
+//
+// Parameters:
+//   - ctx: deadline of the collection
+//   - name: interface name (e.g. "wlan0")
+//
+// Returns:
+//   - SSID (empty if not connected)
+//   - transmit bitrate in Mb/s (0 if unknown)
+//   - error if iw isn't installed or fails
+func readWifi(ctx context.Context, name string) (string, float64, error) {
+	output, err := common.RunCommand(ctx, "iw", "dev", name, "link")
+	if err != nil {
+		return "", 0, fmt.Errorf("error reading the Wi-Fi link of %s: %w", name, err)
+	}
+
+	var ssid string
+	var bitrate float64
+	for _, line := range strings.Split(string(output), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "SSID":
+			ssid = strings.TrimSpace(value)
+		case "tx bitrate":
+			if fields := strings.Fields(value); len(fields) > 0 {
+				bitrate, _ = strconv.ParseFloat(fields[0], 64)
+			}
+		}
+	}
+	return ssid, bitrate, nil
+}
+
+// DefaultInterface finds the interface of the default route in /proc/net/route
+//
+// Returns:
+//   - interface name (e.g. "eth0"); with several default routes, the one with the lowest metric
+//   - error if there is no default route
+func DefaultInterface() (string, error) {
+	file, err := os.Open(paths.Proc("net", "route"))
+	if err != nil {
+		return "", fmt.Errorf("error reading the routing table: %w", err)
+	}
+	defer file.Close()
+
+	best, bestMetric := "", -1
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// Iface Destination Gateway Flags RefCnt Use Metric Mask ...
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 8 || fields[1] != "00000000" || fields[7] != "00000000" {
+			continue
+		}
+		flags, _ := strconv.ParseUint(fields[3], 16, 32)
+		metric, err := strconv.Atoi(fields[6])
+		if err != nil || flags&1 == 0 { // RTF_UP
+			continue
+		}
+		if bestMetric < 0 || metric < bestMetric {
+			best, bestMetric = fields[0], metric
+		}
+	}
+	if best == "" {
+		return "", fmt.Errorf("no default route")
+	}
+	return best, nil
+}
+
+// GetDefaultLink reads the link of the interface of the default route, with the Wi-Fi network
+//
+// Parameters:
+//   - ctx: deadline of the collection (iw is run for Wi-Fi interfaces)
+//
+// Returns:
+//   - interface name
+//   - Link of the interface (without SSID if iw isn't installed)
+//   - error if there is no default route
+func GetDefaultLink(ctx context.Context) (string, Link, error) {
+	name, err := DefaultInterface()
+	if err != nil {
+		return "", Link{}, err
+	}
+	link := readLink(name, readWireless())
+	if link.Wireless {
+		link.SSID, link.BitrateMbps, _ = readWifi(ctx, name)
+	}
+	return name, link, nil
+}
+
+// Summary describes the link in a few words
+// e.g. "up, 1000 Mb/s full duplex", "Wi-Fi home, -52 dBm (80%), 866 Mb/s", "down"
+func (l Link) Summary() string {
+	if l.State != "up" && l.State != "unknown" && l.State != "" {
+		if l.Wireless {
+			return "Wi-Fi " + l.State
+		}
+		return l.State
+	}
+
+	var parts []string
+	if l.Wireless {
+		wifi := "Wi-Fi"
+		if l.SSID != "" {
+			wifi += " " + l.SSID
+		}
+		parts = append(parts, wifi)
+		if l.SignalDBm != 0 {
+			parts = append(parts, fmt.Sprintf("%d dBm (%.0f%%)", l.SignalDBm, l.Quality))
+		}
+		if l.BitrateMbps > 0 {
+			parts = append(parts, fmt.Sprintf("%.0f Mb/s", l.BitrateMbps))
+		}
+		return strings.Join(parts, ", ")
+	}
+
+	parts = append(parts, "up")
+	if l.SpeedMbps > 0 {
+		speed := fmt.Sprintf("%d Mb/s", l.SpeedMbps)
+		if l.Duplex != "" {
+			speed += " " + l.Duplex + " duplex"
+		}
+		parts = append(parts, speed)
+	}
+	return strings.Join(parts, ", ")
+}
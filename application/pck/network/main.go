@@ -0,0 +1,138 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/net"
+)
+
+// InterfaceStats contains the traffic of a network interface
+type InterfaceStats struct {
+	Name          string   // Interface name (e.g. "eth0")
+	Up            bool     // Interface is administratively up
+	Addresses     []string // IP addresses with prefix length (e.g. "192.168.1.10/24")
+	RxBytes       uint64   // Bytes received since boot
+	TxBytes       uint64   // Bytes sent since boot
+	Errors        uint64   // Receive and send errors since boot
+	Drops         uint64   // Incoming and outgoing packets dropped since boot
+	RxBytesPerSec float64  // Receive throughput since the previous sample
+	TxBytesPerSec float64  // Send throughput since the previous sample
+	HasRate       bool     // False until two samples have been taken
+	Link          Link     // State and speed of the link, with the network and signal of Wi-Fi interfaces
+}
+
+// Sampler measures the throughput of the network interfaces between calls
+// Kept by long-running views (e.g. the TUI), which get the rate since their previous refresh
+type Sampler struct {
+	previous map[string]net.IOCountersStat // Counters of the previous call, keyed by interface
+	time     time.Time                     // When the previous counters were read
+	wifi     map[string]wifiReading        // Last iw reading of each Wi-Fi interface
+}
+
+// wifiReading is the network and bitrate of a Wi-Fi interface, read with iw every ssidRefresh
+type wifiReading struct {
+	ssid    string    // Network the interface is connected to
+	bitrate float64   // Transmit bitrate in Mb/s
+	at      time.Time // When iw was run
+}
+
+// NewSampler creates a sampler without previous counters
+//
+// Returns: pointer to a configured Sampler
+func NewSampler() *Sampler {
+	return &Sampler{previous: make(map[string]net.IOCountersStat), wifi: make(map[string]wifiReading)}
+}
+
+// Sample reads the counters of every interface except loopback
+// Interfaces that are up come first, then by name, so the order stays stable between refreshes
+//
+// Returns:
+//   - slice of InterfaceStats (rates since the previous call)
+//   - error if the counters can't be read
+func (s *Sampler) Sample() ([]InterfaceStats, error) {
+	counters, err := net.IOCounters(true)
+	if err != nil {
+		return nil, fmt.Errorf("error reading network counters: %w", err)
+	}
+	now := time.Now()
+
+	// 1. Flags and addresses (missing on some platforms: interfaces are then shown as up)
+	details := make(map[string]net.InterfaceStat)
+	if interfaces, err := net.Interfaces(); err == nil {
+		for _, iface := range interfaces {
+			details[iface.Name] = iface
+		}
+	}
+
+	// 2. Rates since the previous call, and the state of the links
+	seconds := now.Sub(s.time).Seconds()
+	current := make(map[string]net.IOCountersStat, len(counters))
+	var stats []InterfaceStats
+	signals := readWireless()
+	for _, c := range counters {
+		current[c.Name] = c
+		iface, known := details[c.Name]
+		if c.Name == "lo" || hasFlag(iface.Flags, "loopback") {
+			continue
+		}
+
+		entry := InterfaceStats{
+			Name:    c.Name,
+			Up:      !known || hasFlag(iface.Flags, "up"),
+			RxBytes: c.BytesRecv,
+			TxBytes: c.BytesSent,
+			Errors:  c.Errin + c.Errout,
+			Drops:   c.Dropin + c.Dropout,
+		}
+		for _, addr := range iface.Addrs {
+			entry.Addresses = append(entry.Addresses, addr.Addr)
+		}
+		entry.Link = readLink(c.Name, signals)
+		if entry.Link.Wireless && entry.Up {
+			wifi := s.readWifi(c.Name, now)
+			entry.Link.SSID, entry.Link.BitrateMbps = wifi.ssid, wifi.bitrate
+		}
+
+		// Counters that went backwards (interface recreated) have no rate until the next call
+		if previous, ok := s.previous[c.Name]; ok && seconds > 0 &&
+			c.BytesRecv >= previous.BytesRecv && c.BytesSent >= previous.BytesSent {
+			entry.RxBytesPerSec = float64(c.BytesRecv-previous.BytesRecv) / seconds
+			entry.TxBytesPerSec = float64(c.BytesSent-previous.BytesSent) / seconds
+			entry.HasRate = true
+		}
+		stats = append(stats, entry)
+	}
+	s.previous, s.time = current, now
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Up != stats[j].Up {
+			return stats[i].Up
+		}
+		return stats[i].Name < stats[j].Name
+	})
+	return stats, nil
+}
+
+// readWifi returns the network of a Wi-Fi interface, running iw at most every ssidRefresh
+func (s *Sampler) readWifi(name string, now time.Time) wifiReading {
+	if reading, ok := s.wifi[name]; ok && now.Sub(reading.at) < ssidRefresh {
+		return reading
+	}
+	reading := wifiReading{at: now}
+	reading.ssid, reading.bitrate, _ = readWifi(context.Background(), name)
+	s.wifi[name] = reading
+	return reading
+}
+
+// hasFlag checks if an interface flag (e.g. "up") is set
+func hasFlag(flags []string, flag string) bool {
+	for _, f := range flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,259 @@
+package network
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+	gnet "github.com/shirou/gopsutil/v3/net"
+)
+
+// tcpStates are the TCP states of /proc/net/tcp (the "st" column, in hex)
+var tcpStates = map[string]string{
+	"01": "ESTABLISHED", "02": "SYN_SENT", "03": "SYN_RECV", "04": "FIN_WAIT1",
+	"05": "FIN_WAIT2", "06": "TIME_WAIT", "07": "CLOSE", "08": "CLOSE_WAIT",
+	"09": "LAST_ACK", "0A": "LISTEN", "0B": "CLOSING",
+}
+
+// stateOrder lists the TCP states in the order of the connection summary
+var stateOrder = []string{"ESTABLISHED", "LISTEN", "TIME_WAIT", "CLOSE_WAIT", "SYN_SENT", "SYN_RECV",
+	"FIN_WAIT1", "FIN_WAIT2", "LAST_ACK", "CLOSING", "CLOSE"}
+
+// Socket is a listening TCP or UDP socket with the process that owns it
+type Socket struct {
+	Protocol string // "tcp", "tcp6", "udp" or "udp6"
+	Address  string // Local address ("0.0.0.0" or "::" for every address)
+	Port     uint32 // Local port
+	PID      int32  // Owning process (0 if unknown, e.g. a process of another user without root)
+	Process  string // Name of the owning process (empty if unknown)
+	User     string // User of the owning process (empty if unknown)
+}
+
+// GetListeningSockets lists the listening TCP sockets and the unconnected UDP sockets
+//
+// Parameters:
+//   - ctx: deadline of the collection
+//   - processes: process table the owners are looked up in (from common.CollectAllProcessInfo)
+//
+// Returns:
+//   - slice of Socket sorted by port and protocol
+//   - error if the sockets can't be read
+func GetListeningSockets(ctx context.Context, processes []common.ProcessInfo) ([]Socket, error) {
+	connections, err := gnet.ConnectionsWithContext(ctx, "inet")
+	if err != nil {
+		return nil, fmt.Errorf("error reading sockets: %w", err)
+	}
+
+	owners := processIndex(processes)
+	var sockets []Socket
+	for _, c := range connections {
+		listening := (c.Type == syscall.SOCK_STREAM && c.Status == "LISTEN") || (c.Type == syscall.SOCK_DGRAM && c.Raddr.Port == 0)
+		if !listening {
+			continue
+		}
+
+		socket := Socket{Protocol: protocolName(c), Address: c.Laddr.IP, Port: c.Laddr.Port, PID: c.Pid}
+		if owner, ok := owners[c.Pid]; ok && c.Pid > 0 {
+			socket.Process, socket.User = owner.Name, owner.Username
+		}
+		sockets = append(sockets, socket)
+	}
+
+	sort.Slice(sockets, func(i, j int) bool {
+		if sockets[i].Port != sockets[j].Port {
+			return sockets[i].Port < sockets[j].Port
+		}
+		if sockets[i].Protocol != sockets[j].Protocol {
+			return sockets[i].Protocol < sockets[j].Protocol
+		}
+		return sockets[i].Address < sockets[j].Address
+	})
+	return sockets, nil
+}
+
+// Connection is a TCP or UDP socket with its addresses and the process that owns it
+type Connection struct {
+	Protocol string // "tcp", "tcp6", "udp" or "udp6"
+	Local    string // Local address and port (e.g. "192.168.1.10:22")
+	Remote   string // Remote address and port (empty for listening and unconnected sockets)
+	State    string // TCP state (e.g. "ESTABLISHED", empty for UDP)
+	PID      int32  // Owning process (0 if unknown, e.g. a process of another user without root)
+	Process  string // Name of the owning process (empty if unknown)
+	User     string // User of the owning process (empty if unknown)
+}
+
+// GetConnections lists every TCP and UDP socket with its owning process
+//
+// Parameters:
+//   - ctx: deadline of the collection
+//   - processes: process table the owners are looked up in (e.g. the processes of the TUI)
+//
+// Returns:
+//   - slice of Connection in the order of the kernel tables
+//   - error if the sockets can't be read
+func GetConnections(ctx context.Context, processes []common.ProcessInfo) ([]Connection, error) {
+	connections, err := gnet.ConnectionsWithContext(ctx, "inet")
+	if err != nil {
+		return nil, fmt.Errorf("error reading sockets: %w", err)
+	}
+
+	owners := processIndex(processes)
+	result := make([]Connection, 0, len(connections))
+	for _, c := range connections {
+		connection := Connection{Protocol: protocolName(c), Local: formatAddr(c.Laddr), PID: c.Pid}
+		if c.Raddr.Port != 0 {
+			connection.Remote = formatAddr(c.Raddr)
+		}
+		if c.Type == syscall.SOCK_STREAM {
+			connection.State = c.Status
+		}
+		if owner, ok := owners[c.Pid]; ok && c.Pid > 0 {
+			connection.Process, connection.User = owner.Name, owner.Username
+		}
+		result = append(result, connection)
+	}
+	return result, nil
+}
+
+// processIndex maps the processes by PID, to find the owners of sockets
+func processIndex(processes []common.ProcessInfo) map[int32]common.ProcessInfo {
+	index := make(map[int32]common.ProcessInfo, len(processes))
+	for _, p := range processes {
+		index[p.PID] = p
+	}
+	return index
+}
+
+// protocolName returns the protocol of a socket ("tcp", "tcp6", "udp" or "udp6")
+func protocolName(c gnet.ConnectionStat) string {
+	protocol := "udp"
+	if c.Type == syscall.SOCK_STREAM {
+		protocol = "tcp"
+	}
+	if c.Family == syscall.AF_INET6 {
+		protocol += "6"
+	}
+	return protocol
+}
+
+// formatAddr formats an address and port, with brackets around IPv6 addresses (e.g. "[::1]:631")
+func formatAddr(addr gnet.Addr) string {
+	return net.JoinHostPort(addr.IP, strconv.Itoa(int(addr.Port)))
+}
+
+// GetConnectionStates counts the TCP sockets in each state (e.g. ESTABLISHED, TIME_WAIT)
+// /proc/net/tcp is read directly, since finding the owner of every socket is not needed
+//
+// Returns:
+//   - number of sockets by state name (IPv4 and IPv6)
+//   - error if /proc/net/tcp can't be read
+func GetConnectionStates() (map[string]int, error) {
+	counts := make(map[string]int)
+	for i, file := range []string{"tcp", "tcp6"} {
+		if err := countStates(paths.Proc("net", file), counts); err != nil && i == 0 {
+			return nil, fmt.Errorf("error reading TCP connections: %w", err)
+		}
+	}
+	return counts, nil
+}
+
+// countStates adds the sockets of a /proc/net/tcp file to the counts by state
+func countStates(path string, counts map[string]int) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Scan() // Header
+	for scanner.Scan() {
+		// sl local_address rem_address st ...
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+		if state, ok := tcpStates[strings.ToUpper(fields[3])]; ok {
+			counts[state]++
+		}
+	}
+	return scanner.Err()
+}
+
+// FormatStates lists the connection counts in the usual order of the states
+// e.g. "12 ESTABLISHED, 8 LISTEN, 3 TIME_WAIT"
+func FormatStates(counts map[string]int) string {
+	var parts []string
+	for _, state := range stateOrder {
+		if counts[state] > 0 {
+			parts = append(parts, strconv.Itoa(counts[state])+" "+state)
+		}
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// PrintListeningSockets prints the listening sockets with their owning process
+//
+// Parameters:
+//   - sockets: sockets to present (from GetListeningSockets)
+func PrintListeningSockets(sockets []Socket) {
+	table := common.NewTable("Listening Ports",
+		common.Column{Header: "Proto"},
+		common.Column{Header: "Address", Optional: true},
+		common.Column{Header: "Port", Right: true},
+		common.Column{Header: "PID", Right: true},
+		common.Column{Header: "User", Optional: true},
+		common.Column{Header: "Process", Fill: true},
+	)
+
+	unknown := 0
+	for _, s := range sockets {
+		pid, name, user := "-", "-", "-"
+		if s.PID > 0 {
+			pid = strconv.Itoa(int(s.PID))
+		} else {
+			unknown++
+		}
+		if s.Process != "" {
+			name, user = s.Process, s.User
+		}
+		table.AddRow(s.Protocol, s.Address, strconv.Itoa(int(s.Port)), pid, common.TruncateString(user, 10), name)
+	}
+	if len(sockets) == 0 {
+		table.AddNote("No listening sockets")
+	}
+	if unknown > 0 {
+		table.AddNote(fmt.Sprintf("%d sockets without a known owner (other users' processes need root)", unknown))
+	}
+	table.Print()
+}
+
+// PrintConnectionStates prints the number of TCP connections in each state
+// Many TIME_WAIT or CLOSE_WAIT connections point at a service that opens too many
+// short connections or doesn't close them
+//
+// Parameters:
+//   - counts: number of sockets by state (from GetConnectionStates)
+func PrintConnectionStates(counts map[string]int) {
+	box := common.NewBox("TCP Connections")
+	total := 0
+	for _, state := range stateOrder {
+		if counts[state] > 0 {
+			box.Fieldf(state, "%d", counts[state])
+			total += counts[state]
+		}
+	}
+	box.Fieldf("Total", "%d", total)
+	box.End()
+}
@@ -0,0 +1,181 @@
+package paths
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// appName is the directory GoMonitor uses inside each base directory
+const appName = "gomonitor"
+
+// Environment variables that move a directory (the --config-dir and --state-dir flags take precedence)
+const (
+	ConfigDirEnv = "GOM_CONFIG_DIR" // Directory of config.json
+	StateDirEnv  = "GOM_STATE_DIR"  // Directory of the history (events, metrics) and other files written while running
+	CacheDirEnv  = "GOM_CACHE_DIR"  // Directory of data that can be collected again (e.g. the hardware info)
+)
+
+// Environment variables that move the kernel file systems, for running in a container with the
+// host's ones mounted elsewhere (e.g. HOST_PROC=/host/proc); gopsutil reads the same variables
+const (
+	ProcEnv = "HOST_PROC" // Mountpoint of the proc file system (default /proc)
+	SysEnv  = "HOST_SYS"  // Mountpoint of the sys file system (default /sys)
+)
+
+// ReadOnlyEnv makes GoMonitor write no files when set to a non-empty value (same as --read-only)
+const ReadOnlyEnv = "GOM_READ_ONLY"
+
+// Directories given on the command line (empty if not set)
+var (
+	configOverride string
+	stateOverride  string
+)
+
+// readOnly is set by --read-only
+var readOnly bool
+
+// SetConfigDir moves the config directory (--config-dir)
+func SetConfigDir(dir string) {
+	configOverride = dir
+}
+
+// SetStateDir moves the state directory (--state-dir)
+func SetStateDir(dir string) {
+	stateOverride = dir
+}
+
+// SetProcRoot reads the proc file system from another mountpoint (--procfs)
+// The environment variable is set as well, so gopsutil uses it too
+func SetProcRoot(dir string) {
+	os.Setenv(ProcEnv, dir)
+}
+
+// SetSysRoot reads the sys file system from another mountpoint (--sysfs)
+// The environment variable is set as well, so gopsutil uses it too
+func SetSysRoot(dir string) {
+	os.Setenv(SysEnv, dir)
+}
+
+// Proc returns a path in the proc file system (e.g. Proc("meminfo") is "/proc/meminfo")
+// The mountpoint can be moved with --procfs or HOST_PROC
+func Proc(elem ...string) string {
+	return filepath.Join(append([]string{root(ProcEnv, "/proc")}, elem...)...)
+}
+
+// Sys returns a path in the sys file system (e.g. Sys("block") is "/sys/block")
+// The mountpoint can be moved with --sysfs or HOST_SYS
+func Sys(elem ...string) string {
+	return filepath.Join(append([]string{root(SysEnv, "/sys")}, elem...)...)
+}
+
+// root returns the mountpoint set in an environment variable, or the usual one
+func root(env, fallback string) string {
+	if dir := os.Getenv(env); dir != "" {
+		return dir
+	}
+	return fallback
+}
+
+// SetReadOnly stops GoMonitor from writing files (--read-only)
+func SetReadOnly() {
+	readOnly = true
+}
+
+// ReadOnly checks if GoMonitor must not write files (history, daemon PID file and socket),
+// e.g. on the read-only root file system of a container
+func ReadOnly() bool {
+	return readOnly || os.Getenv(ReadOnlyEnv) != ""
+}
+
+// ConfigDir returns the directory GoMonitor reads its settings from
+// In order: --config-dir, GOM_CONFIG_DIR, $XDG_CONFIG_HOME/gomonitor, ~/.config/gomonitor
+//
+// Returns:
+//   - absolute path of the directory (it may not exist yet)
+//   - error if no override is set and the home directory can't be determined
+func ConfigDir() (string, error) {
+	return resolve(configOverride, ConfigDirEnv, "XDG_CONFIG_HOME", ".config")
+}
+
+// StateDir returns the directory GoMonitor writes its history and other data to
+// In order: --state-dir, GOM_STATE_DIR, $XDG_STATE_HOME/gomonitor, ~/.local/state/gomonitor
+//
+// Returns:
+//   - absolute path of the directory (it may not exist yet)
+//   - error if no override is set and the home directory can't be determined
+func StateDir() (string, error) {
+	return resolve(stateOverride, StateDirEnv, "XDG_STATE_HOME", filepath.Join(".local", "state"))
+}
+
+// CacheDir returns the directory GoMonitor keeps data it can collect again in (e.g. hw.json)
+// In order: GOM_CACHE_DIR, $XDG_CACHE_HOME/gomonitor, ~/.cache/gomonitor
+//
+// Returns:
+//   - absolute path of the directory (it may not exist yet)
+//   - error if GOM_CACHE_DIR isn't set and the home directory can't be determined
+func CacheDir() (string, error) {
+	return resolve("", CacheDirEnv, "XDG_CACHE_HOME", ".cache")
+}
+
+// ConfigFile returns the location of a file in the config directory
+//
+// Parameters:
+//   - name: file name (e.g. "config.json")
+func ConfigFile(name string) (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// StateFile returns the location of a file in the state directory
+//
+// Parameters:
+//   - name: file name (e.g. "events.jsonl")
+func StateFile(name string) (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// CacheFile returns the location of a file in the cache directory
+//
+// Parameters:
+//   - name: file name (e.g. "hw.json")
+func CacheFile(name string) (string, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// resolve picks a directory: the flag, the GoMonitor variable, the XDG variable, then the home fallback
+// The XDG specification ignores relative paths, so a relative XDG variable falls back to the home directory
+//
+// Parameters:
+//   - override: directory given on the command line
+//   - env: GoMonitor environment variable (the directory itself)
+//   - xdgEnv: XDG base directory variable (gomonitor is added to it)
+//   - fallback: base directory relative to the home directory
+func resolve(override, env, xdgEnv, fallback string) (string, error) {
+	if override != "" {
+		return filepath.Abs(override)
+	}
+	if dir := os.Getenv(env); dir != "" {
+		return filepath.Abs(dir)
+	}
+	if dir := os.Getenv(xdgEnv); filepath.IsAbs(dir) {
+		return filepath.Join(dir, appName), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error getting home directory: %w", err)
+	}
+	return filepath.Join(home, fallback, appName), nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/disk"
 )
 
 // GetProcessAssociation collects and associates CPU and RAM statistics for each process
@@ -31,7 +32,6 @@ func GetProcessAssociationSorted() ([]common.ProcessInfo, error) {
 	}
 
 	// 2. Sort processes by CPU usage (highest to lowest)
-	// Uses the common sorting function that implements selection sort
 	common.SortProcessesByField(processes, "cpu", true)
 
 	return processes, nil
@@ -48,7 +48,7 @@ func GetProcessAssociationSorted() ([]common.ProcessInfo, error) {
 //   - error if the process is not found or not accessible
 func GetProcessAssociationByPID(targetPID int32) (*common.ProcessInfo, error) {
 	// 1. Get total system memory (needed to calculate percentages)
-	totalSystemMem, err := common.GetSystemMemoryTotal()
+	totalSystemMem, err := common.GetProcessMemoryTotal()
 	if err != nil {
 		return nil, err
 	}
@@ -82,24 +82,139 @@ func MonitorProcessContinuous(targetPID int32, intervalSeconds int) error {
 	return common.MonitorProcessContinuously(targetPID, intervalSeconds)
 }
 
-// PrintTopProcesses prints the N processes with highest CPU usage
-// This function provides a formatted view of the most active processes
+// MonitorProcessGroup continuously monitors every process selected by name or pattern, as one group
+// Prints their total statistics at each interval until Ctrl+C, following respawned workers
+//
+// Parameters:
+//   - matcher: processes to monitor (see common.NewProcessMatcher)
+//   - intervalSeconds: interval between updates in seconds
+//
+// Returns:
+//   - error if no process matches or the processes cannot be read
+func MonitorProcessGroup(matcher common.ProcessMatcher, intervalSeconds int) error {
+	// Delegates to the common function that implements all monitoring logic
+	return common.MonitorProcessGroup(matcher, intervalSeconds)
+}
+
+// topSortTitles describes each field the top processes can be ranked by, for the table title
+var topSortTitles = map[string]string{
+	"cpu":  "CPU usage",
+	"ram":  "RAM usage",
+	"time": "CPU time since start",
+	"pid":  "PID",
+	"name": "name",
+	"io":   "disk I/O",
+}
+
+// IsTopSortField checks if the top processes can be ranked by a field ("cpu", "ram", "time", "pid", "name" or "io")
+func IsTopSortField(field string) bool {
+	_, ok := topSortTitles[field]
+	return ok
+}
+
+// PrintTopProcesses prints the N processes with the highest value of a field
+// This function provides a formatted view of the most active processes; ranking by
+// CPU time shows long-running processes that are idle right now but used the most overall
 //
 // Parameters:
 //   - n: number of processes to show (top N)
+//   - username: only show processes owned by this user (empty = all users)
+//   - field: field to rank by ("cpu", "ram", "time", "pid", "name" or "io")
+//   - options: table columns to show and order of the field
 //
 // Returns:
 //   - error if unable to get process data
-func PrintTopProcesses(n int) error {
-	// 1. Get processes sorted by CPU usage
-	processes, err := GetProcessAssociationSorted()
+func PrintTopProcesses(n int, username, field string, options common.TableOptions) error {
+	// 1. Get all processes
+	processes, collection, err := CollectTopProcesses(field, options)
 	if err != nil {
-		return fmt.Errorf("error getting sorted processes: %w", err)
+		return err
 	}
 
+	PrintTopProcessesOf(processes, collection, n, username, field, options)
+	return nil
+}
+
+// CollectTopProcesses collects the processes for the top listings
+// Their disk I/O is only measured when it is sorted by or shown, since it takes another sample
+//
+// Parameters:
+//   - field: field the processes are ranked by
+//   - options: columns to show
+//
+// Returns:
+//   - every process
+//   - CollectionStats with the number of shown and skipped processes
+//   - error if unable to get process data
+func CollectTopProcesses(field string, options common.TableOptions) ([]common.ProcessInfo, common.CollectionStats, error) {
+	processes, collection, err := common.CollectAllProcessInfoWithStats()
+	if err != nil {
+		return nil, collection, fmt.Errorf("error getting processes: %w", err)
+	}
+	if field == "io" || options.HasColumn("io") {
+		disk.MeasureProcessIO(processes, common.DefaultSampleInterval)
+	}
+	return processes, collection, nil
+}
+
+// PrintTopProcessesOf prints the first N processes in the order of a field from
+// PrintTopProcessesOf prints the N processes with the highest value of a field from
+// processes that were already collected (e.g. shared by the sections of the overview)
+//
+// Parameters:
+//   - processes: every process
+//   - collection: number of shown and skipped processes of the collection
+//   - n, username, field, options: as in PrintTopProcesses
+func PrintTopProcessesOf(processes []common.ProcessInfo, collection common.CollectionStats, n int, username, field string, options common.TableOptions) {
+	// 1. Keep only the processes of the requested user (if any), then the N first in the order of the field
+	processes = common.FilterProcessesByUser(processes, username)
+	processes = common.FirstKProcesses(processes, n, field, common.SortsDescending(field) != options.Reverse)
+
 	// 2. Use the common function to print the formatted table
-	title := fmt.Sprintf("Top %d Processes (sorted by CPU usage)", n)
-	common.PrintProcessTable(processes, n, title)
+	order := topSortTitles[field]
+	if options.Reverse {
+		order += ", reversed"
+	}
+	title := fmt.Sprintf("Top %d Processes (sorted by %s)", n, order)
+	if username != "" {
+		title = fmt.Sprintf("Top %d Processes of %s (sorted by %s)", n, username, order)
+	}
+	common.PrintProcessTableWithOptions(processes, n, title, options)
+
+	// 3. Warn when processes couldn't be read, since they are missing from the list
+	if collection.Skipped > 0 {
+		fmt.Printf("  %s (run as root to see all processes)\n", collection)
+	}
+}
+
+// PrintTopApplications prints the N applications with highest CPU usage
+// The processes of one program (e.g. every renderer of a browser) are shown as one row
+//
+// Parameters:
+//   - n: number of applications to show (top N)
+//   - username: only count processes owned by this user (empty = all users)
+//
+// Returns:
+//   - error if unable to get process data
+func PrintTopApplications(n int, username string) error {
+	// 1. Get all processes of the requested user (if any)
+	processes, collection, err := common.CollectAllProcessInfoWithStats()
+	if err != nil {
+		return fmt.Errorf("error getting processes: %w", err)
+	}
+	processes = common.FilterProcessesByUser(processes, username)
+
+	// 2. Group them by application and print the N with highest CPU usage
+	title := fmt.Sprintf("Top %d Applications (sorted by CPU usage)", n)
+	if username != "" {
+		title = fmt.Sprintf("Top %d Applications of %s (sorted by CPU usage)", n, username)
+	}
+	common.PrintApplicationTable(common.GroupByApplication(processes), n, title)
+
+	// 3. Warn when processes couldn't be read, since they are missing from the totals
+	if collection.Skipped > 0 {
+		fmt.Printf("  %s (run as root to see all processes)\n", collection)
+	}
 
 	return nil
 }
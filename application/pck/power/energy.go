@@ -0,0 +1,236 @@
+package power
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+)
+
+// raplRoot returns where the kernel exposes the RAPL energy counters (powercap)
+func raplRoot() string {
+	return paths.Sys("class", "powercap")
+}
+
+// ErrNoRAPL is returned when the CPU has no RAPL energy counters (e.g. ARM, most virtual machines)
+var ErrNoRAPL = errors.New("no RAPL energy counters (needs an Intel or AMD CPU)")
+
+// ProcessEnergy contains the energy attributed to a process over a sample interval
+type ProcessEnergy struct {
+	Process common.ProcessInfo // Process information (CPU usage measured over the same interval)
+	Watts   float64            // Average power attributed to the process
+	Joules  float64            // Energy attributed to the process over the interval
+}
+
+// raplDomain is the energy counter of one CPU package
+type raplDomain struct {
+	path     string // energy_uj file
+	maxRange uint64 // Value at which the counter wraps around (0 if unknown)
+}
+
+// EnergySampler measures the power of the CPU packages between calls and attributes it to processes
+// Kept by long-running views (e.g. the TUI), which get the power since their previous refresh
+type EnergySampler struct {
+	domains  []raplDomain // Package counters (found on the first call)
+	previous []uint64     // Counter values of the previous call (nil before the first one)
+	taken    time.Time    // When the previous values were read
+}
+
+// NewEnergySampler creates a sampler without previous readings
+//
+// Returns: pointer to a configured EnergySampler
+func NewEnergySampler() *EnergySampler {
+	return &EnergySampler{}
+}
+
+// Sample reads the package energy counters and attributes the power used since the previous call
+// Each process gets the share of the package power matching its share of the total CPU time of
+// every core (e.g. a process using one full core of 4 gets a quarter): the power drawn while the
+// cores are idle isn't attributed to any process. The first call has no power yet
+//
+// Parameters:
+//   - processes: processes with their CPU usage over the same interval
+//
+// Returns:
+//   - map of PID to ProcessEnergy (empty on the first call)
+//   - package power in watts since the previous call (0 on the first call)
+//   - error wrapping ErrNoRAPL, or the permission error (the counters are only readable by root)
+func (s *EnergySampler) Sample(processes []common.ProcessInfo) (map[int32]ProcessEnergy, float64, error) {
+	if s.domains == nil {
+		domains, err := packageDomains()
+		if err != nil {
+			return nil, 0, err
+		}
+		s.domains = domains
+	}
+
+	// 1. Read every package counter
+	now := time.Now()
+	current := make([]uint64, len(s.domains))
+	for i, domain := range s.domains {
+		value, err := readUint(domain.path)
+		if errors.Is(err, os.ErrPermission) {
+			return nil, 0, fmt.Errorf("error reading RAPL energy counter (root only): %w", err)
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("error reading RAPL energy counter: %w", err)
+		}
+		current[i] = value
+	}
+	previous, taken := s.previous, s.taken
+	s.previous, s.taken = current, now
+
+	seconds := now.Sub(taken).Seconds()
+	if previous == nil || seconds <= 0 {
+		return map[int32]ProcessEnergy{}, 0, nil
+	}
+
+	// 2. Energy used since the previous call, handling counters that wrapped around
+	var microjoules uint64
+	for i, domain := range s.domains {
+		if current[i] >= previous[i] {
+			microjoules += current[i] - previous[i]
+		} else if domain.maxRange > previous[i] {
+			microjoules += domain.maxRange - previous[i] + current[i]
+		}
+	}
+	joules := float64(microjoules) / 1e6
+	watts := joules / seconds
+
+	// 3. Attribute it by share of the CPU time of all cores (100% = one core)
+	capacity := float64(runtime.NumCPU()) * 100
+	energy := make(map[int32]ProcessEnergy, len(processes))
+	for _, p := range processes {
+		share := min(p.CPUPercentage/capacity, 1)
+		if share <= 0 {
+			continue
+		}
+		energy[p.PID] = ProcessEnergy{Process: p, Watts: watts * share, Joules: joules * share}
+	}
+	return energy, watts, nil
+}
+
+// packageDomains finds the package-level RAPL counters (intel-rapl:0, intel-rapl:1...)
+// The sub-domains (cores, uncore, DRAM) are part of their package and left out
+//
+// Returns:
+//   - one raplDomain per CPU package
+//   - error wrapping ErrNoRAPL if there are none
+func packageDomains() ([]raplDomain, error) {
+	paths, _ := filepath.Glob(filepath.Join(raplRoot(), "intel-rapl:*", "energy_uj"))
+
+	var domains []raplDomain
+	for _, path := range paths {
+		dir := filepath.Dir(path)
+		if strings.Count(filepath.Base(dir), ":") != 1 {
+			continue // Sub-domain (e.g. intel-rapl:0:0)
+		}
+		maxRange, _ := readUint(filepath.Join(dir, "max_energy_range_uj"))
+		domains = append(domains, raplDomain{path: path, maxRange: maxRange})
+	}
+
+	if len(domains) == 0 {
+		return nil, ErrNoRAPL
+	}
+	return domains, nil
+}
+
+// readUint reads a sysfs file holding one unsigned number
+func readUint(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+}
+
+// GetTopEnergy measures the package power over SampleInterval and the processes it is attributed to
+//
+// Parameters:
+//   - n: number of processes to return
+//
+// Returns:
+//   - slice of ProcessEnergy sorted by power (descending)
+//   - package power in watts over the interval
+//   - error if the counters or the processes can't be read
+func GetTopEnergy(n int) ([]ProcessEnergy, float64, error) {
+	sampler := NewEnergySampler()
+	if _, _, err := sampler.Sample(nil); err != nil {
+		return nil, 0, err
+	}
+
+	// The processes' CPU usage is measured over the same interval as the energy
+	processes, _, err := common.CollectProcessInfo(common.CollectOptions{SampleInterval: SampleInterval})
+	if err != nil {
+		return nil, 0, fmt.Errorf("error collecting processes: %w", err)
+	}
+
+	energy, watts, err := sampler.Sample(processes)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	self := int32(os.Getpid())
+	top := make([]ProcessEnergy, 0, len(energy))
+	for pid, e := range energy {
+		if pid != self { // GoMonitor itself is busy reading /proc during the interval
+			top = append(top, e)
+		}
+	}
+	sort.Slice(top, func(i, j int) bool {
+		return top[i].Watts > top[j].Watts
+	})
+	if n > 0 && len(top) > n {
+		top = top[:n]
+	}
+	return top, watts, nil
+}
+
+// PrintEnergy prints the processes with the most attributed power in a formatted table
+//
+// Parameters:
+//   - energy: slice of ProcessEnergy to present (from GetTopEnergy)
+//   - watts: package power over the interval
+func PrintEnergy(energy []ProcessEnergy, watts float64) {
+	var attributed float64
+	for _, e := range energy {
+		attributed += e.Watts
+	}
+
+	title := fmt.Sprintf("Top %d Processes by Estimated Power (sampled over %s)", len(energy), SampleInterval)
+	table := common.NewTable(title,
+		common.Column{Header: "PID"}, common.Column{Header: "Name", Fill: true},
+		common.Column{Header: "User", Optional: true}, common.Column{Header: "CPU", Right: true},
+		common.Column{Header: "Power", Right: true}, common.Column{Header: "Energy", Right: true, Optional: true})
+
+	for _, e := range energy {
+		table.AddRow(strconv.Itoa(int(e.Process.PID)),
+			common.TruncateString(e.Process.Name, 22),
+			common.TruncateString(e.Process.Username, 10),
+			fmt.Sprintf("%.1f%%", e.Process.CPUPercentage),
+			FormatWatts(e.Watts),
+			fmt.Sprintf("%.2f J", e.Joules))
+	}
+
+	table.AddDivider()
+	table.AddNote(fmt.Sprintf("%-17s%s", "CPU packages:", FormatWatts(watts)))
+	table.AddNote(fmt.Sprintf("%-17s%s", "Listed above:", FormatWatts(attributed)))
+	table.AddNote("Estimated as the package power times each process's share of all cores' CPU time")
+	table.Print()
+}
+
+// FormatWatts formats a power in watts (e.g. "3.25 W", "850 mW")
+func FormatWatts(watts float64) string {
+	if watts < 1 {
+		return fmt.Sprintf("%.0f mW", watts*1000)
+	}
+	return fmt.Sprintf("%.2f W", watts)
+}
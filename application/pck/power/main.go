@@ -0,0 +1,182 @@
+package power
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+)
+
+// SampleInterval is how long wake-ups are counted for
+// Longer than the CPU sample interval, since wake-ups of idle processes are bursty
+const SampleInterval = 2 * time.Second
+
+// ProcessWakeups contains how often a process was woken up over the sample interval
+// A process that sleeps and wakes up often (timers, polling loops) keeps the CPU out of
+// deep idle states and drains the battery even when its CPU usage looks negligible
+type ProcessWakeups struct {
+	Process           common.ProcessInfo // Process information (CPU usage measured over the same interval)
+	WakeupsPerSec     float64            // Voluntary context switches per second (sleep → wake-up cycles)
+	PreemptionsPerSec float64            // Involuntary context switches per second (preempted while running)
+}
+
+// switchCounts contains the context switch counters of a process (summed over its threads)
+type switchCounts struct {
+	voluntary   uint64
+	involuntary uint64
+}
+
+// GetTopWakeups measures the wake-ups of every process over SampleInterval
+// Wake-ups are approximated by voluntary context switches: every time a thread sleeps
+// (timer, poll, read) and is woken up again counts as one, like powertop's top offenders
+//
+// Parameters:
+//   - n: number of processes to return
+//
+// Returns:
+//   - slice of ProcessWakeups sorted by wake-ups per second (descending)
+//   - total wake-ups per second of all processes
+//   - error if the processes can't be read
+func GetTopWakeups(n int) ([]ProcessWakeups, float64, error) {
+	// 1. First snapshot of the context switch counters
+	before := readAllSwitches()
+	start := time.Now()
+
+	// 2. Collect the processes, measuring their CPU usage over the sample interval
+	processes, _, err := common.CollectProcessInfo(common.CollectOptions{SampleInterval: SampleInterval})
+	if err != nil {
+		return nil, 0, fmt.Errorf("error collecting processes: %w", err)
+	}
+
+	// 3. Second snapshot: the difference over the elapsed time is the rate
+	after := readAllSwitches()
+	elapsed := time.Since(start).Seconds()
+
+	var total float64
+	self := int32(os.Getpid())
+	wakeups := make([]ProcessWakeups, 0, len(processes))
+	for _, p := range processes {
+		if p.PID == self {
+			continue // GoMonitor itself is busy reading /proc during the interval
+		}
+		first, ok := before[p.PID]
+		if !ok {
+			continue // Started during the interval
+		}
+		second, ok := after[p.PID]
+		if !ok || second.voluntary < first.voluntary {
+			continue // Exited, or PID reused by a new process
+		}
+
+		entry := ProcessWakeups{
+			Process:       p,
+			WakeupsPerSec: float64(second.voluntary-first.voluntary) / elapsed,
+		}
+		if second.involuntary >= first.involuntary {
+			entry.PreemptionsPerSec = float64(second.involuntary-first.involuntary) / elapsed
+		}
+		total += entry.WakeupsPerSec
+		wakeups = append(wakeups, entry)
+	}
+
+	sort.SliceStable(wakeups, func(i, j int) bool {
+		return wakeups[i].WakeupsPerSec > wakeups[j].WakeupsPerSec
+	})
+	if n > 0 && len(wakeups) > n {
+		wakeups = wakeups[:n]
+	}
+
+	return wakeups, total, nil
+}
+
+// readAllSwitches reads the context switch counters of every process
+//
+// Returns: map of PID to counters (processes that can't be read are left out)
+func readAllSwitches() map[int32]switchCounts {
+	entries, err := os.ReadDir(paths.Proc())
+	if err != nil {
+		return nil
+	}
+
+	counts := make(map[int32]switchCounts, len(entries))
+	for _, entry := range entries {
+		pid, err := strconv.ParseInt(entry.Name(), 10, 32)
+		if err != nil {
+			continue
+		}
+		if switches, ok := readSwitches(int32(pid)); ok {
+			counts[int32(pid)] = switches
+		}
+	}
+	return counts
+}
+
+// readSwitches sums the context switch counters of all threads of a process
+// /proc/<pid>/status only counts the main thread, so each /proc/<pid>/task/<tid>/status is read
+//
+// Returns:
+//   - summed counters
+//   - false if the process can't be read
+func readSwitches(pid int32) (switchCounts, bool) {
+	statusFiles, err := filepath.Glob(paths.Proc(fmt.Sprint(pid), "task", "*", "status"))
+	if err != nil || len(statusFiles) == 0 {
+		return switchCounts{}, false
+	}
+
+	var counts switchCounts
+	for _, path := range statusFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue // Thread exited
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			key, value, ok := strings.Cut(line, ":")
+			if !ok {
+				continue
+			}
+			n, err := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+			if err != nil {
+				continue
+			}
+			switch key {
+			case "voluntary_ctxt_switches":
+				counts.voluntary += n
+			case "nonvoluntary_ctxt_switches":
+				counts.involuntary += n
+			}
+		}
+	}
+	return counts, true
+}
+
+// PrintWakeups prints the processes with the most wake-ups in a formatted table
+//
+// Parameters:
+//   - wakeups: slice of ProcessWakeups to present (from GetTopWakeups)
+//   - total: total wake-ups per second of all processes
+func PrintWakeups(wakeups []ProcessWakeups, total float64) {
+	title := fmt.Sprintf("Top %d Processes by Wake-ups (sampled over %s)", len(wakeups), SampleInterval)
+	table := common.NewTable(title,
+		common.Column{Header: "PID"}, common.Column{Header: "Name", Fill: true},
+		common.Column{Header: "User", Optional: true}, common.Column{Header: "Wakeups/s", Short: "Wake/s", Right: true},
+		common.Column{Header: "Preempt/s", Short: "Pre/s", Right: true, Optional: true}, common.Column{Header: "CPU", Right: true})
+
+	for _, w := range wakeups {
+		table.AddRow(strconv.Itoa(int(w.Process.PID)),
+			common.TruncateString(w.Process.Name, 22),
+			common.TruncateString(w.Process.Username, 10),
+			fmt.Sprintf("%.1f", w.WakeupsPerSec),
+			fmt.Sprintf("%.1f", w.PreemptionsPerSec),
+			fmt.Sprintf("%.1f%%", w.Process.CPUPercentage))
+	}
+
+	table.AddDivider()
+	table.AddNote(fmt.Sprintf("%-17s%.1f wake-ups/s", "Total:", total))
+	table.Print()
+}
@@ -0,0 +1,102 @@
+package procfs
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+)
+
+// OpenFile is an open file descriptor of a process
+type OpenFile struct {
+	FD     int    // Descriptor number
+	Target string // What it refers to: a path, "socket:[12345]", "pipe:[678]", "anon_inode:[eventfd]"...
+}
+
+// CountFDs counts the open file descriptors of a process (the entries of /proc/<pid>/fd)
+// Only the names are read, so it's cheap even for processes with many descriptors
+//
+// Parameters:
+//   - pid: process ID
+//
+// Returns:
+//   - number of open descriptors
+//   - error if the directory can't be read (other users' processes need root)
+func CountFDs(pid int32) (int, error) {
+	dir, err := os.Open(paths.Proc(strconv.Itoa(int(pid)), "fd"))
+	if err != nil {
+		return 0, fmt.Errorf("error reading descriptors of PID %d: %w", pid, err)
+	}
+	defer dir.Close()
+
+	names, err := dir.Readdirnames(-1)
+	if err != nil {
+		return 0, fmt.Errorf("error reading descriptors of PID %d: %w", pid, err)
+	}
+	return len(names), nil
+}
+
+// ReadOpenFiles lists the open file descriptors of a process, like lsof -p
+// Descriptors closed while they are read are left out
+//
+// Parameters:
+//   - pid: process ID
+//
+// Returns:
+//   - slice of OpenFile sorted by descriptor number
+//   - error if the directory can't be read (other users' processes need root)
+func ReadOpenFiles(pid int32) ([]OpenFile, error) {
+	entries, err := os.ReadDir(paths.Proc(strconv.Itoa(int(pid)), "fd"))
+	if err != nil {
+		return nil, fmt.Errorf("error reading descriptors of PID %d: %w", pid, err)
+	}
+
+	files := make([]OpenFile, 0, len(entries))
+	for _, entry := range entries {
+		fd, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		target, err := os.Readlink(paths.Proc(strconv.Itoa(int(pid)), "fd", entry.Name()))
+		if err != nil {
+			continue
+		}
+		files = append(files, OpenFile{FD: fd, Target: target})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].FD < files[j].FD })
+	return files, nil
+}
+
+// ReadFDLimit reads the soft limit of open files of a process (RLIMIT_NOFILE)
+// Opening a file fails with EMFILE ("too many open files") once the limit is reached
+//
+// Parameters:
+//   - pid: process ID
+//
+// Returns:
+//   - soft limit (0 if unlimited)
+//   - error if /proc/<pid>/limits can't be read or has no open files line
+func ReadFDLimit(pid int32) (uint64, error) {
+	// e.g. "Max open files            1024                 524288               files"
+	var limit uint64
+	found := false
+	err := eachLine(paths.Proc(strconv.Itoa(int(pid)), "limits"), func(line string) {
+		value, ok := strings.CutPrefix(line, "Max open files")
+		fields := strings.Fields(value)
+		if !ok || len(fields) == 0 {
+			return
+		}
+		found = true
+		limit, _ = strconv.ParseUint(fields[0], 10, 64) // "unlimited" stays 0
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error reading limits of PID %d: %w", pid, err)
+	}
+	if !found {
+		return 0, fmt.Errorf("error reading limits of PID %d: no open files limit", pid)
+	}
+	return limit, nil
+}
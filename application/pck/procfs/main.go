@@ -0,0 +1,277 @@
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+)
+
+// ClockTicks is the unit of the CPU times in /proc (USER_HZ, 100 on every Linux architecture)
+const ClockTicks = 100
+
+// sectorSize is the unit of the sector counts in /proc/diskstats (always 512 bytes)
+const sectorSize = 512
+
+// Meminfo contains the memory counters of /proc/meminfo (in bytes)
+type Meminfo struct {
+	Total         uint64 // MemTotal: RAM installed
+	Free          uint64 // MemFree: RAM not used at all
+	Available     uint64 // MemAvailable: RAM available for new processes without swapping
+	Buffers       uint64 // Buffers: block device cache
+	Cached        uint64 // Cached: page cache
+	SReclaimable  uint64 // SReclaimable: kernel slab memory that can be reclaimed
+	Shared        uint64 // Shmem: shared memory and tmpfs (part of Cached)
+	Slab          uint64 // Slab: kernel data structures (including SReclaimable)
+	Dirty         uint64 // Dirty: modified file data waiting to be written to disk
+	HugePages     uint64 // HugePages_Total: number of huge pages reserved (a count, not bytes)
+	HugePagesFree uint64 // HugePages_Free: number of reserved huge pages not in use
+	HugePageSize  uint64 // Hugepagesize: size of a huge page
+	SwapTotal     uint64 // SwapTotal: swap space
+	SwapFree      uint64 // SwapFree: unused swap space
+}
+
+// Used returns the RAM in use, without buffers and reclaimable caches (like free and gopsutil)
+func (m Meminfo) Used() uint64 {
+	cache := m.Free + m.Buffers + m.Cached + m.SReclaimable
+	if cache > m.Total {
+		return 0
+	}
+	return m.Total - cache
+}
+
+// CPUTimes contains the time all CPUs spent in each mode since boot (the "cpu" line of /proc/stat)
+type CPUTimes struct {
+	User    float64 // Seconds running user code (without nice)
+	Nice    float64 // Seconds running niced user code
+	System  float64 // Seconds running kernel code
+	Idle    float64 // Seconds idle
+	Iowait  float64 // Seconds idle while waiting for I/O
+	Irq     float64 // Seconds serving interrupts
+	Softirq float64 // Seconds serving soft interrupts
+	Steal   float64 // Seconds taken by the hypervisor for other virtual machines
+}
+
+// Total returns the time spent in all modes
+func (t CPUTimes) Total() float64 {
+	return t.User + t.Nice + t.System + t.Idle + t.Iowait + t.Irq + t.Softirq + t.Steal
+}
+
+// BusyPercent returns the CPU usage between two readings of /proc/stat (0-100%)
+//
+// Parameters:
+//   - before: first reading
+//   - after: second reading
+//
+// Returns: time not idle as a percentage of the time elapsed on all CPUs (0 if no time elapsed)
+func BusyPercent(before, after CPUTimes) float64 {
+	total := after.Total() - before.Total()
+	if total <= 0 {
+		return 0
+	}
+	idle := (after.Idle + after.Iowait) - (before.Idle + before.Iowait)
+	return min(max((total-idle)/total*100, 0), 100)
+}
+
+// DiskStats contains the I/O counters of one block device (a line of /proc/diskstats)
+type DiskStats struct {
+	Name             string // Device name (e.g. "sda", "nvme0n1p1")
+	ReadCount        uint64 // Reads completed
+	MergedReadCount  uint64 // Adjacent reads merged into one
+	ReadBytes        uint64 // Bytes read
+	ReadTime         uint64 // Milliseconds spent reading
+	WriteCount       uint64 // Writes completed
+	MergedWriteCount uint64 // Adjacent writes merged into one
+	WriteBytes       uint64 // Bytes written
+	WriteTime        uint64 // Milliseconds spent writing
+	InProgress       uint64 // I/Os currently in progress
+	IoTime           uint64 // Milliseconds the device was busy
+	WeightedIO       uint64 // Milliseconds spent on I/O, weighted by the number of I/Os in progress
+}
+
+// ReadMeminfo reads the memory counters of /proc/meminfo
+//
+// Returns:
+//   - Meminfo with the counters in bytes
+//   - error if the file can't be read or has no MemTotal
+func ReadMeminfo() (Meminfo, error) {
+	var info Meminfo
+	fields := map[string]*uint64{
+		"MemTotal": &info.Total, "MemFree": &info.Free, "MemAvailable": &info.Available,
+		"Buffers": &info.Buffers, "Cached": &info.Cached, "SReclaimable": &info.SReclaimable,
+		"Shmem": &info.Shared, "Slab": &info.Slab, "Dirty": &info.Dirty,
+		"HugePages_Total": &info.HugePages, "HugePages_Free": &info.HugePagesFree, "Hugepagesize": &info.HugePageSize,
+		"SwapTotal": &info.SwapTotal, "SwapFree": &info.SwapFree,
+	}
+
+	err := eachLine(paths.Proc("meminfo"), func(line string) {
+		// e.g. "MemTotal:       16318412 kB" (the huge page counts have no unit)
+		key, value, ok := strings.Cut(line, ":")
+		field := fields[key]
+		if !ok || field == nil {
+			return
+		}
+		values := strings.Fields(value)
+		if len(values) == 0 {
+			return
+		}
+		if n, err := strconv.ParseUint(values[0], 10, 64); err == nil {
+			*field = n
+			if len(values) > 1 && values[1] == "kB" {
+				*field = n * 1024
+			}
+		}
+	})
+	if err != nil {
+		return info, fmt.Errorf("error reading meminfo: %w", err)
+	}
+	if info.Total == 0 {
+		return info, fmt.Errorf("error reading meminfo: no MemTotal")
+	}
+	return info, nil
+}
+
+// ReadCPUTimes reads the time all CPUs spent in each mode since boot
+//
+// Returns:
+//   - CPUTimes in seconds
+//   - error if /proc/stat can't be read or has no "cpu" line
+func ReadCPUTimes() (CPUTimes, error) {
+	var times CPUTimes
+	found := false
+
+	err := eachLine(paths.Proc("stat"), func(line string) {
+		// e.g. "cpu  4705 356 584 3699176 23060 0 277 0 0 0"
+		fields := strings.Fields(line)
+		if found || len(fields) < 5 || fields[0] != "cpu" {
+			return
+		}
+		times = parseCPUTimes(fields[1:])
+		found = true
+	})
+	if err != nil {
+		return times, fmt.Errorf("error reading CPU times: %w", err)
+	}
+	if !found {
+		return times, fmt.Errorf("error reading CPU times: no cpu line in %s", paths.Proc("stat"))
+	}
+	return times, nil
+}
+
+// ReadPerCPUTimes reads the time each CPU spent in each mode since boot (the "cpuN" lines of /proc/stat)
+//
+// Returns:
+//   - CPUTimes in seconds, one per CPU in the order of /proc/stat
+//   - error if /proc/stat can't be read or has no per-CPU lines
+func ReadPerCPUTimes() ([]CPUTimes, error) {
+	var times []CPUTimes
+
+	err := eachLine(paths.Proc("stat"), func(line string) {
+		// e.g. "cpu0 1180 87 140 924560 5702 0 70 0 0 0"
+		fields := strings.Fields(line)
+		if len(fields) < 5 || fields[0] == "cpu" || !strings.HasPrefix(fields[0], "cpu") {
+			return
+		}
+		times = append(times, parseCPUTimes(fields[1:]))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading CPU times: %w", err)
+	}
+	if len(times) == 0 {
+		return nil, fmt.Errorf("error reading CPU times: no per-CPU lines in %s", paths.Proc("stat"))
+	}
+	return times, nil
+}
+
+// parseCPUTimes converts the tick counts of a cpu line of /proc/stat (without its name) to CPUTimes
+func parseCPUTimes(fields []string) CPUTimes {
+	values := make([]float64, 8)
+	for i := range values {
+		if i < len(fields) {
+			ticks, _ := strconv.ParseUint(fields[i], 10, 64)
+			values[i] = float64(ticks) / ClockTicks
+		}
+	}
+	return CPUTimes{
+		User: values[0], Nice: values[1], System: values[2], Idle: values[3],
+		Iowait: values[4], Irq: values[5], Softirq: values[6], Steal: values[7],
+	}
+}
+
+// BootTime reads when the system booted (the "btime" line of /proc/stat)
+//
+// Returns:
+//   - boot time in seconds since the epoch
+//   - error if /proc/stat can't be read or has no btime
+func BootTime() (int64, error) {
+	var boot int64
+	err := eachLine(paths.Proc("stat"), func(line string) {
+		if value, ok := strings.CutPrefix(line, "btime "); ok {
+			boot, _ = strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		}
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error reading boot time: %w", err)
+	}
+	if boot == 0 {
+		return 0, fmt.Errorf("error reading boot time: no btime in %s", paths.Proc("stat"))
+	}
+	return boot, nil
+}
+
+// ReadDiskstats reads the I/O counters of every block device
+//
+// Returns:
+//   - map of device name to DiskStats
+//   - error if /proc/diskstats can't be read
+func ReadDiskstats() (map[string]DiskStats, error) {
+	stats := make(map[string]DiskStats)
+
+	err := eachLine(paths.Proc("diskstats"), func(line string) {
+		// major minor name, then 11 counters (more on recent kernels, which are ignored)
+		fields := strings.Fields(line)
+		if len(fields) < 14 {
+			return
+		}
+		values := make([]uint64, 11)
+		for i := range values {
+			values[i], _ = strconv.ParseUint(fields[i+3], 10, 64)
+		}
+		stats[fields[2]] = DiskStats{
+			Name:             fields[2],
+			ReadCount:        values[0],
+			MergedReadCount:  values[1],
+			ReadBytes:        values[2] * sectorSize,
+			ReadTime:         values[3],
+			WriteCount:       values[4],
+			MergedWriteCount: values[5],
+			WriteBytes:       values[6] * sectorSize,
+			WriteTime:        values[7],
+			InProgress:       values[8],
+			IoTime:           values[9],
+			WeightedIO:       values[10],
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading diskstats: %w", err)
+	}
+	return stats, nil
+}
+
+// eachLine calls fn for every line of a file
+func eachLine(path string, fn func(line string)) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fn(scanner.Text())
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,130 @@
+package procfs
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+)
+
+// Process contains the information of a process read from /proc/<pid>/stat and /proc/<pid>/status
+type Process struct {
+	PID        int32   // Process ID
+	Name       string  // Executable name (at most 15 characters, like ps)
+	State      string  // Scheduling state letter (R, S, D, Z, T, I...)
+	PPID       int32   // Parent process ID
+	PGID       int32   // Process group ID
+	SID        int32   // Session ID
+	Nice       int32   // Nice value, from -20 to 19
+	NumThreads int32   // Number of threads
+	LastCPU    int     // CPU core the process last ran on
+	StartTime  float64 // Seconds after boot when the process started
+	CPUTime    float64 // User + system CPU time used so far in seconds
+	RSS        uint64  // Resident memory in bytes
+	UID        int32   // Real user ID of the owner
+}
+
+// Pids lists the IDs of all processes
+//
+// Returns:
+//   - process IDs (the numeric directories of /proc)
+//   - error if /proc can't be read
+func Pids() ([]int32, error) {
+	entries, err := os.ReadDir(paths.Proc())
+	if err != nil {
+		return nil, fmt.Errorf("error listing processes: %w", err)
+	}
+
+	pids := make([]int32, 0, len(entries))
+	for _, entry := range entries {
+		if pid, err := strconv.ParseInt(entry.Name(), 10, 32); err == nil && entry.IsDir() {
+			pids = append(pids, int32(pid))
+		}
+	}
+	return pids, nil
+}
+
+// ReadProcess reads the information of a process
+//
+// Parameters:
+//   - pid: process ID
+//
+// Returns:
+//   - Process
+//   - error wrapping os.ErrNotExist if the process terminated, or the permission error if it can't be read
+func ReadProcess(pid int32) (Process, error) {
+	p := Process{PID: pid, LastCPU: -1}
+
+	// 1. /proc/<pid>/stat: state, groups, CPU times, priority and start time
+	data, err := os.ReadFile(paths.Proc(strconv.Itoa(int(pid)), "stat"))
+	if err != nil {
+		return p, fmt.Errorf("error reading process PID %d: %w", pid, err)
+	}
+
+	// The name (2nd field) may contain spaces, so the fields start after its closing parenthesis
+	stat := string(data)
+	end := strings.LastIndex(stat, ")")
+	if start := strings.Index(stat, "("); start >= 0 && end > start {
+		p.Name = stat[start+1 : end]
+	}
+	fields := strings.Fields(stat[end+1:])
+	if end < 0 || len(fields) < 20 {
+		return p, fmt.Errorf("error reading process PID %d: unexpected stat format", pid)
+	}
+	p.State = fields[0]
+	p.PPID = parseInt32(fields[1])
+	p.PGID = parseInt32(fields[2])
+	p.SID = parseInt32(fields[3])
+	p.CPUTime = (parseFloat(fields[11]) + parseFloat(fields[12])) / ClockTicks
+	p.Nice = parseInt32(fields[16])
+	p.NumThreads = parseInt32(fields[17])
+	p.StartTime = parseFloat(fields[19]) / ClockTicks
+	if len(fields) > 36 {
+		if core, err := strconv.Atoi(fields[36]); err == nil {
+			p.LastCPU = core
+		}
+	}
+
+	// 2. /proc/<pid>/status: owner and resident memory (kernel threads have no VmRSS)
+	err = eachLine(paths.Proc(strconv.Itoa(int(pid)), "status"), func(line string) {
+		key, value, _ := strings.Cut(line, ":")
+		values := strings.Fields(value)
+		if len(values) == 0 {
+			return
+		}
+		switch key {
+		case "Name":
+			p.Name = strings.TrimSpace(value)
+		case "Uid":
+			p.UID = parseInt32(values[0])
+		case "VmRSS":
+			kb, _ := strconv.ParseUint(values[0], 10, 64)
+			p.RSS = kb * 1024
+		}
+	})
+	if err != nil {
+		return p, fmt.Errorf("error reading status of process PID %d: %w", pid, err)
+	}
+
+	return p, nil
+}
+
+// parseInt32 parses a numeric field (0 if invalid)
+func parseInt32(field string) int32 {
+	value, err := strconv.ParseInt(field, 10, 32)
+	if err != nil {
+		return 0
+	}
+	return int32(value)
+}
+
+// parseFloat parses a numeric field (0 if invalid)
+func parseFloat(field string) float64 {
+	value, err := strconv.ParseFloat(field, 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
@@ -0,0 +1,168 @@
+package prometheus
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/dfialho05/GoMonitor/application/pck/alerts"
+)
+
+// Grafana dashboard model (only the fields GoMonitor sets)
+type dashboard struct {
+	UID           string     `json:"uid"`
+	Title         string     `json:"title"`
+	Tags          []string   `json:"tags"`
+	SchemaVersion int        `json:"schemaVersion"`
+	Refresh       string     `json:"refresh"`
+	Time          timeRange  `json:"time"`
+	Templating    templating `json:"templating"`
+	Panels        []panel    `json:"panels"`
+}
+
+type timeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type templating struct {
+	List []variable `json:"list"`
+}
+
+type variable struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+	Type  string `json:"type"`
+	Query string `json:"query"`
+}
+
+type datasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+type panel struct {
+	ID          int         `json:"id"`
+	Type        string      `json:"type"`
+	Title       string      `json:"title"`
+	Datasource  datasource  `json:"datasource"`
+	GridPos     gridPos     `json:"gridPos"`
+	Targets     []target    `json:"targets"`
+	FieldConfig fieldConfig `json:"fieldConfig"`
+}
+
+type gridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type target struct {
+	RefID        string     `json:"refId"`
+	Datasource   datasource `json:"datasource"`
+	Expr         string     `json:"expr"`
+	LegendFormat string     `json:"legendFormat"`
+}
+
+type fieldConfig struct {
+	Defaults fieldDefaults `json:"defaults"`
+}
+
+type fieldDefaults struct {
+	Unit       string         `json:"unit"`
+	Min        *float64       `json:"min,omitempty"`
+	Max        *float64       `json:"max,omitempty"`
+	Thresholds thresholds     `json:"thresholds"`
+	Custom     map[string]any `json:"custom"`
+}
+
+type thresholds struct {
+	Mode  string          `json:"mode"`
+	Steps []thresholdStep `json:"steps"`
+}
+
+type thresholdStep struct {
+	Color string   `json:"color"`
+	Value *float64 `json:"value"` // nil is the base step
+}
+
+// promDatasource refers to the Prometheus data source chosen in the dashboard variable
+var promDatasource = datasource{Type: "prometheus", UID: "${datasource}"}
+
+// WriteDashboard writes a Grafana dashboard (JSON) with a panel for each exported alert rule
+// The alert thresholds are drawn as lines, so the panels show how close each host is to alerting
+//
+// Parameters:
+//   - w: where the dashboard is written
+//   - rules: GoMonitor alert rules (from alerts.ConfiguredRules)
+//
+// Returns: error if encoding or writing fails
+func WriteDashboard(w io.Writer, rules []alerts.Rule) error {
+	board := dashboard{
+		UID:           "gomonitor",
+		Title:         "GoMonitor",
+		Tags:          []string{"gomonitor", "node-exporter"},
+		SchemaVersion: 39,
+		Refresh:       "30s",
+		Time:          timeRange{From: "now-6h", To: "now"},
+		Templating: templating{List: []variable{
+			{Name: "datasource", Label: "Data source", Type: "datasource", Query: "prometheus"},
+		}},
+	}
+
+	for _, rule := range rules {
+		query, ok := queries[rule.Metric]
+		if !ok {
+			continue
+		}
+
+		// One threshold line per distinct threshold (the general one and per-target ones)
+		levels := []float64{rule.Threshold}
+		for _, threshold := range rule.Thresholds {
+			levels = append(levels, threshold)
+		}
+		sort.Float64s(levels)
+		steps := []thresholdStep{{Color: "green"}}
+		for i, level := range levels {
+			if i > 0 && level == levels[i-1] {
+				continue
+			}
+			value := level
+			steps = append(steps, thresholdStep{Color: "red", Value: &value})
+		}
+
+		defaults := fieldDefaults{
+			Unit:       query.unit,
+			Thresholds: thresholds{Mode: "absolute", Steps: steps},
+			Custom:     map[string]any{"thresholdsStyle": map[string]string{"mode": "line"}},
+		}
+		if query.unit == "percent" {
+			low, high := 0.0, 100.0
+			defaults.Min, defaults.Max = &low, &high
+		}
+
+		// Two panels per row, each 12 columns wide and 8 rows high
+		n := len(board.Panels)
+		board.Panels = append(board.Panels, panel{
+			ID:         n + 1,
+			Type:       "timeseries",
+			Title:      fmt.Sprintf("%s (%s)", query.title, rule.Name),
+			Datasource: promDatasource,
+			GridPos:    gridPos{H: 8, W: 12, X: (n % 2) * 12, Y: (n / 2) * 8},
+			Targets: []target{{
+				RefID:        "A",
+				Datasource:   promDatasource,
+				Expr:         query.selector(""),
+				LegendFormat: query.legend,
+			}},
+			FieldConfig: fieldConfig{Defaults: defaults},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(board)
+}
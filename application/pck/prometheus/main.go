@@ -0,0 +1,251 @@
+package prometheus
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/dfialho05/GoMonitor/application/pck/alerts"
+)
+
+// Filesystems left out of the disk rules, like the virtual filesystems gom -d skips
+const virtualFilesystems = `fstype!~"tmpfs|devtmpfs|overlay|squashfs|ramfs"`
+
+// metricQuery describes how a GoMonitor metric is expressed in PromQL
+// The metric names are those of node_exporter (CPU, RAM, disk) and NVIDIA's
+// dcgm-exporter (GPU), the usual exporters of a central Prometheus stack
+type metricQuery struct {
+	expr        string                     // PromQL expression with a %s placeholder for label matchers
+	label       string                     // Label that identifies a target (empty for global metrics)
+	labelValue  func(target string) string // Converts a GoMonitor target to the label value
+	unit        string                     // Grafana unit of the values
+	title       string                     // Dashboard panel title
+	legend      string                     // Dashboard legend format
+	description string                     // Alert description ({{ $value }} is the measured value)
+}
+
+// queries maps every metric that has an exporter equivalent to its query
+// Process respawns and renames are tracked by GoMonitor itself and have none
+var queries = map[alerts.Metric]metricQuery{
+	alerts.MetricCPU: {
+		expr:        `100 * (1 - avg by (instance) (rate(node_cpu_seconds_total{mode="idle"%s}[2m])))`,
+		unit:        "percent",
+		title:       "CPU usage",
+		legend:      "{{instance}}",
+		description: "CPU usage on {{ $labels.instance }} is {{ $value | humanize }}%",
+	},
+	alerts.MetricRAM: {
+		expr:        `100 * (1 - node_memory_MemAvailable_bytes{%s} / node_memory_MemTotal_bytes)`,
+		unit:        "percent",
+		title:       "RAM usage",
+		legend:      "{{instance}}",
+		description: "RAM usage on {{ $labels.instance }} is {{ $value | humanize }}%",
+	},
+	alerts.MetricDisk: {
+		expr:        `100 * (1 - node_filesystem_avail_bytes{` + virtualFilesystems + `%s} / node_filesystem_size_bytes)`,
+		label:       "mountpoint",
+		labelValue:  func(target string) string { return target },
+		unit:        "percent",
+		title:       "Disk usage",
+		legend:      "{{instance}} {{mountpoint}}",
+		description: "{{ $labels.mountpoint }} on {{ $labels.instance }} is {{ $value | humanize }}% full",
+	},
+	alerts.MetricInodes: {
+		expr:        `100 * (1 - node_filesystem_files_free{` + virtualFilesystems + `%s} / node_filesystem_files)`,
+		label:       "mountpoint",
+		labelValue:  func(target string) string { return target },
+		unit:        "percent",
+		title:       "Inode usage",
+		legend:      "{{instance}} {{mountpoint}}",
+		description: "{{ $labels.mountpoint }} on {{ $labels.instance }} has used {{ $value | humanize }}% of its inodes",
+	},
+	alerts.MetricGPUTemp: {
+		expr:        `DCGM_FI_DEV_GPU_TEMP{%s}`,
+		label:       "gpu",
+		labelValue:  gpuIndex,
+		unit:        "celsius",
+		title:       "GPU temperature",
+		legend:      "{{instance}} GPU {{gpu}}",
+		description: "GPU {{ $labels.gpu }} on {{ $labels.instance }} is at {{ $value }}°C",
+	},
+	alerts.MetricGPUVRAM: {
+		expr:        `100 * DCGM_FI_DEV_FB_USED{%s} / (DCGM_FI_DEV_FB_USED + DCGM_FI_DEV_FB_FREE)`,
+		label:       "gpu",
+		labelValue:  gpuIndex,
+		unit:        "percent",
+		title:       "GPU memory usage",
+		legend:      "{{instance}} GPU {{gpu}}",
+		description: "GPU {{ $labels.gpu }} memory on {{ $labels.instance }} is {{ $value | humanize }}% used",
+	},
+	alerts.MetricGPUUtil: {
+		expr:        `DCGM_FI_DEV_GPU_UTIL{%s}`,
+		label:       "gpu",
+		labelValue:  gpuIndex,
+		unit:        "percent",
+		title:       "GPU utilization",
+		legend:      "{{instance}} GPU {{gpu}}",
+		description: "GPU {{ $labels.gpu }} on {{ $labels.instance }} is {{ $value }}% busy",
+	},
+}
+
+// selector returns the query with the label matchers filled in
+// An empty matcher list is dropped (e.g. "DCGM_FI_DEV_GPU_TEMP{}" → "DCGM_FI_DEV_GPU_TEMP")
+func (q metricQuery) selector(matchers string) string {
+	return strings.ReplaceAll(fmt.Sprintf(q.expr, matchers), "{}", "")
+}
+
+// gpuIndex converts a GoMonitor GPU target ("GPU 0") to the dcgm-exporter gpu label ("0")
+func gpuIndex(target string) string {
+	return strings.TrimPrefix(target, "GPU ")
+}
+
+// promRule is a single Prometheus alerting rule
+type promRule struct {
+	alert       string        // Alert name (e.g. "HighCPUUsage")
+	expr        string        // PromQL condition
+	duration    time.Duration // "for" clause (0 fires immediately)
+	summary     string        // GoMonitor rule name
+	description string        // Alert description template
+}
+
+// convertRule converts a GoMonitor rule into Prometheus rules
+// Each per-target threshold becomes its own rule, and those targets are excluded from the
+// rule with the general threshold so an alert never fires twice
+//
+// Parameters:
+//   - rule: GoMonitor alert rule
+//
+// Returns:
+//   - slice of Prometheus rules
+//   - false if the metric has no exporter equivalent
+func convertRule(rule alerts.Rule) ([]promRule, bool) {
+	query, ok := queries[rule.Metric]
+	if !ok {
+		return nil, false
+	}
+
+	newRule := func(matchers string, threshold float64) promRule {
+		return promRule{
+			alert:       alertName(rule.Name),
+			expr:        fmt.Sprintf("%s >= %g", query.selector(matchers), threshold),
+			duration:    rule.Duration,
+			summary:     rule.Name,
+			description: fmt.Sprintf("%s (threshold: %g%s)", query.description, threshold, rule.Metric.Unit()),
+		}
+	}
+
+	// Targets with their own threshold, in a stable order
+	var targets []string
+	if query.label != "" {
+		for target := range rule.Thresholds {
+			targets = append(targets, target)
+		}
+		sort.Strings(targets)
+	}
+
+	var rules []promRule
+	var excluded []string
+	for _, target := range targets {
+		value := query.labelValue(target)
+		excluded = append(excluded, regexp.QuoteMeta(value))
+		rules = append(rules, newRule(matcher(query, fmt.Sprintf(`%s=%q`, query.label, value)), rule.Thresholds[target]))
+	}
+
+	general := ""
+	if len(excluded) > 0 {
+		general = matcher(query, fmt.Sprintf(`%s!~%q`, query.label, strings.Join(excluded, "|")))
+	}
+	return append([]promRule{newRule(general, rule.Threshold)}, rules...), true
+}
+
+// matcher formats a label matcher for the query's placeholder
+// Queries that already have matchers (e.g. the disk fstype) need a comma before the new one
+func matcher(query metricQuery, label string) string {
+	if strings.Contains(query.expr, "{%s}") {
+		return label
+	}
+	return "," + label
+}
+
+// alertName converts a rule name to a Prometheus alert name
+// e.g. "High CPU usage" → "HighCPUUsage"
+func alertName(name string) string {
+	var builder strings.Builder
+	for _, word := range strings.Fields(name) {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		for _, r := range runes {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				builder.WriteRune(r)
+			}
+		}
+	}
+	return builder.String()
+}
+
+// formatDuration formats a duration the way Prometheus writes it (e.g. "2m", "1m30s")
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	var parts []string
+	if hours := int(d.Hours()); hours > 0 {
+		parts = append(parts, fmt.Sprintf("%dh", hours))
+	}
+	if minutes := int(d.Minutes()) % 60; minutes > 0 {
+		parts = append(parts, fmt.Sprintf("%dm", minutes))
+	}
+	if seconds := int(d.Seconds()) % 60; seconds > 0 {
+		parts = append(parts, fmt.Sprintf("%ds", seconds))
+	}
+	return strings.Join(parts, "")
+}
+
+// WriteRules writes the alert rules as a Prometheus rule file (YAML)
+// Rules without an exporter equivalent (process respawns and renames) are listed as comments
+//
+// Parameters:
+//   - w: where the rule file is written
+//   - rules: GoMonitor alert rules (from alerts.ConfiguredRules)
+//
+// Returns: error if writing fails
+func WriteRules(w io.Writer, rules []alerts.Rule) error {
+	var b strings.Builder
+	b.WriteString("# Prometheus alerting rules generated by GoMonitor (gom prometheus rules)\n")
+	b.WriteString("# Metrics come from node_exporter and NVIDIA dcgm-exporter\n")
+
+	var skipped []string
+	var converted []promRule
+	for _, rule := range rules {
+		promRules, ok := convertRule(rule)
+		if !ok {
+			skipped = append(skipped, rule.Name)
+			continue
+		}
+		converted = append(converted, promRules...)
+	}
+	for _, name := range skipped {
+		fmt.Fprintf(&b, "# Not exported (tracked by GoMonitor only): %s\n", name)
+	}
+
+	b.WriteString("groups:\n")
+	b.WriteString("  - name: gomonitor\n")
+	b.WriteString("    rules:\n")
+	for _, rule := range converted {
+		fmt.Fprintf(&b, "      - alert: %s\n", rule.alert)
+		fmt.Fprintf(&b, "        expr: %q\n", rule.expr)
+		if rule.duration > 0 {
+			fmt.Fprintf(&b, "        for: %s\n", formatDuration(rule.duration))
+		}
+		b.WriteString("        labels:\n")
+		b.WriteString("          severity: warning\n")
+		b.WriteString("        annotations:\n")
+		fmt.Fprintf(&b, "          summary: %q\n", rule.summary)
+		fmt.Fprintf(&b, "          description: %q\n", rule.description)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -2,8 +2,11 @@ package ram
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/dfialho05/GoMonitor/application/pck/cgroup"
 	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/procfs"
 	"github.com/shirou/gopsutil/v3/mem"
 )
 
@@ -15,28 +18,112 @@ type RamGeneral struct {
 	Free      uint64  // Free/available RAM (in bytes)
 	Available uint64  // Available memory for new processes (in bytes, includes reusable cache)
 	Percent   float64 // Memory usage percentage (0-100%)
+
+	// Breakdown of the memory that isn't Used (Linux): Used + Buffers + Cached + Free = Total
+	Buffers        uint64 // Block device cache (in bytes)
+	Cached         uint64 // Page cache and reclaimable kernel slab, given back when programs need it (in bytes)
+	Shared         uint64 // Shared memory and tmpfs, part of Cached but not reclaimable (in bytes)
+	Slab           uint64 // Kernel data structures, reclaimable or not (in bytes)
+	Dirty          uint64 // File data waiting to be written to disk (in bytes)
+	HugePagesTotal uint64 // Huge pages reserved, counted in Used (a count; 0 if none)
+	HugePagesFree  uint64 // Reserved huge pages not in use (a count)
+	HugePageSize   uint64 // Size of a huge page (in bytes)
+
+	// Inside a container the figures above are relative to the cgroup memory limit
+	HostTotal uint64 // Total RAM of the host (0 if the figures are the host's)
+}
+
+// Limited checks if the figures are relative to a cgroup memory limit instead of the host RAM
+func (stats RamGeneral) Limited() bool {
+	return stats.HostTotal > 0
 }
 
 // GetRamGeneral collects general information about system RAM
 // This function provides global memory usage statistics
+// Inside a container with a memory limit they are relative to the limit (see cgroup.Effective),
+// since that, not the host RAM, is when the OOM killer acts
 //
 // Returns:
 //   - RamGeneral filled with memory statistics
 //   - error if unable to get the information
 func GetRamGeneral() (RamGeneral, error) {
+	stats, err := getHostRamGeneral()
+	if err != nil {
+		return stats, err
+	}
+	if limits, ok := cgroup.Effective(); ok && limits.HasMemoryLimit() {
+		stats = stats.limitedTo(limits)
+	}
+	return stats, nil
+}
+
+// limitedTo returns the memory figures relative to a cgroup memory limit
+// The breakdown of the host caches doesn't apply to the cgroup, so it is left out
+//
+// Parameters:
+//   - limits: limits of GoMonitor's cgroup (with a memory limit)
+//
+// Returns: RamGeneral with the usage of the cgroup against its limit
+func (stats RamGeneral) limitedTo(limits cgroup.Limits) RamGeneral {
+	used := min(limits.MemoryUsage, limits.MemoryLimit)
+	free := limits.MemoryLimit - used
+	return RamGeneral{
+		Total:     limits.MemoryLimit,
+		Used:      used,
+		Free:      free,
+		Available: min(free, stats.Available),
+		Percent:   float64(used) / float64(limits.MemoryLimit) * 100,
+		HostTotal: stats.Total,
+	}
+}
+
+// getHostRamGeneral collects the memory statistics of the whole host
+func getHostRamGeneral() (RamGeneral, error) {
+	// The procfs backend reads /proc/meminfo directly
+	if common.UsingProcfs() {
+		info, err := procfs.ReadMeminfo()
+		if err != nil {
+			return RamGeneral{}, fmt.Errorf("error getting memory information: %w", err)
+		}
+		return RamGeneral{
+			Total:          info.Total,
+			Used:           info.Used(),
+			Free:           info.Free,
+			Available:      info.Available,
+			Percent:        float64(info.Used()) / float64(info.Total) * 100,
+			Buffers:        info.Buffers,
+			Cached:         info.Cached + info.SReclaimable,
+			Shared:         info.Shared,
+			Slab:           info.Slab,
+			Dirty:          info.Dirty,
+			HugePagesTotal: info.HugePages,
+			HugePagesFree:  info.HugePagesFree,
+			HugePageSize:   info.HugePageSize,
+		}, nil
+	}
+
 	// Get virtual memory (RAM) statistics
 	vm, err := mem.VirtualMemory()
 	if err != nil {
 		return RamGeneral{}, fmt.Errorf("error getting memory information: %w", err)
 	}
 
-	// Fill the structure with the obtained data
+	// Fill the structure with the obtained data (gopsutil's Cached already includes
+	// the reclaimable slab, like free)
 	return RamGeneral{
-		Total:     vm.Total,
-		Used:      vm.Used,
-		Free:      vm.Free,
-		Available: vm.Available,
-		Percent:   vm.UsedPercent,
+		Total:          vm.Total,
+		Used:           vm.Used,
+		Free:           vm.Free,
+		Available:      vm.Available,
+		Percent:        vm.UsedPercent,
+		Buffers:        vm.Buffers,
+		Cached:         vm.Cached,
+		Shared:         vm.Shared,
+		Slab:           vm.Slab,
+		Dirty:          vm.Dirty,
+		HugePagesTotal: vm.HugePagesTotal,
+		HugePagesFree:  vm.HugePagesFree,
+		HugePageSize:   vm.HugePageSize,
 	}, nil
 }
 
@@ -72,7 +159,7 @@ func GetProcessStatsByRAM() ([]common.ProcessInfo, error) {
 //   - error if the process doesn't exist or is not accessible
 func GetRAMUsageByPID(pid int32) (float32, uint64, error) {
 	// Get total system memory
-	totalMem, err := common.GetSystemMemoryTotal()
+	totalMem, err := common.GetProcessMemoryTotal()
 	if err != nil {
 		return 0, 0, err
 	}
@@ -98,37 +185,73 @@ func GetRAMUsageByPID(pid int32) (float32, uint64, error) {
 // Parameters:
 //   - stats: RamGeneral structure with data to present
 func PrintGeneralStats(stats RamGeneral) {
-	fmt.Printf("\n╔══════════════════════════════════════════════════════════════════════════════════╗\n")
-	fmt.Printf("║  %-80s  ║\n", "General RAM Memory Information")
-	fmt.Printf("╠══════════════════════════════════════════════════════════════════════════════════╣\n")
-	fmt.Printf("║  Total:           %-62s  ║\n", common.FormatBytes(stats.Total))
-	fmt.Printf("║  Used:            %-62s  ║\n", common.FormatBytes(stats.Used))
-	fmt.Printf("║  Free:            %-62s  ║\n", common.FormatBytes(stats.Free))
-	fmt.Printf("║  Available:       %-62s  ║\n", common.FormatBytes(stats.Available))
-	fmt.Printf("║  Usage:           %-58.2f %%    ║\n", stats.Percent)
-	fmt.Printf("╚══════════════════════════════════════════════════════════════════════════════════╝\n")
+	box := common.NewBox("General RAM Memory Information")
+	if stats.Limited() {
+		box.Field("Total", common.FormatBytes(stats.Total)+" (cgroup limit, --host for the host RAM)")
+		box.Field("Host RAM", common.FormatBytes(stats.HostTotal))
+	} else {
+		box.Field("Total", common.FormatBytes(stats.Total))
+	}
+	box.Field("Used", common.FormatBytes(stats.Used))
+	box.Field("Free", common.FormatBytes(stats.Free))
+	box.Field("Available", common.FormatBytes(stats.Available))
+	box.Fieldf("Usage", "%.2f %%", stats.Percent)
+
+	// Stacked breakdown, since Used alone hides the caches the kernel gives back on demand
+	if stats.Buffers+stats.Cached > 0 {
+		box.Separator()
+		box.Field("Breakdown", stats.BreakdownBar(common.NarrowWidth(58)))
+		box.Field("", fmt.Sprintf("█ used %s  ▓ buffers %s", common.FormatBytes(stats.Used), common.FormatBytes(stats.Buffers)))
+		box.Field("", fmt.Sprintf("▒ cached %s  · free %s", common.FormatBytes(stats.Cached), common.FormatBytes(stats.Free)))
+		box.Field("Shared", common.FormatBytes(stats.Shared)+" (tmpfs and shared memory, in cached)")
+		box.Field("Slab", common.FormatBytes(stats.Slab)+" (kernel)")
+		box.Field("Dirty", common.FormatBytes(stats.Dirty)+" (waiting to be written to disk)")
+	}
+	if stats.HugePagesTotal > 0 {
+		box.Fieldf("Huge Pages", "%d × %s = %s reserved (in used), %d free",
+			stats.HugePagesTotal, common.FormatBytes(stats.HugePageSize),
+			common.FormatBytes(stats.HugePagesTotal*stats.HugePageSize), stats.HugePagesFree)
+	}
+	box.End()
+}
+
+// BreakdownBar draws used, buffers, cached and free memory as one bar
+// Each part takes cells proportional to its share of the total; the rounding goes to free
+//
+// Parameters:
+//   - width: width of the bar without the brackets
+func (stats RamGeneral) BreakdownBar(width int) string {
+	if stats.Total == 0 {
+		return "[" + strings.Repeat("·", width) + "]"
+	}
+
+	var bar strings.Builder
+	used := 0
+	for _, part := range []struct {
+		bytes  uint64
+		symbol string
+	}{{stats.Used, "█"}, {stats.Buffers, "▓"}, {stats.Cached, "▒"}} {
+		cells := min(int(float64(part.bytes)/float64(stats.Total)*float64(width)+0.5), width-used)
+		bar.WriteString(strings.Repeat(part.symbol, cells))
+		used += cells
+	}
+	bar.WriteString(strings.Repeat("·", width-used))
+	return "[" + bar.String() + "]"
 }
 
 // PrintTopProcessesByRAM prints the N processes with highest RAM usage
 // This function provides a formatted view of processes that consume the most memory
 //
 // Parameters:
+//   - processes: every process (from common.CollectAllProcessInfo), shared with the other views
 //   - n: number of processes to show (top N)
-//
-// Returns:
-//   - error if unable to get the data
-func PrintTopProcessesByRAM(n int) error {
-	// Get processes sorted by RAM
-	processes, err := GetProcessStatsByRAM()
-	if err != nil {
-		return err
-	}
+func PrintTopProcessesByRAM(processes []common.ProcessInfo, n int) {
+	// Get the N processes with highest RAM usage
+	processes = common.TopKProcesses(processes, n, "ram")
 
 	// Use the common function to print the table
 	title := fmt.Sprintf("Top %d Processes by RAM Usage", n)
 	common.PrintProcessTable(processes, n, title)
-
-	return nil
 }
 
 // GetSwapMemory gets information about system swap memory
@@ -140,6 +263,19 @@ func PrintTopProcessesByRAM(n int) error {
 //   - percent: swap usage percentage
 //   - error if unable to get the information
 func GetSwapMemory() (uint64, uint64, float64, error) {
+	if common.UsingProcfs() {
+		info, err := procfs.ReadMeminfo()
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("error getting swap information: %w", err)
+		}
+		used := info.SwapTotal - min(info.SwapFree, info.SwapTotal)
+		percent := 0.0
+		if info.SwapTotal > 0 {
+			percent = float64(used) / float64(info.SwapTotal) * 100
+		}
+		return info.SwapTotal, used, percent, nil
+	}
+
 	swapMem, err := mem.SwapMemory()
 	if err != nil {
 		return 0, 0, 0, fmt.Errorf("error getting swap information: %w", err)
@@ -158,14 +294,12 @@ func PrintSwapStats() error {
 
 	free := total - used
 
-	fmt.Printf("\n╔══════════════════════════════════════════════════════════════════════════════════╗\n")
-	fmt.Printf("║  %-80s  ║\n", "Swap Memory Information")
-	fmt.Printf("╠══════════════════════════════════════════════════════════════════════════════════╣\n")
-	fmt.Printf("║  Total:           %-62s  ║\n", common.FormatBytes(total))
-	fmt.Printf("║  Used:            %-62s  ║\n", common.FormatBytes(used))
-	fmt.Printf("║  Free:            %-62s  ║\n", common.FormatBytes(free))
-	fmt.Printf("║  Usage:           %-58.2f %%    ║\n", percent)
-	fmt.Printf("╚══════════════════════════════════════════════════════════════════════════════════╝\n")
+	box := common.NewBox("Swap Memory Information")
+	box.Field("Total", common.FormatBytes(total))
+	box.Field("Used", common.FormatBytes(used))
+	box.Field("Free", common.FormatBytes(free))
+	box.Fieldf("Usage", "%.2f %%", percent)
+	box.End()
 
 	return nil
 }
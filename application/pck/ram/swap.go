@@ -0,0 +1,122 @@
+package ram
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+)
+
+// ProcessSwap contains how much of a process's memory was swapped out
+type ProcessSwap struct {
+	Process   common.ProcessInfo // Process information
+	SwapBytes uint64             // Memory of the process currently in swap (in bytes)
+}
+
+// GetProcessSwap gets the swapped out memory of a process
+// Reads the VmSwap field of /proc/<pid>/status (kernel threads have no such field)
+//
+// Parameters:
+//   - pid: process ID
+//
+// Returns:
+//   - swap used by the process in bytes (0 for kernel threads)
+//   - error if the process can't be read
+func GetProcessSwap(pid int32) (uint64, error) {
+	file, err := os.Open(paths.Proc(fmt.Sprint(pid), "status"))
+	if err != nil {
+		return 0, fmt.Errorf("error reading process %d status: %w", pid, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		value, ok := strings.CutPrefix(scanner.Text(), "VmSwap:")
+		if !ok {
+			continue
+		}
+		// Format: "VmSwap:     1234 kB"
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			break
+		}
+		kb, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("error parsing VmSwap of process %d: %w", pid, err)
+		}
+		return kb * 1024, nil
+	}
+	return 0, nil
+}
+
+// GetTopProcessesBySwap finds the processes with the most memory in swap
+// Processes without swapped out memory are left out
+//
+// Parameters:
+//   - n: maximum number of processes to return
+//
+// Returns:
+//   - slice of ProcessSwap sorted by swap usage (descending)
+//   - error if the processes can't be collected
+func GetTopProcessesBySwap(n int) ([]ProcessSwap, error) {
+	processes, err := common.CollectAllProcessInfo()
+	if err != nil {
+		return nil, fmt.Errorf("error collecting processes: %w", err)
+	}
+
+	var swapped []ProcessSwap
+	for _, p := range processes {
+		if swap, err := GetProcessSwap(p.PID); err == nil && swap > 0 {
+			swapped = append(swapped, ProcessSwap{Process: p, SwapBytes: swap})
+		}
+	}
+
+	sort.Slice(swapped, func(i, j int) bool {
+		return swapped[i].SwapBytes > swapped[j].SwapBytes
+	})
+	if n > 0 && len(swapped) > n {
+		swapped = swapped[:n]
+	}
+
+	return swapped, nil
+}
+
+// PrintTopProcessesBySwap prints the N processes with the most memory in swap
+// Shows which processes were pushed out of RAM (and slow down when they touch that memory again)
+//
+// Parameters:
+//   - n: number of processes to show (top N)
+//
+// Returns:
+//   - error if unable to get the data
+func PrintTopProcessesBySwap(n int) error {
+	swapped, err := GetTopProcessesBySwap(n)
+	if err != nil {
+		return err
+	}
+
+	table := common.NewTable(fmt.Sprintf("Top %d Processes by Swap Usage", n),
+		common.Column{Header: "PID"}, common.Column{Header: "Name", Fill: true},
+		common.Column{Header: "User", Optional: true}, common.Column{Header: "Swap", Right: true},
+		common.Column{Header: "RAM", Right: true, Optional: true})
+
+	if len(swapped) == 0 {
+		table.AddNote("No process has memory in swap")
+	}
+
+	for _, s := range swapped {
+		table.AddRow(strconv.Itoa(int(s.Process.PID)),
+			common.TruncateString(s.Process.Name, 28),
+			common.TruncateString(s.Process.Username, 12),
+			common.FormatBytes(s.SwapBytes),
+			fmt.Sprintf("%.2f%%", s.Process.RAMPercentage))
+	}
+
+	table.Print()
+	return nil
+}
@@ -0,0 +1,171 @@
+package ram
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/disk"
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+// WritebackStats contains dirty page and writeback information correlated with disk activity
+// Large amounts of dirty memory being flushed to a slow disk is the classic cause
+// of the "system freezes during large copies" pattern, which shows up as high I/O wait
+type WritebackStats struct {
+	Dirty             uint64                   // Memory waiting to be written back to disk (in bytes)
+	Writeback         uint64                   // Memory actively being written back to disk (in bytes)
+	DirtiedPerSec     float64                  // Rate at which pages are being dirtied (bytes per second)
+	WrittenPerSec     float64                  // Rate at which pages are being written back (bytes per second)
+	IOWaitPercent     float64                  // Share of CPU time spent waiting for I/O (0-100%)
+	DeviceUtilization []disk.DeviceUtilization // Busy percentage and throughput per physical disk
+}
+
+// GetWritebackStats samples dirty/writeback memory, I/O wait and disk utilization
+// Two snapshots are taken, separated by the given interval, to compute rates
+//
+// Parameters:
+//   - interval: time between both samples (1 second is a good default)
+//
+// Returns:
+//   - WritebackStats filled with the sampled information
+//   - error if unable to get the information
+func GetWritebackStats(interval time.Duration) (WritebackStats, error) {
+	// 1. Take the first snapshot of all counters
+	vmstatBefore, err := readVMStat()
+	if err != nil {
+		return WritebackStats{}, err
+	}
+
+	timesBefore, err := cpu.Times(false)
+	if err != nil {
+		return WritebackStats{}, fmt.Errorf("error getting CPU times: %w", err)
+	}
+
+	ioBefore, err := disk.GetIOCounters()
+	if err != nil {
+		return WritebackStats{}, err
+	}
+
+	// 2. Wait for the interval and take the second snapshot
+	start := time.Now()
+	time.Sleep(interval)
+
+	vmstatAfter, err := readVMStat()
+	if err != nil {
+		return WritebackStats{}, err
+	}
+
+	timesAfter, err := cpu.Times(false)
+	if err != nil {
+		return WritebackStats{}, fmt.Errorf("error getting CPU times: %w", err)
+	}
+
+	ioAfter, err := disk.GetIOCounters()
+	if err != nil {
+		return WritebackStats{}, err
+	}
+
+	elapsed := time.Since(start)
+
+	// 3. Current dirty/writeback volumes come from meminfo
+	vm, err := mem.VirtualMemory()
+	if err != nil {
+		return WritebackStats{}, fmt.Errorf("error getting memory information: %w", err)
+	}
+
+	// 4. Compute rates from the cumulative vmstat page counters
+	pageSize := float64(os.Getpagesize())
+	seconds := elapsed.Seconds()
+
+	stats := WritebackStats{
+		Dirty:             vm.Dirty,
+		Writeback:         vm.WriteBack,
+		DirtiedPerSec:     float64(vmstatAfter["nr_dirtied"]-vmstatBefore["nr_dirtied"]) * pageSize / seconds,
+		WrittenPerSec:     float64(vmstatAfter["nr_written"]-vmstatBefore["nr_written"]) * pageSize / seconds,
+		DeviceUtilization: disk.CalculateUtilization(ioBefore, ioAfter, elapsed),
+	}
+
+	// 5. Compute I/O wait percentage from the CPU time deltas
+	if len(timesBefore) > 0 && len(timesAfter) > 0 {
+		total := timesAfter[0].Total() - timesBefore[0].Total()
+		if total > 0 {
+			stats.IOWaitPercent = (timesAfter[0].Iowait - timesBefore[0].Iowait) / total * 100
+		}
+	}
+
+	return stats, nil
+}
+
+// readVMStat reads the cumulative kernel memory counters from /proc/vmstat
+//
+// Returns:
+//   - map with counter name as key and its value
+//   - error if the file cannot be read
+func readVMStat() (map[string]uint64, error) {
+	file, err := os.Open(paths.Proc("vmstat"))
+	if err != nil {
+		return nil, fmt.Errorf("error reading /proc/vmstat: %w", err)
+	}
+	defer file.Close()
+
+	counters := make(map[string]uint64)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		counters[fields[0]] = value
+	}
+
+	return counters, nil
+}
+
+// PrintWritebackStats prints dirty page, writeback and disk utilization information
+// A hint is shown when a large dirty backlog coincides with high I/O wait
+//
+// Parameters:
+//   - stats: WritebackStats structure with data to present
+func PrintWritebackStats(stats WritebackStats) {
+	box := common.NewBox("Dirty Pages & Writeback")
+	box.Field("Dirty", common.FormatBytes(stats.Dirty))
+	box.Field("Writeback", common.FormatBytes(stats.Writeback))
+	box.Field("Dirtied Rate", common.FormatBytes(uint64(stats.DirtiedPerSec))+"/s")
+	box.Field("Written Rate", common.FormatBytes(uint64(stats.WrittenPerSec))+"/s")
+	box.Fieldf("I/O Wait", "%.2f %%", stats.IOWaitPercent)
+	box.End()
+
+	table := common.NewTable("Disk Utilization",
+		common.Column{Header: "Device", Fill: true}, common.Column{Header: "Busy %", Right: true},
+		common.Column{Header: "Read", Right: true}, common.Column{Header: "Write", Right: true})
+
+	if len(stats.DeviceUtilization) == 0 {
+		table.AddNote("No physical disks found")
+	}
+
+	for _, device := range stats.DeviceUtilization {
+		table.AddRow(device.Name,
+			fmt.Sprintf("%.2f%%", device.Percent),
+			common.FormatBytes(uint64(device.ReadBytesPerSec))+"/s",
+			common.FormatBytes(uint64(device.WriteBytesPerSec))+"/s")
+	}
+
+	table.Print()
+
+	// Flag the typical freeze pattern: lots of dirty data, CPUs stuck waiting on I/O
+	if stats.Dirty+stats.Writeback >= 256*1024*1024 && stats.IOWaitPercent >= 10 {
+		fmt.Println("\n⚠ Large dirty backlog with high I/O wait: the system is likely stalling on writeback")
+	}
+}
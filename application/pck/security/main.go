@@ -0,0 +1,239 @@
+package security
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/shirou/gopsutil/v3/host"
+)
+
+// SSHSession represents an active remote login session
+type SSHSession struct {
+	User     string        // Logged in user
+	Terminal string        // Terminal of the session (e.g. "pts/0")
+	SourceIP string        // Remote host or IP the session comes from
+	Started  time.Time     // When the session started
+	Duration time.Duration // How long the session has been active
+}
+
+// FailedLoginStats contains failed login attempts over a recent time window
+type FailedLoginStats struct {
+	Window    time.Duration  // Time window the attempts were counted in
+	Total     int            // Total number of failed attempts
+	BySource  map[string]int // Failed attempts per source IP
+	Source    string         // Where the data was read from (e.g. "journald", "/var/log/auth.log")
+	Available bool           // False if no log could be read (usually a permission problem)
+}
+
+// FailedLoginWindow is the time window used to count failed login attempts
+const FailedLoginWindow = 24 * time.Hour
+
+// authLogPaths are the syslog files that contain sshd messages (Debian/Ubuntu and RHEL/Fedora)
+var authLogPaths = []string{"/var/log/auth.log", "/var/log/secure"}
+
+// failedLoginPattern matches sshd failed login messages and captures the source IP
+// Only the "Failed <method> for" line is matched: sshd also logs "Invalid user" and PAM
+// "authentication failure" lines for the same attempt, which would count it up to three times
+var failedLoginPattern = regexp.MustCompile(`Failed (?:password|publickey|none|keyboard-interactive/pam) for (?:invalid user )?\S+ from ([0-9a-fA-F.:]+)`)
+
+// GetSSHSessions gets all active remote login sessions
+// Sessions are read from utmp; a session is remote when it has a source host
+//
+// Returns:
+//   - slice of SSHSession sorted by start time (oldest first)
+//   - error if unable to read the sessions
+func GetSSHSessions() ([]SSHSession, error) {
+	users, err := host.Users()
+	if errors.Is(err, fs.ErrNotExist) {
+		// No utmp file (e.g. minimal containers): nobody is logged in
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting logged in users: %w", err)
+	}
+
+	now := time.Now()
+	var sessions []SSHSession
+	for _, u := range users {
+		// Local sessions (console, local X display) have no host or a display name as host
+		if u.Host == "" || strings.HasPrefix(u.Host, ":") {
+			continue
+		}
+
+		started := time.Unix(int64(u.Started), 0)
+		sessions = append(sessions, SSHSession{
+			User:     u.User,
+			Terminal: u.Terminal,
+			SourceIP: u.Host,
+			Started:  started,
+			Duration: now.Sub(started),
+		})
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].Started.Before(sessions[j].Started)
+	})
+
+	return sessions, nil
+}
+
+// GetFailedLogins counts failed login attempts over the last FailedLoginWindow
+// Tries journald first and falls back to the syslog auth files
+// Both usually require root or membership in the adm/systemd-journal groups
+//
+// Returns:
+//   - FailedLoginStats (Available is false if no source could be read)
+func GetFailedLogins() FailedLoginStats {
+	stats := FailedLoginStats{
+		Window:   FailedLoginWindow,
+		BySource: make(map[string]int),
+	}
+
+	// 1. journald
+	since := fmt.Sprintf("-%dh", int(FailedLoginWindow.Hours()))
+	// OpenSSH 9.8 and later log the attempts from the sshd-session process
+	output, err := common.RunCommand(context.Background(), "journalctl", "--since", since, "--no-pager", "-q", "-o", "cat",
+		"_COMM=sshd", "+", "SYSLOG_IDENTIFIER=sshd", "+", "_COMM=sshd-session", "+", "SYSLOG_IDENTIFIER=sshd-session")
+	if err == nil && len(output) > 0 {
+		stats.Source = "journald"
+		stats.Available = true
+		for _, line := range strings.Split(string(output), "\n") {
+			countFailedLogin(&stats, line)
+		}
+		return stats
+	}
+
+	// 2. Syslog auth files
+	cutoff := time.Now().Add(-FailedLoginWindow)
+	for _, path := range authLogPaths {
+		file, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+
+		stats.Source = path
+		stats.Available = true
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if timestamp, ok := parseSyslogTime(line); ok && timestamp.Before(cutoff) {
+				continue
+			}
+			countFailedLogin(&stats, line)
+		}
+		file.Close()
+		break
+	}
+
+	return stats
+}
+
+// countFailedLogin adds a log line to the statistics if it is a failed login message
+func countFailedLogin(stats *FailedLoginStats, line string) {
+	match := failedLoginPattern.FindStringSubmatch(line)
+	if match == nil {
+		return
+	}
+	stats.Total++
+	stats.BySource[match[1]]++
+}
+
+// parseSyslogTime parses the timestamp at the start of a syslog line
+// Supports the classic format ("Jan  2 15:04:05", current year assumed) and RFC3339
+//
+// Returns:
+//   - parsed timestamp
+//   - false if the line doesn't start with a known timestamp
+func parseSyslogTime(line string) (time.Time, bool) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return time.Time{}, false
+	}
+
+	if timestamp, err := time.Parse(time.RFC3339Nano, fields[0]); err == nil {
+		return timestamp, true
+	}
+
+	if len(fields) >= 3 {
+		value := strings.Join(fields[:3], " ")
+		if timestamp, err := time.ParseInLocation("Jan 2 15:04:05", value, time.Local); err == nil {
+			now := time.Now()
+			timestamp = timestamp.AddDate(now.Year(), 0, 0)
+			// Lines from December read in January belong to the previous year
+			if timestamp.After(now) {
+				timestamp = timestamp.AddDate(-1, 0, 0)
+			}
+			return timestamp, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// PrintSSHSessions prints the active remote login sessions in a formatted table
+//
+// Parameters:
+//   - sessions: slice of SSHSession to present
+func PrintSSHSessions(sessions []SSHSession) {
+	table := common.NewTable("Active SSH Sessions",
+		common.Column{Header: "User"}, common.Column{Header: "Terminal", Short: "TTY", Optional: true},
+		common.Column{Header: "Source", Fill: true}, common.Column{Header: "Duration"})
+
+	if len(sessions) == 0 {
+		table.AddNote("No active remote sessions")
+	}
+
+	for _, session := range sessions {
+		table.AddRow(common.TruncateString(session.User, 14),
+			common.TruncateString(session.Terminal, 10),
+			common.TruncateString(session.SourceIP, 28),
+			common.FormatDuration(session.Duration))
+	}
+
+	table.Print()
+}
+
+// PrintFailedLogins prints failed login counts and the top offending source IPs
+//
+// Parameters:
+//   - stats: FailedLoginStats to present
+func PrintFailedLogins(stats FailedLoginStats) {
+	title := fmt.Sprintf("Failed Logins (last %.0fh)", stats.Window.Hours())
+	box := common.NewBox(title)
+
+	if !stats.Available {
+		box.Text("N/A (no access to journald or auth logs, try running as root)")
+		box.End()
+		return
+	}
+
+	box.Field("Source", stats.Source)
+	box.Fieldf("Total", "%d", stats.Total)
+
+	// Show the top 5 source IPs
+	sources := make([]string, 0, len(stats.BySource))
+	for source := range stats.BySource {
+		sources = append(sources, source)
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		return stats.BySource[sources[i]] > stats.BySource[sources[j]]
+	})
+	if len(sources) > 5 {
+		sources = sources[:5]
+	}
+
+	for _, source := range sources {
+		box.Text(fmt.Sprintf("  %-40s %d", source, stats.BySource[source]))
+	}
+
+	box.End()
+}
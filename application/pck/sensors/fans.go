@@ -0,0 +1,273 @@
+package sensors
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// Fan control limits
+const (
+	MinFanLevel   = 30 // Lowest level a fan can be set to (%), so no fan is ever stopped by hand
+	hotMargin     = 5  // A temperature this close to its critical threshold (°C) counts as overheating
+	hotFallback   = 90 // Overheating temperature of sensors without thresholds (°C)
+	pwmMax        = 255
+	pwmModeManual = 1 // pwmN_enable value for manual control
+	pwmModeAuto   = 2 // pwmN_enable value for automatic control by the chip (used if the original mode is unknown)
+)
+
+// ErrOverheating is returned when a fan level can't be lowered because a sensor is too hot
+var ErrOverheating = errors.New("a sensor is overheating")
+
+// Fan is a fan of a hwmon chip, with its PWM control if the chip has one
+type Fan struct {
+	Chip     string  // Chip name (e.g. "nct6775")
+	Label    string  // Fan label (e.g. "CPU Fan", "fan1")
+	RPM      float64 // Current speed (0 if stopped)
+	Level    int     // PWM duty cycle in % (-1 if the fan has no PWM control)
+	Mode     int     // Control mode (pwmN_enable): 0 full speed, 1 manual, 2 or more automatic (-1 if not available)
+	Writable bool    // The level and mode can be changed (needs root and a driver that allows it)
+	pwmPath  string  // sysfs path of pwmN (empty if the fan has no PWM control)
+}
+
+// ID identifies the fan across readings (its PWM control, or else its chip and label)
+func (f Fan) ID() string {
+	if f.pwmPath != "" {
+		return f.pwmPath
+	}
+	return f.Chip + "/" + f.Label
+}
+
+// Manual checks if the fan level is set by hand instead of by the chip
+func (f Fan) Manual() bool {
+	return f.Mode == pwmModeManual
+}
+
+// ModeName describes the control mode (e.g. "auto")
+func (f Fan) ModeName() string {
+	switch {
+	case f.Mode < 0:
+		return "fixed"
+	case f.Mode == 0:
+		return "full speed"
+	case f.Mode == pwmModeManual:
+		return "manual"
+	default:
+		return "auto"
+	}
+}
+
+// GetFans reads every fan of the hwmon chips, with the level of those that have a PWM control
+// Chips number their fans and PWM outputs the same way (fan2_input goes with pwm2)
+//
+// Returns:
+//   - slice of Fan sorted by chip and fan number
+//   - error if the hwmon directory can't be read
+func GetFans() ([]Fan, error) {
+	chipPaths, err := filepath.Glob(filepath.Join(hwmonPath(), "hwmon*"))
+	if err != nil {
+		return nil, fmt.Errorf("error listing hwmon chips: %w", err)
+	}
+	sort.Strings(chipPaths)
+
+	var fans []Fan
+	for _, chipPath := range chipPaths {
+		chipName := readString(filepath.Join(chipPath, "name"))
+		if chipName == "" {
+			chipName = filepath.Base(chipPath)
+		}
+
+		inputs, err := filepath.Glob(filepath.Join(chipPath, "fan[0-9]*_input"))
+		if err != nil {
+			continue
+		}
+		sort.Slice(inputs, func(i, j int) bool { return sensorNumber(inputs[i]) < sensorNumber(inputs[j]) })
+
+		for _, input := range inputs {
+			rpm, err := readInt(input)
+			if err != nil {
+				continue
+			}
+			base := strings.TrimSuffix(input, "_input")
+			fan := Fan{Chip: chipName, Label: readString(base + "_label"), RPM: float64(rpm), Level: -1, Mode: -1}
+			if fan.Label == "" {
+				fan.Label = filepath.Base(base)
+			}
+
+			pwmPath := filepath.Join(chipPath, "pwm"+strconv.Itoa(sensorNumber(input)))
+			if value, err := readInt(pwmPath); err == nil {
+				fan.pwmPath = pwmPath
+				fan.Level = (value*100 + pwmMax/2) / pwmMax
+				if mode, err := readInt(pwmPath + "_enable"); err == nil {
+					fan.Mode = mode
+				}
+				fan.Writable = syscall.Access(pwmPath, 2) == nil && syscall.Access(pwmPath+"_enable", 2) == nil
+			}
+			fans = append(fans, fan)
+		}
+	}
+	return fans, nil
+}
+
+// sensorNumber returns the number of a sensor file (e.g. 2 for "fan2_input"; 0 if there is none)
+func sensorNumber(path string) int {
+	name := strings.TrimLeftFunc(filepath.Base(path), func(r rune) bool { return r < '0' || r > '9' })
+	end := strings.IndexFunc(name, func(r rune) bool { return r < '0' || r > '9' })
+	if end >= 0 {
+		name = name[:end]
+	}
+	number, _ := strconv.Atoi(name)
+	return number
+}
+
+// Overheating finds a temperature close to its critical threshold (or its max, or 90 °C
+// for sensors without thresholds), when fans must not be kept slow
+//
+// Parameters:
+//   - chips: chips to check (from GetChips)
+//
+// Returns:
+//   - description of the hottest reading over its limit (e.g. "coretemp Package id 0 at 97 °C")
+//   - false if every temperature is fine
+func Overheating(chips []Chip) (string, bool) {
+	hottest, found := "", false
+	var margin float64
+	for _, chip := range chips {
+		for _, reading := range chip.Readings {
+			if reading.Kind != KindTemperature || reading.Value <= 0 {
+				continue
+			}
+			limit := float64(hotFallback)
+			switch {
+			case reading.Critical > 0:
+				limit = reading.Critical - hotMargin
+			case reading.Max > 0:
+				limit = reading.Max
+			}
+			if over := reading.Value - limit; over >= 0 && (!found || over > margin) {
+				hottest, found, margin = fmt.Sprintf("%s %s at %.0f °C", chip.Name, reading.Label, reading.Value), true, over
+			}
+		}
+	}
+	return hottest, found
+}
+
+// FanController sets fan levels by hand and gives the fans back to the chip afterwards
+// Only fans changed through it are touched, and their original mode is kept so RestoreAll
+// can put everything back when the TUI exits
+type FanController struct {
+	mu       sync.Mutex     // Serializes changes (RestoreAll also runs when any TUI goroutine panics)
+	original map[string]int // Mode of every changed fan before its first change, by ID
+}
+
+// NewFanController creates a controller that hasn't changed any fan yet
+//
+// Returns: pointer to a configured FanController
+func NewFanController() *FanController {
+	return &FanController{original: make(map[string]int)}
+}
+
+// Changed returns the number of fans whose level was set by hand and not restored
+func (c *FanController) Changed() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.original)
+}
+
+// SetLevel switches a fan to manual control at a level
+// Levels under MinFanLevel are refused so a fan is never stopped, and lowering a level is
+// refused while a sensor is overheating
+//
+// Parameters:
+//   - fan: fan to change (from GetFans)
+//   - level: duty cycle in % (MinFanLevel-100)
+//   - chips: current readings, to check the temperatures before lowering the level
+//
+// Returns: error if the fan can't be controlled, the level is out of range or a sensor is too hot
+func (c *FanController) SetLevel(fan Fan, level int, chips []Chip) error {
+	switch {
+	case fan.pwmPath == "" || !fan.Writable:
+		return fmt.Errorf("%s %s can't be controlled (needs root and a PWM output)", fan.Chip, fan.Label)
+	case level < MinFanLevel || level > 100:
+		return fmt.Errorf("fan levels must be between %d%% and 100%%", MinFanLevel)
+	}
+	if hot, ok := Overheating(chips); ok && level < fan.Level {
+		return fmt.Errorf("%w (%s)", ErrOverheating, hot)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// 1. Remember the mode the chip used, so it can be restored
+	if _, ok := c.original[fan.ID()]; !ok {
+		mode := fan.Mode
+		if mode < 0 || mode == pwmModeManual {
+			mode = pwmModeAuto
+		}
+		c.original[fan.ID()] = mode
+	}
+
+	// 2. Take manual control and set the level; go back to the chip if the level can't be set
+	if err := writeInt(fan.pwmPath+"_enable", pwmModeManual); err != nil {
+		return fmt.Errorf("error taking control of %s %s: %w", fan.Chip, fan.Label, err)
+	}
+	if err := writeInt(fan.pwmPath, (level*pwmMax+50)/100); err != nil {
+		c.restore(fan)
+		return fmt.Errorf("error setting the level of %s %s: %w", fan.Chip, fan.Label, err)
+	}
+	return nil
+}
+
+// Restore gives a fan back to the mode the chip used before it was changed
+//
+// Parameters:
+//   - fan: fan to restore (a fan that wasn't changed is left alone)
+//
+// Returns: error if the mode can't be written
+func (c *FanController) Restore(fan Fan) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.restore(fan)
+}
+
+// restore gives a fan back to its original mode (the caller holds c.mu)
+func (c *FanController) restore(fan Fan) error {
+	mode, ok := c.original[fan.ID()]
+	if !ok {
+		return nil
+	}
+	if err := writeInt(fan.pwmPath+"_enable", mode); err != nil {
+		return fmt.Errorf("error restoring automatic control of %s %s: %w", fan.Chip, fan.Label, err)
+	}
+	delete(c.original, fan.ID())
+	return nil
+}
+
+// RestoreAll gives every changed fan back to the mode the chip used before
+//
+// Returns: error for the fans that couldn't be restored (nil if all were)
+func (c *FanController) RestoreAll() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var errs []error
+	for id, mode := range c.original {
+		if err := writeInt(id+"_enable", mode); err != nil {
+			errs = append(errs, fmt.Errorf("error restoring automatic control of %s: %w", id, err))
+			continue
+		}
+		delete(c.original, id)
+	}
+	return errors.Join(errs...)
+}
+
+// writeInt writes an integer value to a sysfs file
+func writeInt(path string, value int) error {
+	return os.WriteFile(path, []byte(strconv.Itoa(value)), 0o644)
+}
@@ -0,0 +1,365 @@
+package sensors
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+)
+
+// Kind identifies the type of a sensor reading
+type Kind int
+
+const (
+	KindTemperature Kind = iota // Temperature in degrees Celsius
+	KindFan                     // Fan speed in RPM
+	KindVoltage                 // Voltage in volts
+)
+
+// Reading is a single sensor value exposed by a hwmon chip
+type Reading struct {
+	Kind     Kind    // Type of reading (temperature, fan, voltage)
+	Label    string  // Sensor label (e.g. "Package id 0", "Composite", "fan1")
+	Value    float64 // Current value (°C, RPM or V depending on Kind)
+	Max      float64 // High/max threshold (0 if not available)
+	Critical float64 // Critical threshold (0 if not available)
+}
+
+// Chip represents a hwmon chip (e.g. coretemp, k10temp, nvme, acpitz, amdgpu)
+type Chip struct {
+	Name     string    // Chip name as reported by the driver
+	Path     string    // sysfs path of the chip (e.g. "/sys/class/hwmon/hwmon2")
+	Readings []Reading // All readings exposed by the chip
+}
+
+// hwmonPath returns the sysfs directory with one entry per hardware monitoring chip
+func hwmonPath() string {
+	return paths.Sys("class", "hwmon")
+}
+
+// thermalPath returns the sysfs directory with the ACPI/platform thermal zones
+func thermalPath() string {
+	return paths.Sys("class", "thermal")
+}
+
+// chipMatch selects the temperature reading of a hwmon chip
+type chipMatch struct {
+	name   string   // Chip name (contents of the hwmon "name" file)
+	labels []string // Preferred labels in order; an empty label matches the first temperature
+}
+
+// cpuChips are hwmon drivers that report CPU temperatures, in order of preference
+// The generic acpitz zone is last since it's only sometimes the CPU
+var cpuChips = []chipMatch{
+	{"coretemp", []string{"Package id 0", ""}}, // Intel
+	{"k10temp", []string{"Tctl", "Tdie", ""}},  // AMD
+	{"zenpower", []string{"Tdie", "Tctl", ""}}, // AMD (out-of-tree driver)
+	{"k8temp", []string{""}},                   // Older AMD
+	{"cpu_thermal", []string{""}},              // Raspberry Pi and other ARM boards
+	{"soc_thermal", []string{""}},              // Other ARM SoCs
+	{"thinkpad", []string{"CPU", ""}},          // ThinkPad embedded controller
+	{"acpitz", []string{""}},                   // ACPI thermal zone
+}
+
+// gpuChips are hwmon drivers that report GPU temperatures, in order of preference
+var gpuChips = []chipMatch{
+	{"amdgpu", []string{"edge", "junction", ""}},
+	{"radeon", []string{""}},
+	{"nouveau", []string{""}},
+}
+
+// GetChips enumerates every hwmon chip and all its temperature, fan and voltage readings
+//
+// Returns:
+//   - slice of Chip sorted by name
+//   - error if the hwmon directory can't be read
+func GetChips() ([]Chip, error) {
+	chipPaths, err := filepath.Glob(filepath.Join(hwmonPath(), "hwmon*"))
+	if err != nil {
+		return nil, fmt.Errorf("error listing hwmon chips: %w", err)
+	}
+
+	chips := make([]Chip, 0, len(chipPaths))
+	for _, chipPath := range chipPaths {
+		chip := Chip{
+			Name: readString(filepath.Join(chipPath, "name")),
+			Path: chipPath,
+		}
+		if chip.Name == "" {
+			chip.Name = filepath.Base(chipPath)
+		}
+
+		chip.Readings = append(chip.Readings, readSensors(chipPath, "temp", KindTemperature)...)
+		chip.Readings = append(chip.Readings, readSensors(chipPath, "fan", KindFan)...)
+		chip.Readings = append(chip.Readings, readSensors(chipPath, "in", KindVoltage)...)
+
+		if len(chip.Readings) > 0 {
+			chips = append(chips, chip)
+		}
+	}
+
+	sort.Slice(chips, func(i, j int) bool {
+		return chips[i].Name < chips[j].Name
+	})
+
+	return chips, nil
+}
+
+// readSensors reads every <prefix>N_input file of a chip
+//
+// Parameters:
+//   - chipPath: sysfs path of the chip
+//   - prefix: sensor file prefix ("temp", "fan" or "in")
+//   - kind: kind of reading, used to convert the raw values
+//
+// Returns: slice of readings sorted by sensor number
+func readSensors(chipPath, prefix string, kind Kind) []Reading {
+	inputs, err := filepath.Glob(filepath.Join(chipPath, prefix+"[0-9]*_input"))
+	if err != nil {
+		return nil
+	}
+	sort.Slice(inputs, func(i, j int) bool { return sensorNumber(inputs[i]) < sensorNumber(inputs[j]) })
+
+	var readings []Reading
+	for _, input := range inputs {
+		base := strings.TrimSuffix(input, "_input")
+
+		raw, err := readInt(input)
+		if err != nil {
+			continue
+		}
+
+		label := readString(base + "_label")
+		if label == "" {
+			label = filepath.Base(base)
+		}
+
+		reading := Reading{
+			Kind:  kind,
+			Label: label,
+			Value: convertRaw(raw, kind),
+		}
+		if value, err := readInt(base + "_max"); err == nil {
+			reading.Max = convertRaw(value, kind)
+		}
+		if value, err := readInt(base + "_crit"); err == nil {
+			reading.Critical = convertRaw(value, kind)
+		}
+
+		readings = append(readings, reading)
+	}
+
+	return readings
+}
+
+// convertRaw converts a raw hwmon value to its unit
+// Temperatures are in millidegrees Celsius, voltages in millivolts and fans in RPM
+func convertRaw(raw int, kind Kind) float64 {
+	switch kind {
+	case KindTemperature, KindVoltage:
+		return float64(raw) / 1000
+	default:
+		return float64(raw)
+	}
+}
+
+// CPUTemperature gets the CPU package temperature from the hwmon chips
+//
+// Returns:
+//   - temperature in degrees Celsius (0 if not available)
+func CPUTemperature() int {
+	return findTemperature(cpuChips)
+}
+
+// GPUTemperature gets the temperature of a GPU exposed through hwmon (AMD, nouveau)
+//
+// Returns:
+//   - temperature in degrees Celsius (0 if not available)
+func GPUTemperature() int {
+	return findTemperature(gpuChips)
+}
+
+// findTemperature searches the hwmon chips for the first matching temperature reading
+// Only the names of the chips are read, then the temperatures of the matching ones,
+// since this runs on every refresh
+//
+// Parameters:
+//   - matches: chips and labels to look for, in order of preference
+//
+// Returns:
+//   - temperature in degrees Celsius (0 if not available)
+func findTemperature(matches []chipMatch) int {
+	chipPaths, err := filepath.Glob(filepath.Join(hwmonPath(), "hwmon*"))
+	if err != nil {
+		return 0
+	}
+	sort.Strings(chipPaths)
+
+	names := make([]string, len(chipPaths))
+	for i, chipPath := range chipPaths {
+		names[i] = readString(filepath.Join(chipPath, "name"))
+	}
+
+	for _, match := range matches {
+		for i, chipPath := range chipPaths {
+			if names[i] != match.name {
+				continue
+			}
+
+			readings := readSensors(chipPath, "temp", KindTemperature)
+			for _, label := range match.labels {
+				for _, reading := range readings {
+					if label != "" && reading.Label != label {
+						continue
+					}
+					// Validate if temperature is reasonable (between 0 and 150°C)
+					if reading.Value > 0 && reading.Value < 150 {
+						return int(reading.Value)
+					}
+				}
+			}
+		}
+	}
+
+	return 0
+}
+
+// ThermalZoneTemperature searches the thermal zones for one of the given types
+// Thermal zones are the fallback when no hwmon chip reports the temperature
+//
+// Parameters:
+//   - targetTypes: thermal zone types to look for (e.g. "x86_pkg_temp", "acpitz")
+//
+// Returns:
+//   - temperature in degrees Celsius of the first matching zone (0 if not available)
+func ThermalZoneTemperature(targetTypes []string) int {
+	zonePaths, err := filepath.Glob(filepath.Join(thermalPath(), "thermal_zone*"))
+	if err != nil {
+		return 0
+	}
+	sort.Strings(zonePaths)
+
+	for _, zonePath := range zonePaths {
+		zoneType := readString(filepath.Join(zonePath, "type"))
+
+		// Check if it's one of the types we're looking for
+		isTarget := false
+		for _, targetType := range targetTypes {
+			if strings.Contains(zoneType, targetType) {
+				isTarget = true
+				break
+			}
+		}
+		if !isTarget {
+			continue
+		}
+
+		// Convert from millidegrees Celsius to degrees Celsius
+		tempMilliC, err := readInt(filepath.Join(zonePath, "temp"))
+		if err != nil {
+			continue
+		}
+		temp := tempMilliC / 1000
+
+		// Validate if temperature is reasonable (between 0 and 150°C)
+		if temp > 0 && temp < 150 {
+			return temp
+		}
+	}
+
+	return 0
+}
+
+// ReadThermalZone reads the temperature of a thermal zone by index
+//
+// Parameters:
+//   - index: thermal zone number (e.g. 0 for thermal_zone0)
+//
+// Returns:
+//   - temperature in degrees Celsius (0 if not available)
+func ReadThermalZone(index int) int {
+	tempMilliC, err := readInt(filepath.Join(thermalPath(), fmt.Sprintf("thermal_zone%d", index), "temp"))
+	if err != nil {
+		return 0
+	}
+	return tempMilliC / 1000
+}
+
+// readString reads a trimmed string from a sysfs file (empty if not available)
+func readString(path string) string {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(buf))
+}
+
+// readInt reads a single integer value from a sysfs file
+func readInt(path string) (int, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(buf)))
+}
+
+// PrintChips prints every hwmon chip with its temperatures, fans and voltages
+//
+// Parameters:
+//   - chips: slice of Chip to present
+func PrintChips(chips []Chip) {
+	box := common.NewBox("Hardware Sensors")
+
+	if len(chips) == 0 {
+		box.Text("No hwmon sensors found")
+	}
+
+	for i, chip := range chips {
+		if i > 0 {
+			box.Divider()
+		}
+
+		box.Field("Chip", chip.Name)
+		for _, reading := range chip.Readings {
+			box.Text("  " + common.FitString(common.TruncateString(reading.Label, 23), 24) + FormatReading(reading))
+		}
+	}
+
+	box.End()
+}
+
+// FormatReading formats a reading value with its unit and thresholds
+// e.g. "45.0 °C (max 80.0 °C, crit 100.0 °C)", "1200 RPM", "1.05 V"
+func FormatReading(reading Reading) string {
+	var value string
+	var unitFormat string
+
+	switch reading.Kind {
+	case KindTemperature:
+		unitFormat = "%.1f °C"
+	case KindFan:
+		unitFormat = "%.0f RPM"
+	case KindVoltage:
+		unitFormat = "%.2f V"
+	}
+
+	value = fmt.Sprintf(unitFormat, reading.Value)
+
+	var limits []string
+	if reading.Max > 0 {
+		limits = append(limits, "max "+fmt.Sprintf(unitFormat, reading.Max))
+	}
+	if reading.Critical > 0 {
+		limits = append(limits, "crit "+fmt.Sprintf(unitFormat, reading.Critical))
+	}
+	if len(limits) > 0 {
+		value += " (" + strings.Join(limits, ", ") + ")"
+	}
+
+	return value
+}
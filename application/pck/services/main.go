@@ -0,0 +1,260 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dfialho05/GoMonitor/application/pck/cgroup"
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+)
+
+// Service contains the state of a systemd service and the usage of its processes
+type Service struct {
+	Unit         string  // Unit name (e.g. "nginx.service")
+	Description  string  // Unit description
+	ActiveState  string  // High-level state (active, failed, activating, ...)
+	SubState     string  // Low-level state (running, exited, dead, ...)
+	MainPID      int32   // Main process ID (0 if the service has no running main process)
+	ControlGroup string  // cgroup of the service (e.g. "/system.slice/nginx.service")
+	Processes    int     // Number of processes in the service's cgroup
+	CPUPercent   float64 // CPU usage summed over the processes
+	RAMBytes     uint64  // Resident memory summed over the processes
+	MemoryUsage  uint64  // Working set of the service's cgroup (only read if MemoryLimit is set)
+	MemoryLimit  uint64  // Memory limit in bytes, e.g. MemoryMax= (0 if unlimited)
+	CPULimit     float64 // CPU quota in cores, e.g. CPUQuota= (0 if unlimited)
+}
+
+// showProperties are the unit properties read with "systemctl show"
+var showProperties = []string{"Id", "Description", "ActiveState", "SubState", "MainPID", "ControlGroup"}
+
+// GetServices lists the loaded systemd services that are active or failed
+// Each process is matched to its service by cgroup, so the usage covers the whole
+// process tree (workers, helpers), not just the main PID
+//
+// Returns:
+//   - slice of Service, failed services first and then by CPU usage (descending)
+//   - error if systemctl is not available or fails
+func GetServices() ([]Service, error) {
+	// 1. List the service units (active and failed)
+	units, err := listUnits()
+	if err != nil {
+		return nil, err
+	}
+	if len(units) == 0 {
+		return nil, nil
+	}
+
+	// 2. Read their properties
+	services, err := showUnits(units)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Join the process collection against the services by cgroup
+	byUnit := make(map[string]*Service, len(services))
+	for i := range services {
+		byUnit[services[i].Unit] = &services[i]
+	}
+
+	processes, err := common.CollectAllProcessInfo()
+	if err != nil {
+		return nil, fmt.Errorf("error collecting processes: %w", err)
+	}
+	for _, p := range processes {
+		paths, err := cgroup.Paths(p.PID)
+		if err != nil {
+			continue
+		}
+		service, ok := byUnit[unitOf(paths)]
+		if !ok {
+			continue
+		}
+
+		// Every process of a service shares its cgroup limits, so they are read once
+		if service.Processes == 0 {
+			if limits, err := cgroup.Get(p.PID); err == nil {
+				service.MemoryUsage = limits.MemoryUsage
+				service.MemoryLimit = limits.MemoryLimit
+				service.CPULimit = limits.CPULimit
+			}
+		}
+		service.Processes++
+		service.CPUPercent += p.CPUPercentage
+		service.RAMBytes += p.RAMBytes
+	}
+
+	sort.SliceStable(services, func(i, j int) bool {
+		failedI, failedJ := services[i].ActiveState == "failed", services[j].ActiveState == "failed"
+		if failedI != failedJ {
+			return failedI
+		}
+		return services[i].CPUPercent > services[j].CPUPercent
+	})
+
+	return services, nil
+}
+
+// listUnits gets the names of the loaded service units that are active or failed
+//
+// Returns:
+//   - slice of unit names
+//   - error if systemctl can't be run
+func listUnits() ([]string, error) {
+	output, err := runSystemctl("list-units", "--type=service", "--no-legend", "--no-pager", "--plain")
+	if err != nil {
+		return nil, fmt.Errorf("error running systemctl list-units: %w", err)
+	}
+
+	var units []string
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 0 && strings.HasSuffix(fields[0], ".service") {
+			units = append(units, fields[0])
+		}
+	}
+	return units, nil
+}
+
+// showUnits reads the properties of the units with a single "systemctl show" call
+// The output has one "Key=Value" block per unit, separated by blank lines
+//
+// Parameters:
+//   - units: unit names (from listUnits)
+//
+// Returns:
+//   - slice of Service with the unit properties (usage not filled in yet)
+//   - error if systemctl can't be run
+func showUnits(units []string) ([]Service, error) {
+	args := append([]string{"show", "--no-pager", "--property=" + strings.Join(showProperties, ",")}, units...)
+	output, err := runSystemctl(args...)
+	if err != nil {
+		return nil, fmt.Errorf("error running systemctl show: %w", err)
+	}
+
+	var services []Service
+	var current Service
+	flush := func() {
+		if current.Unit != "" {
+			services = append(services, current)
+		}
+		current = Service{}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Id":
+			current.Unit = value
+		case "Description":
+			current.Description = value
+		case "ActiveState":
+			current.ActiveState = value
+		case "SubState":
+			current.SubState = value
+		case "MainPID":
+			if pid, err := strconv.ParseInt(value, 10, 32); err == nil {
+				current.MainPID = int32(pid)
+			}
+		case "ControlGroup":
+			current.ControlGroup = value
+		}
+	}
+	flush()
+
+	return services, nil
+}
+
+// runSystemctl runs systemctl and returns its output
+// The error includes systemctl's message (e.g. "System has not been booted with systemd")
+func runSystemctl(args ...string) ([]byte, error) {
+	output, err := common.RunCommand(context.Background(), "systemctl", args...)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return output, err
+}
+
+// unitOf finds the service a process belongs to from its cgroup paths
+// Services can have sub-cgroups (e.g. "/system.slice/docker.service/payload"), so the
+// innermost path component ending in ".service" is used
+//
+// Parameters:
+//   - paths: cgroup paths of the process (from cgroup.Paths)
+//
+// Returns: unit name (empty if the process isn't in a service)
+func unitOf(paths map[string]string) string {
+	// cgroup v2 uses the unified hierarchy, v1 the named systemd hierarchy
+	cgroupPath, ok := paths[""]
+	if systemdPath, found := paths["name=systemd"]; found && (!ok || cgroupPath == "/") {
+		cgroupPath = systemdPath
+	}
+
+	for dir := cgroupPath; dir != "/" && dir != "."; dir = path.Dir(dir) {
+		if unit := path.Base(dir); strings.HasSuffix(unit, ".service") {
+			return unit
+		}
+	}
+	return ""
+}
+
+// LimitUsage returns the usage of the service against its CPU quota and memory limit
+func (s Service) LimitUsage() cgroup.LimitUsage {
+	return cgroup.LimitUsage{
+		Name:        strings.TrimSuffix(s.Unit, ".service"),
+		CPUPercent:  s.CPUPercent,
+		CPULimit:    s.CPULimit,
+		MemoryUsage: s.MemoryUsage,
+		MemoryLimit: s.MemoryLimit,
+	}
+}
+
+// PrintServices prints the systemd services in a formatted table
+//
+// Parameters:
+//   - services: slice of Service to present
+func PrintServices(services []Service) {
+	table := common.NewTable("systemd Services",
+		common.Column{Header: "Unit", Fill: true}, common.Column{Header: "State"},
+		common.Column{Header: "PID", Optional: true}, common.Column{Header: "Procs", Right: true, Optional: true},
+		common.Column{Header: "CPU", Right: true}, common.Column{Header: "Memory", Right: true})
+
+	if len(services) == 0 {
+		table.AddNote("No active or failed services found")
+	}
+
+	for _, s := range services {
+		pid := "-"
+		if s.MainPID > 0 {
+			pid = strconv.Itoa(int(s.MainPID))
+		}
+
+		table.AddRow(common.TruncateString(strings.TrimSuffix(s.Unit, ".service"), 22),
+			common.TruncateString(s.ActiveState+"/"+s.SubState, 15),
+			pid,
+			strconv.Itoa(s.Processes),
+			fmt.Sprintf("%.1f%%", s.CPUPercent),
+			common.FormatBytes(s.RAMBytes))
+	}
+
+	table.Print()
+}
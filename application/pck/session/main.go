@@ -0,0 +1,62 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+)
+
+// State is the lock and idle state of the login session GoMonitor runs in
+type State struct {
+	Locked bool // Screen is locked (logind LockedHint, set by the screen locker)
+	Idle   bool // No user input for a while (logind IdleHint, set by the desktop)
+}
+
+// Inactive checks if nobody is looking at the screen (locked or idle)
+func (s State) Inactive() bool {
+	return s.Locked || s.Idle
+}
+
+// String describes the state (e.g. "locked")
+func (s State) String() string {
+	switch {
+	case s.Locked:
+		return "locked"
+	case s.Idle:
+		return "idle"
+	default:
+		return "active"
+	}
+}
+
+// Get reads the state of the caller's session from systemd-logind over D-Bus
+// Uses busctl, like the throttle command, so no D-Bus library is needed; logind
+// resolves the "auto" session from the PID of the caller
+//
+// Returns:
+//   - State of the session
+//   - error if busctl or logind aren't available, or GoMonitor doesn't run in a session
+func Get() (State, error) {
+	output, err := common.RunCommand(context.Background(), "busctl", "get-property",
+		"org.freedesktop.login1", "/org/freedesktop/login1/session/auto",
+		"org.freedesktop.login1.Session", "LockedHint", "IdleHint")
+	if err != nil {
+		message := err.Error()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			message = strings.TrimSpace(string(exitErr.Stderr))
+		}
+		return State{}, fmt.Errorf("error reading session state: %s", message)
+	}
+
+	// One "b true" or "b false" line per property
+	fields := strings.Fields(string(output))
+	if len(fields) != 4 || fields[0] != "b" || fields[2] != "b" {
+		return State{}, fmt.Errorf("unexpected busctl output: %q", strings.TrimSpace(string(output)))
+	}
+	return State{Locked: fields[1] == "true", Idle: fields[3] == "true"}, nil
+}
@@ -0,0 +1,76 @@
+package snapshot
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+)
+
+// PrintDiff prints two snapshots side by side: the key metrics with the difference
+// of the second one, then the top processes of each
+//
+// Parameters:
+//   - a: first snapshot (e.g. the healthy node)
+//   - b: second snapshot (e.g. the misbehaving node)
+func PrintDiff(a, b Snapshot) {
+	table := common.NewTable(fmt.Sprintf("Comparison: %s vs %s", a.Host, b.Host),
+		common.Column{Header: "", Fill: true},
+		common.Column{Header: common.TruncateString(a.Host, 21), Right: true},
+		common.Column{Header: common.TruncateString(b.Host, 21), Right: true},
+		common.Column{Header: "Difference", Short: "Diff", Right: true, Optional: true})
+
+	row := func(label, first, second, difference string) {
+		table.AddRow(label, first, second, difference)
+	}
+	row("Taken", common.FormatTimestamp(a.Time), common.FormatTimestamp(b.Time), formatDelta(b.Time.Sub(a.Time).Seconds(), "%+.0fs"))
+	row("Uptime", uptime(a), uptime(b), "")
+	row("CPU usage", fmt.Sprintf("%.1f%%", a.CPUPercent), fmt.Sprintf("%.1f%%", b.CPUPercent), formatDelta(b.CPUPercent-a.CPUPercent, "%+.1f pt"))
+	row("Load (1 min)", fmt.Sprintf("%.2f / %d CPU", a.Load1, a.Cores), fmt.Sprintf("%.2f / %d CPU", b.Load1, b.Cores), formatDelta(b.Load1-a.Load1, "%+.2f"))
+	row("RAM", usage(a.RAMUsed, a.RAMPercent), usage(b.RAMUsed, b.RAMPercent), formatDelta(b.RAMPercent-a.RAMPercent, "%+.1f pt"))
+	row("Disk", usage(a.DiskUsed, a.DiskPercent()), usage(b.DiskUsed, b.DiskPercent()), formatDelta(b.DiskPercent()-a.DiskPercent(), "%+.1f pt"))
+	row("Processes", fmt.Sprint(a.Processes), fmt.Sprint(b.Processes), formatDelta(float64(b.Processes-a.Processes), "%+.0f"))
+	table.Print()
+
+	// Top processes, side by side, splitting the width of the output between the two
+	left := (common.OutputWidth() - 7) / 2
+	right := common.OutputWidth() - 7 - left
+	top := common.NewTable("Top Processes",
+		common.Column{Header: "Top processes of " + common.TruncateString(a.Host, 21), Short: a.Host},
+		common.Column{Header: "Top processes of " + common.TruncateString(b.Host, 22), Short: b.Host, Fill: true})
+	for i := range max(len(a.Top), len(b.Top)) {
+		top.AddRow(topEntry(a.Top, i, left), topEntry(b.Top, i, right))
+	}
+	top.Print()
+}
+
+// formatDelta formats a difference, or nothing when there is none
+func formatDelta(delta float64, format string) string {
+	if fmt.Sprintf(format, delta) == fmt.Sprintf(format, 0.0) {
+		return ""
+	}
+	return fmt.Sprintf(format, delta)
+}
+
+// uptime formats the uptime of a snapshot ("-" if not available)
+func uptime(s Snapshot) string {
+	if s.UptimeSeconds <= 0 {
+		return "-"
+	}
+	return common.FormatDuration(time.Duration(s.UptimeSeconds) * time.Second)
+}
+
+// usage formats a used amount with its percentage (e.g. "3.20 GB (41.0%)")
+func usage(used uint64, percent float64) string {
+	return fmt.Sprintf("%s (%.1f%%)", common.FormatBytes(used), percent)
+}
+
+// topEntry formats the i-th top process as "name  CPU%  RAM" to width characters (empty past the end)
+func topEntry(top []Process, i, width int) string {
+	if i >= len(top) {
+		return ""
+	}
+	p := top[i]
+	values := fmt.Sprintf(" %6.1f%% %10s", p.CPUPercent, common.FormatBytes(p.RAMBytes))
+	return fmt.Sprintf("%s%s", common.FitString(p.Name, width-len(values)), values)
+}
@@ -0,0 +1,198 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/container"
+	"github.com/dfialho05/GoMonitor/application/pck/cpu"
+	"github.com/dfialho05/GoMonitor/application/pck/disk"
+	"github.com/dfialho05/GoMonitor/application/pck/ram"
+)
+
+// LocalHost is the host name of diff --remote that is collected on this machine instead of over SSH
+const LocalHost = "local"
+
+// sshTimeout is how long ssh may take to connect before the host is reported as unreachable
+const sshTimeout = 10 * time.Second
+
+// Snapshot is a summary of a machine at one point in time
+// Written as JSON by "gom snapshot", so it can be saved or collected from other hosts over SSH
+type Snapshot struct {
+	Host          string    `json:"host"`             // Host name of the machine
+	Time          time.Time `json:"time"`             // When the snapshot was taken
+	UptimeSeconds int64     `json:"uptime_seconds"`   // Time since the machine booted
+	Cores         int       `json:"cores"`            // Logical CPUs
+	CPUPercent    float64   `json:"cpu_percent"`      // Global CPU usage (0-100%)
+	Load1         float64   `json:"load1"`            // Load average over the last minute (0 if not available)
+	RAMUsed       uint64    `json:"ram_used_bytes"`   // RAM in use
+	RAMTotal      uint64    `json:"ram_total_bytes"`  // RAM installed
+	RAMPercent    float64   `json:"ram_percent"`      // RAM usage (0-100%)
+	DiskUsed      uint64    `json:"disk_used_bytes"`  // Space used on all listed disks
+	DiskTotal     uint64    `json:"disk_total_bytes"` // Size of all listed disks
+	Processes     int       `json:"processes"`        // Processes that could be read
+	Top           []Process `json:"top"`              // Processes using the most CPU
+}
+
+// Process is a process of a snapshot's top list
+type Process struct {
+	PID        int32      `json:"pid"`
+	Name       string     `json:"name"`
+	User       string     `json:"user"`
+	CPUPercent float64    `json:"cpu_percent"`         // 100% = one core
+	RAMBytes   uint64     `json:"ram_bytes"`           // Resident memory
+	Container  *Container `json:"container,omitempty"` // Container the process runs in (nil on the host)
+}
+
+// Container is the container of a snapshot process, with what its engine reported
+// (image, pod and compose labels), so the usage maps to what was deployed
+type Container struct {
+	ID string `json:"id"` // Full container ID
+	container.Metadata
+}
+
+// DiskPercent returns the usage of all listed disks (0-100%)
+func (s Snapshot) DiskPercent() float64 {
+	if s.DiskTotal == 0 {
+		return 0
+	}
+	return float64(s.DiskUsed) / float64(s.DiskTotal) * 100
+}
+
+// Take collects a snapshot of this machine
+// CPU usage is measured while the processes are sampled (about half a second)
+//
+// Parameters:
+//   - n: number of top processes to keep
+//   - disks: disks counted in the disk usage
+//
+// Returns:
+//   - the Snapshot
+//   - error if the processes or the memory can't be read
+func Take(n int, disks disk.Filter) (Snapshot, error) {
+	snap := Snapshot{Time: time.Now(), Cores: runtime.NumCPU()}
+	snap.Host, _ = os.Hostname()
+
+	// 1. Processes, with the global CPU usage measured over the same interval
+	cpu.GetSystemPercent()
+	processes, err := common.CollectAllProcessInfo()
+	if err != nil {
+		return snap, fmt.Errorf("error collecting processes: %w", err)
+	}
+	snap.CPUPercent, _ = cpu.GetSystemPercent()
+	snap.Processes = len(processes)
+	top := common.TopKProcesses(processes, n, "cpu")
+	resolver := container.NewResolver()
+	resolver.Resolve(top)
+	for _, p := range top {
+		process := Process{PID: p.PID, Name: p.Name, User: p.Username, CPUPercent: p.CPUPercentage, RAMBytes: p.RAMBytes}
+		if id, metadata, ok := resolver.Container(p.PID); ok {
+			process.Container = &Container{ID: id, Metadata: metadata}
+		}
+		snap.Top = append(snap.Top, process)
+	}
+
+	// 2. Memory
+	memory, err := ram.GetRamGeneral()
+	if err != nil {
+		return snap, err
+	}
+	snap.RAMUsed, snap.RAMTotal, snap.RAMPercent = memory.Used, memory.Total, memory.Percent
+
+	// 3. Load, uptime and disks (optional)
+	if load, err := cpu.GetLoadStats(); err == nil {
+		snap.Load1 = load.Load1
+		snap.UptimeSeconds = int64(load.Uptime.Seconds())
+	}
+	snap.DiskTotal, snap.DiskUsed, _, _ = disk.GetTotalStorageStats(disks)
+
+	return snap, nil
+}
+
+// Read reads a snapshot saved with "gom snapshot > file.json"
+//
+// Returns:
+//   - the Snapshot
+//   - error if the file can't be read or isn't a snapshot
+func Read(path string) (Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("error reading snapshot: %w", err)
+	}
+	return decode(path, data)
+}
+
+// Fetch collects a snapshot of another machine by running "gom snapshot" on it over SSH
+// SSH runs in batch mode, so hosts need key-based authentication (no password prompts)
+// The command is quoted for the remote shell, so it is run as one program (no arguments or shell syntax)
+//
+// Parameters:
+//   - host: SSH destination (e.g. "web1" or "admin@10.0.0.5"), or LocalHost for this machine
+//   - command: gom program on the remote host (e.g. "gom" or "/usr/local/bin/gomonitor")
+//   - n: number of top processes to keep
+//   - disks: disks counted in the disk usage of LocalHost (remote hosts use their own settings)
+//
+// Returns:
+//   - the Snapshot
+//   - error if the host is invalid, can't be reached or its output isn't a snapshot
+func Fetch(host, command string, n int, disks disk.Filter) (Snapshot, error) {
+	if host == LocalHost {
+		return Take(n, disks)
+	}
+
+	// A host starting with "-" would be read as an ssh option (e.g. -oProxyCommand=...)
+	if host == "" || strings.HasPrefix(host, "-") {
+		return Snapshot{}, fmt.Errorf("invalid SSH host '%s'", host)
+	}
+
+	remote := shellQuote(command) + " snapshot --top " + strconv.Itoa(n)
+	cmd := exec.Command("ssh", "-o", "BatchMode=yes", "-o", fmt.Sprintf("ConnectTimeout=%d", int(sshTimeout.Seconds())), "--", host, remote)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	started := time.Now()
+	output, err := cmd.Output()
+	common.LogCommand(cmd, started, err)
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return Snapshot{}, fmt.Errorf("error running '%s' on %s: %s", remote, host, message)
+		}
+		return Snapshot{}, fmt.Errorf("error running '%s' on %s: %w", remote, host, err)
+	}
+	return decode(host, output)
+}
+
+// shellQuote quotes a word for a POSIX shell, so the remote shell runs it as is
+func shellQuote(word string) string {
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
+
+// decode parses the JSON of a snapshot
+func decode(source string, data []byte) (Snapshot, error) {
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return Snapshot{}, fmt.Errorf("error parsing snapshot of %s: %w", source, err)
+	}
+	if snap.Time.IsZero() {
+		return Snapshot{}, fmt.Errorf("%s is not a gom snapshot", source)
+	}
+	return snap, nil
+}
+
+// Write writes a snapshot as indented JSON followed by a newline
+func (s Snapshot) Write(w io.Writer) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding snapshot: %w", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
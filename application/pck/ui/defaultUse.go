@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/user"
@@ -8,44 +10,41 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dfialho05/GoMonitor/application/pck/alerts"
+	"github.com/dfialho05/GoMonitor/application/pck/capability"
+	"github.com/dfialho05/GoMonitor/application/pck/cgroup"
+	"github.com/dfialho05/GoMonitor/application/pck/common"
 	"github.com/dfialho05/GoMonitor/application/pck/cpu"
 	"github.com/dfialho05/GoMonitor/application/pck/disk"
 	"github.com/dfialho05/GoMonitor/application/pck/gpu"
+	"github.com/dfialho05/GoMonitor/application/pck/health"
+	"github.com/dfialho05/GoMonitor/application/pck/hwcache"
+	"github.com/dfialho05/GoMonitor/application/pck/network"
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
 	"github.com/dfialho05/GoMonitor/application/pck/ram"
-
 	"golang.org/x/term"
 )
 
-// ANSI color constants
-const (
-	colorReset   = "\033[0m"
-	colorRed     = "\033[31m"
-	colorGreen   = "\033[32m"
-	colorYellow  = "\033[33m"
-	colorBlue    = "\033[34m"
-	colorMagenta = "\033[35m"
-	colorCyan    = "\033[36m"
-	colorWhite   = "\033[37m"
-	colorBold    = "\033[1m"
-)
-
 // GOM Horizontal logo
 // IMPORTANT: All visual lines must have the same length for alignment to work.
 // The box has a visual width of 42 characters.
-var logoLines = []string{
-	"",
-	colorCyan + colorBold + "  ╔════════════════════════════════════════╗" + colorReset,
-	colorCyan + colorBold + "  ║                                        ║" + colorReset,
-	colorCyan + colorBold + "  ║  " + colorGreen + " ██████╗  ██████╗ ███╗   ███╗ " + colorReset + colorCyan + colorBold + "       ║" + colorReset,
-	colorCyan + colorBold + "  ║  " + colorGreen + "██╔════╝ ██╔═══██╗████╗ ████║ " + colorReset + colorCyan + colorBold + "       ║" + colorReset,
-	colorCyan + colorBold + "  ║  " + colorGreen + "██║  ███╗██║   ██║██╔████╔██║ " + colorReset + colorCyan + colorBold + "       ║" + colorReset,
-	colorCyan + colorBold + "  ║  " + colorGreen + "██║   ██║██║   ██║██║╚██╔╝██║ " + colorReset + colorCyan + colorBold + "       ║" + colorReset,
-	colorCyan + colorBold + "  ║  " + colorGreen + "╚██████╔╝╚██████╔╝██║ ╚═╝ ██║ " + colorReset + colorCyan + colorBold + "       ║" + colorReset,
-	colorCyan + colorBold + "  ║  " + colorGreen + " ╚═════╝  ╚═════╝ ╚═╝     ╚═╝ " + colorReset + colorCyan + colorBold + "       ║" + colorReset,
-	colorCyan + colorBold + "  ║                                        ║" + colorReset,
-	colorCyan + colorBold + "  ║                                        ║" + colorReset,
-	colorCyan + colorBold + "  ╚════════════════════════════════════════╝" + colorReset,
-	"",
+// Built on each call so it uses the colors of the active theme
+func logoLines() []string {
+	return []string{
+		"",
+		cyanColor + boldColor + "  ╔════════════════════════════════════════╗" + resetColor,
+		cyanColor + boldColor + "  ║                                        ║" + resetColor,
+		cyanColor + boldColor + "  ║  " + greenColor + " ██████╗  ██████╗ ███╗   ███╗ " + resetColor + cyanColor + boldColor + "       ║" + resetColor,
+		cyanColor + boldColor + "  ║  " + greenColor + "██╔════╝ ██╔═══██╗████╗ ████║ " + resetColor + cyanColor + boldColor + "       ║" + resetColor,
+		cyanColor + boldColor + "  ║  " + greenColor + "██║  ███╗██║   ██║██╔████╔██║ " + resetColor + cyanColor + boldColor + "       ║" + resetColor,
+		cyanColor + boldColor + "  ║  " + greenColor + "██║   ██║██║   ██║██║╚██╔╝██║ " + resetColor + cyanColor + boldColor + "       ║" + resetColor,
+		cyanColor + boldColor + "  ║  " + greenColor + "╚██████╔╝╚██████╔╝██║ ╚═╝ ██║ " + resetColor + cyanColor + boldColor + "       ║" + resetColor,
+		cyanColor + boldColor + "  ║  " + greenColor + " ╚═════╝  ╚═════╝ ╚═╝     ╚═╝ " + resetColor + cyanColor + boldColor + "       ║" + resetColor,
+		cyanColor + boldColor + "  ║                                        ║" + resetColor,
+		cyanColor + boldColor + "  ║                                        ║" + resetColor,
+		cyanColor + boldColor + "  ╚════════════════════════════════════════╝" + resetColor,
+		"",
+	}
 }
 
 // System data structure
@@ -68,36 +67,78 @@ type SystemInfo struct {
 	DiskPercent float64
 	GPUModel    string
 	GPUTemp     int
+	Network     string // Interface of the default route with its link (empty if there's none)
+
+	// Usage against the cgroup memory limit (empty if not limited)
+	RAMLimit string
+
+	// Overall health of the system (nil if not computed)
+	Health *health.Score
+
+	// Metrics whose collection is not implemented on this OS
+	CPUUnsupported  bool
+	RAMUnsupported  bool
+	DiskUnsupported bool
 }
 
+// infoColumnWidth is the room the system information needs next to the logo
+const infoColumnWidth = 62
+
 // PrintDefaultStyle prints the interface
-func PrintDefaultStyle() error {
-	sysInfo, err := collectSystemInfo()
+// The disk line sums the disks that pass filter; the logo is the one of the OS unless
+// logo selects another one. The health score is left out if scorer is nil
+func PrintDefaultStyle(filter disk.Filter, logo LogoOptions, scorer *health.Scorer) error {
+	// Detect terminal width
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
 	if err != nil {
-		return fmt.Errorf("error collecting system information: %w", err)
+		width = 100 // safe default value
 	}
 
-	infoLines := formatSystemInfo(sysInfo)
+	lines, err := defaultStyleLines(filter, logo, scorer, width)
+	if err != nil {
+		return err
+	}
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+	return nil
+}
 
-	// Detect terminal width
-	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+// defaultStyleLines builds the lines of the interface for a screen width
+//
+// Parameters:
+//   - filter: disks summed by the disk line
+//   - logo: logo shown next to the information
+//   - scorer: computes the health score shown first (nil to leave it out)
+//   - width: screen width, which selects the vertical or side-by-side layout
+//
+// Returns:
+//   - lines with color codes, including the empty first and last lines
+//   - error if the logo or the system information can't be read
+func defaultStyleLines(filter disk.Filter, logo LogoOptions, scorer *health.Scorer, width int) ([]string, error) {
+	logoLines, logoWidth, err := selectLogo(logo)
 	if err != nil {
-		width = 100 // safe default value
+		return nil, err
 	}
 
-	fmt.Println()
+	sysInfo, err := collectSystemInfo(filter, scorer)
+	if err != nil {
+		return nil, fmt.Errorf("error collecting system information: %w", err)
+	}
+
+	infoLines := formatSystemInfo(sysInfo)
+
+	lines := []string{""}
 
 	// VISUAL SAFETY LOGIC:
-	// The box is 44 chars wide. The text is about 40-50 chars.
-	// We need at least 100-110 chars of width to avoid wrapping the line.
-	// If less than 110, use Vertical mode.
-	if width < 110 {
+	// The GoMonitor box is 44 chars wide, other logos are narrower. The text is about 40-50 chars.
+	// We need the logo, the spacing and infoColumnWidth (110 for the box) to avoid wrapping the line.
+	// If the screen is narrower, use Vertical mode.
+	if width < logoWidth+4+infoColumnWidth {
 		// Vertical mode (small screen)
-		for _, line := range logoLines {
-			fmt.Println(line)
-		}
+		lines = append(lines, logoLines...)
 		for _, line := range infoLines {
-			fmt.Println("   " + line) // Small indentation to look nice
+			lines = append(lines, "   "+line) // Small indentation to look nice
 		}
 	} else {
 		// Side-by-side mode (large screen)
@@ -107,33 +148,70 @@ func PrintDefaultStyle() error {
 		}
 
 		for i := 0; i < maxLines; i++ {
-			// Print logo line
+			var line string
+
+			// Logo line
 			if i < len(logoLines) {
-				fmt.Print(logoLines[i])
+				line = logoLines[i]
 			} else {
-				// 44 spaces to compensate for the logo box width when it ends
-				fmt.Print(strings.Repeat(" ", 44))
+				// Spaces to compensate for the logo width when it ends
+				line = strings.Repeat(" ", logoWidth)
 			}
 
 			// Spacing between logo and text
-			fmt.Print("    ")
+			line += "    "
 
-			// Print info line
+			// Info line
 			if i < len(infoLines) {
-				fmt.Print(infoLines[i])
+				line += infoLines[i]
 			}
 
-			fmt.Println()
+			lines = append(lines, line)
 		}
 	}
 
-	fmt.Println()
-	return nil
+	return append(lines, ""), nil
 }
 
 // collectSystemInfo gathers the data (same as before)
-func collectSystemInfo() (*SystemInfo, error) {
+// The health score reuses the readings of the other lines, so it costs no extra sampling
+// The readings that wait (CPU usage, GPU, disks) are collected in parallel, so it takes about one second
+func collectSystemInfo(filter disk.Filter, scorer *health.Scorer) (*SystemInfo, error) {
 	info := &SystemInfo{}
+	var inputs health.Inputs
+	var samples []alerts.Sample
+
+	// Static facts (OS, kernel, CPU and GPU models) are read once and kept by hwcache
+	hw := hwcache.Get()
+
+	// Start the slow readings together: the CPU usage is measured over one second, and the
+	// GPU (nvidia-smi) and disks (statfs of every mount) overlap with it
+	ctx, cancel := context.WithTimeout(context.Background(), common.CollectTimeout)
+	defer cancel()
+	cpuReading := common.Async(ctx, cpu.GetGeneralStats)
+	type storageTotals struct{ total, used uint64 }
+	diskReading := common.Async(ctx, func() (storageTotals, error) {
+		total, used, _, err := disk.GetTotalStorageStats(filter)
+		return storageTotals{total: total, used: used}, err
+	})
+	// Only sample the GPU when there is one, so machines without one don't run nvidia-smi on every refresh
+	var gpuReading func() (gpu.GPUStats, error)
+	if len(hw.GPUs) > 0 {
+		gpuReading = common.Async(ctx, func() (gpu.GPUStats, error) { return gpu.GetGPUStats(ctx) })
+	}
+	linkReading := common.Async(ctx, func() (string, error) {
+		name, link, err := network.GetDefaultLink(ctx)
+		if err != nil {
+			return "", err
+		}
+		return name + ": " + link.Summary(), nil
+	})
+	var devicesReading func() ([]disk.StorageDevice, error)
+	if scorer != nil {
+		devicesReading = common.Async(ctx, func() ([]disk.StorageDevice, error) {
+			return disk.GetAllStorageDevices(filter)
+		})
+	}
 
 	currentUser, err := user.Current()
 	if err == nil {
@@ -149,42 +227,72 @@ func collectSystemInfo() (*SystemInfo, error) {
 		info.Hostname = "unknown"
 	}
 
-	info.OS = getOSInfo()
-	info.Kernel = getKernelVersion()
+	info.OS = hw.OS.Name
+	info.Kernel = hw.Kernel
 	info.Uptime = getSystemUptime()
 	info.Shell = os.Getenv("SHELL")
 
-	cpuStats, err := cpu.GetGeneralStats()
+	ramStats, err := ram.GetRamGeneral()
+	if err == nil {
+		info.RAMTotal = formatBytes(ramStats.Total)
+		info.RAMUsed = formatBytes(ramStats.Used)
+		info.RAMPercent = ramStats.Percent
+		inputs.MemoryPercent, inputs.HasMemory = health.MemoryPressure(ramStats), true
+		samples = append(samples, alerts.RAMSample(ramStats))
+	} else {
+		info.RAMUnsupported = capability.IsNotImplemented(err)
+	}
+	if limits, ok := cgroup.Effective(); ok && limits.HasMemoryLimit() {
+		info.RAMLimit = limits.FormatMemory()
+	}
+
+	cpuStats, err := cpuReading()
 	if err == nil {
 		info.CPUModel = cpuStats.ModelName
 		info.CPUCores = cpuStats.Cores
 		info.CPUUsage = cpuStats.Percentage
 		info.CPUTemp = cpuStats.Temperature
+		inputs.CPUPercent, inputs.HasCPU, inputs.Temperature = cpuStats.Percentage, true, cpuStats.Temperature
+		samples = append(samples, alerts.CPUSample(cpuStats.Percentage))
+	} else {
+		info.CPUUnsupported = capability.IsNotImplemented(err)
 	}
 
-	ramStats, err := ram.GetRamGeneral()
+	totals, err := diskReading()
 	if err == nil {
-		info.RAMTotal = formatBytes(ramStats.Total)
-		info.RAMUsed = formatBytes(ramStats.Used)
-		info.RAMPercent = ramStats.Percent
+		info.DiskTotal = formatBytes(totals.total)
+		info.DiskUsed = formatBytes(totals.used)
+		if totals.total > 0 {
+			info.DiskPercent = (float64(totals.used) / float64(totals.total)) * 100
+		}
+	} else {
+		info.DiskUnsupported = capability.IsNotImplemented(err)
 	}
 
-	diskTotal, diskUsed, _, err := disk.GetTotalStorageStats()
-	if err == nil {
-		info.DiskTotal = formatBytes(diskTotal)
-		info.DiskUsed = formatBytes(diskUsed)
-		if diskTotal > 0 {
-			info.DiskPercent = (float64(diskUsed) / float64(diskTotal)) * 100
+	info.GPUModel = "Not detected"
+	if gpuReading != nil {
+		gpuStats, err := gpuReading()
+		switch {
+		case err == nil:
+			info.GPUModel = gpuStats.Model
+			info.GPUTemp = gpuStats.Temp
+		case errors.Is(err, common.ErrCommandTimeout):
+			info.GPUModel = "Not responding"
 		}
 	}
 
-	gpuStats, err := gpu.GetGPUStats()
-	if err == nil {
-		info.GPUModel = gpuStats.Model
-		info.GPUTemp = gpuStats.Temp
-	} else {
-		info.GPUModel = "Not detected"
-		info.GPUTemp = 0
+	if link, err := linkReading(); err == nil {
+		info.Network = link
+	}
+
+	if scorer != nil {
+		if devices, err := devicesReading(); err == nil {
+			inputs.DiskPercent, inputs.HasDisk = health.FullestDisk(devices)
+			samples = append(samples, alerts.DiskSamples(devices)...)
+		}
+		inputs.Alerts = scorer.Violations(samples)
+		score := scorer.Score(inputs)
+		info.Health = &score
 	}
 
 	return info, nil
@@ -197,38 +305,75 @@ func formatSystemInfo(info *SystemInfo) []string {
 	// Start with empty line to align with the top of the box
 	lines = append(lines, "")
 
-	lines = append(lines, formatInfoLine("OS", info.OS, colorBlue))
-	lines = append(lines, formatInfoLine("Kernel", info.Kernel, colorBlue))
-	lines = append(lines, formatInfoLine("Uptime", info.Uptime, colorBlue))
-	lines = append(lines, formatInfoLine("Shell", info.Shell, colorBlue))
+	// The overall health goes first, so problems stand out before the details
+	if info.Health != nil {
+		color := healthColor(info.Health.Level())
+		score := fmt.Sprintf("%s%s%d/100 (%s)%s", color, boldColor, info.Health.Value, info.Health.Level(), resetColor)
+		lines = append(lines, formatInfoLine("Health", score, color))
+	}
+
+	lines = append(lines, formatInfoLine("OS", info.OS, blueColor))
+	lines = append(lines, formatInfoLine("Kernel", info.Kernel, blueColor))
+	lines = append(lines, formatInfoLine("Uptime", info.Uptime, blueColor))
+	lines = append(lines, formatInfoLine("Shell", info.Shell, blueColor))
 
 	// More aggressive truncation (25 chars) to avoid line wrap
-	cpuInfo := fmt.Sprintf("%s (%d cores)", truncateString(info.CPUModel, 25), info.CPUCores)
-	lines = append(lines, formatInfoLine("CPU", cpuInfo, colorCyan))
-	lines = append(lines, formatInfoLine("CPU Usage", fmt.Sprintf("%.2f%%", info.CPUUsage), colorCyan))
+	// Metrics that can't be collected on this OS are shown as unsupported instead of zeros
+	if info.CPUUnsupported {
+		lines = append(lines, formatInfoLine("CPU", capability.Unsupported(), cyanColor))
+	} else {
+		cpuInfo := fmt.Sprintf("%s (%d cores)", common.TruncateString(info.CPUModel, 25), info.CPUCores)
+		lines = append(lines, formatInfoLine("CPU", cpuInfo, cyanColor))
+		lines = append(lines, formatInfoLine("CPU Usage", fmt.Sprintf("%.2f%%", info.CPUUsage), cyanColor))
+	}
 
 	if info.CPUTemp > 0 {
 		cpuTemp := fmt.Sprintf("%d°C", info.CPUTemp)
-		lines = append(lines, formatInfoLine("CPU Temp", cpuTemp, colorCyan))
+		lines = append(lines, formatInfoLine("CPU Temp", cpuTemp, cyanColor))
 	}
 
+	// Inside a limited cgroup (e.g. a container) the limit is what matters, not the host RAM
 	ramInfo := fmt.Sprintf("%s / %s (%.0f%%)", info.RAMUsed, info.RAMTotal, info.RAMPercent)
-	lines = append(lines, formatInfoLine("RAM", ramInfo, colorYellow))
+	if info.RAMLimit != "" {
+		ramInfo = info.RAMLimit
+	} else if info.RAMUnsupported {
+		ramInfo = capability.Unsupported()
+	}
+	lines = append(lines, formatInfoLine("RAM", ramInfo, yellowColor))
 
 	diskInfo := fmt.Sprintf("%s / %s (%.0f%%)", info.DiskUsed, info.DiskTotal, info.DiskPercent)
-	lines = append(lines, formatInfoLine("Disk", diskInfo, colorMagenta))
+	if info.DiskUnsupported {
+		diskInfo = capability.Unsupported()
+	}
+	lines = append(lines, formatInfoLine("Disk", diskInfo, magentaColor))
 
-	gpuInfo := truncateString(info.GPUModel, 25)
+	gpuInfo := common.TruncateString(info.GPUModel, 25)
 	if info.GPUTemp > 0 {
 		gpuInfo = fmt.Sprintf("%s (%d°C)", gpuInfo, info.GPUTemp)
 	}
-	lines = append(lines, formatInfoLine("GPU", gpuInfo, colorGreen))
+	lines = append(lines, formatInfoLine("GPU", gpuInfo, greenColor))
+
+	if info.Network != "" {
+		lines = append(lines, formatInfoLine("Network", common.TruncateString(info.Network, 40), blueColor))
+	}
 
 	return lines
 }
 
+// healthColor returns the theme color of a health level
+func healthColor(level string) string {
+	switch level {
+	case health.LevelGood:
+		return greenColor
+	case health.LevelFair:
+		return yellowColor
+	default:
+		return redColor
+	}
+}
+
 func formatInfoLine(label, value, labelColor string) string {
-	return labelColor + colorBold + label + colorReset + ": " + value
+	return labelColor + boldColor + label + resetColor + ": " + value
 }
 
 func formatBytes(bytes uint64) string {
@@ -252,16 +397,9 @@ func formatBytes(bytes uint64) string {
 	}
 }
 
-func truncateString(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
-	}
-	return s[:maxLen-3] + "..."
-}
-
 func getSystemUptime() string {
 	if runtime.GOOS == "linux" {
-		data, err := os.ReadFile("/proc/uptime")
+		data, err := os.ReadFile(paths.Proc("uptime"))
 		if err == nil {
 			var uptimeSeconds float64
 			fmt.Sscanf(string(data), "%f", &uptimeSeconds)
@@ -281,42 +419,3 @@ func getSystemUptime() string {
 	}
 	return "unknown"
 }
-
-func getOSInfo() string {
-	if runtime.GOOS == "linux" {
-		data, err := os.ReadFile("/etc/os-release")
-		if err == nil {
-			lines := strings.Split(string(data), "\n")
-			for _, line := range lines {
-				if strings.HasPrefix(line, "PRETTY_NAME=") {
-					return strings.Trim(strings.TrimPrefix(line, "PRETTY_NAME="), "\"")
-				}
-			}
-		}
-	}
-	return runtime.GOOS
-}
-
-func getKernelVersion() string {
-	if runtime.GOOS == "linux" {
-		data, err := os.ReadFile("/proc/version_signature")
-		if err == nil {
-			parts := strings.Fields(string(data))
-			if len(parts) >= 3 {
-				return parts[2]
-			}
-		}
-		// Fallback
-		data, err = os.ReadFile("/proc/version")
-		if err == nil {
-			version := string(data)
-			if strings.Contains(version, "Linux version") {
-				parts := strings.Split(version, " ")
-				if len(parts) >= 3 {
-					return parts[2]
-				}
-			}
-		}
-	}
-	return runtime.Version()
-}
@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/disk"
+	"github.com/dfialho05/GoMonitor/application/pck/health"
+)
+
+// exportWidth is the screen width the exported interface is laid out for (side by side)
+const exportWidth = 120
+
+// ExportDefaultStyle writes the default interface to a file for sharing
+// The format follows the file extension:
+//   - ".png": an image, rendered with the colors of the active theme
+//   - ".ans" or ".ansi": text with the ANSI color codes (shown in color by e.g. cat)
+//   - anything else: plain text without colors
+//
+// Parameters:
+//   - filter: disks summed by the disk line
+//   - logo: logo shown next to the information
+//   - scorer: computes the health score (nil to leave it out)
+//   - path: destination file ("" writes plain text to stdout)
+//
+// Returns: error if the information can't be collected or the file can't be written
+func ExportDefaultStyle(filter disk.Filter, logo LogoOptions, scorer *health.Scorer, path string) error {
+	lines, err := defaultStyleLines(filter, logo, scorer, exportWidth)
+	if err != nil {
+		return err
+	}
+
+	if path == "" {
+		return writeTextLines(os.Stdout, lines, false)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png":
+		// The empty first and last lines are replaced by the image padding
+		err = WritePNG(file, lines[1:len(lines)-1])
+	case ".ans", ".ansi":
+		err = writeTextLines(file, lines, true)
+	default:
+		err = writeTextLines(file, lines, false)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// writeTextLines writes lines as text, with or without their color codes
+// Trailing spaces are removed
+func writeTextLines(w io.Writer, lines []string, colors bool) error {
+	for _, line := range lines {
+		if !colors {
+			line = strings.TrimRight(common.StripANSI(line), " ")
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// sparkBlocks are the bar heights of a sparkline, from lowest to highest
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Layout of the graphs panel
+const (
+	graphLabelWidth = 6  // Width of the graph label (e.g. "CPU")
+	graphValueWidth = 10 // Width of the latest value shown after the graph
+	minTempRange    = 10 // Minimum temperature range of the graph in °C, so small changes stay flat
+)
+
+// sampleRing is a fixed-size ring buffer of samples
+// Once full, each new sample replaces the oldest one
+type sampleRing struct {
+	values []float64 // Samples (circular)
+	start  int       // Index of the oldest sample
+	count  int       // Number of samples stored
+}
+
+// newSampleRing creates a ring buffer that keeps the latest size samples
+func newSampleRing(size int) *sampleRing {
+	if size < 1 {
+		size = 1
+	}
+	return &sampleRing{values: make([]float64, size)}
+}
+
+// push adds a sample, dropping the oldest one if the buffer is full
+func (r *sampleRing) push(value float64) {
+	if r.count < len(r.values) {
+		r.values[(r.start+r.count)%len(r.values)] = value
+		r.count++
+		return
+	}
+	r.values[r.start] = value
+	r.start = (r.start + 1) % len(r.values)
+}
+
+// last returns the latest n samples, oldest first
+func (r *sampleRing) last(n int) []float64 {
+	if n > r.count {
+		n = r.count
+	}
+
+	samples := make([]float64, n)
+	for i := range samples {
+		samples[i] = r.values[(r.start+r.count-n+i)%len(r.values)]
+	}
+	return samples
+}
+
+// graphHistory contains the rolling system samples drawn in the graphs panel
+type graphHistory struct {
+	cpu         *sampleRing // System CPU usage (%)
+	ram         *sampleRing // System RAM usage (%)
+	temperature *sampleRing // CPU temperature (°C)
+}
+
+// newGraphHistory creates the sample buffers for the configured history length
+//
+// Parameters:
+//   - history: how far back the graphs go (e.g. 5 minutes)
+//   - interval: refresh interval (one sample is taken per refresh)
+func newGraphHistory(history, interval time.Duration) graphHistory {
+	size := int(history / interval)
+	return graphHistory{
+		cpu:         newSampleRing(size),
+		ram:         newSampleRing(size),
+		temperature: newSampleRing(size),
+	}
+}
+
+// record adds the system usage of a refresh to the graphs
+// Values that couldn't be read are skipped, so gaps don't show up as drops to zero
+func (g graphHistory) record(stats systemStats) {
+	if stats.HasCPU {
+		g.cpu.push(stats.CPUPercent)
+	}
+	if stats.HasRAM {
+		g.ram.push(stats.RAM.Percent)
+	}
+	if stats.CPUTemp > 0 {
+		g.temperature.push(float64(stats.CPUTemp))
+	}
+}
+
+// sparkline draws samples as a row of block characters
+// Samples are scaled between low and high; values outside the range are clamped
+//
+// Parameters:
+//   - samples: values to draw, oldest first
+//   - low: value drawn as the lowest block
+//   - high: value drawn as the highest block
+//
+// Returns: one character per sample
+func sparkline(samples []float64, low, high float64) string {
+	var line strings.Builder
+	for _, value := range samples {
+		level := 0
+		if high > low {
+			level = int((value - low) / (high - low) * float64(len(sparkBlocks)-1))
+		}
+		level = max(0, min(level, len(sparkBlocks)-1))
+		line.WriteRune(sparkBlocks[level])
+	}
+	return line.String()
+}
+
+// graphRows returns the rows of the graphs panel (CPU, RAM and temperature if available)
+// The graphs use the whole terminal width, showing as many of the latest samples as fit
+//
+// Returns:
+//   - slice of rendered (colored) graph rows
+func (tui *InteractiveTUI) graphRows() []string {
+	width := tui.width - graphLabelWidth - graphValueWidth - 6
+	if width < 1 {
+		width = 1
+	}
+
+	row := func(label, color string, samples []float64, low, high float64, value string) string {
+		graph := sparkline(samples, low, high)
+		padding := strings.Repeat(" ", width-len(samples))
+		return fmt.Sprintf("  %s%s%-*s%s %s%s%s %*s", boldColor, color, graphLabelWidth, label, resetColor,
+			color, graph, resetColor+padding, graphValueWidth, value)
+	}
+
+	cpuSamples := tui.graphs.cpu.last(width)
+	ramSamples := tui.graphs.ram.last(width)
+	rows := []string{
+		row("CPU", greenColor, cpuSamples, 0, 100, latestValue(cpuSamples, "%.1f%%")),
+		row("RAM", magentaColor, ramSamples, 0, 100, latestValue(ramSamples, "%.1f%%")),
+	}
+
+	// Temperatures are scaled to their own range, since they rarely go near 0 or 100 °C
+	if tempSamples := tui.graphs.temperature.last(width); len(tempSamples) > 0 {
+		low, high := tempSamples[0], tempSamples[0]
+		for _, value := range tempSamples {
+			low, high = min(low, value), max(high, value)
+		}
+		if high-low < minTempRange {
+			high = low + minTempRange
+		}
+		rows = append(rows, row("Temp", yellowColor, tempSamples, low, high, latestValue(tempSamples, "%.0f°C")))
+	}
+
+	return rows
+}
+
+// latestValue formats the most recent sample ("..." if there are no samples yet)
+func latestValue(samples []float64, format string) string {
+	if len(samples) == 0 {
+		return "..."
+	}
+	return fmt.Sprintf(format, samples[len(samples)-1])
+}
+
+// graphsShown checks if the graphs panel is shown
+// Only the Processes tab has it, since the other tabs have their own history graphs
+func (tui *InteractiveTUI) graphsShown() bool {
+	return tui.showGraphs && tui.tab == tabProcesses
+}
+
+// graphLines returns how many lines the graphs panel uses (0 when hidden)
+func (tui *InteractiveTUI) graphLines() int {
+	if !tui.graphsShown() {
+		return 0
+	}
+	// Graph rows + blank line
+	return len(tui.graphRows()) + 1
+}
+
+// renderGraphs renders the graphs panel below the info bar
+func (tui *InteractiveTUI) renderGraphs(w io.Writer) {
+	if !tui.graphsShown() {
+		return
+	}
+	for _, row := range tui.graphRows() {
+		fmt.Fprintln(w, row)
+	}
+	fmt.Fprintln(w)
+}
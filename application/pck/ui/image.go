@@ -0,0 +1,319 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Geometry of the rendered image: each character cell is cellWidth x cellHeight pixels
+// and every font pixel is drawn as a glyphScale x glyphScale square
+const (
+	cellWidth    = 12
+	cellHeight   = 20
+	glyphScale   = 2
+	glyphTop     = 3  // Pixels above the glyph in its cell
+	imagePadding = 16 // Border around the text
+)
+
+// Colors of the rendered image (a dark terminal)
+var (
+	imageBackground = color.RGBA{0x1e, 0x1e, 0x1e, 0xff}
+	imageForeground = color.RGBA{0xd4, 0xd4, 0xd4, 0xff}
+	imageBold       = color.RGBA{0xff, 0xff, 0xff, 0xff}
+)
+
+// ansiPalette holds the 16 basic terminal colors (normal, then bright)
+var ansiPalette = [16]color.RGBA{
+	{0x00, 0x00, 0x00, 0xff}, {0xcd, 0x31, 0x31, 0xff}, {0x0d, 0xbc, 0x79, 0xff}, {0xe5, 0xe5, 0x10, 0xff},
+	{0x24, 0x72, 0xc8, 0xff}, {0xbc, 0x3f, 0xbc, 0xff}, {0x11, 0xa8, 0xcd, 0xff}, {0xe5, 0xe5, 0xe5, 0xff},
+	{0x66, 0x66, 0x66, 0xff}, {0xf1, 0x4c, 0x4c, 0xff}, {0x23, 0xd1, 0x8b, 0xff}, {0xf5, 0xf5, 0x43, 0xff},
+	{0x3b, 0x8e, 0xea, 0xff}, {0xd6, 0x70, 0xd6, 0xff}, {0x29, 0xb8, 0xdb, 0xff}, {0xff, 0xff, 0xff, 0xff},
+}
+
+// styledCell is one character of the text with its color
+type styledCell struct {
+	r    rune
+	fg   color.RGBA
+	bold bool
+}
+
+// WritePNG renders lines of colored terminal text as a PNG image
+// The SGR color codes of the themes are understood (8/16, 256 and true colors, bold);
+// letters use a built-in bitmap font and box drawing and block characters are drawn as shapes
+//
+// Parameters:
+//   - w: destination of the image
+//   - lines: text lines, possibly with ANSI color codes
+//
+// Returns: error if the image can't be written
+func WritePNG(w io.Writer, lines []string) error {
+	// 1. Split the lines into colored cells
+	rows := make([][]styledCell, len(lines))
+	columns := 0
+	for i, line := range lines {
+		rows[i] = parseStyledLine(line)
+		columns = max(columns, len(rows[i]))
+	}
+
+	// 2. Draw every cell on a dark background
+	bounds := image.Rect(0, 0, columns*cellWidth+2*imagePadding, len(rows)*cellHeight+2*imagePadding)
+	img := image.NewRGBA(bounds)
+	fillRect(img, bounds, imageBackground)
+	for y, row := range rows {
+		for x, cell := range row {
+			drawCell(img, imagePadding+x*cellWidth, imagePadding+y*cellHeight, cell)
+		}
+	}
+
+	// 3. Encode
+	if err := png.Encode(w, img); err != nil {
+		return fmt.Errorf("error encoding PNG: %w", err)
+	}
+	return nil
+}
+
+// parseStyledLine splits a line into characters, applying its SGR color codes
+func parseStyledLine(line string) []styledCell {
+	var cells []styledCell
+	fg, bold, custom := imageForeground, false, false
+
+	for len(line) > 0 {
+		// Color codes change the style of the following characters
+		if loc := ansiCode.FindStringIndex(line); loc != nil && loc[0] == 0 {
+			params := line[2 : loc[1]-1]
+			fg, bold, custom = applySGR(params, fg, bold, custom)
+			line = line[loc[1]:]
+			continue
+		}
+
+		r, size := firstRune(line)
+		line = line[size:]
+		cellColor := fg
+		if bold && !custom {
+			cellColor = imageBold
+		}
+		cells = append(cells, styledCell{r: r, fg: cellColor, bold: bold})
+	}
+	return cells
+}
+
+// firstRune returns the first character of a string and its size in bytes
+func firstRune(s string) (rune, int) {
+	for _, r := range s {
+		return r, len(string(r))
+	}
+	return 0, 0
+}
+
+// applySGR applies the parameters of one SGR code (e.g. "1;91" or "38;5;160") to a style
+// Background colors are skipped
+//
+// Returns: the new foreground color, bold flag and whether the color was set by a code
+func applySGR(params string, fg color.RGBA, bold, custom bool) (color.RGBA, bool, bool) {
+	codes := strings.Split(params, ";")
+	for i := 0; i < len(codes); i++ {
+		code, _ := strconv.Atoi(codes[i]) // An empty code means 0
+		switch {
+		case code == 0:
+			fg, bold, custom = imageForeground, false, false
+		case code == 1:
+			bold = true
+		case code == 22:
+			bold = false
+		case code >= 30 && code <= 37:
+			fg, custom = ansiPalette[code-30], true
+		case code >= 90 && code <= 97:
+			fg, custom = ansiPalette[code-90+8], true
+		case code == 39:
+			fg, custom = imageForeground, false
+		case code == 38 || code == 48:
+			// Extended colors: "38;5;n" (256 colors) or "38;2;r;g;b" (true color)
+			extended, used := extendedColor(codes[i+1:])
+			if code == 38 && used > 0 {
+				fg, custom = extended, true
+			}
+			i += used
+		}
+	}
+	return fg, bold, custom
+}
+
+// extendedColor parses the arguments of a 256-color or true color SGR code
+//
+// Returns:
+//   - the color
+//   - number of arguments consumed (0 if they are invalid)
+func extendedColor(args []string) (color.RGBA, int) {
+	value := func(i int) uint8 {
+		n, _ := strconv.Atoi(args[i])
+		return uint8(n)
+	}
+
+	switch {
+	case len(args) >= 2 && args[0] == "5":
+		return xtermColor(value(1)), 2
+	case len(args) >= 4 && args[0] == "2":
+		return color.RGBA{value(1), value(2), value(3), 0xff}, 4
+	}
+	return color.RGBA{}, 0
+}
+
+// xtermColor converts an index of the 256-color palette to RGB
+func xtermColor(n uint8) color.RGBA {
+	switch {
+	case n < 16:
+		return ansiPalette[n]
+	case n < 232:
+		// 6x6x6 color cube
+		levels := [6]uint8{0, 95, 135, 175, 215, 255}
+		n -= 16
+		return color.RGBA{levels[n/36], levels[n/6%6], levels[n%6], 0xff}
+	default:
+		gray := 8 + 10*(n-232)
+		return color.RGBA{gray, gray, gray, 0xff}
+	}
+}
+
+// Line styles of the box drawing characters
+const (
+	lineNone   = iota
+	lineSingle // ─ │
+	lineDouble // ═ ║
+)
+
+// boxArms describes a box drawing character by the line style going up, down, left and right
+type boxArms struct {
+	up, down, left, right int
+}
+
+// boxChars are the box drawing characters that can be drawn
+var boxChars = map[rune]boxArms{
+	'─': {0, 0, 1, 1}, '│': {1, 1, 0, 0}, '┌': {0, 1, 0, 1}, '┐': {0, 1, 1, 0},
+	'└': {1, 0, 0, 1}, '┘': {1, 0, 1, 0}, '├': {1, 1, 0, 1}, '┤': {1, 1, 1, 0},
+	'┬': {0, 1, 1, 1}, '┴': {1, 0, 1, 1}, '┼': {1, 1, 1, 1},
+	'═': {0, 0, 2, 2}, '║': {2, 2, 0, 0}, '╔': {0, 2, 0, 2}, '╗': {0, 2, 2, 0},
+	'╚': {2, 0, 0, 2}, '╝': {2, 0, 2, 0}, '╠': {2, 2, 0, 2}, '╣': {2, 2, 2, 0},
+	'╦': {0, 2, 2, 2}, '╩': {2, 0, 2, 2}, '╬': {2, 2, 2, 2},
+	'╟': {2, 2, 0, 1}, '╢': {2, 2, 1, 0}, '╤': {0, 1, 2, 2}, '╧': {1, 0, 2, 2},
+}
+
+// drawCell draws one character at the top left corner (x, y) of its cell
+func drawCell(img *image.RGBA, x, y int, cell styledCell) {
+	// 1. Block characters fill part of the cell
+	switch cell.r {
+	case '█':
+		fillRect(img, image.Rect(x, y, x+cellWidth, y+cellHeight), cell.fg)
+		return
+	case '▀':
+		fillRect(img, image.Rect(x, y, x+cellWidth, y+cellHeight/2), cell.fg)
+		return
+	case '▄':
+		fillRect(img, image.Rect(x, y+cellHeight/2, x+cellWidth, y+cellHeight), cell.fg)
+		return
+	case '░', '▒', '▓':
+		drawShade(img, x, y, cell)
+		return
+	}
+
+	// 2. Box drawing characters are lines through the middle of the cell
+	if arms, ok := boxChars[cell.r]; ok {
+		drawBox(img, x, y, arms, cell.fg)
+		return
+	}
+
+	// 3. Everything else uses the bitmap font ('?' if it has no glyph)
+	glyph, ok := glyphs[cell.r]
+	if !ok {
+		glyph = glyphs['?']
+	}
+	left := x + (cellWidth-glyphWidth*glyphScale)/2
+	top := y + glyphTop
+	for row, bits := range glyph {
+		for col := 0; col < glyphWidth; col++ {
+			if bits&(1<<(glyphWidth-1-col)) == 0 {
+				continue
+			}
+			px, py := left+col*glyphScale, top+row*glyphScale
+			// Bold text is drawn one pixel wider
+			width := glyphScale
+			if cell.bold {
+				width++
+			}
+			fillRect(img, image.Rect(px, py, px+width, py+glyphScale), cell.fg)
+		}
+	}
+}
+
+// drawShade draws the light, medium and dark shade characters as a dot pattern
+func drawShade(img *image.RGBA, x, y int, cell styledCell) {
+	every := map[rune]int{'░': 4, '▒': 2, '▓': 1}[cell.r]
+	for py := 0; py < cellHeight; py++ {
+		for px := 0; px < cellWidth; px++ {
+			if cell.r == '▓' && (px+py)%4 == 0 || cell.r != '▓' && (px+py)%every == 0 && py%2 == 0 {
+				img.SetRGBA(x+px, y+py, cell.fg)
+			}
+		}
+	}
+}
+
+// drawBox draws a box drawing character from its arms
+// Double lines are two lines 2 pixels away from the middle
+func drawBox(img *image.RGBA, x, y int, arms boxArms, c color.RGBA) {
+	midX, midY := x+cellWidth/2, y+cellHeight/2
+	offsets := map[int][]int{lineSingle: {0}, lineDouble: {-2, 2}}
+
+	// Horizontal arms stop at the middle (or reach the far vertical line of a double corner)
+	for _, dy := range offsets[max(arms.left, arms.right)] {
+		from, to := x, x+cellWidth
+		if arms.left == lineNone {
+			from = midX + cornerOffset(arms.up, arms.down, dy, arms.up != lineNone)
+		}
+		if arms.right == lineNone {
+			to = midX + 1 + cornerOffset(arms.up, arms.down, -dy, arms.up != lineNone)
+		}
+		fillRect(img, image.Rect(from, midY+dy, to, midY+dy+1), c)
+	}
+
+	for _, dx := range offsets[max(arms.up, arms.down)] {
+		from, to := y, y+cellHeight
+		if arms.up == lineNone {
+			from = midY + cornerOffset(arms.left, arms.right, dx, arms.left != lineNone)
+		}
+		if arms.down == lineNone {
+			to = midY + 1 + cornerOffset(arms.left, arms.right, -dx, arms.left != lineNone)
+		}
+		fillRect(img, image.Rect(midX+dx, from, midX+dx+1, to), c)
+	}
+}
+
+// cornerOffset returns where a double line ends at a corner, so the inner and outer lines
+// of a double corner meet without crossing
+//
+// Parameters:
+//   - a, b: styles of the two perpendicular arms
+//   - offset: offset of the line being drawn from the middle
+//   - first: the perpendicular arm is on the negative side (up or left)
+func cornerOffset(a, b, offset int, first bool) int {
+	if max(a, b) != lineDouble {
+		return 0
+	}
+	if first {
+		return -offset
+	}
+	return offset
+}
+
+// fillRect fills a rectangle of the image with a color
+func fillRect(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	r = r.Intersect(img.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
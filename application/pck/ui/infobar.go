@@ -0,0 +1,260 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/cgroup"
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/cpu"
+	"github.com/dfialho05/GoMonitor/application/pck/health"
+	"github.com/dfialho05/GoMonitor/application/pck/power"
+	"github.com/dfialho05/GoMonitor/application/pck/ram"
+)
+
+// systemStats contains the system-wide usage shown in the info bar
+// Unlike the per-process values, these don't double-count shared memory or exceed 100%
+type systemStats struct {
+	CPUPercent float64        // Global CPU usage (0-100%)
+	HasCPU     bool           // False until two CPU samples have been taken
+	RAM        ram.RamGeneral // System RAM usage
+	HasRAM     bool           // False if the RAM usage couldn't be read
+	Load       cpu.LoadStats  // Load average and uptime
+	HasLoad    bool           // False if the load/uptime couldn't be read
+	Cgroup     cgroup.Limits  // Limits of the cgroup GoMonitor runs in
+	HasCgroup  bool           // False if the cgroup couldn't be read
+	CPUTemp    int            // CPU temperature in °C (0 if not available)
+}
+
+// selectedCgroup caches the cgroup limits of the selected process
+// so moving the selection doesn't re-read the cgroup files on every render
+type selectedCgroup struct {
+	pid    int32         // PID the limits belong to (-1 if nothing is cached)
+	limits cgroup.Limits // Limits of the process
+	ok     bool          // False if the cgroup couldn't be read
+}
+
+// firstCPUSampleInterval is how long the first system CPU sample is measured for
+const firstCPUSampleInterval = 250 * time.Millisecond
+
+// infoItem is one "Label: value" entry of the info bar
+type infoItem struct {
+	label string // Label (e.g. "System CPU")
+	value string // Value shown after the label
+	color string // Color of the label
+}
+
+// sampleSystemStats reads the system-wide CPU, RAM and load
+// CPU usage is measured since the previous call, so it matches the refresh interval
+func sampleSystemStats() systemStats {
+	var stats systemStats
+
+	percent, err := cpu.GetSystemPercent()
+	if err != nil {
+		// First sample: there's nothing to compare with yet, so measure over a short interval
+		time.Sleep(firstCPUSampleInterval)
+		percent, err = cpu.GetSystemPercent()
+	}
+	if err == nil {
+		stats.CPUPercent = percent
+		stats.HasCPU = true
+	} else {
+		common.WarnEvery("cpu", time.Minute, "error reading the CPU usage", "err", err)
+	}
+
+	if ramStats, err := ram.GetRamGeneral(); err == nil {
+		stats.RAM = ramStats
+		stats.HasRAM = true
+	} else {
+		common.WarnEvery("ram", time.Minute, "error reading the memory usage", "err", err)
+	}
+
+	if loadStats, err := cpu.GetLoadStats(); err == nil {
+		stats.Load = loadStats
+		stats.HasLoad = true
+	} else {
+		common.WarnEvery("load", time.Minute, "error reading the load average", "err", err)
+	}
+
+	stats.CPUTemp = cpu.GetTemperature()
+
+	if limits, err := cgroup.Self(); err == nil {
+		stats.Cgroup = limits
+		stats.HasCgroup = true
+	}
+
+	return stats
+}
+
+// healthScore computes the health score from the last refresh, the last disk check and the alerts firing
+func (tui *InteractiveTUI) healthScore() health.Score {
+	inputs := health.Inputs{
+		CPUPercent:  tui.system.CPUPercent,
+		HasCPU:      tui.system.HasCPU,
+		HasMemory:   tui.system.HasRAM,
+		Temperature: tui.system.CPUTemp,
+		Alerts:      len(tui.activeAlerts),
+	}
+	if tui.system.HasRAM {
+		inputs.MemoryPercent = health.MemoryPressure(tui.system.RAM)
+	}
+	if fullest := tui.fullestDisk.Load(); fullest != nil {
+		inputs.DiskPercent, inputs.HasDisk = *fullest, true
+	}
+	return tui.health.Score(inputs)
+}
+
+// infoItems returns the entries shown in the info bar
+// System-wide values and per-process sums are labeled separately, since the sums
+// can exceed 100% per core and double-count shared memory
+func (tui *InteractiveTUI) infoItems() []infoItem {
+	// Sum the per-process values
+	var sumCPU float64
+	var sumRSS uint64
+	for _, p := range tui.processes {
+		sumCPU += p.CPUPercentage
+		sumRSS += p.RAMBytes
+	}
+
+	// Show how many processes couldn't be read, since they are missing from the sums
+	processText := fmt.Sprintf("%d", len(tui.processes))
+	if tui.collection.Skipped > 0 {
+		processText = tui.collection.String()
+	}
+
+	systemCPU := "..."
+	if tui.system.HasCPU {
+		systemCPU = fmt.Sprintf("%.2f%%", tui.system.CPUPercent)
+	}
+
+	systemRAM := "N/A"
+	if tui.system.HasRAM {
+		systemRAM = fmt.Sprintf("%s / %s (%.2f%%)",
+			common.FormatBytes(tui.system.RAM.Used), common.FormatBytes(tui.system.RAM.Total), tui.system.RAM.Percent)
+	}
+
+	score := tui.healthScore()
+	items := []infoItem{
+		{"Health", fmt.Sprintf("%d/100 (%s)", score.Value, score.Level()), healthColor(score.Level())},
+		{"Processes", processText, cyanColor},
+		{"States", common.CountStates(tui.processes).String(), cyanColor},
+		{"System CPU", systemCPU, greenColor},
+		{"Sum of processes CPU", fmt.Sprintf("%.2f%%", sumCPU), greenColor},
+	}
+
+	// Inside a limited cgroup (e.g. a container) the limit determines OOM behavior, not the host RAM
+	if tui.system.HasCgroup && tui.system.Cgroup.HasMemoryLimit() {
+		items = append(items, infoItem{"Cgroup RAM", tui.system.Cgroup.FormatMemory(), magentaColor})
+	} else {
+		items = append(items, infoItem{"System RAM", systemRAM, magentaColor})
+	}
+	if tui.system.HasCgroup && tui.system.Cgroup.HasCPULimit() {
+		items = append(items, infoItem{"CPU limit", fmt.Sprintf("%.2f cores", tui.system.Cgroup.CPULimit), greenColor})
+	}
+	items = append(items, infoItem{"Sum of RSS", common.FormatBytes(sumRSS), magentaColor})
+
+	// Limits of the selected process, if it runs in a different limited cgroup
+	if limits, ok := tui.selectedLimits(); ok && limits.Path != tui.system.Cgroup.Path {
+		if limits.HasMemoryLimit() {
+			items = append(items, infoItem{"Selected cgroup", limits.FormatMemory(), magentaColor})
+		}
+		if limits.HasCPULimit() && limits.CPULimit != tui.system.Cgroup.CPULimit {
+			items = append(items, infoItem{"Selected CPU limit", fmt.Sprintf("%.2f cores", limits.CPULimit), greenColor})
+		}
+	}
+
+	if tui.system.HasLoad {
+		items = append(items,
+			infoItem{"Load", tui.system.Load.FormatLoad(), yellowColor},
+			infoItem{"Up", common.FormatDuration(tui.system.Load.Uptime), blueColor},
+		)
+	}
+
+	// CPU package power, which the POWER column divides among the processes
+	if tui.showEnergy || tui.sortMode == SortByEnergy {
+		powerText := "..."
+		if errors.Is(tui.energyErr, power.ErrNoRAPL) {
+			powerText = "N/A (no RAPL counters)"
+		} else if errors.Is(tui.energyErr, os.ErrPermission) {
+			powerText = "N/A (needs root)"
+		} else if tui.energyErr != nil {
+			powerText = "N/A"
+		} else if tui.packageWatts > 0 {
+			powerText = power.FormatWatts(tui.packageWatts)
+		}
+		items = append(items, infoItem{"CPU power", powerText, magentaColor})
+	}
+
+	refreshText := tui.refreshInterval().String()
+	if tui.sessionState.Inactive() {
+		refreshText += " (session " + tui.sessionState.String() + ")"
+	}
+	if tui.paused {
+		refreshText = yellowColor + "paused" + resetColor
+	}
+	items = append(items, infoItem{"Refresh", refreshText, whiteColor})
+
+	if tui.statusValue != nil {
+		items = append(items, infoItem{tui.statusLabel, tui.statusValue(), redColor})
+	}
+
+	if len(tui.marked) > 0 {
+		items = append(items, infoItem{"Marked", fmt.Sprintf("%d processes", len(tui.marked)), yellowColor})
+	}
+
+	if tui.notice != "" {
+		items = append(items, infoItem{"Action", tui.notice, yellowColor})
+	}
+
+	return append(items, infoItem{"Sort by", yellowColor + tui.sortMode.label() + resetColor, whiteColor})
+}
+
+// selectedLimits returns the cgroup limits of the selected process
+// The limits are read once per selected process and refresh
+//
+// Returns:
+//   - Limits of the selected process
+//   - false if there is no selection or its cgroup can't be read
+func (tui *InteractiveTUI) selectedLimits() (cgroup.Limits, bool) {
+	if tui.selectedIndex < 0 || tui.selectedIndex >= len(tui.processes) {
+		return cgroup.Limits{}, false
+	}
+
+	pid := tui.processes[tui.selectedIndex].PID
+	if tui.selectedCgroup.pid != pid {
+		limits, err := cgroup.Get(pid)
+		tui.selectedCgroup = selectedCgroup{pid: pid, limits: limits, ok: err == nil}
+	}
+	return tui.selectedCgroup.limits, tui.selectedCgroup.ok
+}
+
+// infoBarRows wraps the info bar entries into rows that fit the terminal width
+//
+// Returns:
+//   - slice of rendered (colored) info bar rows
+func (tui *InteractiveTUI) infoBarRows() []string {
+	var rows []string
+	row := "  "
+	rowWidth := 2
+
+	for _, item := range tui.infoItems() {
+		// Visible width: label + ": " + value + "  " (the value may contain color codes)
+		itemWidth := common.DisplayWidth(item.label) + common.DisplayWidth(item.value) + 4
+		if rowWidth+itemWidth > tui.width && rowWidth > 2 {
+			rows = append(rows, row)
+			row = "  "
+			rowWidth = 2
+		}
+
+		row += boldColor + item.color + item.label + ":" + resetColor + " " + item.value + "  "
+		rowWidth += itemWidth
+	}
+
+	return append(rows, row)
+}
+
+// ansiCode matches the SGR escape codes used for colors and styles (e.g. "\033[1;91m")
+var ansiCode = regexp.MustCompile("\033\\[[0-9;]*m")
@@ -1,40 +1,41 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 	"unsafe"
 
+	"github.com/dfialho05/GoMonitor/application/pck/alerts"
+	"github.com/dfialho05/GoMonitor/application/pck/cgroup"
 	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/config"
+	"github.com/dfialho05/GoMonitor/application/pck/container"
+	"github.com/dfialho05/GoMonitor/application/pck/cpu"
+	"github.com/dfialho05/GoMonitor/application/pck/disk"
+	"github.com/dfialho05/GoMonitor/application/pck/gpu"
+	"github.com/dfialho05/GoMonitor/application/pck/health"
+	"github.com/dfialho05/GoMonitor/application/pck/history"
+	"github.com/dfialho05/GoMonitor/application/pck/hwcache"
+	"github.com/dfialho05/GoMonitor/application/pck/network"
+	"github.com/dfialho05/GoMonitor/application/pck/paths"
+	"github.com/dfialho05/GoMonitor/application/pck/power"
+	"github.com/dfialho05/GoMonitor/application/pck/ram"
+	"github.com/dfialho05/GoMonitor/application/pck/session"
 )
 
 // ANSI escape code constants
+// Colors come from the active theme (see theme.go)
 const (
-	// Colors
-	resetColor   = "\033[0m"
-	redColor     = "\033[31m"
-	greenColor   = "\033[32m"
-	yellowColor  = "\033[33m"
-	blueColor    = "\033[34m"
-	magentaColor = "\033[35m"
-	cyanColor    = "\033[36m"
-	whiteColor   = "\033[37m"
-	boldColor    = "\033[1m"
-
-	// Background colors
-	bgBlack   = "\033[40m"
-	bgRed     = "\033[41m"
-	bgGreen   = "\033[42m"
-	bgYellow  = "\033[43m"
-	bgBlue    = "\033[44m"
-	bgMagenta = "\033[45m"
-	bgCyan    = "\033[46m"
-	bgWhite   = "\033[47m"
-
 	// Cursor controls
 	clearScreen   = "\033[2J"
 	moveCursor    = "\033[%d;%dH"
@@ -43,39 +44,189 @@ const (
 	clearLine     = "\033[2K"
 	saveCursor    = "\033[s"
 	restoreCursor = "\033[u"
+	eraseToEnd    = "\033[K"      // From the cursor to the end of the line
+	eraseBelow    = "\033[J"      // From the cursor to the end of the screen
+	beginUpdate   = "\033[?2026h" // Synchronized update: supporting terminals show it at once
+	endUpdate     = "\033[?2026l" // End of the synchronized update
 )
 
 // SortMode defines the process sorting mode
 type SortMode int
 
 const (
-	SortByCPU SortMode = iota // Sort by CPU usage
-	SortByRAM                 // Sort by RAM usage
-	SortByPID                 // Sort by PID
+	SortByCPU       SortMode = iota // Sort by CPU usage
+	SortByRAM                       // Sort by RAM usage
+	SortByPID                       // Sort by PID
+	SortByName                      // Sort by process name
+	SortByUser                      // Sort by owner
+	SortByMemory                    // Sort by resident memory in bytes
+	SortByThreads                   // Sort by thread count
+	SortByTime                      // Sort by running time
+	SortByCPUTime                   // Sort by CPU time used since the process started
+	SortByContainer                 // Group by container
+	SortBySwap                      // Sort by memory in swap
+	SortByIO                        // Sort by disk read and write throughput
+	SortByEnergy                    // Sort by estimated power
+	sortModeCount                   // Number of sort modes (used to cycle)
 )
 
+// label returns the sort mode name with its direction (e.g. "CPU ▼")
+func (m SortMode) label() string {
+	switch m {
+	case SortByCPU:
+		return "CPU ▼"
+	case SortByRAM:
+		return "RAM ▼"
+	case SortByPID:
+		return "PID ▲"
+	case SortByName:
+		return "Name ▲"
+	case SortByUser:
+		return "User ▲"
+	case SortByMemory:
+		return "Memory ▼"
+	case SortByThreads:
+		return "Threads ▼"
+	case SortByTime:
+		return "Running time ▼"
+	case SortByCPUTime:
+		return "CPU time ▼"
+	case SortByContainer:
+		return "Container ▲"
+	case SortBySwap:
+		return "Swap ▼"
+	case SortByIO:
+		return "Disk I/O ▼"
+	case SortByEnergy:
+		return "Power ▼"
+	}
+	return ""
+}
+
+// next returns the sort mode that follows m, wrapping around to the first one
+func (m SortMode) next() SortMode {
+	return (m + 1) % sortModeCount
+}
+
 // InteractiveTUI represents the interactive TUI interface
 type InteractiveTUI struct {
-	processes     []common.ProcessInfo // Process list
-	selectedIndex int                  // Selected process index
-	scrollOffset  int                  // Scroll offset
-	sortMode      SortMode             // Current sort mode
-	running       bool                 // Flag to control main loop
-	width         int                  // Terminal width
-	height        int                  // Terminal height
+	config          config.Config                 // User settings
+	collector       *common.ProcessCollector      // Process cache reused between refreshes
+	tracker         *common.ProcessTracker        // Detects renamed and respawning processes
+	keymap          map[string]config.Action      // Action bound to each key
+	resolver        *container.Resolver           // Maps processes to containers
+	containers      map[int32]string              // Container of each process (host processes are not included)
+	swap            map[int32]uint64              // Memory in swap of each process (bytes)
+	ioSampler       *disk.IOSampler               // Measures the disk throughput of processes between refreshes
+	diskIO          map[int32]disk.IORate         // Disk throughput of each process since the previous refresh
+	energySampler   *power.EnergySampler          // Measures the CPU package power between refreshes
+	throttleSampler *cpu.ThrottleSampler          // Detects CPU throttling between refreshes
+	throttle        cpu.ThrottleStatus            // Frequency and throttling since the previous refresh
+	energy          map[int32]power.ProcessEnergy // Power attributed to each process since the previous refresh
+	packageWatts    float64                       // CPU package power since the previous refresh
+	energyErr       error                         // Error of the last power sample (e.g. no RAPL)
+	cmdlines        map[int32]string              // Command line of each process (kernel threads are not included)
+	processes       []common.ProcessInfo          // Process list
+	allProcesses    []common.ProcessInfo          // Every process of the last update, before grouping by application
+	collection      common.CollectionStats        // Shown and skipped process counts of the last update
+	system          systemStats                   // System-wide usage sampled on the last update
+	alertSystem     atomic.Pointer[systemStats]   // Copy of system for the alert rules (read by watchAlerts)
+	fullestDisk     atomic.Pointer[float64]       // Usage of the fullest mount, measured by watchAlerts (nil before)
+	health          *health.Scorer                // Computes the health score of the info bar
+	selectedCgroup  selectedCgroup                // Cached cgroup limits of the selected process
+	details         processDetails                // Cached details of the selected process (split view)
+	network         *network.Sampler              // Measures the network throughput between refreshes
+	interfaces      []network.InterfaceStats      // Network interfaces of the last update (split view)
+	networkErr      error                         // Error of the last network sample
+	graphs          graphHistory                  // Rolling CPU, RAM and temperature samples
+	tabs            []tabView                     // Views of every tab, in tabKind order
+	tab             tabKind                       // Tab shown
+	tabScroll       int                           // First detail row shown in a subsystem tab
+	metrics         *history.MetricStore          // Stores the system usage for gom chart (nil if history is disabled)
+	selectedIndex   int                           // Selected process index
+	scrollOffset    int                           // Scroll offset
+	sortMode        SortMode                      // Current sort mode
+	pinned          map[string]bool               // Names of the processes listed first (pinned setting)
+	marked          map[int32]int64               // Processes marked for the bulk actions, PID → start time (detects reused PIDs)
+	running         atomic.Bool                   // Flag to control the main loop (read by the background goroutines)
+	paused          bool                          // Auto-refresh paused
+	showCore        bool                          // Show the CPU core column
+	showGroups      bool                          // Show the process group and session ID columns
+	groupApps       bool                          // Show one row per application instead of per process
+	appCounts       map[int32]int                 // Number of processes of each application, by main PID (when grouped)
+	showContainer   bool                          // Show the container column
+	showSwap        bool                          // Show the swap column
+	showIO          bool                          // Show the disk I/O column
+	showEnergy      bool                          // Show the estimated power column
+	showGraphs      bool                          // Show the graphs panel
+	showCmdline     bool                          // Show the command line instead of the name
+	nameScroll      int                           // First character shown in the NAME/COMMAND column
+	pane            paneKind                      // Content of the second pane (paneNone: no split)
+	paneFocused     bool                          // Up/down scroll the second pane instead of the process list
+	paneScroll      int                           // First content row shown in the second pane
+	activeAlerts    []alerts.Alert                // Alerts currently firing
+	sessionState    session.State                 // Lock/idle state of the session (when_locked.enabled)
+	pauseGPU        atomic.Bool                   // Skip GPU polling while the session is locked (read by watchAlerts)
+	statusLabel     string                        // Label of an extra info bar entry set by the caller
+	statusValue     func() string                 // Computes the value of the extra entry (nil if there is none)
+	notice          string                        // Result of the last process action (e.g. throttling or renicing)
+	confirmGroup    int32                         // Process group waiting for the kill confirmation (0: none)
+	confirmMarked   config.Action                 // Bulk action on the marked processes waiting for confirmation (empty: none)
+	done            <-chan struct{}               // Closing it exits the TUI (nil: only the user exits)
+	terminal        *terminalGuard                // Restores the terminal on exit or panic (set by Run)
+	screen          screen                        // Rows on the terminal, so a render only rewrites the changed ones
+	width           int                           // Terminal width
+	height          int                           // Terminal height
 }
 
 // NewInteractiveTUI creates a new TUI interface instance
 // Returns a pointer to configured InteractiveTUI
-func NewInteractiveTUI() *InteractiveTUI {
-	return &InteractiveTUI{
-		selectedIndex: 0,
-		scrollOffset:  0,
-		sortMode:      SortByCPU,
-		running:       true,
-		width:         120,
-		height:        30,
+func NewInteractiveTUI(cfg config.Config) *InteractiveTUI {
+	tui := &InteractiveTUI{
+		config:          cfg,
+		collector:       common.NewProcessCollector(),
+		tracker:         common.NewProcessTracker(),
+		keymap:          cfg.Keymap(),
+		health:          health.NewScorer(cfg),
+		graphs:          newGraphHistory(cfg.GraphHistory.Duration, cfg.RefreshInterval.Duration),
+		tabs:            newTabs(cfg.GraphHistory.Duration),
+		resolver:        container.NewResolver(),
+		ioSampler:       disk.NewIOSampler(),
+		energySampler:   power.NewEnergySampler(),
+		throttleSampler: cpu.NewThrottleSampler(),
+		network:         network.NewSampler(),
+		selectedIndex:   0,
+		scrollOffset:    0,
+		sortMode:        SortByCPU,
+		pinned:          make(map[string]bool, len(cfg.Pinned)),
+		marked:          make(map[int32]int64),
+		width:           defaultWidth,
+		height:          defaultHeight,
 	}
+	for _, name := range cfg.Pinned {
+		tui.pinned[name] = true
+	}
+	tui.running.Store(true)
+	return tui
+}
+
+// SetStatus adds an entry to the info bar whose value is computed on each render
+// Used by modes that run something alongside the TUI (e.g. the remaining time of a stress test)
+//
+// Parameters:
+//   - label: entry label (e.g. "Burn")
+//   - value: function returning the current value
+func (tui *InteractiveTUI) SetStatus(label string, value func() string) {
+	tui.statusLabel = label
+	tui.statusValue = value
+}
+
+// ExitOn makes the TUI exit when the channel is closed
+//
+// Parameters:
+//   - done: channel closed when the TUI should exit (e.g. when a stress test ends)
+func (tui *InteractiveTUI) ExitOn(done <-chan struct{}) {
+	tui.done = done
 }
 
 // Run starts the interactive TUI interface
@@ -86,36 +237,127 @@ func (tui *InteractiveTUI) Run() error {
 	if err != nil {
 		return fmt.Errorf("error configuring terminal: %w", err)
 	}
-	defer restoreTerminal(oldState)
+	// Restore it however the TUI exits, including a panic in any goroutine, and never
+	// leave a fan slowed down by hand
+	tui.terminal = &terminalGuard{oldState: oldState, cleanup: tui.restoreFans}
+	defer tui.terminal.restore()
+	defer tui.terminal.recoverPanic()
 
-	// Hide cursor
+	// Hide cursor, and report clicks and the wheel unless the mouse is turned off
 	fmt.Print(hideCursor)
-	defer fmt.Print(showCursor)
+	if tui.config.Mouse {
+		fmt.Print(enableMouse)
+	}
 
-	// Configure Ctrl+C handler
+	// Configure Ctrl+C handler (also exit cleanly when the terminal is closed)
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGQUIT)
+	defer signal.Stop(sigChan)
+
+	// Configure terminal resize handler
+	resizeChan := make(chan os.Signal, 1)
+	signal.Notify(resizeChan, syscall.SIGWINCH)
+	defer signal.Stop(resizeChan)
+
+	// Query the real terminal size
+	tui.updateTerminalSize()
+
+	// Channels for key and mouse capture
+	keyChan := make(chan string, 10)
+	mouseChan := make(chan mouseEvent, 10)
+	go tui.captureKeys(keyChan, mouseChan)
+
+	// Channel for alert updates (evaluated in the background)
+	alertChan := make(chan []alerts.Alert, 1)
+	go tui.watchAlerts(alertChan)
+
+	// Channel for session lock/idle changes (only if enabled in the config)
+	sessionChan := make(chan session.State, 1)
+	if tui.config.WhenLocked.Enabled {
+		go tui.watchSession(sessionChan)
+	}
 
-	// Channel for key capture
-	keyChan := make(chan byte, 10)
-	go tui.captureKeys(keyChan)
+	// Store the system usage when the history is enabled, dropping samples past the retention
+	if tui.config.HistoryEnabled {
+		if path, err := history.DefaultMetricsPath(); err == nil {
+			tui.metrics = history.NewMetricStore(path)
+			if err := tui.metrics.Prune(); err != nil {
+				common.WarnEvery("history", time.Minute, "error writing the history", "err", err)
+			}
+		}
+	}
 
 	// First data update
 	tui.updateProcesses()
 	tui.render()
 
+	// Automatic refresh
+	ticker := time.NewTicker(tui.refreshInterval())
+	defer ticker.Stop()
+
+	// Overheat check of the fans set by hand (fan_control), at the normal refresh interval
+	// whether auto-refresh is paused or slowed down while the session is locked
+	var fanGuard <-chan time.Time
+	if tui.config.FanControl {
+		fanTicker := time.NewTicker(tui.config.RefreshInterval.Duration)
+		defer fanTicker.Stop()
+		fanGuard = fanTicker.C
+	}
+
 	// Main interface loop
-	for tui.running {
+	for tui.running.Load() {
 		// Wait for events
 		select {
 		case <-sigChan:
 			// Ctrl+C pressed - exit
-			tui.running = false
+			tui.running.Store(false)
+
+		case <-tui.done:
+			// Exit requested by the caller
+			tui.running.Store(false)
+
+		case <-resizeChan:
+			// Terminal resized - relayout with the new size, redrawing the whole screen
+			tui.updateTerminalSize()
+			tui.screen.invalidate()
+			tui.render()
 
 		case key := <-keyChan:
 			// Process pressed key
 			tui.handleKey(key)
 
+		case event := <-mouseChan:
+			// Mouse click or wheel
+			tui.handleMouse(event)
+
+		case <-ticker.C:
+			// Periodic refresh (unless paused)
+			if !tui.paused {
+				tui.updateProcesses()
+				tui.render()
+			}
+
+		case <-fanGuard:
+			// Fans set by hand - give them back to the chip if a sensor overheats
+			if tui.sensorsView().guardFans(tui) {
+				tui.render()
+			}
+
+		case firing := <-alertChan:
+			// Alerts changed - show them
+			tui.activeAlerts = firing
+			tui.render()
+
+		case state := <-sessionChan:
+			// Session locked or idle - sample less often; back in use - refresh right away
+			tui.sessionState = state
+			tui.pauseGPU.Store(state.Inactive() && tui.config.WhenLocked.PauseGPU)
+			ticker.Reset(tui.refreshInterval())
+			if !state.Inactive() && !tui.paused {
+				tui.updateProcesses()
+			}
+			tui.render()
+
 		default:
 			time.Sleep(50 * time.Millisecond)
 		}
@@ -129,13 +371,143 @@ func (tui *InteractiveTUI) Run() error {
 	return nil
 }
 
+// refreshInterval returns the automatic refresh interval for the current session state
+// Slower while the session is locked or idle (when_locked.enabled)
+func (tui *InteractiveTUI) refreshInterval() time.Duration {
+	if tui.sessionState.Inactive() {
+		return tui.config.WhenLocked.RefreshInterval.Duration
+	}
+	return tui.config.RefreshInterval.Duration
+}
+
+// watchSession polls the lock and idle state of the session and sends it when it changes
+// Stops if the state can't be read (no systemd-logind, busctl or session)
+func (tui *InteractiveTUI) watchSession(sessionChan chan session.State) {
+	defer tui.terminal.recoverPanic()
+	var last session.State
+	for tui.running.Load() {
+		state, err := session.Get()
+		if err != nil {
+			common.Logger().Debug("session state not available", "err", err)
+			return
+		}
+
+		if state != last {
+			// Replace any change the main loop hasn't consumed yet
+			select {
+			case <-sessionChan:
+			default:
+			}
+			sessionChan <- state
+			last = state
+		}
+
+		time.Sleep(sessionPollInterval)
+	}
+}
+
+// recordMetrics stores the system usage of the last update in the metrics history
+// Only values that were measured are stored (the first update has no CPU usage yet)
+func (tui *InteractiveTUI) recordMetrics() {
+	if tui.metrics == nil {
+		return
+	}
+
+	values := make(map[string]float64)
+	if tui.system.HasCPU {
+		values[history.SeriesCPU] = tui.system.CPUPercent
+	}
+	if tui.system.HasRAM {
+		values[history.SeriesRAM] = tui.system.RAM.Percent
+	}
+	if tui.system.HasLoad && tui.system.Load.HasLoad {
+		values[history.SeriesLoad] = tui.system.Load.Load1
+	}
+	if tui.system.CPUTemp > 0 {
+		values[history.SeriesTemp] = float64(tui.system.CPUTemp)
+	}
+
+	if err := tui.metrics.Record(history.MetricSample{Time: time.Now(), Values: values}); err != nil {
+		common.WarnEvery("history", time.Minute, "error writing the history", "err", err)
+	}
+}
+
 // updateProcesses updates the process list and sorts according to current mode
+// The selected process stays selected even if its position in the list changes
 func (tui *InteractiveTUI) updateProcesses() {
-	// Collect all processes
-	processes, err := common.CollectAllProcessInfo()
+	// Remember which process is selected
+	selectedPID := int32(-1)
+	if tui.selectedIndex >= 0 && tui.selectedIndex < len(tui.processes) {
+		selectedPID = tui.processes[tui.selectedIndex].PID
+	}
+
+	// The collector reuses processes between refreshes, so CPU usage is measured since the last refresh
+	processes, collection, err := tui.collector.Collect()
 	if err != nil {
+		common.WarnEvery("processes", time.Minute, "error collecting processes", "err", err)
 		return
 	}
+	tui.collection = collection
+	tui.system = sampleSystemStats()
+	tui.throttle = tui.throttleSampler.Sample(tui.system.CPUPercent)
+	system := tui.system
+	tui.alertSystem.Store(&system)
+	tui.graphs.record(tui.system)
+	tui.recordMetrics()
+	tui.selectedCgroup.pid = -1 // Usage changed: re-read the selected process's cgroup
+	tui.details.pid = -1
+
+	// Flag processes that were renamed or keep respawning
+	tui.tracker.Observe(processes, time.Now())
+
+	// Find the containers only when they are shown, since it reads the cgroup of new processes
+	if tui.showContainer || tui.sortMode == SortByContainer {
+		tui.containers = tui.resolver.Resolve(processes)
+	}
+
+	// Read the swap usage only when it is shown, since it reads the status file of every process
+	if tui.showSwap || tui.sortMode == SortBySwap {
+		tui.swap = make(map[int32]uint64, len(processes))
+		for _, p := range processes {
+			if swap, err := ram.GetProcessSwap(p.PID); err == nil {
+				tui.swap[p.PID] = swap
+			}
+		}
+	}
+
+	// Same for the disk I/O, which reads the io file of every process
+	// The first refresh after enabling it has no rates yet, since they need two readings
+	if tui.showIO || tui.sortMode == SortByIO {
+		tui.diskIO = tui.ioSampler.Sample(processes)
+	}
+
+	// Same for the power, attributed by the CPU usage since the previous refresh
+	if tui.showEnergy || tui.sortMode == SortByEnergy {
+		tui.energy, tui.packageWatts, tui.energyErr = tui.energySampler.Sample(processes)
+	}
+
+	// Same for the command lines, which read the cmdline file of every process
+	if tui.showCmdline {
+		tui.cmdlines = make(map[int32]string, len(processes))
+		for _, p := range processes {
+			if cmdline := common.GetCmdline(p.PID); cmdline != "" {
+				tui.cmdlines[p.PID] = cmdline
+			}
+		}
+	}
+
+	// Sample every tab (including the network throughput of the network pane),
+	// so their history graphs are complete when they're opened
+	tui.allProcesses = processes
+	for _, tab := range tui.tabs {
+		tab.sample(tui)
+	}
+	tui.pruneMarks()
+
+	// Merge the processes of each application, after every per-process value was read
+	if tui.groupApps {
+		processes = tui.groupApplications(processes)
+	}
 
 	// Sort according to selected mode
 	tui.sortProcesses(processes)
@@ -143,6 +515,14 @@ func (tui *InteractiveTUI) updateProcesses() {
 	// Update the list
 	tui.processes = processes
 
+	// Follow the selected process to its new position (if it still exists)
+	for i, p := range tui.processes {
+		if p.PID == selectedPID {
+			tui.selectedIndex = i
+			break
+		}
+	}
+
 	// Adjust selected index if necessary
 	if tui.selectedIndex >= len(tui.processes) {
 		tui.selectedIndex = len(tui.processes) - 1
@@ -152,6 +532,47 @@ func (tui *InteractiveTUI) updateProcesses() {
 	}
 }
 
+// groupApplications replaces the processes with one row per application
+// The swap, disk I/O and power read on this refresh are replaced by the totals of each
+// application, stored under the PID of its main process
+//
+// Returns: one ProcessInfo per application, with the totals of its processes
+func (tui *InteractiveTUI) groupApplications(processes []common.ProcessInfo) []common.ProcessInfo {
+	apps := common.GroupByApplication(processes)
+	rows := make([]common.ProcessInfo, len(apps))
+	tui.appCounts = make(map[int32]int, len(apps))
+	for i, app := range apps {
+		rows[i] = app.ProcessInfo
+		tui.appCounts[app.PID] = app.Count
+
+		var swap uint64
+		var io disk.IORate
+		var energy power.ProcessEnergy
+		hasEnergy := false
+		for _, pid := range app.PIDs {
+			swap += tui.swap[pid]
+			io.ReadBytesPerSec += tui.diskIO[pid].ReadBytesPerSec
+			io.WriteBytesPerSec += tui.diskIO[pid].WriteBytesPerSec
+			if e, ok := tui.energy[pid]; ok {
+				energy.Watts += e.Watts
+				energy.Joules += e.Joules
+				hasEnergy = true
+			}
+		}
+		if tui.showSwap || tui.sortMode == SortBySwap {
+			tui.swap[app.PID] = swap
+		}
+		if tui.showIO || tui.sortMode == SortByIO {
+			tui.diskIO[app.PID] = io
+		}
+		if hasEnergy && (tui.showEnergy || tui.sortMode == SortByEnergy) {
+			energy.Process = app.ProcessInfo
+			tui.energy[app.PID] = energy
+		}
+	}
+	return rows
+}
+
 // sortProcesses sorts the process list according to current mode
 func (tui *InteractiveTUI) sortProcesses(processes []common.ProcessInfo) {
 	switch tui.sortMode {
@@ -167,94 +588,311 @@ func (tui *InteractiveTUI) sortProcesses(processes []common.ProcessInfo) {
 		sort.Slice(processes, func(i, j int) bool {
 			return processes[i].PID < processes[j].PID
 		})
+	case SortByName:
+		sort.Slice(processes, func(i, j int) bool {
+			return strings.ToLower(processes[i].Name) < strings.ToLower(processes[j].Name)
+		})
+	case SortByUser:
+		sort.Slice(processes, func(i, j int) bool {
+			if processes[i].Username != processes[j].Username {
+				return processes[i].Username < processes[j].Username
+			}
+			return processes[i].CPUPercentage > processes[j].CPUPercentage
+		})
+	case SortByMemory:
+		sort.Slice(processes, func(i, j int) bool {
+			return processes[i].RAMBytes > processes[j].RAMBytes
+		})
+	case SortByThreads:
+		sort.Slice(processes, func(i, j int) bool {
+			return processes[i].NumThreads > processes[j].NumThreads
+		})
+	case SortByTime:
+		// Oldest processes (earliest start time) have been running the longest
+		sort.Slice(processes, func(i, j int) bool {
+			return processes[i].CreateTime < processes[j].CreateTime
+		})
+	case SortByCPUTime:
+		// Total CPU time shows the processes that used the most overall, even if idle now
+		sort.Slice(processes, func(i, j int) bool {
+			return processes[i].CPUTime > processes[j].CPUTime
+		})
+	case SortByContainer:
+		// Containers first (grouped by name), then host processes; by CPU within each group
+		sort.Slice(processes, func(i, j int) bool {
+			ci, cj := tui.containers[processes[i].PID], tui.containers[processes[j].PID]
+			if ci != cj {
+				if ci == "" || cj == "" {
+					return cj == ""
+				}
+				return ci < cj
+			}
+			return processes[i].CPUPercentage > processes[j].CPUPercentage
+		})
+	case SortBySwap:
+		sort.Slice(processes, func(i, j int) bool {
+			return tui.swap[processes[i].PID] > tui.swap[processes[j].PID]
+		})
+	case SortByIO:
+		sort.Slice(processes, func(i, j int) bool {
+			return tui.diskIO[processes[i].PID].Total() > tui.diskIO[processes[j].PID].Total()
+		})
+	case SortByEnergy:
+		sort.Slice(processes, func(i, j int) bool {
+			return tui.energy[processes[i].PID].Watts > tui.energy[processes[j].PID].Watts
+		})
+	}
+
+	// Pinned processes go first, in the order of the sort mode
+	if len(tui.pinned) > 0 {
+		sort.SliceStable(processes, func(i, j int) bool {
+			return tui.pinned[processes[i].Name] && !tui.pinned[processes[j].Name]
+		})
 	}
 }
 
 // render renders the entire interface on screen
+// The frame is built in memory and only its rows that changed are written (see screen)
 func (tui *InteractiveTUI) render() {
-	// Clear screen
-	fmt.Print(clearScreen)
-	fmt.Printf(moveCursor, 1, 1)
+	var frame strings.Builder
 
 	// Render header
-	tui.renderHeader()
+	tui.renderHeader(&frame)
+
+	// Render the tab bar
+	tui.renderTabBar(&frame)
 
 	// Render info bar
-	tui.renderInfoBar()
+	tui.renderInfoBar(&frame)
+
+	// Render usage graphs (if shown)
+	tui.renderGraphs(&frame)
 
-	// Render table header
-	tui.renderTableHeader()
+	// Render active alerts (if any)
+	tui.renderAlerts(&frame)
 
-	// Render process list
-	tui.renderProcessList()
+	// Render the tab shown (the process table on the Processes tab), using at least as many
+	// lines as the smallest process table
+	for _, row := range tui.currentTab().rows(tui, max(tui.contentLines(), tableHeaderLines+minVisibleRows)) {
+		fmt.Fprintln(&frame, row)
+	}
 
 	// Render footer with controls
-	tui.renderFooter()
+	tui.renderFooter(&frame)
+
+	rows := strings.Split(strings.TrimSuffix(frame.String(), "\n"), "\n")
+	tui.screen.draw(os.Stdout, rows, tui.width, tui.height)
 }
 
 // renderHeader renders the header with logo
-func (tui *InteractiveTUI) renderHeader() {
-	fmt.Println(cyanColor + boldColor + "╔════════════════════════════════════════════════════════════════════════════════════════════════════════════════════╗" + resetColor)
-	fmt.Println(cyanColor + boldColor + "║" + greenColor + "    ██████╗  ██████╗ ███╗   ███╗" + cyanColor + "                    GOMONITOR - Interactive Process Manager                    " + "║" + resetColor)
-	fmt.Println(cyanColor + boldColor + "║" + greenColor + "   ██╔════╝ ██╔═══██╗████╗ ████║" + cyanColor + "                     Real-time System Resource Monitor                         " + "║" + resetColor)
-	fmt.Println(cyanColor + boldColor + "║" + greenColor + "   ██║  ███╗██║   ██║██╔████╔██║" + cyanColor + "                                                                               " + "║" + resetColor)
-	fmt.Println(cyanColor + boldColor + "║" + greenColor + "   ██║   ██║██║   ██║██║╚██╔╝██║" + cyanColor + "                                                                               " + "║" + resetColor)
-	fmt.Println(cyanColor + boldColor + "║" + greenColor + "   ╚██████╔╝╚██████╔╝██║ ╚═╝ ██║" + cyanColor + "                                                                               " + "║" + resetColor)
-	fmt.Println(cyanColor + boldColor + "║" + greenColor + "    ╚═════╝  ╚═════╝ ╚═╝     ╚═╝" + cyanColor + "                                                                               " + "║" + resetColor)
-	fmt.Println(cyanColor + boldColor + "╚════════════════════════════════════════════════════════════════════════════════════════════════════════════════════╝" + resetColor)
-	fmt.Println()
+// Falls back to a single-line header when the terminal is too small for the logo
+func (tui *InteractiveTUI) renderHeader(w io.Writer) {
+	if !tui.useFullHeader() {
+		title := " GOMONITOR - Interactive Process Manager "
+		padding := tui.width - len(title) - 1
+		if padding < 0 {
+			padding = 0
+		}
+		fmt.Fprintln(w, headerBarStyle+title+strings.Repeat(" ", padding)+resetColor)
+		fmt.Fprintln(w)
+		return
+	}
+
+	fmt.Fprintln(w, cyanColor+boldColor+"╔════════════════════════════════════════════════════════════════════════════════════════════════════════════════════╗"+resetColor)
+	fmt.Fprintln(w, cyanColor+boldColor+"║"+greenColor+"    ██████╗  ██████╗ ███╗   ███╗"+cyanColor+"                    GOMONITOR - Interactive Process Manager                    "+"║"+resetColor)
+	fmt.Fprintln(w, cyanColor+boldColor+"║"+greenColor+"   ██╔════╝ ██╔═══██╗████╗ ████║"+cyanColor+"                     Real-time System Resource Monitor                         "+"║"+resetColor)
+	fmt.Fprintln(w, cyanColor+boldColor+"║"+greenColor+"   ██║  ███╗██║   ██║██╔████╔██║"+cyanColor+"                                                                               "+"║"+resetColor)
+	fmt.Fprintln(w, cyanColor+boldColor+"║"+greenColor+"   ██║   ██║██║   ██║██║╚██╔╝██║"+cyanColor+"                                                                               "+"║"+resetColor)
+	fmt.Fprintln(w, cyanColor+boldColor+"║"+greenColor+"   ╚██████╔╝╚██████╔╝██║ ╚═╝ ██║"+cyanColor+"                                                                               "+"║"+resetColor)
+	fmt.Fprintln(w, cyanColor+boldColor+"║"+greenColor+"    ╚═════╝  ╚═════╝ ╚═╝     ╚═╝"+cyanColor+"                                                                               "+"║"+resetColor)
+	fmt.Fprintln(w, cyanColor+boldColor+"╚════════════════════════════════════════════════════════════════════════════════════════════════════════════════════╝"+resetColor)
+	fmt.Fprintln(w)
 }
 
 // renderInfoBar renders the bar with system information
-func (tui *InteractiveTUI) renderInfoBar() {
-	// Calculate total statistics
-	var totalCPU float64
-	var totalRAM float32
-	processCount := len(tui.processes)
+// Items are wrapped into several rows on narrow terminals
+func (tui *InteractiveTUI) renderInfoBar(w io.Writer) {
+	for _, row := range tui.infoBarRows() {
+		fmt.Fprintln(w, row)
+	}
+	fmt.Fprintln(w)
+}
 
-	for _, p := range tui.processes {
-		totalCPU += p.CPUPercentage
-		totalRAM += p.RAMPercentage
+// renderAlerts renders the warnings above the process table, one per line
+func (tui *InteractiveTUI) renderAlerts(w io.Writer) {
+	rows := tui.alertRows()
+	if len(rows) == 0 {
+		return
 	}
 
-	// Get total memory information
-	totalMemory, err := common.GetSystemMemoryTotal()
-	totalMemoryGB := 0.0
-	if err == nil {
-		totalMemoryGB = float64(totalMemory) / 1024 / 1024 / 1024
+	for _, row := range rows {
+		fmt.Fprintln(w, row)
 	}
+	fmt.Fprintln(w)
+}
 
-	// Current sort mode
-	sortModeStr := ""
-	switch tui.sortMode {
-	case SortByCPU:
-		sortModeStr = yellowColor + "CPU ▼" + resetColor
-	case SortByRAM:
-		sortModeStr = yellowColor + "RAM ▼" + resetColor
-	case SortByPID:
-		sortModeStr = yellowColor + "PID ▲" + resetColor
+// alertRows returns the warnings shown above the process table: CPU throttling, then the
+// alerts that are currently firing (at most maxAlertLines, with a count of the remaining ones)
+func (tui *InteractiveTUI) alertRows() []string {
+	var rows []string
+	if warning := tui.throttle.Warning(); warning != "" {
+		rows = append(rows, fmt.Sprintf("  %s%s⚠ %s%s", yellowColor, boldColor, warning, resetColor))
+	}
+	for i, alert := range tui.activeAlerts {
+		if i == maxAlertLines-1 && len(tui.activeAlerts) > maxAlertLines {
+			rows = append(rows, fmt.Sprintf("  %s%s⚠ ... and %d more alerts%s", redColor, boldColor, len(tui.activeAlerts)-i, resetColor))
+			break
+		}
+		rows = append(rows, fmt.Sprintf("  %s%s⚠ %s%s", redColor, boldColor, alert.String(), resetColor))
+	}
+	return rows
+}
+
+// watchAlerts samples the monitored metrics periodically and evaluates the alert rules
+// Runs in the background so slow collectors (e.g. nvidia-smi) don't block the interface
+func (tui *InteractiveTUI) watchAlerts(alertChan chan []alerts.Alert) {
+	defer tui.terminal.recoverPanic()
+	rules, err := alerts.ConfiguredRules(tui.config.Alerts)
+	if err != nil {
+		common.Logger().Error("error loading the alert rules", "err", err)
+	}
+	engine := alerts.NewEngine(rules)
+	hadAlerts := false
+
+	// Record fired alerts and notable events when the history store is enabled
+	var recorder *history.Recorder
+	if tui.config.HistoryEnabled {
+		if path, err := history.DefaultPath(); err == nil {
+			recorder = history.NewRecorder(history.NewStore(path))
+			if err := recorder.RecordBoot(); err != nil {
+				common.WarnEvery("history", time.Minute, "error writing the history", "err", err)
+			}
+		}
+	}
+
+	for tui.running.Load() {
+		var samples []alerts.Sample
+		if !tui.pauseGPU.Load() && len(hwcache.Get().GPUs) > 0 {
+			if allStats, err := gpu.GetAllGPUStats(context.Background()); err == nil {
+				samples = append(samples, alerts.GPUSamples(allStats)...)
+			}
+		}
+		if devices, err := disk.GetAllStorageDevices(tui.config.Disk.Filter()); err == nil {
+			samples = append(samples, alerts.DiskSamples(devices)...)
+			if fullest, ok := health.FullestDisk(devices); ok {
+				tui.fullestDisk.Store(&fullest)
+			}
+		}
+		samples = append(samples, alerts.ProcessSamples(tui.tracker)...)
+
+		// CPU and RAM come from the last refresh, since sampling the CPU here would
+		// reset the interval of the info bar CPU usage
+		if system := tui.alertSystem.Load(); system != nil {
+			if system.HasCPU {
+				samples = append(samples, alerts.CPUSample(system.CPUPercent))
+			}
+			if system.HasRAM {
+				samples = append(samples, alerts.RAMSample(system.RAM))
+			}
+		}
+
+		now := time.Now()
+		firing := engine.Evaluate(samples, now)
+		if recorder != nil {
+			if err := recorder.Observe(firing, now); err != nil {
+				common.WarnEvery("history", time.Minute, "error writing the history", "err", err)
+			}
+		}
+
+		// Only notify the main loop when there is something to show or clear
+		if len(firing) > 0 || hadAlerts {
+			// Replace any alert update the main loop hasn't consumed yet
+			select {
+			case <-alertChan:
+			default:
+			}
+			alertChan <- firing
+		}
+		hadAlerts = len(firing) > 0
+
+		time.Sleep(alertInterval)
 	}
+}
 
-	fmt.Printf("  %s%sProcesses:%s %d  ", boldColor, cyanColor, resetColor, processCount)
-	fmt.Printf("%s%sTotal CPU:%s %.2f%%  ", boldColor, greenColor, resetColor, totalCPU)
-	fmt.Printf("%s%sTotal RAM:%s %.2f%% (%.2f GB)  ", boldColor, magentaColor, resetColor, totalRAM, totalMemoryGB)
-	fmt.Printf("%s%sSort by:%s %s", boldColor, whiteColor, resetColor, sortModeStr)
-	fmt.Println()
-	fmt.Println()
+// tableColumn is a column of the process table header
+type tableColumn struct {
+	label string   // Header text (e.g. "CPU %")
+	width int      // Width of the column
+	right bool     // Right-aligned (numbers)
+	sort  SortMode // Sort mode selected by clicking the header (noSort if there's none)
+}
+
+// noSort marks the columns whose header can't be clicked to sort (e.g. the state)
+const noSort SortMode = -1
+
+// tableColumns returns the columns of the process table, in order, separated by one space
+// after a two-space margin; optional columns are only included while shown
+func (tui *InteractiveTUI) tableColumns() []tableColumn {
+	// The name column shows how far it is scrolled (e.g. "COMMAND +16")
+	nameLabel := "NAME"
+	if tui.showCmdline {
+		nameLabel = "COMMAND"
+	}
+	if tui.nameScroll > 0 {
+		nameLabel += fmt.Sprintf(" +%d", tui.nameScroll)
+	}
+
+	columns := []tableColumn{
+		{"PID", 8, false, SortByPID},
+		{"USER", userColumnWidth, false, SortByUser},
+		{nameLabel, tui.nameColumnWidth(), false, SortByName},
+		{"S", 1, false, noSort},
+		{"NI", 3, true, noSort},
+	}
+	if tui.showGroups {
+		columns = append(columns, tableColumn{"PGID", groupIDWidth, true, noSort}, tableColumn{"SID", groupIDWidth, true, noSort})
+	}
+	if tui.showContainer {
+		columns = append(columns, tableColumn{"CONTAINER", containerWidth, false, SortByContainer})
+	}
+	if tui.showCore {
+		columns = append(columns, tableColumn{"CORE", 5, true, noSort})
+	}
+	columns = append(columns,
+		tableColumn{"CPU %", 10, true, SortByCPU},
+		tableColumn{"RAM %", 10, true, SortByRAM},
+		tableColumn{"MEMORY", 15, true, SortByMemory})
+	if tui.showSwap {
+		columns = append(columns, tableColumn{"SWAP", swapWidth, true, SortBySwap})
+	}
+	if tui.showIO {
+		columns = append(columns, tableColumn{"DISK I/O", ioWidth, true, SortByIO})
+	}
+	if tui.showEnergy {
+		columns = append(columns, tableColumn{"POWER", powerWidth, true, SortByEnergy})
+	}
+	if tui.showCPUTime() {
+		columns = append(columns, tableColumn{"TIME+", cpuTimeWidth, true, SortByCPUTime})
+	}
+	return columns
 }
 
 // renderTableHeader renders the process table header
-func (tui *InteractiveTUI) renderTableHeader() {
-	fmt.Print(boldColor)
-	fmt.Printf("  %-8s %-35s %10s %10s %15s\n", "PID", "NAME", "CPU %", "RAM %", "MEMORY")
-	fmt.Print(resetColor)
-	fmt.Println("  " + "─────────────────────────────────────────────────────────────────────────────────────────────────────────────────")
+func (tui *InteractiveTUI) renderTableHeader(w io.Writer) {
+	fmt.Fprint(w, boldColor+" ")
+	for _, column := range tui.tableColumns() {
+		fmt.Fprint(w, " "+common.PadString(column.label, column.width, column.right))
+	}
+	fmt.Fprintln(w)
+	fmt.Fprint(w, resetColor)
+	fmt.Fprintln(w, tui.separatorLine(tui.tableWidth()))
 }
 
 // renderProcessList renders the process list with scroll
-func (tui *InteractiveTUI) renderProcessList() {
+func (tui *InteractiveTUI) renderProcessList(w io.Writer) {
 	// Determine how many lines we can show (height - headers - footer)
-	maxLines := 20
+	maxLines := tui.visibleRows()
+	nameWidth := tui.nameColumnWidth()
 
 	// Adjust scroll offset if necessary
 	if tui.selectedIndex < tui.scrollOffset {
@@ -272,27 +910,64 @@ func (tui *InteractiveTUI) renderProcessList() {
 		// Check if this process is selected
 		isSelected := index == tui.selectedIndex
 
-		// Apply selection style
+		// Apply selection style, or highlight processes with unusual lifecycle, then pinned ones
+		rowColor := processRowColor(p)
+		if rowColor == "" && tui.pinned[p.Name] {
+			rowColor = pinnedStyle
+		}
 		if isSelected {
-			fmt.Print(bgBlue + whiteColor + boldColor)
+			fmt.Fprint(w, selectionStyle)
+		} else if rowColor != "" {
+			fmt.Fprint(w, rowColor)
 		}
 
 		// Format memory
 		memoryStr := common.FormatBytes(p.RAMBytes)
 
-		// Truncate name if necessary
-		name := p.Name
-		if len(name) > 35 {
-			name = name[:32] + "..."
-		}
+		// Show the part of the name (or command line) the column is scrolled to
+		name := scrollText(tui.nameText(p), tui.nameScroll, nameWidth)
 
-		// Print process line
-		fmt.Printf("  %-8d %-35s %9.2f%% %9.2f%% %15s", p.PID, name, p.CPUPercentage, p.RAMPercentage, memoryStr)
+		// Print process line, with a marker in the margin if it's marked for the bulk actions
+		marker := " "
+		if tui.isMarked(p) {
+			marker = markerSymbol
+		}
+		fmt.Fprintf(w, "%s %-8d %s %s %s %3d ", marker, p.PID, common.FitString(p.Username, userColumnWidth), name, p.State, p.Nice)
+		if tui.showGroups {
+			fmt.Fprintf(w, "%*s %*s ", groupIDWidth, common.FormatID(p.PGID), groupIDWidth, common.FormatID(p.SID))
+		}
+		if tui.showContainer {
+			containerName := tui.containers[p.PID]
+			if containerName == "" {
+				containerName = "-"
+			}
+			fmt.Fprintf(w, "%s ", common.FitString(containerName, containerWidth))
+		}
+		if tui.showCore {
+			fmt.Fprintf(w, "%5s ", common.FormatCore(p.LastCPU))
+		}
+		fmt.Fprintf(w, "%9.2f%% %9.2f%% %15s", p.CPUPercentage, p.RAMPercentage, memoryStr)
+		if tui.showSwap {
+			fmt.Fprintf(w, " %*s", swapWidth, common.FormatBytes(tui.swap[p.PID]))
+		}
+		if tui.showIO {
+			fmt.Fprintf(w, " %*s", ioWidth, common.FormatBytes(uint64(tui.diskIO[p.PID].Total()))+"/s")
+		}
+		if tui.showEnergy {
+			watts := "-"
+			if e, ok := tui.energy[p.PID]; ok {
+				watts = power.FormatWatts(e.Watts)
+			}
+			fmt.Fprintf(w, " %*s", powerWidth, watts)
+		}
+		if tui.showCPUTime() {
+			fmt.Fprintf(w, " %*s", cpuTimeWidth, common.FormatCPUTime(p.CPUTime))
+		}
 
-		if isSelected {
-			fmt.Print(resetColor)
+		if isSelected || rowColor != "" {
+			fmt.Fprint(w, resetColor)
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
 	}
 
 	// Fill empty lines if necessary
@@ -301,67 +976,425 @@ func (tui *InteractiveTUI) renderProcessList() {
 		visibleCount = len(tui.processes) - tui.scrollOffset
 	}
 	for i := visibleCount; i < maxLines; i++ {
-		fmt.Println()
+		fmt.Fprintln(w)
+	}
+}
+
+// showCPUTime checks if the TIME+ column (CPU time used since start) is shown
+// It's shown while sorting by it, since the other sort modes don't need it
+func (tui *InteractiveTUI) showCPUTime() bool {
+	return tui.sortMode == SortByCPUTime
+}
+
+// processRowColor returns the highlight color of a process row
+// Crash looping processes are red, renamed processes magenta, zombies yellow, processes
+// in uninterruptible sleep (stuck on I/O) blue and stopped processes cyan (empty if none applies)
+func processRowColor(p common.ProcessInfo) string {
+	switch {
+	case p.Flags&common.FlagRespawning != 0:
+		return redColor
+	case p.Flags&common.FlagRenamed != 0:
+		return magentaColor
+	case p.State == common.StateZombie:
+		return yellowColor
+	case p.State == common.StateDiskSleep:
+		return blueColor
+	case p.State == common.StateStopped:
+		return cyanColor
+	default:
+		return ""
 	}
 }
 
 // renderFooter renders the footer with control instructions
-func (tui *InteractiveTUI) renderFooter() {
-	fmt.Println()
-	fmt.Println("  " + "─────────────────────────────────────────────────────────────────────────────────────────────────────────────────")
-	fmt.Printf("  %s[↑/↓]%s Navigate  ", cyanColor+boldColor, resetColor)
-	fmt.Printf("%s[F5/R]%s Refresh  ", yellowColor+boldColor, resetColor)
-	fmt.Printf("%s[C]%s CPU  ", greenColor+boldColor, resetColor)
-	fmt.Printf("%s[M]%s RAM  ", magentaColor+boldColor, resetColor)
-	fmt.Printf("%s[P]%s PID  ", yellowColor+boldColor, resetColor)
-	fmt.Printf("%s[D/DEL]%s Kill Process  ", redColor+boldColor, resetColor)
-	fmt.Printf("%s[Q/ESC]%s Quit", whiteColor+boldColor, resetColor)
-	fmt.Println()
+// Key hints wrap to several rows on narrow terminals
+func (tui *InteractiveTUI) renderFooter(w io.Writer) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, tui.separatorLine(tui.width))
+	for _, row := range tui.footerRows() {
+		fmt.Fprintln(w, row)
+	}
 }
 
 // handleKey processes a pressed key
-func (tui *InteractiveTUI) handleKey(key byte) {
-	switch key {
-	case 'q', 'Q', 27: // q, Q or ESC
-		tui.running = false
+// Keys are looked up in the keymap, so remapped and disabled keys are handled the same way
+// While a group kill or a bulk action waits for confirmation, the next key answers it instead
+//
+// Parameters:
+//   - key: key name (from decodeKey)
+func (tui *InteractiveTUI) handleKey(key string) {
+	if tui.confirmGroup != 0 {
+		tui.answerGroupKill(key == "y")
+		tui.render()
+		return
+	}
+	if tui.confirmMarked != "" {
+		tui.answerMarkedAction(key == "y")
+		tui.render()
+		return
+	}
+	if tui.tab == tabConnections && tui.connectionsView().editing {
+		tui.editConnectionFilter(key)
+		tui.render()
+		return
+	}
 
-	case 65: // Up arrow
-		if tui.selectedIndex > 0 {
-			tui.selectedIndex--
-		}
+	switch tui.keymap[key] {
+	case config.ActionQuit:
+		tui.running.Store(false)
+
+	case config.ActionUp:
+		tui.scroll(-1)
 		tui.render()
 
-	case 66: // Down arrow
-		if tui.selectedIndex < len(tui.processes)-1 {
-			tui.selectedIndex++
-		}
+	case config.ActionDown:
+		tui.scroll(1)
 		tui.render()
 
-	case 'r', 'R': // Refresh
+	case config.ActionRefresh:
 		tui.updateProcesses()
 		tui.render()
 
-	case 'c', 'C': // Sort by CPU
+	case config.ActionSortCPU:
 		tui.sortMode = SortByCPU
 		tui.updateProcesses()
 		tui.render()
 
-	case 'm', 'M': // Sort by RAM (Memory)
+	case config.ActionSortRAM:
 		tui.sortMode = SortByRAM
 		tui.updateProcesses()
 		tui.render()
 
-	case 'p', 'P': // Sort by PID
+	case config.ActionSortPID:
 		tui.sortMode = SortByPID
 		tui.updateProcesses()
 		tui.render()
 
-	case 127, 'd', 'D': // Delete or D - kill process
-		tui.killSelectedProcess()
+	case config.ActionSortNext: // Cycle through all sort modes
+		if tui.tab == tabConnections {
+			t := tui.connectionsView()
+			t.sortBy = (t.sortBy + 1) % connectionSortCount
+			tui.render()
+			break
+		}
+		tui.sortMode = tui.sortMode.next()
+		tui.updateProcesses()
+		tui.render()
+
+	case config.ActionFilter:
+		if tui.tab == tabConnections {
+			tui.connectionsView().editing = true
+		} else {
+			tui.notice = "Filtering only works on the Connections tab"
+		}
+		tui.render()
+
+	case config.ActionPause: // Pause/resume automatic refresh
+		tui.paused = !tui.paused
+		tui.render()
+
+	case config.ActionToggleCore:
+		tui.showCore = !tui.showCore
+		tui.render()
+
+	case config.ActionToggleGroups:
+		tui.showGroups = !tui.showGroups
+		tui.render()
+
+	case config.ActionToggleApps:
+		tui.groupApps = !tui.groupApps
+		tui.appCounts = nil
+		tui.updateProcesses()
+		tui.render()
+
+	case config.ActionToggleContainer:
+		tui.showContainer = !tui.showContainer
+		tui.updateProcesses()
+		tui.render()
+
+	case config.ActionToggleSwap:
+		tui.showSwap = !tui.showSwap
+		tui.updateProcesses()
+		tui.render()
+
+	case config.ActionToggleIO:
+		tui.showIO = !tui.showIO
+		tui.updateProcesses()
+		tui.render()
+
+	case config.ActionToggleEnergy:
+		tui.showEnergy = !tui.showEnergy
+		tui.updateProcesses()
+		tui.render()
+
+	case config.ActionToggleGraphs:
+		tui.showGraphs = !tui.showGraphs
+		tui.render()
+
+	case config.ActionToggleCmdline:
+		tui.showCmdline = !tui.showCmdline
+		tui.nameScroll = 0
+		tui.updateProcesses()
+		tui.render()
+
+	case config.ActionScrollLeft:
+		tui.nameScroll = max(tui.nameScroll-nameScrollStep, 0)
+		tui.render()
+
+	case config.ActionScrollRight:
+		tui.nameScroll = min(tui.nameScroll+nameScrollStep, tui.maxNameScroll())
+		tui.render()
+
+	case config.ActionSplit: // Cycle the second pane: network, process details, none
+		tui.pane = tui.pane.next()
+		tui.paneScroll = 0
+		if tui.pane == paneNone {
+			tui.paneFocused = false
+		}
+		tui.updateProcesses()
+		tui.render()
+
+	case config.ActionFocus:
+		tui.paneFocused = tui.splitActive() && !tui.paneFocused
+		tui.render()
+
+	case config.ActionNextTab:
+		tui.switchTab(tui.tab + 1)
+		tui.render()
+
+	case config.ActionPrevTab:
+		tui.switchTab(tui.tab - 1)
+		tui.render()
+
+	case config.ActionTabProcesses, config.ActionTabCPU, config.ActionTabMemory,
+		config.ActionTabDisk, config.ActionTabNetwork, config.ActionTabGPU, config.ActionTabSensors,
+		config.ActionTabConnections:
+		for kind, tab := range tui.tabs {
+			if tab.action() == tui.keymap[key] {
+				tui.switchTab(tabKind(kind))
+			}
+		}
+		tui.render()
+
+	case config.ActionThrottle:
+		if tui.onProcessesTab() {
+			tui.throttleSelectedProcess()
+		}
+		tui.render()
+
+	case config.ActionNiceUp:
+		if tui.tab == tabSensors {
+			tui.changeFanLevel(fanLevelStep)
+		} else if tui.onProcessesTab() && len(tui.marked) > 0 {
+			tui.askMarkedAction(config.ActionNiceUp)
+		} else if tui.tab == tabProcesses {
+			tui.reniceSelectedProcess(1)
+		}
+		tui.render()
+
+	case config.ActionNiceDown:
+		if tui.tab == tabSensors {
+			tui.changeFanLevel(-fanLevelStep)
+		} else if tui.onProcessesTab() && len(tui.marked) > 0 {
+			tui.askMarkedAction(config.ActionNiceDown)
+		} else if tui.tab == tabProcesses {
+			tui.reniceSelectedProcess(-1)
+		}
+		tui.render()
+
+	case config.ActionKill:
+		if tui.tab == tabConnections {
+			tui.killConnectionOwner()
+		} else if tui.onProcessesTab() && len(tui.marked) > 0 {
+			tui.askMarkedAction(config.ActionKill)
+		} else if tui.tab == tabProcesses {
+			tui.killSelectedProcess()
+		}
+		tui.render()
+
+	case config.ActionMark:
+		if tui.onProcessesTab() {
+			tui.toggleMarkSelectedProcess()
+		}
+		tui.render()
+
+	case config.ActionCopy:
+		if tui.onProcessesTab() {
+			tui.copySelectedProcess()
+		}
+		tui.render()
+
+	case config.ActionPin:
+		if tui.onProcessesTab() {
+			tui.togglePinSelectedProcess()
+		}
+		tui.render()
+
+	case config.ActionStop:
+		if tui.onProcessesTab() {
+			tui.toggleStopSelectedProcess()
+		}
+		tui.render()
+
+	case config.ActionKillGroup:
+		if tui.onProcessesTab() {
+			tui.askGroupKill()
+		}
+		tui.render()
+
+	case config.ActionFanAuto:
+		if tui.tab == tabSensors {
+			tui.restoreSelectedFan()
+		}
 		tui.render()
 	}
 }
 
+// scroll moves the selection of the tab shown, or scrolls it when nothing can be selected
+// On the Processes tab, the second pane scrolls instead while it has the focus
+//
+// Parameters:
+//   - delta: rows to move (negative: up)
+func (tui *InteractiveTUI) scroll(delta int) {
+	switch {
+	case tui.tab == tabSensors && tui.config.FanControl:
+		tui.sensorsView().selected += delta // Kept within the fans when rendering
+	case tui.tab == tabConnections:
+		tui.connectionsView().selected += delta // Kept within the connections when rendering
+	case tui.tab != tabProcesses:
+		tui.tabScroll += delta // Kept within the content when rendering
+	case tui.paneFocused && tui.splitActive():
+		tui.paneScroll += delta // Kept within the content when rendering
+	default:
+		tui.moveSelection(delta)
+	}
+}
+
+// moveSelection moves the selected process, stopping at the first and last ones
+//
+// Parameters:
+//   - delta: rows to move (negative: up)
+func (tui *InteractiveTUI) moveSelection(delta int) {
+	tui.selectedIndex = max(0, min(tui.selectedIndex+delta, len(tui.processes)-1))
+}
+
+// fail shows an error in the footer and logs it, so failed actions are kept in the log file
+//
+// Parameters:
+//   - message: error shown to the user
+func (tui *InteractiveTUI) fail(message string) {
+	tui.notice = redColor + message + resetColor
+	common.Logger().Error("TUI action failed", "message", message)
+}
+
+// togglePinSelectedProcess pins the name of the selected process, so every process with that
+// name is listed first whatever the sort mode, or unpins it
+// The pinned names are saved in the config file for the next sessions
+func (tui *InteractiveTUI) togglePinSelectedProcess() {
+	if tui.selectedIndex < 0 || tui.selectedIndex >= len(tui.processes) {
+		return
+	}
+
+	name := tui.processes[tui.selectedIndex].Name
+	tui.pinned[name] = !tui.pinned[name]
+	if !tui.pinned[name] {
+		delete(tui.pinned, name)
+	}
+
+	names := make([]string, 0, len(tui.pinned))
+	for pinned := range tui.pinned {
+		names = append(names, pinned)
+	}
+	sort.Strings(names)
+	tui.config.Pinned = names
+
+	if tui.pinned[name] {
+		tui.notice = name + " pinned to the top of the list"
+	} else {
+		tui.notice = name + " unpinned"
+	}
+	if err := config.SavePinned(names); err != nil {
+		tui.fail(fmt.Sprintf("%s (only for this session): %v", tui.notice, err))
+	}
+
+	// Move the process to its new position, keeping it selected
+	tui.updateProcesses()
+}
+
+// throttleSelectedProcess moves the selected process into a cgroup with the
+// CPU and memory limits of the throttle config, instead of killing it
+func (tui *InteractiveTUI) throttleSelectedProcess() {
+	if tui.selectedIndex < 0 || tui.selectedIndex >= len(tui.processes) {
+		return
+	}
+
+	selectedProcess := tui.processes[tui.selectedIndex]
+	quota := cgroup.Quota{
+		CPUCores:    tui.config.Throttle.CPU,
+		MemoryBytes: tui.config.Throttle.MemoryBytes(),
+	}
+
+	if _, err := cgroup.Throttle(selectedProcess.PID, quota); err != nil {
+		tui.fail(fmt.Sprintf("PID %d: %v", selectedProcess.PID, err))
+		return
+	}
+	tui.notice = fmt.Sprintf("PID %d (%s) limited to %s", selectedProcess.PID, selectedProcess.Name, quota)
+
+	// Read the new limits of the selected process on the next render
+	tui.selectedCgroup = selectedCgroup{pid: -1}
+}
+
+// reniceSelectedProcess changes the nice value of the selected process (setpriority)
+// Every thread is reniced, since on Linux setpriority only changes the thread it's given
+//
+// Parameters:
+//   - delta: change of the nice value (+1 lowers the priority, -1 raises it)
+func (tui *InteractiveTUI) reniceSelectedProcess(delta int) {
+	if tui.selectedIndex < 0 || tui.selectedIndex >= len(tui.processes) {
+		return
+	}
+
+	selectedProcess := &tui.processes[tui.selectedIndex]
+	nice := max(minNice, min(int(selectedProcess.Nice)+delta, maxNice))
+	if nice == int(selectedProcess.Nice) {
+		return
+	}
+
+	if err := setNice(selectedProcess.PID, nice); err != nil {
+		tui.fail(fmt.Sprintf("PID %d: %v", selectedProcess.PID, err))
+		return
+	}
+	tui.notice = fmt.Sprintf("PID %d (%s) nice %d → %d", selectedProcess.PID, selectedProcess.Name, selectedProcess.Nice, nice)
+	selectedProcess.Nice = int32(nice)
+}
+
+// setNice sets the nice value of every thread of a process
+// Falls back to the process itself if its threads can't be listed
+//
+// Returns: error if a thread couldn't be changed (permission denied is explained)
+func setNice(pid int32, nice int) error {
+	tids := []int{int(pid)}
+	if entries, err := os.ReadDir(paths.Proc(fmt.Sprint(pid), "task")); err == nil {
+		tids = tids[:0]
+		for _, entry := range entries {
+			if tid, err := strconv.Atoi(entry.Name()); err == nil {
+				tids = append(tids, tid)
+			}
+		}
+	}
+
+	for _, tid := range tids {
+		err := syscall.Setpriority(syscall.PRIO_PROCESS, tid, nice)
+		if errors.Is(err, syscall.EPERM) || errors.Is(err, syscall.EACCES) {
+			return fmt.Errorf("permission denied (raising the priority or changing other users' processes needs root)")
+		}
+		if err != nil && !errors.Is(err, syscall.ESRCH) { // ESRCH: the thread exited meanwhile
+			return fmt.Errorf("error setting nice value: %w", err)
+		}
+	}
+	return nil
+}
+
 // killSelectedProcess kills the selected process using the system's kill command
 func (tui *InteractiveTUI) killSelectedProcess() {
 	if tui.selectedIndex < 0 || tui.selectedIndex >= len(tui.processes) {
@@ -371,44 +1404,126 @@ func (tui *InteractiveTUI) killSelectedProcess() {
 	selectedProcess := tui.processes[tui.selectedIndex]
 	pid := selectedProcess.PID
 
-	// Use system's kill command to kill the process
-	// First try SIGTERM (15) for graceful termination
-	err := syscall.Kill(int(pid), syscall.SIGTERM)
+	if err := common.KillProcess(pid); err != nil {
+		common.Logger().Error("error killing process", "pid", pid, "err", err)
+	}
+
+	// Wait a bit and update the process list
+	time.Sleep(100 * time.Millisecond)
+	tui.updateProcesses()
+}
+
+// toggleStopSelectedProcess freezes the selected process (SIGSTOP), or lets a stopped one
+// continue (SIGCONT)
+// A stopped process keeps its memory but gets no CPU time, so a runaway job can be paused
+// and resumed later instead of killed
+func (tui *InteractiveTUI) toggleStopSelectedProcess() {
+	if tui.selectedIndex < 0 || tui.selectedIndex >= len(tui.processes) {
+		return
+	}
+
+	selectedProcess := &tui.processes[tui.selectedIndex]
+	if selectedProcess.PID == int32(os.Getpid()) {
+		tui.fail("GoMonitor can't stop itself")
+		return
+	}
 
-	// If SIGTERM fails, try SIGKILL (9) for force
+	// 1. Continue a stopped process, stop any other one
+	signal, name, action, state := syscall.SIGSTOP, "SIGSTOP", "stopped", common.StateStopped
+	if selectedProcess.State == common.StateStopped {
+		signal, name, action, state = syscall.SIGCONT, "SIGCONT", "continued", common.StateSleeping
+	}
+	err := syscall.Kill(int(selectedProcess.PID), signal)
+	if errors.Is(err, syscall.EPERM) {
+		err = fmt.Errorf("permission denied (other users' processes need root)")
+	}
 	if err != nil {
-		syscall.Kill(int(pid), syscall.SIGKILL)
+		tui.fail(fmt.Sprintf("PID %d: %v", selectedProcess.PID, err))
+		return
 	}
 
+	// 2. Show the new state right away; the next refresh reads the real one
+	tui.notice = fmt.Sprintf("PID %d (%s) %s (%s)", selectedProcess.PID, selectedProcess.Name, action, name)
+	selectedProcess.State = state
+}
+
+// askGroupKill asks to confirm killing the process group of the selected process
+// A whole pipeline or shell job shares one group, so its processes go down together
+// instead of respawning from a parent that wasn't killed
+func (tui *InteractiveTUI) askGroupKill() {
+	if tui.selectedIndex < 0 || tui.selectedIndex >= len(tui.processes) {
+		return
+	}
+
+	selectedProcess := tui.processes[tui.selectedIndex]
+	pgid := selectedProcess.PGID
+	switch {
+	case pgid <= 0:
+		tui.fail(fmt.Sprintf("PID %d: process group not available", selectedProcess.PID))
+		return
+	case int(pgid) == syscall.Getpgrp():
+		tui.fail(fmt.Sprintf("Process group %d is GoMonitor's own", pgid))
+		return
+	}
+
+	members := 0
+	for _, p := range tui.processes {
+		if p.PGID == pgid {
+			members++
+		}
+	}
+	tui.confirmGroup = pgid
+	tui.notice = redColor + fmt.Sprintf("Kill process group %d (%d processes, from %s)? Press Y to confirm, any other key cancels",
+		pgid, members, selectedProcess.Name) + resetColor
+}
+
+// answerGroupKill kills the process group waiting for confirmation (SIGTERM to every member), or cancels
+//
+// Parameters:
+//   - confirmed: the user pressed Y
+func (tui *InteractiveTUI) answerGroupKill(confirmed bool) {
+	pgid := tui.confirmGroup
+	tui.confirmGroup = 0
+	if !confirmed {
+		tui.notice = fmt.Sprintf("Process group %d not killed", pgid)
+		return
+	}
+
+	// A negative PID signals every process of the group
+	if err := syscall.Kill(-int(pgid), syscall.SIGTERM); err != nil {
+		if errors.Is(err, syscall.EPERM) {
+			err = errors.New("permission denied (killing other users' processes needs root)")
+		}
+		tui.fail(fmt.Sprintf("Process group %d: %v", pgid, err))
+		return
+	}
+	tui.notice = fmt.Sprintf("Process group %d terminated (SIGTERM)", pgid)
+
 	// Wait a bit and update the process list
 	time.Sleep(100 * time.Millisecond)
 	tui.updateProcesses()
 }
 
-// captureKeys captures keys from the terminal in raw mode
-func (tui *InteractiveTUI) captureKeys(keyChan chan byte) {
-	buf := make([]byte, 6)
-	for tui.running {
+// captureKeys captures keys and mouse events from the terminal in raw mode
+// Each read returns one key press (a character or a whole escape sequence), sent by name,
+// or one or more mouse reports
+func (tui *InteractiveTUI) captureKeys(keyChan chan string, mouseChan chan mouseEvent) {
+	defer tui.terminal.recoverPanic()
+	buf := make([]byte, 64)
+	for tui.running.Load() {
 		n, err := os.Stdin.Read(buf)
 		if err != nil {
 			continue
 		}
 
-		if n > 0 {
-			// Detect escape sequences (arrows and F-keys)
-			if buf[0] == 27 && n >= 3 {
-				// F5 key: ESC [ 1 5 ~
-				if n >= 5 && buf[1] == '[' && buf[2] == '1' && buf[3] == '5' && buf[4] == '~' {
-					keyChan <- 'r' // Treat F5 as refresh (same as 'R')
-					// Escape sequence for arrows: ESC [ A/B/C/D
-				} else if buf[1] == '[' {
-					keyChan <- buf[2] // A=65 (↑), B=66 (↓), C=67 (→), D=68 (←)
-				} else {
-					keyChan <- buf[0] // Simple ESC
-				}
-			} else {
-				keyChan <- buf[0]
+		if events, ok := decodeMouse(buf[:n]); ok {
+			for _, event := range events {
+				mouseChan <- event
 			}
+			continue
+		}
+		if key := decodeKey(buf[:n]); key != "" {
+			keyChan <- key
 		}
 	}
 }
@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dfialho05/GoMonitor/application/pck/config"
+)
+
+// escapeSequences maps terminal escape sequences (without the leading ESC) to key names
+// Terminals differ for Home/End, F1-F4 and modified arrows, so the common variants are listed
+var escapeSequences = map[string]string{
+	"[A": "up", "[B": "down", "[C": "right", "[D": "left",
+	"[1;2C": "shift+right", "[1;2D": "shift+left", "[c": "shift+right", "[d": "shift+left",
+	"[1;3C": "alt+right", "[1;3D": "alt+left", "\x1b[C": "alt+right", "\x1b[D": "alt+left",
+	"[1;5C": "ctrl+right", "[1;5D": "ctrl+left", "Oc": "ctrl+right", "Od": "ctrl+left",
+	"[H": "home", "[F": "end", "[1~": "home", "[4~": "end",
+	"[2~": "insert", "[3~": "delete", "[5~": "pgup", "[6~": "pgdown", "[Z": "shift+tab",
+	"OP": "f1", "OQ": "f2", "OR": "f3", "OS": "f4",
+	"[11~": "f1", "[12~": "f2", "[13~": "f3", "[14~": "f4",
+	"[15~": "f5", "[17~": "f6", "[18~": "f7", "[19~": "f8",
+	"[20~": "f9", "[21~": "f10", "[23~": "f11", "[24~": "f12",
+}
+
+// decodeKey converts the bytes of a key press into a key name (see config.DefaultKeys)
+//
+// Parameters:
+//   - input: bytes read from the terminal in raw mode
+//
+// Returns: normalized key name (empty if the key is unknown)
+func decodeKey(input []byte) string {
+	if len(input) == 0 {
+		return ""
+	}
+
+	switch input[0] {
+	case 27:
+		if len(input) == 1 {
+			return "esc"
+		}
+		return escapeSequences[string(input[1:])]
+	case ' ':
+		return "space"
+	case '\r', '\n':
+		return "enter"
+	case '\t':
+		return "tab"
+	case 127, 8:
+		return "backspace"
+	}
+
+	r, _ := utf8.DecodeRune(input)
+	if r == utf8.RuneError || r < ' ' {
+		return ""
+	}
+	return config.NormalizeKey(string(r))
+}
+
+// keyLabel returns how a key is shown in the footer (e.g. "↑", "F5", "DEL", "Q", "⇧→")
+func keyLabel(key string) string {
+	if modifier, name, ok := strings.Cut(key, "+"); ok && name != "" {
+		return map[string]string{"shift": "⇧", "ctrl": "^", "alt": "M-"}[modifier] + keyLabel(name)
+	}
+
+	switch key {
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	case "delete":
+		return "DEL"
+	case "backspace":
+		return "BKSP"
+	}
+	return strings.ToUpper(key)
+}
+
+// keysLabel returns the footer label of the first two keys of an action (e.g. "F5/R")
+//
+// Returns: label (empty if the action is disabled)
+func (tui *InteractiveTUI) keysLabel(action config.Action) string {
+	keys := tui.config.KeysFor(action)
+	if len(keys) > 2 {
+		keys = keys[:2]
+	}
+
+	labels := make([]string, 0, len(keys))
+	for _, key := range keys {
+		labels = append(labels, keyLabel(key))
+	}
+	return strings.Join(labels, "/")
+}
@@ -0,0 +1,305 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/config"
+	"golang.org/x/term"
+)
+
+// Layout limits of the interactive TUI
+const (
+	defaultWidth     = 120 // Width used when the terminal size can't be queried
+	defaultHeight    = 30  // Height used when the terminal size can't be queried
+	fullHeaderWidth  = 118 // Minimum width to show the full logo header
+	fullHeaderHeight = 34  // Minimum height to show the full logo header
+	fullHeaderLines  = 9   // Lines used by the full logo header (including blank line)
+	compactHdrLines  = 2   // Lines used by the compact header (including blank line)
+	tableHeaderLines = 2   // Lines used by the table header and its separator
+	minNameWidth     = 10  // Minimum width of the NAME column
+	maxNameWidth     = 50  // Maximum width of the NAME column (the COMMAND column has no maximum)
+	nameScrollStep   = 8   // Characters the NAME/COMMAND column scrolls per key press
+	userColumnWidth  = 10  // Width of the USER column
+	groupIDWidth     = 7   // Width of the PGID and SID columns
+	containerWidth   = 12  // Width of the CONTAINER column
+	swapWidth        = 10  // Width of the SWAP column
+	ioWidth          = 12  // Width of the DISK I/O column
+	powerWidth       = 9   // Width of the POWER column
+	cpuTimeWidth     = 10  // Width of the TIME+ column
+	minVisibleRows   = 3   // Minimum number of process rows shown
+	maxAlertLines    = 3   // Maximum number of alert lines shown
+	minNice          = -20 // Highest priority a process can be reniced to
+	maxNice          = 19  // Lowest priority a process can be reniced to
+)
+
+// alertInterval is how often alert rules are evaluated in the TUI
+const alertInterval = 5 * time.Second
+
+// sessionPollInterval is how often the session lock/idle state is checked (when_locked.enabled)
+// Short enough that the normal refresh resumes right after unlocking
+const sessionPollInterval = 2 * time.Second
+
+// footerItem is one key hint shown in the TUI footer
+type footerItem struct {
+	key   string // Key(s) shown between brackets (e.g. "Q/ESC")
+	label string // Action description (e.g. "Quit")
+	color string // Color of the key
+}
+
+// updateTerminalSize queries the real terminal size (TIOCGWINSZ)
+// Keeps the previous values if the size can't be queried
+func (tui *InteractiveTUI) updateTerminalSize() {
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || width <= 0 || height <= 0 {
+		return
+	}
+	tui.width = width
+	tui.height = height
+}
+
+// useFullHeader checks if the terminal is large enough for the full logo header
+func (tui *InteractiveTUI) useFullHeader() bool {
+	return tui.width >= fullHeaderWidth && tui.height >= fullHeaderHeight
+}
+
+// headerLines returns how many lines the header uses with the current terminal size
+func (tui *InteractiveTUI) headerLines() int {
+	if tui.useFullHeader() {
+		return fullHeaderLines
+	}
+	return compactHdrLines
+}
+
+// alertLines returns how many lines the warnings and active alerts use (including blank line)
+func (tui *InteractiveTUI) alertLines() int {
+	rows := len(tui.alertRows())
+	if rows == 0 {
+		return 0
+	}
+	return rows + 1
+}
+
+// footerItems returns the key hints shown in the footer
+// Hints follow the configured key bindings; disabled actions are left out
+func (tui *InteractiveTUI) footerItems() []footerItem {
+	pauseLabel := "Pause"
+	if tui.paused {
+		pauseLabel = "Resume"
+	}
+
+	hints := []struct {
+		action config.Action
+		label  string
+		color  string
+	}{
+		{config.ActionRefresh, "Refresh", yellowColor},
+		{config.ActionPause, pauseLabel, yellowColor},
+		{config.ActionSortCPU, "CPU", greenColor},
+		{config.ActionSortRAM, "RAM", magentaColor},
+		{config.ActionSortPID, "PID", yellowColor},
+		{config.ActionSortNext, "Sort", yellowColor},
+		{config.ActionToggleCore, "Core", cyanColor},
+		{config.ActionToggleGroups, "Groups", cyanColor},
+		{config.ActionToggleApps, "Apps", cyanColor},
+		{config.ActionToggleContainer, "Container", cyanColor},
+		{config.ActionToggleSwap, "Swap", magentaColor},
+		{config.ActionToggleIO, "Disk I/O", magentaColor},
+		{config.ActionToggleEnergy, "Power", magentaColor},
+		{config.ActionToggleGraphs, "Graphs", cyanColor},
+		{config.ActionToggleCmdline, "Command", cyanColor},
+		{config.ActionNextTab, "Next Tab", cyanColor},
+		{config.ActionSplit, "Split", cyanColor},
+		{config.ActionFilter, "Filter", cyanColor},
+		{config.ActionThrottle, "Throttle", yellowColor},
+		{config.ActionCopy, "Copy", cyanColor},
+		{config.ActionPin, "Pin", cyanColor},
+		{config.ActionMark, "Mark", cyanColor},
+		{config.ActionNiceUp, "Nice+", yellowColor},
+		{config.ActionNiceDown, "Nice-", yellowColor},
+		{config.ActionStop, "Stop/Continue", yellowColor},
+		{config.ActionKill, "Kill Process", redColor},
+		{config.ActionKillGroup, "Kill Group", redColor},
+		{config.ActionQuit, "Quit", whiteColor},
+	}
+
+	var items []footerItem
+
+	// Navigation shows the first key of up and down together (e.g. "↑/↓" or "K/J")
+	up, down := tui.config.KeysFor(config.ActionUp), tui.config.KeysFor(config.ActionDown)
+	if len(up) > 0 && len(down) > 0 {
+		items = append(items, footerItem{keyLabel(up[0]) + "/" + keyLabel(down[0]), "Navigate", cyanColor})
+	}
+	left, right := tui.config.KeysFor(config.ActionScrollLeft), tui.config.KeysFor(config.ActionScrollRight)
+	if len(left) > 0 && len(right) > 0 {
+		items = append(items, footerItem{keyLabel(left[0]) + "/" + keyLabel(right[0]), "Scroll Name", cyanColor})
+	}
+	first, last := tui.config.KeysFor(config.ActionTabProcesses), tui.config.KeysFor(config.ActionTabConnections)
+	if len(first) > 0 && len(last) > 0 {
+		items = append(items, footerItem{keyLabel(first[0]) + "-" + keyLabel(last[0]), "Tabs", cyanColor})
+	}
+
+	for _, hint := range hints {
+		if keys := tui.keysLabel(hint.action); keys != "" {
+			items = append(items, footerItem{keys, hint.label, hint.color})
+		}
+	}
+	return items
+}
+
+// footerRows wraps the footer key hints into rows that fit the terminal width
+//
+// Returns:
+//   - slice of rendered (colored) footer rows
+func (tui *InteractiveTUI) footerRows() []string {
+	var rows []string
+	var row strings.Builder
+	rowWidth := 2
+
+	row.WriteString("  ")
+	for _, item := range tui.footerItems() {
+		// Visible width: "[" + key + "] " + label + "  "
+		itemWidth := len([]rune(item.key)) + len(item.label) + 5
+		if rowWidth+itemWidth > tui.width && rowWidth > 2 {
+			rows = append(rows, row.String())
+			row.Reset()
+			row.WriteString("  ")
+			rowWidth = 2
+		}
+
+		row.WriteString(item.color + boldColor + "[" + item.key + "]" + resetColor + " " + item.label + "  ")
+		rowWidth += itemWidth
+	}
+	rows = append(rows, row.String())
+
+	return rows
+}
+
+// contentLines returns how many lines the tab shown gets
+// Everything that isn't the header, tab bar, info bar, graphs, alerts or footer is used for the tab
+func (tui *InteractiveTUI) contentLines() int {
+	// Footer: blank line + separator + key hint rows
+	footerLines := 2 + len(tui.footerRows())
+	return tui.height - tui.contentTop() - footerLines
+}
+
+// contentTop returns the screen row (from 1) of the first line of the tab shown
+// (the table header on the Processes tab), below the header, tab bar, info bar, graphs and alerts
+func (tui *InteractiveTUI) contentTop() int {
+	// Info bar: key/value rows + blank line
+	infoBarLines := len(tui.infoBarRows()) + 1
+	return tui.headerLines() + tabBarLines + infoBarLines + tui.graphLines() + tui.alertLines() + 1
+}
+
+// visibleRows returns how many process rows fit on screen
+// The Processes tab is the table header followed by the process list
+func (tui *InteractiveTUI) visibleRows() int {
+	rows := tui.contentLines() - tableHeaderLines
+	if rows < minVisibleRows {
+		return minVisibleRows
+	}
+	return rows
+}
+
+// nameColumnWidth returns the width of the NAME (or COMMAND) column for the width of the process table
+// The fixed columns are PID, USER, S, NI, CPU %, RAM %, MEMORY and the optional PGID, SID, CONTAINER, CORE, SWAP, DISK I/O, POWER and TIME+ columns
+func (tui *InteractiveTUI) nameColumnWidth() int {
+	fixed := 2 + 9 + userColumnWidth + 1 + 2 + 4 + 11 + 11 + 15 + 1
+	if tui.showCore {
+		fixed += 6
+	}
+	if tui.showGroups {
+		fixed += 2 * (groupIDWidth + 1)
+	}
+	if tui.showContainer {
+		fixed += containerWidth + 1
+	}
+	if tui.showSwap {
+		fixed += swapWidth + 1
+	}
+	if tui.showIO {
+		fixed += ioWidth + 1
+	}
+	if tui.showEnergy {
+		fixed += powerWidth + 1
+	}
+	if tui.showCPUTime() {
+		fixed += cpuTimeWidth + 1
+	}
+
+	width := tui.tableWidth() - fixed
+	if width < minNameWidth {
+		return minNameWidth
+	}
+	if width > maxNameWidth && !tui.showCmdline {
+		return maxNameWidth
+	}
+	return width
+}
+
+// nameText returns the text of the NAME column of a process: its name, or its command line
+// when the COMMAND column is shown (kernel threads have none and show their name in brackets, like ps)
+func (tui *InteractiveTUI) nameText(p common.ProcessInfo) string {
+	if !tui.showCmdline {
+		if count := tui.appCounts[p.PID]; count > 1 {
+			return fmt.Sprintf("%s (%d)", p.Name, count)
+		}
+		return p.Name
+	}
+	if cmdline := tui.cmdlines[p.PID]; cmdline != "" {
+		return cmdline
+	}
+	return "[" + p.Name + "]"
+}
+
+// maxNameScroll returns how far the NAME/COMMAND column can scroll: until the end of the
+// longest text of the rows on screen is visible
+func (tui *InteractiveTUI) maxNameScroll() int {
+	longest := 0
+	end := min(tui.scrollOffset+tui.visibleRows(), len(tui.processes))
+	for _, p := range tui.processes[min(tui.scrollOffset, end):end] {
+		longest = max(longest, common.DisplayWidth(tui.nameText(p)))
+	}
+	return max(longest-tui.nameColumnWidth(), 0)
+}
+
+// scrollText returns width columns of text starting at offset, padded with spaces
+// The text is shown as is; a cut end is marked with "…" so it's clear there is more to scroll to
+// Wide characters (CJK, emoji) take two columns and are never split
+//
+// Parameters:
+//   - text: full text of the cell
+//   - offset: first character shown
+//   - width: width of the column
+//
+// Returns: cell of exactly width columns
+func scrollText(text string, offset, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	runes := []rune(text)
+	visible := string(runes[min(max(offset, 0), len(runes)):])
+	if common.DisplayWidth(visible) > width {
+		visible = common.CutString(visible, width-1) + "…"
+	}
+	if offset > 0 && visible != "" {
+		// Replace the first character, keeping its width
+		r, size := utf8.DecodeRuneInString(visible)
+		visible = "…" + strings.Repeat(" ", max(common.RuneWidth(r)-1, 0)) + visible[size:]
+	}
+	return common.PadString(visible, width, false)
+}
+
+// separatorLine returns a horizontal separator that fits the given width
+// (the terminal width, or the process table width in the split view)
+func (tui *InteractiveTUI) separatorLine(width int) string {
+	width -= 3
+	if width < 1 {
+		width = 1
+	}
+	return "  " + strings.Repeat("─", width)
+}
@@ -0,0 +1,257 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/hwcache"
+)
+
+// Built-in logo names
+const (
+	LogoGoMonitor = "gom"     // GoMonitor logo, used on systems without a logo (e.g. FreeBSD)
+	LogoLinux     = "linux"   // Tux, for any Linux
+	LogoUbuntu    = "ubuntu"  // Ubuntu and derivatives (Mint, Pop!_OS, ...)
+	LogoDebian    = "debian"  // Debian and derivatives
+	LogoArch      = "arch"    // Arch and derivatives (Manjaro, EndeavourOS, ...)
+	LogoFedora    = "fedora"  // Fedora and derivatives
+	LogoMacOS     = "macos"   // macOS
+	LogoWindows   = "windows" // Windows
+)
+
+// maxLogoFileSize bounds --ascii-file, which is meant for a small picture
+const maxLogoFileSize = 64 * 1024
+
+// LogoOptions selects the logo of the default interface
+type LogoOptions struct {
+	Name string // Built-in logo (e.g. "arch"); empty to detect it from the OS
+	File string // Text file with a custom logo (may contain color codes); overrides Name
+}
+
+// logos builds the built-in logos with the colors of the active theme
+// Lines are padded to the same width when they are shown
+var logos = map[string]func() []string{
+	LogoGoMonitor: logoLines,
+	LogoLinux: func() []string {
+		return colorLogo(whiteColor,
+			"",
+			"        .--.",
+			"       |o_o |",
+			"       |:_/ |",
+			"      //   \\ \\",
+			"     (|     | )",
+			"    /'\\_   _/`\\",
+			"    \\___)=(___/",
+			"",
+		)
+	},
+	LogoUbuntu: func() []string {
+		return colorLogo(redColor,
+			"",
+			"              _",
+			"          ---(_)",
+			"      _/  ---  \\",
+			"     (_) |   |",
+			"       \\  --- _/",
+			"          ---(_)",
+			"",
+		)
+	},
+	LogoDebian: func() []string {
+		return colorLogo(redColor,
+			"",
+			"       _____",
+			"      /  __ \\",
+			"     |  /    |",
+			"     |  \\___-",
+			"     -_",
+			"       --_",
+			"",
+		)
+	},
+	LogoArch: func() []string {
+		return colorLogo(cyanColor,
+			"",
+			"          /\\",
+			"         /  \\",
+			"        /\\   \\",
+			"       /      \\",
+			"      /   ,,   \\",
+			"     /   |  |  -\\",
+			"    /_-''    ''-_\\",
+			"",
+		)
+	},
+	LogoFedora: func() []string {
+		return colorLogo(blueColor,
+			"",
+			"          ,'''''.",
+			"         |   ,.  |",
+			"         |  |  '_'",
+			"    ,....|  |..",
+			"  .'  ,_;|   ..'",
+			"  |  |   |  |",
+			"  |  ',_,'  |",
+			"   '.     ,'",
+			"     '''''",
+			"",
+		)
+	},
+	LogoMacOS: func() []string {
+		// The rainbow of the classic Apple logo
+		return []string{
+			"",
+			greenColor + "              .:'" + resetColor,
+			greenColor + "          __ :'__" + resetColor,
+			yellowColor + "       .'`__`-'__``." + resetColor,
+			redColor + "      :__________.-'" + resetColor,
+			redColor + "      :_________:" + resetColor,
+			magentaColor + "       :_________`-;" + resetColor,
+			blueColor + "        `.__.-.__.'" + resetColor,
+			"",
+		}
+	},
+	LogoWindows: func() []string {
+		return colorLogo(blueColor,
+			"",
+			"     _______ _______",
+			"    |       |       |",
+			"    |       |       |",
+			"    |_______|_______|",
+			"    |       |       |",
+			"    |       |       |",
+			"    |_______|_______|",
+			"",
+		)
+	},
+}
+
+// colorLogo colors every line of a logo
+func colorLogo(color string, lines ...string) []string {
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = color + boldColor + line + resetColor
+		}
+	}
+	return lines
+}
+
+// LogoNames lists the built-in logos
+//
+// Returns: sorted logo names (e.g. for an error message)
+func LogoNames() []string {
+	names := make([]string, 0, len(logos))
+	for name := range logos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsLogo checks if a name is a built-in logo
+func IsLogo(name string) bool {
+	_, ok := logos[name]
+	return ok
+}
+
+// selectLogo returns the lines of the logo chosen by the options, all padded to the same width
+// Without a name or file the logo is detected from the OS (the GoMonitor logo if it has none)
+//
+// Returns:
+//   - logo lines
+//   - width of every line
+//   - error if the logo file can't be read or the name is unknown
+func selectLogo(options LogoOptions) ([]string, int, error) {
+	var lines []string
+	switch {
+	case options.File != "":
+		data, err := readLogoFile(options.File)
+		if err != nil {
+			return nil, 0, err
+		}
+		// Each line ends with a reset, so colors of the file don't leak into the information,
+		// and starts with the colors still active at the end of the previous line
+		active := ""
+		for _, line := range strings.Split(strings.TrimRight(data, "\n"), "\n") {
+			line = strings.TrimRight(line, "\r")
+			lines = append(lines, active+line+"\033[0m")
+			for _, code := range ansiCode.FindAllString(line, -1) {
+				if code == "\033[0m" || code == "\033[m" {
+					active = ""
+				} else {
+					active += code
+				}
+			}
+		}
+
+	case options.Name != "":
+		build, ok := logos[options.Name]
+		if !ok {
+			return nil, 0, fmt.Errorf("unknown logo '%s' (%s)", options.Name, strings.Join(LogoNames(), ", "))
+		}
+		lines = build()
+
+	default:
+		lines = logos[detectLogo()]()
+	}
+
+	// Pad every line to the widest one, so the information starts in the same column
+	width := 0
+	for _, line := range lines {
+		width = max(width, common.DisplayWidth(line))
+	}
+	for i, line := range lines {
+		lines[i] = line + strings.Repeat(" ", width-common.DisplayWidth(line))
+	}
+	return lines, width, nil
+}
+
+// readLogoFile reads a custom logo, refusing files too big to be a logo
+func readLogoFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading logo file: %w", err)
+	}
+	if info.Size() > maxLogoFileSize {
+		return "", fmt.Errorf("logo file %s is too big (over %d KB)", path, maxLogoFileSize/1024)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading logo file: %w", err)
+	}
+	// Tabs have no fixed width, so they would break the alignment
+	return strings.ReplaceAll(string(data), "\t", "    "), nil
+}
+
+// detectLogo picks the logo of the running OS
+// On Linux the ID of /etc/os-release is used, then its ID_LIKE (e.g. Mint is like Ubuntu)
+//
+// Returns: logo name (LogoGoMonitor if the OS has no logo)
+func detectLogo() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return LogoMacOS
+	case "windows":
+		return LogoWindows
+	case "linux":
+	default:
+		return LogoGoMonitor
+	}
+
+	// ID_LIKE lists the closest distro first (e.g. "ubuntu debian")
+	osInfo := hwcache.Get().OS
+	candidates := append([]string{osInfo.ID}, osInfo.IDLike...)
+	for _, id := range candidates {
+		switch id {
+		case "ubuntu", "debian", "arch", "fedora":
+			return id
+		case "archlinux":
+			return LogoArch
+		}
+	}
+	return LogoLinux
+}
@@ -0,0 +1,164 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/config"
+)
+
+// Marked processes
+const (
+	markerSymbol   = "●" // Drawn in the margin of marked process rows
+	maxMarkedNames = 3   // Names listed by the bulk action confirmation
+)
+
+// toggleMarkSelectedProcess marks the selected process for the bulk actions, or unmarks it,
+// then selects the next one so several processes can be marked in a row
+// Marks follow the processes when the list is sorted again, and are dropped when they exit
+func (tui *InteractiveTUI) toggleMarkSelectedProcess() {
+	if tui.selectedIndex < 0 || tui.selectedIndex >= len(tui.processes) {
+		return
+	}
+
+	selectedProcess := tui.processes[tui.selectedIndex]
+	if _, ok := tui.marked[selectedProcess.PID]; ok {
+		delete(tui.marked, selectedProcess.PID)
+	} else {
+		tui.marked[selectedProcess.PID] = selectedProcess.CreateTime
+	}
+	tui.moveSelection(1)
+}
+
+// isMarked checks if a process is marked for the bulk actions
+func (tui *InteractiveTUI) isMarked(p common.ProcessInfo) bool {
+	createTime, ok := tui.marked[p.PID]
+	return ok && createTime == p.CreateTime
+}
+
+// pruneMarks drops the marks of processes that exited, or whose PID was reused by
+// a new process (a different start time), so a bulk action never hits the wrong process
+func (tui *InteractiveTUI) pruneMarks() {
+	if len(tui.marked) == 0 {
+		return
+	}
+	alive := make(map[int32]bool, len(tui.marked))
+	for _, p := range tui.allProcesses {
+		if tui.isMarked(p) {
+			alive[p.PID] = true
+		}
+	}
+	for pid := range tui.marked {
+		if !alive[pid] {
+			delete(tui.marked, pid)
+		}
+	}
+}
+
+// markedProcesses returns the marked processes of the last update, sorted by PID
+func (tui *InteractiveTUI) markedProcesses() []common.ProcessInfo {
+	var marked []common.ProcessInfo
+	for _, p := range tui.allProcesses {
+		if tui.isMarked(p) {
+			marked = append(marked, p)
+		}
+	}
+	sort.Slice(marked, func(i, j int) bool { return marked[i].PID < marked[j].PID })
+	return marked
+}
+
+// askMarkedAction asks to confirm killing or renicing every marked process
+// The question says how many processes are affected and names the first ones
+//
+// Parameters:
+//   - action: config.ActionKill, config.ActionNiceUp or config.ActionNiceDown
+func (tui *InteractiveTUI) askMarkedAction(action config.Action) {
+	marked := tui.markedProcesses()
+	if len(marked) == 0 {
+		return
+	}
+
+	var names []string
+	for _, p := range marked {
+		if len(names) == maxMarkedNames {
+			names = append(names, "...")
+			break
+		}
+		names = append(names, p.Name)
+	}
+
+	question := fmt.Sprintf("Kill %d marked processes", len(marked))
+	switch action {
+	case config.ActionNiceUp:
+		question = fmt.Sprintf("Raise the nice value of %d marked processes", len(marked))
+	case config.ActionNiceDown:
+		question = fmt.Sprintf("Lower the nice value of %d marked processes", len(marked))
+	}
+	tui.confirmMarked = action
+	tui.notice = redColor + fmt.Sprintf("%s (%s)? Press Y to confirm, any other key cancels",
+		question, strings.Join(names, ", ")) + resetColor
+}
+
+// answerMarkedAction runs the bulk action waiting for confirmation on every marked process,
+// or cancels it. Killed processes are unmarked; reniced ones stay marked, so the nice value
+// can be changed again
+//
+// Parameters:
+//   - confirmed: the user pressed Y
+func (tui *InteractiveTUI) answerMarkedAction(confirmed bool) {
+	action := tui.confirmMarked
+	tui.confirmMarked = ""
+	if !confirmed {
+		tui.notice = "Marked processes left as they are"
+		return
+	}
+
+	self := int32(os.Getpid())
+	done, failed := 0, 0
+	var firstErr string
+	for _, p := range tui.markedProcesses() {
+		var err error
+		switch action {
+		case config.ActionKill:
+			if p.PID == self {
+				err = fmt.Errorf("GoMonitor can't kill itself")
+			} else {
+				err = common.KillProcess(p.PID)
+			}
+			delete(tui.marked, p.PID)
+		case config.ActionNiceUp, config.ActionNiceDown:
+			delta := 1
+			if action == config.ActionNiceDown {
+				delta = -1
+			}
+			err = setNice(p.PID, max(minNice, min(int(p.Nice)+delta, maxNice)))
+		}
+
+		if err != nil {
+			failed++
+			if firstErr == "" {
+				firstErr = fmt.Sprintf("PID %d: %v", p.PID, err)
+			}
+			continue
+		}
+		done++
+	}
+
+	verb := "killed"
+	if action != config.ActionKill {
+		verb = "reniced"
+	}
+	if failed > 0 {
+		tui.fail(fmt.Sprintf("%d processes %s, %d failed (%s)", done, verb, failed, firstErr))
+	} else {
+		tui.notice = fmt.Sprintf("%d processes %s", done, verb)
+	}
+
+	// Wait a bit and update the process list
+	time.Sleep(100 * time.Millisecond)
+	tui.updateProcesses()
+}
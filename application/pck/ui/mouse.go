@@ -0,0 +1,167 @@
+package ui
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Mouse reporting escape sequences
+// 1000 reports button presses and releases (the wheel included) and 1006 encodes them as
+// "ESC [ < button ; column ; row M", which isn't limited to 223 columns like the legacy encoding
+const (
+	enableMouse  = "\033[?1000h\033[?1006h"
+	disableMouse = "\033[?1006l\033[?1000l"
+)
+
+// wheelStep is how many rows one notch of the mouse wheel scrolls
+const wheelStep = 3
+
+// mouseButton is what a mouse report was for
+type mouseButton int
+
+const (
+	mouseLeft      mouseButton = iota // Left button pressed
+	mouseWheelUp                      // Wheel scrolled up
+	mouseWheelDown                    // Wheel scrolled down
+)
+
+// mouseEvent is a mouse click or wheel notch at a screen position
+type mouseEvent struct {
+	button mouseButton // Button pressed or wheel direction
+	x      int         // Column (from 1)
+	y      int         // Row (from 1)
+}
+
+// decodeMouse converts the SGR mouse reports read from the terminal into mouse events
+// A fast wheel sends several reports in one read; releases, motion and the middle and
+// right buttons are left out
+//
+// Parameters:
+//   - input: bytes read from the terminal in raw mode
+//
+// Returns:
+//   - mouse events, in order
+//   - false if the input isn't a mouse report (so it's decoded as a key)
+func decodeMouse(input []byte) ([]mouseEvent, bool) {
+	text := string(input)
+	if !strings.HasPrefix(text, "\x1b[<") {
+		return nil, false
+	}
+
+	var events []mouseEvent
+	for strings.HasPrefix(text, "\x1b[<") {
+		end := strings.IndexAny(text, "Mm")
+		if end < 0 {
+			break // Cut by the read buffer
+		}
+		report, pressed := text[3:end], text[end] == 'M'
+		text = text[end+1:]
+
+		fields := strings.Split(report, ";")
+		if len(fields) != 3 || !pressed {
+			continue
+		}
+		code, errCode := strconv.Atoi(fields[0])
+		x, errX := strconv.Atoi(fields[1])
+		y, errY := strconv.Atoi(fields[2])
+		if errCode != nil || errX != nil || errY != nil {
+			continue
+		}
+
+		// Bits 2-4 are Shift, Alt and Ctrl, bit 5 motion and bit 6 the wheel
+		switch code &^ (4 | 8 | 16) {
+		case 0:
+			events = append(events, mouseEvent{mouseLeft, x, y})
+		case 64:
+			events = append(events, mouseEvent{mouseWheelUp, x, y})
+		case 65:
+			events = append(events, mouseEvent{mouseWheelDown, x, y})
+		}
+	}
+	return events, true
+}
+
+// handleMouse processes a mouse click or wheel notch
+// Clicking a tab name shows it, clicking a process selects it and clicking a column
+// header of the process table sorts by that column; the wheel moves like the arrow keys.
+// Mouse events are ignored while a key answer or the connection filter is expected
+//
+// Parameters:
+//   - event: mouse event (from decodeMouse)
+func (tui *InteractiveTUI) handleMouse(event mouseEvent) {
+	if tui.confirmGroup != 0 || tui.confirmMarked != "" || (tui.tab == tabConnections && tui.connectionsView().editing) {
+		return
+	}
+
+	// The wheel over the second pane scrolls it, wherever the focus is
+	inPane := tui.tab == tabProcesses && tui.splitActive() && event.x > tui.tableWidth()
+	switch event.button {
+	case mouseWheelUp, mouseWheelDown:
+		step := wheelStep
+		if event.button == mouseWheelUp {
+			step = -wheelStep
+		}
+		switch {
+		case inPane:
+			tui.paneScroll += step // Kept within the content when rendering
+		case tui.tab == tabProcesses:
+			tui.moveSelection(step)
+		default:
+			tui.scroll(step)
+		}
+		tui.render()
+		return
+	}
+
+	// Tab bar, right below the header
+	if event.y == tui.headerLines()+1 {
+		if tab, ok := tui.tabAt(event.x); ok {
+			tui.switchTab(tab)
+			tui.render()
+		}
+		return
+	}
+
+	if tui.tab != tabProcesses {
+		return
+	}
+	if inPane {
+		tui.paneFocused = true
+		tui.render()
+		return
+	}
+
+	// Process table: header, separator, then the visible processes
+	top := tui.contentTop()
+	switch row := event.y - top - tableHeaderLines; {
+	case event.y == top:
+		if mode, ok := tui.columnSortAt(event.x); ok {
+			tui.sortMode = mode
+			tui.updateProcesses()
+		}
+	case row >= 0 && row < tui.visibleRows() && tui.scrollOffset+row < len(tui.processes):
+		tui.selectedIndex = tui.scrollOffset + row
+		tui.paneFocused = false
+	default:
+		return
+	}
+	tui.render()
+}
+
+// columnSortAt returns the sort mode of the process table column at a screen column
+//
+// Parameters:
+//   - x: screen column (from 1)
+//
+// Returns: sort mode, false if the column can't be sorted by or x is between columns
+func (tui *InteractiveTUI) columnSortAt(x int) (SortMode, bool) {
+	start := 3 // After the two-space margin
+	for _, column := range tui.tableColumns() {
+		end := start + max(column.width, len([]rune(column.label)))
+		if x >= start && x < end {
+			return column.sort, column.sort != noSort
+		}
+		start = end + 1 // One space between columns
+	}
+	return 0, false
+}
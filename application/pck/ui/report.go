@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/clipboard"
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+)
+
+// processReport describes a process as plain text, for pasting into a bug report
+//
+// Parameters:
+//   - p: process to describe
+//   - details: its command line, executable and parent (from selectedDetails)
+//
+// Returns: one "Label: value" line per detail
+func processReport(p common.ProcessInfo, details processDetails) string {
+	var b strings.Builder
+	add := func(label, value string) {
+		fmt.Fprintf(&b, "%-12s %s\n", label+":", value)
+	}
+
+	host, _ := os.Hostname()
+	add("Host", host)
+	add("Time", time.Now().Format(time.RFC3339))
+	add("PID", fmt.Sprintf("%d", p.PID))
+	if details.ppid > 0 {
+		add("Parent", fmt.Sprintf("%d %s", details.ppid, details.parentName))
+	}
+	add("Name", p.Name)
+	add("User", p.Username)
+	add("State", fmt.Sprintf("%s (%s)", common.StateName(p.State), p.State))
+	if p.CreateTime > 0 {
+		started := time.UnixMilli(p.CreateTime)
+		add("Started", fmt.Sprintf("%s (%s ago)", started.Format("2006-01-02 15:04:05"), common.FormatDuration(time.Since(started))))
+	}
+	add("CPU", fmt.Sprintf("%.2f%%", p.CPUPercentage))
+	add("CPU time", common.FormatCPUTime(p.CPUTime))
+	add("RAM", fmt.Sprintf("%s (%.2f%%)", common.FormatBytes(p.RAMBytes), p.RAMPercentage))
+	add("Threads", fmt.Sprintf("%d", p.NumThreads))
+	add("Nice", fmt.Sprintf("%d", p.Nice))
+	if details.numFDs >= 0 {
+		add("Open files", formatFDs(details.numFDs, details.fdLimit))
+	}
+	if details.exe != "" {
+		add("Executable", details.exe)
+	}
+	if details.cwd != "" {
+		add("Directory", details.cwd)
+	}
+	add("Command", details.cmdline)
+	return b.String()
+}
+
+// copySelectedProcess copies the details of the selected process to the clipboard
+// Without a clipboard (e.g. over SSH) they are written to a file in the temporary directory instead
+func (tui *InteractiveTUI) copySelectedProcess() {
+	if tui.selectedIndex < 0 || tui.selectedIndex >= len(tui.processes) {
+		return
+	}
+
+	p := tui.processes[tui.selectedIndex]
+	report := processReport(p, tui.selectedDetails())
+
+	tool, err := clipboard.Copy(report)
+	if err == nil {
+		tui.notice = fmt.Sprintf("PID %d (%s) copied to the clipboard (%s)", p.PID, p.Name, tool)
+		return
+	}
+	if !errors.Is(err, clipboard.ErrNoTool) {
+		common.Logger().Warn("error copying to the clipboard", "err", err)
+	}
+
+	path, fileErr := clipboard.WriteFile(fmt.Sprintf("gom-process-%d-*.txt", p.PID), report)
+	if fileErr != nil {
+		tui.fail(fmt.Sprintf("PID %d: %v; %v", p.PID, err, fileErr))
+		return
+	}
+	tui.notice = fmt.Sprintf("PID %d (%s) written to %s (%v)", p.PID, p.Name, path, err)
+}
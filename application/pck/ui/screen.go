@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// screen keeps the rows last drawn on the terminal, so each render only rewrites the
+// rows that changed: the header stays in place and moving the selection redraws two rows,
+// instead of clearing the whole screen (which flickers, especially over SSH)
+type screen struct {
+	rows   []string // Rows on the terminal (nil: the screen must be cleared first)
+	width  int      // Terminal width when the rows were drawn
+	height int      // Terminal height when the rows were drawn
+}
+
+// invalidate makes the next draw clear the screen and write every row
+// Used when something else may have written to the terminal (e.g. after a resize)
+func (s *screen) invalidate() {
+	s.rows = nil
+}
+
+// draw writes the rows that differ from the ones on the terminal, in a single write
+// The whole screen is drawn again after the terminal was resized; rows past the
+// bottom of the terminal are left out, so it never scrolls
+//
+// Parameters:
+//   - w: terminal output
+//   - rows: rendered rows of the frame, from the top of the screen
+//   - width, height: terminal size
+func (s *screen) draw(w io.Writer, rows []string, width, height int) {
+	if len(rows) > height {
+		rows = rows[:height]
+	}
+
+	var out strings.Builder
+	out.WriteString(beginUpdate)
+	if s.rows == nil || width != s.width || height != s.height {
+		fmt.Fprintf(&out, moveCursor, 1, 1)
+		out.WriteString(clearScreen)
+		s.rows = nil
+	}
+
+	for i, row := range rows {
+		if i < len(s.rows) && s.rows[i] == row {
+			continue
+		}
+		fmt.Fprintf(&out, moveCursor, i+1, 1)
+		out.WriteString(row + resetColor + eraseToEnd)
+	}
+	// Erase whatever was below the last row (e.g. fewer alert lines pushed the footer up)
+	if len(rows) < len(s.rows) {
+		fmt.Fprintf(&out, moveCursor, len(rows)+1, 1)
+		out.WriteString(eraseBelow)
+	}
+	out.WriteString(endUpdate)
+
+	io.WriteString(w, out.String())
+	s.rows = rows
+	s.width, s.height = width, height
+}
@@ -0,0 +1,357 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/config"
+	"github.com/dfialho05/GoMonitor/application/pck/procfs"
+)
+
+// paneKind is the content of the second pane of the split view
+type paneKind int
+
+const (
+	paneNone      paneKind = iota // No split: the process table uses the whole width
+	paneNetwork                   // Throughput of the network interfaces
+	paneDetails                   // Details of the selected process
+	paneKindCount                 // Number of pane kinds (used to cycle)
+)
+
+// title returns the pane title shown above its content
+func (k paneKind) title() string {
+	switch k {
+	case paneNetwork:
+		return "Network"
+	case paneDetails:
+		return "Process Details"
+	default:
+		return ""
+	}
+}
+
+// next returns the next pane kind (cycles back to no split)
+func (k paneKind) next() paneKind {
+	return (k + 1) % paneKindCount
+}
+
+// Layout of the split view
+const (
+	minSplitWidth  = 140   // Minimum terminal width to show two panes side by side
+	minPaneWidth   = 60    // Minimum width of the second pane
+	maxPaneWidth   = 90    // Maximum width of the second pane
+	detailLabelLen = 11    // Width of the labels in the details pane
+	maxListedFiles = 200   // Most open descriptors listed in the details pane
+	paneDivider    = " │ " // Drawn between the process table and the second pane
+)
+
+// processDetails contains the selected process's information that isn't in the process list
+type processDetails struct {
+	pid        int32             // PID the details belong to (-1 if nothing is cached)
+	ppid       int32             // Parent PID (0 if not available)
+	parentName string            // Parent process name (empty if not available)
+	exe        string            // Executable path
+	cwd        string            // Working directory
+	cmdline    string            // Full command line (see common.DescribeCmdline)
+	numFDs     int32             // Open file descriptors (-1 if not available)
+	fdLimit    uint64            // Soft limit of open files (0 if unlimited or not available)
+	openFiles  []procfs.OpenFile // Open descriptors, lsof-like (nil if they can't be read)
+}
+
+// splitActive checks if the split view is enabled and the terminal is wide enough for it
+// On narrower terminals the process table keeps the whole width until the terminal grows
+func (tui *InteractiveTUI) splitActive() bool {
+	return tui.pane != paneNone && tui.width >= minSplitWidth
+}
+
+// paneWidth returns the width of the second pane (2/5 of the terminal, within limits)
+func (tui *InteractiveTUI) paneWidth() int {
+	return max(minPaneWidth, min(tui.width*2/5, maxPaneWidth))
+}
+
+// tableWidth returns the width available to the process table
+func (tui *InteractiveTUI) tableWidth() int {
+	if !tui.splitActive() {
+		return tui.width
+	}
+	return tui.width - tui.paneWidth() - len([]rune(paneDivider))
+}
+
+// splitRows joins the process table and the second pane side by side
+//
+// Parameters:
+//   - table: rendered process table rows (header, separator and process rows)
+//
+// Returns: one rendered row per table row
+func (tui *InteractiveTUI) splitRows(table []string) []string {
+	left := tui.tableWidth()
+	pane := tui.paneRows(len(table))
+	rows := make([]string, len(table))
+	for i, row := range table {
+		rows[i] = fitWidth(row, left) + cyanColor + paneDivider + resetColor + pane[i]
+	}
+	return rows
+}
+
+// paneRows returns the rows of the second pane: title, separator and the scrolled content
+//
+// Parameters:
+//   - height: number of rows to return
+//
+// Returns: exactly height rendered rows, each fitting the pane width
+func (tui *InteractiveTUI) paneRows(height int) []string {
+	width := tui.paneWidth()
+
+	// The focused pane has a highlighted title
+	focusHint := tui.keysLabel(config.ActionFocus)
+	title := boldColor + " " + tui.pane.title() + " " + resetColor
+	if tui.paneFocused {
+		title = selectionStyle + " " + tui.pane.title() + " " + resetColor
+	}
+	if focusHint != "" {
+		title += fmt.Sprintf(" [%s] focus", focusHint)
+	}
+
+	var content []string
+	switch tui.pane {
+	case paneNetwork:
+		content = tui.networkLines()
+	case paneDetails:
+		content = tui.detailLines(width)
+	}
+
+	// Keep the scroll position within the content
+	visible := max(height-2, 0)
+	tui.paneScroll = max(0, min(tui.paneScroll, len(content)-visible))
+	content = content[tui.paneScroll:]
+
+	rows := []string{title, strings.Repeat("─", width)}
+	for i := 0; len(rows) < height; i++ {
+		line := ""
+		if i < len(content) {
+			line = content[i]
+		}
+		rows = append(rows, line)
+	}
+	for i := range rows {
+		rows[i] = fitWidth(rows[i], width)
+	}
+	return rows[:height]
+}
+
+// networkLines returns the content of the network pane: one row per interface
+// with its throughput, followed by its link state, addresses and error counts
+func (tui *InteractiveTUI) networkLines() []string {
+	if tui.networkErr != nil {
+		return []string{redColor + tui.networkErr.Error() + resetColor}
+	}
+	if len(tui.interfaces) == 0 {
+		return []string{"No network interfaces found"}
+	}
+
+	lines := []string{boldColor + fmt.Sprintf("  %-10s %11s %11s %10s %10s", "INTERFACE", "RX/s", "TX/s", "RX TOTAL", "TX TOTAL") + resetColor}
+	for _, iface := range tui.interfaces {
+		state := greenColor + "●" + resetColor
+		if !iface.Up {
+			state = redColor + "○" + resetColor
+		}
+
+		rx, tx := "...", "..."
+		if iface.HasRate {
+			rx = common.FormatBytes(uint64(iface.RxBytesPerSec)) + "/s"
+			tx = common.FormatBytes(uint64(iface.TxBytesPerSec)) + "/s"
+		}
+		lines = append(lines, fmt.Sprintf("%s %s %s%11s%s %s%11s%s %10s %10s",
+			state, common.FitString(iface.Name, 10),
+			greenColor, rx, resetColor, magentaColor, tx, resetColor,
+			common.FormatBytes(iface.RxBytes), common.FormatBytes(iface.TxBytes)))
+
+		if iface.Link.State != "" {
+			lines = append(lines, "    "+cyanColor+iface.Link.Summary()+resetColor)
+		}
+		for _, addr := range iface.Addresses {
+			lines = append(lines, "    "+blueColor+addr+resetColor)
+		}
+		if iface.Errors > 0 || iface.Drops > 0 {
+			lines = append(lines, fmt.Sprintf("    %serrors %d, dropped %d%s", redColor, iface.Errors, iface.Drops, resetColor))
+		}
+	}
+	return lines
+}
+
+// detailLines returns the content of the details pane for the selected process
+// Long command lines are wrapped to the pane width
+//
+// Parameters:
+//   - width: pane width
+func (tui *InteractiveTUI) detailLines(width int) []string {
+	if tui.selectedIndex < 0 || tui.selectedIndex >= len(tui.processes) {
+		return []string{"No process selected"}
+	}
+	p := tui.processes[tui.selectedIndex]
+	details := tui.selectedDetails()
+
+	var lines []string
+	add := func(label, value string) {
+		lines = append(lines, fmt.Sprintf("%s%-*s%s %s", cyanColor, detailLabelLen, label, resetColor, value))
+	}
+
+	pid := fmt.Sprintf("%d", p.PID)
+	if details.ppid > 0 {
+		pid += fmt.Sprintf(" (parent %d %s)", details.ppid, details.parentName)
+	}
+	add("PID", pid)
+	add("Name", p.Name)
+	add("User", p.Username)
+	add("State", fmt.Sprintf("%s (%s)", common.StateName(p.State), p.State))
+	if p.CreateTime > 0 {
+		started := time.UnixMilli(p.CreateTime)
+		add("Started", fmt.Sprintf("%s (%s ago)", started.Format("2006-01-02 15:04:05"), common.FormatDuration(time.Since(started))))
+	}
+	add("CPU", fmt.Sprintf("%.2f%% (core %s)", p.CPUPercentage, common.FormatCore(p.LastCPU)))
+	add("Memory", fmt.Sprintf("%s (%.2f%%)", common.FormatBytes(p.RAMBytes), p.RAMPercentage))
+	if swap, ok := tui.swap[p.PID]; ok {
+		add("Swap", common.FormatBytes(swap))
+	}
+	if rate, ok := tui.diskIO[p.PID]; ok {
+		add("Disk I/O", fmt.Sprintf("read %s/s, write %s/s",
+			common.FormatBytes(uint64(rate.ReadBytesPerSec)), common.FormatBytes(uint64(rate.WriteBytesPerSec))))
+	}
+	add("Threads", fmt.Sprintf("%d", p.NumThreads))
+	add("Nice", fmt.Sprintf("%d", p.Nice))
+	if details.numFDs >= 0 {
+		add("Open files", formatFDs(details.numFDs, details.fdLimit))
+	}
+	if name := tui.containers[p.PID]; name != "" {
+		add("Container", name)
+		if _, metadata, ok := tui.resolver.Container(p.PID); ok {
+			if metadata.Image != "" {
+				add("Image", metadata.Image)
+			}
+			if unit := metadata.Unit(); unit != "" {
+				add("Deployed as", unit)
+			}
+		}
+	}
+	if limits, ok := tui.selectedLimits(); ok {
+		if limits.HasMemoryLimit() {
+			add("Cgroup RAM", limits.FormatMemory())
+		}
+		if limits.HasCPULimit() {
+			add("CPU limit", fmt.Sprintf("%.2f cores", limits.CPULimit))
+		}
+	}
+	if details.exe != "" {
+		add("Executable", details.exe)
+	}
+	if details.cwd != "" {
+		add("Directory", details.cwd)
+	}
+
+	// Command line, wrapped
+	lines = append(lines, "", cyanColor+"Command"+resetColor)
+	lines = append(lines, wrapText(details.cmdline, width-2, "  ")...)
+
+	// Open descriptors, the pane scrolls to the rest
+	if len(details.openFiles) > 0 {
+		lines = append(lines, "", cyanColor+"Descriptors"+resetColor)
+		for i, file := range details.openFiles {
+			if i == maxListedFiles {
+				lines = append(lines, fmt.Sprintf("  ... and %d more", len(details.openFiles)-maxListedFiles))
+				break
+			}
+			lines = append(lines, fitWidth(fmt.Sprintf("  %5d %s", file.FD, file.Target), width))
+		}
+	}
+	return lines
+}
+
+// formatFDs describes the open descriptors of a process and its limit (e.g. "230 of 1024 (22%)")
+func formatFDs(count int32, limit uint64) string {
+	if limit == 0 {
+		return fmt.Sprintf("%d", count)
+	}
+	return fmt.Sprintf("%d of %d (%.0f%%)", count, limit, float64(count)/float64(limit)*100)
+}
+
+// selectedDetails returns the details of the selected process
+// The details are read once per selected process and refresh, like the cgroup limits
+func (tui *InteractiveTUI) selectedDetails() processDetails {
+	pid := tui.processes[tui.selectedIndex].PID
+	if tui.details.pid == pid {
+		return tui.details
+	}
+
+	details := processDetails{pid: pid, numFDs: -1}
+	if p, err := common.GetProcessByPID(pid); err == nil {
+		if ppid, err := p.Ppid(); err == nil {
+			details.ppid = ppid
+			if parent, err := common.GetProcessByPID(ppid); err == nil {
+				details.parentName, _ = parent.Name()
+			}
+		}
+		if numFDs, err := p.NumFDs(); err == nil {
+			details.numFDs = numFDs
+			details.fdLimit, _ = procfs.ReadFDLimit(pid)
+			details.openFiles, _ = procfs.ReadOpenFiles(pid)
+		}
+		details.exe, _ = p.Exe()
+		details.cwd, _ = p.Cwd()
+	}
+	details.cmdline = common.DescribeCmdline(tui.processes[tui.selectedIndex])
+	tui.details = details
+	return details
+}
+
+// wrapText splits text into lines of at most width columns
+//
+// Parameters:
+//   - text: text to wrap (cut at any character, since command lines have long words)
+//   - width: maximum line width including the indent
+//   - indent: prefix of every line
+func wrapText(text string, width int, indent string) []string {
+	size := max(width-common.DisplayWidth(indent), 1)
+
+	var lines []string
+	for text != "" {
+		line := common.CutString(text, size)
+		if line == "" {
+			// A character wider than the line still gets a line of its own
+			_, n := utf8.DecodeRuneInString(text)
+			line = text[:n]
+		}
+		lines = append(lines, indent+line)
+		text = text[len(line):]
+	}
+	return lines
+}
+
+// fitWidth cuts or pads a rendered row to exactly width visible columns
+// Color codes don't count towards the width and are kept; a cut row ends with a reset
+func fitWidth(row string, width int) string {
+	var b strings.Builder
+	visible := 0
+	for i := 0; i < len(row); {
+		// Copy color codes as they are
+		if row[i] == '\033' {
+			if loc := ansiCode.FindStringIndex(row[i:]); loc != nil && loc[0] == 0 {
+				b.WriteString(row[i : i+loc[1]])
+				i += loc[1]
+				continue
+			}
+		}
+		r, size := utf8.DecodeRuneInString(row[i:])
+		if visible+common.RuneWidth(r) > width {
+			// A wide character that doesn't fit leaves a blank column
+			b.WriteString(strings.Repeat(" ", width-visible) + resetColor)
+			return b.String()
+		}
+		b.WriteRune(r)
+		visible += common.RuneWidth(r)
+		i += size
+	}
+	return b.String() + strings.Repeat(" ", width-visible)
+}
@@ -0,0 +1,1014 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+	"github.com/dfialho05/GoMonitor/application/pck/config"
+	"github.com/dfialho05/GoMonitor/application/pck/cpu"
+	"github.com/dfialho05/GoMonitor/application/pck/disk"
+	"github.com/dfialho05/GoMonitor/application/pck/gpu"
+	"github.com/dfialho05/GoMonitor/application/pck/history"
+	"github.com/dfialho05/GoMonitor/application/pck/network"
+	"github.com/dfialho05/GoMonitor/application/pck/ram"
+	"github.com/dfialho05/GoMonitor/application/pck/sensors"
+)
+
+// tabKind is one of the tabs of the TUI
+type tabKind int
+
+const (
+	tabProcesses   tabKind = iota // Process table (and the split view)
+	tabCPU                        // CPU usage history and per-core usage
+	tabMemory                     // RAM and swap history and the largest processes
+	tabDisk                       // Disk throughput history, busy devices and mounts
+	tabNetwork                    // Network throughput history and interfaces
+	tabGPU                        // GPU utilization history and memory
+	tabSensors                    // CPU temperature history, temperatures and fans
+	tabConnections                // Network connections with their owning process
+	tabCount                      // Number of tabs (used to cycle)
+)
+
+// Layout of the tabs
+const (
+	tabBarLines    = 2  // Lines used by the tab bar (including blank line)
+	minChartHeight = 3  // Minimum height of a tab's history chart (plot rows)
+	maxChartHeight = 12 // Maximum height of a tab's history chart (plot rows)
+	usageBarWidth  = 20 // Width of the usage bars (e.g. per core or per mount)
+	coreCellWidth  = 32 // Width of one core in the CPU tab ("cpu12 [bar] 100.0%")
+	fanLevelStep   = 10 // Change of a fan level per key press in the Sensors tab (%)
+)
+
+// tabView is the content of one tab
+// Every tab is sampled on each refresh, so its history graphs are complete when it's opened,
+// and the tab shown is rendered between the info bar and the footer
+type tabView interface {
+	title() string                                 // Name shown in the tab bar (e.g. "CPU")
+	action() config.Action                         // Action that switches to the tab
+	sample(tui *InteractiveTUI)                    // Takes the tab's measurements (called on each refresh)
+	rows(tui *InteractiveTUI, height int) []string // Renders exactly height rows
+}
+
+// newTabs creates the views of every tab, in tabKind order
+//
+// Parameters:
+//   - keep: how far back the history graphs go (graph_history)
+func newTabs(keep time.Duration) []tabView {
+	return []tabView{
+		&processesTab{},
+		&cpuTab{history: newTabHistory(keep), modes: cpu.NewBreakdownSampler()},
+		&memoryTab{history: newTabHistory(keep)},
+		&diskTab{history: newTabHistory(keep), sampler: disk.NewUtilizationSampler()},
+		&networkTab{history: newTabHistory(keep)},
+		&gpuTab{history: newTabHistory(keep)},
+		&sensorsTab{history: newTabHistory(keep), controller: sensors.NewFanController()},
+		&connectionsTab{},
+	}
+}
+
+// tabHistory contains the timestamped samples drawn in a tab's history chart
+type tabHistory struct {
+	samples []history.MetricSample // Samples within the history length, oldest first
+	keep    time.Duration          // How far back the chart goes
+}
+
+// newTabHistory creates an empty history that keeps samples for the given duration
+func newTabHistory(keep time.Duration) *tabHistory {
+	return &tabHistory{keep: keep}
+}
+
+// record adds the values of a refresh, dropping the samples older than the history length
+// Refreshes without values (e.g. the first one, which has no rates yet) are skipped
+func (h *tabHistory) record(values map[string]float64) {
+	if len(values) == 0 {
+		return
+	}
+	now := time.Now()
+	h.samples = append(h.samples, history.MetricSample{Time: now, Values: values})
+
+	drop := 0
+	for drop < len(h.samples) && now.Sub(h.samples[drop].Time) > h.keep {
+		drop++
+	}
+	h.samples = h.samples[drop:]
+}
+
+// chart draws the history as a line chart with the same renderer as gom chart
+//
+// Parameters:
+//   - series: series to draw, with their colors
+//   - width: total width including the axis labels
+//   - height: plot rows
+//
+// Returns: chart lines (a waiting message until there are samples)
+func (h *tabHistory) chart(series []history.ChartSeries, width, height int) []string {
+	to := time.Now()
+	options := history.ChartOptions{Width: width, Height: height, Style: history.ChartBraille, Reset: resetColor}
+	lines, ok := history.RenderChart(h.samples, series, to.Add(-h.keep), to, options)
+	if !ok {
+		return []string{"  Collecting samples..."}
+	}
+	return lines
+}
+
+// currentTab returns the view of the tab shown
+func (tui *InteractiveTUI) currentTab() tabView {
+	return tui.tabs[tui.tab]
+}
+
+// switchTab shows another tab, scrolled to its top
+func (tui *InteractiveTUI) switchTab(tab tabKind) {
+	tui.tab = (tab + tabCount) % tabCount
+	tui.tabScroll = 0
+	if tui.tab == tabConnections {
+		tui.tabs[tabConnections].sample(tui) // Only sampled while shown, so read the sockets now
+	}
+}
+
+// onProcessesTab checks if the process actions can be used, explaining why not on the other tabs
+// The selected process isn't visible there, so acting on it would be a surprise
+func (tui *InteractiveTUI) onProcessesTab() bool {
+	if tui.tab == tabProcesses {
+		return true
+	}
+	tui.notice = "Process actions only work on the Processes tab"
+	if key := tui.keysLabel(config.ActionTabProcesses); key != "" {
+		tui.notice += " (press " + key + ")"
+	}
+	return false
+}
+
+// renderTabBar renders the tab names with their keys, highlighting the tab shown
+func (tui *InteractiveTUI) renderTabBar(w io.Writer) {
+	var bar strings.Builder
+	bar.WriteString("  ")
+	for kind := range tui.tabs {
+		label := tui.tabLabel(tabKind(kind))
+		if tabKind(kind) == tui.tab {
+			bar.WriteString(selectionStyle + label + resetColor + " ")
+		} else {
+			bar.WriteString(cyanColor + label + resetColor + " ")
+		}
+	}
+	fmt.Fprintln(w, fitWidth(bar.String(), tui.width-1))
+	fmt.Fprintln(w)
+}
+
+// tabLabel returns the name of a tab in the tab bar, with its first key (e.g. " 2 CPU ")
+func (tui *InteractiveTUI) tabLabel(kind tabKind) string {
+	tab := tui.tabs[kind]
+	if keys := tui.config.KeysFor(tab.action()); len(keys) > 0 {
+		return " " + keyLabel(keys[0]) + " " + tab.title() + " "
+	}
+	return " " + tab.title() + " "
+}
+
+// tabAt returns the tab whose name is at a column of the tab bar
+//
+// Parameters:
+//   - x: screen column (from 1)
+//
+// Returns: tab clicked, false if the column is between or after the names
+func (tui *InteractiveTUI) tabAt(x int) (tabKind, bool) {
+	start := 3 // After the two-space margin
+	for kind := range tui.tabs {
+		end := start + common.DisplayWidth(tui.tabLabel(tabKind(kind)))
+		if x >= start && x < end {
+			return tabKind(kind), true
+		}
+		start = end + 1 // One space between names
+	}
+	return 0, false
+}
+
+// chartHeight returns the plot rows of a tab's history chart: about half of the tab,
+// leaving room for the axis and the legend below it
+//
+// Parameters:
+//   - height: rows of the tab
+//   - series: number of series (one legend line each)
+func chartHeight(height, series int) int {
+	return max(minChartHeight, min(height/2-3-series, maxChartHeight))
+}
+
+// tabRows lays out a subsystem tab: a title, its history chart and the details below it
+// The details scroll with the up/down keys when they don't fit
+//
+// Parameters:
+//   - title: line above the chart (e.g. "CPU usage (%)")
+//   - chart: chart lines (from tabHistory.chart)
+//   - details: detail lines shown below the chart
+//   - height: number of rows to return
+//
+// Returns: exactly height rendered rows, each fitting the terminal width
+func (tui *InteractiveTUI) tabRows(title string, chart, details []string, height int) []string {
+	rows := []string{"  " + boldColor + title + resetColor}
+	rows = append(rows, chart...)
+	rows = append(rows, "")
+
+	// Keep the scroll position within the details
+	visible := max(height-len(rows), 0)
+	tui.tabScroll = max(0, min(tui.tabScroll, len(details)-visible))
+	rows = append(rows, details[tui.tabScroll:]...)
+
+	for len(rows) < height {
+		rows = append(rows, "")
+	}
+	rows = rows[:height]
+	for i := range rows {
+		rows[i] = fitWidth(rows[i], tui.width-1)
+	}
+	return rows
+}
+
+// usageBar draws a percentage as a bar colored by level (green, yellow above 60%, red above 85%)
+//
+// Parameters:
+//   - percent: value to draw (0-100%)
+//   - width: width of the bar without the brackets
+func usageBar(percent float64, width int) string {
+	filled := max(0, min(int(percent/100*float64(width)+0.5), width))
+	color := greenColor
+	switch {
+	case percent >= 85:
+		color = redColor
+	case percent >= 60:
+		color = yellowColor
+	}
+	return "[" + color + strings.Repeat("█", filled) + resetColor + strings.Repeat("·", width-filled) + "]"
+}
+
+// processesTab is the process table, next to the second pane in the split view
+type processesTab struct{}
+
+func (t *processesTab) title() string          { return "Processes" }
+func (t *processesTab) action() config.Action  { return config.ActionTabProcesses }
+func (t *processesTab) sample(*InteractiveTUI) {} // The processes are collected by every refresh
+
+func (t *processesTab) rows(tui *InteractiveTUI, height int) []string {
+	var table strings.Builder
+	tui.renderTableHeader(&table)
+	tui.renderProcessList(&table)
+	rows := strings.Split(strings.TrimSuffix(table.String(), "\n"), "\n")
+	if tui.splitActive() {
+		return tui.splitRows(rows)
+	}
+	return rows
+}
+
+// cpuTab shows the system CPU usage over time and the usage of each core
+type cpuTab struct {
+	history   *tabHistory           // System CPU usage (%)
+	cores     []float64             // Usage of each core since the previous refresh
+	err       error                 // Error of the last per-core sample
+	modes     *cpu.BreakdownSampler // Measures the time spent in each CPU mode between refreshes
+	breakdown cpu.TimeBreakdown     // Time spent in each CPU mode since the previous refresh
+}
+
+func (t *cpuTab) title() string         { return "CPU" }
+func (t *cpuTab) action() config.Action { return config.ActionTabCPU }
+
+func (t *cpuTab) sample(tui *InteractiveTUI) {
+	t.cores, t.err = cpu.GetPerCorePercent()
+	if breakdown, err := t.modes.Sample(); err == nil {
+		t.breakdown = breakdown
+	}
+	if tui.system.HasCPU {
+		t.history.record(map[string]float64{"cpu": tui.system.CPUPercent})
+	}
+}
+
+func (t *cpuTab) rows(tui *InteractiveTUI, height int) []string {
+	series := []history.ChartSeries{{Name: "cpu", Color: greenColor}}
+	chart := t.history.chart(series, tui.width-1, chartHeight(height, len(series)))
+
+	var details []string
+	if tui.system.HasLoad {
+		details = append(details, fmt.Sprintf("  %sLoad%s %s   %sUp%s %s",
+			yellowColor, resetColor, tui.system.Load.FormatLoad(), blueColor, resetColor, common.FormatDuration(tui.system.Load.Uptime)))
+	}
+	if tui.system.CPUTemp > 0 {
+		details = append(details, fmt.Sprintf("  %sTemperature%s %d°C", yellowColor, resetColor, tui.system.CPUTemp))
+	}
+	if tui.throttle.Valid {
+		frequency := fmt.Sprintf("  %sFrequency%s %s", yellowColor, resetColor, tui.throttle.FormatFrequency())
+		if tui.throttle.Turbo != cpu.TurboUnknown {
+			frequency += fmt.Sprintf("   %sTurbo%s %s", yellowColor, resetColor, tui.throttle.Turbo)
+		}
+		if tui.throttle.Throttling {
+			frequency += "   " + redColor + "throttled" + resetColor
+		}
+		details = append(details, frequency)
+	}
+	if t.breakdown.Valid {
+		details = append(details, "  "+modeBar(t.breakdown, max(tui.width-4-2, usageBarWidth)), "  "+modeLegend(t.breakdown))
+	}
+	if len(details) > 0 {
+		details = append(details, "")
+	}
+
+	// Cores side by side, as many per row as fit
+	if t.err != nil {
+		return tui.tabRows("CPU usage (%)", chart, append(details, "  Collecting per-core usage..."), height)
+	}
+	perRow := max(1, (tui.width-2)/coreCellWidth)
+	var row strings.Builder
+	for i, percent := range t.cores {
+		fmt.Fprintf(&row, "  %-5s %s %5.1f%%", fmt.Sprintf("cpu%d", i), usageBar(percent, usageBarWidth), percent)
+		if (i+1)%perRow == 0 || i == len(t.cores)-1 {
+			details = append(details, row.String())
+			row.Reset()
+		}
+	}
+	return tui.tabRows("CPU usage (%)", chart, details, height)
+}
+
+// modeColors returns the colors of the CPU modes in the order of cpu.TimeBreakdown.Modes
+// (user, system, iowait, irq, steal, idle), like htop
+func modeColors() []string {
+	return []string{greenColor, redColor, magentaColor, yellowColor, cyanColor, resetColor}
+}
+
+// modeBar draws the time spent in each CPU mode as one bar, a color per mode
+//
+// Parameters:
+//   - breakdown: share of each mode
+//   - width: width of the bar without the brackets
+func modeBar(breakdown cpu.TimeBreakdown, width int) string {
+	var bar strings.Builder
+	colors := modeColors()
+	cells := breakdown.Cells(width)
+	for i := range breakdown.Modes() {
+		char := "█"
+		if i == len(cells)-1 {
+			char = "·" // Idle
+		}
+		bar.WriteString(colors[i] + strings.Repeat(char, cells[i]) + resetColor)
+	}
+	return "[" + bar.String() + "]"
+}
+
+// modeLegend lists the share of each CPU mode in the color of its part of the bar
+func modeLegend(breakdown cpu.TimeBreakdown) string {
+	colors := modeColors()
+	parts := make([]string, 0, len(colors))
+	for i, mode := range breakdown.Modes() {
+		parts = append(parts, fmt.Sprintf("%s%s%s %.1f%%", colors[i], mode.Name, resetColor, mode.Percent))
+	}
+	return strings.Join(parts, "   ")
+}
+
+// memoryTab shows the RAM and swap usage over time and the processes using the most memory
+type memoryTab struct {
+	history     *tabHistory // RAM and swap usage (%)
+	swapTotal   uint64      // Swap space in bytes (0: no swap)
+	swapUsed    uint64      // Swap in use in bytes
+	swapPercent float64     // Swap usage (0-100%)
+	swapErr     error       // Error of the last swap sample
+}
+
+func (t *memoryTab) title() string         { return "Memory" }
+func (t *memoryTab) action() config.Action { return config.ActionTabMemory }
+
+func (t *memoryTab) sample(tui *InteractiveTUI) {
+	t.swapTotal, t.swapUsed, t.swapPercent, t.swapErr = ram.GetSwapMemory()
+
+	values := make(map[string]float64)
+	if tui.system.HasRAM {
+		values["ram"] = tui.system.RAM.Percent
+	}
+	if t.swapErr == nil && t.swapTotal > 0 {
+		values["swap"] = t.swapPercent
+	}
+	t.history.record(values)
+}
+
+func (t *memoryTab) rows(tui *InteractiveTUI, height int) []string {
+	series := []history.ChartSeries{{Name: "ram", Color: magentaColor}}
+	if t.swapTotal > 0 {
+		series = append(series, history.ChartSeries{Name: "swap", Color: yellowColor})
+	}
+	chart := t.history.chart(series, tui.width-1, chartHeight(height, len(series)))
+
+	var details []string
+	if tui.system.HasRAM {
+		details = append(details, fmt.Sprintf("  %-5s %s %5.1f%%  %s of %s (%s available)", "RAM",
+			usageBar(tui.system.RAM.Percent, usageBarWidth), tui.system.RAM.Percent, common.FormatBytes(tui.system.RAM.Used),
+			common.FormatBytes(tui.system.RAM.Total), common.FormatBytes(tui.system.RAM.Available)))
+	}
+	switch {
+	case t.swapErr != nil:
+		details = append(details, "  Swap  N/A")
+	case t.swapTotal == 0:
+		details = append(details, "  Swap  none")
+	default:
+		details = append(details, fmt.Sprintf("  %-5s %s %5.1f%%  %s of %s", "Swap",
+			usageBar(t.swapPercent, usageBarWidth), t.swapPercent, common.FormatBytes(t.swapUsed), common.FormatBytes(t.swapTotal)))
+	}
+	if tui.system.HasCgroup && tui.system.Cgroup.HasMemoryLimit() {
+		details = append(details, "  Cgroup "+tui.system.Cgroup.FormatMemory())
+	}
+
+	// Largest processes by resident memory
+	top := make([]common.ProcessInfo, len(tui.processes))
+	copy(top, tui.processes)
+	sort.Slice(top, func(i, j int) bool {
+		return top[i].RAMBytes > top[j].RAMBytes
+	})
+	details = append(details, "", boldColor+fmt.Sprintf("  %-8s %-*s %-24s %10s %8s", "PID", userColumnWidth, "USER", "NAME", "MEMORY", "RAM %")+resetColor)
+	for _, p := range top[:min(len(top), 15)] {
+		details = append(details, fmt.Sprintf("  %-8d %s %s %10s %7.2f%%", p.PID,
+			common.FitString(p.Username, userColumnWidth), common.FitString(p.Name, 24),
+			common.FormatBytes(p.RAMBytes), p.RAMPercentage))
+	}
+	return tui.tabRows("Memory usage (%)", chart, details, height)
+}
+
+// diskTab shows the disk throughput over time, how busy each device is and the mounts
+type diskTab struct {
+	history  *tabHistory              // Read and write throughput of all devices (MB/s)
+	sampler  *disk.UtilizationSampler // Measures the device activity between refreshes
+	devices  []disk.DeviceUtilization // Activity of each device since the previous refresh (nil on the first)
+	ioErr    error                    // Error of the last I/O sample
+	mounts   []disk.StorageDevice     // Mounted file systems (read while the tab is shown)
+	mountErr error                    // Error of the last mount sample
+}
+
+func (t *diskTab) title() string         { return "Disk" }
+func (t *diskTab) action() config.Action { return config.ActionTabDisk }
+
+func (t *diskTab) sample(tui *InteractiveTUI) {
+	t.devices, t.ioErr = t.sampler.Sample()
+	if t.devices != nil {
+		var read, write float64
+		for _, device := range t.devices {
+			read += device.ReadBytesPerSec
+			write += device.WriteBytesPerSec
+		}
+		t.history.record(map[string]float64{"read": read / (1024 * 1024), "write": write / (1024 * 1024)})
+	}
+
+	// The mounts have no history, so they are only read while the tab is shown
+	if tui.tab == tabDisk {
+		t.mounts, t.mountErr = disk.GetAllStorageDevices(tui.config.Disk.Filter())
+	}
+}
+
+func (t *diskTab) rows(tui *InteractiveTUI, height int) []string {
+	series := []history.ChartSeries{{Name: "read", Color: greenColor}, {Name: "write", Color: magentaColor}}
+	chart := t.history.chart(series, tui.width-1, chartHeight(height, len(series)))
+
+	var details []string
+	switch {
+	case t.ioErr != nil:
+		details = append(details, "  "+redColor+t.ioErr.Error()+resetColor)
+	case len(t.devices) == 0:
+		details = append(details, "  No disk activity measured yet")
+	default:
+		details = append(details, boldColor+fmt.Sprintf("  %-12s %-*s %7s %12s %12s", "DEVICE", usageBarWidth+2, "BUSY", "", "READ/s", "WRITE/s")+resetColor)
+		for _, device := range t.devices {
+			details = append(details, fmt.Sprintf("  %s %s %6.1f%% %12s %12s", common.FitString(device.Name, 12),
+				usageBar(device.Percent, usageBarWidth), device.Percent,
+				common.FormatBytes(uint64(device.ReadBytesPerSec)), common.FormatBytes(uint64(device.WriteBytesPerSec))))
+		}
+	}
+
+	details = append(details, "")
+	switch {
+	case t.mountErr != nil:
+		details = append(details, "  "+redColor+t.mountErr.Error()+resetColor)
+	case len(t.mounts) == 0:
+		details = append(details, "  No mounted disks found")
+	default:
+		details = append(details, boldColor+fmt.Sprintf("  %-20s %-8s %-*s %7s %10s %10s", "MOUNT", "TYPE", usageBarWidth+2, "USAGE", "", "USED", "TOTAL")+resetColor)
+		for _, mount := range t.mounts {
+			details = append(details, fmt.Sprintf("  %s %s %s %6.1f%% %10s %10s", common.FitString(mount.Mountpoint, 20),
+				common.FitString(mount.Fstype, 8), usageBar(mount.Percent, usageBarWidth), mount.Percent,
+				common.FormatBytes(mount.Used), common.FormatBytes(mount.Total)))
+		}
+	}
+	return tui.tabRows("Disk throughput (MB/s)", chart, details, height)
+}
+
+// networkTab shows the network throughput over time and the interfaces
+// It shares the interfaces with the network pane of the split view
+type networkTab struct {
+	history *tabHistory // Received and sent throughput of all interfaces (KB/s)
+}
+
+func (t *networkTab) title() string         { return "Network" }
+func (t *networkTab) action() config.Action { return config.ActionTabNetwork }
+
+func (t *networkTab) sample(tui *InteractiveTUI) {
+	tui.interfaces, tui.networkErr = tui.network.Sample()
+
+	var rx, tx float64
+	measured := false
+	for _, iface := range tui.interfaces {
+		if iface.HasRate {
+			rx += iface.RxBytesPerSec
+			tx += iface.TxBytesPerSec
+			measured = true
+		}
+	}
+	if measured {
+		t.history.record(map[string]float64{"rx": rx / 1024, "tx": tx / 1024})
+	}
+}
+
+func (t *networkTab) rows(tui *InteractiveTUI, height int) []string {
+	series := []history.ChartSeries{{Name: "rx", Color: greenColor}, {Name: "tx", Color: magentaColor}}
+	chart := t.history.chart(series, tui.width-1, chartHeight(height, len(series)))
+
+	var details []string
+	for _, line := range tui.networkLines() {
+		details = append(details, "  "+line)
+	}
+	return tui.tabRows("Network throughput (KB/s)", chart, details, height)
+}
+
+// gpuTab shows the utilization of each GPU over time, with its memory and temperature
+// GPUs are only polled while the tab is shown, since nvidia-smi is slow
+type gpuTab struct {
+	history *tabHistory    // Utilization of each GPU (%), as "gpu0", "gpu1"... (see gpuSeries)
+	gpus    []gpu.GPUStats // GPUs of the last sample
+	err     error          // Error of the last sample
+	sampled bool           // At least one sample was taken
+}
+
+func (t *gpuTab) title() string         { return "GPU" }
+func (t *gpuTab) action() config.Action { return config.ActionTabGPU }
+
+func (t *gpuTab) sample(tui *InteractiveTUI) {
+	if tui.tab != tabGPU || tui.pauseGPU.Load() {
+		return
+	}
+	t.gpus, t.err = gpu.GetAllGPUStats(context.Background())
+	t.sampled = true
+
+	values := make(map[string]float64)
+	for i, g := range t.gpus {
+		if g.HasUtilization {
+			values[gpuSeries(i)] = g.Utilization
+		}
+	}
+	t.history.record(values)
+}
+
+func (t *gpuTab) rows(tui *InteractiveTUI, height int) []string {
+	switch {
+	case !t.sampled:
+		return tui.tabRows("GPU utilization (%)", nil, []string{"  Reading GPU statistics..."}, height)
+	case errors.Is(t.err, common.ErrCommandTimeout):
+		return tui.tabRows("GPU utilization (%)", nil, []string{"  GPU not responding (" + t.err.Error() + ")"}, height)
+	case t.err != nil || len(t.gpus) == 0:
+		return tui.tabRows("GPU utilization (%)", nil, []string{"  No GPU detected"}, height)
+	}
+
+	colors := []string{greenColor, magentaColor, yellowColor, cyanColor, blueColor, redColor}
+	var series []history.ChartSeries
+	var details []string
+	for i, g := range t.gpus {
+		name := gpuSeries(i)
+		if g.HasUtilization {
+			series = append(series, history.ChartSeries{Name: name, Color: colors[i%len(colors)]})
+		}
+
+		details = append(details, fmt.Sprintf("  %s%s%s %s", boldColor, name, resetColor, g.Model))
+		utilization := "  Usage N/A"
+		if g.HasUtilization {
+			utilization = fmt.Sprintf("  Usage %s %5.1f%%", usageBar(g.Utilization, usageBarWidth), g.Utilization)
+		}
+		if g.Temp > 0 {
+			utilization += fmt.Sprintf("   %sTemperature%s %d°C", yellowColor, resetColor, g.Temp)
+		}
+		details = append(details, utilization)
+		if g.MemoryTotal > 0 {
+			percent := float64(g.MemoryUsed) / float64(g.MemoryTotal) * 100
+			details = append(details, fmt.Sprintf("  VRAM  %s %5.1f%%  %d MB of %d MB", usageBar(percent, usageBarWidth), percent, g.MemoryUsed, g.MemoryTotal))
+		}
+		var hardware []string
+		if g.SMClock > 0 {
+			hardware = append(hardware, fmt.Sprintf("%sClock%s %d MHz (memory %d MHz)", cyanColor, resetColor, g.SMClock, g.MemClock))
+		}
+		switch {
+		case g.PowerDraw > 0 && g.PowerLimit > 0:
+			hardware = append(hardware, fmt.Sprintf("%sPower%s %.0f W of %.0f W", cyanColor, resetColor, g.PowerDraw, g.PowerLimit))
+		case g.PowerDraw > 0:
+			hardware = append(hardware, fmt.Sprintf("%sPower%s %.0f W", cyanColor, resetColor, g.PowerDraw))
+		}
+		if g.HasFanSpeed {
+			hardware = append(hardware, fmt.Sprintf("%sFan%s %d%%", cyanColor, resetColor, g.FanSpeed))
+		}
+		if len(hardware) > 0 {
+			details = append(details, "  "+strings.Join(hardware, "   "))
+		}
+		details = append(details, "")
+	}
+
+	var chart []string
+	if len(series) > 0 {
+		chart = t.history.chart(series, tui.width-1, chartHeight(height, len(series)))
+	}
+	return tui.tabRows("GPU utilization (%)", chart, details, height)
+}
+
+// gpuSeries returns the history series of the i-th GPU of GetAllGPUStats (its GPUStats.Index)
+func gpuSeries(i int) string {
+	return fmt.Sprintf("gpu%d", i)
+}
+
+// sensorsTab shows the CPU temperature over time, every temperature sensor and the fans
+// With fan_control, the fan levels can be set by hand; the fans go back to automatic control
+// when a sensor overheats and when the TUI exits
+type sensorsTab struct {
+	history    *tabHistory            // CPU temperature (°C)
+	chips      []sensors.Chip         // Chips of the last sample
+	fans       []sensors.Fan          // Fans of the last sample
+	err        error                  // Error of the last sample
+	selected   int                    // Fan whose level the keys change (with fan_control)
+	controller *sensors.FanController // Fans set by hand, restored on exit
+}
+
+func (t *sensorsTab) title() string         { return "Sensors" }
+func (t *sensorsTab) action() config.Action { return config.ActionTabSensors }
+
+func (t *sensorsTab) sample(tui *InteractiveTUI) {
+	if tui.system.CPUTemp > 0 {
+		t.history.record(map[string]float64{"cpu": float64(tui.system.CPUTemp)})
+	}
+
+	// The chips are read while the tab is shown; while a fan is set by hand, guardFans reads them
+	if tui.tab != tabSensors || t.controller.Changed() > 0 {
+		return
+	}
+	t.chips, t.err = sensors.GetChips()
+	t.fans, _ = sensors.GetFans()
+}
+
+// guardFans reads the temperatures while a fan is set by hand, and gives every fan back to
+// the chip when a sensor overheats or the temperatures can't be read
+// Runs on its own ticker at the normal refresh interval, so a fan kept slow is watched
+// while auto-refresh is paused and while the session is locked
+//
+// Returns: true if the fans were given back to the chip (the screen must be redrawn)
+func (t *sensorsTab) guardFans(tui *InteractiveTUI) bool {
+	if t.controller.Changed() == 0 {
+		return false
+	}
+	t.chips, t.err = sensors.GetChips()
+	t.fans, _ = sensors.GetFans()
+
+	// A fan kept slow must never let the hardware overheat
+	reason, hot := sensors.Overheating(t.chips)
+	if t.err != nil {
+		reason, hot = "the temperatures can't be read", true
+	}
+	if !hot {
+		return false
+	}
+	message := "Fans back to automatic control: " + reason
+	if err := t.controller.RestoreAll(); err != nil {
+		message += " (" + err.Error() + ")"
+	}
+	tui.fail(message)
+	t.fans, _ = sensors.GetFans()
+	return true
+}
+
+func (t *sensorsTab) rows(tui *InteractiveTUI, height int) []string {
+	series := []history.ChartSeries{{Name: "cpu", Color: yellowColor}}
+	chart := t.history.chart(series, tui.width-1, chartHeight(height, len(series)))
+	if t.err != nil {
+		return tui.tabRows("CPU temperature (°C)", chart, []string{"  " + redColor + t.err.Error() + resetColor}, height)
+	}
+
+	// Fans first, so the selected one is visible without scrolling
+	var details []string
+	t.selected = max(0, min(t.selected, len(t.fans)-1))
+	if len(t.fans) == 0 {
+		details = append(details, "  No fans found")
+	} else {
+		details = append(details, boldColor+fmt.Sprintf("  %-24s %9s %-*s %5s  %s", "FAN", "SPEED", usageBarWidth+2, "LEVEL", "", "MODE")+resetColor)
+	}
+	for i, fan := range t.fans {
+		level := strings.Repeat(" ", usageBarWidth+2+6)
+		if fan.Level >= 0 {
+			level = fmt.Sprintf("%s %4d%%", usageBar(float64(fan.Level), usageBarWidth), fan.Level)
+		}
+		row := fmt.Sprintf("%s %9s %s  %s", common.FitString(fan.Chip+" "+fan.Label, 24), fmt.Sprintf("%.0f RPM", fan.RPM), level, fan.ModeName())
+		if tui.config.FanControl && i == t.selected {
+			row = selectionStyle + "> " + row + resetColor
+		} else {
+			row = "  " + row
+		}
+		details = append(details, row)
+	}
+	details = append(details, "  "+t.controlHelp(tui), "")
+
+	for _, chip := range t.chips {
+		var temperatures []string
+		for _, reading := range chip.Readings {
+			if reading.Kind == sensors.KindTemperature {
+				temperatures = append(temperatures, "    "+common.FitString(reading.Label, 24)+sensors.FormatReading(reading))
+			}
+		}
+		if len(temperatures) > 0 {
+			details = append(details, "  "+boldColor+chip.Name+resetColor)
+			details = append(details, temperatures...)
+		}
+	}
+	return tui.tabRows("CPU temperature (°C)", chart, details, height)
+}
+
+// controlHelp explains how to set the fan levels, or how to turn fan control on
+func (t *sensorsTab) controlHelp(tui *InteractiveTUI) string {
+	if !tui.config.FanControl {
+		return cyanColor + "Fan control is off: set \"fan_control\": true in the config file and run as root to set fan levels" + resetColor
+	}
+	return cyanColor + fmt.Sprintf("%s/%s change the selected fan's level (%d-100%%), %s gives it back to the chip; fans go back to automatic control on exit or when a sensor overheats",
+		tui.keysLabel(config.ActionNiceUp), tui.keysLabel(config.ActionNiceDown), sensors.MinFanLevel, tui.keysLabel(config.ActionFanAuto)) + resetColor
+}
+
+// sensorsView returns the view of the Sensors tab
+func (tui *InteractiveTUI) sensorsView() *sensorsTab {
+	return tui.tabs[tabSensors].(*sensorsTab)
+}
+
+// changeFanLevel sets the level of the selected fan by hand (fan_control)
+//
+// Parameters:
+//   - step: change of the level in % (e.g. 10 or -10), kept within MinFanLevel and 100%
+func (tui *InteractiveTUI) changeFanLevel(step int) {
+	t := tui.sensorsView()
+	if !tui.config.FanControl {
+		tui.notice = "Fan control is off (set \"fan_control\": true in the config file)"
+		return
+	}
+	if t.selected < 0 || t.selected >= len(t.fans) {
+		return
+	}
+
+	fan := t.fans[t.selected]
+	level := max(sensors.MinFanLevel, min(fan.Level+step, 100))
+	if err := t.controller.SetLevel(fan, level, t.chips); err != nil {
+		tui.fail(err.Error())
+		return
+	}
+	tui.notice = fmt.Sprintf("%s %s set to %d%%", fan.Chip, fan.Label, level)
+	t.fans, _ = sensors.GetFans()
+}
+
+// restoreSelectedFan gives the selected fan back to automatic control
+func (tui *InteractiveTUI) restoreSelectedFan() {
+	t := tui.sensorsView()
+	if t.selected < 0 || t.selected >= len(t.fans) {
+		return
+	}
+
+	fan := t.fans[t.selected]
+	if err := t.controller.Restore(fan); err != nil {
+		tui.fail(err.Error())
+		return
+	}
+	tui.notice = fmt.Sprintf("%s %s back to automatic control", fan.Chip, fan.Label)
+	t.fans, _ = sensors.GetFans()
+}
+
+// restoreFans gives every fan set by hand back to automatic control (when the TUI exits)
+func (tui *InteractiveTUI) restoreFans() {
+	if err := tui.sensorsView().controller.RestoreAll(); err != nil {
+		common.Logger().Error("error restoring fan control", "error", err)
+	}
+}
+
+// connectionSort is the order of the Connections tab
+type connectionSort int
+
+const (
+	connectionsByState   connectionSort = iota // By TCP state, then process
+	connectionsByProcess                       // By owning process, then local address
+	connectionsByLocal                         // By local address
+	connectionsByRemote                        // By remote address
+	connectionSortCount                        // Number of orders (used to cycle)
+)
+
+// label returns the name of the order shown in the tab title
+func (s connectionSort) label() string {
+	return [...]string{"state", "process", "local address", "remote address"}[s]
+}
+
+// connectionsTab lists the TCP and UDP sockets with their owning process, like ss -tunap
+// The sockets are only read while the tab is shown, since finding their owners reads the
+// file descriptors of every process
+type connectionsTab struct {
+	all      []network.Connection // Connections of the last sample, in kernel order
+	err      error                // Error of the last sample
+	selected int                  // Connection whose owner the kill key kills (index in visible)
+	sortBy   connectionSort       // Order of the list
+	filter   string               // Only connections containing this text are listed (case-insensitive)
+	editing  bool                 // The filter is being typed
+}
+
+func (t *connectionsTab) title() string         { return "Connections" }
+func (t *connectionsTab) action() config.Action { return config.ActionTabConnections }
+
+func (t *connectionsTab) sample(tui *InteractiveTUI) {
+	if tui.tab != tabConnections {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), common.CollectTimeout)
+	defer cancel()
+	t.all, t.err = network.GetConnections(ctx, tui.allProcesses)
+}
+
+// visible returns the connections matching the filter, in the order of the tab
+func (t *connectionsTab) visible() []network.Connection {
+	filter := strings.ToLower(t.filter)
+	connections := make([]network.Connection, 0, len(t.all))
+	for _, c := range t.all {
+		text := strings.ToLower(fmt.Sprintf("%s %s %s %s %d %s %s", c.Protocol, c.Local, c.Remote, c.State, c.PID, c.Process, c.User))
+		if strings.Contains(text, filter) {
+			connections = append(connections, c)
+		}
+	}
+
+	sort.SliceStable(connections, func(i, j int) bool {
+		a, b := connections[i], connections[j]
+		switch t.sortBy {
+		case connectionsByProcess:
+			if a.Process != b.Process {
+				return a.Process < b.Process
+			}
+			return a.Local < b.Local
+		case connectionsByLocal:
+			return a.Local < b.Local
+		case connectionsByRemote:
+			return a.Remote < b.Remote
+		default:
+			if a.State != b.State {
+				return a.State < b.State
+			}
+			return a.Process < b.Process
+		}
+	})
+	return connections
+}
+
+func (t *connectionsTab) rows(tui *InteractiveTUI, height int) []string {
+	connections := t.visible()
+	t.selected = max(0, min(t.selected, len(connections)-1))
+
+	// 1. Title, help and column headers stay on top while the list scrolls
+	title := fmt.Sprintf("Connections: %d of %d, sorted by %s", len(connections), len(t.all), t.sortBy.label())
+	switch {
+	case t.editing:
+		title += ", filter: " + t.filter + "_"
+	case t.filter != "":
+		title += ", filter: " + t.filter
+	}
+	addrWidth := max(21, min((tui.width-48)/2, 47))
+	rows := []string{
+		"  " + boldColor + title + resetColor,
+		"  " + t.help(tui),
+		"",
+		boldColor + fmt.Sprintf("  %-5s %-*s %-*s %-11s %7s  %s", "PROTO", addrWidth, "LOCAL", addrWidth, "REMOTE", "STATE", "PID", "PROCESS") + resetColor,
+	}
+	switch {
+	case t.err != nil:
+		rows = append(rows, "  "+redColor+t.err.Error()+resetColor)
+	case t.all == nil:
+		rows = append(rows, "  Reading connections...")
+	case len(connections) == 0:
+		rows = append(rows, "  No connections match the filter")
+	}
+
+	// 2. Scroll the list so the selected connection is visible
+	visible := max(height-len(rows), 1)
+	tui.tabScroll = max(0, min(tui.tabScroll, t.selected, len(connections)-visible))
+	if t.selected >= tui.tabScroll+visible {
+		tui.tabScroll = t.selected - visible + 1
+	}
+	for i := tui.tabScroll; i < len(connections) && len(rows) < height; i++ {
+		c := connections[i]
+		pid, process := "-", "-"
+		if c.PID > 0 {
+			pid = fmt.Sprint(c.PID)
+		}
+		if c.Process != "" {
+			process = c.Process + " (" + c.User + ")"
+		}
+		remote := c.Remote
+		if remote == "" {
+			remote = "*"
+		}
+		state := fmt.Sprintf("%-11s", c.State)
+		row := fmt.Sprintf("%-5s %s %s %s %7s  %s", c.Protocol, common.FitString(c.Local, addrWidth), common.FitString(remote, addrWidth), state, pid, process)
+		if i == t.selected {
+			row = selectionStyle + "> " + row + resetColor
+		} else {
+			row = "  " + strings.Replace(row, state, connectionStateColor(c.State)+state+resetColor, 1)
+		}
+		rows = append(rows, row)
+	}
+
+	for len(rows) < height {
+		rows = append(rows, "")
+	}
+	rows = rows[:height]
+	for i := range rows {
+		rows[i] = fitWidth(rows[i], tui.width-1)
+	}
+	return rows
+}
+
+// help explains the keys of the tab, or how to finish typing the filter
+func (t *connectionsTab) help(tui *InteractiveTUI) string {
+	if t.editing {
+		return cyanColor + "Type to filter by address, port, state, PID, process or user; ENTER keeps the filter, ESC clears it" + resetColor
+	}
+	return cyanColor + fmt.Sprintf("%s changes the order, %s filters, %s kills the process that owns the selected connection",
+		tui.keysLabel(config.ActionSortNext), tui.keysLabel(config.ActionFilter), tui.keysLabel(config.ActionKill)) + resetColor
+}
+
+// connectionStateColor returns the color of a TCP state: established connections green,
+// listening sockets cyan and connections being closed yellow
+func connectionStateColor(state string) string {
+	switch state {
+	case "ESTABLISHED":
+		return greenColor
+	case "LISTEN":
+		return cyanColor
+	case "", "NONE":
+		return ""
+	default:
+		return yellowColor
+	}
+}
+
+// connectionsView returns the view of the Connections tab
+func (tui *InteractiveTUI) connectionsView() *connectionsTab {
+	return tui.tabs[tabConnections].(*connectionsTab)
+}
+
+// editConnectionFilter handles a key while the filter of the Connections tab is typed
+// Printable keys add to the filter, BACKSPACE removes the last character, ENTER keeps
+// the filter and ESC clears it
+//
+// Parameters:
+//   - key: key name (from decodeKey)
+func (tui *InteractiveTUI) editConnectionFilter(key string) {
+	t := tui.connectionsView()
+	switch key {
+	case "enter":
+		t.editing = false
+	case "esc":
+		t.editing, t.filter = false, ""
+	case "backspace":
+		if runes := []rune(t.filter); len(runes) > 0 {
+			t.filter = string(runes[:len(runes)-1])
+		}
+	case "space":
+		t.filter += " "
+	default:
+		if len([]rune(key)) == 1 {
+			t.filter += key
+		}
+	}
+	t.selected = 0
+}
+
+// killConnectionOwner kills the process that owns the selected connection of the Connections tab
+func (tui *InteractiveTUI) killConnectionOwner() {
+	t := tui.connectionsView()
+	connections := t.visible()
+	if t.selected < 0 || t.selected >= len(connections) {
+		return
+	}
+
+	c := connections[t.selected]
+	if c.PID <= 0 {
+		tui.fail("The owner of this connection is unknown (other users' processes need root)")
+		return
+	}
+	if c.PID == int32(os.Getpid()) {
+		tui.fail("GoMonitor can't kill itself")
+		return
+	}
+	if err := common.KillProcess(c.PID); err != nil {
+		tui.fail(fmt.Sprintf("PID %d: %v", c.PID, err))
+		return
+	}
+	tui.notice = fmt.Sprintf("PID %d (%s) killed, owner of %s %s", c.PID, c.Process, c.Protocol, c.Local)
+
+	// Wait a bit for the sockets to close and read them again
+	time.Sleep(100 * time.Millisecond)
+	tui.updateProcesses()
+}
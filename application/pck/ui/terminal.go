@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"runtime/debug"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// terminalGuard puts the terminal back the way the TUI found it
+// Restoring only happens once, so every exit path (normal exit, signal, panic in any
+// goroutine) can call it
+type terminalGuard struct {
+	once     sync.Once
+	oldState *syscall.Termios // Settings before raw mode
+	cleanup  func()           // Also run on every exit path, after the terminal is restored (nil: none)
+}
+
+// restore restores the terminal settings, stops the mouse reports, resets the colors and
+// shows the cursor, then runs the cleanup
+func (g *terminalGuard) restore() {
+	g.once.Do(func() {
+		restoreTerminal(g.oldState)
+		fmt.Print(disableMouse + resetColor + showCursor)
+		if g.cleanup != nil {
+			g.cleanup()
+		}
+	})
+}
+
+// recoverPanic restores the terminal if the calling goroutine panics, then prints the panic and exits
+// Deferred at the start of every TUI goroutine: a panic in any of them would otherwise
+// end the program with the terminal in raw mode and the cursor hidden. os.Exit skips the
+// deferred calls of the other goroutines, so the cleanup runs here too
+func (g *terminalGuard) recoverPanic() {
+	if r := recover(); r != nil {
+		g.restore()
+		fmt.Fprintf(os.Stderr, "\npanic: %v\n\n%s", r, debug.Stack())
+		os.Exit(2)
+	}
+}
+
+// FixTerminal puts a terminal left in raw mode back to usable settings, like "stty sane"
+// For when the TUI was killed (e.g. with SIGKILL) before it could restore the terminal
+//
+// Returns: error if stdin isn't a terminal or its settings can't be changed
+func FixTerminal() error {
+	var state syscall.Termios
+	if _, _, err := syscall.Syscall(syscall.SYS_IOCTL,
+		uintptr(syscall.Stdin),
+		syscall.TCGETS,
+		uintptr(unsafe.Pointer(&state))); err != 0 {
+		return fmt.Errorf("error reading terminal settings: %w", err)
+	}
+
+	// Line editing, echo and Ctrl+C back on; Enter and newlines translated as usual
+	state.Lflag |= syscall.ICANON | syscall.ECHO | syscall.ECHOE | syscall.ECHOK | syscall.ISIG | syscall.IEXTEN
+	state.Iflag |= syscall.ICRNL | syscall.IXON
+	state.Iflag &^= syscall.INLCR | syscall.IGNCR
+	state.Oflag |= syscall.OPOST | syscall.ONLCR
+
+	if _, _, err := syscall.Syscall(syscall.SYS_IOCTL,
+		uintptr(syscall.Stdin),
+		syscall.TCSETS,
+		uintptr(unsafe.Pointer(&state))); err != 0 {
+		return fmt.Errorf("error restoring terminal settings: %w", err)
+	}
+
+	// A killed TUI also leaves the mouse reports on, which type escape codes on every click
+	fmt.Print(disableMouse + resetColor + showCursor)
+	return nil
+}
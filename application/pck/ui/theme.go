@@ -0,0 +1,179 @@
+package ui
+
+import (
+	"os"
+	"sort"
+)
+
+// Theme contains the ANSI codes used to color the output
+// Every view uses the color variables below, which are set from the active theme
+type Theme struct {
+	Name      string // Theme name used in the config file (e.g. "solarized")
+	Reset     string // Resets all attributes
+	Bold      string // Bold text
+	Red       string // Errors, kill actions and alerts
+	Green     string // CPU values and the logo
+	Yellow    string // Warnings and sort hints
+	Blue      string // Secondary information
+	Magenta   string // Memory values
+	Cyan      string // Titles and navigation hints
+	White     string // Neutral labels
+	Selection string // Style of the selected row in the TUI
+	Pinned    string // Style of the rows of pinned processes in the TUI
+	HeaderBar string // Style of the compact TUI header bar
+}
+
+// Built-in theme names
+const (
+	ThemeDefault      = "default"       // Standard 8 ANSI colors
+	ThemeMonochrome   = "monochrome"    // No colors, only bold and reverse video (used for NO_COLOR)
+	ThemeSolarized    = "solarized"     // Solarized palette (256-color terminals)
+	ThemeHighContrast = "high-contrast" // Bright colors and bold selection
+)
+
+// themes are the built-in themes by name
+var themes = map[string]Theme{
+	ThemeDefault: {
+		Name:      ThemeDefault,
+		Reset:     "\033[0m",
+		Bold:      "\033[1m",
+		Red:       "\033[31m",
+		Green:     "\033[32m",
+		Yellow:    "\033[33m",
+		Blue:      "\033[34m",
+		Magenta:   "\033[35m",
+		Cyan:      "\033[36m",
+		White:     "\033[37m",
+		Selection: "\033[44m\033[37m\033[1m",
+		Pinned:    "\033[1m\033[32m",
+		HeaderBar: "\033[44m\033[37m\033[1m",
+	},
+	ThemeMonochrome: {
+		Name:      ThemeMonochrome,
+		Reset:     "\033[0m",
+		Bold:      "\033[1m",
+		Selection: "\033[7m",
+		Pinned:    "\033[1m\033[4m",
+		HeaderBar: "\033[7m\033[1m",
+	},
+	ThemeSolarized: {
+		Name:      ThemeSolarized,
+		Reset:     "\033[0m",
+		Bold:      "\033[1m",
+		Red:       "\033[38;5;160m",
+		Green:     "\033[38;5;64m",
+		Yellow:    "\033[38;5;136m",
+		Blue:      "\033[38;5;33m",
+		Magenta:   "\033[38;5;125m",
+		Cyan:      "\033[38;5;37m",
+		White:     "\033[38;5;245m",
+		Selection: "\033[48;5;235m\033[38;5;230m\033[1m",
+		Pinned:    "\033[1m\033[38;5;61m",
+		HeaderBar: "\033[48;5;33m\033[38;5;230m\033[1m",
+	},
+	ThemeHighContrast: {
+		Name:      ThemeHighContrast,
+		Reset:     "\033[0m",
+		Bold:      "\033[1m",
+		Red:       "\033[1;91m",
+		Green:     "\033[1;92m",
+		Yellow:    "\033[1;93m",
+		Blue:      "\033[1;94m",
+		Magenta:   "\033[1;95m",
+		Cyan:      "\033[1;96m",
+		White:     "\033[1;97m",
+		Selection: "\033[107m\033[30m\033[1m",
+		Pinned:    "\033[1;92m\033[4m",
+		HeaderBar: "\033[107m\033[30m\033[1m",
+	},
+}
+
+// Color variables used by the views (set by SetTheme)
+var (
+	resetColor   string
+	boldColor    string
+	redColor     string
+	greenColor   string
+	yellowColor  string
+	blueColor    string
+	magentaColor string
+	cyanColor    string
+	whiteColor   string
+
+	selectionStyle string // Selected TUI row
+	pinnedStyle    string // Rows of pinned processes in the TUI
+	headerBarStyle string // Compact TUI header
+)
+
+// currentTheme is the active theme
+var currentTheme Theme
+
+func init() {
+	SetTheme(themes[ThemeDefault])
+}
+
+// SetTheme makes a theme the active one for every view
+//
+// Parameters:
+//   - theme: theme to use (e.g. from ThemeByName)
+func SetTheme(theme Theme) {
+	currentTheme = theme
+
+	resetColor = theme.Reset
+	boldColor = theme.Bold
+	redColor = theme.Red
+	greenColor = theme.Green
+	yellowColor = theme.Yellow
+	blueColor = theme.Blue
+	magentaColor = theme.Magenta
+	cyanColor = theme.Cyan
+	whiteColor = theme.White
+	selectionStyle = theme.Selection
+	pinnedStyle = theme.Pinned
+	headerBarStyle = theme.HeaderBar
+}
+
+// CurrentTheme returns the active theme
+func CurrentTheme() Theme {
+	return currentTheme
+}
+
+// ThemeByName finds a built-in theme
+//
+// Parameters:
+//   - name: theme name (e.g. "solarized")
+//
+// Returns:
+//   - Theme with that name
+//   - false if there is no such theme
+func ThemeByName(name string) (Theme, bool) {
+	theme, ok := themes[name]
+	return theme, ok
+}
+
+// ThemeNames returns the names of the built-in themes, sorted
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ColorDisabled checks if colors were turned off with the NO_COLOR environment variable
+// (any non-empty value, see https://no-color.org) or the --no-color flag
+//
+// Parameters:
+//   - args: command line arguments
+func ColorDisabled(args []string) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return true
+	}
+	for _, arg := range args {
+		if arg == "--no-color" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,216 @@
+package watchdog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/dfialho05/GoMonitor/application/pck/common"
+)
+
+// Options describes what happens when the watched process exits
+type Options struct {
+	Interval       time.Duration // Time between rows of statistics
+	RestartCommand string        // Shell command run after each exit (empty = stop watching)
+	Webhook        string        // URL the exit is POSTed to as JSON (empty = none)
+	MaxRestarts    int           // Restarts after which the watchdog gives up (0 = no limit)
+}
+
+// restartTimeout is how long the watchdog waits for the restarted process to show up
+const restartTimeout = 30 * time.Second
+
+// webhookTimeout bounds the webhook request, so a slow endpoint doesn't delay the restart
+const webhookTimeout = 10 * time.Second
+
+// Target is the process being watched
+type Target struct {
+	PID  int32  // Process ID
+	Name string // Process name, used to find it again after a restart
+}
+
+// Resolve finds the process to watch from a PID or a process name
+// A name matching several processes (e.g. nginx and its workers) selects the oldest one
+//
+// Parameters:
+//   - target: PID (e.g. "1234") or exact process name (e.g. "nginx")
+//
+// Returns:
+//   - the process
+//   - number of processes with that name (1 for a PID)
+//   - error if there's no such process
+func Resolve(target string) (Target, int, error) {
+	if pid, err := strconv.ParseInt(target, 10, 32); err == nil {
+		if pid <= 0 {
+			return Target{}, 0, fmt.Errorf("invalid PID %d", pid)
+		}
+		p, err := common.GetProcessByPID(int32(pid))
+		if err != nil {
+			return Target{}, 0, err
+		}
+		name, err := p.Name()
+		if err != nil {
+			return Target{}, 0, fmt.Errorf("error getting process name PID %d: %w", pid, err)
+		}
+		return Target{PID: int32(pid), Name: name}, 1, nil
+	}
+
+	processes, err := common.CollectAllProcessInfo()
+	if err != nil {
+		return Target{}, 0, fmt.Errorf("error getting processes: %w", err)
+	}
+	matches := common.FilterProcessesByName(processes, target)
+	if len(matches) == 0 {
+		return Target{}, 0, fmt.Errorf("no process named %q", target)
+	}
+	return Target{PID: matches[0].PID, Name: target}, len(matches), nil
+}
+
+// Exit describes a watched process that exited
+type Exit struct {
+	Target                       // Process that exited
+	Time     time.Time           // When the exit was noticed (within one interval of the real exit)
+	Last     *common.ProcessInfo // Last statistics read before the exit (nil if never read)
+	Restarts int                 // Restarts done before this exit
+}
+
+// Uptime returns how long the process ran (0 if its start time isn't known)
+func (e Exit) Uptime() time.Duration {
+	if e.Last == nil || e.Last.CreateTime <= 0 {
+		return 0
+	}
+	return e.Time.Sub(time.UnixMilli(e.Last.CreateTime))
+}
+
+// String describes the exit with the last known statistics
+// e.g. "nginx (PID 1234) exited after 2h 3m: CPU 1.20%, RAM 120.00 MB (1.50%), threads 8"
+func (e Exit) String() string {
+	message := fmt.Sprintf("%s (PID %d) exited", e.Name, e.PID)
+	switch uptime := e.Uptime(); {
+	case uptime >= time.Minute:
+		message += " after " + common.FormatDuration(uptime)
+	case uptime > 0:
+		message += " after " + uptime.Round(time.Second).String()
+	}
+	if e.Last == nil {
+		return message
+	}
+	return fmt.Sprintf("%s: CPU %.2f%%, RAM %s (%.2f%%), threads %d", message,
+		e.Last.CPUPercentage, common.FormatBytes(e.Last.RAMBytes), e.Last.RAMPercentage, e.Last.NumThreads)
+}
+
+// webhookPayload is the JSON body POSTed to the webhook
+// "text" makes it show up as is in Slack and Mattermost incoming webhooks
+type webhookPayload struct {
+	Event         string  `json:"event"`          // Always "process_exit"
+	Text          string  `json:"text"`           // Human readable description (Exit.String)
+	Host          string  `json:"host"`           // Machine the process ran on
+	PID           int32   `json:"pid"`            // Process ID
+	Name          string  `json:"name"`           // Process name
+	Time          string  `json:"time"`           // When the exit was noticed (RFC 3339)
+	UptimeSeconds float64 `json:"uptime_seconds"` // How long the process ran (0 if unknown)
+	CPUPercent    float64 `json:"cpu_percent"`    // Last CPU usage
+	RAMBytes      uint64  `json:"ram_bytes"`      // Last resident memory
+	RAMPercent    float32 `json:"ram_percent"`    // Last share of the system RAM
+	Threads       int32   `json:"threads"`        // Last thread count
+	Restarts      int     `json:"restarts"`       // Restarts done before this exit
+	Restarting    bool    `json:"restarting"`     // A restart command runs next
+}
+
+// NotifyWebhook POSTs the exit to a webhook as JSON
+//
+// Parameters:
+//   - url: webhook URL
+//   - exit: exit to report
+//   - restarting: the restart command runs next
+//
+// Returns: error if the request fails or the endpoint doesn't answer with a 2xx status
+func NotifyWebhook(url string, exit Exit, restarting bool) error {
+	payload := webhookPayload{
+		Event:         "process_exit",
+		Text:          exit.String(),
+		PID:           exit.PID,
+		Name:          exit.Name,
+		Time:          exit.Time.Format(time.RFC3339),
+		UptimeSeconds: exit.Uptime().Seconds(),
+		Restarts:      exit.Restarts,
+		Restarting:    restarting,
+	}
+	payload.Host, _ = os.Hostname()
+	if exit.Last != nil {
+		payload.CPUPercent = exit.Last.CPUPercentage
+		payload.RAMBytes = exit.Last.RAMBytes
+		payload.RAMPercent = exit.Last.RAMPercentage
+		payload.Threads = exit.Last.NumThreads
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error encoding webhook payload: %w", err)
+	}
+	client := http.Client{Timeout: webhookTimeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error calling webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
+
+// Restart runs the restart command with sh, in a session of its own so Ctrl+C on the
+// watchdog doesn't reach the restarted process
+// The command gets GOM_PID and GOM_NAME of the process that exited; it isn't waited for,
+// so it may start the program in the foreground (e.g. "./server --port 8080")
+//
+// Parameters:
+//   - command: shell command (e.g. "systemctl restart nginx")
+//   - exit: exit that triggered the restart
+//
+// Returns: error if the command can't be started
+func Restart(command string, exit Exit) error {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("GOM_PID=%d", exit.PID), "GOM_NAME="+exit.Name)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	err := cmd.Start()
+	common.LogCommand(cmd, time.Now(), err)
+	if err != nil {
+		return fmt.Errorf("error running restart command: %w", err)
+	}
+	go cmd.Wait() // Reap it whenever it ends
+	return nil
+}
+
+// WaitForRestart waits for a new process with the name of the one that exited
+//
+// Parameters:
+//   - exit: exit the restart command answered
+//   - interval: time between checks
+//
+// Returns:
+//   - the new process (the oldest one started after the exit)
+//   - error if none shows up within restartTimeout
+func WaitForRestart(exit Exit, interval time.Duration) (Target, error) {
+	deadline := time.Now().Add(restartTimeout)
+	for {
+		if processes, err := common.CollectAllProcessInfo(); err == nil {
+			for _, p := range common.FilterProcessesByName(processes, exit.Name) {
+				// Start times are rounded, so allow for a process started right at the exit
+				if p.PID != exit.PID && p.CreateTime >= exit.Time.Add(-interval-time.Second).UnixMilli() {
+					return Target{PID: p.PID, Name: p.Name}, nil
+				}
+			}
+		}
+		if time.Now().After(deadline) {
+			return Target{}, fmt.Errorf("no new %s process within %s", exit.Name, restartTimeout)
+		}
+		time.Sleep(min(interval, time.Second))
+	}
+}